@@ -46,6 +46,7 @@ type Configuration struct {
 		JWTExpireInSec        int64  `default:"2678400" env:"ADMIN_PANEL_JWT_EXPIRE"`
 		JWTRefreshExpireInSec int64  `default:"5356800" env:"ADMIN_PANEL_JWT_REFRESH_EXPIRE"`
 		JWTSecret             string `default:"secret-key-321" env:"ADMIN_PANEL_JWT_SECRET"`
+		TwoFactorRequired     bool   `default:"false" env:"ADMIN_PANEL_2FA_REQUIRED"` // обязательная двухфакторная аутентификация
 	}
 	Admin struct {
 		FirstName   string `default:"Admin" env:"SUPER_ADMIN_FIRST_NAME"`
@@ -55,8 +56,9 @@ type Configuration struct {
 		Password    string `default:"123hygAS" env:"SUPER_ADMIN_PASSWORD"`
 	}
 	AI struct {
-		VkStep1AI string `default:"Ollama" env:"AI_VK_STEP1"` //Ollama | YandexGPT
-		YandexGPT struct {
+		VkStep1AI     string `default:"Ollama" env:"AI_VK_STEP1"` //Ollama | YandexGPT
+		ResumeParseAI string `default:"" env:"AI_RESUME_PARSE"`   //Ollama | YandexGPT, если не указан - данные из резюме извлекаются только по правилам
+		YandexGPT     struct {
 			IAMToken  string `default:"" env:"YANDEXGPT_IAM_TOKEN"`
 			CatalogID string `default:"" env:"YANDEXGPT_CATALOG_ID"`
 		}
@@ -77,6 +79,12 @@ type Configuration struct {
 	HH struct {
 		RedirectUri string `default:"https://a.hr-tools.pro/api/v1/oauth/callback/hh" env:"HH_REDIRECT"`
 	}
+	// тестовый работный сайт на файлах, для разработки и тестирования
+	FakeJobSite struct {
+		Enabled     bool   `default:"false" env:"FAKE_JOB_SITE_ENABLED"`
+		Dir         string `default:"./fake_job_site" env:"FAKE_JOB_SITE_DIR"`
+		RedirectUri string `default:"http://localhost:8080/api/v1/oauth/callback/fake" env:"FAKE_JOB_SITE_REDIRECT"`
+	}
 	S3 struct {
 		Endpoint         string `default:"minio" env:"S3_ENDPOINT"`
 		AccessKeyID      string `default:"" env:"S3_ACCESS_KEY_ID"`
@@ -105,6 +113,26 @@ type Configuration struct {
 		SurveyStep0Path     string `default:"https://s.hr-tools.pro/public/survey/step0/" env:"PUBLIC_SURVEY_STEP0_UI_URL"`
 		VideoSurveyStepPath string `default:"https://s.hr-tools.pro/public/survey/video-interview/" env:"PUBLIC_VIDEO_SURVEY_UI_URL"`
 		SurveyPath          string `default:"https://s.hr-tools.pro/public/survey/" env:"PUBLIC_SURVEY_UI_URL"`
+		CareerSitePath      string `default:"https://s.hr-tools.pro/public/career/" env:"PUBLIC_CAREER_SITE_UI_URL"`
+		InterviewPath       string `default:"https://s.hr-tools.pro/public/interview/" env:"PUBLIC_INTERVIEW_UI_URL"`
+		OfferPath           string `default:"https://s.hr-tools.pro/public/offer/" env:"PUBLIC_OFFER_UI_URL"`
+		ApplicantPath       string `default:"https://s.hr-tools.pro/applicants/" env:"APPLICANT_UI_URL"`
+		VacancyRequestPath  string `default:"https://s.hr-tools.pro/vacancy-requests/" env:"VACANCY_REQUEST_UI_URL"`
+	}
+	Interview struct {
+		ReminderBeforeMin int `default:"60" env:"INTERVIEW_REMINDER_BEFORE_MIN"` // за сколько минут до начала отправлять напоминание
+		BookingExpireDays int `default:"7" env:"INTERVIEW_BOOKING_EXPIRE_DAYS"`  // срок действия ссылки для записи кандидата по умолчанию
+	}
+	Calendar struct {
+		SecretKey       string `default:"calendar-secret-key" env:"CALENDAR_SECRET_KEY"` // ключ шифрования учетных данных календарей
+		TimeoutSec      int    `default:"20" env:"CALENDAR_TIMEOUT_SEC"`
+		SyncIntervalMin int    `default:"5" env:"CALENDAR_SYNC_INTERVAL_MIN"` // период проверки изменений интервью в календарях участников
+	}
+	CareerSite struct {
+		FeedHost            string `default:"hr-tools.pro" env:"CAREER_SITE_FEED_HOST"`
+		ApplyLimit          int    `default:"5" env:"CAREER_SITE_APPLY_LIMIT"`              // кол-во откликов с одного ip за период
+		ApplyLimitPeriodSec int    `default:"600" env:"CAREER_SITE_APPLY_LIMIT_PERIOD_SEC"` // период ограничения откликов
+		MaxResumeSizeMb     int    `default:"10" env:"CAREER_SITE_MAX_RESUME_SIZE_MB"`
 	}
 	Survey struct {
 		VkStep0 struct {
@@ -122,6 +150,53 @@ type Configuration struct {
 			RegenRetryAttempts int `default:"2" env:"SURVEY_VK_STEP1_REGEN_RETRY_ATTEMPTS"`
 		}
 	}
+	Telegram struct {
+		Token         string `default:"" env:"TELEGRAM_BOT_TOKEN"`                       // пусто - уведомления в Telegram отключены
+		BotName       string `default:"" env:"TELEGRAM_BOT_NAME"`                        // имя бота для ссылки привязки аккаунта
+		ApiURL        string `default:"https://api.telegram.org" env:"TELEGRAM_API_URL"` // адрес Bot API, для тестов - локальный сервер
+		WebhookURL    string `default:"" env:"TELEGRAM_WEBHOOK_URL"`                     // пусто - получение обновлений через long polling
+		WebhookSecret string `default:"" env:"TELEGRAM_WEBHOOK_SECRET"`                  // секрет заголовка X-Telegram-Bot-Api-Secret-Token, обязателен при указании адреса webhook
+		TimeoutSec    int    `default:"30" env:"TELEGRAM_TIMEOUT_SEC"`
+		LinkExpireMin int    `default:"60" env:"TELEGRAM_LINK_EXPIRE_MIN"` // срок действия кода привязки аккаунта
+	}
+	Webhook struct {
+		SecretKey            string `default:"webhook-secret-key" env:"WEBHOOK_SECRET_KEY"` // ключ шифрования секретов подписок
+		TimeoutSec           int    `default:"10" env:"WEBHOOK_TIMEOUT_SEC"`
+		MaxAttempts          int    `default:"8" env:"WEBHOOK_MAX_ATTEMPTS"`            // кол-во попыток доставки события
+		DisableAfterFailures int    `default:"20" env:"WEBHOOK_DISABLE_AFTER_FAILURES"` // кол-во неудачных доставок подряд для отключения подписки
+	}
+	Sso struct {
+		SecretKey        string `default:"sso-secret-key" env:"SSO_SECRET_KEY"`                    // ключ шифрования секретов клиентов OIDC
+		ApiHost          string `default:"https://a.hr-tools.pro" env:"SSO_API_HOST"`              // внешний адрес API для адресов возврата IdP
+		LoginUI          string `default:"https://s.hr-tools.pro/auth/sso" env:"SSO_LOGIN_UI_URL"` // страница UI, получающая код входа или ошибку
+		TimeoutSec       int    `default:"20" env:"SSO_TIMEOUT_SEC"`
+		SessionExpireMin int    `default:"10" env:"SSO_SESSION_EXPIRE_MIN"` // срок прохождения аутентификации в IdP
+	}
+	TwoFactor struct {
+		SecretKey          string `default:"2fa-secret-key" env:"TWO_FACTOR_SECRET_KEY"` // ключ шифрования секретов TOTP и подписи токена второго шага входа
+		Issuer             string `default:"HR-Tools" env:"TWO_FACTOR_ISSUER"`           // название сервиса в приложении-аутентификаторе
+		ChallengeExpireSec int64  `default:"300" env:"TWO_FACTOR_CHALLENGE_EXPIRE"`      // срок ввода кода после проверки пароля
+		MaxAttempts        int    `default:"5" env:"TWO_FACTOR_MAX_ATTEMPTS"`            // неверных кодов подряд до блокировки
+		LockMin            int    `default:"15" env:"TWO_FACTOR_LOCK_MIN"`
+	}
+	Password struct {
+		MinLength         int    `default:"8" env:"PASSWORD_MIN_LENGTH"`
+		RequireUpper      bool   `default:"true" env:"PASSWORD_REQUIRE_UPPER"`     // заглавная буква
+		RequireLower      bool   `default:"true" env:"PASSWORD_REQUIRE_LOWER"`     // строчная буква
+		RequireDigit      bool   `default:"true" env:"PASSWORD_REQUIRE_DIGIT"`     // цифра
+		RequireSpecial    bool   `default:"false" env:"PASSWORD_REQUIRE_SPECIAL"`  // спецсимвол
+		DenylistFile      string `default:"" env:"PASSWORD_DENYLIST_FILE"`         // файл скомпрометированных паролей, по одному в строке
+		MaxFailedAttempts int    `default:"10" env:"PASSWORD_MAX_FAILED_ATTEMPTS"` // неверных паролей подряд до блокировки входа
+		LockMin           int    `default:"15" env:"PASSWORD_LOCK_MIN"`
+	}
+	WsHub struct {
+		Backend         string `default:"postgres" env:"WS_HUB_BACKEND"`     // postgres - рассылка событий между экземплярами через LISTEN/NOTIFY, local - только в текущем экземпляре
+		Channel         string `default:"ws_hub" env:"WS_HUB_CHANNEL"`       // канал LISTEN/NOTIFY
+		PingIntervalSec int    `default:"30" env:"WS_HUB_PING_INTERVAL_SEC"` // период отправки ping клиенту
+		PongTimeoutSec  int    `default:"75" env:"WS_HUB_PONG_TIMEOUT_SEC"`  // время ожидания pong или сообщения клиента до закрытия соединения
+		PendingTTLHours int    `default:"72" env:"WS_HUB_PENDING_TTL_HOURS"` // срок хранения неподтвержденных событий
+		PendingMax      int    `default:"100" env:"WS_HUB_PENDING_MAX"`      // максимальное количество последних неподтвержденных событий, отправляемых при подключении
+	}
 	NotifyBot struct {
 		AddrErr string `default:"http://93.189.231.84:8080/error" env:"NOTIFY_BOT_ERR"`
 		AddrAi  string `default:"http://93.189.231.84:8080/ai" env:"NOTIFY_BOT_AI"`
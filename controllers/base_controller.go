@@ -3,6 +3,7 @@ package controllers
 import (
 	"hr-tools-backend/middleware"
 	apimodels "hr-tools-backend/models/api"
+	authapimodels "hr-tools-backend/models/api/auth"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/pkg/errors"
@@ -32,6 +33,14 @@ func (c *BaseAPIController) GetID(ctx *fiber.Ctx) (id string, err error) {
 	return c.GetIDByKey(ctx, "id")
 }
 
+// GetClientInfo данные клиента для сессии входа
+func (c *BaseAPIController) GetClientInfo(ctx *fiber.Ctx) authapimodels.ClientInfo {
+	return authapimodels.ClientInfo{
+		UserAgent: ctx.Get(fiber.HeaderUserAgent),
+		IP:        ctx.IP(),
+	}
+}
+
 func (c *BaseAPIController) GetLogger(ctx *fiber.Ctx) *log.Entry {
 	spaceID := middleware.GetUserSpace(ctx)
 	userID := middleware.GetUserID(ctx)
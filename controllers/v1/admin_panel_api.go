@@ -21,6 +21,22 @@ type adminApiController struct {
 func InitAdminApiRouters(app *fiber.App) {
 	controller := adminApiController{}
 	app.Post("login", controller.login)
+	app.Post("refresh-token", controller.refreshToken)
+	app.Post("2fa/login", controller.loginTwoFactor)
+	app.Post("2fa/login-setup", controller.loginTwoFactorSetup)
+
+	// сессии и двухфакторная аутентификация текущего пользователя
+	app.Route("auth", func(router fiber.Router) {
+		router.Use(middleware.AdminPanelAuthorizationRequired())
+		router.Post("logout", controller.logout)
+		router.Get("sessions", controller.sessionList)
+		router.Delete("sessions/:id", controller.sessionRevoke)
+		router.Get("2fa", controller.twoFactorStatus)
+		router.Post("2fa/setup", controller.twoFactorSetup)
+		router.Post("2fa/enable", controller.twoFactorEnable)
+		router.Post("2fa/disable", controller.twoFactorDisable)
+		router.Post("2fa/recovery-codes", controller.twoFactorRecoveryCodes)
+	})
 
 	// доступ всем авторизованным пользователям
 	//otherApi := fiber.New()
@@ -50,7 +66,7 @@ func InitAdminApiRouters(app *fiber.App) {
 
 // @Summary Аутентификация пользователя
 // @Tags Админ панель
-// @Description Аутентификация пользователя
+// @Description Аутентификация пользователя. При двухфакторной аутентификации токены выдаются на втором шаге входа (2fa/login)
 // @Param	body				body		authapimodels.LoginRequest	true	"request body"
 // @Success 200 {object} apimodels.Response{data=authapimodels.JWTResponse}
 // @Failure 400 {object} apimodels.Response
@@ -66,7 +82,7 @@ func (a *adminApiController) login(ctx *fiber.Ctx) error {
 	if err := payload.Validate(); err != nil {
 		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
 	}
-	resp, err := adminpanelauthhandler.Instance.Login(payload.Email, payload.Password)
+	resp, err := adminpanelauthhandler.Instance.Login(payload.Email, payload.Password, a.GetClientInfo(ctx))
 	if err != nil {
 		return ctx.SendStatus(fiber.StatusUnauthorized)
 	}
@@ -92,10 +108,13 @@ func (a *adminApiController) userCreate(ctx *fiber.Ctx) error {
 	if err := payload.Validate(); err != nil {
 		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
 	}
-	err := handler.Instance.CreateUser(payload)
+	hMsg, err := handler.Instance.CreateUser(payload)
 	if err != nil {
 		return a.SendError(ctx, a.GetLogger(ctx), err, "Ошибка создания пользователя админ панели")
 	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
 	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
 }
 
@@ -120,10 +139,13 @@ func (a *adminApiController) userUpdate(ctx *fiber.Ctx) error {
 		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
 	}
 
-	err := handler.Instance.UpdateUser(value, payload)
+	hMsg, err := handler.Instance.UpdateUser(value, payload)
 	if err != nil {
 		return a.SendError(ctx, a.GetLogger(ctx), err, "Ошибка изменения пользователя админ панели")
 	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
 	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
 }
 
@@ -0,0 +1,265 @@
+package apiv1
+
+import (
+	adminpanelauthhandler "hr-tools-backend/lib/admin-panel/auth"
+	authsession "hr-tools-backend/lib/auth-session"
+	twofactor "hr-tools-backend/lib/two-factor"
+	authutils "hr-tools-backend/lib/utils/auth-utils"
+	"hr-tools-backend/middleware"
+	"hr-tools-backend/models"
+	apimodels "hr-tools-backend/models/api"
+	authapimodels "hr-tools-backend/models/api/auth"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// @Summary Обновить JWT
+// @Tags Админ панель
+// @Description Обновить JWT. Refresh токен одноразовый, в ответе выдается новый
+// @Param	body				body		authapimodels.JWTRefreshRequest	true	"request body"
+// @Success 200 {object} apimodels.Response{data=authapimodels.JWTResponse}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/admin_panel/refresh-token [post]
+func (a *adminApiController) refreshToken(ctx *fiber.Ctx) error {
+	var payload authapimodels.JWTRefreshRequest
+	if err := a.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err := payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+	resp, err := adminpanelauthhandler.Instance.RefreshToken(payload.RefreshToken, a.GetClientInfo(ctx))
+	if err != nil {
+		return ctx.SendStatus(fiber.StatusUnauthorized)
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Второй шаг входа
+// @Tags Админ панель
+// @Description Проверка кода из приложения-аутентификатора или кода восстановления. Если двухфакторная аутентификация обязательна и подключается при входе, в ответе коды восстановления
+// @Param	body				body		authapimodels.TwoFactorLoginRequest	true	"request body"
+// @Success 200 {object} apimodels.Response{data=authapimodels.JWTResponse}
+// @Failure 400 {object} apimodels.Response
+// @Failure 401 {object} apimodels.Response
+// @router /api/v1/admin_panel/2fa/login [post]
+func (a *adminApiController) loginTwoFactor(ctx *fiber.Ctx) error {
+	var payload authapimodels.TwoFactorLoginRequest
+	if err := a.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err := payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+	resp, err := adminpanelauthhandler.Instance.LoginTwoFactor(payload.TwoFactorToken, payload.Code, a.GetClientInfo(ctx))
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(apimodels.NewError(err.Error()))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Подключение двухфакторной аутентификации при входе
+// @Tags Админ панель
+// @Description Секрет для приложения-аутентификатора, когда двухфакторная аутентификация обязательна, но еще не подключена (two_factor_setup_required). Подключение подтверждается кодом на втором шаге входа
+// @Param	body				body		authapimodels.TwoFactorChallengeRequest	true	"request body"
+// @Success 200 {object} apimodels.Response{data=authapimodels.TwoFactorSetup}
+// @Failure 400 {object} apimodels.Response
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/admin_panel/2fa/login-setup [post]
+func (a *adminApiController) loginTwoFactorSetup(ctx *fiber.Ctx) error {
+	var payload authapimodels.TwoFactorChallengeRequest
+	if err := a.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err := payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+	resp, hMsg, err := adminpanelauthhandler.Instance.LoginTwoFactorSetup(payload.TwoFactorToken)
+	if err != nil {
+		return a.SendError(ctx, a.GetLogger(ctx), err, "Ошибка подключения двухфакторной аутентификации")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Выход
+// @Tags Админ панель
+// @Description Завершение текущей сессии, JWT и refresh токен сессии перестают действовать
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/admin_panel/auth/logout [post]
+func (a *adminApiController) logout(ctx *fiber.Ctx) error {
+	_, err := authsession.Instance.Revoke(models.AuthAdminPanelUser, middleware.GetUserID(ctx), authutils.GetSessionID(ctx))
+	if err != nil {
+		return a.SendError(ctx, a.GetLogger(ctx), err, "Ошибка завершения сессии")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Список сессий
+// @Tags Админ панель
+// @Description Действующие сессии пользователя (устройства, с которых выполнен вход)
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Success 200 {object} apimodels.Response{data=[]authapimodels.SessionView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/admin_panel/auth/sessions [get]
+func (a *adminApiController) sessionList(ctx *fiber.Ctx) error {
+	resp, err := authsession.Instance.List(models.AuthAdminPanelUser, middleware.GetUserID(ctx), authutils.GetSessionID(ctx))
+	if err != nil {
+		return a.SendError(ctx, a.GetLogger(ctx), err, "Ошибка получения списка сессий")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Завершение сессии
+// @Tags Админ панель
+// @Description Завершение сессии на другом устройстве
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID сессии"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/admin_panel/auth/sessions/{id} [delete]
+func (a *adminApiController) sessionRevoke(ctx *fiber.Ctx) error {
+	id, err := a.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+	hMsg, err := authsession.Instance.Revoke(models.AuthAdminPanelUser, middleware.GetUserID(ctx), id)
+	if err != nil {
+		return a.SendError(ctx, a.GetLogger(ctx), err, "Ошибка завершения сессии")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Состояние двухфакторной аутентификации
+// @Tags Админ панель
+// @Description Состояние двухфакторной аутентификации текущего пользователя
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Success 200 {object} apimodels.Response{data=authapimodels.TwoFactorStatus}
+// @Failure 400 {object} apimodels.Response
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/admin_panel/auth/2fa [get]
+func (a *adminApiController) twoFactorStatus(ctx *fiber.Ctx) error {
+	resp, err := adminpanelauthhandler.Instance.GetTwoFactorStatus(middleware.GetUserID(ctx))
+	if err != nil {
+		return a.SendError(ctx, a.GetLogger(ctx), err, "Ошибка получения состояния двухфакторной аутентификации")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Секрет для подключения двухфакторной аутентификации
+// @Tags Админ панель
+// @Description Секрет и otpauth ссылка для приложения-аутентификатора, подключение подтверждается кодом (auth/2fa/enable)
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Success 200 {object} apimodels.Response{data=authapimodels.TwoFactorSetup}
+// @Failure 400 {object} apimodels.Response
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/admin_panel/auth/2fa/setup [post]
+func (a *adminApiController) twoFactorSetup(ctx *fiber.Ctx) error {
+	resp, hMsg, err := adminpanelauthhandler.Instance.SetupTwoFactor(middleware.GetUserID(ctx))
+	if err != nil {
+		return a.SendError(ctx, a.GetLogger(ctx), err, "Ошибка подключения двухфакторной аутентификации")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Подключение двухфакторной аутентификации
+// @Tags Админ панель
+// @Description Подтверждение подключения кодом из приложения-аутентификатора, в ответе коды восстановления
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param	body				body		authapimodels.TwoFactorCodeRequest	true	"request body"
+// @Success 200 {object} apimodels.Response{data=authapimodels.RecoveryCodesView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/admin_panel/auth/2fa/enable [post]
+func (a *adminApiController) twoFactorEnable(ctx *fiber.Ctx) error {
+	var payload authapimodels.TwoFactorCodeRequest
+	if err := a.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err := payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+	codes, hMsg, err := twofactor.Instance.Enable(models.AuthAdminPanelUser, middleware.GetUserID(ctx), payload.Code)
+	if err != nil {
+		return a.SendError(ctx, a.GetLogger(ctx), err, "Ошибка подключения двухфакторной аутентификации")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(authapimodels.RecoveryCodesView{RecoveryCodes: codes}))
+}
+
+// @Summary Отключение двухфакторной аутентификации
+// @Tags Админ панель
+// @Description Отключение двухфакторной аутентификации, недоступно, если она обязательна для админки
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param	body				body		authapimodels.TwoFactorCodeRequest	true	"request body"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/admin_panel/auth/2fa/disable [post]
+func (a *adminApiController) twoFactorDisable(ctx *fiber.Ctx) error {
+	var payload authapimodels.TwoFactorCodeRequest
+	if err := a.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err := payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+	hMsg, err := adminpanelauthhandler.Instance.DisableTwoFactor(middleware.GetUserID(ctx), payload.Code)
+	if err != nil {
+		return a.SendError(ctx, a.GetLogger(ctx), err, "Ошибка отключения двухфакторной аутентификации")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Новые коды восстановления
+// @Tags Админ панель
+// @Description Выпуск новых кодов восстановления, прежние коды перестают действовать
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param	body				body		authapimodels.TwoFactorCodeRequest	true	"request body"
+// @Success 200 {object} apimodels.Response{data=authapimodels.RecoveryCodesView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/admin_panel/auth/2fa/recovery-codes [post]
+func (a *adminApiController) twoFactorRecoveryCodes(ctx *fiber.Ctx) error {
+	var payload authapimodels.TwoFactorCodeRequest
+	if err := a.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err := payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+	codes, hMsg, err := twofactor.Instance.RegenerateRecoveryCodes(models.AuthAdminPanelUser, middleware.GetUserID(ctx), payload.Code)
+	if err != nil {
+		return a.SendError(ctx, a.GetLogger(ctx), err, "Ошибка выпуска кодов восстановления")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(authapimodels.RecoveryCodesView{RecoveryCodes: codes}))
+}
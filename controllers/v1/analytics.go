@@ -46,8 +46,9 @@ func (c *analyticsApiController) source(ctx *fiber.Ctx) error {
 	}
 
 	spaceID := middleware.GetUserSpace(ctx)
+	userID := middleware.GetUserID(ctx)
 	payload.Limit = 1000
-	data, err := analytics.Instance.Source(spaceID, payload)
+	data, err := analytics.Instance.Source(spaceID, userID, payload)
 	if err != nil {
 		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения аналитики по источникам кандидатов")
 	}
@@ -74,8 +75,9 @@ func (c *analyticsApiController) sourceExport(ctx *fiber.Ctx) error {
 	}
 
 	spaceID := middleware.GetUserSpace(ctx)
+	userID := middleware.GetUserID(ctx)
 	payload.Limit = 1000
-	data, err := analytics.Instance.SourceExportToXls(spaceID, payload)
+	data, err := analytics.Instance.SourceExportToXls(spaceID, userID, payload)
 	if err != nil {
 		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения аналитики по источникам кандидатов для выгрузки в Excel")
 	}
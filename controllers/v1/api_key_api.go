@@ -0,0 +1,231 @@
+package apiv1
+
+import (
+	"hr-tools-backend/controllers"
+	apikey "hr-tools-backend/lib/api-key"
+	"hr-tools-backend/middleware"
+	"hr-tools-backend/models"
+	apimodels "hr-tools-backend/models/api"
+	apikeyapimodels "hr-tools-backend/models/api/api-key"
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type apiKeyApiController struct {
+	controllers.BaseAPIController
+}
+
+func InitApiKeyApiRouters(app *fiber.App) {
+	controller := apiKeyApiController{}
+	app.Route("api_key", func(router fiber.Router) {
+		router.Use(middleware.LicenseRequired())
+		router.Use(middleware.RbacMiddleware())
+		// управление ключами доступно только пользователям
+		router.Use(middleware.UserRequired())
+		router.Post("", controller.create)
+		router.Get("list", controller.list)
+		router.Get("scopes", controller.scopes)
+		router.Route(":id", func(idRoute fiber.Router) {
+			idRoute.Get("", controller.get)
+			idRoute.Put("", controller.update)
+			idRoute.Put("rotate", controller.rotate)
+			idRoute.Put("revoke", controller.revoke)
+		})
+	})
+}
+
+// @Summary Создание API ключа
+// @Tags API ключи
+// @Description Создание ключа для интеграций. Ключ передается в заголовке X-Api-Key и возвращается только при создании
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param	body body	 apikeyapimodels.ApiKeyData	true	"request body"
+// @Success 200 {object} apimodels.Response{data=apikeyapimodels.ApiKeySecret}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/api_key [post]
+func (c *apiKeyApiController) create(ctx *fiber.Ctx) error {
+	var payload apikeyapimodels.ApiKeyData
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err := payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	userID := middleware.GetUserID(ctx)
+	spaceID := middleware.GetUserSpace(ctx)
+	userRole := middleware.GetSpaceRole(ctx)
+	resp, hMsg, err := apikey.Instance.Create(spaceID, userID, userRole, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка создания API ключа")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Список API ключей
+// @Tags API ключи
+// @Description Список API ключей спейса, включая отозванные
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Success 200 {object} apimodels.Response{data=[]apikeyapimodels.ApiKeyView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/api_key/list [get]
+func (c *apiKeyApiController) list(ctx *fiber.Ctx) error {
+	spaceID := middleware.GetUserSpace(ctx)
+	list, err := apikey.Instance.List(spaceID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения списка API ключей")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(list))
+}
+
+// @Summary Области доступа API ключа
+// @Tags API ключи
+// @Description Пары модуль/разрешение RBAC, которые пользователь может выдать ключу с указанной ролью
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   role          		query    string  				    	true         "Роль ключа"
+// @Success 200 {object} apimodels.Response{data=[]dbmodels.ApiKeyScope}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/api_key/scopes [get]
+func (c *apiKeyApiController) scopes(ctx *fiber.Ctx) error {
+	role := models.UserRole(ctx.Query("role"))
+	if !slices.Contains(models.AllAvailableRoles, role) {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError("некорректная роль ключа"))
+	}
+	userID := middleware.GetUserID(ctx)
+	userRole := middleware.GetSpaceRole(ctx)
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(apikey.Instance.GetScopes(userID, userRole, role)))
+}
+
+// @Summary API ключ
+// @Tags API ключи
+// @Description API ключ
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID ключа"
+// @Success 200 {object} apimodels.Response{data=apikeyapimodels.ApiKeyView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 404
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/api_key/{id} [get]
+func (c *apiKeyApiController) get(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	resp, err := apikey.Instance.Get(spaceID, id)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения API ключа")
+	}
+	if resp == nil {
+		return ctx.SendStatus(fiber.StatusNotFound)
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Изменение API ключа
+// @Tags API ключи
+// @Description Изменение названия, роли, областей доступа и срока действия ключа
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID ключа"
+// @Param	body body	 apikeyapimodels.ApiKeyData	true	"request body"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/api_key/{id} [put]
+func (c *apiKeyApiController) update(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	var payload apikeyapimodels.ApiKeyData
+	if err = c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err = payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	userID := middleware.GetUserID(ctx)
+	spaceID := middleware.GetUserSpace(ctx)
+	userRole := middleware.GetSpaceRole(ctx)
+	hMsg, err := apikey.Instance.Update(spaceID, id, userID, userRole, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка изменения API ключа")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Ротация API ключа
+// @Tags API ключи
+// @Description Выпуск нового ключа с теми же областями доступа, прежний ключ перестает действовать сразу
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID ключа"
+// @Success 200 {object} apimodels.Response{data=apikeyapimodels.ApiKeySecret}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/api_key/{id}/rotate [put]
+func (c *apiKeyApiController) rotate(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	userID := middleware.GetUserID(ctx)
+	spaceID := middleware.GetUserSpace(ctx)
+	userRole := middleware.GetSpaceRole(ctx)
+	resp, hMsg, err := apikey.Instance.Rotate(spaceID, id, userID, userRole)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка ротации API ключа")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Отзыв API ключа
+// @Tags API ключи
+// @Description Отзыв ключа, запросы по нему отклоняются. История действий, выполненных по ключу, сохраняется
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID ключа"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/api_key/{id}/revoke [put]
+func (c *apiKeyApiController) revoke(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	userID := middleware.GetUserID(ctx)
+	spaceID := middleware.GetUserSpace(ctx)
+	hMsg, err := apikey.Instance.Revoke(spaceID, id, userID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка отзыва API ключа")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
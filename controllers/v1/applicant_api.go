@@ -5,13 +5,16 @@ import (
 	"hr-tools-backend/controllers"
 	"hr-tools-backend/lib/applicant"
 	applicanthistoryhandler "hr-tools-backend/lib/applicant-history"
+	datascope "hr-tools-backend/lib/data-scope"
 	filestorage "hr-tools-backend/lib/file-storage"
 	messagetemplate "hr-tools-backend/lib/message-template"
+	resumeparser "hr-tools-backend/lib/resume-parser"
 	"hr-tools-backend/lib/utils/helpers"
 	"hr-tools-backend/lib/vk"
 	"hr-tools-backend/middleware"
 	apimodels "hr-tools-backend/models/api"
 	applicantapimodels "hr-tools-backend/models/api/applicant"
+	candidateapimodels "hr-tools-backend/models/api/candidate"
 	surveyapimodels "hr-tools-backend/models/api/survey"
 	dbmodels "hr-tools-backend/models/db"
 	"io"
@@ -29,8 +32,8 @@ func InitApplicantApiRouters(app *fiber.App) {
 	app.Route("applicant", func(router fiber.Router) {
 		router.Use(middleware.LicenseRequired())
 		router.Use(middleware.RbacMiddleware())
-		router.Get("doc/:id", controller.GetDoc)       // скачать документ по id
-		router.Delete("doc/:id", controller.deleteDoc) // удлить документ по id
+		router.Get("doc/:id", middleware.DataScopeRequired(datascope.EntityFile), controller.GetDoc)       // скачать документ по id
+		router.Delete("doc/:id", middleware.DataScopeRequired(datascope.EntityFile), controller.deleteDoc) // удлить документ по id
 		router.Post("list", controller.list)
 		router.Post("", controller.create)
 		router.Route("multi-actions", func(mRouter fiber.Router) {
@@ -39,17 +42,24 @@ func InitApplicantApiRouters(app *fiber.App) {
 			mRouter.Put("export_xls", controller.multiExportXls)
 			mRouter.Put("send_email", controller.multiSendMail)
 		})
-		router.Get("file/:id", controller.getFile) // получить файл
+		router.Get("file/:id", middleware.DataScopeRequired(datascope.EntityFile), controller.getFile) // получить файл
+		router.Put("analyze-retry/video/:id", middleware.DataScopeRequired(datascope.EntityVideoAnalyze), controller.videoRetry)
+		router.Put("analyze-skip/video/:id", middleware.DataScopeRequired(datascope.EntityVideoAnalyze), controller.videoSkip)
 
+		// маршруты с :id регистрируются последними, проверка области видимости перехватывает все вложенные пути
 		router.Route(":id", func(idRouter fiber.Router) {
+			idRouter.Use(middleware.DataScopeRequired(datascope.EntityApplicant))
 			idRouter.Post("upload-resume", controller.UploadResume) // загрузить резюме кандидата
 			idRouter.Post("upload-doc", controller.UploadDoc)       // загрузить документ кандидата
 			idRouter.Post("upload-photo", controller.uploadPhoto)   // загрузить фото кандидата
 			idRouter.Delete("photo", controller.deletePhoto)
 			idRouter.Delete("resume", controller.deleteResume)
-			idRouter.Get("doc/list", controller.GetDocList) // получить список документов кандидата
-			idRouter.Get("resume", controller.GetResume)    // скачать резюме кандидата
-			idRouter.Get("photo", controller.getPhoto)      // скачать фото кандидата
+			idRouter.Get("resume/suggestion", controller.getResumeSuggestion)        // данные, извлеченные из резюме
+			idRouter.Put("resume/suggestion", controller.applyResumeSuggestion)      // перенести данные из резюме в профиль
+			idRouter.Delete("resume/suggestion", controller.dismissResumeSuggestion) // отклонить данные из резюме
+			idRouter.Get("doc/list", controller.GetDocList)                          // получить список документов кандидата
+			idRouter.Get("resume", controller.GetResume)                             // скачать резюме кандидата
+			idRouter.Get("photo", controller.getPhoto)                               // скачать фото кандидата
 			idRouter.Get("", controller.get)
 			idRouter.Put("", controller.update)
 			idRouter.Put("tag", controller.addTag)
@@ -57,15 +67,15 @@ func InitApplicantApiRouters(app *fiber.App) {
 			idRouter.Put("change_stage", controller.changeStage)
 			idRouter.Put("join", controller.join)
 			idRouter.Put("isolate", controller.isolate)
+			idRouter.Get("duplicates", controller.duplicates) // возможные дубли с оценкой уверенности
+			idRouter.Put("merge", controller.merge)           // объединение с выбором значений полей
 			idRouter.Put("changes", controller.changes)
 			idRouter.Put("note", controller.note)
 			idRouter.Put("reject", controller.reject)
+			idRouter.Post("consider", controller.consider) // рассмотреть на другую вакансию
 			idRouter.Put("survey", controller.surveyUpdate)
 			idRouter.Put("survey_regen", controller.surveyRegen)
 		})
-
-		router.Put("analyze-retry/video/:id", controller.videoRetry)
-		router.Put("analyze-skip/video/:id", controller.videoSkip)
 	})
 }
 
@@ -107,6 +117,97 @@ func (c *applicantApiController) UploadResume(ctx *fiber.Ctx) error {
 	if err != nil {
 		return c.SendError(ctx, logger, err, "Ошибка сохранения файла резюме")
 	}
+	// данные из резюме извлекаются в фоне, результат доступен в applicant/{id}/resume/suggestion
+	go resumeparser.Instance.Parse(spaceID, applicantID, file.Filename, fileBody)
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Данные, извлеченные из резюме
+// @Tags Кандидат
+// @Description Данные, извлеченные из последнего загруженного резюме, для проверки перед переносом в профиль кандидата. Возвращаются только поля, отличающиеся от профиля
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID кандидата"
+// @Success 200 {object} apimodels.Response{data=applicantapimodels.ResumeSuggestionView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/applicant/{id}/resume/suggestion [get]
+func (c *applicantApiController) getResumeSuggestion(ctx *fiber.Ctx) error {
+	applicantID, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	resp, err := resumeparser.Instance.GetSuggestion(spaceID, applicantID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения данных резюме")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Перенести данные из резюме в профиль
+// @Tags Кандидат
+// @Description Перенести выбранные поля, извлеченные из резюме, в профиль кандидата
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID кандидата"
+// @Param	body body	 applicantapimodels.ApplyResumeSuggestionRequest	true	"request body"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/applicant/{id}/resume/suggestion [put]
+func (c *applicantApiController) applyResumeSuggestion(ctx *fiber.Ctx) error {
+	applicantID, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	var payload applicantapimodels.ApplyResumeSuggestionRequest
+	if err = c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err = payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	userID := middleware.GetUserID(ctx)
+	hMsg, err := resumeparser.Instance.ApplySuggestion(spaceID, applicantID, userID, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка переноса данных резюме в профиль кандидата")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Отклонить данные из резюме
+// @Tags Кандидат
+// @Description Отклонить данные, извлеченные из резюме
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID кандидата"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/applicant/{id}/resume/suggestion [delete]
+func (c *applicantApiController) dismissResumeSuggestion(ctx *fiber.Ctx) error {
+	applicantID, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	hMsg, err := resumeparser.Instance.DismissSuggestion(spaceID, applicantID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка отклонения данных резюме")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
 	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
 }
 
@@ -398,7 +499,8 @@ func (c *applicantApiController) list(ctx *fiber.Ctx) error {
 		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
 	}
 	spaceID := middleware.GetUserSpace(ctx)
-	list, rowCount, err := applicant.Instance.ListOfApplicant(spaceID, payload)
+	userID := middleware.GetUserID(ctx)
+	list, rowCount, err := applicant.Instance.ListOfApplicant(spaceID, userID, payload)
 	if err != nil {
 		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения списка кандидатов")
 	}
@@ -426,10 +528,13 @@ func (c *applicantApiController) create(ctx *fiber.Ctx) error {
 	}
 	spaceID := middleware.GetUserSpace(ctx)
 	userID := middleware.GetUserID(ctx)
-	id, err := applicant.Instance.CreateApplicant(spaceID, userID, payload)
+	id, hMsg, err := applicant.Instance.CreateApplicant(spaceID, userID, payload)
 	if err != nil {
 		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка создания кандидата")
 	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
 	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(id))
 }
 
@@ -450,7 +555,8 @@ func (c *applicantApiController) get(ctx *fiber.Ctx) error {
 	}
 
 	spaceID := middleware.GetUserSpace(ctx)
-	resp, err := applicant.Instance.GetApplicant(spaceID, id)
+	userID := middleware.GetUserID(ctx)
+	resp, err := applicant.Instance.GetApplicant(spaceID, id, userID)
 	if err != nil {
 		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения данных кандидата")
 	}
@@ -485,10 +591,13 @@ func (c *applicantApiController) update(ctx *fiber.Ctx) error {
 
 	spaceID := middleware.GetUserSpace(ctx)
 	userID := middleware.GetUserID(ctx)
-	err = applicant.Instance.UpdateApplicant(spaceID, id, userID, payload)
+	hMsg, err := applicant.Instance.UpdateApplicant(spaceID, id, userID, payload)
 	if err != nil {
 		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка обновления данных кандидата")
 	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
 	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
 }
 
@@ -645,6 +754,64 @@ func (c *applicantApiController) changeStage(ctx *fiber.Ctx) error {
 	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
 }
 
+// @Summary (Дубли) Возможные дубликаты кандидата
+// @Tags Кандидат
+// @Description (Дубли) Возможные дубликаты кандидата на вакансии по убыванию уверенности: с учетом транслитерации и опечаток в ФИО, формата телефона, даты рождения и резюме
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "Идентификатор кандидата"
+// @Success 200 {object} apimodels.Response{data=[]applicantapimodels.DuplicateSuggestion}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/applicant/{id}/duplicates [get]
+func (c *applicantApiController) duplicates(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	list, err := applicant.Instance.ListDuplicates(spaceID, id)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка поиска дублей кандидата")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(list))
+}
+
+// @Summary (Дубли) Объединение кандидатов с выбором значений
+// @Tags Кандидат
+// @Description (Дубли) Объединение кандидатов: дубликат переносится в архив, значения указанных полей берутся из дубликата
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "Идентификатор основного кандидата"
+// @Param	body body	 applicantapimodels.MergeRequest	true	"request body"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/applicant/{id}/merge [put]
+func (c *applicantApiController) merge(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+	var payload applicantapimodels.MergeRequest
+	if err = c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err = payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	userID := middleware.GetUserID(ctx)
+	err = applicant.Instance.MergeDuplicate(spaceID, id, userID, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка объединения кандидатов")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
 // @Summary Лог действий
 // @Tags Кандидат
 // @Description Лог действий
@@ -737,6 +904,43 @@ func (c *applicantApiController) reject(ctx *fiber.Ctx) error {
 	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
 }
 
+// @Summary Рассмотреть кандидата на другую вакансию
+// @Tags Кандидат
+// @Description Копирование кандидата с резюме и документами на этап "Добавлен" другой вакансии
+// @Param   Authorization	 header		string	true	"Authorization token"
+// @Param   id          	 path    	string  true    "Идентификатор кандидата"
+// @Param	body body	 candidateapimodels.ConsiderRequest	true	"request body"
+// @Success 200 {object} apimodels.Response{data=string}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/applicant/{id}/consider [post]
+func (c *applicantApiController) consider(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+	var payload candidateapimodels.ConsiderRequest
+	if err = c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err = payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	userID := middleware.GetUserID(ctx)
+	spaceID := middleware.GetUserSpace(ctx)
+	newID, hMsg, err := applicant.Instance.ConsiderForVacancy(spaceID, userID, id, payload.VacancyID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка добавления кандидата на вакансию")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(newID))
+}
+
 // @Summary Отклонить кандидатов
 // @Tags Кандидат
 // @Description Отклонить кандидатов
@@ -844,7 +1048,8 @@ func (c *applicantApiController) multiExportXls(ctx *fiber.Ctx) error {
 	}
 
 	spaceID := middleware.GetUserSpace(ctx)
-	data, err := applicant.Instance.ExportToXls(spaceID, payload)
+	userID := middleware.GetUserID(ctx)
+	data, err := applicant.Instance.ExportToXls(spaceID, userID, payload)
 	if err != nil {
 		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка выгрузки списка кандидатов в Excel")
 	}
@@ -0,0 +1,246 @@
+package apiv1
+
+import (
+	"hr-tools-backend/controllers"
+	approvalroutehandler "hr-tools-backend/lib/aproval-task/route"
+	"hr-tools-backend/middleware"
+	apimodels "hr-tools-backend/models/api"
+	vacancyapimodels "hr-tools-backend/models/api/vacancy"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type approvalRouteApiController struct {
+	controllers.BaseAPIController
+}
+
+func InitApprovalRouteApiRouters(app *fiber.App) {
+	controller := approvalRouteApiController{}
+	app.Route("approval_route", func(router fiber.Router) {
+		router.Use(middleware.LicenseRequired())
+		router.Use(middleware.RbacMiddleware())
+		router.Post("", controller.create)
+		router.Get("list", controller.list)
+		router.Route("delegate", func(delegate fiber.Router) {
+			delegate.Get("list", controller.delegateList)
+			delegate.Put("", controller.delegateSave)
+			delegate.Delete(":userId", controller.delegateDelete)
+		})
+		router.Route(":id", func(idRoute fiber.Router) {
+			idRoute.Get("", controller.get)
+			idRoute.Put("", controller.update)
+			idRoute.Delete("", controller.delete)
+		})
+	})
+}
+
+// @Summary Создание маршрута согласования
+// @Tags Маршруты согласования заявок
+// @Description Создание шаблона маршрута согласования заявок для компании/подразделения/структуры
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param	body body	 vacancyapimodels.ApprovalRouteData	true	"request body"
+// @Success 200 {object} apimodels.Response{data=string}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/approval_route [post]
+func (c *approvalRouteApiController) create(ctx *fiber.Ctx) error {
+	var payload vacancyapimodels.ApprovalRouteData
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err := payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	id, hMsg, err := approvalroutehandler.Instance.Create(spaceID, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка создания маршрута согласования")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(id))
+}
+
+// @Summary Список маршрутов согласования
+// @Tags Маршруты согласования заявок
+// @Description Список маршрутов согласования
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Success 200 {object} apimodels.Response{data=[]vacancyapimodels.ApprovalRouteView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/approval_route/list [get]
+func (c *approvalRouteApiController) list(ctx *fiber.Ctx) error {
+	spaceID := middleware.GetUserSpace(ctx)
+	list, err := approvalroutehandler.Instance.List(spaceID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения списка маршрутов согласования")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(list))
+}
+
+// @Summary Маршрут согласования
+// @Tags Маршруты согласования заявок
+// @Description Маршрут согласования
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID маршрута"
+// @Success 200 {object} apimodels.Response{data=vacancyapimodels.ApprovalRouteView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 404
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/approval_route/{id} [get]
+func (c *approvalRouteApiController) get(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	resp, err := approvalroutehandler.Instance.Get(spaceID, id)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения маршрута согласования")
+	}
+	if resp == nil {
+		return ctx.SendStatus(fiber.StatusNotFound)
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Изменение маршрута согласования
+// @Tags Маршруты согласования заявок
+// @Description Изменение маршрута согласования, на сформированные ранее цепочки заявок не влияет
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID маршрута"
+// @Param	body body	 vacancyapimodels.ApprovalRouteData	true	"request body"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/approval_route/{id} [put]
+func (c *approvalRouteApiController) update(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	var payload vacancyapimodels.ApprovalRouteData
+	if err = c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err = payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	hMsg, err := approvalroutehandler.Instance.Update(spaceID, id, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка изменения маршрута согласования")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Удаление маршрута согласования
+// @Tags Маршруты согласования заявок
+// @Description Удаление маршрута согласования
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID маршрута"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/approval_route/{id} [delete]
+func (c *approvalRouteApiController) delete(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	err = approvalroutehandler.Instance.Delete(spaceID, id)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка удаления маршрута согласования")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Список заместителей согласующих
+// @Tags Маршруты согласования заявок
+// @Description Заместители получают задачи согласования, пока согласующий не работает (отпуск, увольнение)
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Success 200 {object} apimodels.Response{data=[]vacancyapimodels.ApprovalDelegateView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/approval_route/delegate/list [get]
+func (c *approvalRouteApiController) delegateList(ctx *fiber.Ctx) error {
+	spaceID := middleware.GetUserSpace(ctx)
+	list, err := approvalroutehandler.Instance.ListDelegates(spaceID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения списка заместителей")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(list))
+}
+
+// @Summary Назначение заместителя согласующего
+// @Tags Маршруты согласования заявок
+// @Description Назначение заместителя согласующего, ранее назначенный заместитель заменяется
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param	body body	 vacancyapimodels.ApprovalDelegateData	true	"request body"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/approval_route/delegate [put]
+func (c *approvalRouteApiController) delegateSave(ctx *fiber.Ctx) error {
+	var payload vacancyapimodels.ApprovalDelegateData
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err := payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	hMsg, err := approvalroutehandler.Instance.SaveDelegate(spaceID, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка назначения заместителя")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Удаление заместителя согласующего
+// @Tags Маршруты согласования заявок
+// @Description Удаление заместителя согласующего
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   userId          	path    string  				    	true         "ID согласующего"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/approval_route/delegate/{userId} [delete]
+func (c *approvalRouteApiController) delegateDelete(ctx *fiber.Ctx) error {
+	userID, err := c.GetIDByKey(ctx, "userId")
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	err = approvalroutehandler.Instance.DeleteDelegate(spaceID, userID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка удаления заместителя")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
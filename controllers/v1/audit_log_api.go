@@ -0,0 +1,79 @@
+package apiv1
+
+import (
+	"fmt"
+	"hr-tools-backend/controllers"
+	auditlog "hr-tools-backend/lib/audit-log"
+	"hr-tools-backend/lib/utils/helpers"
+	"hr-tools-backend/middleware"
+	apimodels "hr-tools-backend/models/api"
+	auditlogapimodels "hr-tools-backend/models/api/audit-log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type auditLogApiController struct {
+	controllers.BaseAPIController
+}
+
+func InitAuditLogApiRouters(app *fiber.App) {
+	controller := auditLogApiController{}
+	app.Route("audit_log", func(router fiber.Router) {
+		router.Use(middleware.RbacMiddleware())
+		router.Post("list", controller.list)
+		router.Post("export_csv", controller.exportCsv)
+	})
+}
+
+// @Summary Журнал аудита
+// @Tags Журнал аудита
+// @Description Журнал действий пользователей, API ключей и системы в спейсе
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param	body body	 auditlogapimodels.Filter	true	"request filter"
+// @Success 200 {object} apimodels.ScrollerResponse{data=[]auditlogapimodels.View}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/audit_log/list [post]
+func (c *auditLogApiController) list(ctx *fiber.Ctx) error {
+	var payload auditlogapimodels.Filter
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	list, rowCount, err := auditlog.Instance.List(spaceID, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения журнала аудита")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewScrollerResponse(list, rowCount))
+}
+
+// @Summary Выгрузить журнал аудита в CSV
+// @Tags Журнал аудита
+// @Description Выгрузка журнала аудита по фильтру в CSV (разделитель ";")
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param	body body	 auditlogapimodels.Filter	true	"request filter"
+// @Success 200
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/audit_log/export_csv [post]
+func (c *auditLogApiController) exportCsv(ctx *fiber.Ctx) error {
+	var payload auditlogapimodels.Filter
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	data, err := auditlog.Instance.ExportCsv(spaceID, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка выгрузки журнала аудита")
+	}
+	fileName := fmt.Sprintf("audit-log-%v.csv", time.Now().Format("20060102-150405"))
+	ctx.Set("Content-Type", "text/csv; charset=utf-8")
+	ctx.Set(fiber.HeaderContentDisposition, `attachment; filename="`+fileName+`"`)
+	ctx.Set(helpers.HeaderLogIgnore, "true")
+	return ctx.SendStream(data)
+}
@@ -2,8 +2,13 @@ package apiv1
 
 import (
 	"hr-tools-backend/controllers"
+	authsession "hr-tools-backend/lib/auth-session"
+	passwordpolicy "hr-tools-backend/lib/password-policy"
 	spaceauthhandler "hr-tools-backend/lib/space/auth"
+	twofactor "hr-tools-backend/lib/two-factor"
+	authutils "hr-tools-backend/lib/utils/auth-utils"
 	"hr-tools-backend/middleware"
+	"hr-tools-backend/models"
 	apimodels "hr-tools-backend/models/api"
 	authapimodels "hr-tools-backend/models/api/auth"
 
@@ -18,16 +23,28 @@ func InitAuthApiRouters(app *fiber.App) {
 	controller := authApiController{}
 	app.Route("auth", func(router fiber.Router) {
 		router.Post("login", controller.login)
+		router.Post("2fa/login", controller.loginTwoFactor)
+		router.Post("2fa/login-setup", controller.loginTwoFactorSetup)
 		router.Post("refresh-token", controller.refreshToken)
 		router.Post("recovery", controller.recovery)
 		router.Post("reset", controller.reset)
+		router.Get("password-policy", controller.passwordPolicy)
 		router.Use(middleware.AuthorizationRequired()).Get("me", controller.me)
+		router.Use(middleware.UserRequired())
+		router.Post("logout", controller.logout)
+		router.Get("sessions", controller.sessionList)
+		router.Delete("sessions/:id", controller.sessionRevoke)
+		router.Get("2fa", controller.twoFactorStatus)
+		router.Post("2fa/setup", controller.twoFactorSetup)
+		router.Post("2fa/enable", controller.twoFactorEnable)
+		router.Post("2fa/disable", controller.twoFactorDisable)
+		router.Post("2fa/recovery-codes", controller.twoFactorRecoveryCodes)
 	})
 }
 
 // @Summary Аутентификация пользователя
 // @Tags Аутентификация пользователей
-// @Description Аутентификация пользователя
+// @Description Аутентификация пользователя. Если у пользователя подключена (или обязательна в организации) двухфакторная аутентификация, токены не выдаются: в ответе two_factor_required и two_factor_token для второго шага входа
 // @Param	body				body		authapimodels.LoginRequest	true	"request body"
 // @Success 200 {object} apimodels.Response{data=authapimodels.JWTResponse}
 // @Failure 400 {object} apimodels.Response
@@ -43,7 +60,7 @@ func (c *authApiController) login(ctx *fiber.Ctx) error {
 	if err := payload.Validate(); err != nil {
 		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
 	}
-	resp, err := spaceauthhandler.Instance.Login(payload.Email, payload.Password)
+	resp, err := spaceauthhandler.Instance.Login(payload.Email, payload.Password, c.GetClientInfo(ctx))
 	if err != nil {
 		return ctx.Status(fiber.StatusUnauthorized).JSON(apimodels.NewError(err.Error()))
 	}
@@ -69,7 +86,7 @@ func (c *authApiController) me(ctx *fiber.Ctx) error {
 
 // @Summary Обновить JWT
 // @Tags Аутентификация пользователей
-// @Description Обновить JWT
+// @Description Обновить JWT. Refresh токен одноразовый, в ответе выдается новый
 // @Param	body				body		authapimodels.JWTRefreshRequest	true	"request body"
 // @Success 200 {object} apimodels.Response{data=authapimodels.JWTResponse}
 // @Failure 400 {object} apimodels.Response
@@ -85,7 +102,7 @@ func (c *authApiController) refreshToken(ctx *fiber.Ctx) error {
 	if err := payload.Validate(); err != nil {
 		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
 	}
-	resp, err := spaceauthhandler.Instance.RefreshToken(ctx, payload.RefreshToken)
+	resp, err := spaceauthhandler.Instance.RefreshToken(payload.RefreshToken, c.GetClientInfo(ctx))
 	if err != nil {
 		return ctx.SendStatus(fiber.StatusUnauthorized)
 	}
@@ -141,3 +158,238 @@ func (c *authApiController) reset(ctx *fiber.Ctx) error {
 	}
 	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
 }
+
+// @Summary Требования к паролю
+// @Tags Аутентификация пользователей
+// @Description Требования к новому паролю при регистрации, сбросе и смене пароля
+// @Success 200 {object} apimodels.Response{data=authapimodels.PasswordPolicy}
+// @router /api/v1/auth/password-policy [get]
+func (c *authApiController) passwordPolicy(ctx *fiber.Ctx) error {
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(passwordpolicy.Instance.GetPolicy()))
+}
+
+// @Summary Второй шаг входа
+// @Tags Аутентификация пользователей
+// @Description Проверка кода из приложения-аутентификатора или кода восстановления. Если двухфакторная аутентификация обязательна и подключается при входе, в ответе коды восстановления
+// @Param	body				body		authapimodels.TwoFactorLoginRequest	true	"request body"
+// @Success 200 {object} apimodels.Response{data=authapimodels.JWTResponse}
+// @Failure 400 {object} apimodels.Response
+// @Failure 401 {object} apimodels.Response
+// @router /api/v1/auth/2fa/login [post]
+func (c *authApiController) loginTwoFactor(ctx *fiber.Ctx) error {
+	var payload authapimodels.TwoFactorLoginRequest
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err := payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+	resp, err := spaceauthhandler.Instance.LoginTwoFactor(payload.TwoFactorToken, payload.Code, c.GetClientInfo(ctx))
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(apimodels.NewError(err.Error()))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Подключение двухфакторной аутентификации при входе
+// @Tags Аутентификация пользователей
+// @Description Секрет для приложения-аутентификатора, когда двухфакторная аутентификация обязательна, но еще не подключена (two_factor_setup_required). Подключение подтверждается кодом на втором шаге входа
+// @Param	body				body		authapimodels.TwoFactorChallengeRequest	true	"request body"
+// @Success 200 {object} apimodels.Response{data=authapimodels.TwoFactorSetup}
+// @Failure 400 {object} apimodels.Response
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/auth/2fa/login-setup [post]
+func (c *authApiController) loginTwoFactorSetup(ctx *fiber.Ctx) error {
+	var payload authapimodels.TwoFactorChallengeRequest
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err := payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+	resp, hMsg, err := spaceauthhandler.Instance.LoginTwoFactorSetup(payload.TwoFactorToken)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка подключения двухфакторной аутентификации")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Выход
+// @Tags Аутентификация пользователей
+// @Description Завершение текущей сессии, JWT и refresh токен сессии перестают действовать
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/auth/logout [post]
+func (c *authApiController) logout(ctx *fiber.Ctx) error {
+	_, err := authsession.Instance.Revoke(models.AuthSpaceUser, middleware.GetUserID(ctx), authutils.GetSessionID(ctx))
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка завершения сессии")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Список сессий
+// @Tags Аутентификация пользователей
+// @Description Действующие сессии пользователя (устройства, с которых выполнен вход)
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Success 200 {object} apimodels.Response{data=[]authapimodels.SessionView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/auth/sessions [get]
+func (c *authApiController) sessionList(ctx *fiber.Ctx) error {
+	resp, err := authsession.Instance.List(models.AuthSpaceUser, middleware.GetUserID(ctx), authutils.GetSessionID(ctx))
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения списка сессий")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Завершение сессии
+// @Tags Аутентификация пользователей
+// @Description Завершение сессии на другом устройстве
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID сессии"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/auth/sessions/{id} [delete]
+func (c *authApiController) sessionRevoke(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+	hMsg, err := authsession.Instance.Revoke(models.AuthSpaceUser, middleware.GetUserID(ctx), id)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка завершения сессии")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Состояние двухфакторной аутентификации
+// @Tags Аутентификация пользователей
+// @Description Состояние двухфакторной аутентификации текущего пользователя
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Success 200 {object} apimodels.Response{data=authapimodels.TwoFactorStatus}
+// @Failure 400 {object} apimodels.Response
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/auth/2fa [get]
+func (c *authApiController) twoFactorStatus(ctx *fiber.Ctx) error {
+	resp, err := spaceauthhandler.Instance.GetTwoFactorStatus(middleware.GetUserID(ctx))
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения состояния двухфакторной аутентификации")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Секрет для подключения двухфакторной аутентификации
+// @Tags Аутентификация пользователей
+// @Description Секрет и otpauth ссылка для приложения-аутентификатора, подключение подтверждается кодом (2fa/enable)
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Success 200 {object} apimodels.Response{data=authapimodels.TwoFactorSetup}
+// @Failure 400 {object} apimodels.Response
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/auth/2fa/setup [post]
+func (c *authApiController) twoFactorSetup(ctx *fiber.Ctx) error {
+	resp, hMsg, err := spaceauthhandler.Instance.SetupTwoFactor(middleware.GetUserID(ctx))
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка подключения двухфакторной аутентификации")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Подключение двухфакторной аутентификации
+// @Tags Аутентификация пользователей
+// @Description Подтверждение подключения кодом из приложения-аутентификатора, в ответе коды восстановления
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param	body				body		authapimodels.TwoFactorCodeRequest	true	"request body"
+// @Success 200 {object} apimodels.Response{data=authapimodels.RecoveryCodesView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/auth/2fa/enable [post]
+func (c *authApiController) twoFactorEnable(ctx *fiber.Ctx) error {
+	var payload authapimodels.TwoFactorCodeRequest
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err := payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+	codes, hMsg, err := twofactor.Instance.Enable(models.AuthSpaceUser, middleware.GetUserID(ctx), payload.Code)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка подключения двухфакторной аутентификации")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(authapimodels.RecoveryCodesView{RecoveryCodes: codes}))
+}
+
+// @Summary Отключение двухфакторной аутентификации
+// @Tags Аутентификация пользователей
+// @Description Отключение двухфакторной аутентификации, недоступно, если она обязательна в организации
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param	body				body		authapimodels.TwoFactorCodeRequest	true	"request body"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/auth/2fa/disable [post]
+func (c *authApiController) twoFactorDisable(ctx *fiber.Ctx) error {
+	var payload authapimodels.TwoFactorCodeRequest
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err := payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+	hMsg, err := spaceauthhandler.Instance.DisableTwoFactor(middleware.GetUserID(ctx), payload.Code)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка отключения двухфакторной аутентификации")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Новые коды восстановления
+// @Tags Аутентификация пользователей
+// @Description Выпуск новых кодов восстановления, прежние коды перестают действовать
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param	body				body		authapimodels.TwoFactorCodeRequest	true	"request body"
+// @Success 200 {object} apimodels.Response{data=authapimodels.RecoveryCodesView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/auth/2fa/recovery-codes [post]
+func (c *authApiController) twoFactorRecoveryCodes(ctx *fiber.Ctx) error {
+	var payload authapimodels.TwoFactorCodeRequest
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err := payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+	codes, hMsg, err := twofactor.Instance.RegenerateRecoveryCodes(models.AuthSpaceUser, middleware.GetUserID(ctx), payload.Code)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка выпуска кодов восстановления")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(authapimodels.RecoveryCodesView{RecoveryCodes: codes}))
+}
@@ -0,0 +1,231 @@
+package apiv1
+
+import (
+	"hr-tools-backend/controllers"
+	"hr-tools-backend/lib/automation"
+	"hr-tools-backend/middleware"
+	apimodels "hr-tools-backend/models/api"
+	automationapimodels "hr-tools-backend/models/api/automation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type automationApiController struct {
+	controllers.BaseAPIController
+}
+
+func InitAutomationApiRouters(app *fiber.App) {
+	controller := automationApiController{}
+	app.Route("automation_rule", func(router fiber.Router) {
+		router.Use(middleware.LicenseRequired())
+		router.Use(middleware.RbacMiddleware())
+		router.Post("", controller.create)
+		router.Get("list", controller.list)
+		router.Post("log", controller.log)
+		router.Route(":id", func(idRoute fiber.Router) {
+			idRoute.Get("", controller.get)
+			idRoute.Put("", controller.update)
+			idRoute.Delete("", controller.delete)
+			idRoute.Post("dry_run", controller.dryRun)
+		})
+	})
+}
+
+// @Summary Создание правила автоматизации
+// @Tags Правила автоматизации
+// @Description Создание правила автоматизации воронки подбора: событие, условия по кандидату и действия
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param	body body	 automationapimodels.AutomationRuleData	true	"request body"
+// @Success 200 {object} apimodels.Response{data=string}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/automation_rule [post]
+func (c *automationApiController) create(ctx *fiber.Ctx) error {
+	var payload automationapimodels.AutomationRuleData
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err := payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	id, hMsg, err := automation.Instance.Create(spaceID, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка создания правила автоматизации")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(id))
+}
+
+// @Summary Список правил автоматизации
+// @Tags Правила автоматизации
+// @Description Список правил автоматизации
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Success 200 {object} apimodels.Response{data=[]automationapimodels.AutomationRuleView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/automation_rule/list [get]
+func (c *automationApiController) list(ctx *fiber.Ctx) error {
+	spaceID := middleware.GetUserSpace(ctx)
+	list, err := automation.Instance.List(spaceID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения списка правил автоматизации")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(list))
+}
+
+// @Summary Журнал выполнения правил автоматизации
+// @Tags Правила автоматизации
+// @Description Журнал выполнения правил автоматизации, включая тестовые запуски
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param	body body	 automationapimodels.LogFilter	true	"request body"
+// @Success 200 {object} apimodels.ScrollerResponse{data=[]automationapimodels.AutomationLogView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/automation_rule/log [post]
+func (c *automationApiController) log(ctx *fiber.Ctx) error {
+	var payload automationapimodels.LogFilter
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	list, rowCount, err := automation.Instance.ListLog(spaceID, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения журнала правил автоматизации")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewScrollerResponse(list, rowCount))
+}
+
+// @Summary Правило автоматизации
+// @Tags Правила автоматизации
+// @Description Правило автоматизации
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID правила"
+// @Success 200 {object} apimodels.Response{data=automationapimodels.AutomationRuleView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 404
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/automation_rule/{id} [get]
+func (c *automationApiController) get(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	resp, err := automation.Instance.Get(spaceID, id)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения правила автоматизации")
+	}
+	if resp == nil {
+		return ctx.SendStatus(fiber.StatusNotFound)
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Изменение правила автоматизации
+// @Tags Правила автоматизации
+// @Description Изменение правила автоматизации
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID правила"
+// @Param	body body	 automationapimodels.AutomationRuleData	true	"request body"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/automation_rule/{id} [put]
+func (c *automationApiController) update(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	var payload automationapimodels.AutomationRuleData
+	if err = c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err = payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	hMsg, err := automation.Instance.Update(spaceID, id, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка изменения правила автоматизации")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Удаление правила автоматизации
+// @Tags Правила автоматизации
+// @Description Удаление правила автоматизации, журнал выполнения сохраняется
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID правила"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/automation_rule/{id} [delete]
+func (c *automationApiController) delete(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	err = automation.Instance.Delete(spaceID, id)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка удаления правила автоматизации")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Проверка правила автоматизации на кандидате
+// @Tags Правила автоматизации
+// @Description Проверка условий правила и список действий, которые будут выполнены для кандидата, без их выполнения
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID правила"
+// @Param	body body	 automationapimodels.DryRunRequest	true	"request body"
+// @Success 200 {object} apimodels.Response{data=automationapimodels.DryRunResult}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/automation_rule/{id}/dry_run [post]
+func (c *automationApiController) dryRun(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	var payload automationapimodels.DryRunRequest
+	if err = c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err = payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	resp, hMsg, err := automation.Instance.DryRun(spaceID, id, payload.ApplicantID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка проверки правила автоматизации")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
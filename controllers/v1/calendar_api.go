@@ -0,0 +1,123 @@
+package apiv1
+
+import (
+	"hr-tools-backend/controllers"
+	"hr-tools-backend/lib/calendar"
+	"hr-tools-backend/middleware"
+	apimodels "hr-tools-backend/models/api"
+	calendarapimodels "hr-tools-backend/models/api/calendar"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type calendarApiController struct {
+	controllers.BaseAPIController
+}
+
+func InitCalendarApiRouters(app *fiber.App) {
+	controller := calendarApiController{}
+	app.Route("calendar", func(router fiber.Router) {
+		router.Get("", controller.get)
+		router.Put("", controller.set)
+		router.Delete("", controller.delete)
+		router.Post("busy", controller.busy)
+	})
+}
+
+// @Summary Подключение календаря
+// @Tags Календарь
+// @Description Подключение календаря текущего пользователя, data = null - календарь не подключен
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Success 200 {object} apimodels.Response{data=calendarapimodels.ConnectionView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/calendar [get]
+func (c *calendarApiController) get(ctx *fiber.Ctx) error {
+	spaceID := middleware.GetUserSpace(ctx)
+	userID := middleware.GetUserID(ctx)
+	resp, err := calendar.Instance.GetConnection(spaceID, userID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения подключения календаря")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Подключить календарь
+// @Tags Календарь
+// @Description Подключить календарь текущего пользователя (CalDAV или ссылка на ics). Учетные данные проверяются при подключении и хранятся в зашифрованном виде
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param	body body	 calendarapimodels.ConnectionData	true	"request body"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/calendar [put]
+func (c *calendarApiController) set(ctx *fiber.Ctx) error {
+	var payload calendarapimodels.ConnectionData
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err := payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	userID := middleware.GetUserID(ctx)
+	hMsg, err := calendar.Instance.SetConnection(spaceID, userID, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка подключения календаря")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Отключить календарь
+// @Tags Календарь
+// @Description Отключить календарь текущего пользователя
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/calendar [delete]
+func (c *calendarApiController) delete(ctx *fiber.Ctx) error {
+	spaceID := middleware.GetUserSpace(ctx)
+	userID := middleware.GetUserID(ctx)
+	err := calendar.Instance.DeleteConnection(spaceID, userID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка отключения календаря")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Занятость пользователей
+// @Tags Календарь
+// @Description Занятые интервалы пользователей по подключенным календарям
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param	body body	 calendarapimodels.BusyRequest	true	"request body"
+// @Success 200 {object} apimodels.Response{data=[]calendarapimodels.BusyView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/calendar/busy [post]
+func (c *calendarApiController) busy(ctx *fiber.Ctx) error {
+	var payload calendarapimodels.BusyRequest
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err := payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	list, err := calendar.Instance.GetBusy(spaceID, payload.UserIDs, payload.DateFrom, payload.DateTo)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения занятости пользователей")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(list))
+}
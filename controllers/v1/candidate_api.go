@@ -0,0 +1,402 @@
+package apiv1
+
+import (
+	"hr-tools-backend/controllers"
+	"hr-tools-backend/lib/candidate"
+	datascope "hr-tools-backend/lib/data-scope"
+	talentpool "hr-tools-backend/lib/talent-pool"
+	"hr-tools-backend/middleware"
+	apimodels "hr-tools-backend/models/api"
+	applicantapimodels "hr-tools-backend/models/api/applicant"
+	candidateapimodels "hr-tools-backend/models/api/candidate"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type candidateApiController struct {
+	controllers.BaseAPIController
+}
+
+func InitCandidateApiRouters(app *fiber.App) {
+	controller := candidateApiController{}
+	app.Route("candidate", func(router fiber.Router) {
+		router.Use(middleware.LicenseRequired())
+		router.Use(middleware.RbacMiddleware())
+		router.Post("list", controller.list)
+		router.Route(":id", func(idRoute fiber.Router) {
+			idRoute.Use(middleware.DataScopeRequired(datascope.EntityCandidate))
+			idRoute.Get("", controller.get)
+			idRoute.Put("", controller.update)
+			idRoute.Post("history", controller.history) // общая история по всем вакансиям
+			idRoute.Get("files", controller.files)      // резюме и документы по всем вакансиям
+		})
+	})
+	app.Route("talent_pool", func(router fiber.Router) {
+		router.Use(middleware.LicenseRequired())
+		router.Use(middleware.RbacMiddleware())
+		router.Post("", controller.createPool)
+		router.Get("list", controller.listPool)
+		router.Route(":id", func(idRoute fiber.Router) {
+			idRoute.Get("", controller.getPool)
+			idRoute.Put("", controller.updatePool)
+			idRoute.Delete("", controller.deletePool)
+			idRoute.Post("candidates", controller.addToPool)
+			idRoute.Delete("candidates/:candidate_id", controller.removeFromPool)
+		})
+	})
+}
+
+// @Summary Список кандидатов
+// @Tags Кандидаты (база)
+// @Description Список кандидатов (людей) по всем вакансиям
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param	body body	 candidateapimodels.CandidateFilter	true	"request filter"
+// @Success 200 {object} apimodels.ScrollerResponse{data=[]candidateapimodels.CandidateView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/candidate/list [post]
+func (c *candidateApiController) list(ctx *fiber.Ctx) error {
+	var payload candidateapimodels.CandidateFilter
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	userID := middleware.GetUserID(ctx)
+	spaceID := middleware.GetUserSpace(ctx)
+	list, rowCount, err := candidate.Instance.List(spaceID, userID, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения списка кандидатов")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewScrollerResponse(list, rowCount))
+}
+
+// @Summary Карточка кандидата
+// @Tags Кандидаты (база)
+// @Description Карточка кандидата с участием в подборе по вакансиям и кадровыми резервами
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID кандидата"
+// @Success 200 {object} apimodels.Response{data=candidateapimodels.CandidateViewExt}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 404
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/candidate/{id} [get]
+func (c *candidateApiController) get(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	userID := middleware.GetUserID(ctx)
+	spaceID := middleware.GetUserSpace(ctx)
+	resp, err := candidate.Instance.Get(spaceID, id, userID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения кандидата")
+	}
+	if resp == nil {
+		return ctx.SendStatus(fiber.StatusNotFound)
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Изменение кандидата
+// @Tags Кандидаты (база)
+// @Description Изменение контактов кандидата, изменения переносятся во все вакансии кандидата
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID кандидата"
+// @Param	body body	 candidateapimodels.CandidateData	true	"request body"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/candidate/{id} [put]
+func (c *candidateApiController) update(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	var payload candidateapimodels.CandidateData
+	if err = c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err = payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	userID := middleware.GetUserID(ctx)
+	spaceID := middleware.GetUserSpace(ctx)
+	hMsg, err := candidate.Instance.Update(spaceID, id, userID, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка изменения кандидата")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary История кандидата
+// @Tags Кандидаты (база)
+// @Description Общая лента действий по всем вакансиям кандидата
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID кандидата"
+// @Param	body body	 applicantapimodels.ApplicantHistoryFilter	true	"request filter"
+// @Success 200 {object} apimodels.ScrollerResponse{data=[]applicantapimodels.ApplicantHistoryView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/candidate/{id}/history [post]
+func (c *candidateApiController) history(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	var payload applicantapimodels.ApplicantHistoryFilter
+	if err = c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	userID := middleware.GetUserID(ctx)
+	spaceID := middleware.GetUserSpace(ctx)
+	list, rowCount, err := candidate.Instance.History(spaceID, id, userID, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения истории кандидата")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewScrollerResponse(list, rowCount))
+}
+
+// @Summary Файлы кандидата
+// @Tags Кандидаты (база)
+// @Description Резюме и документы кандидата по всем вакансиям
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID кандидата"
+// @Success 200 {object} apimodels.Response{data=[]filesapimodels.FileView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/candidate/{id}/files [get]
+func (c *candidateApiController) files(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	userID := middleware.GetUserID(ctx)
+	spaceID := middleware.GetUserSpace(ctx)
+	list, err := candidate.Instance.Files(spaceID, id, userID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения файлов кандидата")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(list))
+}
+
+// @Summary Создание кадрового резерва
+// @Tags Кадровый резерв
+// @Description Создание кадрового резерва (списка кандидатов)
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param	body body	 candidateapimodels.TalentPoolData	true	"request body"
+// @Success 200 {object} apimodels.Response{data=string}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/talent_pool [post]
+func (c *candidateApiController) createPool(ctx *fiber.Ctx) error {
+	var payload candidateapimodels.TalentPoolData
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err := payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	userID := middleware.GetUserID(ctx)
+	spaceID := middleware.GetUserSpace(ctx)
+	id, err := talentpool.Instance.Create(spaceID, userID, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка создания кадрового резерва")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(id))
+}
+
+// @Summary Список кадровых резервов
+// @Tags Кадровый резерв
+// @Description Список кадровых резервов
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Success 200 {object} apimodels.Response{data=[]candidateapimodels.TalentPoolView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/talent_pool/list [get]
+func (c *candidateApiController) listPool(ctx *fiber.Ctx) error {
+	spaceID := middleware.GetUserSpace(ctx)
+	list, err := talentpool.Instance.List(spaceID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения списка кадровых резервов")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(list))
+}
+
+// @Summary Кадровый резерв
+// @Tags Кадровый резерв
+// @Description Кадровый резерв
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID кадрового резерва"
+// @Success 200 {object} apimodels.Response{data=candidateapimodels.TalentPoolView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 404
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/talent_pool/{id} [get]
+func (c *candidateApiController) getPool(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	resp, err := talentpool.Instance.Get(spaceID, id)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения кадрового резерва")
+	}
+	if resp == nil {
+		return ctx.SendStatus(fiber.StatusNotFound)
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Изменение кадрового резерва
+// @Tags Кадровый резерв
+// @Description Изменение кадрового резерва
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID кадрового резерва"
+// @Param	body body	 candidateapimodels.TalentPoolData	true	"request body"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/talent_pool/{id} [put]
+func (c *candidateApiController) updatePool(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	var payload candidateapimodels.TalentPoolData
+	if err = c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err = payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	hMsg, err := talentpool.Instance.Update(spaceID, id, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка изменения кадрового резерва")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Удаление кадрового резерва
+// @Tags Кадровый резерв
+// @Description Удаление кадрового резерва, карточки кандидатов сохраняются
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID кадрового резерва"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/talent_pool/{id} [delete]
+func (c *candidateApiController) deletePool(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	err = talentpool.Instance.Delete(spaceID, id)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка удаления кадрового резерва")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Добавление кандидатов в кадровый резерв
+// @Tags Кадровый резерв
+// @Description Добавление кандидатов в кадровый резерв, уже добавленные кандидаты пропускаются
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID кадрового резерва"
+// @Param	body body	 candidateapimodels.TalentPoolAddRequest	true	"request body"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/talent_pool/{id}/candidates [post]
+func (c *candidateApiController) addToPool(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	var payload candidateapimodels.TalentPoolAddRequest
+	if err = c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err = payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	userID := middleware.GetUserID(ctx)
+	spaceID := middleware.GetUserSpace(ctx)
+	hMsg, err := talentpool.Instance.AddCandidates(spaceID, id, userID, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка добавления кандидатов в кадровый резерв")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Исключение кандидата из кадрового резерва
+// @Tags Кадровый резерв
+// @Description Исключение кандидата из кадрового резерва
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID кадрового резерва"
+// @Param   candidate_id   		path    string  				    	true         "ID кандидата"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/talent_pool/{id}/candidates/{candidate_id} [delete]
+func (c *candidateApiController) removeFromPool(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+	candidateID := ctx.Params("candidate_id")
+	if candidateID == "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError("не указан идентификатор кандидата"))
+	}
+
+	userID := middleware.GetUserID(ctx)
+	spaceID := middleware.GetUserSpace(ctx)
+	hMsg, err := talentpool.Instance.RemoveCandidate(spaceID, id, candidateID, userID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка исключения кандидата из кадрового резерва")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
@@ -0,0 +1,183 @@
+package apiv1
+
+import (
+	"hr-tools-backend/controllers"
+	customfield "hr-tools-backend/lib/custom-field"
+	"hr-tools-backend/middleware"
+	"hr-tools-backend/models"
+	apimodels "hr-tools-backend/models/api"
+	customfieldapimodels "hr-tools-backend/models/api/custom-field"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type customFieldApiController struct {
+	controllers.BaseAPIController
+}
+
+func InitCustomFieldApiRouters(app *fiber.App) {
+	controller := customFieldApiController{}
+	app.Route("custom_fields", func(router fiber.Router) {
+		router.Use(middleware.LicenseRequired())
+		router.Use(middleware.RbacMiddleware())
+		router.Use(middleware.UserRequired())
+		router.Get("list", controller.list)
+		router.Post("", controller.create)
+		router.Route(":id", func(idRoute fiber.Router) {
+			idRoute.Get("", controller.get)
+			idRoute.Put("", controller.update)
+			idRoute.Delete("", controller.delete)
+		})
+	})
+}
+
+// @Summary Список пользовательских полей
+// @Tags Пользовательские поля
+// @Description Описания пользовательских полей спейса, упорядоченные по сущности и порядку отображения
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   entity          		query    string  				    	false         "Сущность (APPLICANT, VACANCY, VACANCY_REQUEST), не указана - все"
+// @Success 200 {object} apimodels.Response{data=[]customfieldapimodels.CustomFieldView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/custom_fields/list [get]
+func (c *customFieldApiController) list(ctx *fiber.Ctx) error {
+	entity := models.CustomFieldEntity(ctx.Query("entity", ""))
+	if entity != "" {
+		if err := entity.Validate(); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+		}
+	}
+	spaceID := middleware.GetUserSpace(ctx)
+	list, err := customfield.Instance.List(spaceID, entity)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения списка пользовательских полей")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(list))
+}
+
+// @Summary Создание пользовательского поля
+// @Tags Пользовательские поля
+// @Description Создание пользовательского поля, код поля уникален в рамках сущности
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param	body body	 customfieldapimodels.CustomFieldData	true	"request body"
+// @Success 200 {object} apimodels.Response{data=string}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/custom_fields [post]
+func (c *customFieldApiController) create(ctx *fiber.Ctx) error {
+	var payload customfieldapimodels.CustomFieldData
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err := payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	userID := middleware.GetUserID(ctx)
+	spaceID := middleware.GetUserSpace(ctx)
+	id, hMsg, err := customfield.Instance.Create(spaceID, userID, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка создания пользовательского поля")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(id))
+}
+
+// @Summary Пользовательское поле
+// @Tags Пользовательские поля
+// @Description Пользовательское поле
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID поля"
+// @Success 200 {object} apimodels.Response{data=customfieldapimodels.CustomFieldView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 404
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/custom_fields/{id} [get]
+func (c *customFieldApiController) get(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	resp, err := customfield.Instance.Get(spaceID, id)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения пользовательского поля")
+	}
+	if resp == nil {
+		return ctx.SendStatus(fiber.StatusNotFound)
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Изменение пользовательского поля
+// @Tags Пользовательские поля
+// @Description Изменение пользовательского поля, сущность, код и тип поля не меняются
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID поля"
+// @Param	body body	 customfieldapimodels.CustomFieldData	true	"request body"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/custom_fields/{id} [put]
+func (c *customFieldApiController) update(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	var payload customfieldapimodels.CustomFieldData
+	if err = c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err = payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	userID := middleware.GetUserID(ctx)
+	spaceID := middleware.GetUserSpace(ctx)
+	hMsg, err := customfield.Instance.Update(spaceID, id, userID, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка изменения пользовательского поля")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Удаление пользовательского поля
+// @Tags Пользовательские поля
+// @Description Удаление пользовательского поля, значения поля перестают отображаться
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID поля"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/custom_fields/{id} [delete]
+func (c *customFieldApiController) delete(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	userID := middleware.GetUserID(ctx)
+	spaceID := middleware.GetUserSpace(ctx)
+	hMsg, err := customfield.Instance.Delete(spaceID, id, userID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка удаления пользовательского поля")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
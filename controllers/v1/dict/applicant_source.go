@@ -0,0 +1,35 @@
+package dict
+
+import (
+	"hr-tools-backend/controllers"
+	externalservices "hr-tools-backend/lib/external-services"
+	"hr-tools-backend/middleware"
+	apimodels "hr-tools-backend/models/api"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type applicantSourceDictApiController struct {
+	controllers.BaseAPIController
+}
+
+func InitApplicantSourceDictApiRouters(app *fiber.App) {
+	controller := applicantSourceDictApiController{}
+	app.Route("applicant_source", func(router fiber.Router) {
+		router.Use(middleware.RbacMiddleware())
+		router.Get("list", controller.list)
+	})
+}
+
+// @Summary Список источников кандидатов
+// @Tags Справочник. Источники кандидатов
+// @Description Список источников кандидатов, включая источники зарегистрированных коннекторов к работным сайтам
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Success 200 {object} apimodels.Response{data=[]models.ApplicantSource}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/dict/applicant_source/list [get]
+func (c *applicantSourceDictApiController) list(ctx *fiber.Ctx) error {
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(externalservices.ApplicantSources()))
+}
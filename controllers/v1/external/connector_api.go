@@ -0,0 +1,257 @@
+package externalapiv1
+
+import (
+	"hr-tools-backend/controllers"
+	externalservices "hr-tools-backend/lib/external-services"
+	"hr-tools-backend/middleware"
+	apimodels "hr-tools-backend/models/api"
+	connectorapimodels "hr-tools-backend/models/api/connector"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type connectorApiController struct {
+	controllers.BaseAPIController
+}
+
+// InitConnectorApiRouters общие маршруты для всех зарегистрированных коннекторов,
+// регистрируются после маршрутов конкретных сайтов (hh, avito)
+func InitConnectorApiRouters(app *fiber.App) {
+	controller := connectorApiController{}
+	app.Get("connectors", controller.list)
+	app.Route(":code", func(router fiber.Router) {
+		router.Get("check_connected", controller.isConnect)
+		router.Get("connect_uri", controller.connect)
+		router.Get("remove", controller.remove)
+		router.Route(":id", func(vacancyRoute fiber.Router) {
+			vacancyRoute.Put("publish", controller.publish)
+			vacancyRoute.Put("update", controller.update)
+			vacancyRoute.Put("close", controller.close)
+			vacancyRoute.Get("status", controller.status)
+		})
+	})
+}
+
+// @Summary Список коннекторов к работным сайтам
+// @Tags Интеграция с работными сайтами
+// @Description Список коннекторов к работным сайтам
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Success 200 {object} apimodels.Response{data=[]connectorapimodels.ConnectorView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/ext/connectors [get]
+func (c *connectorApiController) list(ctx *fiber.Ctx) error {
+	spaceID := middleware.GetUserSpace(ctx)
+	list := externalservices.ListConnectors()
+	result := make([]connectorapimodels.ConnectorView, 0, len(list))
+	for _, connector := range list {
+		connected := connector.Provider.CheckConnected(ctx.UserContext(), spaceID)
+		result = append(result, connectorapimodels.ConnectorConvert(connector, connected))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(result))
+}
+
+// @Summary Проверка подключения к работному сайту
+// @Tags Интеграция с работными сайтами
+// @Description Проверка подключения к работному сайту
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   code          		path    string  				    	true         "код коннектора"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/ext/{code}/check_connected [get]
+func (c *connectorApiController) isConnect(ctx *fiber.Ctx) error {
+	connector, ok := getConnector(ctx)
+	if !ok {
+		return connectorNotFound(ctx)
+	}
+	spaceID := middleware.GetUserSpace(ctx)
+	connected := connector.Provider.CheckConnected(ctx.UserContext(), spaceID)
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(connected))
+}
+
+// @Summary Получение ссылки для авторизации на работном сайте
+// @Tags Интеграция с работными сайтами
+// @Description Получение ссылки для авторизации на работном сайте
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   code          		path    string  				    	true         "код коннектора"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/ext/{code}/connect_uri [get]
+func (c *connectorApiController) connect(ctx *fiber.Ctx) error {
+	connector, ok := getConnector(ctx, externalservices.CapabilityOAuth)
+	if !ok {
+		return connectorNotFound(ctx)
+	}
+	spaceID := middleware.GetUserSpace(ctx)
+	resp, err := connector.Provider.GetConnectUri(spaceID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения ссылки для авторизации на "+connector.Name)
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Удаление авторизации на работном сайте
+// @Tags Интеграция с работными сайтами
+// @Description Удаление авторизации на работном сайте
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   code          		path    string  				    	true         "код коннектора"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/ext/{code}/remove [get]
+func (c *connectorApiController) remove(ctx *fiber.Ctx) error {
+	connector, ok := getConnector(ctx)
+	if !ok {
+		return connectorNotFound(ctx)
+	}
+	spaceID := middleware.GetUserSpace(ctx)
+	err := connector.Provider.RemoveToken(spaceID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка удаления токена авторизации на "+connector.Name)
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Публикация вакансии на работном сайте
+// @Tags Интеграция с работными сайтами
+// @Description Публикация вакансии на работном сайте
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   code          		path    string  				    	true         "код коннектора"
+// @Param   id          		path    string  				    	true         "идентификатор вакансии"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router  /api/v1/space/ext/{code}/{id}/publish [put]
+func (c *connectorApiController) publish(ctx *fiber.Ctx) error {
+	connector, ok := getConnector(ctx, externalservices.CapabilityPublish)
+	if !ok {
+		return connectorNotFound(ctx)
+	}
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+	spaceID := middleware.GetUserSpace(ctx)
+	hMsg, err := connector.Provider.VacancyPublish(ctx.UserContext(), spaceID, id)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка публикация вакансии на "+connector.Name)
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Публикация обновления по вакансии на работном сайте
+// @Tags Интеграция с работными сайтами
+// @Description Публикация обновления по вакансии на работном сайте
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   code          		path    string  				    	true         "код коннектора"
+// @Param   id          		path    string  				    	true         "идентификатор вакансии"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router  /api/v1/space/ext/{code}/{id}/update [put]
+func (c *connectorApiController) update(ctx *fiber.Ctx) error {
+	connector, ok := getConnector(ctx, externalservices.CapabilityPublish)
+	if !ok {
+		return connectorNotFound(ctx)
+	}
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+	spaceID := middleware.GetUserSpace(ctx)
+	hMsg, err := connector.Provider.VacancyUpdate(ctx.UserContext(), spaceID, id)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка обновления вакансии на "+connector.Name)
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Снятие вакансии с публикации на работном сайте
+// @Tags Интеграция с работными сайтами
+// @Description Снятие вакансии с публикации на работном сайте
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   code          		path    string  				    	true         "код коннектора"
+// @Param   id          		path    string  				    	true         "идентификатор вакансии"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router  /api/v1/space/ext/{code}/{id}/close [put]
+func (c *connectorApiController) close(ctx *fiber.Ctx) error {
+	connector, ok := getConnector(ctx, externalservices.CapabilityClose)
+	if !ok {
+		return connectorNotFound(ctx)
+	}
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+	spaceID := middleware.GetUserSpace(ctx)
+	hMsg, err := connector.Provider.VacancyClose(ctx.UserContext(), spaceID, id)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка снятия вакансии с публикации на "+connector.Name)
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Статус размещения на работном сайте
+// @Tags Интеграция с работными сайтами
+// @Description Статус размещения на работном сайте
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   code          		path    string  				    	true         "код коннектора"
+// @Param   id          		path    string  				    	true         "идентификатор вакансии"
+// @Success 200 {object} apimodels.Response{data=vacancyapimodels.ExtVacancyInfo}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/ext/{code}/{id}/status [get]
+func (c *connectorApiController) status(ctx *fiber.Ctx) error {
+	connector, ok := getConnector(ctx, externalservices.CapabilityPublish)
+	if !ok {
+		return connectorNotFound(ctx)
+	}
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+	spaceID := middleware.GetUserSpace(ctx)
+	info, err := connector.Provider.GetVacancyInfo(ctx.UserContext(), spaceID, id)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения статуса размещения объявления на "+connector.Name)
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(info))
+}
+
+func getConnector(ctx *fiber.Ctx, capabilities ...externalservices.Capability) (externalservices.Connector, bool) {
+	connector, ok := externalservices.GetConnector(ctx.Params("code"))
+	if !ok {
+		return externalservices.Connector{}, false
+	}
+	for _, capability := range capabilities {
+		if !connector.Has(capability) {
+			return externalservices.Connector{}, false
+		}
+	}
+	return connector, true
+}
+
+func connectorNotFound(ctx *fiber.Ctx) error {
+	return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError("коннектор не найден или не поддерживает операцию"))
+}
@@ -0,0 +1,299 @@
+package apiv1
+
+import (
+	"hr-tools-backend/controllers"
+	datascope "hr-tools-backend/lib/data-scope"
+	"hr-tools-backend/lib/interview"
+	"hr-tools-backend/middleware"
+	apimodels "hr-tools-backend/models/api"
+	interviewapimodels "hr-tools-backend/models/api/interview"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type interviewApiController struct {
+	controllers.BaseAPIController
+}
+
+func InitInterviewApiRouters(app *fiber.App) {
+	controller := interviewApiController{}
+	app.Route("interview", func(router fiber.Router) {
+		router.Use(middleware.LicenseRequired())
+		router.Post("", controller.create)
+		router.Post("list", controller.list)
+		router.Post("booking", controller.createBooking) // ссылка для самостоятельной записи кандидата
+		router.Route("slot", func(slotRoute fiber.Router) {
+			slotRoute.Post("", controller.createSlot)
+			slotRoute.Post("list", controller.listSlots)
+			slotRoute.Delete(":id", controller.deleteSlot)
+		})
+		router.Route(":id", func(idRoute fiber.Router) {
+			idRoute.Use(middleware.DataScopeRequired(datascope.EntityInterview))
+			idRoute.Get("", controller.get)
+			idRoute.Put("", controller.update)
+			idRoute.Put("cancel", controller.cancel)
+		})
+	})
+}
+
+// @Summary Назначить интервью
+// @Tags Интервью
+// @Description Назначить интервью кандидату. Участники получают уведомление, участники и кандидат - приглашение в календарь на email
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param	body body	 interviewapimodels.CreateInterviewRequest	true	"request body"
+// @Success 200 {object} apimodels.Response{data=string}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/interview [post]
+func (c *interviewApiController) create(ctx *fiber.Ctx) error {
+	var payload interviewapimodels.CreateInterviewRequest
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err := payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	userID := middleware.GetUserID(ctx)
+	id, hMsg, err := interview.Instance.Create(spaceID, userID, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка назначения интервью")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(id))
+}
+
+// @Summary Список интервью
+// @Tags Интервью
+// @Description Список интервью
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param	body body	 interviewapimodels.InterviewFilter	true	"request filter"
+// @Success 200 {object} apimodels.ScrollerResponse{data=[]interviewapimodels.InterviewView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/interview/list [post]
+func (c *interviewApiController) list(ctx *fiber.Ctx) error {
+	var payload interviewapimodels.InterviewFilter
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	userID := middleware.GetUserID(ctx)
+	list, rowCount, err := interview.Instance.List(spaceID, userID, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения списка интервью")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewScrollerResponse(list, rowCount))
+}
+
+// @Summary Получить интервью
+// @Tags Интервью
+// @Description Получить интервью
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID интервью"
+// @Success 200 {object} apimodels.Response{data=interviewapimodels.InterviewView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/interview/{id} [get]
+func (c *interviewApiController) get(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	resp, err := interview.Instance.GetByID(spaceID, id)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения интервью")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Изменить интервью
+// @Tags Интервью
+// @Description Изменить время, участников или место проведения интервью. Участники и кандидат получают обновленное приглашение
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID интервью"
+// @Param	body body	 interviewapimodels.InterviewData	true	"request body"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/interview/{id} [put]
+func (c *interviewApiController) update(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	var payload interviewapimodels.InterviewData
+	if err = c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err = payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	userID := middleware.GetUserID(ctx)
+	hMsg, err := interview.Instance.Update(spaceID, id, userID, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка изменения интервью")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Отменить интервью
+// @Tags Интервью
+// @Description Отменить интервью. Участники и кандидат получают отмену приглашения
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID интервью"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/interview/{id}/cancel [put]
+func (c *interviewApiController) cancel(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	userID := middleware.GetUserID(ctx)
+	hMsg, err := interview.Instance.Cancel(spaceID, id, userID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка отмены интервью")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Добавить слот интервьюера
+// @Tags Интервью
+// @Description Добавить интервал, в который интервьюер доступен для проведения интервью
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param	body body	 interviewapimodels.SlotData	true	"request body"
+// @Success 200 {object} apimodels.Response{data=string}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/interview/slot [post]
+func (c *interviewApiController) createSlot(ctx *fiber.Ctx) error {
+	var payload interviewapimodels.SlotData
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err := payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	userID := middleware.GetUserID(ctx)
+	id, hMsg, err := interview.Instance.CreateSlot(spaceID, userID, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка добавления слота")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(id))
+}
+
+// @Summary Список слотов интервьюеров
+// @Tags Интервью
+// @Description Список слотов интервьюеров
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param	body body	 interviewapimodels.SlotFilter	true	"request filter"
+// @Success 200 {object} apimodels.Response{data=[]interviewapimodels.SlotView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/interview/slot/list [post]
+func (c *interviewApiController) listSlots(ctx *fiber.Ctx) error {
+	var payload interviewapimodels.SlotFilter
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	list, err := interview.Instance.ListSlots(spaceID, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения списка слотов")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(list))
+}
+
+// @Summary Удалить слот интервьюера
+// @Tags Интервью
+// @Description Удалить слот интервьюера
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID слота"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/interview/slot/{id} [delete]
+func (c *interviewApiController) deleteSlot(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	hMsg, err := interview.Instance.DeleteSlot(spaceID, id)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка удаления слота")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Ссылка для записи кандидата на интервью
+// @Tags Интервью
+// @Description Создать ссылку, по которой кандидат самостоятельно выбирает время интервью из свободных слотов интервьюеров
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param	body body	 interviewapimodels.BookingRequest	true	"request body"
+// @Success 200 {object} apimodels.Response{data=interviewapimodels.BookingView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/interview/booking [post]
+func (c *interviewApiController) createBooking(ctx *fiber.Ctx) error {
+	var payload interviewapimodels.BookingRequest
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err := payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	userID := middleware.GetUserID(ctx)
+	resp, hMsg, err := interview.Instance.CreateBooking(spaceID, userID, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка создания ссылки для записи на интервью")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
@@ -118,7 +118,8 @@ func (c *msgTemplateApiController) create(ctx *fiber.Ctx) error {
 		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
 	}
 	spaceID := middleware.GetUserSpace(ctx)
-	id, err := messagetemplate.Instance.Create(spaceID, payload)
+	userID := middleware.GetUserID(ctx)
+	id, err := messagetemplate.Instance.Create(spaceID, userID, payload)
 	if err != nil {
 		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка добавления шаблона сообщений")
 	}
@@ -135,7 +136,12 @@ func (c *msgTemplateApiController) create(ctx *fiber.Ctx) error {
 // @Failure 500 {object} apimodels.Response
 // @router /api/v1/space/msg-templates/variables [get]
 func (c *msgTemplateApiController) variables(ctx *fiber.Ctx) error {
-	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(messagetemplate.GetVariables()))
+	spaceID := middleware.GetUserSpace(ctx)
+	resp, err := messagetemplate.Instance.GetVariables(spaceID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения переменных шаблона")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
 }
 
 // @Summary Обновление
@@ -165,7 +171,8 @@ func (c *msgTemplateApiController) update(ctx *fiber.Ctx) error {
 	}
 
 	spaceID := middleware.GetUserSpace(ctx)
-	err = messagetemplate.Instance.Update(spaceID, id, payload)
+	userID := middleware.GetUserID(ctx)
+	err = messagetemplate.Instance.Update(spaceID, id, userID, payload)
 	if err != nil {
 		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка изменения шаблона сообщений")
 	}
@@ -213,7 +220,8 @@ func (c *msgTemplateApiController) delete(ctx *fiber.Ctx) error {
 	}
 
 	spaceID := middleware.GetUserSpace(ctx)
-	err = messagetemplate.Instance.Delete(spaceID, id)
+	userID := middleware.GetUserID(ctx)
+	err = messagetemplate.Instance.Delete(spaceID, id, userID)
 	if err != nil {
 		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка удаления причины шаблона сообщений")
 	}
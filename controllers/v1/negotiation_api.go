@@ -4,6 +4,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"hr-tools-backend/controllers"
 	"hr-tools-backend/lib/applicant"
+	datascope "hr-tools-backend/lib/data-scope"
 	"hr-tools-backend/middleware"
 	apimodels "hr-tools-backend/models/api"
 	negotiationapimodels "hr-tools-backend/models/api/negotiation"
@@ -21,6 +22,7 @@ func InitNegotiationApiRouters(app *fiber.App) {
 		
 		router.Post("list", controller.list)
 		router.Route(":id", func(idRoute fiber.Router) {
+			idRoute.Use(middleware.DataScopeRequired(datascope.EntityApplicant))
 			idRoute.Put("status_change", controller.statusChange)
 			idRoute.Get("", controller.get)
 			idRoute.Put("comment", controller.updateComment)
@@ -48,7 +50,8 @@ func (c *negotiationApiController) list(ctx *fiber.Ctx) error {
 	}
 
 	spaceID := middleware.GetUserSpace(ctx)
-	list, err := applicant.Instance.ListOfNegotiation(spaceID, payload)
+	userID := middleware.GetUserID(ctx)
+	list, err := applicant.Instance.ListOfNegotiation(spaceID, userID, payload)
 	if err != nil {
 		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения списка откликов")
 	}
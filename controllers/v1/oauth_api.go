@@ -2,8 +2,7 @@ package apiv1
 
 import (
 	"hr-tools-backend/controllers"
-	avitoclient "hr-tools-backend/lib/external-services/avito"
-	hhclient "hr-tools-backend/lib/external-services/hh"
+	externalservices "hr-tools-backend/lib/external-services"
 
 	"github.com/gofiber/fiber/v2"
 	log "github.com/sirupsen/logrus"
@@ -17,53 +16,35 @@ func InitOAuthApiRouters(app *fiber.App) {
 	controller := oAuthApiController{}
 	app.Route("oauth", func(router fiber.Router) {
 		router.Route("callback", func(callback fiber.Router) {
-			callback.Get("hh", controller.hhCallBack)
-			callback.Get("avito", controller.avitoCallBack)
+			callback.Get(":code", controller.callBack)
 		})
 	})
 }
 
-// @Summary Аутентификация HH
+// @Summary Аутентификация на работном сайте
 // @Tags Аутентификация OAuth
-// @Description Аутентификация HH
+// @Description Аутентификация на работном сайте (hh, avito и другие подключенные коннекторы)
+// @Param   code      path     string  				    true   "код коннектора"
 // @Param   state     query     string  				true   "space ID"
 // @Param   code      query    string  				    false       "authorization_code"
 // @Success 200 {object} apimodels.Response
 // @Failure 400 {object} apimodels.Response
 // @Failure 403
+// @Failure 404
 // @Failure 500 {object} apimodels.Response
-// @router /api/v1/oauth/callback/hh [get]
-func (c *oAuthApiController) hhCallBack(ctx *fiber.Ctx) error {
-
-	spaceID := ctx.Query("state", "")
-	code := ctx.Query("code", "")
-	log.Infof("hhCallBack (code: %v) (state: %v) (uri: %v)", code, spaceID, ctx.Request().URI().String())
-
-	if code != "" && spaceID != "" {
-		go hhclient.Instance.RequestToken(spaceID, code)
+// @router /api/v1/oauth/callback/{code} [get]
+func (c *oAuthApiController) callBack(ctx *fiber.Ctx) error {
+	connector, ok := externalservices.GetConnector(ctx.Params("code"))
+	if !ok || !connector.Has(externalservices.CapabilityOAuth) {
+		return ctx.SendStatus(fiber.StatusNotFound)
 	}
 
-	_, err := ctx.Status(fiber.StatusOK).Write([]byte("ok"))
-	return err
-}
-
-// @Summary Аутентификация Avito
-// @Tags Аутентификация OAuth
-// @Description Аутентификация Avito
-// @Param   state     query     string  				true   "space ID"
-// @Param   code      query     string  				false  "authorization_code"
-// @Success 200 {object} apimodels.Response
-// @Failure 400 {object} apimodels.Response
-// @Failure 403
-// @Failure 500 {object} apimodels.Response
-// @router /api/v1/oauth/callback/avito [get]
-func (c *oAuthApiController) avitoCallBack(ctx *fiber.Ctx) error {
 	spaceID := ctx.Query("state", "")
 	code := ctx.Query("code", "")
-	log.Infof("avitoCallBack (code: %v) (state: %v) (uri: %v)", code, spaceID, ctx.Request().URI().String())
+	log.Infof("%v callBack (code: %v) (state: %v) (uri: %v)", connector.Code, code, spaceID, ctx.Request().URI().String())
 
 	if code != "" && spaceID != "" {
-		go avitoclient.Instance.RequestToken(spaceID, code)
+		go connector.Provider.RequestToken(spaceID, code)
 	}
 
 	_, err := ctx.Status(fiber.StatusOK).Write([]byte("ok"))
@@ -0,0 +1,470 @@
+package apiv1
+
+import (
+	"hr-tools-backend/controllers"
+	datascope "hr-tools-backend/lib/data-scope"
+	"hr-tools-backend/lib/offer"
+	"hr-tools-backend/middleware"
+	apimodels "hr-tools-backend/models/api"
+	offerapimodels "hr-tools-backend/models/api/offer"
+	vacancyapimodels "hr-tools-backend/models/api/vacancy"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type offerApiController struct {
+	controllers.BaseAPIController
+}
+
+func InitOfferApiRouters(app *fiber.App) {
+	controller := offerApiController{}
+	app.Route("offer", func(router fiber.Router) {
+		router.Use(middleware.LicenseRequired())
+		router.Post("", controller.create)
+		router.Post("list", controller.list)
+		router.Route(":id", func(idRoute fiber.Router) {
+			idRoute.Use(middleware.DataScopeRequired(datascope.EntityOffer))
+			idRoute.Get("", controller.get)
+			idRoute.Put("", controller.update)
+			idRoute.Get("versions", controller.versions)
+			idRoute.Get("pdf", controller.pdf)
+			idRoute.Put("on_approval", controller.onApproval) // на согласование
+			idRoute.Put("send", controller.send)              // отправить кандидату
+			idRoute.Put("cancel", controller.cancel)          // отозвать
+			idRoute.Route("approvals", func(approvals fiber.Router) {
+				approvals.Get("", controller.getApprovals)
+				approvals.Put("", controller.saveApprovals)
+				approvals.Route(":taskId", func(taskRoute fiber.Router) {
+					taskRoute.Post("approve", controller.approve) // согласовать
+					taskRoute.Post("reject", controller.reject)   // отклонить
+				})
+			})
+			idRoute.Get("approval_history", controller.getApprovalHistory)
+		})
+	})
+}
+
+// @Summary Создание оффера
+// @Tags Оффер
+// @Description Создание оффера для кандидата на этапе "Оффер"
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param	body body	 offerapimodels.CreateOfferRequest	true	"request body"
+// @Success 200 {object} apimodels.Response{data=string}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/offer [post]
+func (c *offerApiController) create(ctx *fiber.Ctx) error {
+	var payload offerapimodels.CreateOfferRequest
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err := payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	userID := middleware.GetUserID(ctx)
+	id, hMsg, err := offer.Instance.Create(spaceID, userID, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка создания оффера")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(id))
+}
+
+// @Summary Список офферов кандидата
+// @Tags Оффер
+// @Description Список офферов кандидата
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param	body body	 offerapimodels.ListRequest	true	"request body"
+// @Success 200 {object} apimodels.Response{data=[]offerapimodels.OfferView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/offer/list [post]
+func (c *offerApiController) list(ctx *fiber.Ctx) error {
+	var payload offerapimodels.ListRequest
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+	if payload.ApplicantID == "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError("не указан кандидат"))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	userID := middleware.GetUserID(ctx)
+	resp, err := offer.Instance.List(spaceID, payload.ApplicantID, userID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения списка офферов")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Оффер
+// @Tags Оффер
+// @Description Оффер с цепочкой согласования
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID оффера"
+// @Success 200 {object} apimodels.Response{data=offerapimodels.OfferView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 404
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/offer/{id} [get]
+func (c *offerApiController) get(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	resp, err := offer.Instance.GetByID(spaceID, id)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения оффера")
+	}
+	if resp == nil {
+		return ctx.SendStatus(fiber.StatusNotFound)
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Изменение оффера
+// @Tags Оффер
+// @Description Изменение условий оффера, создается новая версия, оффер требует повторного согласования и отправки
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID оффера"
+// @Param	body body	 offerapimodels.OfferData	true	"request body"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/offer/{id} [put]
+func (c *offerApiController) update(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+	var payload offerapimodels.OfferData
+	if err = c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err = payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	userID := middleware.GetUserID(ctx)
+	hMsg, err := offer.Instance.Update(spaceID, id, userID, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка изменения оффера")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Версии оффера
+// @Tags Оффер
+// @Description История изменения условий оффера
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID оффера"
+// @Success 200 {object} apimodels.Response{data=[]offerapimodels.OfferVersionView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/offer/{id}/versions [get]
+func (c *offerApiController) versions(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	resp, err := offer.Instance.Versions(spaceID, id)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения версий оффера")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Pdf оффера
+// @Tags Оффер
+// @Description Отправленный кандидату документ, до отправки - предпросмотр текущей версии
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID оффера"
+// @Success 200
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/offer/{id}/pdf [get]
+func (c *offerApiController) pdf(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	userID := middleware.GetUserID(ctx)
+	body, hMsg, err := offer.Instance.GetPdf(ctx.Context(), spaceID, id, userID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения pdf оффера")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	ctx.Set(fiber.HeaderContentType, "application/pdf")
+	return ctx.Send(body)
+}
+
+// @Summary Отправить оффер на согласование
+// @Tags Оффер
+// @Description Отправить оффер на согласование, при отсутствии согласующих оффер считается согласованным
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID оффера"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/offer/{id}/on_approval [put]
+func (c *offerApiController) onApproval(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	userID := middleware.GetUserID(ctx)
+	hMsg, err := offer.Instance.SendToApproval(spaceID, id, userID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка отправки оффера на согласование")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Отправить оффер кандидату
+// @Tags Оффер
+// @Description Отправить согласованный оффер кандидату на почту: pdf во вложении и ссылка для принятия или отклонения
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID оффера"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/offer/{id}/send [put]
+func (c *offerApiController) send(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	userID := middleware.GetUserID(ctx)
+	hMsg, err := offer.Instance.Send(ctx.Context(), spaceID, id, userID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка отправки оффера кандидату")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Отозвать оффер
+// @Tags Оффер
+// @Description Отозвать оффер
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID оффера"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/offer/{id}/cancel [put]
+func (c *offerApiController) cancel(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	userID := middleware.GetUserID(ctx)
+	hMsg, err := offer.Instance.Cancel(spaceID, id, userID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка отзыва оффера")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Получить цепочку согласования оффера
+// @Tags Оффер
+// @Description Получить цепочку согласования оффера
+// @Param   Authorization		header	string								true	"Authorization token"
+// @Param   id          		path    string  				    		true    "ID оффера"
+// @Success 200 {object} apimodels.Response{data=[]vacancyapimodels.ApprovalTaskView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/offer/{id}/approvals [get]
+func (c *offerApiController) getApprovals(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	resp, err := offer.Instance.GetByID(spaceID, id)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения списка задач согласования")
+	}
+	if resp == nil {
+		return ctx.SendStatus(fiber.StatusNotFound)
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp.Approvals))
+}
+
+// @Summary Обновление цепочки согласования оффера
+// @Tags Оффер
+// @Description Обновление цепочки согласования оффера
+// @Param   Authorization		header	string								true	"Authorization token"
+// @Param	body 				body	vacancyapimodels.ApprovalTasks	true	"request body"
+// @Param   id          		path    string  				    		true    "ID оффера"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/offer/{id}/approvals [put]
+func (c *offerApiController) saveApprovals(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	var payload vacancyapimodels.ApprovalTasks
+	if err = c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err = payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	hMsg, err := offer.Instance.SaveApprovals(spaceID, id, payload.ApprovalTasks)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка обновления цепочки согласования")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Согласовать оффер
+// @Tags Оффер
+// @Description Согласовать оффер
+// @Param   Authorization		header	string								true	"Authorization token"
+// @Param   id          		path    string  				    		true    "ID оффера"
+// @Param   taskId          	path    string  				    		true    "task rec ID"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/offer/{id}/approvals/{taskId}/approve [post]
+func (c *offerApiController) approve(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+	taskID, err := c.GetIDByKey(ctx, "taskId")
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	userID := middleware.GetUserID(ctx)
+	hMsg, err := offer.Instance.Approve(spaceID, id, taskID, userID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка согласования оффера")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Отклонить оффер
+// @Tags Оффер
+// @Description Отклонить оффер при согласовании
+// @Param   Authorization		header	string										true	"Authorization token"
+// @Param	body 				body	vacancyapimodels.ApprovalReject			true	"request body"
+// @Param   id          		path    string  				    				true    "ID оффера"
+// @Param   taskId          	path    string  				    				true    "task rec ID"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/offer/{id}/approvals/{taskId}/reject [post]
+func (c *offerApiController) reject(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+	taskID, err := c.GetIDByKey(ctx, "taskId")
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+	var payload vacancyapimodels.ApprovalReject
+	if err = c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err = payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	userID := middleware.GetUserID(ctx)
+	hMsg, err := offer.Instance.Reject(spaceID, id, taskID, userID, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка отклонения оффера")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary История согласования оффера
+// @Tags Оффер
+// @Description История согласования оффера
+// @Param   Authorization		header	string								true	"Authorization token"
+// @Param   id          		path    string  				    		true    "ID оффера"
+// @Success 200 {object} apimodels.Response{data=[]vacancyapimodels.ApprovalHistoryView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/offer/{id}/approval_history [get]
+func (c *offerApiController) getApprovalHistory(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	resp, err := offer.Instance.ApprovalHistory(spaceID, id)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения истории согласования оффера")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
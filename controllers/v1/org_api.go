@@ -78,9 +78,12 @@ func (c *orgApiController) createOrg(ctx *fiber.Ctx) error {
 	if err := payload.Validate(); err != nil {
 		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
 	}
-	err := spacehandler.Instance.CreateOrganizationSpace(payload)
+	hMsg, err := spacehandler.Instance.CreateOrganizationSpace(payload)
 	if err != nil {
 		return c.SendError(ctx, c.GetLogger(ctx), err, fmt.Sprintf("Ошибка создания организации: %v", err.Error()))
 	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
 	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
 }
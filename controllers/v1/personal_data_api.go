@@ -0,0 +1,201 @@
+package apiv1
+
+import (
+	"hr-tools-backend/controllers"
+	personaldata "hr-tools-backend/lib/personal-data"
+	"hr-tools-backend/middleware"
+	apimodels "hr-tools-backend/models/api"
+	personaldataapimodels "hr-tools-backend/models/api/personal-data"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type personalDataApiController struct {
+	controllers.BaseAPIController
+}
+
+func InitPersonalDataApiRouters(app *fiber.App) {
+	controller := personalDataApiController{}
+	app.Route("personal_data", func(router fiber.Router) {
+		router.Use(middleware.LicenseRequired())
+		router.Use(middleware.RbacMiddleware())
+		router.Get("consent/:applicant_id", controller.consentList)
+		router.Post("audit/list", controller.auditList)
+		router.Route("request", func(requestRoute fiber.Router) {
+			requestRoute.Post("", controller.createRequest)
+			requestRoute.Post("list", controller.requestList)
+			requestRoute.Put(":id/execute", controller.executeRequest)
+			requestRoute.Put(":id/reject", controller.rejectRequest)
+		})
+	})
+}
+
+// @Summary Согласия на обработку ПД
+// @Tags Персональные данные
+// @Description Реестр согласий кандидата на обработку персональных данных по всем его вакансиям
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   applicant_id		path    string  				    	true         "ID кандидата"
+// @Success 200 {object} apimodels.Response{data=[]personaldataapimodels.ConsentView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/personal_data/consent/{applicant_id} [get]
+func (c *personalDataApiController) consentList(ctx *fiber.Ctx) error {
+	applicantID, err := c.GetIDByKey(ctx, "applicant_id")
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	list, err := personaldata.Instance.ListConsents(spaceID, applicantID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения согласий на обработку ПД")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(list))
+}
+
+// @Summary Регистрация запроса субъекта ПД
+// @Tags Персональные данные
+// @Description Регистрация запроса кандидата на выгрузку (export) или удаление (erasure) персональных данных
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param	body body	 personaldataapimodels.RequestData	true	"request body"
+// @Success 200 {object} apimodels.Response{data=string}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/personal_data/request [post]
+func (c *personalDataApiController) createRequest(ctx *fiber.Ctx) error {
+	var payload personaldataapimodels.RequestData
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err := payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	userID := middleware.GetUserID(ctx)
+	spaceID := middleware.GetUserSpace(ctx)
+	id, hMsg, err := personaldata.Instance.CreateRequest(spaceID, userID, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка регистрации запроса субъекта ПД")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(id))
+}
+
+// @Summary Список запросов субъектов ПД
+// @Tags Персональные данные
+// @Description Список запросов субъектов ПД
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param	body body	 personaldataapimodels.RequestFilter	true	"request filter"
+// @Success 200 {object} apimodels.ScrollerResponse{data=[]personaldataapimodels.RequestView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/personal_data/request/list [post]
+func (c *personalDataApiController) requestList(ctx *fiber.Ctx) error {
+	var payload personaldataapimodels.RequestFilter
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	list, rowCount, err := personaldata.Instance.ListRequests(spaceID, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения списка запросов субъектов ПД")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewScrollerResponse(list, rowCount))
+}
+
+// @Summary Исполнение запроса субъекта ПД
+// @Tags Персональные данные
+// @Description Исполнение запроса: для выгрузки возвращаются данные кандидата по всем вакансиям, для удаления данные обезличиваются
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID запроса"
+// @Success 200 {object} apimodels.Response{data=personaldataapimodels.DataExport}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/personal_data/request/{id}/execute [put]
+func (c *personalDataApiController) executeRequest(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	userID := middleware.GetUserID(ctx)
+	spaceID := middleware.GetUserSpace(ctx)
+	export, hMsg, err := personaldata.Instance.ExecuteRequest(ctx.UserContext(), spaceID, id, userID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка исполнения запроса субъекта ПД")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(export))
+}
+
+// @Summary Отказ по запросу субъекта ПД
+// @Tags Персональные данные
+// @Description Отказ в исполнении запроса субъекта ПД с указанием причины
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID запроса"
+// @Param	body body	 personaldataapimodels.RejectRequest	true	"request body"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/personal_data/request/{id}/reject [put]
+func (c *personalDataApiController) rejectRequest(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	var payload personaldataapimodels.RejectRequest
+	if err = c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err = payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	userID := middleware.GetUserID(ctx)
+	spaceID := middleware.GetUserSpace(ctx)
+	hMsg, err := personaldata.Instance.RejectRequest(spaceID, id, userID, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка отказа по запросу субъекта ПД")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Журнал действий с ПД
+// @Tags Персональные данные
+// @Description Журнал действий с персональными данными кандидатов: согласия, запросы, выгрузки, обезличивание и удаление
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param	body body	 personaldataapimodels.AuditFilter	true	"request filter"
+// @Success 200 {object} apimodels.ScrollerResponse{data=[]personaldataapimodels.AuditView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/personal_data/audit/list [post]
+func (c *personalDataApiController) auditList(ctx *fiber.Ctx) error {
+	var payload personaldataapimodels.AuditFilter
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	list, rowCount, err := personaldata.Instance.ListAudit(spaceID, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения журнала ПД")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewScrollerResponse(list, rowCount))
+}
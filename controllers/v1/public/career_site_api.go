@@ -0,0 +1,208 @@
+package publicapi
+
+import (
+	"hr-tools-backend/config"
+	"hr-tools-backend/controllers"
+	careersite "hr-tools-backend/lib/career-site"
+	"hr-tools-backend/lib/utils/helpers"
+	"hr-tools-backend/models"
+	apimodels "hr-tools-backend/models/api"
+	careersiteapimodels "hr-tools-backend/models/api/career-site"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+	log "github.com/sirupsen/logrus"
+)
+
+type careerSiteApiController struct {
+	controllers.BaseAPIController
+}
+
+var resumeExtensions = map[string]bool{".pdf": true, ".doc": true, ".docx": true, ".rtf": true, ".odt": true, ".txt": true}
+
+func InitCareerSiteApiRouters(app *fiber.App) {
+	controller := careerSiteApiController{}
+	// ограничение кол-ва откликов с одного адреса, при превышении UI показывает капчу/таймер по Retry-After
+	applyLimiter := limiter.New(limiter.Config{
+		Max:        config.Conf.CareerSite.ApplyLimit,
+		Expiration: time.Duration(config.Conf.CareerSite.ApplyLimitPeriodSec) * time.Second,
+		LimitReached: func(ctx *fiber.Ctx) error {
+			return ctx.Status(fiber.StatusTooManyRequests).JSON(apimodels.NewError("Слишком много откликов, повторите попытку позже"))
+		},
+	})
+	app.Route("career/:space_id", func(router fiber.Router) {
+		router.Get("vacancies", controller.list)
+		router.Get("feed.xml", controller.feed)
+		router.Get("rss", controller.rss)
+		router.Route("vacancies/:id", func(idRoute fiber.Router) {
+			idRoute.Get("", controller.get)
+			idRoute.Post("apply", applyLimiter, controller.apply)
+		})
+	})
+}
+
+// @Summary Список открытых вакансий
+// @Tags Карьерный сайт
+// @Description Список открытых вакансий
+// @Param   space_id          		path    string  true         "Идентификатор спейса"
+// @Success 200 {object} apimodels.Response{data=[]careersiteapimodels.VacancyView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/public/career/{space_id}/vacancies [get]
+func (c *careerSiteApiController) list(ctx *fiber.Ctx) error {
+	spaceID := ctx.Params("space_id")
+	list, hMsg, err := careersite.Instance.ListVacancies(spaceID)
+	if err != nil {
+		return c.SendError(ctx, c.getLogger(spaceID), err, "Ошибка получения списка вакансий")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(list))
+}
+
+// @Summary Вакансия
+// @Tags Карьерный сайт
+// @Description Вакансия
+// @Param   space_id          		path    string  true         "Идентификатор спейса"
+// @Param   id          		path    string  true         "Идентификатор вакансии"
+// @Success 200 {object} apimodels.Response{data=careersiteapimodels.VacancyView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/public/career/{space_id}/vacancies/{id} [get]
+func (c *careerSiteApiController) get(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+	spaceID := ctx.Params("space_id")
+	view, hMsg, err := careersite.Instance.GetVacancy(spaceID, id)
+	if err != nil {
+		return c.SendError(ctx, c.getLogger(spaceID), err, "Ошибка получения вакансии")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(view))
+}
+
+// @Summary XML фид вакансий
+// @Tags Карьерный сайт
+// @Description XML фид открытых вакансий в формате Яндекс.Работы/HeadHunter
+// @Param   space_id          		path    string  true         "Идентификатор спейса"
+// @Produce xml
+// @Success 200
+// @Failure 400 {object} apimodels.Response
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/public/career/{space_id}/feed.xml [get]
+func (c *careerSiteApiController) feed(ctx *fiber.Ctx) error {
+	spaceID := ctx.Params("space_id")
+	body, hMsg, err := careersite.Instance.GetFeed(spaceID)
+	if err != nil {
+		return c.SendError(ctx, c.getLogger(spaceID), err, "Ошибка формирования фида вакансий")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	ctx.Set(fiber.HeaderContentType, fiber.MIMEApplicationXMLCharsetUTF8)
+	return ctx.Status(fiber.StatusOK).Send(body)
+}
+
+// @Summary RSS лента вакансий
+// @Tags Карьерный сайт
+// @Description RSS лента открытых вакансий
+// @Param   space_id          		path    string  true         "Идентификатор спейса"
+// @Produce xml
+// @Success 200
+// @Failure 400 {object} apimodels.Response
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/public/career/{space_id}/rss [get]
+func (c *careerSiteApiController) rss(ctx *fiber.Ctx) error {
+	spaceID := ctx.Params("space_id")
+	body, hMsg, err := careersite.Instance.GetRss(spaceID)
+	if err != nil {
+		return c.SendError(ctx, c.getLogger(spaceID), err, "Ошибка формирования RSS ленты вакансий")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	ctx.Set(fiber.HeaderContentType, "application/rss+xml; charset=utf-8")
+	return ctx.Status(fiber.StatusOK).Send(body)
+}
+
+// @Summary Отклик на вакансию
+// @Tags Карьерный сайт
+// @Description Отклик на вакансию с карьерного сайта. При превышении лимита откликов возвращается 429 и заголовок Retry-After
+// @Param   space_id          		path    string  true         "Идентификатор спейса"
+// @Param   id          		path    string  true         "Идентификатор вакансии"
+// @Param   first_name		formData	string 	true 	"Имя"
+// @Param   last_name		formData	string 	true 	"Фамилия"
+// @Param   middle_name		formData	string 	false 	"Отчество"
+// @Param   phone		formData	string 	false 	"Телефон"
+// @Param   email		formData	string 	false 	"Емайл"
+// @Param   salary		formData	int 	false 	"Желаемая ЗП"
+// @Param   cover_letter		formData	string 	false 	"Сопроводительное письмо"
+// @Param   resume		formData	file 	false 	"Резюме (pdf, doc, docx, rtf, odt, txt)"
+// @Param   pd_consent		formData	bool 	true 	"Согласие на обработку персональных данных"
+// @Success 200 {object} apimodels.Response{data=careersiteapimodels.ApplyResponse}
+// @Failure 400 {object} apimodels.Response
+// @Failure 429 {object} apimodels.Response
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/public/career/{space_id}/vacancies/{id}/apply [post]
+func (c *careerSiteApiController) apply(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+	spaceID := ctx.Params("space_id")
+	var payload careersiteapimodels.ApplyRequest
+	if err = ctx.BodyParser(&payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+	if err = payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	logger := c.getLogger(spaceID).WithField("vacancy_id", id)
+	var resume *models.File
+	file, err := ctx.FormFile("resume")
+	if err == nil {
+		if !resumeExtensions[strings.ToLower(filepath.Ext(file.Filename))] {
+			return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError("неподдерживаемый формат файла резюме"))
+		}
+		if file.Size > int64(config.Conf.CareerSite.MaxResumeSizeMb)*1024*1024 {
+			return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError("превышен допустимый размер файла резюме"))
+		}
+		buffer, err := file.Open()
+		if err != nil {
+			return c.SendError(ctx, logger, err, "Ошибка при получении файла резюме")
+		}
+		defer buffer.Close()
+		fileBody, err := io.ReadAll(buffer)
+		if err != nil {
+			return c.SendError(ctx, logger, err, "Ошибка при загрузке файла резюме")
+		}
+		resume = &models.File{
+			FileName:    file.Filename,
+			ContentType: helpers.GetFileContentType(file),
+			Body:        fileBody,
+		}
+	}
+
+	applicantID, hMsg, err := careersite.Instance.Apply(ctx.UserContext(), spaceID, id, payload, resume, getConsentMeta(ctx, models.ConsentSourceCareerSite))
+	if err != nil {
+		return c.SendError(ctx, logger, err, "Ошибка отклика на вакансию")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(careersiteapimodels.ApplyResponse{ApplicantID: applicantID}))
+}
+
+func (c *careerSiteApiController) getLogger(spaceID string) *log.Entry {
+	return log.WithField("space_id", spaceID)
+}
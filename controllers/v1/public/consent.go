@@ -0,0 +1,17 @@
+package publicapi
+
+import (
+	"hr-tools-backend/models"
+	personaldataapimodels "hr-tools-backend/models/api/personal-data"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// getConsentMeta данные запроса, сохраняемые вместе с согласием на обработку ПД
+func getConsentMeta(ctx *fiber.Ctx, source models.ConsentSource) personaldataapimodels.ConsentMeta {
+	return personaldataapimodels.ConsentMeta{
+		Source:    source,
+		IP:        ctx.IP(),
+		UserAgent: ctx.Get(fiber.HeaderUserAgent),
+	}
+}
@@ -0,0 +1,85 @@
+package publicapi
+
+import (
+	"hr-tools-backend/controllers"
+	"hr-tools-backend/lib/interview"
+	apimodels "hr-tools-backend/models/api"
+	interviewapimodels "hr-tools-backend/models/api/interview"
+
+	"github.com/gofiber/fiber/v2"
+	log "github.com/sirupsen/logrus"
+)
+
+type publicInterviewApiController struct {
+	controllers.BaseAPIController
+}
+
+func InitPublicInterviewApiRouters(app *fiber.App) {
+	controller := publicInterviewApiController{}
+	app.Route("interview", func(router fiber.Router) {
+		router.Route(":id", func(idRoute fiber.Router) {
+			idRoute.Get("", controller.getBooking)
+			idRoute.Put("", controller.book)
+		})
+	})
+}
+
+// @Summary Получение свободных слотов для записи на интервью
+// @Tags Запись на интервью
+// @Description Получение свободных слотов для записи на интервью
+// @Param   id          		path    string  true         "Идентификатор ссылки для записи"
+// @Success 200 {object} apimodels.Response{data=interviewapimodels.PublicBookingView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/public/interview/{id} [get]
+func (c *publicInterviewApiController) getBooking(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	resp, hMsg, err := interview.Instance.GetPublicBooking(id)
+	if err != nil {
+		return c.SendError(ctx, c.getLogger(id), err, "Ошибка получения данных для записи на интервью")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Запись на интервью
+// @Tags Запись на интервью
+// @Description Запись кандидата на выбранный слот
+// @Param   id          		path    string  true         "Идентификатор ссылки для записи"
+// @Param	body body	 interviewapimodels.PublicBookRequest	true	"request body"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/public/interview/{id} [put]
+func (c *publicInterviewApiController) book(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+	var payload interviewapimodels.PublicBookRequest
+	if err = c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+	if err = payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	hMsg, err := interview.Instance.Book(id, payload)
+	if err != nil {
+		return c.SendError(ctx, c.getLogger(id), err, "Ошибка записи на интервью")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+func (c *publicInterviewApiController) getLogger(id string) *log.Entry {
+	return log.WithField("booking_id", id)
+}
@@ -0,0 +1,133 @@
+package publicapi
+
+import (
+	"hr-tools-backend/controllers"
+	"hr-tools-backend/lib/offer"
+	apimodels "hr-tools-backend/models/api"
+	offerapimodels "hr-tools-backend/models/api/offer"
+
+	"github.com/gofiber/fiber/v2"
+	log "github.com/sirupsen/logrus"
+)
+
+type publicOfferApiController struct {
+	controllers.BaseAPIController
+}
+
+func InitPublicOfferApiRouters(app *fiber.App) {
+	controller := publicOfferApiController{}
+	app.Route("offer", func(router fiber.Router) {
+		router.Route(":id", func(idRoute fiber.Router) {
+			idRoute.Get("", controller.get)
+			idRoute.Get("pdf", controller.pdf)
+			idRoute.Put("accept", controller.accept)
+			idRoute.Put("decline", controller.decline)
+		})
+	})
+}
+
+// @Summary Получение оффера
+// @Tags Оффер для кандидата
+// @Description Получение условий оффера по ссылке из письма
+// @Param   id          		path    string  true         "Идентификатор оффера"
+// @Success 200 {object} apimodels.Response{data=offerapimodels.PublicOfferView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/public/offer/{id} [get]
+func (c *publicOfferApiController) get(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	resp, hMsg, err := offer.Instance.GetPublic(id)
+	if err != nil {
+		return c.SendError(ctx, c.getLogger(id), err, "Ошибка получения оффера")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Pdf оффера
+// @Tags Оффер для кандидата
+// @Description Документ оффера, отправленный кандидату
+// @Param   id          		path    string  true         "Идентификатор оффера"
+// @Success 200
+// @Failure 400 {object} apimodels.Response
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/public/offer/{id}/pdf [get]
+func (c *publicOfferApiController) pdf(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	body, hMsg, err := offer.Instance.GetPublicPdf(ctx.Context(), id)
+	if err != nil {
+		return c.SendError(ctx, c.getLogger(id), err, "Ошибка получения pdf оффера")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	ctx.Set(fiber.HeaderContentType, "application/pdf")
+	return ctx.Send(body)
+}
+
+// @Summary Принять оффер
+// @Tags Оффер для кандидата
+// @Description Принятие оффера кандидатом
+// @Param   id          		path    string  true         "Идентификатор оффера"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/public/offer/{id}/accept [put]
+func (c *publicOfferApiController) accept(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	hMsg, err := offer.Instance.Accept(id)
+	if err != nil {
+		return c.SendError(ctx, c.getLogger(id), err, "Ошибка принятия оффера")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Отклонить оффер
+// @Tags Оффер для кандидата
+// @Description Отказ кандидата от оффера
+// @Param   id          		path    string  true         "Идентификатор оффера"
+// @Param	body body	 offerapimodels.DeclineRequest	true	"request body"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/public/offer/{id}/decline [put]
+func (c *publicOfferApiController) decline(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+	var payload offerapimodels.DeclineRequest
+	if err = c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	hMsg, err := offer.Instance.Decline(id, payload)
+	if err != nil {
+		return c.SendError(ctx, c.getLogger(id), err, "Ошибка отклонения оффера")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+func (c *publicOfferApiController) getLogger(id string) *log.Entry {
+	return log.WithField("offer_id", id)
+}
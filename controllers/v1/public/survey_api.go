@@ -7,7 +7,9 @@ import (
 	"hr-tools-backend/lib/survey"
 	aichecker "hr-tools-backend/lib/utils/ai-checker"
 	"hr-tools-backend/lib/vk"
+	"hr-tools-backend/models"
 	apimodels "hr-tools-backend/models/api"
+	personaldataapimodels "hr-tools-backend/models/api/personal-data"
 	surveyapimodels "hr-tools-backend/models/api/survey"
 	"time"
 
@@ -32,6 +34,7 @@ func InitPublicSurveyApiRouters(app *fiber.App) {
 			idRoute.Put("", controller.setVkStep0Survey)
 		})
 		router.Get("/video-interview/:id", controller.getVideoSurveyData)
+		router.Put("/video-interview/:id/consent", controller.videoInterviewConsent)
 		router.Post("/upload-answer/:id/:questionID", controller.uploadAnswer)
 		router.All("/upload-stream/:id/:questionID", controller.streamUploadAnswer)
 	})
@@ -79,8 +82,11 @@ func (c *publicsurveyApiController) setSurvey(ctx *fiber.Ctx) error {
 	if err = c.BodyParser(ctx, &payload); err != nil {
 		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
 	}
+	if err = payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
 
-	hMsg, err := survey.Instance.AnswerPublicApplicantSurvey(id, payload.Responses)
+	hMsg, err := survey.Instance.AnswerPublicApplicantSurvey(id, payload.Responses, getConsentMeta(ctx, models.ConsentSourceSurvey))
 	if err != nil {
 		logger := log.WithField("survey_id", id)
 		return c.SendError(ctx, logger, err, "Ошибка сохранения ответов по анкете")
@@ -139,7 +145,7 @@ func (c *publicsurveyApiController) setVkStep0Survey(ctx *fiber.Ctx) error {
 	if err != nil {
 		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
 	}
-	result, err := vk.Instance.HandleSurveyStep0(id, payload)
+	result, err := vk.Instance.HandleSurveyStep0(id, payload, getConsentMeta(ctx, models.ConsentSourceVkStep0))
 	if err != nil {
 		logger := log.WithField("survey_id", id)
 		return c.SendError(ctx, logger, err, "Ошибка сохранения ответов по анкете")
@@ -170,6 +176,36 @@ func (c *publicsurveyApiController) getVideoSurveyData(ctx *fiber.Ctx) error {
 	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
 }
 
+// @Summary ВК. Шаг 8. Прохождение видео-интервью (согласие на обработку ПД)
+// @Tags ВК
+// @Description ВК. Шаг 8. Согласие кандидата на обработку персональных данных, обязательно перед загрузкой видео ответов
+// @Param   id          		path    string  true         "Идентификатор анкеты"
+// @Param	body body	 personaldataapimodels.ConsentRequest	true	"request body"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/public/survey/video-interview/{id}/consent [put]
+func (c *publicsurveyApiController) videoInterviewConsent(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+	var payload personaldataapimodels.ConsentRequest
+	if err = c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+	if err = payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+	err = vk.Instance.VideoInterviewConsent(id, getConsentMeta(ctx, models.ConsentSourceVideoInterview))
+	if err != nil {
+		logger := log.WithField("survey_id", id)
+		return c.SendError(ctx, logger, err, "Ошибка сохранения согласия на обработку ПД")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
 // @Summary ВК. Шаг 8. Прохождение видео-интервью (загрузка видео ответа на сервер)
 // @Tags ВК
 // @Description ВК. Шаг 8. Прохождение видео-интервью (загрузка видео ответа на сервер)
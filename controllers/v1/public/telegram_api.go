@@ -0,0 +1,48 @@
+package publicapi
+
+import (
+	"context"
+	"hr-tools-backend/controllers"
+	telegrambot "hr-tools-backend/lib/telegram/bot"
+	apimodels "hr-tools-backend/models/api"
+	telegramapimodels "hr-tools-backend/models/api/telegram"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type publicTelegramApiController struct {
+	controllers.BaseAPIController
+}
+
+func InitPublicTelegramApiRouters(app *fiber.App) {
+	controller := publicTelegramApiController{}
+	app.Route("telegram", func(router fiber.Router) {
+		router.Post("webhook", controller.webhook)
+	})
+}
+
+// @Summary Webhook бота Telegram
+// @Tags Telegram
+// @Description Получение обновлений бота уведомлений от Telegram
+// @Param   X-Telegram-Bot-Api-Secret-Token		header		string	true	"Секрет webhook"
+// @Param	body body	 telegramapimodels.Update	true	"request body"
+// @Success 200
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 404
+// @router /api/v1/public/telegram/webhook [post]
+func (c *publicTelegramApiController) webhook(ctx *fiber.Ctx) error {
+	if !telegrambot.Instance.IsWebhookMode() {
+		return ctx.SendStatus(fiber.StatusNotFound)
+	}
+	if !telegrambot.Instance.CheckSecret(ctx.Get("X-Telegram-Bot-Api-Secret-Token")) {
+		return ctx.SendStatus(fiber.StatusForbidden)
+	}
+	var payload telegramapimodels.Update
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+	// Telegram повторяет доставку при долгом ответе, обрабатываем в фоне
+	go telegrambot.Instance.HandleUpdate(context.Background(), payload)
+	return ctx.SendStatus(fiber.StatusOK)
+}
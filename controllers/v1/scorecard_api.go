@@ -0,0 +1,206 @@
+package apiv1
+
+import (
+	"hr-tools-backend/controllers"
+	datascope "hr-tools-backend/lib/data-scope"
+	"hr-tools-backend/lib/scorecard"
+	"hr-tools-backend/middleware"
+	apimodels "hr-tools-backend/models/api"
+	scorecardapimodels "hr-tools-backend/models/api/scorecard"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type scorecardApiController struct {
+	controllers.BaseAPIController
+}
+
+func InitScorecardApiRouters(app *fiber.App) {
+	controller := scorecardApiController{}
+	app.Route("scorecard", func(router fiber.Router) {
+		router.Use(middleware.LicenseRequired())
+		router.Route("template", func(templateRoute fiber.Router) {
+			templateRoute.Post("", controller.saveTemplate)
+			templateRoute.Post("list", controller.listTemplates)
+			templateRoute.Get(":id", controller.getTemplate)
+			templateRoute.Delete(":id", controller.deleteTemplate)
+		})
+		router.Route("applicant/:id", func(applicantRoute fiber.Router) {
+			applicantRoute.Use(middleware.DataScopeRequired(datascope.EntityApplicant))
+			applicantRoute.Get("", controller.getApplicantScorecards)
+			applicantRoute.Put("", controller.saveScorecard)
+		})
+	})
+}
+
+// @Summary Сохранить шаблон оценочной карты
+// @Tags Оценочные карты
+// @Description Создать или изменить шаблон оценочной карты этапа подбора вакансии (компетенции, шкала оценок, обязательные компетенции)
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param	body body	 scorecardapimodels.TemplateData	true	"request body"
+// @Success 200 {object} apimodels.Response{data=string}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/scorecard/template [post]
+func (c *scorecardApiController) saveTemplate(ctx *fiber.Ctx) error {
+	var payload scorecardapimodels.TemplateData
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err := payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	id, hMsg, err := scorecard.Instance.SaveTemplate(spaceID, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка сохранения шаблона оценочной карты")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(id))
+}
+
+// @Summary Список шаблонов оценочных карт
+// @Tags Оценочные карты
+// @Description Список шаблонов оценочных карт этапов подбора вакансии
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param	body body	 scorecardapimodels.TemplateListRequest	true	"request body"
+// @Success 200 {object} apimodels.Response{data=[]scorecardapimodels.TemplateView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/scorecard/template/list [post]
+func (c *scorecardApiController) listTemplates(ctx *fiber.Ctx) error {
+	var payload scorecardapimodels.TemplateListRequest
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+	if payload.VacancyID == "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError("не указана вакансия"))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	resp, err := scorecard.Instance.ListTemplates(spaceID, payload.VacancyID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения списка шаблонов оценочных карт")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Шаблон оценочной карты
+// @Tags Оценочные карты
+// @Description Шаблон оценочной карты
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID шаблона"
+// @Success 200 {object} apimodels.Response{data=scorecardapimodels.TemplateView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/scorecard/template/{id} [get]
+func (c *scorecardApiController) getTemplate(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	resp, err := scorecard.Instance.GetTemplate(spaceID, id)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения шаблона оценочной карты")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Удалить шаблон оценочной карты
+// @Tags Оценочные карты
+// @Description Удалить шаблон оценочной карты, удаление невозможно, если по шаблону уже есть оценки
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID шаблона"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/scorecard/template/{id} [delete]
+func (c *scorecardApiController) deleteTemplate(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	hMsg, err := scorecard.Instance.DeleteTemplate(spaceID, id)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка удаления шаблона оценочной карты")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Оценки кандидата
+// @Tags Оценочные карты
+// @Description Оценочные карты кандидата по этапам подбора со сводной оценкой. Участнику команды вакансии оценки других интервьюеров доступны после отправки своей оценки
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID кандидата"
+// @Success 200 {object} apimodels.Response{data=scorecardapimodels.ApplicantScorecardsView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/scorecard/applicant/{id} [get]
+func (c *scorecardApiController) getApplicantScorecards(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	userID := middleware.GetUserID(ctx)
+	resp, err := scorecard.Instance.GetApplicantScorecards(spaceID, id, userID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения оценок кандидата")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Оценить кандидата
+// @Tags Оценочные карты
+// @Description Сохранить черновик или отправить оценочную карту кандидата по этапу подбора. Доступно участникам команды вакансии, отправленная оценка не изменяется
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID кандидата"
+// @Param	body body	 scorecardapimodels.ScorecardData	true	"request body"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/scorecard/applicant/{id} [put]
+func (c *scorecardApiController) saveScorecard(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	var payload scorecardapimodels.ScorecardData
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err := payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	userID := middleware.GetUserID(ctx)
+	hMsg, err := scorecard.Instance.SaveScorecard(spaceID, id, userID, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка сохранения оценочной карты")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
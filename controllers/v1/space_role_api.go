@@ -0,0 +1,191 @@
+package apiv1
+
+import (
+	"hr-tools-backend/controllers"
+	spacerolehandler "hr-tools-backend/lib/space/roles"
+	"hr-tools-backend/middleware"
+	apimodels "hr-tools-backend/models/api"
+	spaceroleapimodels "hr-tools-backend/models/api/space-role"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type spaceRoleApiController struct {
+	controllers.BaseAPIController
+}
+
+func InitSpaceRoleApiRouters(app *fiber.App) {
+	controller := spaceRoleApiController{}
+	app.Route("roles", func(router fiber.Router) {
+		router.Use(middleware.LicenseRequired())
+		router.Use(middleware.RbacMiddleware())
+		router.Use(middleware.UserRequired())
+		router.Get("catalog", controller.catalog)
+		router.Get("list", controller.list)
+		router.Post("", controller.create)
+		router.Route(":id", func(idRoute fiber.Router) {
+			idRoute.Get("", controller.get)
+			idRoute.Put("", controller.update)
+			idRoute.Delete("", controller.delete)
+		})
+	})
+}
+
+// @Summary Справочник разрешений
+// @Tags Роли спейса
+// @Description Модули и разрешения для матрицы роли, а также разрешения встроенных ролей
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Success 200 {object} apimodels.Response{data=spaceroleapimodels.RoleCatalog}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/roles/catalog [get]
+func (c *spaceRoleApiController) catalog(ctx *fiber.Ctx) error {
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(spacerolehandler.Instance.GetCatalog()))
+}
+
+// @Summary Список ролей спейса
+// @Tags Роли спейса
+// @Description Список ролей спейса с кол-вом назначенных пользователей
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Success 200 {object} apimodels.Response{data=[]spaceroleapimodels.SpaceRoleView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/roles/list [get]
+func (c *spaceRoleApiController) list(ctx *fiber.Ctx) error {
+	spaceID := middleware.GetUserSpace(ctx)
+	list, err := spacerolehandler.Instance.List(spaceID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения списка ролей")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(list))
+}
+
+// @Summary Создание роли спейса
+// @Tags Роли спейса
+// @Description Создание роли с матрицей разрешений на основе базовой роли
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param	body body	 spaceroleapimodels.SpaceRoleData	true	"request body"
+// @Success 200 {object} apimodels.Response{data=string}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/roles [post]
+func (c *spaceRoleApiController) create(ctx *fiber.Ctx) error {
+	var payload spaceroleapimodels.SpaceRoleData
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err := payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	userID := middleware.GetUserID(ctx)
+	spaceID := middleware.GetUserSpace(ctx)
+	userRole := middleware.GetSpaceRole(ctx)
+	id, hMsg, err := spacerolehandler.Instance.Create(spaceID, userID, userRole, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка создания роли")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(id))
+}
+
+// @Summary Роль спейса
+// @Tags Роли спейса
+// @Description Роль спейса
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID роли"
+// @Success 200 {object} apimodels.Response{data=spaceroleapimodels.SpaceRoleView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 404
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/roles/{id} [get]
+func (c *spaceRoleApiController) get(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	resp, err := spacerolehandler.Instance.Get(spaceID, id)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения роли")
+	}
+	if resp == nil {
+		return ctx.SendStatus(fiber.StatusNotFound)
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Изменение роли спейса
+// @Tags Роли спейса
+// @Description Изменение роли и ее матрицы разрешений, действует для назначенных пользователей сразу
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID роли"
+// @Param	body body	 spaceroleapimodels.SpaceRoleData	true	"request body"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/roles/{id} [put]
+func (c *spaceRoleApiController) update(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	var payload spaceroleapimodels.SpaceRoleData
+	if err = c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err = payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	userID := middleware.GetUserID(ctx)
+	spaceID := middleware.GetUserSpace(ctx)
+	userRole := middleware.GetSpaceRole(ctx)
+	hMsg, err := spacerolehandler.Instance.Update(spaceID, id, userID, userRole, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка изменения роли")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Удаление роли спейса
+// @Tags Роли спейса
+// @Description Удаление роли, недоступно пока роль назначена пользователям
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID роли"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/roles/{id} [delete]
+func (c *spaceRoleApiController) delete(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	userID := middleware.GetUserID(ctx)
+	spaceID := middleware.GetUserSpace(ctx)
+	hMsg, err := spacerolehandler.Instance.Delete(spaceID, id, userID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка удаления роли")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
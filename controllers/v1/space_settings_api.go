@@ -52,7 +52,8 @@ func (c *spaceSettingsApiController) UpdateSetting(ctx *fiber.Ctx) error {
 		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
 	}
 	spaceID := middleware.GetUserSpace(ctx)
-	err = spacesettingshandler.Instance.UpdateSettingValue(spaceID, settingCode, payload.Value)
+	userID := middleware.GetUserID(ctx)
+	err = spacesettingshandler.Instance.UpdateSettingValue(spaceID, userID, settingCode, payload.Value)
 	if err != nil {
 		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка обновления настройки")
 	}
@@ -4,6 +4,7 @@ import (
 	"hr-tools-backend/controllers"
 	filestorage "hr-tools-backend/lib/file-storage"
 	spaceusershander "hr-tools-backend/lib/space/users/hander"
+	telegrambot "hr-tools-backend/lib/telegram/bot"
 	"hr-tools-backend/lib/utils/helpers"
 	"hr-tools-backend/middleware"
 	apimodels "hr-tools-backend/models/api"
@@ -22,6 +23,7 @@ func InitSpaceUserRouters(app *fiber.App) {
 	controller := spaceUserController{}
 	app.Route("users", func(usersRootRoute fiber.Router) {
 		usersRootRoute.Use(middleware.AuthorizationRequired())
+		usersRootRoute.Use(middleware.AuditLog())
 		usersRootRoute.Use(middleware.LicenseRequired())
 		usersRootRoute.Use(middleware.RbacMiddleware())
 		
@@ -38,6 +40,7 @@ func InitSpaceUserRouters(app *fiber.App) {
 	})
 	app.Route("user_profile", func(userRootRoute fiber.Router) {
 		userRootRoute.Use(middleware.AuthorizationRequired())
+		userRootRoute.Use(middleware.AuditLog())
 		userRootRoute.Use(middleware.RbacMiddleware())
 		userRootRoute.Get("", controller.getProfile)
 		userRootRoute.Put("", controller.updateProfile)
@@ -49,6 +52,8 @@ func InitSpaceUserRouters(app *fiber.App) {
 			pushRoute.Put("", controller.pushSettinsUgpdate)
 			pushRoute.Put("enable", controller.pushSettinsEnable)
 		})
+		userRootRoute.Get("telegram", controller.telegramLink)      // ссылка для привязки Telegram
+		userRootRoute.Delete("telegram", controller.telegramUnlink) // отвязать Telegram
 	})
 }
 
@@ -72,7 +77,8 @@ func (c *spaceUserController) CreateUser(ctx *fiber.Ctx) error {
 		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
 	}
 	spaceID := middleware.GetUserSpace(ctx)
-	id, hMsg, err := spaceusershander.Instance.CreateUser(payload, spaceID)
+	authorID := middleware.GetUserID(ctx)
+	id, hMsg, err := spaceusershander.Instance.CreateUser(payload, spaceID, authorID)
 	if err != nil {
 		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка создания пользователя")
 	}
@@ -98,7 +104,8 @@ func (c *spaceUserController) DeleteUser(ctx *fiber.Ctx) error {
 	if err != nil {
 		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
 	}
-	err = spaceusershander.Instance.DeleteUser(userID)
+	authorID := middleware.GetUserID(ctx)
+	err = spaceusershander.Instance.DeleteUser(userID, authorID)
 	if err != nil {
 		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка удаления пользователя")
 	}
@@ -129,10 +136,14 @@ func (c *spaceUserController) UpdateUser(ctx *fiber.Ctx) error {
 	if err := payload.Validate(); err != nil {
 		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
 	}
-	err = spaceusershander.Instance.UpdateUser(userID, payload)
+	authorID := middleware.GetUserID(ctx)
+	hMsg, err := spaceusershander.Instance.UpdateUser(userID, authorID, payload)
 	if err != nil {
 		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка обновления данных пользователя")
 	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
 	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
 }
 
@@ -160,7 +171,8 @@ func (c *spaceUserController) UpdateUserStatus(ctx *fiber.Ctx) error {
 	if err := payload.Validate(); err != nil {
 		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
 	}
-	user, err := spaceusershander.Instance.UpdateUserStatus(userID, payload)
+	authorID := middleware.GetUserID(ctx)
+	user, err := spaceusershander.Instance.UpdateUserStatus(userID, authorID, payload)
 	if err != nil {
 		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка обновления статуса пользователя")
 	}
@@ -445,3 +457,42 @@ func (c *spaceUserController) pushSettinsEnable(ctx *fiber.Ctx) error {
 	}
 	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
 }
+
+// @Summary Telegram - ссылка для привязки
+// @Tags Профиль пользователя space
+// @Description Ссылка на бота для привязки аккаунта Telegram к уведомлениям, ссылка действует ограниченное время
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Success 200 {object} apimodels.Response{data=spaceapimodels.TelegramLinkView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/user_profile/telegram [get]
+func (c *spaceUserController) telegramLink(ctx *fiber.Ctx) error {
+	userID := middleware.GetUserID(ctx)
+	resp, hMsg, err := telegrambot.Instance.CreateLink(userID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения ссылки для привязки Telegram")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Telegram - отвязать
+// @Tags Профиль пользователя space
+// @Description Отвязать аккаунт Telegram, уведомления в Telegram перестанут приходить
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/user_profile/telegram [delete]
+func (c *spaceUserController) telegramUnlink(ctx *fiber.Ctx) error {
+	userID := middleware.GetUserID(ctx)
+	err := telegrambot.Instance.Unlink(userID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка отвязки Telegram")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
@@ -0,0 +1,207 @@
+package apiv1
+
+import (
+	"hr-tools-backend/controllers"
+	"hr-tools-backend/lib/sso"
+	"hr-tools-backend/middleware"
+	apimodels "hr-tools-backend/models/api"
+	ssoapimodels "hr-tools-backend/models/api/sso"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ssoApiController struct {
+	controllers.BaseAPIController
+}
+
+// InitSsoApiRouters вход через корпоративный IdP, без авторизации
+func InitSsoApiRouters(app *fiber.App) {
+	controller := ssoApiController{}
+	app.Route("sso", func(router fiber.Router) {
+		router.Post("discover", controller.discover)
+		router.Post("token", controller.token)
+		router.Get("oidc/callback", controller.oidcCallback)
+		router.Post("saml/acs", controller.samlAcs)
+		router.Route(":space_id", func(spaceRoute fiber.Router) {
+			spaceRoute.Get("login", controller.login)
+			spaceRoute.Get("metadata", controller.metadata)
+		})
+	})
+}
+
+// InitSsoSettingsApiRouters настройки входа через IdP
+func InitSsoSettingsApiRouters(app *fiber.App) {
+	controller := ssoApiController{}
+	app.Route("sso", func(router fiber.Router) {
+		router.Use(middleware.LicenseRequired())
+		router.Use(middleware.RbacMiddleware())
+		router.Use(middleware.UserRequired())
+		router.Get("", controller.getSettings)
+		router.Put("", controller.updateSettings)
+	})
+}
+
+// @Summary Способ входа по почте
+// @Tags Вход через IdP
+// @Description Проверка, настроен ли для почтового домена сотрудника вход через корпоративный IdP
+// @Param	body body	 ssoapimodels.DiscoverRequest	true	"request body"
+// @Success 200 {object} apimodels.Response{data=ssoapimodels.DiscoverResult}
+// @Failure 400 {object} apimodels.Response
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/sso/discover [post]
+func (c *ssoApiController) discover(ctx *fiber.Ctx) error {
+	var payload ssoapimodels.DiscoverRequest
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err := payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	resp, err := sso.Instance.Discover(payload.Email)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения способа входа")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Начало входа через IdP
+// @Tags Вход через IdP
+// @Description Перенаправление на страницу входа корпоративного IdP
+// @Param   space_id          		path    string  				    	true         "ID спейса"
+// @Success 302
+// @Failure 400 {object} apimodels.Response
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/sso/{space_id}/login [get]
+func (c *ssoApiController) login(ctx *fiber.Ctx) error {
+	redirectURL, hMsg, err := sso.Instance.Login(ctx.UserContext(), ctx.Params("space_id"))
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка входа через IdP")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Redirect(redirectURL, fiber.StatusFound)
+}
+
+// @Summary Возврат из IdP (OIDC)
+// @Tags Вход через IdP
+// @Description Адрес возврата OIDC, перенаправляет на страницу входа UI с одноразовым кодом (code) или ошибкой (error)
+// @Param   state          		query    string  				    	true         "сессия входа"
+// @Param   code          		query    string  				    	false         "authorization_code"
+// @Param   error          		query    string  				    	false         "ошибка IdP"
+// @Success 302
+// @router /api/v1/sso/oidc/callback [get]
+func (c *ssoApiController) oidcCallback(ctx *fiber.Ctx) error {
+	redirectURL := sso.Instance.OidcCallback(ctx.UserContext(), ctx.Query("state"), ctx.Query("code"), ctx.Query("error"))
+	return ctx.Redirect(redirectURL, fiber.StatusFound)
+}
+
+// @Summary Прием ответа IdP (SAML)
+// @Tags Вход через IdP
+// @Description Assertion Consumer Service (HTTP-POST), перенаправляет на страницу входа UI с одноразовым кодом (code) или ошибкой (error)
+// @Accept  x-www-form-urlencoded
+// @Param   SAMLResponse          		formData    string  				    	true         "ответ IdP"
+// @Param   RelayState          		formData    string  				    	true         "сессия входа"
+// @Success 302
+// @router /api/v1/sso/saml/acs [post]
+func (c *ssoApiController) samlAcs(ctx *fiber.Ctx) error {
+	redirectURL := sso.Instance.SamlAcs(ctx.FormValue("SAMLResponse"), ctx.FormValue("RelayState"))
+	return ctx.Redirect(redirectURL, fiber.StatusFound)
+}
+
+// @Summary Метаданные SP (SAML)
+// @Tags Вход через IdP
+// @Description Метаданные для регистрации спейса в IdP
+// @Param   space_id          		path    string  				    	true         "ID спейса"
+// @Success 200
+// @Failure 404
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/sso/{space_id}/metadata [get]
+func (c *ssoApiController) metadata(ctx *fiber.Ctx) error {
+	resp, err := sso.Instance.GetSamlMetadata(ctx.Params("space_id"))
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения метаданных")
+	}
+	if resp == nil {
+		return ctx.SendStatus(fiber.StatusNotFound)
+	}
+	ctx.Set(fiber.HeaderContentType, fiber.MIMEApplicationXMLCharsetUTF8)
+	return ctx.Status(fiber.StatusOK).Send(resp)
+}
+
+// @Summary Получение токенов по коду входа
+// @Tags Вход через IdP
+// @Description Обмен одноразового кода со страницы входа UI на JWT
+// @Param	body body	 ssoapimodels.CodeRequest	true	"request body"
+// @Success 200 {object} apimodels.Response{data=authapimodels.JWTResponse}
+// @Failure 400 {object} apimodels.Response
+// @Failure 401 {object} apimodels.Response
+// @router /api/v1/sso/token [post]
+func (c *ssoApiController) token(ctx *fiber.Ctx) error {
+	var payload ssoapimodels.CodeRequest
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err := payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	resp, err := sso.Instance.ExchangeCode(payload.Code, c.GetClientInfo(ctx))
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(apimodels.NewError(err.Error()))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Настройки входа через IdP
+// @Tags Вход через IdP
+// @Description Настройки входа через IdP и адреса SP для регистрации в IdP
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Success 200 {object} apimodels.Response{data=ssoapimodels.SettingsView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/sso [get]
+func (c *ssoApiController) getSettings(ctx *fiber.Ctx) error {
+	spaceID := middleware.GetUserSpace(ctx)
+	resp, err := sso.Instance.GetSettings(spaceID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения настроек входа через IdP")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Изменение настроек входа через IdP
+// @Tags Вход через IdP
+// @Description Изменение настроек входа через IdP
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param	body body	 ssoapimodels.SettingsData	true	"request body"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/sso [put]
+func (c *ssoApiController) updateSettings(ctx *fiber.Ctx) error {
+	var payload ssoapimodels.SettingsData
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err := payload.Validate(); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	userID := middleware.GetUserID(ctx)
+	spaceID := middleware.GetUserSpace(ctx)
+	hMsg, err := sso.Instance.UpdateSettings(spaceID, userID, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка изменения настроек входа через IdP")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
@@ -2,6 +2,7 @@ package apiv1
 
 import (
 	"hr-tools-backend/controllers"
+	datascope "hr-tools-backend/lib/data-scope"
 	"hr-tools-backend/lib/survey"
 	vacancyhandler "hr-tools-backend/lib/vacancy"
 	"hr-tools-backend/middleware"
@@ -24,6 +25,7 @@ func InitVacancyApiRouters(app *fiber.App) {
 		router.Post("list", controller.list)
 		router.Post("", controller.create)
 		router.Route(":id", func(idRoute fiber.Router) {
+			idRoute.Use(middleware.DataScopeRequired(datascope.EntityVacancy))
 			idRoute.Put("", controller.update)
 			idRoute.Get("", controller.get)
 			idRoute.Delete("", controller.delete)
@@ -112,10 +114,14 @@ func (c *vacancyApiController) update(ctx *fiber.Ctx) error {
 	}
 
 	spaceID := middleware.GetUserSpace(ctx)
-	err = vacancyhandler.Instance.Update(spaceID, id, payload)
+	userID := middleware.GetUserID(ctx)
+	hMsg, err := vacancyhandler.Instance.Update(spaceID, id, userID, payload)
 	if err != nil {
 		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка изменения вакансии")
 	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
 	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
 }
 
@@ -136,7 +142,8 @@ func (c *vacancyApiController) get(ctx *fiber.Ctx) error {
 	}
 
 	spaceID := middleware.GetUserSpace(ctx)
-	resp, err := vacancyhandler.Instance.GetByID(spaceID, id)
+	userID := middleware.GetUserID(ctx)
+	resp, err := vacancyhandler.Instance.GetByID(spaceID, id, userID)
 	if err != nil {
 		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения вакансии")
 	}
@@ -160,7 +167,8 @@ func (c *vacancyApiController) delete(ctx *fiber.Ctx) error {
 	}
 
 	spaceID := middleware.GetUserSpace(ctx)
-	err = vacancyhandler.Instance.Delete(spaceID, id)
+	userID := middleware.GetUserID(ctx)
+	err = vacancyhandler.Instance.Delete(spaceID, id, userID)
 	if err != nil {
 		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка удаления вакансии")
 	}
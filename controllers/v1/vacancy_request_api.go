@@ -2,6 +2,7 @@ package apiv1
 
 import (
 	"hr-tools-backend/controllers"
+	datascope "hr-tools-backend/lib/data-scope"
 	vacancyhandler "hr-tools-backend/lib/vacancy"
 	vacancyreqhandler "hr-tools-backend/lib/vacancy-req"
 	"hr-tools-backend/middleware"
@@ -24,6 +25,7 @@ func InitVacancyRequestApiRouters(app *fiber.App) {
 		router.Post("list", controller.list)
 		router.Post("", controller.create)
 		router.Route(":id", func(idRoute fiber.Router) {
+			idRoute.Use(middleware.DataScopeRequired(datascope.EntityVacancyRequest))
 			idRoute.Put("", controller.update)
 			idRoute.Get("", controller.get)
 			idRoute.Get("vacancies", controller.vacancies)
@@ -38,6 +40,7 @@ func InitVacancyRequestApiRouters(app *fiber.App) {
 			idRoute.Route("approvals", func(approvals fiber.Router) {
 				approvals.Get("", controller.getApprovals)
 				approvals.Put("", controller.saveApprovals)
+				approvals.Put("route", controller.applyRoute) // сформировать по маршруту согласования
 				approvals.Route(":taskId", func(taskRoute fiber.Router) {
 					taskRoute.Post("approve", controller.approve)                // согласовать
 					taskRoute.Post("request_changes", controller.requestChanges) // на доработку
@@ -107,7 +110,8 @@ func (c *vacancyReqApiController) update(ctx *fiber.Ctx) error {
 	}
 
 	spaceID := middleware.GetUserSpace(ctx)
-	hMsg, err := vacancyreqhandler.Instance.Update(spaceID, id, payload)
+	userID := middleware.GetUserID(ctx)
+	hMsg, err := vacancyreqhandler.Instance.Update(spaceID, id, userID, payload)
 	if err != nil {
 		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка обновления заявки")
 	}
@@ -134,7 +138,8 @@ func (c *vacancyReqApiController) get(ctx *fiber.Ctx) error {
 	}
 
 	spaceID := middleware.GetUserSpace(ctx)
-	resp, err := vacancyreqhandler.Instance.GetByID(spaceID, id)
+	userID := middleware.GetUserID(ctx)
+	resp, err := vacancyreqhandler.Instance.GetByID(spaceID, id, userID)
 	if err != nil {
 		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения заявки")
 	}
@@ -158,10 +163,11 @@ func (c *vacancyReqApiController) vacancies(ctx *fiber.Ctx) error {
 	}
 
 	spaceID := middleware.GetUserSpace(ctx)
+	userID := middleware.GetUserID(ctx)
 	filter := vacancyapimodels.VacancyFilter{
 		VacancyRequestID: id,
 	}
-	list, rowCount, err := vacancyhandler.Instance.List(spaceID, id, filter)
+	list, rowCount, err := vacancyhandler.Instance.List(spaceID, userID, filter)
 	if err != nil {
 		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения заявки")
 	}
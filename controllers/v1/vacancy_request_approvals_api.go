@@ -72,6 +72,39 @@ func (c *vacancyReqApiController) saveApprovals(ctx *fiber.Ctx) error {
 	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
 }
 
+// @Summary Сформировать цепочку согласования по маршруту
+// @Tags Согласование заявок
+// @Description Заменяет цепочку согласования заявки шагами маршрута. Если маршрут не указан, подбирается наиболее подходящий активный маршрут
+// @Param   Authorization		header	string								true	"Authorization token"
+// @Param	body 				body	vacancyapimodels.ApplyRouteRequest	true	"request body"
+// @Param   id          		path    string  				    		true    "rec ID"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/vacancy_request/{id}/approvals/route [put]
+func (c *vacancyReqApiController) applyRoute(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	var payload vacancyapimodels.ApplyRouteRequest
+	if err = c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	hMsg, err := vacancyreqhandler.Instance.ApplyRoute(spaceID, id, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка формирования цепочки согласования")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
 // @Summary Согласовать
 // @Tags Согласование заявок
 // @Description Согласовать
@@ -0,0 +1,226 @@
+package apiv1
+
+import (
+	"hr-tools-backend/controllers"
+	"hr-tools-backend/lib/webhook"
+	"hr-tools-backend/middleware"
+	apimodels "hr-tools-backend/models/api"
+	webhookapimodels "hr-tools-backend/models/api/webhook"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type webhookApiController struct {
+	controllers.BaseAPIController
+}
+
+func InitWebhookApiRouters(app *fiber.App) {
+	controller := webhookApiController{}
+	app.Route("webhook", func(router fiber.Router) {
+		router.Use(middleware.LicenseRequired())
+		router.Use(middleware.RbacMiddleware())
+		router.Post("", controller.create)
+		router.Get("list", controller.list)
+		router.Route("delivery", func(deliveryRoute fiber.Router) {
+			deliveryRoute.Post("list", controller.deliveryList)
+			deliveryRoute.Put(":id/redeliver", controller.redeliver)
+		})
+		router.Route(":id", func(idRoute fiber.Router) {
+			idRoute.Get("", controller.get)
+			idRoute.Put("", controller.update)
+			idRoute.Delete("", controller.delete)
+		})
+	})
+}
+
+// @Summary Создание подписки на события
+// @Tags Вебхуки
+// @Description Создание подписки внешней системы на события спейса. Запросы подписываются заголовком X-Webhook-Signature: sha256=HMAC-SHA256(secret, X-Webhook-Timestamp + "." + тело запроса)
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param	body body	 webhookapimodels.SubscriptionData	true	"request body"
+// @Success 200 {object} apimodels.Response{data=string}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/webhook [post]
+func (c *webhookApiController) create(ctx *fiber.Ctx) error {
+	var payload webhookapimodels.SubscriptionData
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err := payload.Validate(true); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	userID := middleware.GetUserID(ctx)
+	spaceID := middleware.GetUserSpace(ctx)
+	id, hMsg, err := webhook.Instance.Create(spaceID, userID, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка создания подписки на события")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(id))
+}
+
+// @Summary Список подписок на события
+// @Tags Вебхуки
+// @Description Список подписок на события спейса
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Success 200 {object} apimodels.Response{data=[]webhookapimodels.SubscriptionView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/webhook/list [get]
+func (c *webhookApiController) list(ctx *fiber.Ctx) error {
+	spaceID := middleware.GetUserSpace(ctx)
+	list, err := webhook.Instance.List(spaceID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения списка подписок на события")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(list))
+}
+
+// @Summary Подписка на события
+// @Tags Вебхуки
+// @Description Подписка на события
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID подписки"
+// @Success 200 {object} apimodels.Response{data=webhookapimodels.SubscriptionView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 404
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/webhook/{id} [get]
+func (c *webhookApiController) get(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	resp, err := webhook.Instance.Get(spaceID, id)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения подписки на события")
+	}
+	if resp == nil {
+		return ctx.SendStatus(fiber.StatusNotFound)
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
+
+// @Summary Изменение подписки на события
+// @Tags Вебхуки
+// @Description Изменение подписки на события, при включении отключенной подписки счетчик неудачных доставок сбрасывается
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID подписки"
+// @Param	body body	 webhookapimodels.SubscriptionData	true	"request body"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/webhook/{id} [put]
+func (c *webhookApiController) update(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	var payload webhookapimodels.SubscriptionData
+	if err = c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	if err = payload.Validate(false); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	userID := middleware.GetUserID(ctx)
+	spaceID := middleware.GetUserSpace(ctx)
+	hMsg, err := webhook.Instance.Update(spaceID, id, userID, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка изменения подписки на события")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Удаление подписки на события
+// @Tags Вебхуки
+// @Description Удаление подписки на события вместе с журналом доставки
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID подписки"
+// @Success 200 {object} apimodels.Response
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/webhook/{id} [delete]
+func (c *webhookApiController) delete(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	userID := middleware.GetUserID(ctx)
+	spaceID := middleware.GetUserSpace(ctx)
+	err = webhook.Instance.Delete(spaceID, id, userID)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка удаления подписки на события")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(nil))
+}
+
+// @Summary Журнал доставки событий
+// @Tags Вебхуки
+// @Description Журнал доставки событий по подпискам: статус, попытки, ответ внешней системы
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param	body body	 webhookapimodels.DeliveryFilter	true	"request filter"
+// @Success 200 {object} apimodels.ScrollerResponse{data=[]webhookapimodels.DeliveryView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/webhook/delivery/list [post]
+func (c *webhookApiController) deliveryList(ctx *fiber.Ctx) error {
+	var payload webhookapimodels.DeliveryFilter
+	if err := c.BodyParser(ctx, &payload); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	list, rowCount, err := webhook.Instance.ListDeliveries(spaceID, payload)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка получения журнала доставки событий")
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewScrollerResponse(list, rowCount))
+}
+
+// @Summary Повторная отправка события
+// @Tags Вебхуки
+// @Description Повторная отправка события из журнала доставки с тем же идентификатором, возвращается результат попытки
+// @Param   Authorization		header		string	true	"Authorization token"
+// @Param   id          		path    string  				    	true         "ID доставки"
+// @Success 200 {object} apimodels.Response{data=webhookapimodels.DeliveryView}
+// @Failure 400 {object} apimodels.Response
+// @Failure 403
+// @Failure 500 {object} apimodels.Response
+// @router /api/v1/space/webhook/delivery/{id}/redeliver [put]
+func (c *webhookApiController) redeliver(ctx *fiber.Ctx) error {
+	id, err := c.GetID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(err.Error()))
+	}
+
+	spaceID := middleware.GetUserSpace(ctx)
+	resp, hMsg, err := webhook.Instance.Redeliver(ctx.UserContext(), spaceID, id)
+	if err != nil {
+		return c.SendError(ctx, c.GetLogger(ctx), err, "Ошибка повторной отправки события")
+	}
+	if hMsg != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(apimodels.NewError(hMsg))
+	}
+	return ctx.Status(fiber.StatusOK).JSON(apimodels.NewResponse(resp))
+}
@@ -15,7 +15,7 @@ var DB *gorm.DB
 
 func Connect(host string, port string, database string, user string, pass string, debugMode bool, migrate bool) (err error) {
 	if DB == nil {
-		dbConnString := fmt.Sprintf("host=%s port=%s user=%s dbname=%s sslmode=disable password=%s", host, port, user, database, pass)
+		dbConnString := ConnString(host, port, database, user, pass)
 		db, err := gorm.Open(postgres.Open(dbConnString), &gorm.Config{
 			Logger: gorm_logrus.New(),
 		})
@@ -39,6 +39,11 @@ func Connect(host string, port string, database string, user string, pass string
 	return err
 }
 
+// ConnString строка подключения к БД, используется также для отдельных соединений (LISTEN/NOTIFY)
+func ConnString(host string, port string, database string, user string, pass string) string {
+	return fmt.Sprintf("host=%s port=%s user=%s dbname=%s sslmode=disable password=%s", host, port, user, database, pass)
+}
+
 func PingDB() error {
 	db, err := DB.DB()
 	if err != nil {
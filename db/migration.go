@@ -88,6 +88,15 @@ func AutoMigrateDB() error {
 	if err := DB.AutoMigrate(&dbmodels.Applicant{}); err != nil {
 		return errors.Wrap(err, "ошибка создания структуры Applicant")
 	}
+	if err := DB.AutoMigrate(&dbmodels.Candidate{}); err != nil {
+		return errors.Wrap(err, "ошибка создания структуры Candidate")
+	}
+	if err := DB.AutoMigrate(&dbmodels.TalentPool{}); err != nil {
+		return errors.Wrap(err, "ошибка создания структуры TalentPool")
+	}
+	if err := DB.AutoMigrate(&dbmodels.TalentPoolCandidate{}); err != nil {
+		return errors.Wrap(err, "ошибка создания структуры TalentPoolCandidate")
+	}
 	if err := DB.AutoMigrate(&dbmodels.MessageTemplate{}); err != nil {
 		return errors.Wrap(err, "ошибка создания структуры MessageTemplate")
 	}
@@ -184,6 +193,114 @@ func AutoMigrateDB() error {
 		return errors.Wrap(err, "ошибка создания структуры PromptExecution")
 	}
 
+	if err := DB.AutoMigrate(&dbmodels.ApplicantResumeSuggestion{}); err != nil {
+		return errors.Wrap(err, "ошибка создания структуры ApplicantResumeSuggestion")
+	}
+
+	if err := DB.AutoMigrate(&dbmodels.Interview{}); err != nil {
+		return errors.Wrap(err, "ошибка создания структуры Interview")
+	}
+
+	if err := DB.AutoMigrate(&dbmodels.InterviewParticipant{}); err != nil {
+		return errors.Wrap(err, "ошибка создания структуры InterviewParticipant")
+	}
+
+	if err := DB.AutoMigrate(&dbmodels.InterviewSlot{}); err != nil {
+		return errors.Wrap(err, "ошибка создания структуры InterviewSlot")
+	}
+
+	if err := DB.AutoMigrate(&dbmodels.InterviewBooking{}); err != nil {
+		return errors.Wrap(err, "ошибка создания структуры InterviewBooking")
+	}
+
+	if err := DB.AutoMigrate(&dbmodels.CalendarConnection{}); err != nil {
+		return errors.Wrap(err, "ошибка создания структуры CalendarConnection")
+	}
+
+	if err := DB.AutoMigrate(&dbmodels.InterviewCalendarEvent{}); err != nil {
+		return errors.Wrap(err, "ошибка создания структуры InterviewCalendarEvent")
+	}
+
+	if err := DB.AutoMigrate(&dbmodels.ScorecardTemplate{}); err != nil {
+		return errors.Wrap(err, "ошибка создания структуры ScorecardTemplate")
+	}
+
+	if err := DB.AutoMigrate(&dbmodels.Scorecard{}); err != nil {
+		return errors.Wrap(err, "ошибка создания структуры Scorecard")
+	}
+
+	if err := DB.AutoMigrate(&dbmodels.Offer{}); err != nil {
+		return errors.Wrap(err, "ошибка создания структуры Offer")
+	}
+
+	if err := DB.AutoMigrate(&dbmodels.OfferVersion{}); err != nil {
+		return errors.Wrap(err, "ошибка создания структуры OfferVersion")
+	}
+
+	if err := DB.AutoMigrate(&dbmodels.ApprovalRoute{}); err != nil {
+		return errors.Wrap(err, "ошибка создания структуры ApprovalRoute")
+	}
+
+	if err := DB.AutoMigrate(&dbmodels.ApprovalDelegate{}); err != nil {
+		return errors.Wrap(err, "ошибка создания структуры ApprovalDelegate")
+	}
+
+	if err := DB.AutoMigrate(&dbmodels.AutomationRule{}); err != nil {
+		return errors.Wrap(err, "ошибка создания структуры AutomationRule")
+	}
+
+	if err := DB.AutoMigrate(&dbmodels.AutomationLog{}); err != nil {
+		return errors.Wrap(err, "ошибка создания структуры AutomationLog")
+	}
+
+	if err := DB.AutoMigrate(&dbmodels.PersonalDataConsent{}); err != nil {
+		return errors.Wrap(err, "ошибка создания структуры PersonalDataConsent")
+	}
+
+	if err := DB.AutoMigrate(&dbmodels.DataSubjectRequest{}); err != nil {
+		return errors.Wrap(err, "ошибка создания структуры DataSubjectRequest")
+	}
+
+	if err := DB.AutoMigrate(&dbmodels.PersonalDataAudit{}); err != nil {
+		return errors.Wrap(err, "ошибка создания структуры PersonalDataAudit")
+	}
+
+	if err := DB.AutoMigrate(&dbmodels.WebhookSubscription{}); err != nil {
+		return errors.Wrap(err, "ошибка создания структуры WebhookSubscription")
+	}
+
+	if err := DB.AutoMigrate(&dbmodels.WebhookDelivery{}); err != nil {
+		return errors.Wrap(err, "ошибка создания структуры WebhookDelivery")
+	}
+
+	if err := DB.AutoMigrate(&dbmodels.SpaceRole{}); err != nil {
+		return errors.Wrap(err, "ошибка создания структуры SpaceRole")
+	}
+
+	if err := DB.AutoMigrate(&dbmodels.ApiKey{}); err != nil {
+		return errors.Wrap(err, "ошибка создания структуры ApiKey")
+	}
+
+	if err := DB.AutoMigrate(&dbmodels.SpaceSso{}); err != nil {
+		return errors.Wrap(err, "ошибка создания структуры SpaceSso")
+	}
+
+	if err := DB.AutoMigrate(&dbmodels.SsoSession{}); err != nil {
+		return errors.Wrap(err, "ошибка создания структуры SsoSession")
+	}
+	if err := DB.AutoMigrate(&dbmodels.AuthSession{}); err != nil {
+		return errors.Wrap(err, "ошибка создания структуры AuthSession")
+	}
+	if err := DB.AutoMigrate(&dbmodels.UserTwoFactor{}); err != nil {
+		return errors.Wrap(err, "ошибка создания структуры UserTwoFactor")
+	}
+	if err := DB.AutoMigrate(&dbmodels.AuditLog{}); err != nil {
+		return errors.Wrap(err, "ошибка создания структуры AuditLog")
+	}
+	if err := DB.AutoMigrate(&dbmodels.CustomField{}); err != nil {
+		return errors.Wrap(err, "ошибка создания структуры CustomField")
+	}
+
 	log.Info("Миграция прошла успешно")
 	return nil
 }
@@ -42,10 +42,15 @@ func addSuperAdmin() {
 	if existedRec != nil {
 		return
 	}
+	passwordHash, err := authhelpers.HashPassword(config.Conf.Admin.Password)
+	if err != nil {
+		log.WithError(err).Error("ошибка добавления суперадмина")
+		return
+	}
 	rec := dbmodels.AdminPanelUser{
 		IsActive:    true,
 		Role:        models.UserRoleSuperAdmin,
-		Password:    authhelpers.GetMD5Hash(config.Conf.Admin.Password),
+		Password:    passwordHash,
 		FirstName:   config.Conf.Admin.FirstName,
 		LastName:    config.Conf.Admin.LastName,
 		Email:       config.Conf.Admin.Email,
@@ -60,24 +65,23 @@ func addSuperAdmin() {
 func addPushSettings() {
 	store := pushsettingsstore.NewInstance(DB)
 
-	userList, err := store.GetUsersWithoutSettings()
-	if err != nil {
-		log.WithError(err).Error("ошибка добавления настроек пушей")
-		return
-	}
-
 	value := false
 	rec := dbmodels.SpacePushSetting{
 		SystemValue: &value,
 		EmailValue:  &value,
 		TgValue:     &value,
 	}
-	for _, user := range userList {
-		rec.SpaceID = user.SpaceID
-		rec.SpaceUserID = user.ID
-
-		for key := range models.PushCodeMap {
-			rec.Code = key
+	// настройки добавляются и по новым событиям для уже существующих пользователей
+	for key := range models.PushCodeMap {
+		userList, err := store.GetUsersWithoutSetting(key)
+		if err != nil {
+			log.WithError(err).Error("ошибка добавления настроек пушей")
+			return
+		}
+		rec.Code = key
+		for _, user := range userList {
+			rec.SpaceID = user.SpaceID
+			rec.SpaceUserID = user.ID
 			err := store.Create(rec)
 			if err != nil {
 				log.WithError(err).Error("ошибка добавления настроек пушей")
@@ -9,9 +9,24 @@ import (
 	masaihandler "hr-tools-backend/lib/ai/masai"
 	promptcheckhandler "hr-tools-backend/lib/ai/prompt-check"
 	"hr-tools-backend/lib/analytics"
+	apikey "hr-tools-backend/lib/api-key"
 	"hr-tools-backend/lib/applicant"
 	applicanthistoryhandler "hr-tools-backend/lib/applicant-history"
+	applicantstagelimitworker "hr-tools-backend/lib/applicant/stage-limit-worker"
 	aprovaltaskhandler "hr-tools-backend/lib/aproval-task"
+	approvalroutehandler "hr-tools-backend/lib/aproval-task/route"
+	auditlog "hr-tools-backend/lib/audit-log"
+	auditlogretentionworker "hr-tools-backend/lib/audit-log/retention-worker"
+	authsession "hr-tools-backend/lib/auth-session"
+	"hr-tools-backend/lib/automation"
+	automationstageworker "hr-tools-backend/lib/automation/stage-worker"
+	"hr-tools-backend/lib/calendar"
+	calendarsyncworker "hr-tools-backend/lib/calendar/sync-worker"
+	"hr-tools-backend/lib/candidate"
+	candidatelinkworker "hr-tools-backend/lib/candidate/link-worker"
+	careersite "hr-tools-backend/lib/career-site"
+	customfield "hr-tools-backend/lib/custom-field"
+	datascope "hr-tools-backend/lib/data-scope"
 	cityprovider "hr-tools-backend/lib/dicts/city"
 	companyprovider "hr-tools-backend/lib/dicts/company"
 	companystructprovider "hr-tools-backend/lib/dicts/company-struct"
@@ -19,9 +34,12 @@ import (
 	jobtitleprovider "hr-tools-backend/lib/dicts/job-title"
 	languagesprovider "hr-tools-backend/lib/dicts/languages"
 	rejectreasonprovider "hr-tools-backend/lib/dicts/reject-reason"
+	emailinbox "hr-tools-backend/lib/email-inbox"
+	emailinboxworker "hr-tools-backend/lib/email-inbox/worker"
 	xlsexport "hr-tools-backend/lib/export/xls"
 	avitohandler "hr-tools-backend/lib/external-services/avito"
 	avitoclient "hr-tools-backend/lib/external-services/avito/client"
+	fakehandler "hr-tools-backend/lib/external-services/fake"
 	hhhandler "hr-tools-backend/lib/external-services/hh"
 	"hr-tools-backend/lib/external-services/hh/hhclient"
 	negotiationchathandler "hr-tools-backend/lib/external-services/negotiation-chat"
@@ -30,21 +48,37 @@ import (
 	externalserviceworker "hr-tools-backend/lib/external-services/worker"
 	filestorage "hr-tools-backend/lib/file-storage"
 	gpthandler "hr-tools-backend/lib/gpt"
+	"hr-tools-backend/lib/interview"
+	interviewreminderworker "hr-tools-backend/lib/interview/reminder-worker"
 	licencehandler "hr-tools-backend/lib/licence"
 	licenseworker "hr-tools-backend/lib/licence/worker"
 	messagetemplate "hr-tools-backend/lib/message-template"
+	"hr-tools-backend/lib/offer"
+	passwordpolicy "hr-tools-backend/lib/password-policy"
+	personaldata "hr-tools-backend/lib/personal-data"
+	pdretentionworker "hr-tools-backend/lib/personal-data/retention-worker"
 	"hr-tools-backend/lib/rbac"
+	resumeparser "hr-tools-backend/lib/resume-parser"
+	"hr-tools-backend/lib/scorecard"
 	spaceauthhandler "hr-tools-backend/lib/space/auth"
 	spacehandler "hr-tools-backend/lib/space/handler"
 	pushhandler "hr-tools-backend/lib/space/push/handler"
+	spacerolehandler "hr-tools-backend/lib/space/roles"
 	spacesettingshandler "hr-tools-backend/lib/space/settings/handler"
 	spaceusershander "hr-tools-backend/lib/space/users/hander"
+	"hr-tools-backend/lib/sso"
 	supersethandler "hr-tools-backend/lib/superset"
 	"hr-tools-backend/lib/survey"
+	talentpool "hr-tools-backend/lib/talent-pool"
+	"hr-tools-backend/lib/telegram"
+	telegrambot "hr-tools-backend/lib/telegram/bot"
+	telegramupdateworker "hr-tools-backend/lib/telegram/update-worker"
+	twofactor "hr-tools-backend/lib/two-factor"
 	initchecker "hr-tools-backend/lib/utils/init-checker"
 	"hr-tools-backend/lib/utils/lock"
 	vacancyhandler "hr-tools-backend/lib/vacancy"
 	vacancyreqhandler "hr-tools-backend/lib/vacancy-req"
+	vacancyreqescalationworker "hr-tools-backend/lib/vacancy-req/escalation-worker"
 	"hr-tools-backend/lib/vk"
 	vkstatuscheckworker "hr-tools-backend/lib/vk/status-check-worker"
 	vkstep0runworker "hr-tools-backend/lib/vk/step0-run-worker"
@@ -54,6 +88,8 @@ import (
 	vkstep9doneworker "hr-tools-backend/lib/vk/step9-done-worker"
 	vkstep9runworker "hr-tools-backend/lib/vk/step9-run-worker"
 	vkstep9scoreworker "hr-tools-backend/lib/vk/step9-score-worker"
+	"hr-tools-backend/lib/webhook"
+	webhookretryworker "hr-tools-backend/lib/webhook/retry-worker"
 	connectionhub "hr-tools-backend/lib/ws/hub/connection-hub"
 	"time"
 )
@@ -66,15 +102,20 @@ func InitAllServices(ctx context.Context) {
 	InitDBConnection()
 	InitS3()
 	InitSmtp()
-	connectionhub.Init()
+	connectionhub.Init(ctx)
 	lock.InitResourceLock(ctx)
 
 	filestorage.NewHandler()
 	cityprovider.NewHandler()
+	telegram.NewHandler()
 	pushhandler.NewHandler()
 	hhclient.NewProvider(config.Conf.HH.RedirectUri)
 	avitoclient.NewProvider()
 	applicanthistoryhandler.NewHandler()
+	auditlog.NewHandler()
+	authsession.NewHandler()
+	passwordpolicy.NewHandler()
+	twofactor.NewHandler()
 	spaceusershander.NewHandler()
 	spacehandler.NewHandler(config.Conf.Sales.Email)
 	spaceauthhandler.NewHandler()
@@ -87,24 +128,46 @@ func InitAllServices(ctx context.Context) {
 	languagesprovider.NewHandler()
 	rejectreasonprovider.NewHandler()
 	aprovaltaskhandler.NewHandler()
+	approvalroutehandler.NewHandler()
+	customfield.NewHandler()
 	vacancyhandler.NewHandler()
 	vacancyreqhandler.NewHandler()
+	telegrambot.NewHandler()
 	spacesettingshandler.NewHandler()
 	gpthandler.NewHandler(false)
 	hhhandler.NewHandler()
 	avitohandler.NewHandler()
+	if config.Conf.FakeJobSite.Enabled {
+		fakehandler.NewHandler(config.Conf.FakeJobSite.Dir, config.Conf.FakeJobSite.RedirectUri)
+	}
 	applicant.NewHandler()
+	candidate.NewHandler()
+	talentpool.NewHandler()
+	personaldata.NewHandler()
+	careersite.NewHandler()
+	resumeparser.NewHandler(ctx)
+	emailinbox.NewHandler()
 	messagetemplate.NewHandler()
+	calendar.NewHandler()
+	interview.NewHandler()
+	scorecard.NewHandler()
+	offer.NewHandler()
 	xlsexport.NewHandler()
 	analytics.NewHandler()
 	negotiationchathandler.NewHandler()
 	survey.NewHandler()
 	vk.NewHandler(ctx)
+	automation.NewHandler()
+	webhook.NewHandler()
 	supersethandler.NewHandler(config.Conf.Superset.Host, config.Conf.Superset.Username, config.Conf.Superset.Password, config.Conf.Superset.DashboardParams)
 	licencehandler.NewHandler()
 	masaihandler.NewHandler(ctx)
 	promptcheckhandler.NewHandler(ctx)
 	rbac.NewHandler()
+	datascope.NewHandler()
+	spacerolehandler.NewHandler()
+	apikey.NewHandler()
+	sso.NewHandler()
 
 	checkInstances()
 
@@ -115,10 +178,15 @@ func checkInstances() {
 	initchecker.CheckInit(
 		"filestorage", filestorage.Instance,
 		"cityprovider", cityprovider.Instance,
+		"telegram", telegram.Instance,
 		"pushhandler", pushhandler.Instance,
 		"hhclient", hhclient.Instance,
 		"avitoclient", avitoclient.Instance,
 		"applicanthistoryhandler", applicanthistoryhandler.Instance,
+		"auditlog", auditlog.Instance,
+		"authsession", authsession.Instance,
+		"passwordpolicy", passwordpolicy.Instance,
+		"twofactor", twofactor.Instance,
 		"spaceusershander", spaceusershander.Instance,
 		"spacehandler", spacehandler.Instance,
 		"spaceauthhandler", spaceauthhandler.Instance,
@@ -131,19 +199,38 @@ func checkInstances() {
 		"languagesprovider", languagesprovider.Instance,
 		"rejectreasonprovider", rejectreasonprovider.Instance,
 		"aprovaltaskhandler", aprovaltaskhandler.Instance,
+		"approvalroutehandler", approvalroutehandler.Instance,
+		"customfield", customfield.Instance,
 		"vacancyhandler", vacancyhandler.Instance,
 		"vacancyreqhandler", vacancyreqhandler.Instance,
+		"telegrambot", telegrambot.Instance,
 		"spacesettingshandler", spacesettingshandler.Instance,
 		"gpthandler", gpthandler.Instance,
 		"hhhandler", hhhandler.Instance,
 		"avitohandler", avitohandler.Instance,
 		"applicant", applicant.Instance,
+		"candidate", candidate.Instance,
+		"talentpool", talentpool.Instance,
+		"personaldata", personaldata.Instance,
+		"careersite", careersite.Instance,
+		"resumeparser", resumeparser.Instance,
+		"emailinbox", emailinbox.Instance,
 		"messagetemplate", messagetemplate.Instance,
+		"calendar", calendar.Instance,
+		"interview", interview.Instance,
+		"scorecard", scorecard.Instance,
+		"offer", offer.Instance,
 		"xlsexport", xlsexport.Instance,
 		"analytics", analytics.Instance,
 		"negotiationchathandler", negotiationchathandler.Instance,
 		"survey", survey.Instance,
 		"vk", vk.Instance,
+		"automation", automation.Instance,
+		"webhook", webhook.Instance,
+		"spacerolehandler", spacerolehandler.Instance,
+		"datascope", datascope.Instance,
+		"apikey", apikey.Instance,
+		"sso", sso.Instance,
 		"supersethandler", supersethandler.Instance,
 		"licencehandler", licencehandler.Instance,
 		"masaihandler", masaihandler.Instance,
@@ -188,6 +275,50 @@ func initWorkers(ctx context.Context) {
 		// Задача получения сообщений из HH/Avito от кандидатов
 		newmsgworker.StartWorker(ctx)
 	}
+	if makeTimeGap(ctx) {
+		// Задача получения откликов кандидатов из почтовых ящиков спейсов
+		emailinboxworker.StartWorker(ctx)
+	}
+	if makeTimeGap(ctx) {
+		// Задача отправки напоминаний о предстоящих интервью
+		interviewreminderworker.StartWorker(ctx)
+	}
+	if makeTimeGap(ctx) {
+		// Задача синхронизации интервью с календарями участников
+		calendarsyncworker.StartWorker(ctx)
+	}
+	if makeTimeGap(ctx) {
+		// Задача эскалации просроченных задач согласования заявок
+		vacancyreqescalationworker.StartWorker(ctx)
+	}
+	if makeTimeGap(ctx) {
+		// Задача правил автоматизации по сроку нахождения кандидата на этапе
+		automationstageworker.StartWorker(ctx)
+	}
+	if makeTimeGap(ctx) {
+		// Задача контроля лимита времени нахождения кандидатов на этапах подбора
+		applicantstagelimitworker.StartWorker(ctx)
+	}
+	if makeTimeGap(ctx) {
+		// Задача получения обновлений бота Telegram (webhook / long polling)
+		telegramupdateworker.StartWorker(ctx)
+	}
+	if makeTimeGap(ctx) {
+		// Задача привязки кандидатов на вакансиях к карточкам кандидатов
+		candidatelinkworker.StartWorker(ctx)
+	}
+	if makeTimeGap(ctx) {
+		// Задача обезличивания/удаления кандидатов по истечении срока хранения ПД
+		pdretentionworker.StartWorker(ctx)
+	}
+	if makeTimeGap(ctx) {
+		// Задача удаления записей журнала аудита по истечении срока хранения
+		auditlogretentionworker.StartWorker(ctx)
+	}
+	if makeTimeGap(ctx) {
+		// Задача повторной отправки событий по подпискам вебхуков
+		webhookretryworker.StartWorker(ctx)
+	}
 	// Deprecated: используются vkstep
 	/*
 		if makeTimeGap(ctx) {
@@ -2,20 +2,36 @@ package adminpanelauthhandler
 
 import (
 	"fmt"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"hr-tools-backend/config"
 	"hr-tools-backend/db"
 	adminpaneluserstore "hr-tools-backend/lib/admin-panel/store"
+	authsession "hr-tools-backend/lib/auth-session"
+	passwordpolicy "hr-tools-backend/lib/password-policy"
+	twofactor "hr-tools-backend/lib/two-factor"
 	authhelpers "hr-tools-backend/lib/utils/auth-helpers"
+	authutils "hr-tools-backend/lib/utils/auth-utils"
 	initchecker "hr-tools-backend/lib/utils/init-checker"
+	"hr-tools-backend/models"
 	authapimodels "hr-tools-backend/models/api/auth"
+	dbmodels "hr-tools-backend/models/db"
 	"time"
 )
 
 type Provider interface {
-	Login(email, password string) (response authapimodels.JWTResponse, err error)
+	// Login вход по паролю, при подключенной двухфакторной аутентификации токены выдаются после LoginTwoFactor
+	Login(email, password string, client authapimodels.ClientInfo) (response authapimodels.JWTResponse, err error)
+	// LoginTwoFactor второй шаг входа по коду TOTP или коду восстановления.
+	// Если двухфакторная аутентификация обязательна и еще не подключена, код подтверждает подключение
+	LoginTwoFactor(challenge, code string, client authapimodels.ClientInfo) (response authapimodels.JWTResponse, err error)
+	// LoginTwoFactorSetup секрет TOTP для подключения при входе, когда двухфакторная аутентификация обязательна
+	LoginTwoFactorSetup(challenge string) (resp authapimodels.TwoFactorSetup, hMsg string, err error)
+	RefreshToken(refreshToken string, client authapimodels.ClientInfo) (response authapimodels.JWTResponse, err error)
+	GetTwoFactorStatus(userID string) (authapimodels.TwoFactorStatus, error)
+	// SetupTwoFactor секрет TOTP для подключения двухфакторной аутентификации из профиля
+	SetupTwoFactor(userID string) (resp authapimodels.TwoFactorSetup, hMsg string, err error)
+	DisableTwoFactor(userID, code string) (hMsg string, err error)
 }
 
 var Instance Provider
@@ -34,7 +50,7 @@ type impl struct {
 	store adminpaneluserstore.Provider
 }
 
-func (i impl) Login(email, password string) (response authapimodels.JWTResponse, err error) {
+func (i impl) Login(email, password string, client authapimodels.ClientInfo) (response authapimodels.JWTResponse, err error) {
 	logger := log.WithField("email", email)
 	user, err := i.store.FindByEmail(email)
 	if err != nil {
@@ -47,18 +63,160 @@ func (i impl) Login(email, password string) (response authapimodels.JWTResponse,
 		logger.Debug("пользователь с такой почтой не найден")
 		return authapimodels.JWTResponse{}, errors.New("пользователь с такой почтой не найден")
 	}
-	if authhelpers.GetMD5Hash(password) != user.Password {
+	if hMsg := passwordpolicy.Instance.CheckLock(user.LockedUntil); hMsg != "" {
+		logger.Debug("вход по паролю заблокирован")
+		return authapimodels.JWTResponse{}, errors.New(hMsg)
+	}
+	ok, needRehash := authhelpers.CheckPassword(password, user.Password)
+	if !ok {
 		logger.Debug("пользователь не прошел проверку пароля")
-		return authapimodels.JWTResponse{}, errors.New("пользователь не прошел проверку пароля")
+		return authapimodels.JWTResponse{}, i.addFailedLogin(user.ID)
+	}
+	i.onPasswordLogin(*user, password, needRehash)
+	if !user.IsActive {
+		logger.Debug("пользователь деактивирован")
+		return authapimodels.JWTResponse{}, errors.New("учетная запись деактивирована")
+	}
+	twoFactorEnabled, err := twofactor.Instance.IsEnabled(models.AuthAdminPanelUser, user.ID)
+	if err != nil {
+		logger.WithError(err).Error("ошибка проверки двухфакторной аутентификации")
+		return authapimodels.JWTResponse{}, err
+	}
+	setupRequired := !twoFactorEnabled && config.Conf.AdminPanelAuth.TwoFactorRequired
+	if twoFactorEnabled || setupRequired {
+		challenge, err := twofactor.Instance.CreateChallenge(models.AuthAdminPanelUser, user.ID)
+		if err != nil {
+			logger.WithError(err).Error("ошибка генерации токена второго шага входа")
+			return authapimodels.JWTResponse{}, err
+		}
+		return authapimodels.JWTResponse{
+			TwoFactorRequired:      true,
+			TwoFactorSetupRequired: setupRequired,
+			TwoFactorToken:         challenge,
+		}, nil
+	}
+	return i.issueTokens(*user, client)
+}
+
+func (i impl) LoginTwoFactor(challenge, code string, client authapimodels.ClientInfo) (response authapimodels.JWTResponse, err error) {
+	user, err := i.getChallengeUser(challenge)
+	if err != nil {
+		return authapimodels.JWTResponse{}, err
+	}
+	logger := log.WithField("user_id", user.ID)
+	enabled, err := twofactor.Instance.IsEnabled(models.AuthAdminPanelUser, user.ID)
+	if err != nil {
+		logger.WithError(err).Error("ошибка проверки двухфакторной аутентификации")
+		return authapimodels.JWTResponse{}, err
+	}
+	var recoveryCodes []string
+	if enabled {
+		hMsg, err := twofactor.Instance.Verify(models.AuthAdminPanelUser, user.ID, code)
+		if err != nil {
+			logger.WithError(err).Error("ошибка проверки кода")
+			return authapimodels.JWTResponse{}, err
+		}
+		if hMsg != "" {
+			return authapimodels.JWTResponse{}, errors.New(hMsg)
+		}
+	} else {
+		if !config.Conf.AdminPanelAuth.TwoFactorRequired {
+			return authapimodels.JWTResponse{}, errors.New("двухфакторная аутентификация не подключена")
+		}
+		codes, hMsg, err := twofactor.Instance.Enable(models.AuthAdminPanelUser, user.ID, code)
+		if err != nil {
+			logger.WithError(err).Error("ошибка подключения двухфакторной аутентификации")
+			return authapimodels.JWTResponse{}, err
+		}
+		if hMsg != "" {
+			return authapimodels.JWTResponse{}, errors.New(hMsg)
+		}
+		recoveryCodes = codes
+	}
+	response, err = i.issueTokens(*user, client)
+	if err != nil {
+		return authapimodels.JWTResponse{}, err
+	}
+	response.RecoveryCodes = recoveryCodes
+	return response, nil
+}
+
+func (i impl) LoginTwoFactorSetup(challenge string) (resp authapimodels.TwoFactorSetup, hMsg string, err error) {
+	user, err := i.getChallengeUser(challenge)
+	if err != nil {
+		return authapimodels.TwoFactorSetup{}, err.Error(), nil
+	}
+	if !config.Conf.AdminPanelAuth.TwoFactorRequired {
+		return authapimodels.TwoFactorSetup{}, "двухфакторная аутентификация подключается после входа", nil
+	}
+	return twofactor.Instance.Setup(models.AuthAdminPanelUser, user.ID, user.Email)
+}
+
+func (i impl) RefreshToken(refreshToken string, client authapimodels.ClientInfo) (response authapimodels.JWTResponse, err error) {
+	session, newRefreshToken, err := authsession.Instance.Refresh(models.AuthAdminPanelUser, refreshToken, client)
+	if err != nil {
+		return authapimodels.JWTResponse{}, err
+	}
+	user, err := i.store.GetByID(session.UserID)
+	if err != nil {
+		log.
+			WithField("user_id", session.UserID).
+			WithError(err).
+			Error("ошибка поиска пользователя")
+		return authapimodels.JWTResponse{}, err
+	}
+	if user == nil {
+		return authapimodels.JWTResponse{}, errors.New("пользователь не найден")
+	}
+	if !user.IsActive {
+		return authapimodels.JWTResponse{}, errors.New("учетная запись деактивирована")
+	}
+	tokenString, err := authutils.GetAdminPanelToken(user.ID, getName(*user), user.Role, session.ID)
+	if err != nil {
+		log.WithError(err).Error("ошибка генерации JWT")
+		return authapimodels.JWTResponse{}, err
+	}
+	return authapimodels.JWTResponse{
+		Token:        tokenString,
+		RefreshToken: newRefreshToken,
+	}, nil
+}
+
+func (i impl) SetupTwoFactor(userID string) (resp authapimodels.TwoFactorSetup, hMsg string, err error) {
+	user, err := i.store.GetByID(userID)
+	if err != nil {
+		return authapimodels.TwoFactorSetup{}, "", errors.Wrap(err, "ошибка поиска пользователя")
 	}
-	claims := jwt.MapClaims{
-		"name": fmt.Sprintf("%s %s", user.FirstName, user.LastName),
-		"sub":  user.ID,
-		"role": user.Role,
-		"exp":  time.Now().Add(time.Second * time.Duration(config.Conf.AdminPanelAuth.JWTExpireInSec)).Unix(),
+	if user == nil {
+		return authapimodels.TwoFactorSetup{}, "", errors.New("пользователь не найден")
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(config.Conf.AdminPanelAuth.JWTSecret))
+	return twofactor.Instance.Setup(models.AuthAdminPanelUser, user.ID, user.Email)
+}
+
+func (i impl) GetTwoFactorStatus(userID string) (authapimodels.TwoFactorStatus, error) {
+	status, err := twofactor.Instance.GetStatus(models.AuthAdminPanelUser, userID)
+	if err != nil {
+		return authapimodels.TwoFactorStatus{}, err
+	}
+	status.Required = config.Conf.AdminPanelAuth.TwoFactorRequired
+	return status, nil
+}
+
+func (i impl) DisableTwoFactor(userID, code string) (hMsg string, err error) {
+	if config.Conf.AdminPanelAuth.TwoFactorRequired {
+		return "двухфакторная аутентификация обязательна для админки", nil
+	}
+	return twofactor.Instance.Disable(models.AuthAdminPanelUser, userID, code)
+}
+
+func (i impl) issueTokens(user dbmodels.AdminPanelUser, client authapimodels.ClientInfo) (authapimodels.JWTResponse, error) {
+	logger := log.WithField("user_id", user.ID)
+	sessionID, refreshToken, err := authsession.Instance.Create(models.AuthAdminPanelUser, user.ID, "", client)
+	if err != nil {
+		logger.WithError(err).Error("ошибка создания сессии")
+		return authapimodels.JWTResponse{}, err
+	}
+	tokenString, err := authutils.GetAdminPanelToken(user.ID, getName(user), user.Role, sessionID)
 	if err != nil {
 		logger.WithError(err).Error("ошибка генерации JWT")
 		return authapimodels.JWTResponse{}, err
@@ -70,6 +228,85 @@ func (i impl) Login(email, password string) (response authapimodels.JWTResponse,
 			Error("ошибка обновления даты последнего входа")
 	}
 	return authapimodels.JWTResponse{
-		Token: tokenString,
+		Token:        tokenString,
+		RefreshToken: refreshToken,
 	}, nil
 }
+
+// addFailedLogin учет неверного пароля, после превышения попыток вход по паролю блокируется
+func (i impl) addFailedLogin(userID string) error {
+	logger := log.WithField("user_id", userID)
+	errWrongPassword := errors.New("пользователь не прошел проверку пароля")
+	attempts, err := i.store.AddFailedLogin(userID)
+	if err != nil {
+		logger.WithError(err).Error("ошибка учета неверного пароля")
+		return errWrongPassword
+	}
+	lockedUntil := passwordpolicy.Instance.GetLockUntil(attempts)
+	if lockedUntil == nil {
+		return errWrongPassword
+	}
+	updMap := map[string]interface{}{
+		"failed_login_attempts": 0,
+		"locked_until":          lockedUntil,
+	}
+	err = i.store.Update(userID, updMap)
+	if err != nil {
+		logger.WithError(err).Error("ошибка блокировки входа по паролю")
+		return errWrongPassword
+	}
+	logger.Warn("вход в админку по паролю заблокирован после неудачных попыток")
+	return errors.New(passwordpolicy.Instance.CheckLock(lockedUntil))
+}
+
+// onPasswordLogin сброс счетчика неверных паролей и пересчет хеша устаревшего формата
+func (i impl) onPasswordLogin(user dbmodels.AdminPanelUser, password string, needRehash bool) {
+	logger := log.WithField("user_id", user.ID)
+	updMap := map[string]interface{}{}
+	if user.FailedLoginAttempts != 0 || user.LockedUntil != nil {
+		updMap["failed_login_attempts"] = 0
+		updMap["locked_until"] = nil
+	}
+	if needRehash {
+		hash, err := authhelpers.HashPassword(password)
+		if err != nil {
+			logger.WithError(err).Error("ошибка пересчета хеша пароля")
+		} else {
+			updMap["password"] = hash
+		}
+	}
+	if len(updMap) == 0 {
+		return
+	}
+	err := i.store.Update(user.ID, updMap)
+	if err != nil {
+		logger.WithError(err).Error("ошибка обновления данных входа по паролю")
+	}
+}
+
+// getChallengeUser пользователь, прошедший проверку пароля
+func (i impl) getChallengeUser(challenge string) (*dbmodels.AdminPanelUser, error) {
+	userID, err := twofactor.Instance.ParseChallenge(models.AuthAdminPanelUser, challenge)
+	if err != nil {
+		return nil, err
+	}
+	user, err := i.store.GetByID(userID)
+	if err != nil {
+		log.
+			WithField("user_id", userID).
+			WithError(err).
+			Error("ошибка поиска пользователя")
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("пользователь не найден")
+	}
+	if !user.IsActive {
+		return nil, errors.New("учетная запись деактивирована")
+	}
+	return user, nil
+}
+
+func getName(user dbmodels.AdminPanelUser) string {
+	return fmt.Sprintf("%s %s", user.FirstName, user.LastName)
+}
@@ -5,15 +5,18 @@ import (
 	log "github.com/sirupsen/logrus"
 	"hr-tools-backend/db"
 	adminpaneluserstore "hr-tools-backend/lib/admin-panel/store"
+	authsession "hr-tools-backend/lib/auth-session"
+	passwordpolicy "hr-tools-backend/lib/password-policy"
 	authhelpers "hr-tools-backend/lib/utils/auth-helpers"
 	initchecker "hr-tools-backend/lib/utils/init-checker"
+	"hr-tools-backend/models"
 	adminpanelapimodels "hr-tools-backend/models/api/admin-panel"
 	dbmodels "hr-tools-backend/models/db"
 )
 
 type Provider interface {
-	CreateUser(request adminpanelapimodels.User) error
-	UpdateUser(userID string, request adminpanelapimodels.UserUpdate) error
+	CreateUser(request adminpanelapimodels.User) (hMsg string, err error)
+	UpdateUser(userID string, request adminpanelapimodels.UserUpdate) (hMsg string, err error)
 	DeleteUser(userID string) error
 	GetUser(userID string) (adminpanelapimodels.UserView, error)
 	List() ([]adminpanelapimodels.UserView, error)
@@ -35,11 +38,18 @@ type impl struct {
 	store adminpaneluserstore.Provider
 }
 
-func (i impl) CreateUser(request adminpanelapimodels.User) error {
+func (i impl) CreateUser(request adminpanelapimodels.User) (hMsg string, err error) {
+	if hMsg = passwordpolicy.Instance.Validate(request.Password); hMsg != "" {
+		return hMsg, nil
+	}
+	passwordHash, err := authhelpers.HashPassword(request.Password)
+	if err != nil {
+		return "", err
+	}
 	rec := dbmodels.AdminPanelUser{
 		IsActive:    true,
 		Role:        request.Role,
-		Password:    authhelpers.GetMD5Hash(request.Password),
+		Password:    passwordHash,
 		FirstName:   request.FirstName,
 		LastName:    request.LastName,
 		Email:       request.Email,
@@ -47,16 +57,16 @@ func (i impl) CreateUser(request adminpanelapimodels.User) error {
 	}
 	userID, err := i.store.Create(rec)
 	if err != nil {
-		return err
+		return "", err
 	}
 	log.
 		WithField("user_id", userID).
 		WithField("email", rec.Email).
 		Info("Создан пользователь админки")
-	return nil
+	return "", nil
 }
 
-func (i impl) UpdateUser(userID string, request adminpanelapimodels.UserUpdate) error {
+func (i impl) UpdateUser(userID string, request adminpanelapimodels.UserUpdate) (hMsg string, err error) {
 	logger := log.WithField("user_id", userID)
 	updMap := map[string]interface{}{}
 	if request.Role != nil {
@@ -69,7 +79,16 @@ func (i impl) UpdateUser(userID string, request adminpanelapimodels.UserUpdate)
 		updMap["LastName"] = *request.LastName
 	}
 	if request.Password != nil {
-		updMap["Password"] = authhelpers.GetMD5Hash(*request.Password)
+		if hMsg = passwordpolicy.Instance.Validate(*request.Password); hMsg != "" {
+			return hMsg, nil
+		}
+		passwordHash, err := authhelpers.HashPassword(*request.Password)
+		if err != nil {
+			return "", err
+		}
+		updMap["Password"] = passwordHash
+		updMap["FailedLoginAttempts"] = 0
+		updMap["LockedUntil"] = nil
 	}
 	if request.Email != nil {
 		updMap["Email"] = *request.Email
@@ -80,12 +99,17 @@ func (i impl) UpdateUser(userID string, request adminpanelapimodels.UserUpdate)
 	if request.IsActive != nil {
 		updMap["IsActive"] = *request.IsActive
 	}
-	err := i.store.Update(userID, updMap)
+	err = i.store.Update(userID, updMap)
 	if err != nil {
-		return err
+		return "", err
 	}
 	logger.Info("Обновлен пользователь админки")
-	return nil
+	if request.Password != nil {
+		revokeSessions(userID, "смена пароля")
+	} else if request.IsActive != nil && !*request.IsActive {
+		revokeSessions(userID, "деактивация пользователя")
+	}
+	return "", nil
 }
 
 func (i impl) DeleteUser(userID string) error {
@@ -95,6 +119,7 @@ func (i impl) DeleteUser(userID string) error {
 		return err
 	}
 	logger.Info("Удален пользователь админки")
+	revokeSessions(userID, "удаление пользователя")
 	return nil
 }
 
@@ -120,3 +145,14 @@ func (i impl) List() ([]adminpanelapimodels.UserView, error) {
 	}
 	return result, nil
 }
+
+// revokeSessions отзыв сессий пользователя, ошибка отзыва не отменяет выполненное изменение
+func revokeSessions(userID, reason string) {
+	err := authsession.Instance.RevokeAll(models.AuthAdminPanelUser, userID, reason)
+	if err != nil {
+		log.
+			WithField("user_id", userID).
+			WithError(err).
+			Error("ошибка отзыва сессий пользователя")
+	}
+}
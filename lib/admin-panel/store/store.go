@@ -3,6 +3,7 @@ package adminpaneluserstore
 import (
 	"github.com/pkg/errors"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	dbmodels "hr-tools-backend/models/db"
 )
 
@@ -13,6 +14,8 @@ type Provider interface {
 	Update(userID string, updMap map[string]interface{}) error
 	Delete(userID string) error
 	List() ([]dbmodels.AdminPanelUser, error)
+	// AddFailedLogin увеличение счетчика неверных паролей, возвращает новое значение
+	AddFailedLogin(userID string) (int, error)
 }
 
 func NewInstance(DB *gorm.DB) Provider {
@@ -123,3 +126,17 @@ func (i impl) List() ([]dbmodels.AdminPanelUser, error) {
 	}
 	return list, nil
 }
+
+func (i impl) AddFailedLogin(userID string) (int, error) {
+	rec := dbmodels.AdminPanelUser{}
+	err := i.db.
+		Model(&rec).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "failed_login_attempts"}}}).
+		Where("id = ?", userID).
+		UpdateColumn("failed_login_attempts", gorm.Expr("failed_login_attempts + 1")).
+		Error
+	if err != nil {
+		return 0, err
+	}
+	return rec.FailedLoginAttempts, nil
+}
@@ -0,0 +1,41 @@
+package ollamasearchhandler
+
+import (
+	"encoding/json"
+	"fmt"
+	dbmodels "hr-tools-backend/models/db"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func (i impl) ParseResume(spaceID, applicantID, text string) (data dbmodels.ResumeData, err error) {
+	err = i.checkConfig()
+	if err != nil {
+		return data, err
+	}
+	prompt := fmt.Sprintf(resumeParseTemplate, text)
+
+	now := time.Now()
+	// запрос к локальной модели
+	response, err := i.QueryOllama(prompt)
+	if err != nil {
+		return data, errors.Wrap(err, "ошибка извлечения данных из резюме")
+	}
+	i.getLogger().
+		WithField("space_id", spaceID).
+		WithField("applicant_id", applicantID).
+		WithField("answer", response).
+		WithField("answer_duration_sec", time.Now().Sub(now).Seconds()).
+		Info("Ответ AI на запрос ParseResume")
+
+	answer := extractAnswer(response)
+	if answer == "" {
+		return data, errors.New("ошибка извлечения json из ответа ИИ")
+	}
+	err = json.Unmarshal([]byte(answer), &data)
+	if err != nil {
+		return data, errors.Wrap(err, "ошибка декодирования json в структуру данных резюме")
+	}
+	return data, nil
+}
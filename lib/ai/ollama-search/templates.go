@@ -124,3 +124,35 @@ const step11ReportTemplate = `Ты — эксперт HR. Твоя задача
 Задача: Дай заключение по кандидату, обоснуй почему кандидат подходит или не подходит на данную позицию. 
 Ответ должен быть кратким, но сожержательным, не более 5 предложений.
 `
+
+const resumeParseTemplate = `Ты — эксперт HR, который всегда отвечает на русском языке.
+Извлеки из текста резюме данные кандидата для заполнения профиля.
+
+РЕЗЮМЕ: %s
+
+ФОРМАТ ОТВЕТА (строгое соблюдение):
+Ты должен вернуть **только** JSON в указанном ниже формате. Запрещено добавлять любые пояснения, комментарии, текст до или после JSON. Запрещено использовать markdown-разметку. Используй только стандартные двойные кавычки (").
+Если значение не указано в резюме — оставь пустую строку, 0 или пустой список. Не придумывай данные.
+
+Требуемый формат:
+{
+  "first_name": "", "last_name": "", "middle_name": "",
+  "phone": "", "email": "",
+  "birth_date": "ДД.ММ.ГГГГ",
+  "address": "город проживания", "citizenship": "",
+  "salary": 0,
+  "total_experience": 0,
+  "education": "",
+  "languages": [{"name": "Английский", "language_level": "b2"}],
+  "employments": [],
+  "schedules": [],
+  "driver_license_types": []
+}
+
+ВАЖНО:
+- total_experience — общий опыт работы в месяцах (число).
+- education — одно из: secondary, special_secondary, unfinished_higher, higher, bachelor, master, candidate, doctor.
+- language_level — одно из: a1, a2, b1, b2, c1, c2, l1 (родной язык).
+- employments — значения из списка: full, partial, temporary, internship, volunteer, probation.
+- schedules — значения из списка: fullDay, partTime, shift, flexible, flyInFlyOut.
+- driver_license_types — значения из списка: A, B, C, D, E, BE, CE, DE, TM, TB.`
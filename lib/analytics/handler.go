@@ -9,8 +9,8 @@ import (
 )
 
 type Provider interface {
-	Source(spaceID string, filter applicantapimodels.ApplicantFilter) (applicantapimodels.ApplicantSourceData, error)
-	SourceExportToXls(spaceID string, filter applicantapimodels.ApplicantFilter) (*bytes.Buffer, error)
+	Source(spaceID, userID string, filter applicantapimodels.ApplicantFilter) (applicantapimodels.ApplicantSourceData, error)
+	SourceExportToXls(spaceID, userID string, filter applicantapimodels.ApplicantFilter) (*bytes.Buffer, error)
 }
 
 var Instance Provider
@@ -29,12 +29,12 @@ type impl struct {
 	applicantProvider applicant.Provider
 }
 
-func (i impl) Source(spaceID string, filter applicantapimodels.ApplicantFilter) (applicantapimodels.ApplicantSourceData, error) {
-	return i.applicantProvider.ListOfSource(spaceID, filter)
+func (i impl) Source(spaceID, userID string, filter applicantapimodels.ApplicantFilter) (applicantapimodels.ApplicantSourceData, error) {
+	return i.applicantProvider.ListOfSource(spaceID, userID, filter)
 }
 
-func (i impl) SourceExportToXls(spaceID string, filter applicantapimodels.ApplicantFilter) (*bytes.Buffer, error) {
-	data, err := i.applicantProvider.ListOfSource(spaceID, filter)
+func (i impl) SourceExportToXls(spaceID, userID string, filter applicantapimodels.ApplicantFilter) (*bytes.Buffer, error) {
+	data, err := i.applicantProvider.ListOfSource(spaceID, userID, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,344 @@
+package apikey
+
+import (
+	"cmp"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hr-tools-backend/db"
+	apikeystore "hr-tools-backend/lib/api-key/store"
+	auditlog "hr-tools-backend/lib/audit-log"
+	"hr-tools-backend/lib/rbac"
+	spaceusersstore "hr-tools-backend/lib/space/users/store"
+	initchecker "hr-tools-backend/lib/utils/init-checker"
+	"hr-tools-backend/models"
+	apikeyapimodels "hr-tools-backend/models/api/api-key"
+	dbmodels "hr-tools-backend/models/db"
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	keyPrefix    = "hrt_"
+	keyBytes     = 24
+	prefixLen    = 12
+	serviceName  = "(API)"
+	lastUsedStep = time.Minute // дата использования обновляется не чаще, чтобы не писать в базу на каждый запрос
+)
+
+type Provider interface {
+	// Create создание ключа, роль и области доступа ключа не должны превышать разрешений автора
+	Create(spaceID, userID string, userRole models.UserRole, data apikeyapimodels.ApiKeyData) (result apikeyapimodels.ApiKeySecret, hMsg string, err error)
+	Update(spaceID, id, userID string, userRole models.UserRole, data apikeyapimodels.ApiKeyData) (hMsg string, err error)
+	Get(spaceID, id string) (*apikeyapimodels.ApiKeyView, error)
+	List(spaceID string) ([]apikeyapimodels.ApiKeyView, error)
+	// Rotate выпуск нового ключа взамен текущего, прежний ключ перестает действовать
+	Rotate(spaceID, id, userID string, userRole models.UserRole) (result apikeyapimodels.ApiKeySecret, hMsg string, err error)
+	// Revoke отзыв ключа, служебная учетная запись деактивируется
+	Revoke(spaceID, id, userID string) (hMsg string, err error)
+	// GetScopes области доступа, которые пользователь может выдать ключу с указанной ролью
+	GetScopes(userID string, userRole models.UserRole, role models.UserRole) dbmodels.ApiKeyScopes
+	// Authenticate проверка ключа из запроса, для недействительного ключа возвращается nil
+	Authenticate(key, ip string) (*dbmodels.ApiKey, error)
+}
+
+var Instance Provider
+
+func NewHandler() {
+	instance := impl{
+		store: apikeystore.NewInstance(db.DB),
+	}
+	initchecker.CheckInit(
+		"store", instance.store,
+	)
+	Instance = instance
+}
+
+type impl struct {
+	store apikeystore.Provider
+}
+
+func (i impl) getLogger(spaceID, id string) *log.Entry {
+	logger := log.WithField("space_id", spaceID)
+	if id != "" {
+		logger = logger.WithField("api_key_id", id)
+	}
+	return logger
+}
+
+func (i impl) Create(spaceID, userID string, userRole models.UserRole, data apikeyapimodels.ApiKeyData) (result apikeyapimodels.ApiKeySecret, hMsg string, err error) {
+	role := data.Role
+	hMsg = checkAccess(role, data.Scopes, rbac.Instance.GetPermissions(role), rbac.Instance.GetUserPermissions(userID, userRole))
+	if hMsg != "" {
+		return result, hMsg, nil
+	}
+	key, err := generateKey()
+	if err != nil {
+		return result, "", err
+	}
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		serviceUser := dbmodels.SpaceUser{
+			FirstName: data.Name,
+			LastName:  serviceName,
+			IsActive:  true,
+			SpaceID:   spaceID,
+			Role:      role,
+			Status:    models.SpaceWorkingStatus,
+			IsService: true,
+		}
+		serviceUserID, err := spaceusersstore.NewInstance(tx).Create(serviceUser)
+		if err != nil {
+			return errors.Wrap(err, "ошибка создания служебной учетной записи")
+		}
+		rec := dbmodels.ApiKey{
+			BaseSpaceModel: dbmodels.BaseSpaceModel{SpaceID: spaceID},
+			Name:           data.Name,
+			Prefix:         key[:prefixLen],
+			KeyHash:        getKeyHash(key),
+			Role:           role,
+			Scopes:         data.Scopes,
+			ExpiresAt:      data.ExpiresAt,
+			ServiceUserID:  serviceUserID,
+			AuthorID:       userID,
+		}
+		result.ID, err = apikeystore.NewInstance(tx).Create(rec)
+		if err != nil {
+			return errors.Wrap(err, "ошибка создания API ключа")
+		}
+		return nil
+	})
+	if err != nil {
+		return result, "", err
+	}
+	result.Key = key
+	i.getLogger(spaceID, result.ID).WithField("user_id", userID).Info("создан API ключ")
+	i.audit(spaceID, result.ID, userID, models.AuditActionCreate, "Создан API ключ", nil)
+	return result, "", nil
+}
+
+func (i impl) Update(spaceID, id, userID string, userRole models.UserRole, data apikeyapimodels.ApiKeyData) (hMsg string, err error) {
+	rec, err := i.store.GetByID(spaceID, id)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения API ключа")
+	}
+	if rec == nil {
+		return "API ключ не найден", nil
+	}
+	if rec.RevokedAt != nil {
+		return "API ключ отозван", nil
+	}
+	role := data.Role
+	hMsg = checkAccess(role, data.Scopes, rbac.Instance.GetPermissions(role), rbac.Instance.GetUserPermissions(userID, userRole))
+	if hMsg != "" {
+		return hMsg, nil
+	}
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		updMap := map[string]interface{}{
+			"name":       data.Name,
+			"role":       role,
+			"scopes":     data.Scopes,
+			"expires_at": data.ExpiresAt,
+		}
+		err := apikeystore.NewInstance(tx).Update(spaceID, id, updMap)
+		if err != nil {
+			return errors.Wrap(err, "ошибка изменения API ключа")
+		}
+		userMap := map[string]interface{}{
+			"first_name": data.Name,
+			"role":       role,
+		}
+		err = spaceusersstore.NewInstance(tx).Update(rec.ServiceUserID, userMap)
+		if err != nil {
+			return errors.Wrap(err, "ошибка изменения служебной учетной записи")
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	i.getLogger(spaceID, id).Info("изменен API ключ")
+	i.audit(spaceID, id, userID, models.AuditActionUpdate, "Изменен API ключ", rec)
+	return "", nil
+}
+
+func (i impl) Get(spaceID, id string) (*apikeyapimodels.ApiKeyView, error) {
+	rec, err := i.store.GetByID(spaceID, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения API ключа")
+	}
+	if rec == nil {
+		return nil, nil
+	}
+	result := apikeyapimodels.ApiKeyConvert(*rec)
+	return &result, nil
+}
+
+func (i impl) List(spaceID string) ([]apikeyapimodels.ApiKeyView, error) {
+	list, err := i.store.List(spaceID)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения списка API ключей")
+	}
+	result := make([]apikeyapimodels.ApiKeyView, 0, len(list))
+	for _, rec := range list {
+		result = append(result, apikeyapimodels.ApiKeyConvert(rec))
+	}
+	return result, nil
+}
+
+func (i impl) Rotate(spaceID, id, userID string, userRole models.UserRole) (result apikeyapimodels.ApiKeySecret, hMsg string, err error) {
+	rec, err := i.store.GetByID(spaceID, id)
+	if err != nil {
+		return result, "", errors.Wrap(err, "ошибка получения API ключа")
+	}
+	if rec == nil {
+		return result, "API ключ не найден", nil
+	}
+	if rec.RevokedAt != nil {
+		return result, "API ключ отозван", nil
+	}
+	// новый ключ получает пользователь, выполнивший ротацию
+	hMsg = checkAccess(rec.Role, rec.Scopes, rbac.Instance.GetPermissions(rec.Role), rbac.Instance.GetUserPermissions(userID, userRole))
+	if hMsg != "" {
+		return result, hMsg, nil
+	}
+	key, err := generateKey()
+	if err != nil {
+		return result, "", err
+	}
+	updMap := map[string]interface{}{
+		"prefix":     key[:prefixLen],
+		"key_hash":   getKeyHash(key),
+		"rotated_at": time.Now(),
+	}
+	err = i.store.Update(spaceID, id, updMap)
+	if err != nil {
+		return result, "", errors.Wrap(err, "ошибка ротации API ключа")
+	}
+	i.getLogger(spaceID, id).Info("выполнена ротация API ключа")
+	i.audit(spaceID, id, userID, models.AuditActionUpdate, "Выполнена ротация API ключа", rec)
+	return apikeyapimodels.ApiKeySecret{ID: id, Key: key}, "", nil
+}
+
+func (i impl) Revoke(spaceID, id, userID string) (hMsg string, err error) {
+	rec, err := i.store.GetByID(spaceID, id)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения API ключа")
+	}
+	if rec == nil {
+		return "API ключ не найден", nil
+	}
+	if rec.RevokedAt != nil {
+		return "", nil
+	}
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		err := apikeystore.NewInstance(tx).Update(spaceID, id, map[string]interface{}{"revoked_at": time.Now()})
+		if err != nil {
+			return errors.Wrap(err, "ошибка отзыва API ключа")
+		}
+		// учетная запись сохраняется для истории действий, выполненных по ключу
+		err = spaceusersstore.NewInstance(tx).Update(rec.ServiceUserID, map[string]interface{}{"is_active": false})
+		if err != nil {
+			return errors.Wrap(err, "ошибка деактивации служебной учетной записи")
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	i.getLogger(spaceID, id).Info("API ключ отозван")
+	i.audit(spaceID, id, userID, models.AuditActionUpdate, "API ключ отозван", rec)
+	return "", nil
+}
+
+// audit запись действия с ключом в журнал аудита, before - ключ до изменения (nil при создании)
+func (i impl) audit(spaceID, id, userID string, action models.AuditAction, description string, before *dbmodels.ApiKey) {
+	after, err := i.Get(spaceID, id)
+	if err != nil || after == nil {
+		i.getLogger(spaceID, id).WithError(err).Warn("ошибка получения API ключа для журнала аудита")
+		return
+	}
+	var beforeView *apikeyapimodels.ApiKeyView
+	if before != nil {
+		view := apikeyapimodels.ApiKeyConvert(*before)
+		beforeView = &view
+	}
+	auditlog.Instance.SaveChanges(spaceID, userID, action, models.AuditEntityApiKey, id, description, beforeView, after)
+}
+
+func (i impl) GetScopes(userID string, userRole models.UserRole, role models.UserRole) dbmodels.ApiKeyScopes {
+	result := dbmodels.ApiKeyScopes{}
+	userPermissions := rbac.Instance.GetUserPermissions(userID, userRole)
+	for module, permissions := range rbac.Instance.GetPermissions(role) {
+		for _, permission := range permissions {
+			if slices.Contains(userPermissions[module], permission) {
+				result = append(result, dbmodels.ApiKeyScope{Module: module, Permission: permission})
+			}
+		}
+	}
+	slices.SortFunc(result, func(a, b dbmodels.ApiKeyScope) int {
+		if a.Module != b.Module {
+			return cmp.Compare(a.Module, b.Module)
+		}
+		return cmp.Compare(a.Permission, b.Permission)
+	})
+	return result
+}
+
+func (i impl) Authenticate(key, ip string) (*dbmodels.ApiKey, error) {
+	rec, err := i.store.GetByHash(getKeyHash(key))
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения API ключа")
+	}
+	now := time.Now()
+	if rec == nil || !rec.IsValid(now) || rec.ServiceUser == nil || !rec.ServiceUser.IsActive {
+		return nil, nil
+	}
+	if rec.LastUsedAt == nil || now.Sub(*rec.LastUsedAt) > lastUsedStep || rec.LastUsedIP != ip {
+		updMap := map[string]interface{}{
+			"last_used_at": now,
+			"last_used_ip": ip,
+		}
+		err = i.store.Update(rec.SpaceID, rec.ID, updMap)
+		if err != nil {
+			i.getLogger(rec.SpaceID, rec.ID).WithError(err).Error("ошибка сохранения даты использования API ключа")
+		}
+	}
+	return rec, nil
+}
+
+// checkAccess роль ключа не должна давать разрешений, которых нет у автора, области доступа ключа должны быть доступны его роли
+// (и, следовательно, автору)
+func checkAccess(role models.UserRole, scopes dbmodels.ApiKeyScopes, rolePermissions, authorPermissions map[models.Module][]models.Permission) string {
+	for module, permissions := range rolePermissions {
+		for _, permission := range permissions {
+			if !slices.Contains(authorPermissions[module], permission) {
+				return fmt.Sprintf("роль %v дает разрешение %v/%v, которого нет у автора ключа", role, module, permission)
+			}
+		}
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(rolePermissions[scope.Module], scope.Permission) {
+			return fmt.Sprintf("область доступа %v/%v недоступна для роли %v", scope.Module, scope.Permission, role)
+		}
+	}
+	return ""
+}
+
+func generateKey() (string, error) {
+	buf := make([]byte, keyBytes)
+	_, err := rand.Read(buf)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка генерации API ключа")
+	}
+	return keyPrefix + hex.EncodeToString(buf), nil
+}
+
+func getKeyHash(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,39 @@
+package apikey
+
+import (
+	"hr-tools-backend/models"
+	apikeyapimodels "hr-tools-backend/models/api/api-key"
+	dbmodels "hr-tools-backend/models/db"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckAccess(t *testing.T) {
+	hrPermissions := map[models.Module][]models.Permission{
+		models.ApplicantModule: {models.ViewPermission, models.EditPermission},
+	}
+	adminPermissions := map[models.Module][]models.Permission{
+		models.ApplicantModule: {models.ViewPermission, models.EditPermission},
+		models.SecurityModule:  {models.ManagePermission},
+	}
+	scopes := dbmodels.ApiKeyScopes{{Module: models.ApplicantModule, Permission: models.ViewPermission}}
+
+	// ключ с ролью автора
+	require.Empty(t, checkAccess(models.HRRole, scopes, hrPermissions, hrPermissions))
+	// роль ключа дает разрешения, которых нет у автора
+	require.NotEmpty(t, checkAccess(models.AdminRole, scopes, adminPermissions, hrPermissions))
+	// область доступа не входит в разрешения роли ключа
+	adminScopes := dbmodels.ApiKeyScopes{{Module: models.SecurityModule, Permission: models.ManagePermission}}
+	require.NotEmpty(t, checkAccess(models.HRRole, adminScopes, hrPermissions, adminPermissions))
+}
+
+func TestApiKeyRoleRequired(t *testing.T) {
+	data := apikeyapimodels.ApiKeyData{
+		Name:   "интеграция",
+		Scopes: dbmodels.ApiKeyScopes{{Module: models.ApplicantModule, Permission: models.ViewPermission}},
+	}
+	require.Error(t, data.Validate())
+	data.Role = models.SpecialistRole
+	require.NoError(t, data.Validate())
+}
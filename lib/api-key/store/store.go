@@ -0,0 +1,97 @@
+package apikeystore
+
+import (
+	dbmodels "hr-tools-backend/models/db"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Provider interface {
+	Create(rec dbmodels.ApiKey) (id string, err error)
+	Update(spaceID, id string, updMap map[string]interface{}) error
+	GetByID(spaceID, id string) (*dbmodels.ApiKey, error)
+	GetByHash(keyHash string) (*dbmodels.ApiKey, error)
+	List(spaceID string) ([]dbmodels.ApiKey, error)
+}
+
+func NewInstance(DB *gorm.DB) Provider {
+	return &impl{
+		db: DB,
+	}
+}
+
+type impl struct {
+	db *gorm.DB
+}
+
+func (i impl) Create(rec dbmodels.ApiKey) (id string, err error) {
+	err = i.db.
+		Omit(clause.Associations).
+		Save(&rec).
+		Error
+	if err != nil {
+		return "", err
+	}
+	return rec.ID, nil
+}
+
+func (i impl) Update(spaceID, id string, updMap map[string]interface{}) error {
+	if len(updMap) == 0 {
+		return nil
+	}
+	return i.db.
+		Model(&dbmodels.ApiKey{}).
+		Where("space_id = ?", spaceID).
+		Where("id = ?", id).
+		Updates(updMap).
+		Error
+}
+
+func (i impl) GetByID(spaceID, id string) (*dbmodels.ApiKey, error) {
+	rec := dbmodels.ApiKey{}
+	err := i.db.
+		Preload("Author").
+		Where("space_id = ?", spaceID).
+		Where("id = ?", id).
+		First(&rec).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (i impl) GetByHash(keyHash string) (*dbmodels.ApiKey, error) {
+	rec := dbmodels.ApiKey{}
+	err := i.db.
+		Preload("ServiceUser").
+		Where("key_hash = ?", keyHash).
+		First(&rec).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (i impl) List(spaceID string) ([]dbmodels.ApiKey, error) {
+	list := []dbmodels.ApiKey{}
+	err := i.db.
+		Preload("Author").
+		Where("space_id = ?", spaceID).
+		Order("created_at desc").
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
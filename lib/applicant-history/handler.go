@@ -4,6 +4,7 @@ import (
 	"hr-tools-backend/db"
 	applicanthistorystore "hr-tools-backend/lib/applicant-history/store"
 	applicantstore "hr-tools-backend/lib/applicant/store"
+	automationevent "hr-tools-backend/lib/automation/event"
 	pushhandler "hr-tools-backend/lib/space/push/handler"
 	spaceusersstore "hr-tools-backend/lib/space/users/store"
 	initchecker "hr-tools-backend/lib/utils/init-checker"
@@ -19,6 +20,8 @@ import (
 
 type Provider interface {
 	List(spaceID, applicantID string, filter applicantapimodels.ApplicantHistoryFilter) ([]applicantapimodels.ApplicantHistoryView, int64, error)
+	// ListByCandidate история действий по всем вакансиям кандидата (человека), видимым в области видимости scope
+	ListByCandidate(spaceID, candidateID string, scope *dbmodels.DataScope, filter applicantapimodels.ApplicantHistoryFilter) ([]applicantapimodels.ApplicantHistoryView, int64, error)
 	Save(spaceID, applicantID, vacancyID, userID string, action dbmodels.ActionType, changes dbmodels.ApplicantChanges)
 	SaveWithUser(spaceID, applicantID, vacancyID, userID, userName string, action dbmodels.ActionType, changes dbmodels.ApplicantChanges)
 	SaveNote(spaceID, applicantID, userID string, action applicantapimodels.ApplicantNote) error
@@ -83,6 +86,30 @@ func (i impl) List(spaceID, applicantID string, filter applicantapimodels.Applic
 	return result, rowCount, nil
 }
 
+func (i impl) ListByCandidate(spaceID, candidateID string, scope *dbmodels.DataScope, filter applicantapimodels.ApplicantHistoryFilter) ([]applicantapimodels.ApplicantHistoryView, int64, error) {
+	rowCount, err := i.store.ListCountByCandidate(spaceID, candidateID, scope, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	page, limit := filter.GetPage()
+	offset := (page - 1) * limit
+	if int64(offset) > rowCount {
+		return []applicantapimodels.ApplicantHistoryView{}, rowCount, nil
+	}
+
+	list, err := i.store.ListByCandidate(spaceID, candidateID, scope, filter)
+	if err != nil {
+		log.WithError(err).Error("ошибка получения списка действий по кандидату")
+		return nil, 0, errors.New("ошибка получения списка действий по кандидату")
+	}
+	result := make([]applicantapimodels.ApplicantHistoryView, 0, len(list))
+	for _, rec := range list {
+		result = append(result, applicantapimodels.Convert(rec))
+	}
+	return result, rowCount, nil
+}
+
 func (i impl) Save(spaceID, applicantID, vacancyID, userID string, action dbmodels.ActionType, changes dbmodels.ApplicantChanges) {
 	logger := log.WithField("space_id", spaceID).
 		WithField("applicant_id", applicantID).
@@ -116,7 +143,9 @@ func (i impl) Save(spaceID, applicantID, vacancyID, userID string, action dbmode
 	_, err := i.store.Create(rec)
 	if err != nil {
 		logger.WithError(err).Error("ошибка сохранения истории действий по кандидату")
+		return
 	}
+	publishEvent(spaceID, applicantID, vacancyID, action)
 }
 
 func (i impl) SaveWithUser(spaceID, applicantID, vacancyID, userID, userName string, action dbmodels.ActionType, changes dbmodels.ApplicantChanges) {
@@ -141,7 +170,9 @@ func (i impl) SaveWithUser(spaceID, applicantID, vacancyID, userID, userName str
 			WithField("action", action).
 			WithField("description", changes.Description).
 			WithError(err).Error("ошибка сохранения истории действий по кандидату")
+		return
 	}
+	publishEvent(spaceID, applicantID, vacancyID, action)
 }
 
 func (i impl) SaveNote(spaceID, applicantID, userID string, note applicantapimodels.ApplicantNote) error {
@@ -191,7 +222,7 @@ func (i impl) SaveNote(spaceID, applicantID, userID string, note applicantapimod
 		if vacancyRec == nil {
 			return
 		}
-		notification := models.GetPushApplicantNote(vacancyRec.VacancyName, rec.GetFIO(), user.GetFullName())
+		notification := models.GetPushApplicantNote(vacancyRec.VacancyName, rec.GetFIO(), user.GetFullName()).WithEntity(rec.ID)
 		i.sendNotification(*vacancyRec, notification)
 	}(applicantRec.Applicant, userID)
 	return nil
@@ -234,3 +265,17 @@ func (i *impl) getUser(userID string, logger *log.Entry) *dbmodels.SpaceUser {
 	}
 	return userRec
 }
+
+// publishEvent передача события в правила автоматизации
+func publishEvent(spaceID, applicantID, vacancyID string, action dbmodels.ActionType) {
+	trigger, ok := automationevent.GetHistoryTrigger(action)
+	if !ok {
+		return
+	}
+	automationevent.Publish(automationevent.Event{
+		Trigger:     trigger,
+		SpaceID:     spaceID,
+		ApplicantID: applicantID,
+		VacancyID:   vacancyID,
+	})
+}
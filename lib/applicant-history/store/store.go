@@ -13,6 +13,11 @@ type Provider interface {
 	Create(rec dbmodels.ApplicantHistory) (id string, err error)
 	ListCount(spaceID, userID string, filter applicantapimodels.ApplicantHistoryFilter) (count int64, err error)
 	List(spaceID, applicantID string, filter applicantapimodels.ApplicantHistoryFilter) (list []dbmodels.ApplicantHistory, err error)
+	ListCountByCandidate(spaceID, candidateID string, scope *dbmodels.DataScope, filter applicantapimodels.ApplicantHistoryFilter) (count int64, err error)
+	ListByCandidate(spaceID, candidateID string, scope *dbmodels.DataScope, filter applicantapimodels.ApplicantHistoryFilter) (list []dbmodels.ApplicantHistory, err error)
+	ListAll(spaceID string, applicantIDs []string) (list []dbmodels.ApplicantHistory, err error)
+	// ClearChanges удаление описания и списка изменений из истории кандидата (обезличивание)
+	ClearChanges(spaceID, applicantID string) error
 }
 
 func NewInstance(DB *gorm.DB) Provider {
@@ -75,6 +80,84 @@ func (i impl) List(spaceID, applicantID string, filter applicantapimodels.Applic
 	return list, nil
 }
 
+func (i impl) ListCountByCandidate(spaceID, candidateID string, scope *dbmodels.DataScope, filter applicantapimodels.ApplicantHistoryFilter) (count int64, err error) {
+	var rowCount int64
+	tx := i.db.
+		Model(dbmodels.ApplicantHistory{}).
+		Where("space_id = ?", spaceID).
+		Where("applicant_id in (?)", i.candidateApplicants(candidateID, scope))
+	if filter.CommentsOnly {
+		tx = tx.Where("action_type = ?", dbmodels.HistoryTypeComment)
+	}
+	err = tx.Count(&rowCount).Error
+	if err != nil {
+		log.WithError(err).Error("ошибка получения общего количества действий по кандидату")
+		return 0, errors.New("ошибка получения общего количества действий по кандидату")
+	}
+	return rowCount, nil
+}
+
+// ListByCandidate общая история действий по всем вакансиям кандидата (человека)
+func (i impl) ListByCandidate(spaceID, candidateID string, scope *dbmodels.DataScope, filter applicantapimodels.ApplicantHistoryFilter) (list []dbmodels.ApplicantHistory, err error) {
+	list = []dbmodels.ApplicantHistory{}
+	tx := i.db.
+		Model(dbmodels.ApplicantHistory{}).
+		Where("space_id = ?", spaceID).
+		Where("applicant_id in (?)", i.candidateApplicants(candidateID, scope))
+	if filter.CommentsOnly {
+		tx = tx.Where("action_type = ?", dbmodels.HistoryTypeComment)
+	}
+	page, limit := filter.GetPage()
+	i.setPage(tx, page, limit)
+	tx.Order("created_at")
+	err = tx.Preload("Vacancy").Find(&list).Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (i impl) ListAll(spaceID string, applicantIDs []string) (list []dbmodels.ApplicantHistory, err error) {
+	list = []dbmodels.ApplicantHistory{}
+	err = i.db.
+		Model(dbmodels.ApplicantHistory{}).
+		Where("space_id = ?", spaceID).
+		Where("applicant_id in (?)", applicantIDs).
+		Order("created_at").
+		Preload("Vacancy").
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (i impl) ClearChanges(spaceID, applicantID string) error {
+	err := i.db.
+		Model(&dbmodels.ApplicantHistory{}).
+		Where("space_id = ?", spaceID).
+		Where("applicant_id = ?", applicantID).
+		Update("changes", dbmodels.ApplicantChanges{}).
+		Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// candidateApplicants участие кандидата в подборе, видимое пользователю
+func (i impl) candidateApplicants(candidateID string, scope *dbmodels.DataScope) *gorm.DB {
+	tx := i.db.
+		Model(dbmodels.Applicant{}).
+		Select("applicants.id").
+		Where("applicants.candidate_id = ?", candidateID)
+	if query, args := scope.ApplicantCondition("applicants"); query != "" {
+		tx.Where(query, args)
+	}
+	return tx
+}
+
 func (i impl) setPage(tx *gorm.DB, page, limit int) {
 	offset := (page - 1) * limit
 	tx.Limit(limit).Offset(offset)
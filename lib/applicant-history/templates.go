@@ -145,12 +145,169 @@ func GetArchiveChange(reason string) dbmodels.ApplicantChanges {
 	}
 }
 
+func GetCareerSiteNegotiation(coverLetter, resumeName string) dbmodels.ApplicantChanges {
+	result := dbmodels.ApplicantChanges{
+		Description: "Получен отклик с карьерного сайта",
+		Data:        make([]dbmodels.ApplicantChange, 0, 2),
+	}
+	if coverLetter != "" {
+		result.Data = append(result.Data, dbmodels.ApplicantChange{
+			Field:    "Сопроводительное письмо",
+			OldValue: "",
+			NewValue: coverLetter,
+		})
+	}
+	if resumeName != "" {
+		result.Data = append(result.Data, dbmodels.ApplicantChange{
+			Field:    "Резюме",
+			OldValue: "",
+			NewValue: resumeName,
+		})
+	}
+	return result
+}
+
+func GetConsiderChange(vacancyName string) dbmodels.ApplicantChanges {
+	return dbmodels.ApplicantChanges{
+		Description: fmt.Sprintf("Кандидат рассматривается на вакансию %v", vacancyName),
+	}
+}
+
 func GetMailSentChange(title string) dbmodels.ApplicantChanges {
 	return dbmodels.ApplicantChanges{
 		Description: fmt.Sprintf("Отправлен емайл с темой: %v", title),
 	}
 }
 
+func GetEmailNegotiation(subject, text string, attachments []string) dbmodels.ApplicantChanges {
+	result := GetMailReceivedChange(subject, text, attachments)
+	result.Description = fmt.Sprintf("Получен отклик по электронной почте с темой: %v", subject)
+	return result
+}
+
+func GetMailReceivedChange(subject, text string, attachments []string) dbmodels.ApplicantChanges {
+	result := dbmodels.ApplicantChanges{
+		Description: fmt.Sprintf("Получен емайл с темой: %v", subject),
+		Data:        make([]dbmodels.ApplicantChange, 0, len(attachments)+1),
+	}
+	if text != "" {
+		result.Data = append(result.Data, dbmodels.ApplicantChange{
+			Field:    "Текст письма",
+			OldValue: "",
+			NewValue: text,
+		})
+	}
+	for _, name := range attachments {
+		result.Data = append(result.Data, dbmodels.ApplicantChange{
+			Field:    "Вложение",
+			OldValue: "",
+			NewValue: name,
+		})
+	}
+	return result
+}
+
+func GetInterviewChange(description, startAt, location string) dbmodels.ApplicantChanges {
+	result := dbmodels.ApplicantChanges{
+		Description: description,
+		Data: []dbmodels.ApplicantChange{
+			{
+				Field:    "Время интервью",
+				OldValue: "",
+				NewValue: startAt,
+			},
+		},
+	}
+	if location != "" {
+		result.Data = append(result.Data, dbmodels.ApplicantChange{
+			Field:    "Место проведения",
+			OldValue: "",
+			NewValue: location,
+		})
+	}
+	return result
+}
+
+func GetScorecardChange(stageName string, rec dbmodels.Scorecard) dbmodels.ApplicantChanges {
+	result := dbmodels.ApplicantChanges{
+		Description: fmt.Sprintf("Заполнена оценочная карта, этап: %v", stageName),
+		Data:        make([]dbmodels.ApplicantChange, 0, len(rec.Ratings)+3),
+	}
+	for _, rating := range rec.Ratings {
+		if rating.Rating == 0 {
+			continue
+		}
+		result.Data = append(result.Data, dbmodels.ApplicantChange{
+			Field:    rating.Name,
+			OldValue: "",
+			NewValue: rating.Rating,
+		})
+	}
+	result.Data = append(result.Data,
+		dbmodels.ApplicantChange{
+			Field:    "Средняя оценка",
+			OldValue: "",
+			NewValue: fmt.Sprintf("%.2f", rec.Ratings.Average()),
+		},
+		dbmodels.ApplicantChange{
+			Field:    "Рекомендация",
+			OldValue: "",
+			NewValue: dbmodels.RecommendationNames[rec.Recommendation],
+		})
+	if rec.Comment != "" {
+		result.Data = append(result.Data, dbmodels.ApplicantChange{
+			Field:    "Комментарий",
+			OldValue: "",
+			NewValue: rec.Comment,
+		})
+	}
+	return result
+}
+
+func GetOfferChange(description string, rec dbmodels.Offer) dbmodels.ApplicantChanges {
+	return dbmodels.ApplicantChanges{
+		Description: description,
+		Data: []dbmodels.ApplicantChange{
+			{
+				Field:    "Версия",
+				OldValue: "",
+				NewValue: rec.Version,
+			},
+			{
+				Field:    "Заработная плата",
+				OldValue: "",
+				NewValue: rec.Salary,
+			},
+			{
+				Field:    "Дата выхода",
+				OldValue: "",
+				NewValue: rec.StartDate.Format("02.01.2006"),
+			},
+			{
+				Field:    "Срок действия",
+				OldValue: "",
+				NewValue: rec.ExpiresAt.Format("02.01.2006"),
+			},
+		},
+	}
+}
+
+// GetCustomFieldChanges изменения дополнительных полей, значения - для отображения по коду поля
+func GetCustomFieldChanges(fields []dbmodels.CustomField, oldValues, newValues map[string]string) []dbmodels.ApplicantChange {
+	result := []dbmodels.ApplicantChange{}
+	for _, field := range fields {
+		if oldValues[field.Code] == newValues[field.Code] {
+			continue
+		}
+		result = append(result, dbmodels.ApplicantChange{
+			Field:    field.Name,
+			OldValue: oldValues[field.Code],
+			NewValue: newValues[field.Code],
+		})
+	}
+	return result
+}
+
 func getParamChanges(oldParams, newParams dbmodels.ApplicantParams) []dbmodels.ApplicantChange {
 	result := []dbmodels.ApplicantChange{}
 	rType := reflect.TypeOf(oldParams)
@@ -181,7 +338,7 @@ func getParamChanges(oldParams, newParams dbmodels.ApplicantParams) []dbmodels.A
 }
 
 var ignoreFields = map[string]bool{"base_space_model": true, "not_duplicates": true, "params": true, "vacancy": true,
-	"selection_stage": true, "duplicates": true, "space_id": true, "vacancy_id": true}
+	"selection_stage": true, "duplicates": true, "space_id": true, "vacancy_id": true, "custom_fields": true}
 
 func getValue(value interface{}) interface{} {
 	xType := fmt.Sprintf("%T", value)
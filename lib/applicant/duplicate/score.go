@@ -0,0 +1,203 @@
+package applicantduplicate
+
+import (
+	"fmt"
+	"hr-tools-backend/models"
+	dbmodels "hr-tools-backend/models/db"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// MinConfidence минимальная уверенность, начиная с которой кандидат предлагается как возможный дубликат
+const MinConfidence = 0.5
+
+// минимальная схожесть ФИО, при которой она учитывается в оценке
+const minNameSimilarity = 0.5
+
+const (
+	phoneWeight          = 0.35
+	emailWeight          = 0.35
+	nameWeight           = 0.35
+	birthDateWeight      = 0.15
+	birthDateConflict    = 0.3
+	contactConflictValue = 0.1
+)
+
+type Match struct {
+	Confidence    float64
+	DuplicateType models.DuplicateType
+	Reasons       []string
+}
+
+func (m Match) IsDuplicate() bool {
+	return m.Confidence >= MinConfidence
+}
+
+// Score оценка вероятности того, что rec и origin - один и тот же человек
+func Score(origin, rec dbmodels.Applicant) Match {
+	if origin.ExtApplicantID != "" && origin.ExtApplicantID == rec.ExtApplicantID {
+		return Match{
+			Confidence:    1,
+			DuplicateType: models.DuplicateTypeByAuthor,
+			Reasons:       []string{"совпадает автор резюме во внешней системе"},
+		}
+	}
+	if origin.ResumeID != "" && origin.ResumeID == rec.ResumeID {
+		return Match{
+			Confidence:    1,
+			DuplicateType: models.DuplicateTypeByResume,
+			Reasons:       []string{"совпадает резюме во внешней системе"},
+		}
+	}
+	result := Match{
+		DuplicateType: models.DuplicateTypeByName,
+		Reasons:       []string{},
+	}
+	score := 0.0
+
+	originPhone, recPhone := NormalizePhone(origin.Phone), NormalizePhone(rec.Phone)
+	if originPhone != "" && recPhone != "" {
+		if originPhone == recPhone {
+			score += phoneWeight
+			result.DuplicateType = models.DuplicateTypeByContacts
+			result.Reasons = append(result.Reasons, "совпадает телефон")
+		} else {
+			score -= contactConflictValue
+		}
+	}
+	originEmail, recEmail := NormalizeEmail(origin.Email), NormalizeEmail(rec.Email)
+	if originEmail != "" && recEmail != "" {
+		if originEmail == recEmail {
+			score += emailWeight
+			result.DuplicateType = models.DuplicateTypeByContacts
+			result.Reasons = append(result.Reasons, "совпадает email")
+		} else {
+			score -= contactConflictValue
+		}
+	}
+	nameSimilarity := NameSimilarity(origin, rec)
+	if nameSimilarity >= minNameSimilarity {
+		score += nameWeight * nameSimilarity
+		result.Reasons = append(result.Reasons, fmt.Sprintf("похожее ФИО (%v%%)", math.Round(nameSimilarity*100)))
+	}
+	if !origin.BirthDate.IsZero() && !rec.BirthDate.IsZero() {
+		if sameDate(origin, rec) {
+			score += birthDateWeight
+			result.Reasons = append(result.Reasons, "совпадает дата рождения")
+		} else {
+			score -= birthDateConflict
+		}
+	}
+	result.Confidence = math.Max(0, math.Min(1, math.Round(score*100)/100))
+	return result
+}
+
+func sameDate(origin, rec dbmodels.Applicant) bool {
+	y1, m1, d1 := origin.BirthDate.Date()
+	y2, m2, d2 := rec.BirthDate.Date()
+	return y1 == y2 && m1 == m2 && d1 == d2
+}
+
+// NameSimilarity схожесть фамилии и имени (отчество - если указано у обоих) по триграммам с учетом транслитерации
+func NameSimilarity(origin, rec dbmodels.Applicant) float64 {
+	originName := []string{origin.LastName, origin.FirstName}
+	recName := []string{rec.LastName, rec.FirstName}
+	if strings.TrimSpace(origin.MiddleName) != "" && strings.TrimSpace(rec.MiddleName) != "" {
+		originName = append(originName, origin.MiddleName)
+		recName = append(recName, rec.MiddleName)
+	}
+	return Similarity(strings.Join(originName, " "), strings.Join(recName, " "))
+}
+
+// Similarity схожесть строк по триграммам (аналог pg_trgm similarity) после транслитерации, порядок слов не учитывается
+func Similarity(a, b string) float64 {
+	aSet := trigrams(NormalizeName(a))
+	bSet := trigrams(NormalizeName(b))
+	if len(aSet) == 0 || len(bSet) == 0 {
+		return 0
+	}
+	common := 0
+	for trigram := range aSet {
+		if bSet[trigram] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(aSet)+len(bSet)-common)
+}
+
+func trigrams(value string) map[string]bool {
+	result := map[string]bool{}
+	for _, word := range strings.Fields(value) {
+		runes := []rune("  " + word + " ")
+		for k := 0; k+3 <= len(runes); k++ {
+			result[string(runes[k:k+3])] = true
+		}
+	}
+	return result
+}
+
+var cyrillicToLatin = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "i", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "h", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "sch", 'ъ': "", 'ы': "i", 'ь': "", 'э': "e", 'ю': "iu",
+	'я': "ia",
+}
+
+// варианты латинского написания, приводимые к одному виду
+var latinReplacer = strings.NewReplacer(
+	"kh", "h",
+	"shch", "sch",
+	"ph", "f",
+	"ck", "k",
+	"x", "ks",
+	"w", "v",
+	"q", "k",
+	"j", "i",
+	"y", "i",
+)
+
+// NormalizeName приведение ФИО к нижнему регистру латиницей, кириллица транслитерируется
+func NormalizeName(value string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(value) {
+		if latin, ok := cyrillicToLatin[r]; ok {
+			sb.WriteString(latin)
+			continue
+		}
+		if unicode.IsLetter(r) {
+			sb.WriteRune(r)
+			continue
+		}
+		sb.WriteRune(' ')
+	}
+	return strings.Join(strings.Fields(latinReplacer.Replace(sb.String())), " ")
+}
+
+// NormalizePhone приведение телефона к формату E.164, номера без кода страны считаются российскими
+func NormalizePhone(phone string) string {
+	digits := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, phone)
+	digits = strings.TrimPrefix(digits, "00")
+	switch {
+	case len(digits) < 10:
+		return ""
+	case len(digits) == 10:
+		digits = "7" + digits
+	case len(digits) == 11 && digits[0] == '8':
+		digits = "7" + digits[1:]
+	}
+	if len(digits) > 15 {
+		return ""
+	}
+	return "+" + digits
+}
+
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,92 @@
+package applicantduplicate
+
+import (
+	"hr-tools-backend/models"
+	dbmodels "hr-tools-backend/models/db"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	require.Equal(t, "+79771234567", NormalizePhone("+7 (977) 123-45-67"))
+	require.Equal(t, "+79771234567", NormalizePhone("89771234567"))
+	require.Equal(t, "+79771234567", NormalizePhone("977 123 45 67"))
+	require.Equal(t, "+375291234567", NormalizePhone("00375 29 123-45-67"))
+	require.Equal(t, "", NormalizePhone("123-45-67"))
+	require.Equal(t, "", NormalizePhone(""))
+}
+
+func TestNormalizeName(t *testing.T) {
+	require.Equal(t, "ivanov ivan", NormalizeName("Иванов  Иван"))
+	require.Equal(t, "ivanov ivan", NormalizeName("IVANOV Ivan"))
+	require.Equal(t, "iurii", NormalizeName("Юрий"))
+	require.Equal(t, "iurii", NormalizeName("Yurii"))
+	require.Equal(t, "aleksandr", NormalizeName("Alexandr"))
+	require.Equal(t, "hariton", NormalizeName("Khariton"))
+}
+
+func TestSimilarity(t *testing.T) {
+	require.Equal(t, 1.0, Similarity("Иванов Иван", "Ivanov Ivan"))
+	require.Equal(t, 1.0, Similarity("Иванов Иван", "Иван Иванов"))
+	require.Greater(t, Similarity("Иванов Иван", "Иваноф Иван"), 0.5)
+	require.Less(t, Similarity("Иванов Иван", "Петров Петр"), 0.1)
+	require.Equal(t, 0.0, Similarity("", "Петров Петр"))
+}
+
+func TestScore(t *testing.T) {
+	origin := dbmodels.Applicant{
+		LastName:  "Иванов",
+		FirstName: "Иван",
+		Phone:     "+7 (977) 123-45-67",
+		Email:     "ivanov@mail.ru",
+	}
+
+	t.Run("транслитерация и телефон в другом формате", func(t *testing.T) {
+		rec := dbmodels.Applicant{LastName: "Ivanov", FirstName: "Ivan", Phone: "89771234567"}
+		match := Score(origin, rec)
+		require.True(t, match.IsDuplicate())
+		require.Equal(t, models.DuplicateTypeByContacts, match.DuplicateType)
+		require.Equal(t, 0.7, match.Confidence)
+	})
+
+	t.Run("опечатка в ФИО и совпадение контактов", func(t *testing.T) {
+		rec := dbmodels.Applicant{LastName: "Иваноф", FirstName: "Иван", Phone: "9771234567", Email: "Ivanov@mail.ru"}
+		match := Score(origin, rec)
+		require.True(t, match.IsDuplicate())
+		require.Greater(t, match.Confidence, 0.9)
+	})
+
+	t.Run("общий телефон у разных людей", func(t *testing.T) {
+		rec := dbmodels.Applicant{LastName: "Петрова", FirstName: "Мария", Phone: "89771234567"}
+		require.False(t, Score(origin, rec).IsDuplicate())
+	})
+
+	t.Run("только совпадение ФИО", func(t *testing.T) {
+		rec := dbmodels.Applicant{LastName: "Иванов", FirstName: "Иван"}
+		require.False(t, Score(origin, rec).IsDuplicate())
+	})
+
+	t.Run("ФИО и дата рождения", func(t *testing.T) {
+		birthDate := time.Date(1990, 5, 1, 0, 0, 0, 0, time.UTC)
+		originRec := dbmodels.Applicant{LastName: "Иванов", FirstName: "Иван", BirthDate: birthDate}
+		rec := dbmodels.Applicant{LastName: "Ivanov", FirstName: "Ivan", BirthDate: birthDate}
+		match := Score(originRec, rec)
+		require.True(t, match.IsDuplicate())
+		require.Equal(t, models.DuplicateTypeByName, match.DuplicateType)
+
+		rec.BirthDate = birthDate.AddDate(1, 0, 0)
+		require.False(t, Score(originRec, rec).IsDuplicate())
+	})
+
+	t.Run("совпадение резюме и автора", func(t *testing.T) {
+		rec := dbmodels.Applicant{ResumeID: "r1"}
+		match := Score(dbmodels.Applicant{ResumeID: "r1"}, rec)
+		require.Equal(t, 1.0, match.Confidence)
+		require.Equal(t, models.DuplicateTypeByResume, match.DuplicateType)
+
+		match = Score(dbmodels.Applicant{ExtApplicantID: "a1"}, dbmodels.Applicant{ExtApplicantID: "a1"})
+		require.Equal(t, models.DuplicateTypeByAuthor, match.DuplicateType)
+	})
+}
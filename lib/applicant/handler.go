@@ -2,17 +2,27 @@ package applicant
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"hr-tools-backend/db"
 	applicanthistoryhandler "hr-tools-backend/lib/applicant-history"
+	applicantduplicate "hr-tools-backend/lib/applicant/duplicate"
 	applicantstore "hr-tools-backend/lib/applicant/store"
+	automationevent "hr-tools-backend/lib/automation/event"
+	candidatestore "hr-tools-backend/lib/candidate/store"
+	customfield "hr-tools-backend/lib/custom-field"
+	datascope "hr-tools-backend/lib/data-scope"
 	xlsexport "hr-tools-backend/lib/export/xls"
+	filestorage "hr-tools-backend/lib/file-storage"
+	filesdbstorage "hr-tools-backend/lib/file-storage/storage"
+	scorecardstore "hr-tools-backend/lib/scorecard/store"
 	pushhandler "hr-tools-backend/lib/space/push/handler"
 	spaceusersstore "hr-tools-backend/lib/space/users/store"
 	initchecker "hr-tools-backend/lib/utils/init-checker"
 	vacancyhandler "hr-tools-backend/lib/vacancy"
 	selectionstagestore "hr-tools-backend/lib/vacancy/selection-stage-store"
 	vacancystore "hr-tools-backend/lib/vacancy/store"
+	webhookevent "hr-tools-backend/lib/webhook/event"
 	"hr-tools-backend/models"
 	applicantapimodels "hr-tools-backend/models/api/applicant"
 	negotiationapimodels "hr-tools-backend/models/api/negotiation"
@@ -29,23 +39,32 @@ import (
 )
 
 type Provider interface {
-	ListOfNegotiation(spaceID string, filter dbmodels.NegotiationFilter) (list []negotiationapimodels.NegotiationView, err error)
+	ListOfNegotiation(spaceID, userID string, filter dbmodels.NegotiationFilter) (list []negotiationapimodels.NegotiationView, err error)
 	UpdateComment(spaceID, id, userID string, comment string) error
 	UpdateStatus(spaceID, id, userID string, status models.NegotiationStatus) (hMsg string, err error)
 	GetByID(spaceID, id string) (negotiationapimodels.NegotiationView, error)
-	CreateApplicant(spaceID, userID string, applicant applicantapimodels.ApplicantData) (string, error)
-	GetApplicant(spaceID string, id string) (applicantapimodels.ApplicantViewExt, error)
-	ListOfApplicant(spaceID string, filter applicantapimodels.ApplicantFilter) (list []applicantapimodels.ApplicantView, rowCount int64, err error)
-	UpdateApplicant(spaceID string, id, userID string, applicant applicantapimodels.ApplicantData) error
+	// CreateApplicant создание кандидата, userID пусто - кандидат создан системой, обязательность пользовательских полей не проверяется
+	CreateApplicant(spaceID, userID string, applicant applicantapimodels.ApplicantData) (id, hMsg string, err error)
+	GetApplicant(spaceID, id, userID string) (applicantapimodels.ApplicantViewExt, error)
+	ListOfApplicant(spaceID, userID string, filter applicantapimodels.ApplicantFilter) (list []applicantapimodels.ApplicantView, rowCount int64, err error)
+	UpdateApplicant(spaceID string, id, userID string, applicant applicantapimodels.ApplicantData) (hMsg string, err error)
 	ApplicantAddTag(spaceID string, id, userID string, tag string) error
 	ApplicantRemoveTag(spaceID string, id, userID string, tag string) error
 	ChangeStage(spaceID, userID string, applicantID, stageID string) (hMsg string, err error)
 	MultiChangeStage(spaceID, userID string, data applicantapimodels.MultiChangeStageRequest) error
+	// Hire перевод кандидата на этап "Принят" с указанной датой выхода (принятие оффера)
+	Hire(spaceID, userID string, applicantID string, startDate time.Time) (hMsg string, err error)
 	ResolveDuplicate(spaceID string, mainID, minorID, userID string, isDuplicate bool) error
+	// ListDuplicates возможные дубликаты кандидата с оценкой уверенности
+	ListDuplicates(spaceID, id string) ([]applicantapimodels.DuplicateSuggestion, error)
+	// MergeDuplicate объединение с дубликатом с переносом выбранных полей из дубликата
+	MergeDuplicate(spaceID, mainID, userID string, data applicantapimodels.MergeRequest) error
 	ApplicantReject(spaceID string, id, userID string, data applicantapimodels.RejectRequest) error
 	ApplicantMultiReject(spaceID string, userID string, data applicantapimodels.MultiRejectRequest) error
-	ExportToXls(spaceID string, data applicantapimodels.XlsExportRequest) (*bytes.Buffer, error)
-	ListOfSource(spaceID string, filter applicantapimodels.ApplicantFilter) (data applicantapimodels.ApplicantSourceData, err error)
+	ExportToXls(spaceID, userID string, data applicantapimodels.XlsExportRequest) (*bytes.Buffer, error)
+	ListOfSource(spaceID, userID string, filter applicantapimodels.ApplicantFilter) (data applicantapimodels.ApplicantSourceData, err error)
+	// ConsiderForVacancy копирование кандидата на этап "Добавлен" другой вакансии
+	ConsiderForVacancy(spaceID, userID, applicantID, vacancyID string) (id, hMsg string, err error)
 }
 
 var Instance Provider
@@ -58,6 +77,9 @@ func NewHandler() {
 		applicantHistory:    applicanthistoryhandler.Instance,
 		userStore:           spaceusersstore.NewInstance(db.DB),
 		vacancyStore:        vacancystore.NewInstance(db.DB),
+		scorecardStore:      scorecardstore.NewInstance(db.DB),
+		candidateStore:      candidatestore.NewInstance(db.DB),
+		filesStore:          filesdbstorage.NewInstance(db.DB),
 	}
 	initchecker.CheckInit(
 		"store", instance.store,
@@ -66,6 +88,9 @@ func NewHandler() {
 		"applicantHistory", instance.applicantHistory,
 		"userStore", instance.userStore,
 		"vacancyStore", instance.vacancyStore,
+		"scorecardStore", instance.scorecardStore,
+		"candidateStore", instance.candidateStore,
+		"filesStore", instance.filesStore,
 	)
 	Instance = instance
 }
@@ -77,6 +102,9 @@ type impl struct {
 	applicantHistory    applicanthistoryhandler.Provider
 	userStore           spaceusersstore.Provider
 	vacancyStore        vacancystore.Provider
+	scorecardStore      scorecardstore.Provider
+	candidateStore      candidatestore.Provider
+	filesStore          filesdbstorage.Provider
 }
 
 func (i *impl) getLogger(spaceID, applicantID, userID string) *log.Entry {
@@ -90,8 +118,12 @@ func (i *impl) getLogger(spaceID, applicantID, userID string) *log.Entry {
 	return logger
 }
 
-func (i impl) ListOfNegotiation(spaceID string, filter dbmodels.NegotiationFilter) ([]negotiationapimodels.NegotiationView, error) {
-	list, err := i.store.ListOfNegotiation(spaceID, filter)
+func (i impl) ListOfNegotiation(spaceID, userID string, filter dbmodels.NegotiationFilter) ([]negotiationapimodels.NegotiationView, error) {
+	scope, err := datascope.Instance.GetScope(spaceID, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения области видимости")
+	}
+	list, err := i.store.ListOfNegotiation(spaceID, scope, filter)
 	if err != nil {
 		return nil, errors.Wrap(err, "ошибка получения списка откликов")
 	}
@@ -131,7 +163,7 @@ func (i impl) UpdateComment(spaceID, id, userID string, comment string) error {
 		if vacancyRec == nil {
 			return
 		}
-		notification := models.GetPushApplicantNote(vacancyRec.VacancyName, rec.GetFIO(), userRec.GetFullName())
+		notification := models.GetPushApplicantNote(vacancyRec.VacancyName, rec.GetFIO(), userRec.GetFullName()).WithEntity(rec.ID)
 		i.sendNotification(*vacancyRec, notification)
 
 	}(rec.Applicant, userID)
@@ -194,11 +226,15 @@ func (i impl) GetByID(spaceID, id string) (negotiationapimodels.NegotiationView,
 	return negotiationapimodels.NegotiationConvertExt(*rec), nil
 }
 
-func (i impl) CreateApplicant(spaceID, userID string, data applicantapimodels.ApplicantData) (id string, err error) {
+func (i impl) CreateApplicant(spaceID, userID string, data applicantapimodels.ApplicantData) (id, hMsg string, err error) {
 	logger := i.getLogger(spaceID, "", userID)
 	vacancy, err := i.checkDependency(spaceID, data)
 	if err != nil {
-		return "", err
+		return "", "", err
+	}
+	customFields, hMsg, err := customfield.Instance.PrepareValues(spaceID, userID, models.CustomFieldEntityApplicant, data.CustomFields, nil)
+	if err != nil || hMsg != "" {
+		return "", hMsg, err
 	}
 	rec := dbmodels.Applicant{
 		BaseSpaceModel: dbmodels.BaseSpaceModel{
@@ -225,10 +261,11 @@ func (i impl) CreateApplicant(spaceID, userID string, data applicantapimodels.Ap
 		TotalExperience:       data.TotalExperience,
 		Params:                data.Params,
 		Comment:               data.Comment,
+		CustomFields:          customFields,
 	}
 	birthDate, err := data.GetBirthDate()
 	if err != nil {
-		return "", errors.Wrap(err, "ошибка получения даты рождения кандидата")
+		return "", "", errors.Wrap(err, "ошибка получения даты рождения кандидата")
 	}
 	rec.BirthDate = birthDate
 	for _, stage := range vacancy.SelectionStages {
@@ -239,18 +276,24 @@ func (i impl) CreateApplicant(spaceID, userID string, data applicantapimodels.Ap
 	}
 	recID, err := i.store.Create(rec)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 	changes := applicanthistoryhandler.GetCreateChanges("Кандидат добавлен на вакансию", rec)
 	i.applicantHistory.Save(rec.SpaceID, recID, rec.VacancyID, userID, dbmodels.HistoryTypeAdded, changes)
+	webhookevent.Publish(webhookevent.Event{
+		Type:        dbmodels.WebhookEventApplicantCreated,
+		SpaceID:     rec.SpaceID,
+		ApplicantID: recID,
+		VacancyID:   rec.VacancyID,
+	})
 	logger.
 		WithField("rec_id", recID).
 		Info("Создан кандидат")
-	return recID, nil
+	return recID, "", nil
 
 }
 
-func (i impl) GetApplicant(spaceID string, id string) (applicantapimodels.ApplicantViewExt, error) {
+func (i impl) GetApplicant(spaceID, id, userID string) (applicantapimodels.ApplicantViewExt, error) {
 	rec, err := i.store.GetByID(spaceID, id)
 	if err != nil {
 		return applicantapimodels.ApplicantViewExt{}, err
@@ -258,10 +301,15 @@ func (i impl) GetApplicant(spaceID string, id string) (applicantapimodels.Applic
 	if rec == nil {
 		return applicantapimodels.ApplicantViewExt{}, errors.New("кандидат не найден")
 	}
+	fields, err := customfield.Instance.GetFields(spaceID, userID, models.CustomFieldEntityApplicant)
+	if err != nil {
+		return applicantapimodels.ApplicantViewExt{}, err
+	}
 	result := applicantapimodels.ApplicantViewExt{
 		ApplicantView: applicantapimodels.ApplicantConvert(rec.Applicant),
 		Tags:          rec.Tags,
 	}
+	result.CustomFields = rec.CustomFields.Only(fields)
 	result.Duplicates = make([]string, 0, len(rec.Duplicates))
 	for _, item := range rec.Duplicates {
 		result.Duplicates = append(result.Duplicates, item.ID)
@@ -272,8 +320,15 @@ func (i impl) GetApplicant(spaceID string, id string) (applicantapimodels.Applic
 	return result, nil
 }
 
-func (i impl) ListOfApplicant(spaceID string, filter applicantapimodels.ApplicantFilter) (list []applicantapimodels.ApplicantView, rowCount int64, err error) {
-	rowCount, err = i.store.ListCountOfApplicant(spaceID, filter)
+func (i impl) ListOfApplicant(spaceID, userID string, filter applicantapimodels.ApplicantFilter) (list []applicantapimodels.ApplicantView, rowCount int64, err error) {
+	scope, err := datascope.Instance.GetScope(spaceID, userID)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "ошибка получения области видимости")
+	}
+	if err = i.prepareFilter(spaceID, userID, &filter); err != nil {
+		return nil, 0, err
+	}
+	rowCount, err = i.store.ListCountOfApplicant(spaceID, scope, filter)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -284,27 +339,33 @@ func (i impl) ListOfApplicant(spaceID string, filter applicantapimodels.Applican
 		return []applicantapimodels.ApplicantView{}, rowCount, nil
 	}
 
-	recList, err := i.store.ListOfApplicant(spaceID, filter)
+	recList, err := i.store.ListOfApplicant(spaceID, scope, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	fields, err := customfield.Instance.GetFields(spaceID, userID, models.CustomFieldEntityApplicant)
 	if err != nil {
 		return nil, 0, err
 	}
 	result := make([]applicantapimodels.ApplicantView, 0, len(recList))
 	for _, rec := range recList {
-		result = append(result, applicantapimodels.ApplicantConvert(rec))
+		item := applicantapimodels.ApplicantConvert(rec)
+		item.CustomFields = rec.CustomFields.Only(fields)
+		result = append(result, item)
 	}
 	return result, rowCount, nil
 }
 
-func (i impl) UpdateApplicant(spaceID string, id, userID string, data applicantapimodels.ApplicantData) error {
+func (i impl) UpdateApplicant(spaceID string, id, userID string, data applicantapimodels.ApplicantData) (hMsg string, err error) {
 	logger := i.getLogger(spaceID, id, userID)
 	vacancy, err := i.checkDependency(spaceID, data)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	birthDate, err := data.GetBirthDate()
 	if err != nil {
-		return errors.Wrap(err, "Некорректный формат даты рождения кандидата")
+		return "", errors.Wrap(err, "Некорректный формат даты рождения кандидата")
 	}
 	updMap := map[string]interface{}{
 		"SpaceID":         spaceID,
@@ -327,14 +388,19 @@ func (i impl) UpdateApplicant(spaceID string, id, userID string, data applicanta
 	}
 	rec, err := i.store.GetByID(spaceID, id)
 	if err != nil {
-		return errors.Wrap(err, "ошибка получения кандидата")
+		return "", errors.Wrap(err, "ошибка получения кандидата")
 	}
 	if rec == nil {
-		return errors.New("кандидат не найден")
+		return "", errors.New("кандидат не найден")
 	}
 	if rec.Status == models.ApplicantStatusArchive {
-		return errors.Errorf("обновление данных кандидата в статусе '%v' - недоступно", models.ApplicantStatusArchive)
+		return "", errors.Errorf("обновление данных кандидата в статусе '%v' - недоступно", models.ApplicantStatusArchive)
+	}
+	customFields, hMsg, err := customfield.Instance.PrepareValues(spaceID, userID, models.CustomFieldEntityApplicant, data.CustomFields, rec.CustomFields)
+	if err != nil || hMsg != "" {
+		return hMsg, err
 	}
+	updMap["CustomFields"] = customFields
 	if rec.Status == "" {
 		updMap["Status"] = models.ApplicantStatusInProcess
 	}
@@ -352,21 +418,31 @@ func (i impl) UpdateApplicant(spaceID string, id, userID string, data applicanta
 			}
 		}
 		if newSelectionStageID == "" {
-			return errors.New("смена вакансии невозможна, не найден этап подбора")
+			return "", errors.New("смена вакансии невозможна, не найден этап подбора")
 		}
 		updMap["SelectionStageID"] = newSelectionStageID
+		updMap["StageDeadline"] = nil
+		updMap["StageOverdueAt"] = nil
+		updMap["StageEscalatedAt"] = nil
 	}
 
 	err = i.store.Update(id, updMap)
 	if err != nil {
-		return errors.Wrap(err, "ошибка обновления кандидата")
+		return "", errors.Wrap(err, "ошибка обновления кандидата")
 	}
 	changes := applicanthistoryhandler.GetUpdateChanges("Изменен профиль", rec.Applicant, updMap)
+	fields, err := customfield.Instance.GetFields(spaceID, "", models.CustomFieldEntityApplicant)
+	if err != nil {
+		logger.WithError(err).Error("ошибка получения пользовательских полей для истории изменений")
+	} else {
+		values := customfield.Instance.FormatValues(fields, rec.CustomFields, customFields)
+		changes.Data = append(changes.Data, applicanthistoryhandler.GetCustomFieldChanges(fields, values[0], values[1])...)
+	}
 	if len(changes.Data) != 0 {
 		i.applicantHistory.Save(rec.SpaceID, id, rec.VacancyID, userID, dbmodels.HistoryTypeUpdate, changes)
 	}
 	logger.Info("Обновлен кандидат")
-	return nil
+	return "", nil
 }
 
 func (i impl) ApplicantAddTag(spaceID string, id, userID string, tag string) error {
@@ -440,12 +516,16 @@ func (i impl) ChangeStage(spaceID, userID string, applicantID, stageID string) (
 }
 
 func (i impl) MultiChangeStage(spaceID, userID string, data applicantapimodels.MultiChangeStageRequest) error {
+	ids, err := i.visibleIDs(spaceID, userID, data.IDs)
+	if err != nil || len(ids) == 0 {
+		return err
+	}
 	userName, err := i.getUserName(userID)
 	if err != nil {
 		return errors.Wrap(err, "ошибка перевода кандидата на этап")
 	}
 	err = db.DB.Transaction(func(tx *gorm.DB) error {
-		for _, id := range data.IDs {
+		for _, id := range ids {
 			hMsg, err := i.сhangeStage(nil, spaceID, userID, userName, id, data.StageID)
 			if err != nil {
 				return err
@@ -459,16 +539,83 @@ func (i impl) MultiChangeStage(spaceID, userID string, data applicantapimodels.M
 	return err
 }
 
+func (i impl) Hire(spaceID, userID string, applicantID string, startDate time.Time) (hMsg string, err error) {
+	applicantRec, err := i.store.GetByID(spaceID, applicantID)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения данных кандидата")
+	}
+	if applicantRec == nil {
+		return "кандидат не найден", nil
+	}
+	stageList, err := i.selectionStageStore.List(spaceID, applicantRec.VacancyID)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения этапов подбора")
+	}
+	stageID := ""
+	for _, stage := range stageList {
+		if stage.Name == dbmodels.HiredStage {
+			stageID = stage.ID
+			break
+		}
+	}
+	if stageID == "" {
+		return fmt.Sprintf("этап '%v' не найден", dbmodels.HiredStage), nil
+	}
+	userName, err := i.getUserName(userID)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка перевода кандидата на этап")
+	}
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		hMsg, err = i.сhangeStage(tx, spaceID, userID, userName, applicantID, stageID)
+		if err != nil || hMsg != "" {
+			return err
+		}
+		updMap := map[string]interface{}{
+			"start_date": startDate,
+		}
+		return applicantstore.NewInstance(tx).Update(applicantID, updMap)
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка перевода кандидата на этап")
+	}
+	return hMsg, nil
+}
+
 func (i impl) ResolveDuplicate(spaceID string, mainID, minorID, userID string, isDuplicate bool) error {
 	logger := i.getLogger(spaceID, "", userID).
 		WithField("main_id", mainID).
 		WithField("minor_ID", mainID)
 	if isDuplicate {
-		return i.joinApplicants(spaceID, mainID, minorID, userID, logger)
+		return i.joinApplicants(spaceID, mainID, minorID, userID, nil, logger)
 	}
 	return i.markAsDifferentApplicants(spaceID, mainID, minorID, userID, logger)
 }
 
+func (i impl) ListDuplicates(spaceID, id string) ([]applicantapimodels.DuplicateSuggestion, error) {
+	rec, err := i.store.GetByID(spaceID, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения кандидата")
+	}
+	if rec == nil {
+		return nil, errors.New("кандидат не найден")
+	}
+	if rec.Status == models.ApplicantStatusArchive {
+		return []applicantapimodels.DuplicateSuggestion{}, nil
+	}
+	list, err := i.findDuplicates(rec)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка поиска дублей кандидата")
+	}
+	return list, nil
+}
+
+func (i impl) MergeDuplicate(spaceID, mainID, userID string, data applicantapimodels.MergeRequest) error {
+	logger := i.getLogger(spaceID, "", userID).
+		WithField("main_id", mainID).
+		WithField("minor_ID", data.DuplicateID)
+	return i.joinApplicants(spaceID, mainID, data.DuplicateID, userID, data.Fields, logger)
+}
+
 func (i impl) ApplicantReject(spaceID string, id, userID string, data applicantapimodels.RejectRequest) error {
 	userName, err := i.getUserName(userID)
 	if err != nil {
@@ -482,13 +629,17 @@ func (i impl) ApplicantReject(spaceID string, id, userID string, data applicanta
 }
 
 func (i impl) ApplicantMultiReject(spaceID string, userID string, data applicantapimodels.MultiRejectRequest) error {
+	ids, err := i.visibleIDs(spaceID, userID, data.IDs)
+	if err != nil || len(ids) == 0 {
+		return err
+	}
 	userName, err := i.getUserName(userID)
 	if err != nil {
 		return errors.Wrap(err, "ошибка перевода кандидатов в отклоненные")
 	}
 
 	err = db.DB.Transaction(func(tx *gorm.DB) error {
-		for _, id := range data.IDs {
+		for _, id := range ids {
 			err := i.applicantReject(tx, spaceID, id, userID, userName, data.Reject)
 			if err != nil {
 				return err
@@ -499,16 +650,59 @@ func (i impl) ApplicantMultiReject(spaceID string, userID string, data applicant
 	return err
 }
 
-func (i impl) ExportToXls(spaceID string, data applicantapimodels.XlsExportRequest) (*bytes.Buffer, error) {
-	list, err := i.store.ListOfApplicantByIDs(spaceID, data.IDs, data.Filter)
+// visibleIDs кандидаты из списка, входящие в область видимости пользователя, остальные пропускаются
+func (i impl) visibleIDs(spaceID, userID string, ids []string) ([]string, error) {
+	scope, err := datascope.Instance.GetScope(spaceID, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения области видимости")
+	}
+	visibleIDs, err := i.store.ListVisibleIDs(spaceID, scope, ids)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка проверки области видимости кандидатов")
+	}
+	return visibleIDs, nil
+}
+
+func (i impl) ExportToXls(spaceID, userID string, data applicantapimodels.XlsExportRequest) (*bytes.Buffer, error) {
+	scope, err := datascope.Instance.GetScope(spaceID, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения области видимости")
+	}
+	if err = i.prepareFilter(spaceID, userID, data.Filter); err != nil {
+		return nil, err
+	}
+	list, err := i.store.ListOfApplicantByIDs(spaceID, scope, data.IDs, data.Filter)
 	if err != nil {
 		return nil, err
 	}
-	return xlsexport.Instance.ExportApplicantList(list)
+	applicantIDs := make([]string, 0, len(list))
+	for _, item := range list {
+		applicantIDs = append(applicantIDs, item.ID)
+	}
+	scorecards, err := i.scorecardStore.ListSubmittedByApplicants(spaceID, applicantIDs)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения оценочных карт")
+	}
+	fields, err := customfield.Instance.GetFields(spaceID, userID, models.CustomFieldEntityApplicant)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]dbmodels.CustomFieldValues, 0, len(list))
+	for _, item := range list {
+		values = append(values, item.CustomFields)
+	}
+	return xlsexport.Instance.ExportApplicantList(list, scorecards, fields, customfield.Instance.FormatValues(fields, values...))
 }
 
-func (i impl) ListOfSource(spaceID string, filter applicantapimodels.ApplicantFilter) (data applicantapimodels.ApplicantSourceData, err error) {
-	recList, err := i.store.ListOfApplicantSource(spaceID, filter)
+func (i impl) ListOfSource(spaceID, userID string, filter applicantapimodels.ApplicantFilter) (data applicantapimodels.ApplicantSourceData, err error) {
+	scope, err := datascope.Instance.GetScope(spaceID, userID)
+	if err != nil {
+		return applicantapimodels.ApplicantSourceData{}, errors.Wrap(err, "ошибка получения области видимости")
+	}
+	if err = i.prepareFilter(spaceID, userID, &filter); err != nil {
+		return applicantapimodels.ApplicantSourceData{}, err
+	}
+	recList, err := i.store.ListOfApplicantSource(spaceID, scope, filter)
 	if err != nil {
 		return applicantapimodels.ApplicantSourceData{}, err
 	}
@@ -547,6 +741,113 @@ func (i impl) ListOfSource(spaceID string, filter applicantapimodels.ApplicantFi
 	return result, nil
 }
 
+func (i impl) ConsiderForVacancy(spaceID, userID, applicantID, vacancyID string) (id, hMsg string, err error) {
+	logger := i.getLogger(spaceID, applicantID, userID).WithField("vacancy_id", vacancyID)
+	rec, err := i.store.GetByID(spaceID, applicantID)
+	if err != nil {
+		return "", "", errors.Wrap(err, "ошибка получения кандидата")
+	}
+	if rec == nil {
+		return "", "кандидат не найден", nil
+	}
+	if rec.VacancyID == vacancyID {
+		return "", "кандидат уже рассматривается на эту вакансию", nil
+	}
+	if rec.CandidateID != nil {
+		candidate, err := i.candidateStore.GetByID(spaceID, *rec.CandidateID)
+		if err != nil {
+			return "", "", errors.Wrap(err, "ошибка получения карточки кандидата")
+		}
+		if candidate != nil {
+			for _, item := range candidate.Applicants {
+				if item.VacancyID == vacancyID && item.Status != models.ApplicantStatusArchive {
+					return "", "кандидат уже рассматривается на эту вакансию", nil
+				}
+			}
+		}
+	}
+	vacancy, err := i.vacancyStore.GetByID(spaceID, vacancyID)
+	if err != nil {
+		return "", "", errors.Wrap(err, "ошибка получения вакансии")
+	}
+	if vacancy == nil {
+		return "", "вакансия не найдена", nil
+	}
+	newRec := dbmodels.Applicant{
+		BaseSpaceModel: dbmodels.BaseSpaceModel{
+			SpaceID: spaceID,
+		},
+		VacancyID:             vacancyID,
+		CandidateID:           rec.CandidateID,
+		ResumeTitle:           rec.ResumeTitle,
+		Source:                rec.Source,
+		NegotiationAcceptDate: time.Now(),
+		Status:                models.ApplicantStatusInProcess,
+		FirstName:             rec.FirstName,
+		LastName:              rec.LastName,
+		MiddleName:            rec.MiddleName,
+		Salary:                rec.Salary,
+		Address:               rec.Address,
+		BirthDate:             rec.BirthDate,
+		Citizenship:           rec.Citizenship,
+		Gender:                rec.Gender,
+		Relocation:            rec.Relocation,
+		Phone:                 rec.Phone,
+		Email:                 rec.Email,
+		TotalExperience:       rec.TotalExperience,
+		Params:                rec.Params,
+		Comment:               rec.Comment,
+		Tags:                  rec.Tags,
+	}
+	for _, stage := range vacancy.SelectionStages {
+		if stage.Name == dbmodels.AddedStage {
+			newRec.SelectionStageID = stage.ID
+			break
+		}
+	}
+	id, err = i.store.Create(newRec)
+	if err != nil {
+		return "", "", errors.Wrap(err, "ошибка создания кандидата на вакансии")
+	}
+	logger = logger.WithField("rec_id", id)
+	i.copyFiles(spaceID, applicantID, id, logger)
+
+	changes := applicanthistoryhandler.GetCreateChanges("Кандидат добавлен на вакансию из другой вакансии", newRec)
+	i.applicantHistory.Save(spaceID, id, vacancyID, userID, dbmodels.HistoryTypeAdded, changes)
+	webhookevent.Publish(webhookevent.Event{
+		Type:        dbmodels.WebhookEventApplicantCreated,
+		SpaceID:     spaceID,
+		ApplicantID: id,
+		VacancyID:   vacancyID,
+	})
+	i.applicantHistory.Save(spaceID, applicantID, rec.VacancyID, userID, dbmodels.HistoryTypeAdded, applicanthistoryhandler.GetConsiderChange(vacancy.VacancyName))
+	logger.Info("кандидат добавлен на другую вакансию")
+	return id, "", nil
+}
+
+// copyFiles копирование резюме, фото и документов кандидата, ошибки копирования не прерывают добавление на вакансию
+func (i impl) copyFiles(spaceID, fromID, toID string, logger *log.Entry) {
+	ctx := context.TODO()
+	for _, fileType := range []dbmodels.FileType{dbmodels.ApplicantResume, dbmodels.ApplicantPhoto, dbmodels.ApplicantDoc} {
+		list, err := i.filesStore.GetFileListByType(fromID, fileType)
+		if err != nil {
+			logger.WithError(err).Error("ошибка получения списка файлов кандидата")
+			continue
+		}
+		for _, file := range list {
+			body, contentType, name, err := filestorage.Instance.GetFile(ctx, spaceID, file.ID)
+			if err != nil {
+				logger.WithError(err).WithField("file_id", file.ID).Error("ошибка получения файла кандидата")
+				continue
+			}
+			err = filestorage.Instance.Upload(ctx, spaceID, toID, body, name, fileType, contentType)
+			if err != nil {
+				logger.WithError(err).WithField("file_id", file.ID).Error("ошибка копирования файла кандидата")
+			}
+		}
+	}
+}
+
 func (i impl) markAsDifferentApplicants(spaceID string, mainID, minorID, userID string, logger *log.Entry) error {
 	mainRec, err := i.store.GetByID(spaceID, mainID)
 	if err != nil {
@@ -583,7 +884,8 @@ func (i impl) markAsDifferentApplicants(spaceID string, mainID, minorID, userID
 	return nil
 }
 
-func (i impl) joinApplicants(spaceID string, mainID, minorID, userID string, logger *log.Entry) error {
+// joinApplicants объединение кандидатов: дубликат переводится в архив, поля fields переносятся из дубликата в основного кандидата
+func (i impl) joinApplicants(spaceID string, mainID, minorID, userID string, fields []string, logger *log.Entry) error {
 	mainRec, err := i.store.GetByID(spaceID, mainID)
 	if err != nil {
 		return errors.Wrap(err, "ошибка получения данных основного кандидата")
@@ -604,6 +906,10 @@ func (i impl) joinApplicants(spaceID string, mainID, minorID, userID string, log
 	if minorRec.Status == models.ApplicantStatusArchive {
 		return errors.Errorf("объединение данных кандидата в статусе '%v' - недоступно", models.ApplicantStatusArchive)
 	}
+	if mainID == minorID {
+		return errors.New("кандидат не может быть объединен сам с собой")
+	}
+	mergeMap := getMergeMap(minorRec.Applicant, fields)
 	err = db.DB.Transaction(func(tx *gorm.DB) error {
 		store := applicantstore.NewInstance(tx)
 		updMap := map[string]interface{}{
@@ -614,6 +920,23 @@ func (i impl) joinApplicants(spaceID string, mainID, minorID, userID string, log
 		if err != nil {
 			return errors.Wrap(err, "ошибка перевода дубликата в архив")
 		}
+		err = store.Update(mainID, mergeMap)
+		if err != nil {
+			return errors.Wrap(err, "ошибка переноса данных дубликата")
+		}
+		if mainRec.CandidateID == nil || minorRec.CandidateID == nil || *mainRec.CandidateID == *minorRec.CandidateID {
+			return nil
+		}
+		// объединение карточек кандидатов
+		candidateStore := candidatestore.NewInstance(tx)
+		err = candidateStore.MoveApplicants(spaceID, *minorRec.CandidateID, *mainRec.CandidateID)
+		if err != nil {
+			return err
+		}
+		err = candidateStore.Delete(spaceID, *minorRec.CandidateID)
+		if err != nil {
+			return errors.Wrap(err, "ошибка удаления карточки кандидата дубликата")
+		}
 		return nil
 	})
 	if err != nil {
@@ -621,15 +944,67 @@ func (i impl) joinApplicants(spaceID string, mainID, minorID, userID string, log
 	}
 	descr := applicanthistoryhandler.GetDuplicateMark(minorRec.Applicant)
 	i.applicantHistory.Save(spaceID, mainID, mainRec.VacancyID, userID, dbmodels.HistoryTypeDuplicate, descr)
+	changes := applicanthistoryhandler.GetUpdateChanges("Профиль дополнен данными дубликата", mainRec.Applicant, mergeMap)
+	if len(changes.Data) != 0 {
+		i.applicantHistory.Save(spaceID, mainID, mainRec.VacancyID, userID, dbmodels.HistoryTypeUpdate, changes)
+	}
 	logger.Info("дубликат кандидата перемещем в архив")
 	return nil
 }
 
+func getMergeMap(minorRec dbmodels.Applicant, fields []string) map[string]interface{} {
+	result := map[string]interface{}{}
+	for _, field := range fields {
+		switch field {
+		case "first_name":
+			result[field] = minorRec.FirstName
+		case "last_name":
+			result[field] = minorRec.LastName
+		case "middle_name":
+			result[field] = minorRec.MiddleName
+		case "phone":
+			result[field] = minorRec.Phone
+		case "email":
+			result[field] = minorRec.Email
+		case "birth_date":
+			result[field] = minorRec.BirthDate
+		case "citizenship":
+			result[field] = minorRec.Citizenship
+		case "gender":
+			result[field] = minorRec.Gender
+		case "relocation":
+			result[field] = minorRec.Relocation
+		case "address":
+			result[field] = minorRec.Address
+		case "salary":
+			result[field] = minorRec.Salary
+		case "total_experience":
+			result[field] = minorRec.TotalExperience
+		case "comment":
+			result[field] = minorRec.Comment
+		}
+	}
+	return result
+}
+
+// prepareFilter проверка фильтров по пользовательским полям и заполнение типа полей
+func (i impl) prepareFilter(spaceID, userID string, filter *applicantapimodels.ApplicantFilter) error {
+	if filter == nil {
+		return nil
+	}
+	customFields, err := customfield.Instance.PrepareFilter(spaceID, userID, models.CustomFieldEntityApplicant, filter.CustomFields)
+	if err != nil {
+		return err
+	}
+	filter.CustomFields = customFields
+	return nil
+}
+
 func (i impl) checkDependency(spaceID string, data applicantapimodels.ApplicantData) (vacancy vacancyapimodels.VacancyView, err error) {
 	if data.VacancyID == "" {
 		return vacancyapimodels.VacancyView{}, errors.New("необходима указать вакансию")
 	}
-	vacancy, err = i.vacancyProvider.GetByID(spaceID, data.VacancyID)
+	vacancy, err = i.vacancyProvider.GetByID(spaceID, data.VacancyID, "")
 	if err != nil {
 		return vacancyapimodels.VacancyView{}, err
 	}
@@ -637,24 +1012,29 @@ func (i impl) checkDependency(spaceID string, data applicantapimodels.ApplicantD
 }
 
 func (i impl) checkDuplicate(originRec *dbmodels.ApplicantExt) applicantapimodels.ApplicantDuplicate {
-	logger := i.getLogger(originRec.SpaceID, originRec.ID, "")
-	applicantFIO := getLowerFio(originRec.Applicant)
-	filter := dbmodels.DuplicateApplicantFilter{
-		VacancyID:      originRec.VacancyID,
-		FIO:            applicantFIO,
-		Phone:          originRec.Phone,
-		Email:          originRec.Email,
-		ExtApplicantID: originRec.ExtApplicantID,
-	}
-	list, err := i.store.ListOfDuplicateApplicant(originRec.SpaceID, filter)
-	if err != nil {
-		logger.WithError(err).Error("Ошибка получения списка кандидатов для поиска дублей")
+	list, err := i.findDuplicates(originRec)
+	if err != nil {
+		i.getLogger(originRec.SpaceID, originRec.ID, "").WithError(err).Error("Ошибка получения списка кандидатов для поиска дублей")
 		return applicantapimodels.ApplicantDuplicate{}
 	}
-	if len(list) <= 1 {
+	if len(list) == 0 {
 		return applicantapimodels.ApplicantDuplicate{}
 	}
+	return applicantapimodels.ApplicantDuplicate{
+		Found:         true,
+		DuplicateID:   list[0].ApplicantID,
+		DuplicateType: list[0].DuplicateType,
+		Confidence:    list[0].Confidence,
+	}
+}
 
+// findDuplicates возможные дубликаты кандидата на вакансии, по убыванию уверенности
+func (i impl) findDuplicates(originRec *dbmodels.ApplicantExt) ([]applicantapimodels.DuplicateSuggestion, error) {
+	list, err := i.store.ListForDuplicateCheck(originRec.SpaceID, originRec.VacancyID)
+	if err != nil {
+		return nil, err
+	}
+	result := []applicantapimodels.DuplicateSuggestion{}
 	for _, rec := range list {
 		if rec.ID == originRec.ID {
 			continue
@@ -663,50 +1043,28 @@ func (i impl) checkDuplicate(originRec *dbmodels.ApplicantExt) applicantapimodel
 			//уже помечен как дубль
 			continue
 		}
-
 		if originRec.IsMarkAsNotDuplicate(rec) {
 			//уже помечен как не дубль
 			continue
 		}
-		if originRec.ExtApplicantID != "" && originRec.ExtApplicantID == rec.ExtApplicantID {
-			//совпадение по автору во внешней системе
-			return applicantapimodels.ApplicantDuplicate{
-				Found:         true,
-				DuplicateID:   rec.ID,
-				DuplicateType: models.DuplicateTypeByAuthor,
-			}
-		}
-		if applicantFIO == "" || applicantFIO != getLowerFio(rec) {
+		match := applicantduplicate.Score(originRec.Applicant, rec)
+		if !match.IsDuplicate() {
 			continue
 		}
-		phoneEquals := false
-		if originRec.Phone != "" && originRec.Phone == rec.Phone {
-			if originRec.Email == "" || rec.Email == "" {
-				//ФИО+телефон если почта не указанна
-				return applicantapimodels.ApplicantDuplicate{
-					Found:         true,
-					DuplicateID:   rec.ID,
-					DuplicateType: models.DuplicateTypeByContacts,
-				}
-			}
-			phoneEquals = true
-		}
-		if originRec.Email != "" && originRec.Email == rec.Email {
-			if phoneEquals || (originRec.Phone == "" || rec.Phone == "") {
-				//ФИО, почта, телефон или ФИО+почта если телефон не указанна
-				return applicantapimodels.ApplicantDuplicate{
-					Found:         true,
-					DuplicateID:   rec.ID,
-					DuplicateType: models.DuplicateTypeByContacts,
-				}
-			}
-		}
-	}
-	return applicantapimodels.ApplicantDuplicate{}
-}
-
-func getLowerFio(rec dbmodels.Applicant) string {
-	return fmt.Sprintf("%v %v %v", rec.LastName, rec.FirstName, rec.MiddleName)
+		result = append(result, applicantapimodels.DuplicateSuggestion{
+			ApplicantID:   rec.ID,
+			FIO:           rec.GetFIO(),
+			Phone:         rec.Phone,
+			Email:         rec.Email,
+			DuplicateType: match.DuplicateType,
+			Confidence:    match.Confidence,
+			Reasons:       match.Reasons,
+		})
+	}
+	sort.SliceStable(result, func(a, b int) bool {
+		return result[a].Confidence > result[b].Confidence
+	})
+	return result, nil
 }
 
 func (i impl) applicantReject(tx *gorm.DB, spaceID string, id, userID, userName string, data applicantapimodels.RejectRequest) error {
@@ -744,6 +1102,13 @@ func (i impl) applicantReject(tx *gorm.DB, spaceID string, id, userID, userName
 	// История изменений
 	changes := applicanthistoryhandler.GetRejectChange(data.Reason, rec.Applicant, updMap)
 	applicantHistory.SaveWithUser(spaceID, id, rec.VacancyID, userID, userName, dbmodels.HistoryTypeReject, changes)
+	webhookevent.Publish(webhookevent.Event{
+		Type:        dbmodels.WebhookEventApplicantRejected,
+		SpaceID:     spaceID,
+		ApplicantID: id,
+		VacancyID:   rec.VacancyID,
+		Reason:      data.Reason,
+	})
 	return err
 }
 
@@ -807,6 +1172,10 @@ func (i impl) сhangeStage(tx *gorm.DB, spaceID, userID, userName string, applic
 	}
 	updMap := map[string]interface{}{
 		"selection_stage_id": stageRec.ID,
+		// срок на новом этапе рассчитывается заново
+		"stage_deadline":     nil,
+		"stage_overdue_at":   nil,
+		"stage_escalated_at": nil,
 	}
 
 	switch stageRec.Name {
@@ -831,6 +1200,20 @@ func (i impl) сhangeStage(tx *gorm.DB, spaceID, userID, userName string, applic
 	}
 	changes := applicanthistoryhandler.GetStageChange(stageRec.Name)
 	applicantHistory.SaveWithUser(spaceID, applicantID, applicantRec.VacancyID, userID, userName, dbmodels.HistoryTypeStageChange, changes)
+	automationevent.Publish(automationevent.Event{
+		Trigger:     dbmodels.AutomationTriggerStageChange,
+		SpaceID:     spaceID,
+		ApplicantID: applicantID,
+		VacancyID:   applicantRec.VacancyID,
+		StageName:   stageRec.Name,
+	})
+	webhookevent.Publish(webhookevent.Event{
+		Type:        dbmodels.WebhookEventApplicantStageChanged,
+		SpaceID:     spaceID,
+		ApplicantID: applicantID,
+		VacancyID:   applicantRec.VacancyID,
+		StageName:   stageRec.Name,
+	})
 
 	go func(rec dbmodels.Applicant, userName string) {
 		logger := i.getLogger(rec.SpaceID, rec.ID, userID).
@@ -839,7 +1222,7 @@ func (i impl) сhangeStage(tx *gorm.DB, spaceID, userID, userName string, applic
 		if vacancyRec == nil {
 			return
 		}
-		notification := models.GetPushApplicantNewStage(vacancyRec.VacancyName, userName, stageRec.Name)
+		notification := models.GetPushApplicantNewStage(vacancyRec.VacancyName, userName, stageRec.Name).WithEntity(rec.ID)
 		i.sendNotification(*vacancyRec, notification)
 	}(applicantRec.Applicant, userName)
 	return "", nil
@@ -0,0 +1,54 @@
+package applicant
+
+import (
+	applicantstore "hr-tools-backend/lib/applicant/store"
+	datascopefake "hr-tools-backend/lib/data-scope/fake"
+	applicantapimodels "hr-tools-backend/models/api/applicant"
+	dbmodels "hr-tools-backend/models/db"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	applicantstore.Provider
+	visible []string
+	checked []string
+}
+
+func (s *fakeStore) ListVisibleIDs(spaceID string, scope *dbmodels.DataScope, ids []string) ([]string, error) {
+	s.checked = append(s.checked, ids...)
+	result := []string{}
+	for _, id := range ids {
+		for _, visibleID := range s.visible {
+			if id == visibleID {
+				result = append(result, id)
+			}
+		}
+	}
+	return result, nil
+}
+
+func TestMultiActionsOutsideScope(t *testing.T) {
+	datascopefake.Set(t, datascopefake.DataScope{})
+	store := &fakeStore{visible: []string{"own"}}
+	// кандидаты вне области видимости пропускаются, без видимых кандидатов изменения не выполняются
+	i := impl{store: store}
+
+	err := i.MultiChangeStage("space", "user", applicantapimodels.MultiChangeStageRequest{
+		IDs:     []string{"other-1", "other-2"},
+		StageID: "stage",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"other-1", "other-2"}, store.checked)
+
+	err = i.ApplicantMultiReject("space", "user", applicantapimodels.MultiRejectRequest{
+		IDs: []string{"other-3"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"other-1", "other-2", "other-3"}, store.checked)
+
+	ids, err := i.visibleIDs("space", "user", []string{"own", "other-1"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"own"}, ids)
+}
@@ -0,0 +1,180 @@
+package applicantstagelimitworker
+
+import (
+	"context"
+	"hr-tools-backend/db"
+	applicantstore "hr-tools-backend/lib/applicant/store"
+	pushhandler "hr-tools-backend/lib/space/push/handler"
+	spacesettingsstore "hr-tools-backend/lib/space/settings/store"
+	spaceusersstore "hr-tools-backend/lib/space/users/store"
+	baseworker "hr-tools-backend/lib/utils/base-worker"
+	"hr-tools-backend/lib/utils/helpers"
+	workcalendar "hr-tools-backend/lib/utils/work-calendar"
+	vacancystore "hr-tools-backend/lib/vacancy/store"
+	"hr-tools-backend/models"
+	dbmodels "hr-tools-backend/models/db"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const dateTimeLayout = "02.01.2006 15:04"
+
+// Задача контроля лимита времени нахождения кандидатов на этапах подбора
+func StartWorker(ctx context.Context) {
+	i := &impl{
+		BaseImpl:       *baseworker.NewInstance("ApplicantStageLimitWorker", 30*time.Second, 5*time.Minute),
+		applicantStore: applicantstore.NewInstance(db.DB),
+		vacancyStore:   vacancystore.NewInstance(db.DB),
+		settingsStore:  spacesettingsstore.NewInstance(db.DB),
+		userStore:      spaceusersstore.NewInstance(db.DB),
+	}
+	go i.Run(ctx, i.handle)
+}
+
+type impl struct {
+	baseworker.BaseImpl
+	applicantStore applicantstore.Provider
+	vacancyStore   vacancystore.Provider
+	settingsStore  spacesettingsstore.Provider
+	userStore      spaceusersstore.Provider
+}
+
+func (i impl) handle(ctx context.Context) {
+	logger := i.GetLogger()
+	list, err := i.applicantStore.ListForStageLimit()
+	if err != nil {
+		logger.WithError(err).Error("ошибка получения списка кандидатов на этапах с лимитом времени")
+		return
+	}
+	calendars := map[string]workcalendar.Calendar{}
+	vacancies := map[string]*dbmodels.Vacancy{}
+	for _, rec := range list {
+		if helpers.IsContextDone(ctx) {
+			break
+		}
+		if rec.StageStartAt.IsZero() {
+			continue
+		}
+		recLogger := logger.
+			WithField("space_id", rec.SpaceID).
+			WithField("applicant_id", rec.ID)
+		calendar, ok := calendars[rec.SpaceID]
+		if !ok {
+			calendar, err = i.getCalendar(rec.SpaceID)
+			if err != nil {
+				// считаем по календарю без праздников, выходные не учитываются
+				recLogger.WithError(err).Warn("ошибка получения производственного календаря спейса")
+			}
+			calendars[rec.SpaceID] = calendar
+		}
+		vacancyRec, ok := vacancies[rec.VacancyID]
+		if !ok {
+			vacancyRec, err = i.vacancyStore.GetByID(rec.SpaceID, rec.VacancyID)
+			if err != nil {
+				recLogger.WithError(err).Error("ошибка получения вакансии")
+				continue
+			}
+			vacancies[rec.VacancyID] = vacancyRec
+		}
+		if vacancyRec == nil {
+			continue
+		}
+		err = i.checkApplicant(rec, calendar, *vacancyRec)
+		if err != nil {
+			recLogger.WithError(err).Error("ошибка контроля лимита времени на этапе")
+		}
+	}
+}
+
+func (i impl) checkApplicant(rec dbmodels.ApplicantStageLimit, calendar workcalendar.Calendar, vacancyRec dbmodels.Vacancy) error {
+	now := time.Now()
+	deadline := calendar.AddLimit(rec.StageStartAt, rec.LimitValue, rec.LimitType)
+	updMap := map[string]interface{}{}
+	if rec.StageDeadline == nil || !rec.StageDeadline.Equal(deadline) {
+		updMap["stage_deadline"] = deadline
+	}
+	var notifyOverdue, notifyEscalation bool
+	if now.After(deadline) {
+		if rec.StageOverdueAt == nil {
+			updMap["stage_overdue_at"] = now
+			notifyOverdue = true
+		} else if rec.StageEscalatedAt == nil && now.After(calendar.AddLimit(deadline, rec.LimitValue, rec.LimitType)) {
+			// лимит превышен вдвое
+			updMap["stage_escalated_at"] = now
+			notifyEscalation = true
+		}
+	} else if rec.StageOverdueAt != nil {
+		// срок сдвинулся после изменения производственного календаря
+		updMap["stage_overdue_at"] = nil
+		updMap["stage_escalated_at"] = nil
+	}
+	if len(updMap) == 0 {
+		return nil
+	}
+	err := i.applicantStore.Update(rec.ID, updMap)
+	if err != nil {
+		return err
+	}
+	responsibleID := getResponsibleID(vacancyRec)
+	if notifyOverdue {
+		data := models.GetPushApplicantOverdue(rec.GetFIO(), rec.StageName, vacancyRec.VacancyName, deadline.Format(dateTimeLayout)).WithEntity(rec.ID)
+		pushhandler.Instance.SendNotification(responsibleID, data)
+		log.
+			WithField("space_id", rec.SpaceID).
+			WithField("applicant_id", rec.ID).
+			Info("превышен лимит времени на этапе, ответственный уведомлен")
+	}
+	if notifyEscalation && vacancyRec.AuthorID != "" {
+		responsibleName := ""
+		user, err := i.userStore.GetByID(responsibleID)
+		if err != nil {
+			return errors.Wrap(err, "ошибка получения ответственного по вакансии")
+		}
+		if user != nil {
+			responsibleName = user.GetFullName()
+		}
+		data := models.GetPushApplicantEscalation(rec.GetFIO(), rec.StageName, vacancyRec.VacancyName, deadline.Format(dateTimeLayout), responsibleName).WithEntity(rec.ID)
+		pushhandler.Instance.SendNotification(vacancyRec.AuthorID, data)
+		log.
+			WithField("space_id", rec.SpaceID).
+			WithField("applicant_id", rec.ID).
+			Info("просрочка этапа эскалирована автору вакансии")
+	}
+	return nil
+}
+
+func (i impl) getCalendar(spaceID string) (workcalendar.Calendar, error) {
+	holidays, err := i.getSetting(spaceID, models.HolidaysSetting)
+	if err != nil {
+		return workcalendar.Calendar{}, err
+	}
+	workdays, err := i.getSetting(spaceID, models.WorkdaysSetting)
+	if err != nil {
+		return workcalendar.Calendar{}, err
+	}
+	return workcalendar.New(holidays, workdays)
+}
+
+func (i impl) getSetting(spaceID string, code models.SpaceSettingCode) (string, error) {
+	value, err := i.settingsStore.GetValueByCode(spaceID, code)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil
+		}
+		return "", errors.Wrapf(err, "ошибка получения настройки %v", code)
+	}
+	return value, nil
+}
+
+// getResponsibleID ответственный по вакансии из команды, если не назначен - автор вакансии
+func getResponsibleID(rec dbmodels.Vacancy) string {
+	for _, teamMember := range rec.VacancyTeam {
+		if teamMember.Responsible {
+			return teamMember.UserID
+		}
+	}
+	return rec.AuthorID
+}
@@ -20,16 +20,32 @@ type Provider interface {
 	Update(id string, updMap map[string]interface{}) error
 	GetByID(spaceID, id string) (rec *dbmodels.ApplicantExt, err error)
 	IsExistNegotiationID(spaceID, negotiationID string, source models.ApplicantSource) (found bool, err error)
-	ListOfNegotiation(spaceID string, filter dbmodels.NegotiationFilter) ([]dbmodels.Applicant, error)
-	ListCountOfApplicant(spaceID string, filter applicantapimodels.ApplicantFilter) (count int64, err error)
-	ListOfApplicant(spaceID string, filter applicantapimodels.ApplicantFilter) ([]dbmodels.Applicant, error)
-	ListOfDuplicateApplicant(spaceID string, filter dbmodels.DuplicateApplicantFilter) (list []dbmodels.Applicant, err error)
+	ListOfNegotiation(spaceID string, scope *dbmodels.DataScope, filter dbmodels.NegotiationFilter) ([]dbmodels.Applicant, error)
+	ListCountOfApplicant(spaceID string, scope *dbmodels.DataScope, filter applicantapimodels.ApplicantFilter) (count int64, err error)
+	ListOfApplicant(spaceID string, scope *dbmodels.DataScope, filter applicantapimodels.ApplicantFilter) ([]dbmodels.Applicant, error)
+	// IsVisible кандидат виден в области видимости пользователя
+	IsVisible(spaceID, id string, scope *dbmodels.DataScope) (bool, error)
+	// ListVisibleIDs идентификаторы кандидатов из списка, входящие в область видимости пользователя
+	ListVisibleIDs(spaceID string, scope *dbmodels.DataScope, ids []string) ([]string, error)
+	// ListForDuplicateCheck кандидаты вакансии (кроме архивных) с полями, необходимыми для оценки дублей
+	ListForDuplicateCheck(spaceID, vacancyID string) (list []dbmodels.Applicant, err error)
 	ApplicantsByStages(spaceID string, vacancyIDs []string) (list []dbmodels.ApplicantsStage, err error)
-	ListOfApplicantByIDs(spaceID string, ids []string, filter *applicantapimodels.ApplicantFilter) ([]dbmodels.ApplicantWithJob, error)
-	ListOfApplicantSource(spaceID string, filter applicantapimodels.ApplicantFilter) ([]dbmodels.ApplicantSource, error)
-	ListOfActiveApplicants() ([]dbmodels.Applicant, error)
+	ListOfApplicantByIDs(spaceID string, scope *dbmodels.DataScope, ids []string, filter *applicantapimodels.ApplicantFilter) ([]dbmodels.ApplicantWithJob, error)
+	ListOfApplicantSource(spaceID string, scope *dbmodels.DataScope, filter applicantapimodels.ApplicantFilter) ([]dbmodels.ApplicantSource, error)
+	ListOfActiveApplicants(sources []models.ApplicantSource) ([]dbmodels.Applicant, error)
 	ListOfActivefNegotiation(withHrSurvy bool) ([]dbmodels.Applicant, error)
 	ListForSurveySend() ([]dbmodels.Applicant, error)
+	ListByEmail(spaceID, email string) ([]dbmodels.Applicant, error)
+	ListForStageLimit() ([]dbmodels.ApplicantStageLimit, error)
+	ListForCandidateLink(limit int) ([]dbmodels.Applicant, error)
+	ListForRetention(spaceID string, before time.Time, limit int) ([]dbmodels.Applicant, error)
+	// DeleteArtifacts удаление ответов кандидата на анкеты, результатов видео-интервью и рекомендаций ИИ по резюме
+	DeleteArtifacts(id string) error
+	// AnonymizeRelated очистка ПД в интервью, офферах и оценочных картах кандидата, удаление ссылок на запись на интервью.
+	// Записи сохраняются для статистики воронки
+	AnonymizeRelated(id string) error
+	// Delete удаление кандидата вместе со связанными записями (интервью, офферы, оценки, анкеты)
+	Delete(spaceID, id string) error
 }
 
 func NewInstance(DB *gorm.DB) Provider {
@@ -94,6 +110,35 @@ func (i impl) GetByID(spaceID, id string) (*dbmodels.ApplicantExt, error) {
 	return &rec, nil
 }
 
+func (i impl) IsVisible(spaceID, id string, scope *dbmodels.DataScope) (bool, error) {
+	var exists bool
+	tx := i.db.
+		Model(&dbmodels.Applicant{}).
+		Select("count(*) > 0").
+		Where("applicants.id = ?", id).
+		Where("applicants.space_id = ?", spaceID)
+	i.addScope(tx, scope)
+	err := tx.Find(&exists).Error
+	return exists, err
+}
+
+func (i impl) ListVisibleIDs(spaceID string, scope *dbmodels.DataScope, ids []string) ([]string, error) {
+	result := []string{}
+	if len(ids) == 0 {
+		return result, nil
+	}
+	tx := i.db.
+		Model(&dbmodels.Applicant{}).
+		Where("applicants.id in (?)", ids).
+		Where("applicants.space_id = ?", spaceID)
+	i.addScope(tx, scope)
+	err := tx.Pluck("applicants.id", &result).Error
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (i impl) IsExistNegotiationID(spaceID, negotiationID string, source models.ApplicantSource) (found bool, err error) {
 	var exists bool
 	err = i.db.Model(&dbmodels.Applicant{}).
@@ -105,7 +150,7 @@ func (i impl) IsExistNegotiationID(spaceID, negotiationID string, source models.
 	return exists, err
 }
 
-func (i impl) ListOfNegotiation(spaceID string, filter dbmodels.NegotiationFilter) (list []dbmodels.Applicant, err error) {
+func (i impl) ListOfNegotiation(spaceID string, scope *dbmodels.DataScope, filter dbmodels.NegotiationFilter) (list []dbmodels.Applicant, err error) {
 	list = []dbmodels.Applicant{}
 	tx := i.db.
 		Select("applicants.*").
@@ -115,6 +160,7 @@ func (i impl) ListOfNegotiation(spaceID string, filter dbmodels.NegotiationFilte
 		Where("applicants.vacancy_id = ?", filter.VacancyID).
 		Where("(applicants.negotiation_id is not null and applicants.negotiation_id <> '')").
 		Where("applicants.status != ?", models.ApplicantStatusArchive)
+	i.addScope(tx, scope)
 	i.addNegotiationFilter(tx, filter)
 	err = tx.Preload(clause.Associations).Preload("SelectionStage").Find(&list).Error
 
@@ -127,7 +173,7 @@ func (i impl) ListOfNegotiation(spaceID string, filter dbmodels.NegotiationFilte
 	return list, nil
 }
 
-func (i impl) ListOfApplicant(spaceID string, filter applicantapimodels.ApplicantFilter) (list []dbmodels.Applicant, err error) {
+func (i impl) ListOfApplicant(spaceID string, scope *dbmodels.DataScope, filter applicantapimodels.ApplicantFilter) (list []dbmodels.Applicant, err error) {
 	list = []dbmodels.Applicant{}
 	tx := i.db.
 		Select("applicants.*, (last_name || ' ' || first_name|| ' ' || middle_name) as fio").
@@ -135,6 +181,7 @@ func (i impl) ListOfApplicant(spaceID string, filter applicantapimodels.Applican
 		Where("applicants.space_id = ?", spaceID).
 		Joins("left join vacancies as v on vacancy_id = v.id").
 		Joins("left join selection_stages as st on selection_stage_id = st.id")
+	i.addScope(tx, scope)
 	i.addApplicantFilter(tx, filter)
 	i.addSort(tx, filter.Sort)
 	page, limit := filter.GetPage()
@@ -154,13 +201,14 @@ func (i impl) ListOfApplicant(spaceID string, filter applicantapimodels.Applican
 	return list, nil
 }
 
-func (i impl) ListCountOfApplicant(spaceID string, filter applicantapimodels.ApplicantFilter) (count int64, err error) {
+func (i impl) ListCountOfApplicant(spaceID string, scope *dbmodels.DataScope, filter applicantapimodels.ApplicantFilter) (count int64, err error) {
 	var rowCount int64
 	tx := i.db.
 		Model(dbmodels.Applicant{}).
 		Joins("left join vacancies as v on vacancy_id = v.id").
 		Joins("left join selection_stages as st on selection_stage_id = st.id").
 		Where("applicants.space_id = ?", spaceID)
+	i.addScope(tx, scope)
 	i.addApplicantFilter(tx, filter)
 	err = tx.Count(&rowCount).Error
 	if err != nil {
@@ -170,28 +218,17 @@ func (i impl) ListCountOfApplicant(spaceID string, filter applicantapimodels.App
 	return rowCount, nil
 }
 
-func (i impl) ListOfDuplicateApplicant(spaceID string, filter dbmodels.DuplicateApplicantFilter) (list []dbmodels.Applicant, err error) {
+func (i impl) ListForDuplicateCheck(spaceID, vacancyID string) (list []dbmodels.Applicant, err error) {
 	list = []dbmodels.Applicant{}
-	tx := i.db.
+	err = i.db.
 		Model(dbmodels.Applicant{}).
+		Select("id, space_id, vacancy_id, first_name, last_name, middle_name, phone, email, birth_date, resume_id, ext_applicant_id, duplicate_id, not_duplicates").
 		Where("space_id = ?", spaceID).
 		Where("status != ?", models.ApplicantStatusArchive).
-		Where("vacancy_id = ?", filter.VacancyID).
-		Where("LOWER(last_name || ' ' || first_name|| ' ' || middle_name) = ?", strings.ToLower(filter.FIO))
-	if filter.ExtApplicantID != "" {
-		tx.Or("ext_applicant_id = ?", filter.ExtApplicantID)
-	}
-	if filter.Phone != "" {
-		tx.Or("phone = ?", filter.Phone)
-	}
-	if filter.Email != "" {
-		tx.Or("email = ?", filter.Email)
-	}
-	err = tx.Find(&list).Error
+		Where("vacancy_id = ?", vacancyID).
+		Find(&list).
+		Error
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, nil
-		}
 		return nil, err
 	}
 	return list, nil
@@ -212,7 +249,7 @@ func (i impl) ApplicantsByStages(spaceID string, vacancyIDs []string) (list []db
 	return list, nil
 }
 
-func (i impl) ListOfApplicantByIDs(spaceID string, ids []string, filter *applicantapimodels.ApplicantFilter) (list []dbmodels.ApplicantWithJob, err error) {
+func (i impl) ListOfApplicantByIDs(spaceID string, scope *dbmodels.DataScope, ids []string, filter *applicantapimodels.ApplicantFilter) (list []dbmodels.ApplicantWithJob, err error) {
 	if len(ids) == 0 && filter == nil {
 		return nil, nil
 	}
@@ -224,6 +261,7 @@ func (i impl) ListOfApplicantByIDs(spaceID string, ids []string, filter *applica
 		Joins("left join job_titles as jt on v.job_title_id = jt.id").
 		Joins("left join selection_stages as st on selection_stage_id = st.id").
 		Where("applicants.space_id = ?", spaceID)
+	i.addScope(tx, scope)
 	if len(ids) > 0 {
 		tx = tx.Where("applicants.id in (?)", ids)
 	} else {
@@ -241,7 +279,7 @@ func (i impl) ListOfApplicantByIDs(spaceID string, ids []string, filter *applica
 	return list, nil
 }
 
-func (i impl) ListOfApplicantSource(spaceID string, filter applicantapimodels.ApplicantFilter) ([]dbmodels.ApplicantSource, error) {
+func (i impl) ListOfApplicantSource(spaceID string, scope *dbmodels.DataScope, filter applicantapimodels.ApplicantFilter) ([]dbmodels.ApplicantSource, error) {
 	list := []dbmodels.ApplicantSource{}
 	tx := i.db.
 		Select("count(*) as total, applicants.source, (negotiation_id is not null and negotiation_id <> '') as is_negotiation").
@@ -250,7 +288,7 @@ func (i impl) ListOfApplicantSource(spaceID string, filter applicantapimodels.Ap
 		Joins("left join job_titles as jt on v.job_title_id = jt.id").
 		Joins("left join selection_stages as st on selection_stage_id = st.id").
 		Where("applicants.space_id = ?", spaceID)
-
+	i.addScope(tx, scope)
 	i.addApplicantFilter(tx, filter)
 	tx.Group("applicants.source")
 	tx.Group("is_negotiation")
@@ -263,13 +301,16 @@ func (i impl) ListOfApplicantSource(spaceID string, filter applicantapimodels.Ap
 	return list, nil
 }
 
-func (i impl) ListOfActiveApplicants() ([]dbmodels.Applicant, error) {
+func (i impl) ListOfActiveApplicants(sources []models.ApplicantSource) ([]dbmodels.Applicant, error) {
 	list := []dbmodels.Applicant{}
+	if len(sources) == 0 {
+		return list, nil
+	}
 	tx := i.db.
 		Model(dbmodels.Applicant{}).
 		Where("applicants.status in (?)", []models.ApplicantStatus{models.ApplicantStatusNegotiation, models.ApplicantStatusInProcess}).
 		Where("applicants.negotiation_status <> ?", models.NegotiationStatusRejected).
-		Where("applicants.source in (?)", []models.ApplicantSource{models.ApplicantSourceAvito, models.ApplicantSourceHh})
+		Where("applicants.source in (?)", sources)
 	err := tx.Preload(clause.Associations).Find(&list).Error
 
 	if err != nil {
@@ -313,6 +354,50 @@ func (i impl) ListForSurveySend() ([]dbmodels.Applicant, error) {
 	return list, nil
 }
 
+// ListByEmail список кандидатов спейса с указанным емайлом, последние добавленные первыми
+func (i impl) ListByEmail(spaceID, email string) ([]dbmodels.Applicant, error) {
+	list := []dbmodels.Applicant{}
+	if email == "" {
+		return list, nil
+	}
+	err := i.db.
+		Model(dbmodels.Applicant{}).
+		Where("space_id = ?", spaceID).
+		Where("lower(email) = ?", strings.ToLower(email)).
+		Order("created_at desc").
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// ListForStageLimit кандидаты в работе на этапах с лимитом времени
+func (i impl) ListForStageLimit() ([]dbmodels.ApplicantStageLimit, error) {
+	list := []dbmodels.ApplicantStageLimit{}
+	err := i.db.
+		Select("applicants.*, st.name as stage_name, st.limit_value, st.limit_type, "+
+			"COALESCE((select max(h.created_at) from applicant_histories h where h.applicant_id = applicants.id and h.action_type = ?), "+
+			"applicants.negotiation_accept_date) as stage_start_at", dbmodels.HistoryTypeStageChange).
+		Model(dbmodels.Applicant{}).
+		Joins("join selection_stages as st on selection_stage_id = st.id").
+		Where("applicants.status = ?", models.ApplicantStatusInProcess).
+		Where("st.limit_value > 0").
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (i impl) addScope(tx *gorm.DB, scope *dbmodels.DataScope) {
+	if query, args := scope.ApplicantCondition("applicants"); query != "" {
+		tx.Where(query, args)
+	}
+}
+
 func (i impl) addApplicantFilter(tx *gorm.DB, filter applicantapimodels.ApplicantFilter) {
 	if filter.VacancyID != "" {
 		tx.Where("applicants.vacancy_id = ?", filter.VacancyID)
@@ -415,6 +500,16 @@ func (i impl) addApplicantFilter(tx *gorm.DB, filter applicantapimodels.Applican
 		jWhere := fmt.Sprintf("(params->'languages')::jsonb @> '[{\"name\":\"%v\"}]'", filter.Language)
 		tx.Where(jWhere)
 	}
+	if filter.StageOverdue != nil {
+		if *filter.StageOverdue {
+			tx.Where("applicants.stage_overdue_at is not null and applicants.status = ?", models.ApplicantStatusInProcess)
+		} else {
+			tx.Where("applicants.stage_overdue_at is null")
+		}
+	}
+	if query, args := filter.CustomFields.Condition("applicants.custom_fields"); query != "" {
+		tx.Where(query, args)
+	}
 }
 
 func (i impl) addNegotiationFilter(tx *gorm.DB, filter dbmodels.NegotiationFilter) {
@@ -584,6 +679,12 @@ func (i impl) addSort(tx *gorm.DB, sort applicantapimodels.ApplicantSort) {
 	if sort.StatusDesc != nil {
 		specifyOrder(tx, "applicants.status", *sort.StatusDesc)
 	}
+
+	if sort.StageDeadlineDesc != nil {
+		// кандидаты без срока на этапе в конце списка
+		tx.Order("applicants.stage_deadline is null")
+		specifyOrder(tx, "applicants.stage_deadline", *sort.StageDeadlineDesc)
+	}
 }
 
 func specifyOrder(tx *gorm.DB, fieldName string, isDesc bool) {
@@ -593,3 +694,183 @@ func specifyOrder(tx *gorm.DB, fieldName string, isDesc bool) {
 	}
 	tx = tx.Order(fmt.Sprintf("%v  asc", fieldName))
 }
+
+// ListForCandidateLink кандидаты без привязки к кандидату (человеку), сначала основные записи, затем дубли
+func (i impl) ListForCandidateLink(limit int) ([]dbmodels.Applicant, error) {
+	list := []dbmodels.Applicant{}
+	err := i.db.
+		Model(dbmodels.Applicant{}).
+		Where("candidate_id IS NULL").
+		Where("anonymized_at IS NULL").
+		Order("duplicate_id IS NOT NULL").
+		Order("created_at").
+		Limit(limit).
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// ListForRetention отклоненные и архивные кандидаты с истекшим сроком хранения персональных данных
+func (i impl) ListForRetention(spaceID string, before time.Time, limit int) ([]dbmodels.Applicant, error) {
+	list := []dbmodels.Applicant{}
+	err := i.db.
+		Model(dbmodels.Applicant{}).
+		Where("space_id = ?", spaceID).
+		Where("status in (?)", []models.ApplicantStatus{models.ApplicantStatusRejected, models.ApplicantStatusArchive}).
+		Where("updated_at < ?", before).
+		Where("anonymized_at IS NULL").
+		Order("updated_at").
+		Limit(limit).
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (i impl) Delete(spaceID, id string) error {
+	err := i.db.
+		Model(&dbmodels.Applicant{}).
+		Where("space_id = ?", spaceID).
+		Where("duplicate_id = ?", id).
+		Update("duplicate_id", nil).
+		Error
+	if err != nil {
+		return errors.Wrap(err, "ошибка снятия связи с дубликатами")
+	}
+	interviewIDs := i.db.Model(&dbmodels.Interview{}).Select("id").Where("applicant_id = ?", id)
+	err = i.db.Where("interview_id in (?)", interviewIDs).Delete(&dbmodels.InterviewParticipant{}).Error
+	if err != nil {
+		return errors.Wrap(err, "ошибка удаления участников интервью")
+	}
+	err = i.db.Where("interview_id in (?)", interviewIDs).Delete(&dbmodels.InterviewCalendarEvent{}).Error
+	if err != nil {
+		return errors.Wrap(err, "ошибка удаления событий календаря")
+	}
+	// занятые интервью кандидата слоты освобождаются
+	err = i.db.
+		Model(&dbmodels.InterviewSlot{}).
+		Where("interview_id in (?)", interviewIDs).
+		Update("interview_id", nil).
+		Error
+	if err != nil {
+		return errors.Wrap(err, "ошибка освобождения слотов интервью")
+	}
+	offerIDs := i.db.Model(&dbmodels.Offer{}).Select("id").Where("applicant_id = ?", id)
+	err = i.db.Where("offer_id in (?)", offerIDs).Delete(&dbmodels.OfferVersion{}).Error
+	if err != nil {
+		return errors.Wrap(err, "ошибка удаления версий оффера")
+	}
+	err = i.DeleteArtifacts(id)
+	if err != nil {
+		return err
+	}
+	err = i.deleteRelated(id,
+		&dbmodels.Interview{},
+		&dbmodels.InterviewBooking{},
+		&dbmodels.Offer{},
+		&dbmodels.Scorecard{},
+		&dbmodels.ApplicantHistory{},
+		&dbmodels.AutomationLog{},
+		&dbmodels.PersonalDataConsent{},
+	)
+	if err != nil {
+		return err
+	}
+	err = i.db.
+		Where("space_id = ?", spaceID).
+		Where("id = ?", id).
+		Delete(&dbmodels.Applicant{}).
+		Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (i impl) DeleteArtifacts(id string) error {
+	vkStepIDs := i.db.Model(&dbmodels.ApplicantVkStep{}).Select("id").Where("applicant_id = ?", id)
+	err := i.db.Where("applicant_vk_step_id in (?)", vkStepIDs).Delete(&dbmodels.ApplicantVkVideoSurvey{}).Error
+	if err != nil {
+		return errors.Wrap(err, "ошибка удаления результатов видео-интервью")
+	}
+	return i.deleteRelated(id,
+		&dbmodels.ApplicantSurvey{},
+		&dbmodels.ApplicantVkStep{},
+		&dbmodels.MasaiSession{},
+		&dbmodels.ApplicantResumeSuggestion{},
+	)
+}
+
+func (i impl) AnonymizeRelated(id string) error {
+	err := i.db.
+		Model(&dbmodels.Interview{}).
+		Where("applicant_id = ?", id).
+		Updates(map[string]interface{}{
+			"location": "",
+			"comment":  "",
+		}).
+		Error
+	if err != nil {
+		return errors.Wrap(err, "ошибка обезличивания интервью")
+	}
+	offerIDs := i.db.Model(&dbmodels.Offer{}).Select("id").Where("applicant_id = ?", id)
+	err = i.db.
+		Model(&dbmodels.OfferVersion{}).
+		Where("offer_id in (?)", offerIDs).
+		Updates(map[string]interface{}{
+			"salary":     0,
+			"conditions": "",
+		}).
+		Error
+	if err != nil {
+		return errors.Wrap(err, "ошибка обезличивания версий оффера")
+	}
+	err = i.db.
+		Model(&dbmodels.Offer{}).
+		Where("applicant_id = ?", id).
+		Updates(map[string]interface{}{
+			"salary":           0,
+			"conditions":       "",
+			"decline_reason":   "",
+			"document_file_id": "",
+		}).
+		Error
+	if err != nil {
+		return errors.Wrap(err, "ошибка обезличивания офферов")
+	}
+	// оценки по компетенциям сохраняются для статистики, комментарии удаляются
+	scorecards := []dbmodels.Scorecard{}
+	err = i.db.Where("applicant_id = ?", id).Find(&scorecards).Error
+	if err != nil {
+		return errors.Wrap(err, "ошибка получения оценочных карт")
+	}
+	for _, scorecard := range scorecards {
+		err = i.db.
+			Model(&dbmodels.Scorecard{}).
+			Where("id = ?", scorecard.ID).
+			Updates(map[string]interface{}{
+				"ratings": scorecard.Ratings.WithoutComments(),
+				"comment": "",
+			}).
+			Error
+		if err != nil {
+			return errors.Wrap(err, "ошибка обезличивания оценочных карт")
+		}
+	}
+	return i.deleteRelated(id, &dbmodels.InterviewBooking{})
+}
+
+func (i impl) deleteRelated(id string, recs ...interface{}) error {
+	for _, model := range recs {
+		err := i.db.Where("applicant_id = ?", id).Delete(model).Error
+		if err != nil {
+			return errors.Wrapf(err, "ошибка удаления связанных записей (%T)", model)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package approvaldelegatestore
+
+import (
+	dbmodels "hr-tools-backend/models/db"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Provider interface {
+	Save(spaceID, userID, delegateUserID string) error
+	Delete(spaceID, userID string) error
+	GetByUser(spaceID, userID string) (*dbmodels.ApprovalDelegate, error)
+	List(spaceID string) ([]dbmodels.ApprovalDelegate, error)
+}
+
+func NewInstance(DB *gorm.DB) Provider {
+	return &impl{
+		db: DB,
+	}
+}
+
+type impl struct {
+	db *gorm.DB
+}
+
+func (i impl) Save(spaceID, userID, delegateUserID string) error {
+	rec, err := i.GetByUser(spaceID, userID)
+	if err != nil {
+		return err
+	}
+	if rec != nil {
+		return i.db.
+			Model(&dbmodels.ApprovalDelegate{}).
+			Where("id = ?", rec.ID).
+			Update("delegate_user_id", delegateUserID).
+			Error
+	}
+	newRec := dbmodels.ApprovalDelegate{
+		BaseSpaceModel: dbmodels.BaseSpaceModel{
+			SpaceID: spaceID,
+		},
+		UserID:         userID,
+		DelegateUserID: delegateUserID,
+	}
+	return i.db.
+		Omit(clause.Associations).
+		Save(&newRec).
+		Error
+}
+
+func (i impl) Delete(spaceID, userID string) error {
+	return i.db.
+		Where("space_id = ?", spaceID).
+		Where("user_id = ?", userID).
+		Delete(&dbmodels.ApprovalDelegate{}).
+		Error
+}
+
+func (i impl) GetByUser(spaceID, userID string) (*dbmodels.ApprovalDelegate, error) {
+	rec := dbmodels.ApprovalDelegate{}
+	err := i.db.
+		Preload("DelegateUser").
+		Where("space_id = ?", spaceID).
+		Where("user_id = ?", userID).
+		First(&rec).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (i impl) List(spaceID string) ([]dbmodels.ApprovalDelegate, error) {
+	list := []dbmodels.ApprovalDelegate{}
+	err := i.db.
+		Preload("User").
+		Preload("DelegateUser").
+		Where("space_id = ?", spaceID).
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
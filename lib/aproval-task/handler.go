@@ -6,7 +6,9 @@ import (
 	log "github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 	"hr-tools-backend/db"
+	approvaldelegatestore "hr-tools-backend/lib/aproval-task/delegate-store"
 	approvaltaskhistorystore "hr-tools-backend/lib/aproval-task/history-store"
+	approvalroutestore "hr-tools-backend/lib/aproval-task/route-store"
 	approvaltaskstore "hr-tools-backend/lib/aproval-task/store"
 	spaceusersstore "hr-tools-backend/lib/space/users/store"
 	initchecker "hr-tools-backend/lib/utils/init-checker"
@@ -21,6 +23,14 @@ type Provider interface {
 	History(spaceID, requestID string) ([]vacancyapimodels.ApprovalHistoryView, error)
 	Audit(data dbmodels.ApprovalTask)
 	AuditCommon(rec dbmodels.ApprovalHistory)
+	// ApplyRoute формирование цепочки согласования заявки по шаблону маршрута, пустой routeID - подбор маршрута по заявке
+	ApplyRoute(spaceID string, rec dbmodels.VacancyRequest, routeID string) (hMsg string, err error)
+	// Start запуск согласования: предыдущие решения аннулируются, активируется первый шаг
+	Start(spaceID, requestID string) (activated []dbmodels.ApprovalTask, err error)
+	// Approve согласование задачи, при наборе кворума шага активируется следующий шаг
+	Approve(task dbmodels.ApprovalTask) (completed bool, activated []dbmodels.ApprovalTask, err error)
+	// Escalate передача просроченной задачи ответственному за эскалацию, пустой newAssigneeID - передавать некому
+	Escalate(task dbmodels.ApprovalTask) (newAssigneeID string, err error)
 }
 
 var Instance Provider
@@ -30,11 +40,15 @@ func NewHandler() {
 		store:                approvaltaskstore.NewInstance(db.DB),
 		spaceUsersStore:      spaceusersstore.NewInstance(db.DB),
 		approvalHistoryStore: approvaltaskhistorystore.NewInstance(db.DB),
+		routeStore:           approvalroutestore.NewInstance(db.DB),
+		delegateStore:        approvaldelegatestore.NewInstance(db.DB),
 	}
 	initchecker.CheckInit(
 		"store", instance.store,
 		"spaceUsersStore", instance.spaceUsersStore,
 		"approvalHistoryStore", instance.approvalHistoryStore,
+		"routeStore", instance.routeStore,
+		"delegateStore", instance.delegateStore,
 	)
 	Instance = instance
 }
@@ -44,6 +58,8 @@ func NewHandlerWithTx(tx *gorm.DB) Provider {
 		approvalHistoryStore: approvaltaskhistorystore.NewInstance(tx),
 		store:                approvaltaskstore.NewInstance(tx),
 		spaceUsersStore:      spaceusersstore.NewInstance(tx),
+		routeStore:           approvalroutestore.NewInstance(tx),
+		delegateStore:        approvaldelegatestore.NewInstance(tx),
 	}
 }
 
@@ -51,6 +67,8 @@ type impl struct {
 	store                approvaltaskstore.Provider
 	spaceUsersStore      spaceusersstore.Provider
 	approvalHistoryStore approvaltaskhistorystore.Provider
+	routeStore           approvalroutestore.Provider
+	delegateStore        approvaldelegatestore.Provider
 }
 
 func (i impl) GetLogger(spaceID, requestID string) *log.Entry {
@@ -75,6 +93,8 @@ func (i impl) Get(spaceID, requestID string) ([]vacancyapimodels.ApprovalTaskVie
 func (i impl) Save(spaceID, requestID string, stages []vacancyapimodels.ApprovalTaskData) (hMsg string, err error) {
 	usersMap := map[string]int{}                     //0-оставить/1-добавить/-1 удалить
 	currentMap := map[string]dbmodels.ApprovalTask{} //[userid]rec
+	stepMap := map[string]int{}                      //[userid]step
+	firstStep := -1
 	currentList, err := i.store.List(spaceID, requestID)
 	if err != nil {
 		return "", err
@@ -93,6 +113,10 @@ func (i impl) Save(spaceID, requestID string, stages []vacancyapimodels.Approval
 			return fmt.Sprintf("Сотрудник %v, не найден в справочнике сотрудников", stage.AssigneeUserID), nil
 		}
 
+		stepMap[stage.AssigneeUserID] = stage.Step
+		if firstStep < 0 || stage.Step < firstStep {
+			firstStep = stage.Step
+		}
 		what, ok := usersMap[stage.AssigneeUserID]
 		if ok {
 			if what < 0 {
@@ -116,6 +140,19 @@ func (i impl) Save(spaceID, requestID string, stages []vacancyapimodels.Approval
 				currentRec.State = models.AStateRemoved
 				i.Audit(currentRec)
 			}
+		case 0: // у оставшихся мог измениться шаг
+			currentRec := currentMap[userID]
+			if currentRec.Step == stepMap[userID] {
+				continue
+			}
+			updMap := map[string]interface{}{
+				"Step":  stepMap[userID],
+				"State": getInitialState(stepMap[userID], firstStep),
+			}
+			err = i.store.Update(spaceID, currentRec.ID, updMap)
+			if err != nil {
+				return "", errors.Wrapf(err, "Ошибка сохранения цепочки согласования, stage=%+v", currentRec)
+			}
 		case 1: // добавляем новых
 			rec := dbmodels.ApprovalTask{
 				BaseSpaceModel: dbmodels.BaseSpaceModel{
@@ -123,7 +160,8 @@ func (i impl) Save(spaceID, requestID string, stages []vacancyapimodels.Approval
 				},
 				RequestID:      requestID,
 				AssigneeUserID: userID,
-				State:          models.AStatePending,
+				State:          getInitialState(stepMap[userID], firstStep),
+				Step:           stepMap[userID],
 			}
 			recID, err := i.store.Create(rec)
 			if err != nil {
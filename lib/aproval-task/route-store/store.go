@@ -0,0 +1,109 @@
+package approvalroutestore
+
+import (
+	dbmodels "hr-tools-backend/models/db"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Provider interface {
+	Create(rec dbmodels.ApprovalRoute) (id string, err error)
+	Update(spaceID, id string, updMap map[string]interface{}) error
+	GetByID(spaceID, id string) (*dbmodels.ApprovalRoute, error)
+	List(spaceID string) ([]dbmodels.ApprovalRoute, error)
+	ListActive(spaceID string) ([]dbmodels.ApprovalRoute, error)
+	Delete(spaceID, id string) error
+}
+
+func NewInstance(DB *gorm.DB) Provider {
+	return &impl{
+		db: DB,
+	}
+}
+
+type impl struct {
+	db *gorm.DB
+}
+
+func (i impl) Create(rec dbmodels.ApprovalRoute) (id string, err error) {
+	err = i.db.
+		Omit(clause.Associations).
+		Save(&rec).
+		Error
+	if err != nil {
+		return "", err
+	}
+	return rec.ID, nil
+}
+
+func (i impl) Update(spaceID, id string, updMap map[string]interface{}) error {
+	if len(updMap) == 0 {
+		return nil
+	}
+	return i.db.
+		Model(&dbmodels.ApprovalRoute{}).
+		Where("space_id = ?", spaceID).
+		Where("id = ?", id).
+		Updates(updMap).
+		Error
+}
+
+func (i impl) GetByID(spaceID, id string) (*dbmodels.ApprovalRoute, error) {
+	rec := dbmodels.ApprovalRoute{}
+	err := i.preload().
+		Where("space_id = ?", spaceID).
+		Where("id = ?", id).
+		First(&rec).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (i impl) List(spaceID string) ([]dbmodels.ApprovalRoute, error) {
+	list := []dbmodels.ApprovalRoute{}
+	err := i.preload().
+		Where("space_id = ?", spaceID).
+		Order("name").
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (i impl) ListActive(spaceID string) ([]dbmodels.ApprovalRoute, error) {
+	list := []dbmodels.ApprovalRoute{}
+	err := i.db.
+		Where("space_id = ?", spaceID).
+		Where("is_active = ?", true).
+		Order("created_at").
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (i impl) Delete(spaceID, id string) error {
+	return i.db.
+		Where("space_id = ?", spaceID).
+		Where("id = ?", id).
+		Delete(&dbmodels.ApprovalRoute{}).
+		Error
+}
+
+func (i impl) preload() *gorm.DB {
+	return i.db.
+		Preload("Company").
+		Preload("Department").
+		Preload("CompanyStruct")
+}
@@ -0,0 +1,372 @@
+package aprovaltaskhandler
+
+import (
+	"fmt"
+	"hr-tools-backend/models"
+	dbmodels "hr-tools-backend/models/db"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func (i impl) ApplyRoute(spaceID string, rec dbmodels.VacancyRequest, routeID string) (hMsg string, err error) {
+	var route *dbmodels.ApprovalRoute
+	if routeID != "" {
+		route, err = i.routeStore.GetByID(spaceID, routeID)
+		if err != nil {
+			return "", errors.Wrap(err, "ошибка получения маршрута согласования")
+		}
+		if route == nil {
+			return "маршрут согласования не найден", nil
+		}
+	} else {
+		list, err := i.routeStore.ListActive(spaceID)
+		if err != nil {
+			return "", errors.Wrap(err, "ошибка получения маршрутов согласования")
+		}
+		route = selectRoute(list, rec)
+		if route == nil {
+			return "не найден маршрут согласования, подходящий для заявки", nil
+		}
+	}
+	tasks := buildRouteTasks(*route, rec)
+	if len(tasks) == 0 {
+		return "ни один шаг маршрута согласования не подходит под условия заявки", nil
+	}
+	for _, task := range tasks {
+		user, err := i.spaceUsersStore.GetByID(task.AssigneeUserID)
+		if err != nil {
+			return "", err
+		}
+		if user == nil || user.SpaceID != spaceID {
+			return fmt.Sprintf("Сотрудник %v из маршрута согласования не найден в справочнике сотрудников", task.AssigneeUserID), nil
+		}
+	}
+
+	currentList, err := i.store.List(spaceID, rec.ID)
+	if err != nil {
+		return "", err
+	}
+	for _, current := range currentList {
+		err = i.store.Delete(spaceID, current.ID)
+		if err != nil {
+			return "", err
+		}
+		current.State = models.AStateRemoved
+		i.Audit(current)
+	}
+	for _, task := range tasks {
+		task.SpaceID = spaceID
+		task.RequestID = rec.ID
+		task.ID, err = i.store.Create(task)
+		if err != nil {
+			return "", errors.Wrapf(err, "Ошибка сохранения цепочки согласования, stage=%+v", task)
+		}
+		i.Audit(task)
+	}
+	return "", nil
+}
+
+func (i impl) Start(spaceID, requestID string) (activated []dbmodels.ApprovalTask, err error) {
+	list, err := i.store.List(spaceID, requestID)
+	if err != nil {
+		return nil, err
+	}
+	firstStep := getFirstStep(list)
+	// Все предыдущие решения согласующих и передачи задач аннулируются
+	for idx := range list {
+		if list[idx].DelegatedFromUserID != "" {
+			list[idx].AssigneeUserID = list[idx].DelegatedFromUserID
+			list[idx].DelegatedFromUserID = ""
+		}
+	}
+	busy := getStepAssignees(list, firstStep)
+	activated = []dbmodels.ApprovalTask{}
+	for _, task := range list {
+		task.State = getInitialState(task.Step, firstStep)
+		task.Comment = ""
+		task.DecidedAt = nil
+		task.DueAt = nil
+		task.EscalatedAt = nil
+		if task.State == models.AStatePending {
+			task, err = i.activate(task, busy)
+			if err != nil {
+				return nil, err
+			}
+			activated = append(activated, task)
+		}
+		updMap := map[string]interface{}{
+			"AssigneeUserID":      task.AssigneeUserID,
+			"DelegatedFromUserID": task.DelegatedFromUserID,
+			"State":               task.State,
+			"Comment":             "",
+			"DecidedAt":           nil,
+			"DueAt":               task.DueAt,
+			"EscalatedAt":         nil,
+		}
+		err = i.store.Update(spaceID, task.ID, updMap)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return activated, nil
+}
+
+func (i impl) Approve(task dbmodels.ApprovalTask) (completed bool, activated []dbmodels.ApprovalTask, err error) {
+	now := time.Now()
+	updMap := map[string]interface{}{
+		"State":     models.AStateApproved,
+		"Comment":   "",
+		"DecidedAt": now,
+	}
+	err = i.store.Update(task.SpaceID, task.ID, updMap)
+	if err != nil {
+		return false, nil, err
+	}
+	// для аудита
+	task.State = models.AStateApproved
+	task.Comment = ""
+	task.DecidedAt = &now
+	i.Audit(task)
+
+	list, err := i.store.List(task.SpaceID, task.RequestID)
+	if err != nil {
+		return false, nil, err
+	}
+	approved, total := 0, 0
+	for _, item := range list {
+		if item.Step != task.Step {
+			continue
+		}
+		total++
+		if item.State == models.AStateApproved {
+			approved++
+		}
+	}
+	if !dbmodels.IsQuorumReached(task.Quorum, approved, total) {
+		return false, nil, nil
+	}
+	// кворум набран, решения остальных согласующих шага не требуются
+	for _, item := range list {
+		if item.Step != task.Step || item.State != models.AStatePending {
+			continue
+		}
+		updMap = map[string]interface{}{
+			"State": models.AStateSkipped,
+			"DueAt": nil,
+		}
+		err = i.store.Update(task.SpaceID, item.ID, updMap)
+		if err != nil {
+			return false, nil, err
+		}
+		item.State = models.AStateSkipped
+		i.Audit(item)
+	}
+	nextStep, ok := getNextStep(list, task.Step)
+	if !ok {
+		return true, nil, nil
+	}
+	busy := getStepAssignees(list, nextStep)
+	activated = []dbmodels.ApprovalTask{}
+	for _, item := range list {
+		if item.Step != nextStep || item.State != models.AStateWaiting {
+			continue
+		}
+		item.State = models.AStatePending
+		item, err = i.activate(item, busy)
+		if err != nil {
+			return false, nil, err
+		}
+		updMap = map[string]interface{}{
+			"AssigneeUserID":      item.AssigneeUserID,
+			"DelegatedFromUserID": item.DelegatedFromUserID,
+			"State":               item.State,
+			"DueAt":               item.DueAt,
+		}
+		err = i.store.Update(task.SpaceID, item.ID, updMap)
+		if err != nil {
+			return false, nil, err
+		}
+		activated = append(activated, item)
+	}
+	return false, activated, nil
+}
+
+func (i impl) Escalate(task dbmodels.ApprovalTask) (newAssigneeID string, err error) {
+	now := time.Now()
+	updMap := map[string]interface{}{
+		"EscalatedAt": now,
+	}
+	if task.EscalationUserID != "" && task.EscalationUserID != task.AssigneeUserID {
+		list, err := i.store.List(task.SpaceID, task.RequestID)
+		if err != nil {
+			return "", err
+		}
+		busy := getStepAssignees(list, task.Step)
+		assigneeID, err := i.resolveAssignee(task.SpaceID, task.EscalationUserID, busy)
+		if err != nil {
+			return "", err
+		}
+		if !busy[assigneeID] {
+			newAssigneeID = assigneeID
+			delegatedFrom := task.DelegatedFromUserID
+			if delegatedFrom == "" {
+				delegatedFrom = task.AssigneeUserID
+			}
+			updMap["AssigneeUserID"] = newAssigneeID
+			updMap["DelegatedFromUserID"] = delegatedFrom
+			if task.SlaHours > 0 {
+				updMap["DueAt"] = now.Add(time.Duration(task.SlaHours) * time.Hour)
+			}
+		}
+	}
+	err = i.store.Update(task.SpaceID, task.ID, updMap)
+	if err != nil {
+		return "", err
+	}
+	if newAssigneeID != "" {
+		i.AuditCommon(dbmodels.ApprovalHistory{
+			BaseSpaceModel: dbmodels.BaseSpaceModel{SpaceID: task.SpaceID},
+			RequestID:      task.RequestID,
+			TaskID:         task.ID,
+			AssigneeUserID: newAssigneeID,
+			State:          models.AStatePending,
+			Comment:        "Истек срок согласования, задача эскалирована",
+			Changes: dbmodels.EntityChanges{
+				Description: "Изменен согласующий",
+				Data: []dbmodels.FieldChanges{
+					{
+						Field:    "AssigneeUserID",
+						OldValue: task.AssigneeUserID,
+						NewValue: newAssigneeID,
+					},
+				},
+			},
+		})
+	}
+	return newAssigneeID, nil
+}
+
+// activate назначение задачи активного шага: передача заместителю отсутствующего согласующего и срок согласования
+func (i impl) activate(task dbmodels.ApprovalTask, busy map[string]bool) (dbmodels.ApprovalTask, error) {
+	assigneeID, err := i.resolveAssignee(task.SpaceID, task.AssigneeUserID, busy)
+	if err != nil {
+		return task, err
+	}
+	if assigneeID != task.AssigneeUserID {
+		task.DelegatedFromUserID = task.AssigneeUserID
+		task.AssigneeUserID = assigneeID
+		busy[assigneeID] = true
+		// для аудита
+		auditRec := task
+		auditRec.Comment = "Согласующий отсутствует, задача передана заместителю"
+		i.Audit(auditRec)
+	}
+	if task.SlaHours > 0 {
+		dueAt := time.Now().Add(time.Duration(task.SlaHours) * time.Hour)
+		task.DueAt = &dueAt
+	}
+	return task, nil
+}
+
+// resolveAssignee заместитель, если сотрудник не работает (отпуск/увольнение) и заместитель доступен
+func (i impl) resolveAssignee(spaceID, userID string, busy map[string]bool) (string, error) {
+	user, err := i.spaceUsersStore.GetByID(userID)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения согласующего")
+	}
+	if isAvailable(user) {
+		return userID, nil
+	}
+	delegate, err := i.delegateStore.GetByUser(spaceID, userID)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения заместителя согласующего")
+	}
+	if delegate == nil || busy[delegate.DelegateUserID] || !isAvailable(delegate.DelegateUser) {
+		return userID, nil
+	}
+	return delegate.DelegateUserID, nil
+}
+
+func isAvailable(user *dbmodels.SpaceUser) bool {
+	if user == nil || user.DeletedAt != nil {
+		return false
+	}
+	return user.Status == "" || user.Status == models.SpaceWorkingStatus
+}
+
+// selectRoute наиболее точно совпадающий с заявкой маршрут
+func selectRoute(list []dbmodels.ApprovalRoute, rec dbmodels.VacancyRequest) *dbmodels.ApprovalRoute {
+	var result *dbmodels.ApprovalRoute
+	bestScore := -1
+	for idx, route := range list {
+		score := route.Match(rec)
+		if score > bestScore {
+			bestScore = score
+			result = &list[idx]
+		}
+	}
+	return result
+}
+
+// buildRouteTasks задачи согласования по шагам маршрута, подходящим под условия заявки
+func buildRouteTasks(route dbmodels.ApprovalRoute, rec dbmodels.VacancyRequest) []dbmodels.ApprovalTask {
+	result := []dbmodels.ApprovalTask{}
+	step := 0
+	for _, routeStep := range route.Steps {
+		if !routeStep.IsApplicable(rec) {
+			continue
+		}
+		for _, userID := range routeStep.Approvers {
+			result = append(result, dbmodels.ApprovalTask{
+				AssigneeUserID:   userID,
+				State:            getInitialState(step, 0),
+				Step:             step,
+				StepName:         routeStep.Name,
+				Quorum:           routeStep.Quorum,
+				SlaHours:         routeStep.SlaHours,
+				EscalationUserID: routeStep.EscalationUserID,
+			})
+		}
+		step++
+	}
+	return result
+}
+
+func getInitialState(step, firstStep int) models.ApprovalState {
+	if step > firstStep {
+		return models.AStateWaiting
+	}
+	return models.AStatePending
+}
+
+func getFirstStep(list []dbmodels.ApprovalTask) int {
+	firstStep := 0
+	for idx, task := range list {
+		if idx == 0 || task.Step < firstStep {
+			firstStep = task.Step
+		}
+	}
+	return firstStep
+}
+
+func getNextStep(list []dbmodels.ApprovalTask, step int) (int, bool) {
+	nextStep, found := 0, false
+	for _, task := range list {
+		if task.Step > step && (!found || task.Step < nextStep) {
+			nextStep = task.Step
+			found = true
+		}
+	}
+	return nextStep, found
+}
+
+func getStepAssignees(list []dbmodels.ApprovalTask, step int) map[string]bool {
+	result := map[string]bool{}
+	for _, task := range list {
+		if task.Step == step {
+			result[task.AssigneeUserID] = true
+		}
+	}
+	return result
+}
@@ -0,0 +1,208 @@
+package approvalroutehandler
+
+import (
+	"fmt"
+	"hr-tools-backend/db"
+	approvaldelegatestore "hr-tools-backend/lib/aproval-task/delegate-store"
+	approvalroutestore "hr-tools-backend/lib/aproval-task/route-store"
+	spaceusersstore "hr-tools-backend/lib/space/users/store"
+	initchecker "hr-tools-backend/lib/utils/init-checker"
+	vacancyapimodels "hr-tools-backend/models/api/vacancy"
+	dbmodels "hr-tools-backend/models/db"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+type Provider interface {
+	Create(spaceID string, data vacancyapimodels.ApprovalRouteData) (id, hMsg string, err error)
+	Update(spaceID, id string, data vacancyapimodels.ApprovalRouteData) (hMsg string, err error)
+	Get(spaceID, id string) (item *vacancyapimodels.ApprovalRouteView, err error)
+	List(spaceID string) (list []vacancyapimodels.ApprovalRouteView, err error)
+	Delete(spaceID, id string) error
+	// заместители согласующих
+	SaveDelegate(spaceID string, data vacancyapimodels.ApprovalDelegateData) (hMsg string, err error)
+	DeleteDelegate(spaceID, userID string) error
+	ListDelegates(spaceID string) (list []vacancyapimodels.ApprovalDelegateView, err error)
+}
+
+var Instance Provider
+
+func NewHandler() {
+	instance := impl{
+		store:           approvalroutestore.NewInstance(db.DB),
+		delegateStore:   approvaldelegatestore.NewInstance(db.DB),
+		spaceUsersStore: spaceusersstore.NewInstance(db.DB),
+	}
+	initchecker.CheckInit(
+		"store", instance.store,
+		"delegateStore", instance.delegateStore,
+		"spaceUsersStore", instance.spaceUsersStore,
+	)
+	Instance = instance
+}
+
+type impl struct {
+	store           approvalroutestore.Provider
+	delegateStore   approvaldelegatestore.Provider
+	spaceUsersStore spaceusersstore.Provider
+}
+
+func (i impl) Create(spaceID string, data vacancyapimodels.ApprovalRouteData) (id, hMsg string, err error) {
+	hMsg, err = i.checkUsers(spaceID, data)
+	if err != nil || hMsg != "" {
+		return "", hMsg, err
+	}
+	rec := dbmodels.ApprovalRoute{
+		BaseSpaceModel: dbmodels.BaseSpaceModel{
+			SpaceID: spaceID,
+		},
+		Name:            data.Name,
+		CompanyID:       getNullable(data.CompanyID),
+		DepartmentID:    getNullable(data.DepartmentID),
+		CompanyStructID: getNullable(data.CompanyStructID),
+		IsActive:        data.IsActive,
+		Steps:           data.Steps,
+	}
+	id, err = i.store.Create(rec)
+	if err != nil {
+		return "", "", err
+	}
+	log.WithField("space_id", spaceID).
+		WithField("rec_id", id).
+		Info("создан маршрут согласования")
+	return id, "", nil
+}
+
+func (i impl) Update(spaceID, id string, data vacancyapimodels.ApprovalRouteData) (hMsg string, err error) {
+	rec, err := i.store.GetByID(spaceID, id)
+	if err != nil {
+		return "", err
+	}
+	if rec == nil {
+		return "маршрут согласования не найден", nil
+	}
+	hMsg, err = i.checkUsers(spaceID, data)
+	if err != nil || hMsg != "" {
+		return hMsg, err
+	}
+	updMap := map[string]interface{}{
+		"Name":            data.Name,
+		"CompanyID":       getNullable(data.CompanyID),
+		"DepartmentID":    getNullable(data.DepartmentID),
+		"CompanyStructID": getNullable(data.CompanyStructID),
+		"IsActive":        data.IsActive,
+		"Steps":           dbmodels.ApprovalRouteSteps(data.Steps),
+	}
+	err = i.store.Update(spaceID, id, updMap)
+	if err != nil {
+		return "", err
+	}
+	log.WithField("space_id", spaceID).
+		WithField("rec_id", id).
+		Info("обновлен маршрут согласования")
+	return "", nil
+}
+
+func (i impl) Get(spaceID, id string) (item *vacancyapimodels.ApprovalRouteView, err error) {
+	rec, err := i.store.GetByID(spaceID, id)
+	if err != nil {
+		return nil, err
+	}
+	if rec == nil {
+		return nil, nil
+	}
+	result := vacancyapimodels.ApprovalRouteConvert(*rec)
+	return &result, nil
+}
+
+func (i impl) List(spaceID string) (list []vacancyapimodels.ApprovalRouteView, err error) {
+	recList, err := i.store.List(spaceID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]vacancyapimodels.ApprovalRouteView, 0, len(recList))
+	for _, rec := range recList {
+		result = append(result, vacancyapimodels.ApprovalRouteConvert(rec))
+	}
+	return result, nil
+}
+
+func (i impl) Delete(spaceID, id string) error {
+	err := i.store.Delete(spaceID, id)
+	if err != nil {
+		return err
+	}
+	log.WithField("space_id", spaceID).
+		WithField("rec_id", id).
+		Info("удален маршрут согласования")
+	return nil
+}
+
+func (i impl) SaveDelegate(spaceID string, data vacancyapimodels.ApprovalDelegateData) (hMsg string, err error) {
+	for _, userID := range []string{data.UserID, data.DelegateUserID} {
+		hMsg, err = i.checkUser(spaceID, userID)
+		if err != nil || hMsg != "" {
+			return hMsg, err
+		}
+	}
+	err = i.delegateStore.Save(spaceID, data.UserID, data.DelegateUserID)
+	if err != nil {
+		return "", err
+	}
+	log.WithField("space_id", spaceID).
+		WithField("user_id", data.UserID).
+		WithField("delegate_user_id", data.DelegateUserID).
+		Info("назначен заместитель согласующего")
+	return "", nil
+}
+
+func (i impl) DeleteDelegate(spaceID, userID string) error {
+	return i.delegateStore.Delete(spaceID, userID)
+}
+
+func (i impl) ListDelegates(spaceID string) (list []vacancyapimodels.ApprovalDelegateView, err error) {
+	recList, err := i.delegateStore.List(spaceID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]vacancyapimodels.ApprovalDelegateView, 0, len(recList))
+	for _, rec := range recList {
+		result = append(result, vacancyapimodels.ApprovalDelegateConvert(rec))
+	}
+	return result, nil
+}
+
+func (i impl) checkUsers(spaceID string, data vacancyapimodels.ApprovalRouteData) (hMsg string, err error) {
+	for _, step := range data.Steps {
+		userIDs := append([]string{}, step.Approvers...)
+		if step.EscalationUserID != "" {
+			userIDs = append(userIDs, step.EscalationUserID)
+		}
+		for _, userID := range userIDs {
+			hMsg, err = i.checkUser(spaceID, userID)
+			if err != nil || hMsg != "" {
+				return hMsg, err
+			}
+		}
+	}
+	return "", nil
+}
+
+func (i impl) checkUser(spaceID, userID string) (hMsg string, err error) {
+	user, err := i.spaceUsersStore.GetByID(userID)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения сотрудника")
+	}
+	if user == nil || user.SpaceID != spaceID {
+		return fmt.Sprintf("Сотрудник %v не найден в справочнике сотрудников", userID), nil
+	}
+	return "", nil
+}
+
+func getNullable(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,90 @@
+package aprovaltaskhandler
+
+import (
+	"hr-tools-backend/models"
+	dbmodels "hr-tools-backend/models/db"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsQuorumReached(t *testing.T) {
+	require.True(t, dbmodels.IsQuorumReached(2, 2, 3))
+	require.False(t, dbmodels.IsQuorumReached(2, 1, 3))
+	// 0 - требуются согласования всех
+	require.False(t, dbmodels.IsQuorumReached(0, 2, 3))
+	require.True(t, dbmodels.IsQuorumReached(0, 3, 3))
+	// кворум больше числа согласующих (часть удалена из цепочки)
+	require.True(t, dbmodels.IsQuorumReached(5, 2, 2))
+}
+
+func TestSelectRoute(t *testing.T) {
+	companyID := "company"
+	otherCompanyID := "other"
+	departmentID := "department"
+	list := []dbmodels.ApprovalRoute{
+		{Name: "default"},
+		{Name: "company", CompanyID: &companyID},
+		{Name: "department", CompanyID: &companyID, DepartmentID: &departmentID},
+		{Name: "other", CompanyID: &otherCompanyID},
+	}
+
+	route := selectRoute(list, dbmodels.VacancyRequest{CompanyID: &companyID, DepartmentID: &departmentID})
+	require.NotNil(t, route)
+	require.Equal(t, "department", route.Name)
+
+	route = selectRoute(list, dbmodels.VacancyRequest{CompanyID: &companyID})
+	require.NotNil(t, route)
+	require.Equal(t, "company", route.Name)
+
+	route = selectRoute(list, dbmodels.VacancyRequest{})
+	require.NotNil(t, route)
+	require.Equal(t, "default", route.Name)
+
+	require.Nil(t, selectRoute(list[3:], dbmodels.VacancyRequest{CompanyID: &companyID}))
+}
+
+func TestBuildRouteTasks(t *testing.T) {
+	route := dbmodels.ApprovalRoute{
+		Steps: dbmodels.ApprovalRouteSteps{
+			{Name: "Руководители", Approvers: []string{"u1", "u2", "u3"}, Quorum: 2, SlaHours: 24, EscalationUserID: "boss"},
+			{Name: "Финансы", Approvers: []string{"u4"}, MinSalary: 200000},
+			{Name: "Новая позиция", Approvers: []string{"u5"}, RequestType: models.VRTypeNew},
+		},
+	}
+
+	tasks := buildRouteTasks(route, dbmodels.VacancyRequest{Salary: 100000, RequestType: models.VRTypeNew})
+	require.Len(t, tasks, 4)
+	for _, task := range tasks[:3] {
+		require.Equal(t, 0, task.Step)
+		require.Equal(t, 2, task.Quorum)
+		require.Equal(t, 24, task.SlaHours)
+		require.Equal(t, "boss", task.EscalationUserID)
+		require.Equal(t, models.AStatePending, task.State)
+	}
+	// шаг по зарплате пропущен, нумерация шагов без разрывов
+	require.Equal(t, "u5", tasks[3].AssigneeUserID)
+	require.Equal(t, 1, tasks[3].Step)
+	require.Equal(t, models.AStateWaiting, tasks[3].State)
+
+	tasks = buildRouteTasks(route, dbmodels.VacancyRequest{Salary: 300000})
+	require.Len(t, tasks, 4)
+	require.Equal(t, "u4", tasks[3].AssigneeUserID)
+}
+
+func TestSteps(t *testing.T) {
+	list := []dbmodels.ApprovalTask{
+		{AssigneeUserID: "u1", Step: 1},
+		{AssigneeUserID: "u2", Step: 1},
+		{AssigneeUserID: "u3", Step: 3},
+	}
+	require.Equal(t, 1, getFirstStep(list))
+	nextStep, ok := getNextStep(list, 1)
+	require.True(t, ok)
+	require.Equal(t, 3, nextStep)
+	_, ok = getNextStep(list, 3)
+	require.False(t, ok)
+	require.Equal(t, map[string]bool{"u1": true, "u2": true}, getStepAssignees(list, 1))
+	require.Equal(t, models.AStatePending, getInitialState(1, 1))
+	require.Equal(t, models.AStateWaiting, getInitialState(3, 1))
+}
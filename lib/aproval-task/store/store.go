@@ -3,7 +3,9 @@ package approvaltaskstore
 import (
 	"github.com/pkg/errors"
 	"gorm.io/gorm"
+	"hr-tools-backend/models"
 	dbmodels "hr-tools-backend/models/db"
+	"time"
 )
 
 type Provider interface {
@@ -13,6 +15,8 @@ type Provider interface {
 	Delete(spaceID, id string) error
 	DeleteByVacancyRequest(spaceID, requestID string) error
 	List(spaceID, requestID string) (list []dbmodels.ApprovalTask, err error)
+	// ListOverdue задачи на согласовании с истекшим сроком, по которым не было эскалации
+	ListOverdue(now time.Time) (list []dbmodels.ApprovalTask, err error)
 }
 
 func NewInstance(DB *gorm.DB) Provider {
@@ -103,7 +107,7 @@ func (i impl) List(spaceID, requestID string) (list []dbmodels.ApprovalTask, err
 	tx := i.db.
 		Where("space_id = ?", spaceID).
 		Where("request_id = ?", requestID).
-		Order("created_at ASC").
+		Order("step ASC, created_at ASC").
 		Preload("AssigneeUser")
 	err = tx.Find(&list).Error
 	if err != nil {
@@ -114,3 +118,18 @@ func (i impl) List(spaceID, requestID string) (list []dbmodels.ApprovalTask, err
 	}
 	return list, nil
 }
+
+func (i impl) ListOverdue(now time.Time) (list []dbmodels.ApprovalTask, err error) {
+	list = []dbmodels.ApprovalTask{}
+	err = i.db.
+		Where("state = ?", models.AStatePending).
+		Where("due_at < ?", now).
+		Where("escalated_at is null").
+		Preload("AssigneeUser").
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
@@ -0,0 +1,141 @@
+package auditlog
+
+import (
+	dbmodels "hr-tools-backend/models/db"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const maskedValue = "***"
+
+// поля с этими подстроками в названии не попадают в журнал в открытом виде
+var secretFields = []string{"secret", "password", "token"}
+
+// Diff изменения между состояниями сущности до и после действия.
+// Сравниваются поля структуры (включая встроенные структуры) по json тегу либо ключи map[string]...;
+// before или after может быть nil - при создании и удалении сущности
+func Diff(before, after any) []dbmodels.FieldChanges {
+	bv := indirect(reflect.ValueOf(before))
+	av := indirect(reflect.ValueOf(after))
+	if !bv.IsValid() && !av.IsValid() {
+		return nil
+	}
+	if !bv.IsValid() {
+		bv = reflect.Zero(av.Type())
+	}
+	if !av.IsValid() {
+		av = reflect.Zero(bv.Type())
+	}
+	if bv.Type() != av.Type() {
+		return nil
+	}
+	result := []dbmodels.FieldChanges{}
+	switch bv.Kind() {
+	case reflect.Struct:
+		diffStruct(bv, av, &result)
+	case reflect.Map:
+		if bv.Type().Key().Kind() == reflect.String {
+			diffMap(bv, av, &result)
+		}
+	}
+	return result
+}
+
+func diffStruct(bv, av reflect.Value, result *[]dbmodels.FieldChanges) {
+	for n := 0; n < bv.NumField(); n++ {
+		field := bv.Type().Field(n)
+		if !field.IsExported() {
+			continue
+		}
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			diffStruct(bv.Field(n), av.Field(n), result)
+			continue
+		}
+		name := fieldName(field)
+		if name == "" {
+			continue
+		}
+		appendChange(name, bv.Field(n).Interface(), av.Field(n).Interface(), result)
+	}
+}
+
+func diffMap(bv, av reflect.Value, result *[]dbmodels.FieldChanges) {
+	keys := map[string]reflect.Value{}
+	for _, key := range append(bv.MapKeys(), av.MapKeys()...) {
+		keys[key.String()] = key
+	}
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		appendChange(name, mapValue(bv, keys[name]), mapValue(av, keys[name]), result)
+	}
+}
+
+func appendChange(name string, oldValue, newValue any, result *[]dbmodels.FieldChanges) {
+	if reflect.DeepEqual(oldValue, newValue) {
+		return
+	}
+	if isSecret(name) {
+		oldValue = mask(oldValue)
+		newValue = mask(newValue)
+	}
+	*result = append(*result, dbmodels.FieldChanges{
+		Field:    name,
+		OldValue: oldValue,
+		NewValue: newValue,
+	})
+}
+
+func fieldName(field reflect.StructField) string {
+	tag := strings.Split(field.Tag.Get("json"), ",")[0]
+	if tag == "-" {
+		return ""
+	}
+	if tag == "" {
+		return field.Name
+	}
+	return tag
+}
+
+func mapValue(v reflect.Value, key reflect.Value) any {
+	if v.IsNil() {
+		return nil
+	}
+	value := v.MapIndex(key)
+	if !value.IsValid() {
+		return nil
+	}
+	return value.Interface()
+}
+
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func isSecret(name string) bool {
+	name = strings.ToLower(name)
+	for _, part := range secretFields {
+		if strings.Contains(name, part) {
+			return true
+		}
+	}
+	return false
+}
+
+func mask(value any) any {
+	v := indirect(reflect.ValueOf(value))
+	if !v.IsValid() || v.IsZero() {
+		return ""
+	}
+	return maskedValue
+}
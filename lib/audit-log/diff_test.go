@@ -0,0 +1,63 @@
+package auditlog
+
+import (
+	"testing"
+
+	dbmodels "hr-tools-backend/models/db"
+
+	"github.com/stretchr/testify/require"
+)
+
+type TestBase struct {
+	Name string `json:"name"`
+}
+
+type testData struct {
+	TestBase
+	Url          string   `json:"url"`
+	ClientSecret string   `json:"client_secret"`
+	Events       []string `json:"events"`
+	Internal     string   `json:"-"`
+	Count        int
+}
+
+func TestDiff(t *testing.T) {
+	t.Run("update", func(t *testing.T) {
+		before := testData{TestBase: TestBase{Name: "old"}, Url: "https://a", ClientSecret: "enc1", Events: []string{"a"}, Internal: "x"}
+		after := testData{TestBase: TestBase{Name: "new"}, Url: "https://a", ClientSecret: "enc2", Events: []string{"a", "b"}, Internal: "y", Count: 1}
+		changes := Diff(before, &after)
+		require.Equal(t, []dbmodels.FieldChanges{
+			{Field: "name", OldValue: "old", NewValue: "new"},
+			{Field: "client_secret", OldValue: maskedValue, NewValue: maskedValue},
+			{Field: "events", OldValue: []string{"a"}, NewValue: []string{"a", "b"}},
+			{Field: "Count", OldValue: 0, NewValue: 1},
+		}, changes)
+	})
+	t.Run("no changes", func(t *testing.T) {
+		rec := testData{Url: "https://a"}
+		require.Empty(t, Diff(rec, rec))
+	})
+	t.Run("create and delete", func(t *testing.T) {
+		var empty *testData
+		rec := &testData{Url: "https://a", ClientSecret: "enc"}
+		require.Equal(t, []dbmodels.FieldChanges{
+			{Field: "url", OldValue: "", NewValue: "https://a"},
+			{Field: "client_secret", OldValue: "", NewValue: maskedValue},
+		}, Diff(empty, rec))
+		require.Equal(t, []dbmodels.FieldChanges{
+			{Field: "url", OldValue: "https://a", NewValue: ""},
+			{Field: "client_secret", OldValue: maskedValue, NewValue: ""},
+		}, Diff(rec, nil))
+	})
+	t.Run("map", func(t *testing.T) {
+		changes := Diff(map[string]string{"ImapPassword": "", "ImapHost": "a"}, map[string]string{"ImapPassword": "p", "ImapHost": "b"})
+		require.Equal(t, []dbmodels.FieldChanges{
+			{Field: "ImapHost", OldValue: "a", NewValue: "b"},
+			{Field: "ImapPassword", OldValue: "", NewValue: maskedValue},
+		}, changes)
+	})
+	t.Run("different types", func(t *testing.T) {
+		require.Nil(t, Diff(testData{}, TestBase{}))
+		require.Nil(t, Diff(nil, nil))
+	})
+}
@@ -0,0 +1,184 @@
+package auditlog
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"hr-tools-backend/db"
+	auditlogstore "hr-tools-backend/lib/audit-log/store"
+	spaceusersstore "hr-tools-backend/lib/space/users/store"
+	initchecker "hr-tools-backend/lib/utils/init-checker"
+	"hr-tools-backend/models"
+	auditlogapimodels "hr-tools-backend/models/api/audit-log"
+	dbmodels "hr-tools-backend/models/db"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// максимальное количество записей в выгрузке CSV
+const exportLimit = 50000
+
+type Provider interface {
+	// Save запись в журнал аудита, ошибка записи логируется и не прерывает основную операцию
+	Save(rec dbmodels.AuditLog)
+	// SaveChanges запись изменения сущности из доменного обработчика, userID пусто - действие системы.
+	// Изменение без отличий между before и after не записывается
+	SaveChanges(spaceID, userID string, action models.AuditAction, entity models.AuditEntity, entityID, description string, before, after any)
+	List(spaceID string, filter auditlogapimodels.Filter) (list []auditlogapimodels.View, rowCount int64, err error)
+	ExportCsv(spaceID string, filter auditlogapimodels.Filter) (*bytes.Buffer, error)
+}
+
+var Instance Provider
+
+func NewHandler() {
+	instance := impl{
+		store:     auditlogstore.NewInstance(db.DB),
+		userStore: spaceusersstore.NewInstance(db.DB),
+	}
+	initchecker.CheckInit(
+		"store", instance.store,
+		"userStore", instance.userStore,
+	)
+	Instance = instance
+}
+
+type impl struct {
+	store     auditlogstore.Provider
+	userStore spaceusersstore.Provider
+}
+
+func (i impl) Save(rec dbmodels.AuditLog) {
+	_, err := i.store.Create(rec)
+	if err != nil {
+		log.
+			WithField("space_id", rec.SpaceID).
+			WithField("entity_type", rec.EntityType).
+			WithField("entity_id", rec.EntityID).
+			WithError(err).
+			Error("ошибка записи в журнал аудита")
+	}
+}
+
+func (i impl) SaveChanges(spaceID, userID string, action models.AuditAction, entity models.AuditEntity, entityID, description string, before, after any) {
+	changes := Diff(before, after)
+	if action == models.AuditActionUpdate && len(changes) == 0 {
+		return
+	}
+	rec := dbmodels.AuditLog{
+		BaseSpaceModel: dbmodels.BaseSpaceModel{SpaceID: spaceID},
+		ActorType:      models.AuditActorSystem,
+		Action:         action,
+		EntityType:     entity,
+		EntityID:       entityID,
+		Changes: dbmodels.EntityChanges{
+			Description: description,
+			Data:        changes,
+		},
+	}
+	if userID != "" {
+		rec.ActorType = models.AuditActorUser
+		rec.ActorID = userID
+		user, err := i.userStore.GetByID(userID)
+		if err != nil {
+			log.
+				WithField("space_id", spaceID).
+				WithField("user_id", userID).
+				WithError(err).
+				Warn("ошибка получения пользователя для журнала аудита")
+		}
+		if user != nil {
+			rec.ActorName = user.GetFullName()
+			if user.IsService {
+				rec.ActorType = models.AuditActorApiKey
+			}
+		}
+	}
+	i.Save(rec)
+}
+
+func (i impl) List(spaceID string, filter auditlogapimodels.Filter) (list []auditlogapimodels.View, rowCount int64, err error) {
+	rowCount, err = i.store.ListCount(spaceID, filter)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "ошибка получения количества записей журнала аудита")
+	}
+	page, limit := filter.GetPage()
+	offset := (page - 1) * limit
+	if int64(offset) > rowCount {
+		return []auditlogapimodels.View{}, rowCount, nil
+	}
+	recList, err := i.store.List(spaceID, filter)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "ошибка получения журнала аудита")
+	}
+	list = make([]auditlogapimodels.View, 0, len(recList))
+	for _, rec := range recList {
+		list = append(list, auditlogapimodels.Convert(rec))
+	}
+	return list, rowCount, nil
+}
+
+func (i impl) ExportCsv(spaceID string, filter auditlogapimodels.Filter) (*bytes.Buffer, error) {
+	recList, err := i.store.ListForExport(spaceID, filter, exportLimit)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения журнала аудита")
+	}
+	buffer := new(bytes.Buffer)
+	// BOM, чтобы Excel открывал файл в UTF-8
+	buffer.WriteString("\xEF\xBB\xBF")
+	writer := csv.NewWriter(buffer)
+	writer.Comma = ';'
+	err = writer.Write([]string{"Дата", "Инициатор", "Пользователь", "Действие", "Сущность", "ID сущности",
+		"Метод", "Адрес", "Код ответа", "IP", "Браузер", "Изменения"})
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка записи заголовка CSV")
+	}
+	for _, rec := range recList {
+		status := ""
+		if rec.Status != 0 {
+			status = strconv.Itoa(rec.Status)
+		}
+		err = writer.Write([]string{
+			rec.CreatedAt.Format("02.01.2006 15:04:05"),
+			rec.ActorType.ToHuman(),
+			rec.ActorName,
+			rec.Action.ToHuman(),
+			string(rec.EntityType),
+			rec.EntityID,
+			rec.Method,
+			rec.Path,
+			status,
+			rec.IP,
+			rec.UserAgent,
+			changesToString(rec.Changes),
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "ошибка записи строки CSV")
+		}
+	}
+	writer.Flush()
+	if err = writer.Error(); err != nil {
+		return nil, errors.Wrap(err, "ошибка формирования CSV")
+	}
+	return buffer, nil
+}
+
+func changesToString(changes dbmodels.EntityChanges) string {
+	parts := []string{}
+	if changes.Description != "" {
+		parts = append(parts, changes.Description)
+	}
+	for _, item := range changes.Data {
+		parts = append(parts, fmt.Sprintf("%v: %v -> %v", item.Field, valueToString(item.OldValue), valueToString(item.NewValue)))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func valueToString(value any) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,96 @@
+package auditlogretentionworker
+
+import (
+	"context"
+	"hr-tools-backend/db"
+	auditlogstore "hr-tools-backend/lib/audit-log/store"
+	spacesettingsstore "hr-tools-backend/lib/space/settings/store"
+	spacestore "hr-tools-backend/lib/space/store"
+	baseworker "hr-tools-backend/lib/utils/base-worker"
+	"hr-tools-backend/lib/utils/helpers"
+	"hr-tools-backend/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// Задача удаления записей журнала аудита по истечении срока хранения
+func StartWorker(ctx context.Context) {
+	i := &impl{
+		BaseImpl:      *baseworker.NewInstance("AuditLogRetentionWorker", 50*time.Second, 6*time.Hour),
+		spaceStore:    spacestore.NewInstance(db.DB),
+		settingsStore: spacesettingsstore.NewInstance(db.DB),
+		store:         auditlogstore.NewInstance(db.DB),
+	}
+	go i.Run(ctx, i.handle)
+}
+
+type impl struct {
+	baseworker.BaseImpl
+	spaceStore    spacestore.Provider
+	settingsStore spacesettingsstore.Provider
+	store         auditlogstore.Provider
+}
+
+func (i impl) handle(ctx context.Context) {
+	logger := i.GetLogger()
+	ids, err := i.spaceStore.GetActiveIds()
+	if err != nil {
+		logger.WithError(err).Error("ошибка получения списка спейсов")
+		return
+	}
+	for _, spaceID := range ids {
+		if helpers.IsContextDone(ctx) {
+			break
+		}
+		days, err := i.getRetentionDays(spaceID)
+		if err != nil {
+			logger.WithError(err).
+				WithField("space_id", spaceID).
+				Error("ошибка получения срока хранения журнала аудита")
+			continue
+		}
+		if days == 0 {
+			continue
+		}
+		count, err := i.store.DeleteBefore(spaceID, time.Now().AddDate(0, 0, -days))
+		if err != nil {
+			logger.WithError(err).
+				WithField("space_id", spaceID).
+				Error("ошибка удаления записей журнала аудита с истекшим сроком хранения")
+			continue
+		}
+		if count > 0 {
+			logger.
+				WithField("space_id", spaceID).
+				Infof("удалено записей журнала аудита с истекшим сроком хранения: %v", count)
+		}
+	}
+}
+
+func (i impl) getRetentionDays(spaceID string) (int, error) {
+	value, err := i.settingsStore.GetValueByCode(spaceID, models.AuditLogRetentionDaysSetting)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil
+		}
+		return 0, errors.Wrapf(err, "ошибка получения настройки %v", models.AuditLogRetentionDaysSetting)
+	}
+	return parseRetentionDays(value)
+}
+
+// parseRetentionDays срок хранения в днях, 0 - без ограничения
+func parseRetentionDays(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 0 {
+		return 0, errors.Errorf("некорректный срок хранения журнала аудита: %v", value)
+	}
+	return days, nil
+}
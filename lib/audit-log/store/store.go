@@ -0,0 +1,120 @@
+package auditlogstore
+
+import (
+	auditlogapimodels "hr-tools-backend/models/api/audit-log"
+	dbmodels "hr-tools-backend/models/db"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Provider interface {
+	Create(rec dbmodels.AuditLog) (id string, err error)
+	ListCount(spaceID string, filter auditlogapimodels.Filter) (int64, error)
+	List(spaceID string, filter auditlogapimodels.Filter) ([]dbmodels.AuditLog, error)
+	// ListForExport записи для выгрузки, не более limit последних
+	ListForExport(spaceID string, filter auditlogapimodels.Filter, limit int) ([]dbmodels.AuditLog, error)
+	// DeleteBefore удаление записей, созданных ранее указанной даты
+	DeleteBefore(spaceID string, before time.Time) (int64, error)
+}
+
+func NewInstance(DB *gorm.DB) Provider {
+	return &impl{
+		db: DB,
+	}
+}
+
+type impl struct {
+	db *gorm.DB
+}
+
+func (i impl) Create(rec dbmodels.AuditLog) (id string, err error) {
+	err = i.db.
+		Omit(clause.Associations).
+		Save(&rec).
+		Error
+	if err != nil {
+		return "", err
+	}
+	return rec.ID, nil
+}
+
+func (i impl) ListCount(spaceID string, filter auditlogapimodels.Filter) (int64, error) {
+	var rowCount int64
+	err := i.filteredQuery(spaceID, filter).
+		Count(&rowCount).
+		Error
+	if err != nil {
+		return 0, err
+	}
+	return rowCount, nil
+}
+
+func (i impl) List(spaceID string, filter auditlogapimodels.Filter) ([]dbmodels.AuditLog, error) {
+	list := []dbmodels.AuditLog{}
+	page, limit := filter.GetPage()
+	offset := (page - 1) * limit
+	err := i.filteredQuery(spaceID, filter).
+		Limit(limit).
+		Offset(offset).
+		Order("created_at desc").
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (i impl) ListForExport(spaceID string, filter auditlogapimodels.Filter, limit int) ([]dbmodels.AuditLog, error) {
+	list := []dbmodels.AuditLog{}
+	err := i.filteredQuery(spaceID, filter).
+		Limit(limit).
+		Order("created_at desc").
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (i impl) DeleteBefore(spaceID string, before time.Time) (int64, error) {
+	tx := i.db.
+		Where("space_id = ?", spaceID).
+		Where("created_at < ?", before).
+		Delete(&dbmodels.AuditLog{})
+	if tx.Error != nil {
+		return 0, tx.Error
+	}
+	return tx.RowsAffected, nil
+}
+
+func (i impl) filteredQuery(spaceID string, filter auditlogapimodels.Filter) *gorm.DB {
+	tx := i.db.
+		Model(dbmodels.AuditLog{}).
+		Where("space_id = ?", spaceID)
+	if filter.ActorType != "" {
+		tx.Where("actor_type = ?", filter.ActorType)
+	}
+	if filter.ActorID != "" {
+		tx.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Action != "" {
+		tx.Where("action = ?", filter.Action)
+	}
+	if filter.EntityType != "" {
+		tx.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		tx.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.DateFrom != nil {
+		tx.Where("created_at >= ?", *filter.DateFrom)
+	}
+	if filter.DateTo != nil {
+		tx.Where("created_at <= ?", *filter.DateTo)
+	}
+	return tx
+}
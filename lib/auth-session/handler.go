@@ -0,0 +1,231 @@
+package authsession
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"hr-tools-backend/config"
+	"hr-tools-backend/db"
+	authsessionstore "hr-tools-backend/lib/auth-session/store"
+	initchecker "hr-tools-backend/lib/utils/init-checker"
+	"hr-tools-backend/models"
+	authapimodels "hr-tools-backend/models/api/auth"
+	dbmodels "hr-tools-backend/models/db"
+	"strings"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// результат проверки сессии кешируется, на других экземплярах сервиса отзыв вступает в силу в течение этого времени
+	activeCacheTTL = time.Minute
+	// повторный запрос с только что замененным токеном (параллельные запросы клиента) не считается утечкой
+	rotateGracePeriod = time.Minute
+	maxUserAgentLen   = 500
+)
+
+var errInvalidToken = errors.New("refresh токен недействителен")
+
+type Provider interface {
+	// Create новая сессия входа, возвращает ID сессии и refresh токен
+	Create(kind models.AuthUserKind, userID, spaceID string, client authapimodels.ClientInfo) (sessionID, refreshToken string, err error)
+	// Refresh замена refresh токена на новый, повторное использование замененного токена отзывает сессию
+	Refresh(kind models.AuthUserKind, refreshToken string, client authapimodels.ClientInfo) (session *dbmodels.AuthSession, newRefreshToken string, err error)
+	IsActive(sessionID string) (bool, error)
+	List(kind models.AuthUserKind, userID, currentSessionID string) ([]authapimodels.SessionView, error)
+	Revoke(kind models.AuthUserKind, userID, sessionID string) (hMsg string, err error)
+	RevokeAll(kind models.AuthUserKind, userID, reason string) error
+}
+
+var Instance Provider
+
+func NewHandler() {
+	instance := impl{
+		store: authsessionstore.NewInstance(db.DB),
+		cache: cache.New(activeCacheTTL, 5*activeCacheTTL),
+	}
+	initchecker.CheckInit(
+		"store", instance.store,
+	)
+	Instance = instance
+}
+
+type impl struct {
+	store authsessionstore.Provider
+	cache *cache.Cache
+}
+
+func (i impl) Create(kind models.AuthUserKind, userID, spaceID string, client authapimodels.ClientInfo) (sessionID, refreshToken string, err error) {
+	secret, err := generateSecret()
+	if err != nil {
+		return "", "", err
+	}
+	now := time.Now()
+	rec := dbmodels.AuthSession{
+		UserKind:   kind,
+		UserID:     userID,
+		SpaceID:    spaceID,
+		TokenHash:  getHash(secret),
+		RotatedAt:  now,
+		UserAgent:  truncate(client.UserAgent, maxUserAgentLen),
+		IP:         client.IP,
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(getRefreshTTL(kind)),
+	}
+	sessionID, err = i.store.Create(rec)
+	if err != nil {
+		return "", "", errors.Wrap(err, "ошибка создания сессии")
+	}
+	return sessionID, sessionID + "." + secret, nil
+}
+
+func (i impl) Refresh(kind models.AuthUserKind, refreshToken string, client authapimodels.ClientInfo) (*dbmodels.AuthSession, string, error) {
+	sessionID, secret, found := strings.Cut(strings.TrimSpace(refreshToken), ".")
+	if !found || sessionID == "" || secret == "" {
+		return nil, "", errInvalidToken
+	}
+	rec, err := i.store.GetByID(sessionID)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "ошибка получения сессии")
+	}
+	now := time.Now()
+	if rec == nil || rec.UserKind != kind || !rec.IsActive(now) {
+		return nil, "", errInvalidToken
+	}
+	logger := log.
+		WithField("session_id", rec.ID).
+		WithField("user_id", rec.UserID)
+	tokenHash := getHash(secret)
+	if tokenHash != rec.TokenHash {
+		if tokenHash == rec.PrevTokenHash && rec.RotatedAt.Add(rotateGracePeriod).Before(now) {
+			// замененный токен предъявлен повторно - токен скопирован, сессия отзывается
+			logger.Warn("повторное использование refresh токена, сессия отозвана")
+			err = i.revoke(rec.ID, "повторное использование refresh токена", now)
+			if err != nil {
+				logger.WithError(err).Error("ошибка отзыва сессии")
+			}
+		}
+		return nil, "", errInvalidToken
+	}
+
+	newSecret, err := generateSecret()
+	if err != nil {
+		return nil, "", err
+	}
+	updMap := map[string]interface{}{
+		"token_hash":      getHash(newSecret),
+		"prev_token_hash": tokenHash,
+		"rotated_at":      now,
+		"user_agent":      truncate(client.UserAgent, maxUserAgentLen),
+		"ip":              client.IP,
+		"last_used_at":    now,
+		"expires_at":      now.Add(getRefreshTTL(kind)),
+	}
+	updated, err := i.store.UpdateToken(rec.ID, tokenHash, updMap)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "ошибка обновления сессии")
+	}
+	if !updated {
+		// токен заменен параллельным запросом
+		return nil, "", errInvalidToken
+	}
+	return rec, rec.ID + "." + newSecret, nil
+}
+
+func (i impl) IsActive(sessionID string) (bool, error) {
+	if value, found := i.cache.Get(sessionID); found {
+		return value.(bool), nil
+	}
+	rec, err := i.store.GetByID(sessionID)
+	if err != nil {
+		return false, errors.Wrap(err, "ошибка получения сессии")
+	}
+	active := rec != nil && rec.IsActive(time.Now())
+	i.cache.SetDefault(sessionID, active)
+	return active, nil
+}
+
+func (i impl) List(kind models.AuthUserKind, userID, currentSessionID string) ([]authapimodels.SessionView, error) {
+	list, err := i.store.ListActive(kind, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	result := make([]authapimodels.SessionView, 0, len(list))
+	for _, rec := range list {
+		result = append(result, rec.ToModelView(currentSessionID))
+	}
+	return result, nil
+}
+
+func (i impl) Revoke(kind models.AuthUserKind, userID, sessionID string) (hMsg string, err error) {
+	rec, err := i.store.GetByID(sessionID)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения сессии")
+	}
+	if rec == nil || rec.UserKind != kind || rec.UserID != userID {
+		return "сессия не найдена", nil
+	}
+	err = i.revoke(rec.ID, "завершена пользователем", time.Now())
+	if err != nil {
+		return "", err
+	}
+	return "", nil
+}
+
+func (i impl) RevokeAll(kind models.AuthUserKind, userID, reason string) error {
+	ids, err := i.store.RevokeAll(kind, userID, reason, time.Now())
+	if err != nil {
+		return errors.Wrap(err, "ошибка отзыва сессий")
+	}
+	for _, id := range ids {
+		i.cache.Delete(id)
+	}
+	if len(ids) > 0 {
+		log.
+			WithField("user_id", userID).
+			WithField("count", len(ids)).
+			WithField("reason", reason).
+			Info("сессии пользователя отозваны")
+	}
+	return nil
+}
+
+func (i impl) revoke(id, reason string, now time.Time) error {
+	err := i.store.Revoke(id, reason, now)
+	if err != nil {
+		return errors.Wrap(err, "ошибка отзыва сессии")
+	}
+	i.cache.Delete(id)
+	return nil
+}
+
+func getRefreshTTL(kind models.AuthUserKind) time.Duration {
+	if kind == models.AuthAdminPanelUser {
+		return time.Second * time.Duration(config.Conf.AdminPanelAuth.JWTRefreshExpireInSec)
+	}
+	return time.Second * time.Duration(config.Conf.Auth.JWTRefreshExpireInSec)
+}
+
+func generateSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", errors.Wrap(err, "ошибка генерации refresh токена")
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+func getHash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+func truncate(value string, maxLen int) string {
+	runes := []rune(value)
+	if len(runes) > maxLen {
+		return string(runes[:maxLen])
+	}
+	return value
+}
@@ -0,0 +1,135 @@
+package authsessionstore
+
+import (
+	"hr-tools-backend/models"
+	dbmodels "hr-tools-backend/models/db"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Provider interface {
+	Create(rec dbmodels.AuthSession) (id string, err error)
+	GetByID(id string) (*dbmodels.AuthSession, error)
+	Update(id string, updMap map[string]interface{}) error
+	// UpdateToken замена refresh токена, false - токен уже заменен другим запросом
+	UpdateToken(id, tokenHash string, updMap map[string]interface{}) (bool, error)
+	// ListActive действующие сессии пользователя
+	ListActive(kind models.AuthUserKind, userID string, now time.Time) ([]dbmodels.AuthSession, error)
+	Revoke(id, reason string, now time.Time) error
+	// RevokeAll отзыв всех сессий пользователя, возвращает ID отозванных сессий
+	RevokeAll(kind models.AuthUserKind, userID, reason string, now time.Time) ([]string, error)
+}
+
+func NewInstance(DB *gorm.DB) Provider {
+	return &impl{
+		db: DB,
+	}
+}
+
+type impl struct {
+	db *gorm.DB
+}
+
+func (i impl) Create(rec dbmodels.AuthSession) (id string, err error) {
+	err = i.db.
+		Omit(clause.Associations).
+		Save(&rec).
+		Error
+	if err != nil {
+		return "", err
+	}
+	return rec.ID, nil
+}
+
+func (i impl) GetByID(id string) (*dbmodels.AuthSession, error) {
+	rec := dbmodels.AuthSession{}
+	err := i.db.
+		Where("id = ?", id).
+		First(&rec).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (i impl) Update(id string, updMap map[string]interface{}) error {
+	if len(updMap) == 0 {
+		return nil
+	}
+	return i.db.
+		Model(&dbmodels.AuthSession{}).
+		Where("id = ?", id).
+		Updates(updMap).
+		Error
+}
+
+func (i impl) UpdateToken(id, tokenHash string, updMap map[string]interface{}) (bool, error) {
+	tx := i.db.
+		Model(&dbmodels.AuthSession{}).
+		Where("id = ?", id).
+		Where("token_hash = ?", tokenHash).
+		Where("revoked_at is null").
+		Updates(updMap)
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+	return tx.RowsAffected == 1, nil
+}
+
+func (i impl) ListActive(kind models.AuthUserKind, userID string, now time.Time) ([]dbmodels.AuthSession, error) {
+	list := []dbmodels.AuthSession{}
+	err := i.db.
+		Where("user_kind = ?", kind).
+		Where("user_id = ?", userID).
+		Where("revoked_at is null").
+		Where("expires_at > ?", now).
+		Order("last_used_at desc").
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (i impl) Revoke(id, reason string, now time.Time) error {
+	return i.db.
+		Model(&dbmodels.AuthSession{}).
+		Where("id = ?", id).
+		Where("revoked_at is null").
+		Updates(map[string]interface{}{
+			"revoked_at":    now,
+			"revoke_reason": reason,
+		}).
+		Error
+}
+
+func (i impl) RevokeAll(kind models.AuthUserKind, userID, reason string, now time.Time) ([]string, error) {
+	list := []dbmodels.AuthSession{}
+	err := i.db.
+		Model(&list).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+		Where("user_kind = ?", kind).
+		Where("user_id = ?", userID).
+		Where("revoked_at is null").
+		Updates(map[string]interface{}{
+			"revoked_at":    now,
+			"revoke_reason": reason,
+		}).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(list))
+	for _, rec := range list {
+		ids = append(ids, rec.ID)
+	}
+	return ids, nil
+}
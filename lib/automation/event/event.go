@@ -0,0 +1,51 @@
+package automationevent
+
+import (
+	dbmodels "hr-tools-backend/models/db"
+	"sync"
+)
+
+// Event событие по кандидату, на которое срабатывают правила автоматизации
+type Event struct {
+	Trigger     dbmodels.AutomationTrigger
+	SpaceID     string
+	ApplicantID string
+	VacancyID   string
+	StageName   string              // этап, на который переведен кандидат / на котором находится
+	VkStatus    dbmodels.StepStatus // статус шага ВК
+	RuleID      string              // применяется только указанное правило (срок нахождения на этапе)
+}
+
+type Handler func(event Event)
+
+var (
+	handlerMx sync.RWMutex
+	handler   Handler
+)
+
+// SetHandler регистрирует обработчик событий, вызывается из NewHandler движка автоматизации
+func SetHandler(h Handler) {
+	handlerMx.Lock()
+	defer handlerMx.Unlock()
+	handler = h
+}
+
+// Publish асинхронная передача события обработчику, без обработчика событие отбрасывается
+func Publish(event Event) {
+	handlerMx.RLock()
+	h := handler
+	handlerMx.RUnlock()
+	if h == nil {
+		return
+	}
+	go h(event)
+}
+
+// GetHistoryTrigger событие автоматизации по записи истории действий кандидата
+func GetHistoryTrigger(action dbmodels.ActionType) (dbmodels.AutomationTrigger, bool) {
+	switch action {
+	case dbmodels.HistoryTypeNegotiation:
+		return dbmodels.AutomationTriggerNegotiation, true
+	}
+	return "", false
+}
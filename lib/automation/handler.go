@@ -0,0 +1,453 @@
+package automation
+
+import (
+	"context"
+	"fmt"
+	"hr-tools-backend/db"
+	"hr-tools-backend/lib/applicant"
+	applicantstore "hr-tools-backend/lib/applicant/store"
+	automationevent "hr-tools-backend/lib/automation/event"
+	automationlogstore "hr-tools-backend/lib/automation/log-store"
+	automationrulestore "hr-tools-backend/lib/automation/store"
+	messagetemplate "hr-tools-backend/lib/message-template"
+	pushhandler "hr-tools-backend/lib/space/push/handler"
+	initchecker "hr-tools-backend/lib/utils/init-checker"
+	selectionstagestore "hr-tools-backend/lib/vacancy/selection-stage-store"
+	vacancystore "hr-tools-backend/lib/vacancy/store"
+	"hr-tools-backend/lib/vk"
+	"hr-tools-backend/models"
+	applicantapimodels "hr-tools-backend/models/api/applicant"
+	automationapimodels "hr-tools-backend/models/api/automation"
+	dbmodels "hr-tools-backend/models/db"
+	"sync"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+type Provider interface {
+	Create(spaceID string, data automationapimodels.AutomationRuleData) (id, hMsg string, err error)
+	Update(spaceID, id string, data automationapimodels.AutomationRuleData) (hMsg string, err error)
+	Get(spaceID, id string) (*automationapimodels.AutomationRuleView, error)
+	List(spaceID string) ([]automationapimodels.AutomationRuleView, error)
+	Delete(spaceID, id string) error
+	// DryRun проверка правила на кандидате без выполнения действий
+	DryRun(spaceID, id, applicantID string) (result automationapimodels.DryRunResult, hMsg string, err error)
+	ListLog(spaceID string, filter automationapimodels.LogFilter) (list []automationapimodels.AutomationLogView, rowCount int64, err error)
+	// HandleEvent выполнение правил спейса по событию кандидата
+	HandleEvent(event automationevent.Event)
+}
+
+var Instance Provider
+
+func NewHandler() {
+	instance := &impl{
+		store:               automationrulestore.NewInstance(db.DB),
+		logStore:            automationlogstore.NewInstance(db.DB),
+		applicantStore:      applicantstore.NewInstance(db.DB),
+		selectionStageStore: selectionstagestore.NewInstance(db.DB),
+		vacancyStore:        vacancystore.NewInstance(db.DB),
+	}
+	initchecker.CheckInit(
+		"store", instance.store,
+		"logStore", instance.logStore,
+		"applicantStore", instance.applicantStore,
+		"selectionStageStore", instance.selectionStageStore,
+		"vacancyStore", instance.vacancyStore,
+	)
+	Instance = instance
+	automationevent.SetHandler(instance.HandleEvent)
+}
+
+type impl struct {
+	store               automationrulestore.Provider
+	logStore            automationlogstore.Provider
+	applicantStore      applicantstore.Provider
+	selectionStageStore selectionstagestore.Provider
+	vacancyStore        vacancystore.Provider
+	// события обрабатываются последовательно, чтобы действия одного правила не запускали его повторно до записи в журнал
+	mu sync.Mutex
+}
+
+func (i *impl) getLogger(spaceID, ruleID string) *log.Entry {
+	logger := log.WithField("space_id", spaceID)
+	if ruleID != "" {
+		logger = logger.WithField("rule_id", ruleID)
+	}
+	return logger
+}
+
+func (i *impl) Create(spaceID string, data automationapimodels.AutomationRuleData) (id, hMsg string, err error) {
+	hMsg, err = i.checkVacancy(spaceID, data.VacancyID)
+	if err != nil || hMsg != "" {
+		return "", hMsg, err
+	}
+	rec := dbmodels.AutomationRule{
+		BaseSpaceModel: dbmodels.BaseSpaceModel{SpaceID: spaceID},
+		Name:           data.Name,
+		IsActive:       data.IsActive,
+		DryRun:         data.DryRun,
+		Trigger:        data.Trigger,
+		StageName:      data.StageName,
+		VkStatus:       data.VkStatus,
+		DaysInStage:    data.DaysInStage,
+		Conditions:     data.Conditions,
+		Actions:        data.Actions,
+	}
+	if data.VacancyID != "" {
+		rec.VacancyID = &data.VacancyID
+	}
+	id, err = i.store.Create(rec)
+	if err != nil {
+		return "", "", errors.Wrap(err, "ошибка создания правила автоматизации")
+	}
+	i.getLogger(spaceID, id).Info("создано правило автоматизации")
+	return id, "", nil
+}
+
+func (i *impl) Update(spaceID, id string, data automationapimodels.AutomationRuleData) (hMsg string, err error) {
+	rec, err := i.store.GetByID(spaceID, id)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения правила автоматизации")
+	}
+	if rec == nil {
+		return "правило автоматизации не найдено", nil
+	}
+	hMsg, err = i.checkVacancy(spaceID, data.VacancyID)
+	if err != nil || hMsg != "" {
+		return hMsg, err
+	}
+	var vacancyID *string
+	if data.VacancyID != "" {
+		vacancyID = &data.VacancyID
+	}
+	updMap := map[string]interface{}{
+		"Name":        data.Name,
+		"IsActive":    data.IsActive,
+		"DryRun":      data.DryRun,
+		"VacancyID":   vacancyID,
+		"Trigger":     data.Trigger,
+		"StageName":   data.StageName,
+		"VkStatus":    data.VkStatus,
+		"DaysInStage": data.DaysInStage,
+		"Conditions":  data.Conditions,
+		"Actions":     data.Actions,
+	}
+	err = i.store.Update(spaceID, id, updMap)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка изменения правила автоматизации")
+	}
+	i.getLogger(spaceID, id).Info("изменено правило автоматизации")
+	return "", nil
+}
+
+func (i *impl) Get(spaceID, id string) (*automationapimodels.AutomationRuleView, error) {
+	rec, err := i.store.GetByID(spaceID, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения правила автоматизации")
+	}
+	if rec == nil {
+		return nil, nil
+	}
+	result := automationapimodels.AutomationRuleConvert(*rec)
+	return &result, nil
+}
+
+func (i *impl) List(spaceID string) ([]automationapimodels.AutomationRuleView, error) {
+	list, err := i.store.List(spaceID)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения списка правил автоматизации")
+	}
+	result := make([]automationapimodels.AutomationRuleView, 0, len(list))
+	for _, rec := range list {
+		result = append(result, automationapimodels.AutomationRuleConvert(rec))
+	}
+	return result, nil
+}
+
+func (i *impl) Delete(spaceID, id string) error {
+	err := i.store.Delete(spaceID, id)
+	if err != nil {
+		return errors.Wrap(err, "ошибка удаления правила автоматизации")
+	}
+	i.getLogger(spaceID, id).Info("удалено правило автоматизации")
+	return nil
+}
+
+func (i *impl) DryRun(spaceID, id, applicantID string) (result automationapimodels.DryRunResult, hMsg string, err error) {
+	rule, err := i.store.GetByID(spaceID, id)
+	if err != nil {
+		return result, "", errors.Wrap(err, "ошибка получения правила автоматизации")
+	}
+	if rule == nil {
+		return result, "правило автоматизации не найдено", nil
+	}
+	applicantRec, err := i.applicantStore.GetByID(spaceID, applicantID)
+	if err != nil {
+		return result, "", errors.Wrap(err, "ошибка получения кандидата")
+	}
+	if applicantRec == nil {
+		return result, "кандидат не найден", nil
+	}
+	stageName, vkStatus := getApplicantState(applicantRec.Applicant)
+	result.MatchConditions = rule.CheckConditions(applicantRec.Applicant)
+	result.Executed, err = i.logStore.IsExecuted(rule.ID, applicantID, rule.GetEventKey(stageName, vkStatus))
+	if err != nil {
+		return result, "", errors.Wrap(err, "ошибка проверки журнала правил автоматизации")
+	}
+	result.Actions = dbmodels.AutomationActionResults{}
+	for _, action := range rule.Actions {
+		result.Actions = append(result.Actions, i.planAction(applicantRec.Applicant, action))
+	}
+	return result, "", nil
+}
+
+func (i *impl) ListLog(spaceID string, filter automationapimodels.LogFilter) (list []automationapimodels.AutomationLogView, rowCount int64, err error) {
+	rowCount, err = i.logStore.ListCount(spaceID, filter)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "ошибка получения журнала правил автоматизации")
+	}
+	recList, err := i.logStore.List(spaceID, filter)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "ошибка получения журнала правил автоматизации")
+	}
+	list = make([]automationapimodels.AutomationLogView, 0, len(recList))
+	for _, rec := range recList {
+		list = append(list, automationapimodels.AutomationLogConvert(rec))
+	}
+	return list, rowCount, nil
+}
+
+func (i *impl) HandleEvent(event automationevent.Event) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	logger := i.getLogger(event.SpaceID, "").
+		WithField("applicant_id", event.ApplicantID).
+		WithField("trigger", event.Trigger)
+	rules, err := i.store.ListActive(event.SpaceID, event.Trigger)
+	if err != nil {
+		logger.WithError(err).Error("ошибка получения правил автоматизации")
+		return
+	}
+	if len(rules) == 0 {
+		return
+	}
+	applicantRec, err := i.applicantStore.GetByID(event.SpaceID, event.ApplicantID)
+	if err != nil {
+		logger.WithError(err).Error("ошибка получения кандидата")
+		return
+	}
+	if applicantRec == nil {
+		return
+	}
+	if applicantRec.Status == models.ApplicantStatusRejected || applicantRec.Status == models.ApplicantStatusArchive {
+		return
+	}
+	if event.VacancyID == "" {
+		event.VacancyID = applicantRec.VacancyID
+	}
+	stageName, vkStatus := getApplicantState(applicantRec.Applicant)
+	if event.StageName == "" {
+		event.StageName = stageName
+	}
+	if event.Trigger != dbmodels.AutomationTriggerVkStep {
+		event.VkStatus = vkStatus
+	}
+	for _, rule := range rules {
+		if event.RuleID != "" && event.RuleID != rule.ID {
+			continue
+		}
+		i.applyRule(rule, applicantRec.Applicant, event, logger.WithField("rule_id", rule.ID))
+	}
+}
+
+func (i *impl) applyRule(rule dbmodels.AutomationRule, applicantRec dbmodels.Applicant, event automationevent.Event, logger *log.Entry) {
+	if !rule.MatchEvent(event.Trigger, event.VacancyID, event.StageName, event.VkStatus) {
+		return
+	}
+	if !rule.CheckConditions(applicantRec) {
+		return
+	}
+	eventKey := rule.GetEventKey(event.StageName, event.VkStatus)
+	executed, err := i.logStore.IsExecuted(rule.ID, applicantRec.ID, eventKey)
+	if err != nil {
+		logger.WithError(err).Error("ошибка проверки журнала правил автоматизации")
+		return
+	}
+	if executed {
+		return
+	}
+	logRec := dbmodels.AutomationLog{
+		BaseSpaceModel: dbmodels.BaseSpaceModel{SpaceID: rule.SpaceID},
+		RuleID:         rule.ID,
+		RuleName:       rule.Name,
+		ApplicantID:    applicantRec.ID,
+		VacancyID:      event.VacancyID,
+		Trigger:        event.Trigger,
+		EventKey:       eventKey,
+		DryRun:         rule.DryRun,
+		Success:        true,
+		Results:        dbmodels.AutomationActionResults{},
+	}
+	for _, action := range rule.Actions {
+		var result dbmodels.AutomationActionResult
+		if rule.DryRun {
+			result = i.planAction(applicantRec, action)
+		} else {
+			result = i.executeAction(rule, applicantRec, action)
+		}
+		if !result.Success {
+			logRec.Success = false
+			logger.WithField("action", action.Type).Warn(result.Message)
+		}
+		logRec.Results = append(logRec.Results, result)
+	}
+	_, err = i.logStore.Create(logRec)
+	if err != nil {
+		logger.WithError(err).Error("ошибка сохранения журнала правил автоматизации")
+		return
+	}
+	logger.Info("выполнено правило автоматизации")
+}
+
+// planAction описание действия без выполнения (тестовый режим)
+func (i *impl) planAction(applicantRec dbmodels.Applicant, action dbmodels.AutomationAction) dbmodels.AutomationActionResult {
+	result := dbmodels.AutomationActionResult{
+		Type:    action.Type,
+		Success: true,
+	}
+	switch action.Type {
+	case dbmodels.AutomationActionSendTemplate:
+		result.Message = fmt.Sprintf("будет отправлено письмо по шаблону %v", action.TemplateID)
+	case dbmodels.AutomationActionChangeStage:
+		stage, err := i.getStage(applicantRec, action.StageName)
+		if err != nil {
+			result.Success = false
+			result.Message = err.Error()
+		} else {
+			result.Message = fmt.Sprintf("кандидат будет переведен на этап «%v»", stage.Name)
+		}
+	case dbmodels.AutomationActionReject:
+		result.Message = fmt.Sprintf("кандидат будет отклонен, причина: %v", action.RejectReason)
+	case dbmodels.AutomationActionAddTag:
+		result.Message = fmt.Sprintf("кандидату будет добавлен тег «%v»", action.Tag)
+	case dbmodels.AutomationActionNotifyTeam:
+		result.Message = fmt.Sprintf("команде вакансии будет отправлено уведомление: %v", action.Message)
+	case dbmodels.AutomationActionVkStep0:
+		result.Message = "кандидату будут отправлены вопросы (шаг 0 ВК)"
+	default:
+		result.Success = false
+		result.Message = fmt.Sprintf("некорректный тип действия: %v", action.Type)
+	}
+	return result
+}
+
+func (i *impl) executeAction(rule dbmodels.AutomationRule, applicantRec dbmodels.Applicant, action dbmodels.AutomationAction) dbmodels.AutomationActionResult {
+	result := dbmodels.AutomationActionResult{
+		Type: action.Type,
+	}
+	var hMsg string
+	var err error
+	switch action.Type {
+	case dbmodels.AutomationActionSendTemplate:
+		hMsg, err = messagetemplate.Instance.SendEmailMessage(context.Background(), rule.SpaceID, action.TemplateID, applicantRec.ID, "")
+		result.Message = "отправлено письмо по шаблону"
+	case dbmodels.AutomationActionChangeStage:
+		var stage *dbmodels.SelectionStage
+		stage, err = i.getStage(applicantRec, action.StageName)
+		if err == nil {
+			hMsg, err = applicant.Instance.ChangeStage(rule.SpaceID, "", applicantRec.ID, stage.ID)
+			result.Message = fmt.Sprintf("кандидат переведен на этап «%v»", stage.Name)
+		}
+	case dbmodels.AutomationActionReject:
+		err = applicant.Instance.ApplicantReject(rule.SpaceID, applicantRec.ID, "", applicantapimodels.RejectRequest{
+			Reason:    action.RejectReason,
+			Initiator: action.RejectInitiator,
+		})
+		result.Message = "кандидат отклонен"
+	case dbmodels.AutomationActionAddTag:
+		err = applicant.Instance.ApplicantAddTag(rule.SpaceID, applicantRec.ID, "", action.Tag)
+		result.Message = fmt.Sprintf("добавлен тег «%v»", action.Tag)
+	case dbmodels.AutomationActionNotifyTeam:
+		err = i.notifyTeam(rule, applicantRec, action.Message)
+		result.Message = "отправлено уведомление команде вакансии"
+	case dbmodels.AutomationActionVkStep0:
+		var ok bool
+		ok, err = vk.Instance.RunStep0(applicantRec)
+		if err == nil && !ok {
+			hMsg = "вопросы кандидату не отправлены"
+		}
+		result.Message = "кандидату отправлены вопросы (шаг 0 ВК)"
+	default:
+		hMsg = fmt.Sprintf("некорректный тип действия: %v", action.Type)
+	}
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+	if hMsg != "" {
+		result.Message = hMsg
+		return result
+	}
+	result.Success = true
+	return result
+}
+
+func (i *impl) notifyTeam(rule dbmodels.AutomationRule, applicantRec dbmodels.Applicant, message string) error {
+	vacancyRec, err := i.vacancyStore.GetByID(rule.SpaceID, applicantRec.VacancyID)
+	if err != nil {
+		return errors.Wrap(err, "ошибка получения вакансии")
+	}
+	if vacancyRec == nil {
+		return errors.New("вакансия не найдена")
+	}
+	data := models.GetPushApplicantAutomation(rule.Name, applicantRec.GetFIO(), vacancyRec.VacancyName, message).WithEntity(applicantRec.ID)
+	//отправляем автору
+	pushhandler.Instance.SendNotification(vacancyRec.AuthorID, data)
+	for _, teamMember := range vacancyRec.VacancyTeam {
+		//отправляем команде
+		if vacancyRec.AuthorID == teamMember.UserID {
+			continue
+		}
+		pushhandler.Instance.SendNotification(teamMember.UserID, data)
+	}
+	return nil
+}
+
+func (i *impl) getStage(applicantRec dbmodels.Applicant, stageName string) (*dbmodels.SelectionStage, error) {
+	stages, err := i.selectionStageStore.List(applicantRec.SpaceID, applicantRec.VacancyID)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения этапов подбора")
+	}
+	for idx := range stages {
+		if stages[idx].Name == stageName {
+			return &stages[idx], nil
+		}
+	}
+	return nil, errors.Errorf("этап «%v» не найден в вакансии", stageName)
+}
+
+func (i *impl) checkVacancy(spaceID, vacancyID string) (hMsg string, err error) {
+	if vacancyID == "" {
+		return "", nil
+	}
+	vacancyRec, err := i.vacancyStore.GetByID(spaceID, vacancyID)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения вакансии")
+	}
+	if vacancyRec == nil {
+		return "вакансия не найдена", nil
+	}
+	return "", nil
+}
+
+func getApplicantState(rec dbmodels.Applicant) (stageName string, vkStatus dbmodels.StepStatus) {
+	if rec.SelectionStage != nil {
+		stageName = rec.SelectionStage.Name
+	}
+	if rec.ApplicantVkStep != nil {
+		vkStatus = rec.ApplicantVkStep.Status
+	}
+	return stageName, vkStatus
+}
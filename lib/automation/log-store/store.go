@@ -0,0 +1,93 @@
+package automationlogstore
+
+import (
+	automationapimodels "hr-tools-backend/models/api/automation"
+	dbmodels "hr-tools-backend/models/db"
+
+	"gorm.io/gorm"
+)
+
+type Provider interface {
+	Create(rec dbmodels.AutomationLog) (id string, err error)
+	IsExecuted(ruleID, applicantID, eventKey string) (bool, error)
+	ListCount(spaceID string, filter automationapimodels.LogFilter) (int64, error)
+	List(spaceID string, filter automationapimodels.LogFilter) ([]dbmodels.AutomationLog, error)
+}
+
+func NewInstance(DB *gorm.DB) Provider {
+	return &impl{
+		db: DB,
+	}
+}
+
+type impl struct {
+	db *gorm.DB
+}
+
+func (i impl) Create(rec dbmodels.AutomationLog) (id string, err error) {
+	err = i.db.
+		Save(&rec).
+		Error
+	if err != nil {
+		return "", err
+	}
+	return rec.ID, nil
+}
+
+// IsExecuted правило уже выполнялось для кандидата по событию (без учета тестовых запусков)
+func (i impl) IsExecuted(ruleID, applicantID, eventKey string) (bool, error) {
+	var rowCount int64
+	err := i.db.
+		Model(&dbmodels.AutomationLog{}).
+		Where("rule_id = ?", ruleID).
+		Where("applicant_id = ?", applicantID).
+		Where("event_key = ?", eventKey).
+		Where("dry_run = ?", false).
+		Count(&rowCount).
+		Error
+	if err != nil {
+		return false, err
+	}
+	return rowCount > 0, nil
+}
+
+func (i impl) ListCount(spaceID string, filter automationapimodels.LogFilter) (int64, error) {
+	var rowCount int64
+	err := i.filter(spaceID, filter).
+		Count(&rowCount).
+		Error
+	if err != nil {
+		return 0, err
+	}
+	return rowCount, nil
+}
+
+func (i impl) List(spaceID string, filter automationapimodels.LogFilter) ([]dbmodels.AutomationLog, error) {
+	list := []dbmodels.AutomationLog{}
+	tx := i.filter(spaceID, filter)
+	page, limit := filter.GetPage()
+	offset := (page - 1) * limit
+	err := tx.
+		Order("created_at desc").
+		Limit(limit).
+		Offset(offset).
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (i impl) filter(spaceID string, filter automationapimodels.LogFilter) *gorm.DB {
+	tx := i.db.
+		Model(&dbmodels.AutomationLog{}).
+		Where("space_id = ?", spaceID)
+	if filter.RuleID != "" {
+		tx = tx.Where("rule_id = ?", filter.RuleID)
+	}
+	if filter.ApplicantID != "" {
+		tx = tx.Where("applicant_id = ?", filter.ApplicantID)
+	}
+	return tx
+}
@@ -0,0 +1,77 @@
+package automation
+
+import (
+	dbmodels "hr-tools-backend/models/db"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleMatchEvent(t *testing.T) {
+	vacancyID := "vacancy"
+	rule := dbmodels.AutomationRule{
+		Trigger:   dbmodels.AutomationTriggerStageChange,
+		VacancyID: &vacancyID,
+		StageName: "Интервью",
+	}
+	require.True(t, rule.MatchEvent(dbmodels.AutomationTriggerStageChange, vacancyID, "Интервью", 0))
+	require.False(t, rule.MatchEvent(dbmodels.AutomationTriggerStageChange, vacancyID, "Оффер", 0))
+	require.False(t, rule.MatchEvent(dbmodels.AutomationTriggerStageChange, "other", "Интервью", 0))
+	require.False(t, rule.MatchEvent(dbmodels.AutomationTriggerNegotiation, vacancyID, "Интервью", 0))
+
+	// без вакансии и этапа - любая вакансия и этап
+	rule = dbmodels.AutomationRule{Trigger: dbmodels.AutomationTriggerStageChange}
+	require.True(t, rule.MatchEvent(dbmodels.AutomationTriggerStageChange, "other", "Оффер", 0))
+
+	status := dbmodels.VkStep10Filtered
+	rule = dbmodels.AutomationRule{Trigger: dbmodels.AutomationTriggerVkStep, VkStatus: &status}
+	require.True(t, rule.MatchEvent(dbmodels.AutomationTriggerVkStep, vacancyID, "", dbmodels.VkStep10Filtered))
+	require.False(t, rule.MatchEvent(dbmodels.AutomationTriggerVkStep, vacancyID, "", dbmodels.VkStep0NotSent))
+}
+
+func TestRuleEventKey(t *testing.T) {
+	rule := dbmodels.AutomationRule{Trigger: dbmodels.AutomationTriggerStageChange}
+	require.Equal(t, "STAGE_CHANGE:Интервью", rule.GetEventKey("Интервью", 0))
+	rule = dbmodels.AutomationRule{Trigger: dbmodels.AutomationTriggerNegotiation}
+	require.Equal(t, "NEGOTIATION", rule.GetEventKey("Интервью", 0))
+}
+
+func TestRuleCheckConditions(t *testing.T) {
+	rec := dbmodels.Applicant{
+		Salary: 120000,
+		Tags:   []string{"java", "senior"},
+		ApplicantSurvey: &dbmodels.ApplicantSurvey{
+			IsScored: true,
+			ScoreAI:  dbmodels.ScoreAI{Score: 75},
+		},
+	}
+	rule := dbmodels.AutomationRule{
+		Conditions: dbmodels.AutomationConditions{
+			{Field: "salary", Operator: dbmodels.AutomationOpLte, Value: "150000"},
+			{Field: "tag", Operator: dbmodels.AutomationOpEq, Value: "Java"},
+			{Field: "survey_score", Operator: dbmodels.AutomationOpGte, Value: "70"},
+		},
+	}
+	require.True(t, rule.CheckConditions(rec))
+
+	rule.Conditions = append(rule.Conditions, dbmodels.AutomationCondition{Field: "tag", Operator: dbmodels.AutomationOpNe, Value: "senior"})
+	require.False(t, rule.CheckConditions(rec))
+
+	// оценка анкеты отсутствует - условие не выполняется
+	rec.ApplicantSurvey = nil
+	rule.Conditions = dbmodels.AutomationConditions{{Field: "survey_score", Operator: dbmodels.AutomationOpLt, Value: "50"}}
+	require.False(t, rule.CheckConditions(rec))
+
+	// без условий правило применяется ко всем кандидатам
+	rule.Conditions = nil
+	require.True(t, rule.CheckConditions(rec))
+}
+
+func TestGetApplicantState(t *testing.T) {
+	stageName, vkStatus := getApplicantState(dbmodels.Applicant{
+		SelectionStage:  &dbmodels.SelectionStage{Name: "Интервью"},
+		ApplicantVkStep: &dbmodels.ApplicantVkStep{Status: dbmodels.VkStep10Filtered},
+	})
+	require.Equal(t, "Интервью", stageName)
+	require.Equal(t, dbmodels.VkStep10Filtered, vkStatus)
+}
@@ -0,0 +1,62 @@
+package automationstageworker
+
+import (
+	"context"
+	"hr-tools-backend/db"
+	"hr-tools-backend/lib/automation"
+	automationevent "hr-tools-backend/lib/automation/event"
+	automationrulestore "hr-tools-backend/lib/automation/store"
+	baseworker "hr-tools-backend/lib/utils/base-worker"
+	"hr-tools-backend/lib/utils/helpers"
+	dbmodels "hr-tools-backend/models/db"
+	"time"
+)
+
+// Задача правил автоматизации по сроку нахождения кандидата на этапе
+func StartWorker(ctx context.Context) {
+	i := &impl{
+		BaseImpl: *baseworker.NewInstance("AutomationStageWorker", 30*time.Second, 5*time.Minute),
+		store:    automationrulestore.NewInstance(db.DB),
+	}
+	go i.Run(ctx, i.handle)
+}
+
+type impl struct {
+	baseworker.BaseImpl
+	store automationrulestore.Provider
+}
+
+func (i impl) handle(ctx context.Context) {
+	logger := i.GetLogger()
+	rules, err := i.store.ListActiveByTrigger(dbmodels.AutomationTriggerTimeInStage)
+	if err != nil {
+		logger.WithError(err).Error("ошибка получения правил автоматизации")
+		return
+	}
+	for _, rule := range rules {
+		if helpers.IsContextDone(ctx) {
+			return
+		}
+		before := time.Now().AddDate(0, 0, -rule.DaysInStage)
+		ids, err := i.store.ListApplicantsInStage(rule, before)
+		if err != nil {
+			logger.
+				WithError(err).
+				WithField("rule_id", rule.ID).
+				Error("ошибка получения кандидатов на этапе")
+			continue
+		}
+		for _, applicantID := range ids {
+			if helpers.IsContextDone(ctx) {
+				return
+			}
+			// повторное выполнение по тому же этапу исключается журналом правил
+			automation.Instance.HandleEvent(automationevent.Event{
+				Trigger:     dbmodels.AutomationTriggerTimeInStage,
+				SpaceID:     rule.SpaceID,
+				ApplicantID: applicantID,
+				RuleID:      rule.ID,
+			})
+		}
+	}
+}
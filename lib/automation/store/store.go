@@ -0,0 +1,146 @@
+package automationrulestore
+
+import (
+	"hr-tools-backend/models"
+	dbmodels "hr-tools-backend/models/db"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Provider interface {
+	Create(rec dbmodels.AutomationRule) (id string, err error)
+	Update(spaceID, id string, updMap map[string]interface{}) error
+	GetByID(spaceID, id string) (*dbmodels.AutomationRule, error)
+	List(spaceID string) ([]dbmodels.AutomationRule, error)
+	ListActive(spaceID string, trigger dbmodels.AutomationTrigger) ([]dbmodels.AutomationRule, error)
+	ListActiveByTrigger(trigger dbmodels.AutomationTrigger) ([]dbmodels.AutomationRule, error)
+	ListApplicantsInStage(rule dbmodels.AutomationRule, before time.Time) ([]string, error)
+	Delete(spaceID, id string) error
+}
+
+func NewInstance(DB *gorm.DB) Provider {
+	return &impl{
+		db: DB,
+	}
+}
+
+type impl struct {
+	db *gorm.DB
+}
+
+func (i impl) Create(rec dbmodels.AutomationRule) (id string, err error) {
+	err = i.db.
+		Omit(clause.Associations).
+		Save(&rec).
+		Error
+	if err != nil {
+		return "", err
+	}
+	return rec.ID, nil
+}
+
+func (i impl) Update(spaceID, id string, updMap map[string]interface{}) error {
+	if len(updMap) == 0 {
+		return nil
+	}
+	return i.db.
+		Model(&dbmodels.AutomationRule{}).
+		Where("space_id = ?", spaceID).
+		Where("id = ?", id).
+		Updates(updMap).
+		Error
+}
+
+func (i impl) GetByID(spaceID, id string) (*dbmodels.AutomationRule, error) {
+	rec := dbmodels.AutomationRule{}
+	err := i.db.
+		Preload("Vacancy").
+		Where("space_id = ?", spaceID).
+		Where("id = ?", id).
+		First(&rec).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (i impl) List(spaceID string) ([]dbmodels.AutomationRule, error) {
+	list := []dbmodels.AutomationRule{}
+	err := i.db.
+		Preload("Vacancy").
+		Where("space_id = ?", spaceID).
+		Order("name").
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (i impl) ListActive(spaceID string, trigger dbmodels.AutomationTrigger) ([]dbmodels.AutomationRule, error) {
+	list := []dbmodels.AutomationRule{}
+	err := i.db.
+		Where("space_id = ?", spaceID).
+		Where("is_active = ?", true).
+		Where("trigger = ?", trigger).
+		Order("created_at").
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (i impl) ListActiveByTrigger(trigger dbmodels.AutomationTrigger) ([]dbmodels.AutomationRule, error) {
+	list := []dbmodels.AutomationRule{}
+	err := i.db.
+		Where("is_active = ?", true).
+		Where("trigger = ?", trigger).
+		Order("created_at").
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// ListApplicantsInStage кандидаты в работе, находящиеся на этапе правила с момента ранее указанного
+func (i impl) ListApplicantsInStage(rule dbmodels.AutomationRule, before time.Time) ([]string, error) {
+	ids := []string{}
+	tx := i.db.
+		Model(&dbmodels.Applicant{}).
+		Joins("join selection_stages ss on ss.id = applicants.selection_stage_id").
+		Where("applicants.space_id = ?", rule.SpaceID).
+		Where("applicants.status = ?", models.ApplicantStatusInProcess).
+		Where("COALESCE((select max(h.created_at) from applicant_histories h where h.applicant_id = applicants.id and h.action_type = ?), applicants.negotiation_accept_date) < ?",
+			dbmodels.HistoryTypeStageChange, before)
+	if rule.StageName != "" {
+		tx = tx.Where("ss.name = ?", rule.StageName)
+	}
+	if rule.VacancyID != nil && *rule.VacancyID != "" {
+		tx = tx.Where("applicants.vacancy_id = ?", *rule.VacancyID)
+	}
+	err := tx.Pluck("applicants.id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (i impl) Delete(spaceID, id string) error {
+	return i.db.
+		Where("space_id = ?", spaceID).
+		Where("id = ?", id).
+		Delete(&dbmodels.AutomationRule{}).
+		Error
+}
@@ -0,0 +1,250 @@
+package caldav
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	safehttp "hr-tools-backend/lib/utils/safe-http"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrNotFound           = errors.New("ресурс календаря не найден")
+	ErrPreconditionFailed = errors.New("событие изменено в календаре")
+	ErrUnauthorized       = errors.New("неверный логин или пароль календаря")
+)
+
+const (
+	timeRangeFormat = "20060102T150405Z"
+	maxResponseSize = 10 * 1024 * 1024
+)
+
+// newHTTPClient адрес календаря указывает пользователь, соединения с внутренней сетью запрещены
+var newHTTPClient = safehttp.NewClient
+
+// Client клиент CalDAV (RFC 4791) для коллекции календаря пользователя
+type Client struct {
+	baseURL    *url.URL
+	login      string
+	password   string
+	httpClient *http.Client
+}
+
+// NewClient calendarURL - адрес коллекции календаря
+func NewClient(calendarURL, login, password string, timeout time.Duration) (*Client, error) {
+	baseURL, err := url.Parse(calendarURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "некорректный адрес календаря")
+	}
+	if baseURL.Scheme != "http" && baseURL.Scheme != "https" {
+		return nil, errors.New("некорректный адрес календаря")
+	}
+	if !strings.HasSuffix(baseURL.Path, "/") {
+		baseURL.Path += "/"
+	}
+	return &Client{
+		baseURL:    baseURL,
+		login:      login,
+		password:   password,
+		httpClient: newHTTPClient(timeout),
+	}, nil
+}
+
+// Check проверка доступа к коллекции календаря
+func (c Client) Check(ctx context.Context) error {
+	body := `<?xml version="1.0" encoding="utf-8"?><d:propfind xmlns:d="DAV:"><d:prop><d:resourcetype/></d:prop></d:propfind>`
+	resp, err := c.do(ctx, "PROPFIND", c.baseURL.String(), []byte(body), map[string]string{
+		"Depth":        "0",
+		"Content-Type": "application/xml; charset=utf-8",
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return checkStatus(resp, http.StatusMultiStatus, http.StatusOK)
+}
+
+// Put сохранение события, etag - версия события для изменения существующего, пусто - новое событие.
+// Возвращает адрес и новую версию события (версия пустая, если сервер ее не вернул)
+func (c Client) Put(ctx context.Context, name string, data []byte, etag string) (href, newETag string, err error) {
+	href = c.baseURL.ResolveReference(&url.URL{Path: name}).String()
+	headers := map[string]string{
+		"Content-Type": "text/calendar; charset=utf-8",
+	}
+	if etag != "" {
+		headers["If-Match"] = etag
+	} else {
+		headers["If-None-Match"] = "*"
+	}
+	resp, err := c.do(ctx, http.MethodPut, href, data, headers)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+	err = checkStatus(resp, http.StatusCreated, http.StatusNoContent, http.StatusOK)
+	if err != nil {
+		return "", "", err
+	}
+	return href, resp.Header.Get("ETag"), nil
+}
+
+// Get получение события по адресу
+func (c Client) Get(ctx context.Context, href string) (data []byte, etag string, err error) {
+	resp, err := c.do(ctx, http.MethodGet, href, nil, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	err = checkStatus(resp, http.StatusOK)
+	if err != nil {
+		return nil, "", err
+	}
+	data, err = io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, "", errors.Wrap(err, "ошибка чтения события")
+	}
+	return data, resp.Header.Get("ETag"), nil
+}
+
+// Delete удаление события, отсутствующее событие не считается ошибкой
+func (c Client) Delete(ctx context.Context, href, etag string) error {
+	headers := map[string]string{}
+	if etag != "" {
+		headers["If-Match"] = etag
+	}
+	resp, err := c.do(ctx, http.MethodDelete, href, nil, headers)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	err = checkStatus(resp, http.StatusNoContent, http.StatusOK)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// Query события календаря, пересекающиеся с интервалом [from, to)
+func (c Client) Query(ctx context.Context, from, to time.Time) ([]Event, error) {
+	body := fmt.Sprintf(`<?xml version="1.0" encoding="utf-8"?>
+<c:calendar-query xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+  <d:prop><d:getetag/><c:calendar-data/></d:prop>
+  <c:filter>
+    <c:comp-filter name="VCALENDAR">
+      <c:comp-filter name="VEVENT">
+        <c:time-range start="%v" end="%v"/>
+      </c:comp-filter>
+    </c:comp-filter>
+  </c:filter>
+</c:calendar-query>`, from.UTC().Format(timeRangeFormat), to.UTC().Format(timeRangeFormat))
+	resp, err := c.do(ctx, "REPORT", c.baseURL.String(), []byte(body), map[string]string{
+		"Depth":        "1",
+		"Content-Type": "application/xml; charset=utf-8",
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	err = checkStatus(resp, http.StatusMultiStatus)
+	if err != nil {
+		return nil, err
+	}
+	ms := multiStatus{}
+	err = xml.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&ms)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка разбора ответа календаря")
+	}
+	result := []Event{}
+	for _, item := range ms.Responses {
+		for _, propStat := range item.PropStats {
+			if propStat.Prop.CalendarData == "" {
+				continue
+			}
+			events, err := ParseEvents([]byte(propStat.Prop.CalendarData))
+			if err != nil {
+				return nil, errors.Wrapf(err, "ошибка разбора события %v", item.Href)
+			}
+			result = append(result, events...)
+		}
+	}
+	return result, nil
+}
+
+// FetchICS получение событий календаря по ссылке в формате iCalendar (только чтение)
+func FetchICS(ctx context.Context, icsURL, login, password string, timeout time.Duration) ([]Event, error) {
+	u, err := url.Parse(icsURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "некорректная ссылка на календарь")
+	}
+	if u.Scheme == "webcal" {
+		u.Scheme = "https"
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, errors.New("некорректная ссылка на календарь")
+	}
+	client := Client{
+		baseURL:    u,
+		login:      login,
+		password:   password,
+		httpClient: newHTTPClient(timeout),
+	}
+	data, _, err := client.Get(ctx, u.String())
+	if err != nil {
+		return nil, err
+	}
+	return ParseEvents(data)
+}
+
+func (c Client) do(ctx context.Context, method, href string, body []byte, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, href, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка формирования запроса к календарю")
+	}
+	if c.login != "" || c.password != "" {
+		req.SetBasicAuth(c.login, c.password)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка отправки запроса к календарю")
+	}
+	return resp, nil
+}
+
+func checkStatus(resp *http.Response, expected ...int) error {
+	for _, code := range expected {
+		if resp.StatusCode == code {
+			return nil
+		}
+	}
+	switch resp.StatusCode {
+	case http.StatusNotFound, http.StatusGone:
+		return ErrNotFound
+	case http.StatusPreconditionFailed:
+		return ErrPreconditionFailed
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrUnauthorized
+	}
+	return errors.Errorf("календарь вернул неожиданный ответ: %v", resp.Status)
+}
+
+type multiStatus struct {
+	Responses []struct {
+		Href      string `xml:"href"`
+		PropStats []struct {
+			Prop struct {
+				ETag         string `xml:"getetag"`
+				CalendarData string `xml:"calendar-data"`
+			} `xml:"prop"`
+		} `xml:"propstat"`
+	} `xml:"response"`
+}
@@ -0,0 +1,240 @@
+package caldav
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	safehttp "hr-tools-backend/lib/utils/safe-http"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServer локальная замена CalDAV сервера: коллекция /calendars/user/ с basic-авторизацией
+type fakeServer struct {
+	mu      sync.Mutex
+	events  map[string]string // путь -> данные события
+	etags   map[string]string
+	version int
+}
+
+// allowLocalServer разрешение соединений с тестовым сервером на локальном адресе
+func allowLocalServer(t *testing.T) {
+	defaultClient := newHTTPClient
+	newHTTPClient = func(timeout time.Duration) *http.Client {
+		return &http.Client{Timeout: timeout}
+	}
+	t.Cleanup(func() { newHTTPClient = defaultClient })
+}
+
+func newFakeServer() (*fakeServer, *httptest.Server) {
+	fake := &fakeServer{events: map[string]string{}, etags: map[string]string{}}
+	return fake, httptest.NewServer(fake)
+}
+
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	login, password, ok := r.BasicAuth()
+	if !ok || login != "user" || password != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if !strings.HasPrefix(r.URL.Path, "/calendars/user/") {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	path := r.URL.Path
+	switch r.Method {
+	case "PROPFIND":
+		w.WriteHeader(http.StatusMultiStatus)
+		fmt.Fprint(w, `<?xml version="1.0"?><d:multistatus xmlns:d="DAV:"><d:response><d:href>/calendars/user/</d:href></d:response></d:multistatus>`)
+	case http.MethodPut:
+		etag, exists := f.etags[path]
+		if match := r.Header.Get("If-Match"); match != "" && match != etag {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		if r.Header.Get("If-None-Match") == "*" && exists {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		f.set(path, string(data))
+		w.Header().Set("ETag", f.etags[path])
+		if exists {
+			w.WriteHeader(http.StatusNoContent)
+		} else {
+			w.WriteHeader(http.StatusCreated)
+		}
+	case http.MethodGet:
+		data, ok := f.events[path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("ETag", f.etags[path])
+		fmt.Fprint(w, data)
+	case http.MethodDelete:
+		if _, ok := f.events[path]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(f.events, path)
+		delete(f.etags, path)
+		w.WriteHeader(http.StatusNoContent)
+	case "REPORT":
+		w.WriteHeader(http.StatusMultiStatus)
+		fmt.Fprint(w, `<?xml version="1.0"?><d:multistatus xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">`)
+		for href, data := range f.events {
+			fmt.Fprintf(w, `<d:response><d:href>%v</d:href><d:propstat><d:prop><d:getetag>%v</d:getetag><c:calendar-data>`, href, f.etags[href])
+			_ = xml.EscapeText(w, []byte(data))
+			fmt.Fprint(w, `</c:calendar-data></d:prop></d:propstat></d:response>`)
+		}
+		fmt.Fprint(w, `</d:multistatus>`)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// set изменение события на стороне сервера (в т.ч. пользователем в календаре)
+func (f *fakeServer) set(path, data string) {
+	f.version++
+	f.events[path] = data
+	f.etags[path] = fmt.Sprintf(`"%d"`, f.version)
+}
+
+func getEventData(uid string, startAt, endAt time.Time) string {
+	return "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VEVENT\r\n" +
+		"UID:" + uid + "\r\n" +
+		"DTSTART:" + startAt.UTC().Format(timeRangeFormat) + "\r\n" +
+		"DTEND:" + endAt.UTC().Format(timeRangeFormat) + "\r\n" +
+		"SUMMARY:Интервью\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+}
+
+func TestClient(t *testing.T) {
+	allowLocalServer(t)
+	fake, server := newFakeServer()
+	defer server.Close()
+	ctx := context.Background()
+	startAt := time.Date(2024, 5, 10, 9, 0, 0, 0, time.UTC)
+	endAt := startAt.Add(time.Hour)
+
+	t.Run("check", func(t *testing.T) {
+		client, err := NewClient(server.URL+"/calendars/user", "user", "secret", time.Second)
+		require.NoError(t, err)
+		require.NoError(t, client.Check(ctx))
+
+		client, err = NewClient(server.URL+"/calendars/user/", "user", "wrong", time.Second)
+		require.NoError(t, err)
+		require.ErrorIs(t, client.Check(ctx), ErrUnauthorized)
+
+		client, err = NewClient(server.URL+"/calendars/other/", "user", "secret", time.Second)
+		require.NoError(t, err)
+		require.ErrorIs(t, client.Check(ctx), ErrNotFound)
+
+		_, err = NewClient("ftp://example.com/cal", "user", "secret", time.Second)
+		require.Error(t, err)
+	})
+
+	client, err := NewClient(server.URL+"/calendars/user/", "user", "secret", time.Second)
+	require.NoError(t, err)
+
+	t.Run("put, get, move, delete", func(t *testing.T) {
+		href, etag, err := client.Put(ctx, "id@hr-tools.ics", []byte(getEventData("id@hr-tools", startAt, endAt)), "")
+		require.NoError(t, err)
+		require.Equal(t, server.URL+"/calendars/user/id@hr-tools.ics", href)
+		require.NotEmpty(t, etag)
+
+		// повторное создание не перезаписывает существующее событие
+		_, _, err = client.Put(ctx, "id@hr-tools.ics", []byte(getEventData("id@hr-tools", startAt, endAt)), "")
+		require.ErrorIs(t, err, ErrPreconditionFailed)
+
+		data, getETag, err := client.Get(ctx, href)
+		require.NoError(t, err)
+		require.Equal(t, etag, getETag)
+		events, err := ParseEvents(data)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		require.True(t, events[0].StartAt.Equal(startAt))
+
+		// пользователь перенес событие в календаре
+		fake.mu.Lock()
+		fake.set("/calendars/user/id@hr-tools.ics", getEventData("id@hr-tools", startAt.Add(2*time.Hour), endAt.Add(2*time.Hour)))
+		fake.mu.Unlock()
+		_, _, err = client.Put(ctx, "id@hr-tools.ics", []byte(getEventData("id@hr-tools", startAt, endAt)), etag)
+		require.ErrorIs(t, err, ErrPreconditionFailed)
+
+		data, movedETag, err := client.Get(ctx, href)
+		require.NoError(t, err)
+		require.NotEqual(t, etag, movedETag)
+		events, err = ParseEvents(data)
+		require.NoError(t, err)
+		require.True(t, events[0].StartAt.Equal(startAt.Add(2*time.Hour)))
+
+		_, newETag, err := client.Put(ctx, "id@hr-tools.ics", []byte(getEventData("id@hr-tools", startAt, endAt)), movedETag)
+		require.NoError(t, err)
+		require.NotEqual(t, movedETag, newETag)
+
+		require.NoError(t, client.Delete(ctx, href, ""))
+		require.NoError(t, client.Delete(ctx, href, ""))
+		_, _, err = client.Get(ctx, href)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("query", func(t *testing.T) {
+		_, _, err := client.Put(ctx, "a.ics", []byte(getEventData("a", startAt, endAt)), "")
+		require.NoError(t, err)
+		_, _, err = client.Put(ctx, "b.ics", []byte(getEventData("b", startAt.AddDate(0, 0, 1), endAt.AddDate(0, 0, 1))), "")
+		require.NoError(t, err)
+
+		events, err := client.Query(ctx, startAt.Add(-time.Hour), startAt.Add(3*time.Hour))
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		busy := 0
+		for _, event := range events {
+			if event.IsBusy(startAt.Add(-time.Hour), startAt.Add(3*time.Hour)) {
+				busy++
+			}
+		}
+		require.Equal(t, 1, busy)
+	})
+}
+
+func TestFetchICS(t *testing.T) {
+	allowLocalServer(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("token") != "abc" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		fmt.Fprint(w, getEventData("a", time.Date(2024, 5, 10, 9, 0, 0, 0, time.UTC), time.Date(2024, 5, 10, 10, 0, 0, 0, time.UTC)))
+	}))
+	defer server.Close()
+
+	events, err := FetchICS(context.Background(), server.URL+"/basic.ics?token=abc", "", "", time.Second)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, "a", events[0].UID)
+
+	_, err = FetchICS(context.Background(), server.URL+"/basic.ics", "", "", time.Second)
+	require.ErrorIs(t, err, ErrUnauthorized)
+}
+
+func TestClientDeniesInternalAddress(t *testing.T) {
+	_, server := newFakeServer()
+	defer server.Close()
+
+	client, err := NewClient(server.URL+"/calendars/user/", "user", "secret", time.Second)
+	require.NoError(t, err)
+	require.ErrorIs(t, client.Check(context.Background()), safehttp.ErrDeniedAddress)
+
+	_, err = FetchICS(context.Background(), server.URL+"/basic.ics", "", "", time.Second)
+	require.ErrorIs(t, err, safehttp.ErrDeniedAddress)
+}
@@ -0,0 +1,202 @@
+package caldav
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Event событие календаря
+type Event struct {
+	UID         string
+	Summary     string
+	StartAt     time.Time
+	EndAt       time.Time
+	Canceled    bool // STATUS:CANCELLED
+	Transparent bool // TRANSP:TRANSPARENT, событие не занимает время
+}
+
+// IsBusy событие занимает время в интервале [from, to)
+func (e Event) IsBusy(from, to time.Time) bool {
+	if e.Canceled || e.Transparent {
+		return false
+	}
+	return e.StartAt.Before(to) && e.EndAt.After(from)
+}
+
+var durationRe = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// ParseEvents разбор событий (VEVENT) из данных в формате iCalendar.
+// Повторяющиеся события (RRULE) учитываются только первым вхождением
+func ParseEvents(data []byte) ([]Event, error) {
+	lines := unfoldLines(data)
+	result := []Event{}
+	var event *Event
+	var duration time.Duration
+	allDay := false
+	depth := 0 // вложенные компоненты события (VALARM)
+	for _, line := range lines {
+		name, params, value := splitProperty(line)
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			event = &Event{}
+			duration = 0
+			allDay = false
+			depth = 0
+			continue
+		case event == nil:
+			continue
+		case name == "BEGIN":
+			depth++
+			continue
+		case name == "END" && value != "VEVENT":
+			depth--
+			continue
+		case name == "END":
+			if event.StartAt.IsZero() {
+				return nil, errors.Errorf("у события %v не указано время начала", event.UID)
+			}
+			if event.EndAt.IsZero() {
+				event.EndAt = event.StartAt.Add(duration)
+				if duration == 0 && allDay {
+					// событие на весь день без окончания
+					event.EndAt = event.StartAt.AddDate(0, 0, 1)
+				}
+			}
+			result = append(result, *event)
+			event = nil
+			continue
+		}
+		if depth != 0 {
+			continue
+		}
+		var err error
+		switch name {
+		case "UID":
+			event.UID = value
+		case "SUMMARY":
+			event.Summary = unescapeText(value)
+		case "STATUS":
+			event.Canceled = strings.EqualFold(value, "CANCELLED")
+		case "TRANSP":
+			event.Transparent = strings.EqualFold(value, "TRANSPARENT")
+		case "DTSTART":
+			event.StartAt, err = parseTime(params, value)
+			allDay = isDate(params, value)
+		case "DTEND":
+			event.EndAt, err = parseTime(params, value)
+		case "DURATION":
+			duration, err = parseDuration(value)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "ошибка разбора свойства %v события %v", name, event.UID)
+		}
+	}
+	return result, nil
+}
+
+// unfoldLines разбивка на строки с объединением перенесенных строк (RFC 5545, 3.1)
+func unfoldLines(data []byte) []string {
+	lines := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(lines) != 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// splitProperty разбор строки вида NAME;PARAM=VALUE:VALUE
+func splitProperty(line string) (name string, params map[string]string, value string) {
+	head, value, _ := cutUnquoted(line, ':')
+	parts := strings.Split(head, ";")
+	name = strings.ToUpper(parts[0])
+	for _, part := range parts[1:] {
+		key, val, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		if params == nil {
+			params = map[string]string{}
+		}
+		params[strings.ToUpper(key)] = strings.Trim(val, `"`)
+	}
+	return name, params, value
+}
+
+// cutUnquoted strings.Cut без учета разделителя в кавычках (значения параметров)
+func cutUnquoted(s string, sep byte) (before, after string, found bool) {
+	quoted := false
+	for k := 0; k < len(s); k++ {
+		switch s[k] {
+		case '"':
+			quoted = !quoted
+		case sep:
+			if !quoted {
+				return s[:k], s[k+1:], true
+			}
+		}
+	}
+	return s, "", false
+}
+
+func parseTime(params map[string]string, value string) (time.Time, error) {
+	if isDate(params, value) {
+		return time.ParseInLocation("20060102", value, time.UTC)
+	}
+	if strings.HasSuffix(value, "Z") {
+		return time.Parse("20060102T150405Z", value)
+	}
+	loc := time.UTC
+	if tzID := params["TZID"]; tzID != "" {
+		tz, err := time.LoadLocation(tzID)
+		if err == nil {
+			loc = tz
+		}
+	}
+	return time.ParseInLocation("20060102T150405", value, loc)
+}
+
+func parseDuration(value string) (time.Duration, error) {
+	match := durationRe.FindStringSubmatch(value)
+	if match == nil {
+		return 0, errors.Errorf("некорректная длительность %v", value)
+	}
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var result time.Duration
+	for k, unit := range units {
+		if match[k+2] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(match[k+2])
+		if err != nil {
+			return 0, err
+		}
+		result += time.Duration(n) * unit
+	}
+	if match[1] == "-" {
+		result = -result
+	}
+	return result, nil
+}
+
+func isDate(params map[string]string, value string) bool {
+	return params["VALUE"] == "DATE" || len(value) == len("20060102")
+}
+
+func unescapeText(text string) string {
+	return strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(text)
+}
@@ -0,0 +1,79 @@
+package caldav
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEvents(t *testing.T) {
+	data := "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"BEGIN:VTIMEZONE\r\nTZID:Europe/Moscow\r\nBEGIN:STANDARD\r\nDTSTART:19700101T000000\r\nEND:STANDARD\r\nEND:VTIMEZONE\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:1\r\n" +
+		"DTSTART;TZID=Europe/Moscow:20240510T120000\r\n" +
+		"DTEND;TZID=\"Europe/Moscow\":20240510T133000\r\n" +
+		"SUMMARY:Встреча\\, важная\r\n" +
+		"BEGIN:VALARM\r\nTRIGGER:-PT15M\r\nDTSTART:20000101T000000Z\r\nEND:VALARM\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:2\r\n" +
+		"DTSTART:20240510T090000Z\r\n" +
+		"DURATION:PT1H30M\r\n" +
+		"TRANSP:TRANSPARENT\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:3\r\n" +
+		"DTSTART;VALUE=DATE:20240511\r\n" +
+		"STATUS:CANCELLED\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:very-long-\r\n" +
+		" uid\r\n" +
+		"DTSTART:20240512T090000Z\r\n" +
+		"DTEND:20240512T100000Z\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+	events, err := ParseEvents([]byte(data))
+	require.NoError(t, err)
+	require.Len(t, events, 4)
+
+	require.Equal(t, "1", events[0].UID)
+	require.Equal(t, "Встреча, важная", events[0].Summary)
+	require.True(t, events[0].StartAt.Equal(time.Date(2024, 5, 10, 9, 0, 0, 0, time.UTC)))
+	require.True(t, events[0].EndAt.Equal(time.Date(2024, 5, 10, 10, 30, 0, 0, time.UTC)))
+
+	require.True(t, events[1].EndAt.Equal(time.Date(2024, 5, 10, 10, 30, 0, 0, time.UTC)))
+	require.True(t, events[1].Transparent)
+	require.False(t, events[1].IsBusy(events[1].StartAt, events[1].EndAt))
+
+	require.True(t, events[2].Canceled)
+	require.True(t, events[2].EndAt.Equal(time.Date(2024, 5, 12, 0, 0, 0, 0, time.UTC)))
+
+	require.Equal(t, "very-long-uid", events[3].UID)
+	require.True(t, events[3].IsBusy(time.Date(2024, 5, 12, 9, 30, 0, 0, time.UTC), time.Date(2024, 5, 12, 11, 0, 0, 0, time.UTC)))
+	require.False(t, events[3].IsBusy(time.Date(2024, 5, 12, 10, 0, 0, 0, time.UTC), time.Date(2024, 5, 12, 11, 0, 0, 0, time.UTC)))
+
+	_, err = ParseEvents([]byte("BEGIN:VEVENT\r\nUID:x\r\nEND:VEVENT\r\n"))
+	require.Error(t, err)
+}
+
+func TestParseDuration(t *testing.T) {
+	cases := map[string]time.Duration{
+		"PT15M":   15 * time.Minute,
+		"P1D":     24 * time.Hour,
+		"P1W":     7 * 24 * time.Hour,
+		"P1DT2H":  26 * time.Hour,
+		"-PT30S":  -30 * time.Second,
+		"PT1H30M": 90 * time.Minute,
+	}
+	for value, expected := range cases {
+		result, err := parseDuration(value)
+		require.NoError(t, err, value)
+		require.Equal(t, expected, result, value)
+	}
+	_, err := parseDuration("1H")
+	require.Error(t, err)
+}
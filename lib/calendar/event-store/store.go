@@ -0,0 +1,96 @@
+package calendareventstore
+
+import (
+	dbmodels "hr-tools-backend/models/db"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Provider interface {
+	Create(rec dbmodels.InterviewCalendarEvent) (id string, err error)
+	Update(id string, updMap map[string]interface{}) error
+	Delete(id string) error
+	DeleteByConnection(spaceID, connectionID string) error
+	ListByInterview(spaceID, interviewID string) ([]dbmodels.InterviewCalendarEvent, error)
+	// ListForSync события предстоящих назначенных интервью для проверки изменений в календарях
+	ListForSync(now time.Time) ([]dbmodels.InterviewCalendarEvent, error)
+}
+
+func NewInstance(DB *gorm.DB) Provider {
+	return &impl{
+		db: DB,
+	}
+}
+
+type impl struct {
+	db *gorm.DB
+}
+
+func (i impl) Create(rec dbmodels.InterviewCalendarEvent) (id string, err error) {
+	err = i.db.
+		Omit(clause.Associations).
+		Save(&rec).
+		Error
+	if err != nil {
+		return "", err
+	}
+	return rec.ID, nil
+}
+
+func (i impl) Update(id string, updMap map[string]interface{}) error {
+	if len(updMap) == 0 {
+		return nil
+	}
+	return i.db.
+		Model(&dbmodels.InterviewCalendarEvent{}).
+		Where("id = ?", id).
+		Updates(updMap).
+		Error
+}
+
+func (i impl) Delete(id string) error {
+	return i.db.
+		Where("id = ?", id).
+		Delete(&dbmodels.InterviewCalendarEvent{}).
+		Error
+}
+
+func (i impl) DeleteByConnection(spaceID, connectionID string) error {
+	return i.db.
+		Where("space_id = ?", spaceID).
+		Where("connection_id = ?", connectionID).
+		Delete(&dbmodels.InterviewCalendarEvent{}).
+		Error
+}
+
+func (i impl) ListByInterview(spaceID, interviewID string) ([]dbmodels.InterviewCalendarEvent, error) {
+	list := []dbmodels.InterviewCalendarEvent{}
+	err := i.db.
+		Preload("Connection").
+		Where("space_id = ?", spaceID).
+		Where("interview_id = ?", interviewID).
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (i impl) ListForSync(now time.Time) ([]dbmodels.InterviewCalendarEvent, error) {
+	list := []dbmodels.InterviewCalendarEvent{}
+	err := i.db.
+		Preload("Connection").
+		Joins("join interviews on interviews.id = interview_calendar_events.interview_id").
+		Where("interviews.status = ?", dbmodels.InterviewScheduled).
+		Where("interviews.end_at > ?", now).
+		Order("interview_calendar_events.connection_id").
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
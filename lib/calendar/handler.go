@@ -0,0 +1,466 @@
+package calendar
+
+import (
+	"context"
+	"hr-tools-backend/config"
+	"hr-tools-backend/db"
+	"hr-tools-backend/lib/calendar/caldav"
+	calendareventstore "hr-tools-backend/lib/calendar/event-store"
+	calendarstore "hr-tools-backend/lib/calendar/store"
+	authhelpers "hr-tools-backend/lib/utils/auth-helpers"
+	"hr-tools-backend/lib/utils/helpers"
+	safehttp "hr-tools-backend/lib/utils/safe-http"
+	calendarapimodels "hr-tools-backend/models/api/calendar"
+	dbmodels "hr-tools-backend/models/db"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type Provider interface {
+	GetConnection(spaceID, userID string) (*calendarapimodels.ConnectionView, error)
+	SetConnection(spaceID, userID string, data calendarapimodels.ConnectionData) (hMsg string, err error)
+	DeleteConnection(spaceID, userID string) error
+	// GetBusy занятость пользователей по их календарям, пользователи без подключенного календаря не учитываются
+	GetBusy(spaceID string, userIDs []string, from, to time.Time) ([]calendarapimodels.BusyView, error)
+	// PushInterview размещение, изменение или удаление события интервью в CalDAV календарях участников
+	PushInterview(spaceID string, event InterviewEvent)
+	// GetMovedInterviews проверка событий интервью в календарях участников, возвращает интервью, перенесенные в календаре
+	GetMovedInterviews(ctx context.Context) []MovedInterview
+}
+
+var Instance Provider
+
+func NewHandler() {
+	instance := impl{
+		store:      calendarstore.NewInstance(db.DB),
+		eventStore: calendareventstore.NewInstance(db.DB),
+	}
+	Instance = instance
+}
+
+type impl struct {
+	store      calendarstore.Provider
+	eventStore calendareventstore.Provider
+}
+
+// InterviewEvent событие интервью для календарей участников
+type InterviewEvent struct {
+	InterviewID string
+	UID         string
+	UserIDs     []string // участники, в календарях которых должно быть событие
+	StartAt     time.Time
+	EndAt       time.Time
+	Canceled    bool
+	Data        []byte // событие в формате iCalendar
+}
+
+// MovedInterview интервью, время которого изменено в календаре участника
+type MovedInterview struct {
+	SpaceID     string
+	InterviewID string
+	UserID      string
+	StartAt     time.Time
+	EndAt       time.Time
+}
+
+func (i impl) getLogger(spaceID, userID string) *log.Entry {
+	return log.
+		WithField("space_id", spaceID).
+		WithField("user_id", userID)
+}
+
+func (i impl) GetConnection(spaceID, userID string) (*calendarapimodels.ConnectionView, error) {
+	rec, err := i.store.GetByUser(spaceID, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения подключения календаря")
+	}
+	if rec == nil {
+		return nil, nil
+	}
+	result := calendarapimodels.ConnectionView{
+		Type:       rec.Type,
+		Login:      rec.Login,
+		LastSyncAt: rec.LastSyncAt,
+		LastError:  rec.LastError,
+	}
+	calendarURL, err := authhelpers.DecryptString(config.Conf.Calendar.SecretKey, rec.URL)
+	if err != nil {
+		i.getLogger(spaceID, userID).WithError(err).Warn("ошибка расшифровки адреса календаря")
+	} else if u, err := url.Parse(calendarURL); err == nil {
+		result.Host = u.Host
+	}
+	return &result, nil
+}
+
+func (i impl) SetConnection(spaceID, userID string, data calendarapimodels.ConnectionData) (hMsg string, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), i.getTimeout())
+	defer cancel()
+	// webcal - схема ссылок на календарь, запрашиваемых по https
+	hMsg, err = safehttp.CheckUrl(ctx, strings.Replace(data.URL, "webcal://", "https://", 1))
+	if err != nil || hMsg != "" {
+		return hMsg, err
+	}
+	logger := i.getLogger(spaceID, userID)
+	switch data.Type {
+	case dbmodels.CalendarTypeCalDAV:
+		client, err := caldav.NewClient(data.URL, data.Login, data.Password, i.getTimeout())
+		if err != nil {
+			return err.Error(), nil
+		}
+		err = client.Check(ctx)
+		if err != nil {
+			logger.WithError(err).Warn("ошибка подключения к календарю")
+			return getConnectionMsg(err), nil
+		}
+	case dbmodels.CalendarTypeICS:
+		_, err = caldav.FetchICS(ctx, data.URL, data.Login, data.Password, i.getTimeout())
+		if err != nil {
+			logger.WithError(err).Warn("ошибка подключения к календарю")
+			return getConnectionMsg(err), nil
+		}
+	}
+	secret := config.Conf.Calendar.SecretKey
+	rec := dbmodels.CalendarConnection{
+		BaseSpaceModel: dbmodels.BaseSpaceModel{
+			SpaceID: spaceID,
+		},
+		UserID: userID,
+		Type:   data.Type,
+		Login:  data.Login,
+	}
+	rec.URL, err = authhelpers.EncryptString(secret, data.URL)
+	if err != nil {
+		return "", err
+	}
+	rec.Password, err = authhelpers.EncryptString(secret, data.Password)
+	if err != nil {
+		return "", err
+	}
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		// события в прежнем календаре больше не синхронизируются
+		err := i.deleteConnection(tx, spaceID, userID)
+		if err != nil {
+			return err
+		}
+		_, err = calendarstore.NewInstance(tx).Save(rec)
+		return err
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка сохранения подключения календаря")
+	}
+	return "", nil
+}
+
+func (i impl) DeleteConnection(spaceID, userID string) error {
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		return i.deleteConnection(tx, spaceID, userID)
+	})
+	if err != nil {
+		return errors.Wrap(err, "ошибка удаления подключения календаря")
+	}
+	return nil
+}
+
+func (i impl) GetBusy(spaceID string, userIDs []string, from, to time.Time) ([]calendarapimodels.BusyView, error) {
+	connections, err := i.store.ListByUsers(spaceID, userIDs)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения подключений календарей")
+	}
+	result := []calendarapimodels.BusyView{}
+	wg := sync.WaitGroup{}
+	mu := sync.Mutex{}
+	for _, conn := range connections {
+		wg.Add(1)
+		go func(conn dbmodels.CalendarConnection) {
+			defer wg.Done()
+			events, err := i.getEvents(conn, from, to)
+			if err != nil {
+				i.getLogger(spaceID, conn.UserID).WithError(err).Warn("ошибка получения занятости из календаря")
+				i.setSyncState(conn, err)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for _, event := range events {
+				if !event.IsBusy(from, to) {
+					continue
+				}
+				result = append(result, calendarapimodels.BusyView{
+					UserID:  conn.UserID,
+					StartAt: event.StartAt,
+					EndAt:   event.EndAt,
+				})
+			}
+		}(conn)
+	}
+	wg.Wait()
+	slices.SortFunc(result, func(a, b calendarapimodels.BusyView) int {
+		return a.StartAt.Compare(b.StartAt)
+	})
+	return result, nil
+}
+
+func (i impl) PushInterview(spaceID string, event InterviewEvent) {
+	logger := log.
+		WithField("space_id", spaceID).
+		WithField("interview_id", event.InterviewID)
+	existing, err := i.eventStore.ListByInterview(spaceID, event.InterviewID)
+	if err != nil {
+		logger.WithError(err).Error("ошибка получения событий интервью в календарях")
+		return
+	}
+	connections, err := i.store.ListByUsers(spaceID, event.UserIDs)
+	if err != nil {
+		logger.WithError(err).Error("ошибка получения подключений календарей")
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), i.getTimeout())
+	defer cancel()
+
+	// удаление событий из календарей исключенных участников и при отмене интервью
+	for _, rec := range existing {
+		if !event.Canceled && slices.Contains(event.UserIDs, rec.UserID) {
+			continue
+		}
+		if rec.Connection != nil {
+			client, err := i.newCalDAVClient(*rec.Connection)
+			if err == nil {
+				err = client.Delete(ctx, rec.Href, "")
+			}
+			if err != nil {
+				logger.WithError(err).WithField("user_id", rec.UserID).Warn("ошибка удаления события интервью из календаря")
+			}
+		}
+		err = i.eventStore.Delete(rec.ID)
+		if err != nil {
+			logger.WithError(err).Error("ошибка удаления события интервью")
+		}
+	}
+	if event.Canceled {
+		return
+	}
+
+	for _, conn := range connections {
+		if conn.Type != dbmodels.CalendarTypeCalDAV {
+			continue
+		}
+		userLogger := logger.WithField("user_id", conn.UserID)
+		idx := slices.IndexFunc(existing, func(rec dbmodels.InterviewCalendarEvent) bool {
+			return rec.ConnectionID == conn.ID
+		})
+		client, err := i.newCalDAVClient(conn)
+		if err != nil {
+			userLogger.WithError(err).Warn("ошибка подключения к календарю")
+			continue
+		}
+		etag := ""
+		if idx != -1 {
+			etag = existing[idx].ETag
+		}
+		href, newETag, err := client.Put(ctx, event.UID+".ics", event.Data, etag)
+		if err != nil {
+			// ErrPreconditionFailed - событие изменено в календаре, изменение будет обработано при синхронизации
+			userLogger.WithError(err).Warn("ошибка сохранения события интервью в календаре")
+			i.setSyncState(conn, err)
+			continue
+		}
+		if idx != -1 {
+			updMap := map[string]interface{}{
+				"href":     href,
+				"e_tag":    newETag,
+				"start_at": event.StartAt,
+				"end_at":   event.EndAt,
+			}
+			err = i.eventStore.Update(existing[idx].ID, updMap)
+		} else {
+			_, err = i.eventStore.Create(dbmodels.InterviewCalendarEvent{
+				BaseSpaceModel: dbmodels.BaseSpaceModel{
+					SpaceID: spaceID,
+				},
+				InterviewID:  event.InterviewID,
+				UserID:       conn.UserID,
+				ConnectionID: conn.ID,
+				Href:         href,
+				ETag:         newETag,
+				StartAt:      event.StartAt,
+				EndAt:        event.EndAt,
+			})
+		}
+		if err != nil {
+			userLogger.WithError(err).Error("ошибка сохранения события интервью")
+		}
+	}
+}
+
+func (i impl) GetMovedInterviews(ctx context.Context) []MovedInterview {
+	list, err := i.eventStore.ListForSync(time.Now())
+	if err != nil {
+		log.WithError(err).Error("ошибка получения событий интервью для синхронизации")
+		return nil
+	}
+	result := []MovedInterview{}
+	connections := map[string]dbmodels.CalendarConnection{}
+	syncErrors := map[string]error{}
+	for _, rec := range list {
+		if helpers.IsContextDone(ctx) {
+			break
+		}
+		if rec.Connection == nil || rec.Connection.Type != dbmodels.CalendarTypeCalDAV {
+			continue
+		}
+		connections[rec.ConnectionID] = *rec.Connection
+		moved, err := i.checkEvent(ctx, rec)
+		if err != nil {
+			i.getLogger(rec.SpaceID, rec.UserID).
+				WithError(err).
+				WithField("interview_id", rec.InterviewID).
+				Warn("ошибка синхронизации события интервью")
+			syncErrors[rec.ConnectionID] = err
+			continue
+		}
+		if moved != nil {
+			result = append(result, *moved)
+		}
+	}
+	for id, conn := range connections {
+		i.setSyncState(conn, syncErrors[id])
+	}
+	return result
+}
+
+// checkEvent проверка изменения времени события интервью в календаре
+func (i impl) checkEvent(ctx context.Context, rec dbmodels.InterviewCalendarEvent) (*MovedInterview, error) {
+	client, err := i.newCalDAVClient(*rec.Connection)
+	if err != nil {
+		return nil, err
+	}
+	reqCtx, cancel := context.WithTimeout(ctx, i.getTimeout())
+	defer cancel()
+	data, etag, err := client.Get(reqCtx, rec.Href)
+	if err != nil {
+		if errors.Is(err, caldav.ErrNotFound) {
+			// событие удалено пользователем из календаря, интервью не отменяется
+			return nil, i.eventStore.Delete(rec.ID)
+		}
+		return nil, err
+	}
+	if etag != "" && etag == rec.ETag {
+		return nil, nil
+	}
+	events, err := caldav.ParseEvents(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, errors.New("событие интервью не найдено в данных календаря")
+	}
+	event := events[0]
+	updMap := map[string]interface{}{
+		"e_tag":    etag,
+		"start_at": event.StartAt,
+		"end_at":   event.EndAt,
+	}
+	err = i.eventStore.Update(rec.ID, updMap)
+	if err != nil {
+		return nil, err
+	}
+	if event.StartAt.Equal(rec.StartAt) && event.EndAt.Equal(rec.EndAt) {
+		return nil, nil
+	}
+	return &MovedInterview{
+		SpaceID:     rec.SpaceID,
+		InterviewID: rec.InterviewID,
+		UserID:      rec.UserID,
+		StartAt:     event.StartAt,
+		EndAt:       event.EndAt,
+	}, nil
+}
+
+func (i impl) getEvents(conn dbmodels.CalendarConnection, from, to time.Time) ([]caldav.Event, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), i.getTimeout())
+	defer cancel()
+	if conn.Type == dbmodels.CalendarTypeICS {
+		calendarURL, password, err := decryptCredentials(conn)
+		if err != nil {
+			return nil, err
+		}
+		return caldav.FetchICS(ctx, calendarURL, conn.Login, password, i.getTimeout())
+	}
+	client, err := i.newCalDAVClient(conn)
+	if err != nil {
+		return nil, err
+	}
+	return client.Query(ctx, from, to)
+}
+
+func (i impl) newCalDAVClient(conn dbmodels.CalendarConnection) (*caldav.Client, error) {
+	calendarURL, password, err := decryptCredentials(conn)
+	if err != nil {
+		return nil, err
+	}
+	return caldav.NewClient(calendarURL, conn.Login, password, i.getTimeout())
+}
+
+func (i impl) setSyncState(conn dbmodels.CalendarConnection, syncErr error) {
+	updMap := map[string]interface{}{
+		"last_sync_at": time.Now(),
+		"last_error":   "",
+	}
+	if syncErr != nil {
+		updMap["last_error"] = syncErr.Error()
+	}
+	err := i.store.Update(conn.ID, updMap)
+	if err != nil {
+		i.getLogger(conn.SpaceID, conn.UserID).WithError(err).Error("ошибка сохранения состояния синхронизации календаря")
+	}
+}
+
+func (i impl) deleteConnection(tx *gorm.DB, spaceID, userID string) error {
+	store := calendarstore.NewInstance(tx)
+	rec, err := store.GetByUser(spaceID, userID)
+	if err != nil || rec == nil {
+		return err
+	}
+	err = calendareventstore.NewInstance(tx).DeleteByConnection(spaceID, rec.ID)
+	if err != nil {
+		return err
+	}
+	return store.Delete(spaceID, rec.ID)
+}
+
+func (i impl) getTimeout() time.Duration {
+	return time.Duration(config.Conf.Calendar.TimeoutSec) * time.Second
+}
+
+func decryptCredentials(conn dbmodels.CalendarConnection) (calendarURL, password string, err error) {
+	secret := config.Conf.Calendar.SecretKey
+	calendarURL, err = authhelpers.DecryptString(secret, conn.URL)
+	if err != nil {
+		return "", "", errors.Wrap(err, "ошибка расшифровки адреса календаря")
+	}
+	password, err = authhelpers.DecryptString(secret, conn.Password)
+	if err != nil {
+		return "", "", errors.Wrap(err, "ошибка расшифровки пароля календаря")
+	}
+	return calendarURL, password, nil
+}
+
+func getConnectionMsg(err error) string {
+	switch {
+	case errors.Is(err, caldav.ErrUnauthorized):
+		return caldav.ErrUnauthorized.Error()
+	case errors.Is(err, caldav.ErrNotFound):
+		return "календарь не найден по указанному адресу"
+	case errors.Is(err, safehttp.ErrDeniedAddress):
+		return safehttp.DeniedAddrMsg
+	}
+	// текст ошибки не возвращается пользователю, чтобы не раскрывать ответы узлов сети
+	return "не удалось подключиться к календарю"
+}
@@ -0,0 +1,96 @@
+package calendarstore
+
+import (
+	dbmodels "hr-tools-backend/models/db"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+type Provider interface {
+	// Save создание или замена подключения календаря пользователя
+	Save(rec dbmodels.CalendarConnection) (id string, err error)
+	GetByUser(spaceID, userID string) (*dbmodels.CalendarConnection, error)
+	ListByUsers(spaceID string, userIDs []string) ([]dbmodels.CalendarConnection, error)
+	Update(id string, updMap map[string]interface{}) error
+	Delete(spaceID, id string) error
+}
+
+func NewInstance(DB *gorm.DB) Provider {
+	return &impl{
+		db: DB,
+	}
+}
+
+type impl struct {
+	db *gorm.DB
+}
+
+func (i impl) Save(rec dbmodels.CalendarConnection) (id string, err error) {
+	err = i.db.
+		Where("space_id = ?", rec.SpaceID).
+		Where("user_id = ?", rec.UserID).
+		Delete(&dbmodels.CalendarConnection{}).
+		Error
+	if err != nil {
+		return "", err
+	}
+	err = i.db.
+		Save(&rec).
+		Error
+	if err != nil {
+		return "", err
+	}
+	return rec.ID, nil
+}
+
+func (i impl) GetByUser(spaceID, userID string) (*dbmodels.CalendarConnection, error) {
+	rec := dbmodels.CalendarConnection{}
+	err := i.db.
+		Where("space_id = ?", spaceID).
+		Where("user_id = ?", userID).
+		First(&rec).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (i impl) ListByUsers(spaceID string, userIDs []string) ([]dbmodels.CalendarConnection, error) {
+	list := []dbmodels.CalendarConnection{}
+	if len(userIDs) == 0 {
+		return list, nil
+	}
+	err := i.db.
+		Where("space_id = ?", spaceID).
+		Where("user_id in (?)", userIDs).
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (i impl) Update(id string, updMap map[string]interface{}) error {
+	if len(updMap) == 0 {
+		return nil
+	}
+	return i.db.
+		Model(&dbmodels.CalendarConnection{}).
+		Where("id = ?", id).
+		Updates(updMap).
+		Error
+}
+
+func (i impl) Delete(spaceID, id string) error {
+	return i.db.
+		Where("space_id = ?", spaceID).
+		Where("id = ?", id).
+		Delete(&dbmodels.CalendarConnection{}).
+		Error
+}
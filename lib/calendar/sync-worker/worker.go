@@ -0,0 +1,47 @@
+package calendarsyncworker
+
+import (
+	"context"
+	"hr-tools-backend/config"
+	"hr-tools-backend/lib/calendar"
+	"hr-tools-backend/lib/interview"
+	baseworker "hr-tools-backend/lib/utils/base-worker"
+	"hr-tools-backend/lib/utils/helpers"
+	"time"
+)
+
+// Задача синхронизации интервью, перенесенных участниками в своих календарях
+func StartWorker(ctx context.Context) {
+	interval := time.Duration(config.Conf.Calendar.SyncIntervalMin) * time.Minute
+	i := &impl{
+		BaseImpl: *baseworker.NewInstance("CalendarSyncWorker", time.Minute, interval),
+	}
+	go i.Run(ctx, i.handle)
+}
+
+type impl struct {
+	baseworker.BaseImpl
+}
+
+func (i impl) handle(ctx context.Context) {
+	logger := i.GetLogger()
+	for _, moved := range calendar.Instance.GetMovedInterviews(ctx) {
+		if helpers.IsContextDone(ctx) {
+			break
+		}
+		hMsg, err := interview.Instance.Reschedule(moved.SpaceID, moved.InterviewID, moved.UserID, moved.StartAt, moved.EndAt)
+		if err != nil {
+			logger.
+				WithError(err).
+				WithField("interview_id", moved.InterviewID).
+				Error("ошибка переноса интервью по событию календаря")
+			continue
+		}
+		if hMsg != "" {
+			logger.
+				WithField("interview_id", moved.InterviewID).
+				WithField("reason", hMsg).
+				Warn("интервью не перенесено по событию календаря")
+		}
+	}
+}
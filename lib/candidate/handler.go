@@ -0,0 +1,384 @@
+package candidate
+
+import (
+	"hr-tools-backend/db"
+	applicanthistoryhandler "hr-tools-backend/lib/applicant-history"
+	applicantstore "hr-tools-backend/lib/applicant/store"
+	candidatestore "hr-tools-backend/lib/candidate/store"
+	datascope "hr-tools-backend/lib/data-scope"
+	filesdbstorage "hr-tools-backend/lib/file-storage/storage"
+	talentpoolstore "hr-tools-backend/lib/talent-pool/store"
+	initchecker "hr-tools-backend/lib/utils/init-checker"
+	applicantapimodels "hr-tools-backend/models/api/applicant"
+	candidateapimodels "hr-tools-backend/models/api/candidate"
+	filesapimodels "hr-tools-backend/models/api/files"
+	dbmodels "hr-tools-backend/models/db"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type Provider interface {
+	// List список кандидатов, у которых есть участие в подборе в области видимости пользователя
+	List(spaceID, userID string, filter candidateapimodels.CandidateFilter) (list []candidateapimodels.CandidateView, rowCount int64, err error)
+	// Get карточка кандидата, участие в подборе ограничивается областью видимости пользователя
+	Get(spaceID, id, userID string) (*candidateapimodels.CandidateViewExt, error)
+	// Update изменение контактов кандидата, изменения переносятся во все его вакансии
+	Update(spaceID, id, userID string, data candidateapimodels.CandidateData) (hMsg string, err error)
+	// History общая история действий по всем вакансиям кандидата
+	History(spaceID, id, userID string, filter applicantapimodels.ApplicantHistoryFilter) (list []applicantapimodels.ApplicantHistoryView, rowCount int64, err error)
+	// Files резюме и документы кандидата по всем вакансиям
+	Files(spaceID, id, userID string) ([]filesapimodels.FileView, error)
+	// LinkApplicant привязка кандидата на вакансии к кандидату (человеку): по дублю, email или телефону, иначе создается новый
+	LinkApplicant(rec dbmodels.Applicant) (candidateID string, err error)
+}
+
+var Instance Provider
+
+func NewHandler() {
+	instance := impl{
+		store:            candidatestore.NewInstance(db.DB),
+		applicantStore:   applicantstore.NewInstance(db.DB),
+		poolStore:        talentpoolstore.NewInstance(db.DB),
+		filesStore:       filesdbstorage.NewInstance(db.DB),
+		applicantHistory: applicanthistoryhandler.Instance,
+	}
+	initchecker.CheckInit(
+		"store", instance.store,
+		"applicantStore", instance.applicantStore,
+		"poolStore", instance.poolStore,
+		"filesStore", instance.filesStore,
+		"applicantHistory", instance.applicantHistory,
+	)
+	Instance = instance
+}
+
+type impl struct {
+	store            candidatestore.Provider
+	applicantStore   applicantstore.Provider
+	poolStore        talentpoolstore.Provider
+	filesStore       filesdbstorage.Provider
+	applicantHistory applicanthistoryhandler.Provider
+}
+
+func (i impl) getLogger(spaceID, candidateID string) *log.Entry {
+	logger := log.WithField("space_id", spaceID)
+	if candidateID != "" {
+		logger = logger.WithField("candidate_id", candidateID)
+	}
+	return logger
+}
+
+func (i impl) List(spaceID, userID string, filter candidateapimodels.CandidateFilter) (list []candidateapimodels.CandidateView, rowCount int64, err error) {
+	scope, err := datascope.Instance.GetScope(spaceID, userID)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "ошибка получения области видимости")
+	}
+	rowCount, err = i.store.ListCount(spaceID, scope, filter)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "ошибка получения количества кандидатов")
+	}
+	page, limit := filter.GetPage()
+	offset := (page - 1) * limit
+	if int64(offset) > rowCount {
+		return []candidateapimodels.CandidateView{}, rowCount, nil
+	}
+	recList, err := i.store.List(spaceID, scope, filter)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "ошибка получения списка кандидатов")
+	}
+	list = make([]candidateapimodels.CandidateView, 0, len(recList))
+	for _, rec := range recList {
+		list = append(list, candidateapimodels.CandidateConvert(rec))
+	}
+	return list, rowCount, nil
+}
+
+func (i impl) Get(spaceID, id, userID string) (*candidateapimodels.CandidateViewExt, error) {
+	rec, err := i.getVisible(spaceID, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if rec == nil {
+		return nil, nil
+	}
+	pools, err := i.poolStore.ListByCandidate(spaceID, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения кадровых резервов кандидата")
+	}
+	result := candidateapimodels.CandidateViewExt{
+		CandidateView: candidateapimodels.CandidateConvert(*rec),
+		Applications:  make([]candidateapimodels.ApplicationView, 0, len(rec.Applicants)),
+		TalentPools:   make([]candidateapimodels.TalentPoolShort, 0, len(pools)),
+	}
+	for _, applicant := range rec.Applicants {
+		result.Applications = append(result.Applications, candidateapimodels.ApplicationConvert(applicant))
+	}
+	for _, pool := range pools {
+		result.TalentPools = append(result.TalentPools, candidateapimodels.TalentPoolShort{ID: pool.ID, Name: pool.Name})
+	}
+	return &result, nil
+}
+
+func (i impl) Update(spaceID, id, userID string, data candidateapimodels.CandidateData) (hMsg string, err error) {
+	logger := i.getLogger(spaceID, id).WithField("user_id", userID)
+	rec, err := i.store.GetByID(spaceID, id)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения кандидата")
+	}
+	if rec == nil {
+		return "кандидат не найден", nil
+	}
+	birthDate, err := data.GetBirthDate()
+	if err != nil {
+		return "некоректный формат даты рождения", nil
+	}
+	contacts := map[string]interface{}{
+		"FirstName":   data.FirstName,
+		"LastName":    data.LastName,
+		"MiddleName":  data.MiddleName,
+		"Phone":       data.Phone,
+		"Email":       data.Email,
+		"BirthDate":   birthDate,
+		"Citizenship": data.Citizenship,
+		"Gender":      data.Gender,
+		"Relocation":  data.Relocation,
+		"Address":     data.Address,
+	}
+	updMap := map[string]interface{}{
+		"Comment":     data.Comment,
+		"PhoneDigits": NormalizePhone(data.Phone),
+	}
+	for key, value := range contacts {
+		updMap[key] = value
+	}
+	updMap["Email"] = NormalizeEmail(data.Email)
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		err = candidatestore.NewInstance(tx).Update(spaceID, id, updMap)
+		if err != nil {
+			return errors.Wrap(err, "ошибка изменения кандидата")
+		}
+		applicantStore := applicantstore.NewInstance(tx)
+		for _, applicant := range rec.Applicants {
+			err = applicantStore.Update(applicant.ID, contacts)
+			if err != nil {
+				return errors.Wrap(err, "ошибка изменения контактов кандидата на вакансии")
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	for _, applicant := range rec.Applicants {
+		changes := applicanthistoryhandler.GetUpdateChanges("Изменен профиль кандидата", applicant, contacts)
+		if len(changes.Data) == 0 {
+			continue
+		}
+		i.applicantHistory.Save(spaceID, applicant.ID, applicant.VacancyID, userID, dbmodels.HistoryTypeUpdate, changes)
+	}
+	logger.Info("изменены данные кандидата")
+	return "", nil
+}
+
+func (i impl) History(spaceID, id, userID string, filter applicantapimodels.ApplicantHistoryFilter) (list []applicantapimodels.ApplicantHistoryView, rowCount int64, err error) {
+	scope, err := datascope.Instance.GetScope(spaceID, userID)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "ошибка получения области видимости")
+	}
+	return i.applicantHistory.ListByCandidate(spaceID, id, scope, filter)
+}
+
+func (i impl) Files(spaceID, id, userID string) ([]filesapimodels.FileView, error) {
+	rec, err := i.getVisible(spaceID, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	result := []filesapimodels.FileView{}
+	if rec == nil {
+		return result, nil
+	}
+	for _, applicant := range rec.Applicants {
+		for _, fileType := range []dbmodels.FileType{dbmodels.ApplicantResume, dbmodels.ApplicantDoc} {
+			list, err := i.filesStore.GetFileListByType(applicant.ID, fileType)
+			if err != nil {
+				return nil, errors.Wrap(err, "ошибка получения списка файлов кандидата")
+			}
+			for _, file := range list {
+				result = append(result, file.ToModel())
+			}
+		}
+	}
+	return result, nil
+}
+
+// getVisible кандидат с участием в подборе, входящим в область видимости пользователя
+func (i impl) getVisible(spaceID, id, userID string) (*dbmodels.Candidate, error) {
+	scope, err := datascope.Instance.GetScope(spaceID, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения области видимости")
+	}
+	rec, err := i.store.GetByID(spaceID, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения кандидата")
+	}
+	if rec == nil || len(rec.Applicants) == 0 {
+		return rec, nil
+	}
+	ids := make([]string, 0, len(rec.Applicants))
+	for _, applicant := range rec.Applicants {
+		ids = append(ids, applicant.ID)
+	}
+	visibleIDs, err := i.applicantStore.ListVisibleIDs(spaceID, scope, ids)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка проверки области видимости кандидата")
+	}
+	applicants := make([]dbmodels.Applicant, 0, len(visibleIDs))
+	for _, applicant := range rec.Applicants {
+		if slices.Contains(visibleIDs, applicant.ID) {
+			applicants = append(applicants, applicant)
+		}
+	}
+	rec.Applicants = applicants
+	return rec, nil
+}
+
+func (i impl) LinkApplicant(rec dbmodels.Applicant) (candidateID string, err error) {
+	logger := i.getLogger(rec.SpaceID, "").WithField("applicant_id", rec.ID)
+	candidate, err := i.findCandidate(rec)
+	if err != nil {
+		return "", err
+	}
+	if candidate != nil {
+		candidateID = candidate.ID
+		// новые контакты из отклика дополняют данные кандидата
+		updMap := fillEmptyContacts(*candidate, rec)
+		err = i.store.Update(rec.SpaceID, candidateID, updMap)
+		if err != nil {
+			return "", errors.Wrap(err, "ошибка изменения контактов кандидата")
+		}
+	} else {
+		candidateID, err = i.store.Create(newCandidate(rec))
+		if err != nil {
+			return "", errors.Wrap(err, "ошибка создания кандидата")
+		}
+	}
+	err = i.applicantStore.Update(rec.ID, map[string]interface{}{"candidate_id": candidateID})
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка привязки кандидата")
+	}
+	logger.WithField("candidate_id", candidateID).Info("кандидат на вакансии привязан к кандидату")
+	return candidateID, nil
+}
+
+func (i impl) findCandidate(rec dbmodels.Applicant) (*dbmodels.Candidate, error) {
+	if rec.DuplicateID != nil {
+		mainRec, err := i.applicantStore.GetByID(rec.SpaceID, *rec.DuplicateID)
+		if err != nil {
+			return nil, errors.Wrap(err, "ошибка получения основного кандидата")
+		}
+		if mainRec != nil && mainRec.CandidateID != nil {
+			candidate, err := i.store.GetByID(rec.SpaceID, *mainRec.CandidateID)
+			if err != nil {
+				return nil, errors.Wrap(err, "ошибка получения кандидата")
+			}
+			if candidate != nil {
+				return candidate, nil
+			}
+		}
+	}
+	if email := NormalizeEmail(rec.Email); email != "" {
+		candidate, err := i.store.FindByEmail(rec.SpaceID, email)
+		if err != nil {
+			return nil, errors.Wrap(err, "ошибка поиска кандидата по email")
+		}
+		if candidate != nil && !isMarkedDifferent(*candidate, rec) {
+			return candidate, nil
+		}
+	}
+	if phone := NormalizePhone(rec.Phone); phone != "" {
+		candidate, err := i.store.FindByPhone(rec.SpaceID, phone)
+		if err != nil {
+			return nil, errors.Wrap(err, "ошибка поиска кандидата по телефону")
+		}
+		if candidate != nil && !isMarkedDifferent(*candidate, rec) {
+			return candidate, nil
+		}
+	}
+	return nil, nil
+}
+
+// isMarkedDifferent кандидат на вакансии отмечен как другой человек для одной из вакансий кандидата
+func isMarkedDifferent(candidate dbmodels.Candidate, rec dbmodels.Applicant) bool {
+	for _, applicant := range candidate.Applicants {
+		if applicant.IsMarkAsNotDuplicate(rec) {
+			return true
+		}
+	}
+	return false
+}
+
+func newCandidate(rec dbmodels.Applicant) dbmodels.Candidate {
+	return dbmodels.Candidate{
+		BaseSpaceModel: dbmodels.BaseSpaceModel{
+			SpaceID: rec.SpaceID,
+		},
+		FirstName:   rec.FirstName,
+		LastName:    rec.LastName,
+		MiddleName:  rec.MiddleName,
+		Phone:       rec.Phone,
+		PhoneDigits: NormalizePhone(rec.Phone),
+		Email:       NormalizeEmail(rec.Email),
+		BirthDate:   rec.BirthDate,
+		Citizenship: rec.Citizenship,
+		Gender:      rec.Gender,
+		Relocation:  rec.Relocation,
+		Address:     rec.Address,
+	}
+}
+
+func fillEmptyContacts(candidate dbmodels.Candidate, rec dbmodels.Applicant) map[string]interface{} {
+	updMap := map[string]interface{}{}
+	if candidate.Phone == "" && rec.Phone != "" {
+		updMap["phone"] = rec.Phone
+		updMap["phone_digits"] = NormalizePhone(rec.Phone)
+	}
+	if candidate.Email == "" && rec.Email != "" {
+		updMap["email"] = NormalizeEmail(rec.Email)
+	}
+	if candidate.MiddleName == "" && rec.MiddleName != "" {
+		updMap["middle_name"] = rec.MiddleName
+	}
+	if candidate.BirthDate.IsZero() && !rec.BirthDate.IsZero() {
+		updMap["birth_date"] = rec.BirthDate
+	}
+	if candidate.Citizenship == "" && rec.Citizenship != "" {
+		updMap["citizenship"] = rec.Citizenship
+	}
+	if candidate.Address == "" && rec.Address != "" {
+		updMap["address"] = rec.Address
+	}
+	return updMap
+}
+
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// NormalizePhone цифры телефона без кода страны, для сравнения номеров в разных форматах (+7, 8, пробелы, скобки)
+func NormalizePhone(phone string) string {
+	digits := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, phone)
+	if len(digits) < 10 {
+		return ""
+	}
+	return digits[len(digits)-10:]
+}
@@ -0,0 +1,166 @@
+package candidate
+
+import (
+	applicanthistoryhandler "hr-tools-backend/lib/applicant-history"
+	applicantstore "hr-tools-backend/lib/applicant/store"
+	candidatestore "hr-tools-backend/lib/candidate/store"
+	datascopefake "hr-tools-backend/lib/data-scope/fake"
+	filesdbstorage "hr-tools-backend/lib/file-storage/storage"
+	talentpoolstore "hr-tools-backend/lib/talent-pool/store"
+	applicantapimodels "hr-tools-backend/models/api/applicant"
+	candidateapimodels "hr-tools-backend/models/api/candidate"
+	dbmodels "hr-tools-backend/models/db"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	require.Equal(t, "9161234567", NormalizePhone("+7 (916) 123-45-67"))
+	require.Equal(t, "9161234567", NormalizePhone("89161234567"))
+	require.Equal(t, "9161234567", NormalizePhone("916 123 45 67"))
+	require.Equal(t, "", NormalizePhone("123-45-67"))
+	require.Equal(t, "", NormalizePhone(""))
+}
+
+func TestNormalizeEmail(t *testing.T) {
+	require.Equal(t, "ivanov@mail.ru", NormalizeEmail(" Ivanov@Mail.RU "))
+	require.Equal(t, "", NormalizeEmail(""))
+}
+
+func TestIsMarkedDifferent(t *testing.T) {
+	rec := dbmodels.Applicant{}
+	rec.ID = "new"
+	candidate := dbmodels.Candidate{
+		Applicants: []dbmodels.Applicant{
+			{NotDuplicates: []string{"other"}},
+		},
+	}
+	require.False(t, isMarkedDifferent(candidate, rec))
+
+	candidate.Applicants = append(candidate.Applicants, dbmodels.Applicant{NotDuplicates: []string{"new"}})
+	require.True(t, isMarkedDifferent(candidate, rec))
+}
+
+func TestFillEmptyContacts(t *testing.T) {
+	candidate := dbmodels.Candidate{
+		Phone: "+7 916 123-45-67",
+	}
+	rec := dbmodels.Applicant{
+		Phone:      "89160000000",
+		Email:      "Ivanov@Mail.ru",
+		MiddleName: "Петрович",
+	}
+	updMap := fillEmptyContacts(candidate, rec)
+	require.Equal(t, map[string]interface{}{
+		"email":       "ivanov@mail.ru",
+		"middle_name": "Петрович",
+	}, updMap)
+
+	candidate.Email = "ivanov@mail.ru"
+	candidate.MiddleName = "Петрович"
+	require.Empty(t, fillEmptyContacts(candidate, rec))
+}
+
+type fakeCandidateStore struct {
+	candidatestore.Provider
+	rec   dbmodels.Candidate
+	scope *dbmodels.DataScope
+}
+
+func (s *fakeCandidateStore) GetByID(spaceID, id string) (*dbmodels.Candidate, error) {
+	rec := s.rec
+	return &rec, nil
+}
+
+func (s *fakeCandidateStore) ListCount(spaceID string, scope *dbmodels.DataScope, filter candidateapimodels.CandidateFilter) (int64, error) {
+	s.scope = scope
+	return 0, nil
+}
+
+func (s *fakeCandidateStore) List(spaceID string, scope *dbmodels.DataScope, filter candidateapimodels.CandidateFilter) ([]dbmodels.Candidate, error) {
+	return []dbmodels.Candidate{}, nil
+}
+
+type fakeApplicantStore struct {
+	applicantstore.Provider
+	visible []string
+}
+
+func (s fakeApplicantStore) ListVisibleIDs(spaceID string, scope *dbmodels.DataScope, ids []string) ([]string, error) {
+	result := []string{}
+	for _, id := range ids {
+		if slices.Contains(s.visible, id) {
+			result = append(result, id)
+		}
+	}
+	return result, nil
+}
+
+type fakePoolStore struct {
+	talentpoolstore.Provider
+}
+
+func (s fakePoolStore) ListByCandidate(spaceID, candidateID string) ([]dbmodels.TalentPool, error) {
+	return []dbmodels.TalentPool{}, nil
+}
+
+type fakeFilesStore struct {
+	filesdbstorage.Provider
+}
+
+func (s fakeFilesStore) GetFileListByType(applicantID string, fileType dbmodels.FileType) ([]dbmodels.FileStorage, error) {
+	rec := dbmodels.FileStorage{ApplicantID: applicantID, Type: fileType}
+	rec.ID = applicantID + "-" + string(fileType)
+	return []dbmodels.FileStorage{rec}, nil
+}
+
+type fakeHistory struct {
+	applicanthistoryhandler.Provider
+	scope *dbmodels.DataScope
+}
+
+func (h *fakeHistory) ListByCandidate(spaceID, candidateID string, scope *dbmodels.DataScope, filter applicantapimodels.ApplicantHistoryFilter) ([]applicantapimodels.ApplicantHistoryView, int64, error) {
+	h.scope = scope
+	return []applicantapimodels.ApplicantHistoryView{}, 0, nil
+}
+
+func TestCandidateScope(t *testing.T) {
+	datascopefake.Set(t, datascopefake.DataScope{})
+	own, other := dbmodels.Applicant{}, dbmodels.Applicant{}
+	own.ID = "own"
+	other.ID = "other"
+	store := &fakeCandidateStore{rec: dbmodels.Candidate{Applicants: []dbmodels.Applicant{own, other}}}
+	history := &fakeHistory{}
+	i := impl{
+		store:            store,
+		applicantStore:   fakeApplicantStore{visible: []string{"own"}},
+		poolStore:        fakePoolStore{},
+		filesStore:       fakeFilesStore{},
+		applicantHistory: history,
+	}
+
+	// участие в подборе по вакансии вне области видимости не раскрывается
+	view, err := i.Get("space", "candidate", "user")
+	require.NoError(t, err)
+	require.Len(t, view.Applications, 1)
+	require.Equal(t, "own", view.Applications[0].ApplicantID)
+	require.Equal(t, 1, view.ApplicationCount)
+
+	files, err := i.Files("space", "candidate", "user")
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	for _, file := range files {
+		require.Equal(t, "own", file.ApplicantID)
+	}
+
+	_, _, err = i.History("space", "candidate", "user", applicantapimodels.ApplicantHistoryFilter{})
+	require.NoError(t, err)
+	require.Equal(t, "user", history.scope.UserID)
+
+	list, _, err := i.List("space", "user", candidateapimodels.CandidateFilter{})
+	require.NoError(t, err)
+	require.Empty(t, list)
+	require.Equal(t, "user", store.scope.UserID)
+}
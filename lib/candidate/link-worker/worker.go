@@ -0,0 +1,49 @@
+package candidatelinkworker
+
+import (
+	"context"
+	"hr-tools-backend/db"
+	applicantstore "hr-tools-backend/lib/applicant/store"
+	"hr-tools-backend/lib/candidate"
+	baseworker "hr-tools-backend/lib/utils/base-worker"
+	"hr-tools-backend/lib/utils/helpers"
+	"time"
+)
+
+const batchSize = 500
+
+// Задача привязки кандидатов на вакансии к карточкам кандидатов (в т.ч. заполнение для ранее созданных кандидатов)
+func StartWorker(ctx context.Context) {
+	i := &impl{
+		BaseImpl:       *baseworker.NewInstance("CandidateLinkWorker", 40*time.Second, 1*time.Minute),
+		applicantStore: applicantstore.NewInstance(db.DB),
+	}
+	go i.Run(ctx, i.handle)
+}
+
+type impl struct {
+	baseworker.BaseImpl
+	applicantStore applicantstore.Provider
+}
+
+func (i impl) handle(ctx context.Context) {
+	logger := i.GetLogger()
+	list, err := i.applicantStore.ListForCandidateLink(batchSize)
+	if err != nil {
+		logger.WithError(err).Error("ошибка получения списка кандидатов без карточки кандидата")
+		return
+	}
+	for _, rec := range list {
+		if helpers.IsContextDone(ctx) {
+			break
+		}
+		_, err = candidate.Instance.LinkApplicant(rec)
+		if err != nil {
+			logger.
+				WithField("space_id", rec.SpaceID).
+				WithField("applicant_id", rec.ID).
+				WithError(err).
+				Error("ошибка привязки кандидата к карточке кандидата")
+		}
+	}
+}
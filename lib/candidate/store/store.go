@@ -0,0 +1,228 @@
+package candidatestore
+
+import (
+	candidateapimodels "hr-tools-backend/models/api/candidate"
+	dbmodels "hr-tools-backend/models/db"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Provider interface {
+	Create(rec dbmodels.Candidate) (id string, err error)
+	Update(spaceID, id string, updMap map[string]interface{}) error
+	Delete(spaceID, id string) error
+	GetByID(spaceID, id string) (*dbmodels.Candidate, error)
+	FindByEmail(spaceID, email string) (*dbmodels.Candidate, error)
+	FindByPhone(spaceID, phoneDigits string) (*dbmodels.Candidate, error)
+	// IsVisible кандидат виден в области видимости пользователя
+	IsVisible(spaceID, id string, scope *dbmodels.DataScope) (bool, error)
+	ListCount(spaceID string, scope *dbmodels.DataScope, filter candidateapimodels.CandidateFilter) (int64, error)
+	// List список кандидатов, участие в подборе ограничивается областью видимости пользователя
+	List(spaceID string, scope *dbmodels.DataScope, filter candidateapimodels.CandidateFilter) ([]dbmodels.Candidate, error)
+	// MoveApplicants перенос участия в подборе и кадровых резервов кандидата fromID к кандидату toID
+	MoveApplicants(spaceID, fromID, toID string) error
+}
+
+func NewInstance(DB *gorm.DB) Provider {
+	return &impl{
+		db: DB,
+	}
+}
+
+type impl struct {
+	db *gorm.DB
+}
+
+func (i impl) Create(rec dbmodels.Candidate) (id string, err error) {
+	err = i.db.
+		Omit(clause.Associations).
+		Save(&rec).
+		Error
+	if err != nil {
+		return "", err
+	}
+	return rec.ID, nil
+}
+
+func (i impl) Update(spaceID, id string, updMap map[string]interface{}) error {
+	if len(updMap) == 0 {
+		return nil
+	}
+	err := i.db.
+		Model(&dbmodels.Candidate{}).
+		Where("space_id = ?", spaceID).
+		Where("id = ?", id).
+		Updates(updMap).
+		Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (i impl) Delete(spaceID, id string) error {
+	err := i.db.
+		Where("space_id = ?", spaceID).
+		Where("id = ?", id).
+		Delete(&dbmodels.Candidate{}).
+		Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (i impl) GetByID(spaceID, id string) (*dbmodels.Candidate, error) {
+	rec := dbmodels.Candidate{}
+	err := i.db.
+		Where("space_id = ?", spaceID).
+		Where("id = ?", id).
+		Preload("Applicants", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at desc")
+		}).
+		Preload("Applicants.Vacancy").
+		Preload("Applicants.SelectionStage").
+		First(&rec).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (i impl) FindByEmail(spaceID, email string) (*dbmodels.Candidate, error) {
+	return i.findBy(spaceID, "email = ?", email)
+}
+
+func (i impl) FindByPhone(spaceID, phoneDigits string) (*dbmodels.Candidate, error) {
+	return i.findBy(spaceID, "phone_digits = ?", phoneDigits)
+}
+
+func (i impl) findBy(spaceID, query string, value string) (*dbmodels.Candidate, error) {
+	rec := dbmodels.Candidate{}
+	err := i.db.
+		Where("space_id = ?", spaceID).
+		Where(query, value).
+		Preload("Applicants").
+		Order("created_at").
+		First(&rec).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (i impl) IsVisible(spaceID, id string, scope *dbmodels.DataScope) (bool, error) {
+	var exists bool
+	tx := i.db.
+		Model(&dbmodels.Candidate{}).
+		Select("count(*) > 0").
+		Where("candidates.id = ?", id).
+		Where("candidates.space_id = ?", spaceID)
+	i.addScope(tx, scope)
+	err := tx.Find(&exists).Error
+	return exists, err
+}
+
+func (i impl) ListCount(spaceID string, scope *dbmodels.DataScope, filter candidateapimodels.CandidateFilter) (int64, error) {
+	var rowCount int64
+	tx := i.db.
+		Model(dbmodels.Candidate{}).
+		Where("space_id = ?", spaceID)
+	i.addScope(tx, scope)
+	i.addFilter(tx, filter)
+	err := tx.Count(&rowCount).Error
+	if err != nil {
+		return 0, err
+	}
+	return rowCount, nil
+}
+
+func (i impl) List(spaceID string, scope *dbmodels.DataScope, filter candidateapimodels.CandidateFilter) ([]dbmodels.Candidate, error) {
+	list := []dbmodels.Candidate{}
+	tx := i.db.
+		Model(dbmodels.Candidate{}).
+		Where("space_id = ?", spaceID)
+	i.addScope(tx, scope)
+	i.addFilter(tx, filter)
+	page, limit := filter.GetPage()
+	offset := (page - 1) * limit
+	err := tx.
+		Limit(limit).
+		Offset(offset).
+		Order("updated_at desc").
+		Preload("Applicants", func(db *gorm.DB) *gorm.DB {
+			if query, args := scope.ApplicantCondition("applicants"); query != "" {
+				return db.Where(query, args)
+			}
+			return db
+		}).
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (i impl) MoveApplicants(spaceID, fromID, toID string) error {
+	err := i.db.
+		Model(&dbmodels.Applicant{}).
+		Where("space_id = ?", spaceID).
+		Where("candidate_id = ?", fromID).
+		Update("candidate_id", toID).
+		Error
+	if err != nil {
+		return errors.Wrap(err, "ошибка переноса участия в подборе")
+	}
+	// кадровые резервы, в которых уже есть кандидат toID, не переносятся
+	err = i.db.
+		Where("space_id = ?", spaceID).
+		Where("candidate_id = ?", fromID).
+		Where("talent_pool_id in (?)", i.db.Model(&dbmodels.TalentPoolCandidate{}).
+			Select("talent_pool_id").
+			Where("candidate_id = ?", toID)).
+		Delete(&dbmodels.TalentPoolCandidate{}).
+		Error
+	if err != nil {
+		return errors.Wrap(err, "ошибка переноса кадровых резервов")
+	}
+	err = i.db.
+		Model(&dbmodels.TalentPoolCandidate{}).
+		Where("space_id = ?", spaceID).
+		Where("candidate_id = ?", fromID).
+		Update("candidate_id", toID).
+		Error
+	if err != nil {
+		return errors.Wrap(err, "ошибка переноса кадровых резервов")
+	}
+	return nil
+}
+
+func (i impl) addScope(tx *gorm.DB, scope *dbmodels.DataScope) {
+	if query, args := scope.CandidateCondition("candidates"); query != "" {
+		tx.Where(query, args)
+	}
+}
+
+func (i impl) addFilter(tx *gorm.DB, filter candidateapimodels.CandidateFilter) {
+	if filter.Search != "" {
+		searchValue := "%" + strings.ToLower(filter.Search) + "%"
+		tx.Where("LOWER(CONCAT(last_name,' ', first_name, ' ' , middle_name)) like ? or phone like ? or email like ?", searchValue, searchValue, searchValue)
+	}
+	if filter.TalentPoolID != "" {
+		tx.Where("id in (?)", i.db.Model(&dbmodels.TalentPoolCandidate{}).
+			Select("candidate_id").
+			Where("talent_pool_id = ?", filter.TalentPoolID))
+	}
+}
@@ -0,0 +1,301 @@
+package careersite
+
+import (
+	"context"
+	"encoding/xml"
+	"hr-tools-backend/config"
+	"hr-tools-backend/db"
+	"hr-tools-backend/lib/applicant"
+	applicanthistoryhandler "hr-tools-backend/lib/applicant-history"
+	applicantduplicate "hr-tools-backend/lib/applicant/duplicate"
+	applicantstore "hr-tools-backend/lib/applicant/store"
+	filestorage "hr-tools-backend/lib/file-storage"
+	personaldata "hr-tools-backend/lib/personal-data"
+	pushhandler "hr-tools-backend/lib/space/push/handler"
+	spacestore "hr-tools-backend/lib/space/store"
+	initchecker "hr-tools-backend/lib/utils/init-checker"
+	vacancystore "hr-tools-backend/lib/vacancy/store"
+	"hr-tools-backend/models"
+	applicantapimodels "hr-tools-backend/models/api/applicant"
+	careersiteapimodels "hr-tools-backend/models/api/career-site"
+	personaldataapimodels "hr-tools-backend/models/api/personal-data"
+	dbmodels "hr-tools-backend/models/db"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+type Provider interface {
+	ListVacancies(spaceID string) (list []careersiteapimodels.VacancyView, hMsg string, err error)
+	GetVacancy(spaceID, vacancyID string) (view *careersiteapimodels.VacancyView, hMsg string, err error)
+	GetFeed(spaceID string) (body []byte, hMsg string, err error)
+	GetRss(spaceID string) (body []byte, hMsg string, err error)
+	Apply(ctx context.Context, spaceID, vacancyID string, data careersiteapimodels.ApplyRequest, resume *models.File, consent personaldataapimodels.ConsentMeta) (applicantID, hMsg string, err error)
+}
+
+var Instance Provider
+
+func NewHandler() {
+	instance := impl{
+		spaceStore:       spacestore.NewInstance(db.DB),
+		vacancyStore:     vacancystore.NewInstance(db.DB),
+		applicantStore:   applicantstore.NewInstance(db.DB),
+		applicant:        applicant.Instance,
+		applicantHistory: applicanthistoryhandler.Instance,
+		filesStorage:     filestorage.Instance,
+		personalData:     personaldata.Instance,
+	}
+	initchecker.CheckInit(
+		"spaceStore", instance.spaceStore,
+		"vacancyStore", instance.vacancyStore,
+		"applicantStore", instance.applicantStore,
+		"applicant", instance.applicant,
+		"applicantHistory", instance.applicantHistory,
+		"filesStorage", instance.filesStorage,
+		"personalData", instance.personalData,
+	)
+	Instance = instance
+}
+
+type impl struct {
+	spaceStore       spacestore.Provider
+	vacancyStore     vacancystore.Provider
+	applicantStore   applicantstore.Provider
+	applicant        applicant.Provider
+	applicantHistory applicanthistoryhandler.Provider
+	filesStorage     filestorage.Provider
+	personalData     personaldata.Provider
+}
+
+const (
+	SpaceNotFoundMsg   = "карьерный сайт не найден"
+	VacancyNotFoundMsg = "вакансия не найдена"
+	AlreadyAppliedMsg  = "вы уже откликнулись на эту вакансию"
+)
+
+func (i impl) getLogger(spaceID, vacancyID string) *log.Entry {
+	logger := log.
+		WithField("integration", "CareerSite").
+		WithField("space_id", spaceID)
+	if vacancyID != "" {
+		logger = logger.WithField("vacancy_id", vacancyID)
+	}
+	return logger
+}
+
+func (i impl) ListVacancies(spaceID string) (list []careersiteapimodels.VacancyView, hMsg string, err error) {
+	recList, hMsg, err := i.listVacancies(spaceID)
+	if err != nil || hMsg != "" {
+		return nil, hMsg, err
+	}
+	list = make([]careersiteapimodels.VacancyView, 0, len(recList))
+	for _, rec := range recList {
+		list = append(list, careersiteapimodels.VacancyConvert(rec, getVacancyUrl(rec)))
+	}
+	return list, "", nil
+}
+
+func (i impl) GetVacancy(spaceID, vacancyID string) (view *careersiteapimodels.VacancyView, hMsg string, err error) {
+	rec, hMsg, err := i.getOpenedVacancy(spaceID, vacancyID)
+	if err != nil || hMsg != "" {
+		return nil, hMsg, err
+	}
+	result := careersiteapimodels.VacancyConvert(*rec, getVacancyUrl(*rec))
+	return &result, "", nil
+}
+
+func (i impl) GetFeed(spaceID string) (body []byte, hMsg string, err error) {
+	recList, hMsg, err := i.listVacancies(spaceID)
+	if err != nil || hMsg != "" {
+		return nil, hMsg, err
+	}
+	feed := careersiteapimodels.Feed{
+		CreationTime: time.Now().Format(careersiteapimodels.FeedTimeLayout),
+		Host:         config.Conf.CareerSite.FeedHost,
+		Vacancies:    make([]careersiteapimodels.FeedVacancy, 0, len(recList)),
+	}
+	for _, rec := range recList {
+		feed.Vacancies = append(feed.Vacancies, careersiteapimodels.FeedVacancyConvert(rec, getVacancyUrl(rec)))
+	}
+	body, err = marshalXml(feed)
+	return body, "", err
+}
+
+func (i impl) GetRss(spaceID string) (body []byte, hMsg string, err error) {
+	space, hMsg, err := i.getSpace(spaceID)
+	if err != nil || hMsg != "" {
+		return nil, hMsg, err
+	}
+	recList, err := i.vacancyStore.ListOpened(spaceID)
+	if err != nil {
+		return nil, "", err
+	}
+	rss := careersiteapimodels.Rss{
+		Version: "2.0",
+		Channel: careersiteapimodels.RssChannel{
+			Title:       space.OrganizationName,
+			Link:        config.Conf.UIParams.CareerSitePath + spaceID,
+			Description: space.Description,
+			Items:       make([]careersiteapimodels.RssItem, 0, len(recList)),
+		},
+	}
+	for _, rec := range recList {
+		rss.Channel.Items = append(rss.Channel.Items, careersiteapimodels.RssItemConvert(rec, getVacancyUrl(rec)))
+	}
+	body, err = marshalXml(rss)
+	return body, "", err
+}
+
+func (i impl) Apply(ctx context.Context, spaceID, vacancyID string, data careersiteapimodels.ApplyRequest, resume *models.File, consent personaldataapimodels.ConsentMeta) (applicantID, hMsg string, err error) {
+	logger := i.getLogger(spaceID, vacancyID)
+	vacancy, hMsg, err := i.getOpenedVacancy(spaceID, vacancyID)
+	if err != nil || hMsg != "" {
+		return "", hMsg, err
+	}
+	isDuplicate, err := i.isDuplicate(spaceID, vacancyID, data)
+	if err != nil {
+		return "", "", err
+	}
+	if isDuplicate {
+		return "", AlreadyAppliedMsg, nil
+	}
+
+	applicantData := applicantapimodels.ApplicantData{
+		VacancyID:  vacancyID,
+		Source:     models.ApplicantSite,
+		FirstName:  strings.TrimSpace(data.FirstName),
+		LastName:   strings.TrimSpace(data.LastName),
+		MiddleName: strings.TrimSpace(data.MiddleName),
+		Phone:      strings.TrimSpace(data.Phone),
+		Email:      strings.TrimSpace(data.Email),
+		Salary:     data.Salary,
+		Params: dbmodels.ApplicantParams{
+			Employments:        []models.Employment{},
+			Schedules:          []models.Schedule{},
+			Languages:          []dbmodels.Language{},
+			DriverLicenseTypes: []models.DriverLicenseType{},
+		},
+	}
+	applicantID, hMsg, err = i.applicant.CreateApplicant(spaceID, "", applicantData)
+	if err != nil {
+		return "", "", errors.Wrap(err, "ошибка создания кандидата по отклику с карьерного сайта")
+	}
+	if hMsg != "" {
+		return "", hMsg, nil
+	}
+	logger = logger.WithField("applicant_id", applicantID)
+
+	err = i.personalData.SaveConsent(spaceID, applicantID, consent)
+	if err != nil {
+		logger.WithError(err).Error("ошибка сохранения согласия на обработку ПД по отклику с карьерного сайта")
+	}
+
+	updMap := map[string]interface{}{
+		"negotiation_date": time.Now(),
+	}
+	err = i.applicantStore.Update(applicantID, updMap)
+	if err != nil {
+		logger.WithError(err).Error("ошибка сохранения даты отклика")
+	}
+
+	resumeName := ""
+	if resume != nil {
+		err = i.filesStorage.Upload(ctx, spaceID, applicantID, resume.Body, resume.FileName, dbmodels.ApplicantResume, resume.ContentType)
+		if err != nil {
+			logger.WithError(err).Error("ошибка сохранения резюме по отклику с карьерного сайта")
+		} else {
+			resumeName = resume.FileName
+		}
+	}
+	changes := applicanthistoryhandler.GetCareerSiteNegotiation(strings.TrimSpace(data.CoverLetter), resumeName)
+	i.applicantHistory.Save(spaceID, applicantID, vacancyID, "", dbmodels.HistoryTypeNegotiation, changes)
+
+	fio := strings.TrimSpace(strings.Join([]string{applicantData.LastName, applicantData.FirstName, applicantData.MiddleName}, " "))
+	notification := models.GetPushApplicantNegotiation(vacancy.VacancyName, fio).WithEntity(applicantID)
+	go i.sendNotification(*vacancy, notification)
+	logger.Info("получен отклик с карьерного сайта")
+	return applicantID, "", nil
+}
+
+func (i impl) getSpace(spaceID string) (*dbmodels.Space, string, error) {
+	space, err := i.spaceStore.GetByID(spaceID)
+	if err != nil {
+		return nil, "", err
+	}
+	if space == nil || !space.IsActive {
+		return nil, SpaceNotFoundMsg, nil
+	}
+	return space, "", nil
+}
+
+func (i impl) listVacancies(spaceID string) ([]dbmodels.Vacancy, string, error) {
+	_, hMsg, err := i.getSpace(spaceID)
+	if err != nil || hMsg != "" {
+		return nil, hMsg, err
+	}
+	list, err := i.vacancyStore.ListOpened(spaceID)
+	if err != nil {
+		return nil, "", err
+	}
+	return list, "", nil
+}
+
+func (i impl) getOpenedVacancy(spaceID, vacancyID string) (*dbmodels.Vacancy, string, error) {
+	_, hMsg, err := i.getSpace(spaceID)
+	if err != nil || hMsg != "" {
+		return nil, hMsg, err
+	}
+	rec, err := i.vacancyStore.GetByID(spaceID, vacancyID)
+	if err != nil {
+		return nil, "", err
+	}
+	if rec == nil || rec.Status != models.VacancyStatusOpened {
+		return nil, VacancyNotFoundMsg, nil
+	}
+	return rec, "", nil
+}
+
+// isDuplicate повторный отклик на вакансию с тем же телефоном или емайлом
+func (i impl) isDuplicate(spaceID, vacancyID string, data careersiteapimodels.ApplyRequest) (bool, error) {
+	phone := applicantduplicate.NormalizePhone(data.Phone)
+	email := applicantduplicate.NormalizeEmail(data.Email)
+	list, err := i.applicantStore.ListForDuplicateCheck(spaceID, vacancyID)
+	if err != nil {
+		return false, errors.Wrap(err, "ошибка получения списка кандидатов для поиска дублей")
+	}
+	for _, rec := range list {
+		if phone != "" && applicantduplicate.NormalizePhone(rec.Phone) == phone {
+			return true, nil
+		}
+		if email != "" && applicantduplicate.NormalizeEmail(rec.Email) == email {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (i impl) sendNotification(rec dbmodels.Vacancy, data models.NotificationData) {
+	//отправляем автору
+	pushhandler.Instance.SendNotification(rec.AuthorID, data)
+	for _, teamMember := range rec.VacancyTeam {
+		//отправляем команде
+		if rec.AuthorID == teamMember.UserID {
+			continue
+		}
+		pushhandler.Instance.SendNotification(teamMember.UserID, data)
+	}
+}
+
+func getVacancyUrl(rec dbmodels.Vacancy) string {
+	return config.Conf.UIParams.CareerSitePath + rec.SpaceID + "/" + rec.ID
+}
+
+func marshalXml(v any) ([]byte, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка формирования xml")
+	}
+	return append([]byte(xml.Header), body...), nil
+}
@@ -0,0 +1,372 @@
+package customfield
+
+import (
+	"fmt"
+	"hr-tools-backend/db"
+	auditlog "hr-tools-backend/lib/audit-log"
+	customfieldstore "hr-tools-backend/lib/custom-field/store"
+	spacerolestore "hr-tools-backend/lib/space/roles/store"
+	spaceusersstore "hr-tools-backend/lib/space/users/store"
+	initchecker "hr-tools-backend/lib/utils/init-checker"
+	"hr-tools-backend/models"
+	customfieldapimodels "hr-tools-backend/models/api/custom-field"
+	dbmodels "hr-tools-backend/models/db"
+	"slices"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+type Provider interface {
+	// List описания полей спейса, entity пусто - поля всех сущностей
+	List(spaceID string, entity models.CustomFieldEntity) ([]customfieldapimodels.CustomFieldView, error)
+	Get(spaceID, id string) (*customfieldapimodels.CustomFieldView, error)
+	Create(spaceID, userID string, data customfieldapimodels.CustomFieldData) (id, hMsg string, err error)
+	// Update изменение поля, сущность, код и тип не меняются
+	Update(spaceID, id, userID string, data customfieldapimodels.CustomFieldData) (hMsg string, err error)
+	// Delete удаление поля, значения в сущностях больше не отображаются и удаляются при следующем сохранении сущности
+	Delete(spaceID, id, userID string) (hMsg string, err error)
+	// GetFields поля сущности, доступные пользователю, userID пусто - все поля (системные вызовы)
+	GetFields(spaceID, userID string, entity models.CustomFieldEntity) ([]dbmodels.CustomField, error)
+	// PrepareValues проверка и приведение значений полей сущности перед сохранением.
+	// Значения полей, недоступных пользователю или отсутствующих в values, берутся из current, значения неизвестных полей отбрасываются.
+	// Обязательность проверяется только для изменений пользователем (userID пусто - системное создание: карьерный сайт, почта, площадки):
+	// при создании (current nil) - для всех доступных полей, при изменении - только для полей, переданных в values
+	PrepareValues(spaceID, userID string, entity models.CustomFieldEntity, values, current dbmodels.CustomFieldValues) (result dbmodels.CustomFieldValues, hMsg string, err error)
+	// PrepareFilter проверка фильтров по полям, доступным пользователю, и заполнение типа поля
+	PrepareFilter(spaceID, userID string, entity models.CustomFieldEntity, filters dbmodels.CustomFieldFilters) (dbmodels.CustomFieldFilters, error)
+	// FormatValues значения полей для отображения (выгрузки, шаблоны сообщений): код поля - значение, для каждого элемента list
+	FormatValues(fields []dbmodels.CustomField, list ...dbmodels.CustomFieldValues) []map[string]string
+}
+
+var Instance Provider
+
+func NewHandler() {
+	instance := impl{
+		store:          customfieldstore.NewInstance(db.DB),
+		userStore:      spaceusersstore.NewInstance(db.DB),
+		spaceRoleStore: spacerolestore.NewInstance(db.DB),
+	}
+	initchecker.CheckInit(
+		"store", instance.store,
+		"userStore", instance.userStore,
+		"spaceRoleStore", instance.spaceRoleStore,
+	)
+	Instance = instance
+}
+
+type impl struct {
+	store          customfieldstore.Provider
+	userStore      spaceusersstore.Provider
+	spaceRoleStore spacerolestore.Provider
+}
+
+func (i impl) getLogger(spaceID, id string) *log.Entry {
+	logger := log.WithField("space_id", spaceID)
+	if id != "" {
+		logger = logger.WithField("custom_field_id", id)
+	}
+	return logger
+}
+
+func (i impl) List(spaceID string, entity models.CustomFieldEntity) ([]customfieldapimodels.CustomFieldView, error) {
+	list, err := i.store.List(spaceID, entity)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения списка полей")
+	}
+	result := make([]customfieldapimodels.CustomFieldView, 0, len(list))
+	for _, rec := range list {
+		result = append(result, customfieldapimodels.CustomFieldConvert(rec))
+	}
+	return result, nil
+}
+
+func (i impl) Get(spaceID, id string) (*customfieldapimodels.CustomFieldView, error) {
+	rec, err := i.store.GetByID(spaceID, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения поля")
+	}
+	if rec == nil {
+		return nil, nil
+	}
+	result := customfieldapimodels.CustomFieldConvert(*rec)
+	return &result, nil
+}
+
+func (i impl) Create(spaceID, userID string, data customfieldapimodels.CustomFieldData) (id, hMsg string, err error) {
+	hMsg, err = i.checkCode(spaceID, data.Entity, data.Code)
+	if err != nil || hMsg != "" {
+		return "", hMsg, err
+	}
+	hMsg, err = i.checkVisibleRoles(spaceID, data.VisibleRoles)
+	if err != nil || hMsg != "" {
+		return "", hMsg, err
+	}
+	rec := dbmodels.CustomField{
+		BaseSpaceModel: dbmodels.BaseSpaceModel{SpaceID: spaceID},
+		Entity:         data.Entity,
+		Code:           data.Code,
+		Name:           strings.TrimSpace(data.Name),
+		Type:           data.Type,
+		Options:        data.Options,
+		Required:       data.Required,
+		VisibleRoles:   visibleRoles(data.VisibleRoles),
+		Order:          data.Order,
+	}
+	id, err = i.store.Create(rec)
+	if err != nil {
+		return "", "", errors.Wrap(err, "ошибка создания поля")
+	}
+	i.getLogger(spaceID, id).Info("создано пользовательское поле")
+	auditlog.Instance.SaveChanges(spaceID, userID, models.AuditActionCreate, models.AuditEntityCustomField, id,
+		"Создано пользовательское поле", nil, customfieldapimodels.CustomFieldConvert(rec).CustomFieldData)
+	return id, "", nil
+}
+
+func (i impl) Update(spaceID, id, userID string, data customfieldapimodels.CustomFieldData) (hMsg string, err error) {
+	rec, err := i.store.GetByID(spaceID, id)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения поля")
+	}
+	if rec == nil {
+		return "поле не найдено", nil
+	}
+	if rec.Entity != data.Entity || rec.Code != data.Code || rec.Type != data.Type {
+		return "сущность, код и тип поля изменить нельзя", nil
+	}
+	hMsg, err = i.checkVisibleRoles(spaceID, data.VisibleRoles)
+	if err != nil || hMsg != "" {
+		return hMsg, err
+	}
+	options := pq.StringArray(data.Options)
+	if options == nil {
+		options = pq.StringArray{}
+	}
+	updMap := map[string]interface{}{
+		"name":          strings.TrimSpace(data.Name),
+		"options":       options,
+		"required":      data.Required,
+		"visible_roles": visibleRoles(data.VisibleRoles),
+		"order":         data.Order,
+	}
+	err = i.store.Update(spaceID, id, updMap)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка изменения поля")
+	}
+	i.getLogger(spaceID, id).Info("изменено пользовательское поле")
+	after := *rec
+	after.Name = strings.TrimSpace(data.Name)
+	after.Options = options
+	after.Required = data.Required
+	after.VisibleRoles = visibleRoles(data.VisibleRoles)
+	after.Order = data.Order
+	auditlog.Instance.SaveChanges(spaceID, userID, models.AuditActionUpdate, models.AuditEntityCustomField, id,
+		"Изменено пользовательское поле", customfieldapimodels.CustomFieldConvert(*rec).CustomFieldData,
+		customfieldapimodels.CustomFieldConvert(after).CustomFieldData)
+	return "", nil
+}
+
+func (i impl) Delete(spaceID, id, userID string) (hMsg string, err error) {
+	rec, err := i.store.GetByID(spaceID, id)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения поля")
+	}
+	if rec == nil {
+		return "поле не найдено", nil
+	}
+	err = i.store.Delete(spaceID, id)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка удаления поля")
+	}
+	i.getLogger(spaceID, id).Info("удалено пользовательское поле")
+	auditlog.Instance.SaveChanges(spaceID, userID, models.AuditActionDelete, models.AuditEntityCustomField, id,
+		"Удалено пользовательское поле", customfieldapimodels.CustomFieldConvert(*rec).CustomFieldData, nil)
+	return "", nil
+}
+
+func (i impl) GetFields(spaceID, userID string, entity models.CustomFieldEntity) ([]dbmodels.CustomField, error) {
+	list, err := i.store.List(spaceID, entity)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения списка полей")
+	}
+	if userID == "" || len(list) == 0 {
+		return list, nil
+	}
+	role, spaceRoleID, err := i.getUserRole(userID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]dbmodels.CustomField, 0, len(list))
+	for _, field := range list {
+		if field.IsVisible(role, spaceRoleID) {
+			result = append(result, field)
+		}
+	}
+	return result, nil
+}
+
+func (i impl) PrepareValues(spaceID, userID string, entity models.CustomFieldEntity, values, current dbmodels.CustomFieldValues) (result dbmodels.CustomFieldValues, hMsg string, err error) {
+	list, err := i.store.List(spaceID, entity)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "ошибка получения списка полей")
+	}
+	role := models.AdminRole
+	spaceRoleID := ""
+	if userID != "" && len(list) != 0 {
+		role, spaceRoleID, err = i.getUserRole(userID)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	result = dbmodels.CustomFieldValues{}
+	for _, field := range list {
+		rawValue, sent := values[field.Code]
+		if !field.IsVisible(role, spaceRoleID) || (!sent && current != nil) {
+			if value, ok := current[field.Code]; ok {
+				result[field.Code] = value
+			}
+			continue
+		}
+		value, hMsg := normalizeValue(field, rawValue)
+		if hMsg != "" {
+			return nil, hMsg, nil
+		}
+		if value == nil {
+			if field.Required && userID != "" {
+				return nil, fmt.Sprintf("не заполнено обязательное поле '%v'", field.Name), nil
+			}
+			continue
+		}
+		if field.Type == models.CustomFieldUser {
+			user, err := i.userStore.GetByID(value.(string))
+			if err != nil {
+				return nil, "", errors.Wrap(err, "ошибка получения пользователя")
+			}
+			if user == nil || user.SpaceID != spaceID {
+				return nil, fmt.Sprintf("пользователь, указанный в поле '%v', не найден", field.Name), nil
+			}
+		}
+		result[field.Code] = value
+	}
+	return result, "", nil
+}
+
+func (i impl) PrepareFilter(spaceID, userID string, entity models.CustomFieldEntity, filters dbmodels.CustomFieldFilters) (dbmodels.CustomFieldFilters, error) {
+	if len(filters) == 0 {
+		return filters, nil
+	}
+	fields, err := i.GetFields(spaceID, userID, entity)
+	if err != nil {
+		return nil, err
+	}
+	fieldMap := make(map[string]dbmodels.CustomField, len(fields))
+	for _, field := range fields {
+		fieldMap[field.Code] = field
+	}
+	result := make(dbmodels.CustomFieldFilters, 0, len(filters))
+	for _, filter := range filters {
+		field, ok := fieldMap[filter.Code]
+		if !ok {
+			return nil, errors.Errorf("поле %v не найдено", filter.Code)
+		}
+		filter.Type = field.Type
+		if err = filter.Validate(); err != nil {
+			return nil, err
+		}
+		result = append(result, filter)
+	}
+	return result, nil
+}
+
+func (i impl) FormatValues(fields []dbmodels.CustomField, list ...dbmodels.CustomFieldValues) []map[string]string {
+	userNames := map[string]string{}
+	for _, values := range list {
+		for _, field := range fields {
+			if field.Type != models.CustomFieldUser {
+				continue
+			}
+			id, ok := values[field.Code].(string)
+			if !ok {
+				continue
+			}
+			if _, ok = userNames[id]; ok {
+				continue
+			}
+			userNames[id] = ""
+			user, err := i.userStore.GetByID(id)
+			if err != nil {
+				log.
+					WithField("user_id", id).
+					WithError(err).
+					Warn("ошибка получения пользователя для значения пользовательского поля")
+				continue
+			}
+			if user != nil {
+				userNames[id] = user.GetFullName()
+			}
+		}
+	}
+	result := make([]map[string]string, 0, len(list))
+	for _, values := range list {
+		item := make(map[string]string, len(fields))
+		for _, field := range fields {
+			item[field.Code] = formatValue(field, values[field.Code], userNames)
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// getUserRole встроенная роль и роль спейса пользователя
+func (i impl) getUserRole(userID string) (role models.UserRole, spaceRoleID string, err error) {
+	user, err := i.userStore.GetByID(userID)
+	if err != nil {
+		return "", "", errors.Wrap(err, "ошибка получения пользователя")
+	}
+	if user == nil {
+		return "", "", errors.New("пользователь не найден")
+	}
+	if user.SpaceRoleID != nil {
+		spaceRoleID = *user.SpaceRoleID
+	}
+	return user.Role, spaceRoleID, nil
+}
+
+// checkVisibleRoles роли видимости поля - встроенные роли или роли спейса
+func (i impl) checkVisibleRoles(spaceID string, roles []string) (hMsg string, err error) {
+	for _, role := range roles {
+		if slices.Contains(models.AllAvailableRoles, models.UserRole(role)) {
+			continue
+		}
+		spaceRole, err := i.spaceRoleStore.GetByID(spaceID, role)
+		if err != nil {
+			return "", errors.Wrap(err, "ошибка получения роли спейса")
+		}
+		if spaceRole == nil {
+			return fmt.Sprintf("роль %v не найдена", role), nil
+		}
+	}
+	return "", nil
+}
+
+func (i impl) checkCode(spaceID string, entity models.CustomFieldEntity, code string) (hMsg string, err error) {
+	list, err := i.store.List(spaceID, entity)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения списка полей")
+	}
+	for _, rec := range list {
+		if rec.Code == code {
+			return "поле с таким кодом уже существует", nil
+		}
+	}
+	return "", nil
+}
+
+func visibleRoles(roles []string) pq.StringArray {
+	if roles == nil {
+		return pq.StringArray{}
+	}
+	return roles
+}
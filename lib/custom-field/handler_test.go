@@ -0,0 +1,93 @@
+package customfield
+
+import (
+	customfieldstore "hr-tools-backend/lib/custom-field/store"
+	spaceusersstore "hr-tools-backend/lib/space/users/store"
+	"hr-tools-backend/models"
+	dbmodels "hr-tools-backend/models/db"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeFieldStore struct {
+	customfieldstore.Provider
+	list []dbmodels.CustomField
+}
+
+func (s fakeFieldStore) List(spaceID string, entity models.CustomFieldEntity) ([]dbmodels.CustomField, error) {
+	return s.list, nil
+}
+
+type fakeUserStore struct {
+	spaceusersstore.Provider
+	users map[string]dbmodels.SpaceUser
+}
+
+func (s fakeUserStore) GetByID(userID string) (*dbmodels.SpaceUser, error) {
+	user, ok := s.users[userID]
+	if !ok {
+		return nil, nil
+	}
+	return &user, nil
+}
+
+func TestPrepareValues(t *testing.T) {
+	spaceRoleID := "space-role"
+	i := impl{
+		store: fakeFieldStore{list: []dbmodels.CustomField{
+			{Code: "grade", Name: "Грейд", Type: models.CustomFieldText, Required: true},
+			{Code: "source", Name: "Источник", Type: models.CustomFieldText},
+			{Code: "salary", Name: "Зарплата", Type: models.CustomFieldNumber, VisibleRoles: []string{spaceRoleID}},
+		}},
+		userStore: fakeUserStore{users: map[string]dbmodels.SpaceUser{
+			"hr":         {Role: models.HRRole},
+			"space-user": {Role: models.HRRole, SpaceRoleID: &spaceRoleID},
+		}},
+	}
+	current := dbmodels.CustomFieldValues{"source": "сайт", "salary": 100.0}
+
+	// при создании обязательное поле должно быть заполнено
+	_, hMsg, err := i.PrepareValues("space", "hr", models.CustomFieldEntityApplicant, dbmodels.CustomFieldValues{}, nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, hMsg)
+
+	// при изменении без полей в запросе сохраненные значения не теряются, обязательность не проверяется
+	result, hMsg, err := i.PrepareValues("space", "hr", models.CustomFieldEntityApplicant, nil, current)
+	require.NoError(t, err)
+	require.Empty(t, hMsg)
+	require.Equal(t, current, result)
+
+	// переданное пустое обязательное поле не допускается
+	_, hMsg, err = i.PrepareValues("space", "hr", models.CustomFieldEntityApplicant, dbmodels.CustomFieldValues{"grade": ""}, current)
+	require.NoError(t, err)
+	require.NotEmpty(t, hMsg)
+
+	// переданное пустое необязательное поле очищается, скрытое поле берется из current
+	result, hMsg, err = i.PrepareValues("space", "hr", models.CustomFieldEntityApplicant,
+		dbmodels.CustomFieldValues{"source": "", "salary": 1.0}, current)
+	require.NoError(t, err)
+	require.Empty(t, hMsg)
+	require.Equal(t, dbmodels.CustomFieldValues{"salary": 100.0}, result)
+
+	// поле, доступное роли спейса, изменяется пользователем с этой ролью
+	result, hMsg, err = i.PrepareValues("space", "space-user", models.CustomFieldEntityApplicant,
+		dbmodels.CustomFieldValues{"salary": 200.0}, current)
+	require.NoError(t, err)
+	require.Empty(t, hMsg)
+	require.Equal(t, 200.0, result["salary"])
+}
+
+func TestIsVisible(t *testing.T) {
+	field := dbmodels.CustomField{}
+	require.True(t, field.IsVisible(models.HRRole, ""))
+
+	field.VisibleRoles = []string{string(models.HRRole), "space-role"}
+	require.True(t, field.IsVisible(models.HRRole, ""))
+	require.True(t, field.IsVisible(models.AdminRole, ""))
+	require.False(t, field.IsVisible(models.ManagerRole, ""))
+	// пользователю с ролью спейса поле доступно только по роли спейса
+	require.True(t, field.IsVisible(models.ManagerRole, "space-role"))
+	require.False(t, field.IsVisible(models.HRRole, "other-role"))
+	require.False(t, field.IsVisible(models.AdminRole, "other-role"))
+}
@@ -0,0 +1,95 @@
+package customfieldstore
+
+import (
+	"hr-tools-backend/models"
+	dbmodels "hr-tools-backend/models/db"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Provider interface {
+	Create(rec dbmodels.CustomField) (id string, err error)
+	Update(spaceID, id string, updMap map[string]interface{}) error
+	Delete(spaceID, id string) error
+	GetByID(spaceID, id string) (*dbmodels.CustomField, error)
+	// List поля спейса в порядке отображения, entity пусто - поля всех сущностей
+	List(spaceID string, entity models.CustomFieldEntity) ([]dbmodels.CustomField, error)
+}
+
+func NewInstance(DB *gorm.DB) Provider {
+	return &impl{
+		db: DB,
+	}
+}
+
+type impl struct {
+	db *gorm.DB
+}
+
+func (i impl) Create(rec dbmodels.CustomField) (id string, err error) {
+	err = i.db.
+		Omit(clause.Associations).
+		Save(&rec).
+		Error
+	if err != nil {
+		return "", err
+	}
+	return rec.ID, nil
+}
+
+func (i impl) Update(spaceID, id string, updMap map[string]interface{}) error {
+	if len(updMap) == 0 {
+		return nil
+	}
+	return i.db.
+		Model(&dbmodels.CustomField{}).
+		Where("space_id = ?", spaceID).
+		Where("id = ?", id).
+		Updates(updMap).
+		Error
+}
+
+func (i impl) Delete(spaceID, id string) error {
+	return i.db.
+		Where("space_id = ?", spaceID).
+		Where("id = ?", id).
+		Delete(&dbmodels.CustomField{}).
+		Error
+}
+
+func (i impl) GetByID(spaceID, id string) (*dbmodels.CustomField, error) {
+	rec := dbmodels.CustomField{}
+	err := i.db.
+		Where("space_id = ?", spaceID).
+		Where("id = ?", id).
+		First(&rec).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (i impl) List(spaceID string, entity models.CustomFieldEntity) ([]dbmodels.CustomField, error) {
+	list := []dbmodels.CustomField{}
+	tx := i.db.
+		Where("space_id = ?", spaceID)
+	if entity != "" {
+		tx = tx.Where("entity = ?", entity)
+	}
+	err := tx.
+		Order("entity").
+		Order(`"order"`).
+		Order("name").
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
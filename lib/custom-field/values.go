@@ -0,0 +1,146 @@
+package customfield
+
+import (
+	"fmt"
+	"hr-tools-backend/models"
+	dbmodels "hr-tools-backend/models/db"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// normalizeValue приведение значения поля к формату хранения, nil - значение не заполнено
+func normalizeValue(field dbmodels.CustomField, raw interface{}) (value interface{}, hMsg string) {
+	if raw == nil {
+		return nil, ""
+	}
+	wrongValue := fmt.Sprintf("некорректное значение поля '%v'", field.Name)
+	switch field.Type {
+	case models.CustomFieldText, models.CustomFieldUser:
+		text, ok := raw.(string)
+		if !ok {
+			return nil, wrongValue
+		}
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return nil, ""
+		}
+		return text, ""
+	case models.CustomFieldNumber:
+		switch number := raw.(type) {
+		case float64:
+			return number, ""
+		case int:
+			return float64(number), ""
+		case string:
+			if strings.TrimSpace(number) == "" {
+				return nil, ""
+			}
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+			if err != nil {
+				return nil, wrongValue
+			}
+			return parsed, ""
+		}
+		return nil, wrongValue
+	case models.CustomFieldDate:
+		text, ok := raw.(string)
+		if !ok {
+			return nil, wrongValue
+		}
+		if text == "" {
+			return nil, ""
+		}
+		date, err := time.Parse("02.01.2006", text)
+		if err != nil {
+			return nil, fmt.Sprintf("некорректный формат даты в поле '%v', ожидается ДД.ММ.ГГГГ", field.Name)
+		}
+		return date.Format("02.01.2006"), ""
+	case models.CustomFieldEnum:
+		text, ok := raw.(string)
+		if !ok {
+			return nil, wrongValue
+		}
+		if text == "" {
+			return nil, ""
+		}
+		if !slices.Contains(field.Options, text) {
+			return nil, fmt.Sprintf("значение '%v' отсутствует в вариантах поля '%v'", text, field.Name)
+		}
+		return text, ""
+	case models.CustomFieldMultiEnum:
+		items, ok := toStrings(raw)
+		if !ok {
+			return nil, wrongValue
+		}
+		result := []string{}
+		for _, item := range items {
+			if !slices.Contains(field.Options, item) {
+				return nil, fmt.Sprintf("значение '%v' отсутствует в вариантах поля '%v'", item, field.Name)
+			}
+			if !slices.Contains(result, item) {
+				result = append(result, item)
+			}
+		}
+		if len(result) == 0 {
+			return nil, ""
+		}
+		return result, ""
+	case models.CustomFieldBoolean:
+		flag, ok := raw.(bool)
+		if !ok {
+			return nil, wrongValue
+		}
+		return flag, ""
+	}
+	return nil, wrongValue
+}
+
+// formatValue значение поля для отображения в выгрузках и шаблонах, userNames - ФИО пользователей по идентификатору
+func formatValue(field dbmodels.CustomField, value interface{}, userNames map[string]string) string {
+	if value == nil {
+		return ""
+	}
+	switch field.Type {
+	case models.CustomFieldNumber:
+		if number, ok := value.(float64); ok {
+			return strconv.FormatFloat(number, 'f', -1, 64)
+		}
+	case models.CustomFieldMultiEnum:
+		if items, ok := toStrings(value); ok {
+			return strings.Join(items, ", ")
+		}
+	case models.CustomFieldBoolean:
+		if flag, ok := value.(bool); ok {
+			if flag {
+				return "Да"
+			}
+			return "Нет"
+		}
+	case models.CustomFieldUser:
+		if id, ok := value.(string); ok {
+			return userNames[id]
+		}
+	}
+	return fmt.Sprint(value)
+}
+
+// toStrings список строк из значения, после чтения из jsonb список приходит как []interface{}
+func toStrings(value interface{}) ([]string, bool) {
+	switch items := value.(type) {
+	case []string:
+		return items, true
+	case []interface{}:
+		result := make([]string, 0, len(items))
+		for _, item := range items {
+			text, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			result = append(result, text)
+		}
+		return result, true
+	}
+	return nil, false
+}
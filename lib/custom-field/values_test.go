@@ -0,0 +1,91 @@
+package customfield
+
+import (
+	"hr-tools-backend/models"
+	dbmodels "hr-tools-backend/models/db"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeValue(t *testing.T) {
+	field := dbmodels.CustomField{Name: "Поле", Type: models.CustomFieldText}
+	value, hMsg := normalizeValue(field, "  текст ")
+	require.Empty(t, hMsg)
+	require.Equal(t, "текст", value)
+	value, hMsg = normalizeValue(field, "   ")
+	require.Empty(t, hMsg)
+	require.Nil(t, value)
+	_, hMsg = normalizeValue(field, 10.0)
+	require.NotEmpty(t, hMsg)
+
+	field.Type = models.CustomFieldNumber
+	value, hMsg = normalizeValue(field, "12.5")
+	require.Empty(t, hMsg)
+	require.Equal(t, 12.5, value)
+	_, hMsg = normalizeValue(field, "abc")
+	require.NotEmpty(t, hMsg)
+
+	field.Type = models.CustomFieldDate
+	value, hMsg = normalizeValue(field, "01.02.2024")
+	require.Empty(t, hMsg)
+	require.Equal(t, "01.02.2024", value)
+	_, hMsg = normalizeValue(field, "2024-02-01")
+	require.NotEmpty(t, hMsg)
+
+	field.Type = models.CustomFieldEnum
+	field.Options = []string{"A", "B"}
+	value, hMsg = normalizeValue(field, "B")
+	require.Empty(t, hMsg)
+	require.Equal(t, "B", value)
+	_, hMsg = normalizeValue(field, "C")
+	require.NotEmpty(t, hMsg)
+
+	// после разбора json список приходит как []interface{}, повторы удаляются
+	field.Type = models.CustomFieldMultiEnum
+	value, hMsg = normalizeValue(field, []interface{}{"A", "B", "A"})
+	require.Empty(t, hMsg)
+	require.Equal(t, []string{"A", "B"}, value)
+	value, hMsg = normalizeValue(field, []interface{}{})
+	require.Empty(t, hMsg)
+	require.Nil(t, value)
+	_, hMsg = normalizeValue(field, []interface{}{"A", "C"})
+	require.NotEmpty(t, hMsg)
+
+	field.Type = models.CustomFieldBoolean
+	value, hMsg = normalizeValue(field, false)
+	require.Empty(t, hMsg)
+	require.Equal(t, false, value)
+	_, hMsg = normalizeValue(field, "true")
+	require.NotEmpty(t, hMsg)
+}
+
+func TestFormatValue(t *testing.T) {
+	userNames := map[string]string{"user": "Иванов Иван"}
+	require.Equal(t, "12.5", formatValue(dbmodels.CustomField{Type: models.CustomFieldNumber}, 12.5, userNames))
+	require.Equal(t, "100", formatValue(dbmodels.CustomField{Type: models.CustomFieldNumber}, 100.0, userNames))
+	require.Equal(t, "A, B", formatValue(dbmodels.CustomField{Type: models.CustomFieldMultiEnum}, []interface{}{"A", "B"}, userNames))
+	require.Equal(t, "Да", formatValue(dbmodels.CustomField{Type: models.CustomFieldBoolean}, true, userNames))
+	require.Equal(t, "Нет", formatValue(dbmodels.CustomField{Type: models.CustomFieldBoolean}, false, userNames))
+	require.Equal(t, "Иванов Иван", formatValue(dbmodels.CustomField{Type: models.CustomFieldUser}, "user", userNames))
+	require.Equal(t, "01.02.2024", formatValue(dbmodels.CustomField{Type: models.CustomFieldDate}, "01.02.2024", userNames))
+	require.Equal(t, "", formatValue(dbmodels.CustomField{Type: models.CustomFieldText}, nil, userNames))
+}
+
+func TestFilterCondition(t *testing.T) {
+	filters := dbmodels.CustomFieldFilters{
+		{Code: "grade", Value: "Senior", Type: models.CustomFieldEnum},
+		{Code: "budget", From: "100", To: "200", Type: models.CustomFieldNumber},
+	}
+	query, args := filters.Condition("applicants.custom_fields")
+	require.Equal(t, "((applicants.custom_fields->>@cf_code_0) = @cf_value_0 AND "+
+		"(applicants.custom_fields->>@cf_code_1)::numeric >= @cf_from_1 AND "+
+		"(applicants.custom_fields->>@cf_code_1)::numeric <= @cf_to_1)", query)
+	require.Equal(t, "grade", args["cf_code_0"])
+	require.Equal(t, "Senior", args["cf_value_0"])
+	require.Equal(t, 100.0, args["cf_from_1"])
+	require.Equal(t, 200.0, args["cf_to_1"])
+
+	query, _ = dbmodels.CustomFieldFilters{{Code: "grade", Type: models.CustomFieldEnum}}.Condition("applicants.custom_fields")
+	require.Empty(t, query)
+}
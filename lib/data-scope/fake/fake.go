@@ -0,0 +1,32 @@
+// Package datascopefake подмена области видимости данных в тестах
+package datascopefake
+
+import (
+	datascope "hr-tools-backend/lib/data-scope"
+	dbmodels "hr-tools-backend/models/db"
+	"slices"
+	"testing"
+)
+
+// DataScope пользователю видны только собственные записи и записи из Visible
+type DataScope struct {
+	datascope.Provider
+	Visible map[datascope.Entity][]string
+}
+
+func (f DataScope) GetScope(spaceID, userID string) (*dbmodels.DataScope, error) {
+	return &dbmodels.DataScope{UserID: userID}, nil
+}
+
+func (f DataScope) IsVisible(spaceID, userID string, entity datascope.Entity, id string) (bool, error) {
+	return slices.Contains(f.Visible[entity], id), nil
+}
+
+// Set подмена datascope.Instance на время теста, после теста восстанавливается прежний экземпляр
+func Set(t testing.TB, fake DataScope) {
+	prev := datascope.Instance
+	datascope.Instance = fake
+	t.Cleanup(func() {
+		datascope.Instance = prev
+	})
+}
@@ -0,0 +1,132 @@
+package datascope
+
+import (
+	"hr-tools-backend/db"
+	applicantstore "hr-tools-backend/lib/applicant/store"
+	candidatestore "hr-tools-backend/lib/candidate/store"
+	departmentstore "hr-tools-backend/lib/dicts/department/store"
+	filesdbstorage "hr-tools-backend/lib/file-storage/storage"
+	interviewstore "hr-tools-backend/lib/interview/store"
+	offerstore "hr-tools-backend/lib/offer/store"
+	spaceusersstore "hr-tools-backend/lib/space/users/store"
+	initchecker "hr-tools-backend/lib/utils/init-checker"
+	vacancyreqstore "hr-tools-backend/lib/vacancy-req/store"
+	vacancystore "hr-tools-backend/lib/vacancy/store"
+	vkvideoanalyzestore "hr-tools-backend/lib/vk/vk-video-analyze-store"
+	"hr-tools-backend/models"
+	dbmodels "hr-tools-backend/models/db"
+
+	"github.com/pkg/errors"
+)
+
+// Entity тип записи, видимость которой проверяется
+type Entity int
+
+const (
+	EntityVacancy Entity = iota
+	EntityVacancyRequest
+	EntityApplicant
+	EntityCandidate
+	EntityInterview
+	EntityOffer
+	EntityFile
+	EntityVideoAnalyze
+)
+
+type Provider interface {
+	// GetScope область видимости пользователя для ограничения выборок
+	GetScope(spaceID, userID string) (*dbmodels.DataScope, error)
+	// IsVisible запись видна пользователю
+	IsVisible(spaceID, userID string, entity Entity, id string) (bool, error)
+}
+
+var Instance Provider
+
+func NewHandler() {
+	instance := impl{
+		userStore:       spaceusersstore.NewInstance(db.DB),
+		departmentStore: departmentstore.NewInstance(db.DB),
+		vacancyStore:    vacancystore.NewInstance(db.DB),
+		requestStore:    vacancyreqstore.NewInstance(db.DB),
+		applicantStore:  applicantstore.NewInstance(db.DB),
+		candidateStore:  candidatestore.NewInstance(db.DB),
+		interviewStore:  interviewstore.NewInstance(db.DB),
+		offerStore:      offerstore.NewInstance(db.DB),
+		filesStore:      filesdbstorage.NewInstance(db.DB),
+		videoStore:      vkvideoanalyzestore.NewInstance(db.DB),
+	}
+	initchecker.CheckInit(
+		"userStore", instance.userStore,
+		"departmentStore", instance.departmentStore,
+		"vacancyStore", instance.vacancyStore,
+		"requestStore", instance.requestStore,
+		"applicantStore", instance.applicantStore,
+		"candidateStore", instance.candidateStore,
+		"interviewStore", instance.interviewStore,
+		"offerStore", instance.offerStore,
+		"filesStore", instance.filesStore,
+		"videoStore", instance.videoStore,
+	)
+	Instance = instance
+}
+
+type impl struct {
+	userStore       spaceusersstore.Provider
+	departmentStore departmentstore.Provider
+	vacancyStore    vacancystore.Provider
+	requestStore    vacancyreqstore.Provider
+	applicantStore  applicantstore.Provider
+	candidateStore  candidatestore.Provider
+	interviewStore  interviewstore.Provider
+	offerStore      offerstore.Provider
+	filesStore      filesdbstorage.Provider
+	videoStore      vkvideoanalyzestore.Provider
+}
+
+func (i impl) GetScope(spaceID, userID string) (*dbmodels.DataScope, error) {
+	user, err := i.userStore.GetByID(userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения пользователя")
+	}
+	if user == nil || user.SpaceID != spaceID {
+		return nil, errors.New("пользователь не найден")
+	}
+	scope := dbmodels.DataScope{
+		UserID:     userID,
+		Scope:      user.GetDataScope(),
+		FullAccess: user.Role.IsSpaceAdmin(),
+	}
+	if scope.Scope == models.DataScopeDepartment && user.DepartmentID != nil {
+		scope.DepartmentIDs, err = i.departmentStore.GetSubtreeIDs(spaceID, *user.DepartmentID)
+		if err != nil {
+			return nil, errors.Wrap(err, "ошибка получения дочерних подразделений")
+		}
+	}
+	return &scope, nil
+}
+
+func (i impl) IsVisible(spaceID, userID string, entity Entity, id string) (bool, error) {
+	scope, err := i.GetScope(spaceID, userID)
+	if err != nil {
+		return false, err
+	}
+	switch entity {
+	case EntityVacancy:
+		return i.vacancyStore.IsVisible(spaceID, id, scope)
+	case EntityVacancyRequest:
+		return i.requestStore.IsVisible(spaceID, id, scope)
+	case EntityApplicant:
+		return i.applicantStore.IsVisible(spaceID, id, scope)
+	case EntityCandidate:
+		return i.candidateStore.IsVisible(spaceID, id, scope)
+	case EntityInterview:
+		return i.interviewStore.IsVisible(spaceID, id, scope)
+	case EntityOffer:
+		return i.offerStore.IsVisible(spaceID, id, scope)
+	case EntityFile:
+		return i.filesStore.IsVisible(spaceID, id, scope)
+	case EntityVideoAnalyze:
+		return i.videoStore.IsVisible(spaceID, id, scope)
+	}
+	return false, errors.Errorf("неизвестный тип записи: %v", entity)
+}
@@ -0,0 +1,202 @@
+package datascope
+
+import (
+	"strings"
+	"testing"
+
+	applicantstore "hr-tools-backend/lib/applicant/store"
+	candidatestore "hr-tools-backend/lib/candidate/store"
+	departmentstore "hr-tools-backend/lib/dicts/department/store"
+	filesdbstorage "hr-tools-backend/lib/file-storage/storage"
+	interviewstore "hr-tools-backend/lib/interview/store"
+	offerstore "hr-tools-backend/lib/offer/store"
+	spaceusersstore "hr-tools-backend/lib/space/users/store"
+	vkvideoanalyzestore "hr-tools-backend/lib/vk/vk-video-analyze-store"
+	"hr-tools-backend/models"
+	dbmodels "hr-tools-backend/models/db"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeUserStore struct {
+	spaceusersstore.Provider
+	users map[string]*dbmodels.SpaceUser
+}
+
+func (s fakeUserStore) GetByID(userID string) (*dbmodels.SpaceUser, error) {
+	return s.users[userID], nil
+}
+
+type fakeDepartmentStore struct {
+	departmentstore.Provider
+}
+
+func (s fakeDepartmentStore) GetSubtreeIDs(spaceID, id string) ([]string, error) {
+	return []string{id, id + "-child"}, nil
+}
+
+func TestGetScope(t *testing.T) {
+	departmentID := "dep"
+	i := impl{
+		userStore: fakeUserStore{users: map[string]*dbmodels.SpaceUser{
+			"admin":      {SpaceID: "space", Role: models.AdminRole, DataScope: models.DataScopeOwn},
+			"hr":         {SpaceID: "space", Role: models.HRRole},
+			"manager":    {SpaceID: "space", Role: models.ManagerRole, DepartmentID: &departmentID},
+			"no-dep":     {SpaceID: "space", Role: models.ManagerRole},
+			"specialist": {SpaceID: "space", Role: models.SpecialistRole, DataScope: models.DataScopeOwn},
+		}},
+		departmentStore: fakeDepartmentStore{},
+	}
+
+	scope, err := i.GetScope("space", "admin")
+	require.NoError(t, err)
+	require.True(t, scope.FullAccess)
+	query, _ := scope.VacancyCondition("vacancies")
+	require.Empty(t, query, "администратор видит все")
+
+	scope, err = i.GetScope("space", "hr")
+	require.NoError(t, err)
+	require.False(t, scope.FullAccess)
+	require.Equal(t, models.DataScopeSpace, scope.GetScope())
+	query, args := scope.VacancyRequestCondition("vacancy_requests")
+	require.Contains(t, query, "vacancy_requests.confidential IS NOT TRUE")
+	require.Equal(t, "hr", args["scope_user"])
+
+	scope, err = i.GetScope("space", "manager")
+	require.NoError(t, err)
+	require.Equal(t, models.DataScopeDepartment, scope.GetScope())
+	require.Equal(t, []string{"dep", "dep-child"}, scope.DepartmentIDs)
+	query, _ = scope.VacancyCondition("vacancies")
+	require.Contains(t, query, "vacancies.department_id IN @scope_departments")
+
+	scope, err = i.GetScope("space", "no-dep")
+	require.NoError(t, err)
+	require.Equal(t, models.DataScopeTeam, scope.GetScope(), "без подразделения видимость ограничена командой")
+	query, _ = scope.VacancyCondition("vacancies")
+	require.NotContains(t, query, "department_id")
+
+	scope, err = i.GetScope("space", "specialist")
+	require.NoError(t, err)
+	query, _ = scope.ApplicantCondition("applicants")
+	require.True(t, strings.HasPrefix(query, "applicants.vacancy_id IN (SELECT v_scope.id FROM vacancies v_scope"))
+	require.NotContains(t, query, "vt_s.vacancy_id = v_scope.id AND vt_s.user_id = @scope_user)", "участие в команде без ответственности не учитывается")
+	query, _ = scope.VacancyRequestCondition("vacancy_requests")
+	require.NotContains(t, query, "confidential", "свои заявки видны, в том числе конфиденциальные")
+
+	_, err = i.GetScope("other-space", "hr")
+	require.Error(t, err)
+
+	var system *dbmodels.DataScope
+	query, _ = system.ApplicantCondition("applicants")
+	require.Empty(t, query, "системные выборки без ограничений")
+}
+
+func TestApplicantRefConditions(t *testing.T) {
+	scope := &dbmodels.DataScope{UserID: "user", Scope: models.DataScopeTeam}
+	query, args := scope.ApplicantRefCondition("interviews.applicant_id")
+	require.True(t, strings.HasPrefix(query, "interviews.applicant_id IN (SELECT a_scope.id FROM applicants a_scope WHERE a_scope.vacancy_id IN"))
+	require.Equal(t, "user", args["scope_user"])
+
+	query, _ = scope.CandidateCondition("candidates")
+	require.True(t, strings.HasPrefix(query, "EXISTS (SELECT 1 FROM applicants a_scope WHERE a_scope.candidate_id = candidates.id AND"))
+
+	scope.FullAccess = true
+	query, _ = scope.ApplicantRefCondition("offers.applicant_id")
+	require.Empty(t, query)
+	query, _ = scope.CandidateCondition("candidates")
+	require.Empty(t, query)
+}
+
+// fakeVisibility записи, видимые пользователю без полного доступа
+type fakeVisibility map[string]bool
+
+func (v fakeVisibility) isVisible(id string, scope *dbmodels.DataScope) (bool, error) {
+	return scope.FullAccess || v[id], nil
+}
+
+type fakeApplicantStore struct {
+	applicantstore.Provider
+	fakeVisibility
+}
+
+func (s fakeApplicantStore) IsVisible(spaceID, id string, scope *dbmodels.DataScope) (bool, error) {
+	return s.isVisible(id, scope)
+}
+
+type fakeCandidateStore struct {
+	candidatestore.Provider
+	fakeVisibility
+}
+
+func (s fakeCandidateStore) IsVisible(spaceID, id string, scope *dbmodels.DataScope) (bool, error) {
+	return s.isVisible(id, scope)
+}
+
+type fakeInterviewStore struct {
+	interviewstore.Provider
+	fakeVisibility
+}
+
+func (s fakeInterviewStore) IsVisible(spaceID, id string, scope *dbmodels.DataScope) (bool, error) {
+	return s.isVisible(id, scope)
+}
+
+type fakeOfferStore struct {
+	offerstore.Provider
+	fakeVisibility
+}
+
+func (s fakeOfferStore) IsVisible(spaceID, id string, scope *dbmodels.DataScope) (bool, error) {
+	return s.isVisible(id, scope)
+}
+
+type fakeFilesStore struct {
+	filesdbstorage.Provider
+	fakeVisibility
+}
+
+func (s fakeFilesStore) IsVisible(spaceID, id string, scope *dbmodels.DataScope) (bool, error) {
+	return s.isVisible(id, scope)
+}
+
+type fakeVideoStore struct {
+	vkvideoanalyzestore.Provider
+	fakeVisibility
+}
+
+func (s fakeVideoStore) IsVisible(spaceID, id string, scope *dbmodels.DataScope) (bool, error) {
+	return s.isVisible(id, scope)
+}
+
+func TestIsVisible(t *testing.T) {
+	visible := fakeVisibility{"own": true}
+	i := impl{
+		userStore: fakeUserStore{users: map[string]*dbmodels.SpaceUser{
+			"admin":      {SpaceID: "space", Role: models.AdminRole},
+			"specialist": {SpaceID: "space", Role: models.SpecialistRole, DataScope: models.DataScopeOwn},
+		}},
+		departmentStore: fakeDepartmentStore{},
+		applicantStore:  fakeApplicantStore{fakeVisibility: visible},
+		candidateStore:  fakeCandidateStore{fakeVisibility: visible},
+		interviewStore:  fakeInterviewStore{fakeVisibility: visible},
+		offerStore:      fakeOfferStore{fakeVisibility: visible},
+		filesStore:      fakeFilesStore{fakeVisibility: visible},
+		videoStore:      fakeVideoStore{fakeVisibility: visible},
+	}
+	entities := []Entity{EntityApplicant, EntityCandidate, EntityInterview, EntityOffer, EntityFile, EntityVideoAnalyze}
+	for _, entity := range entities {
+		ok, err := i.IsVisible("space", "specialist", entity, "own")
+		require.NoError(t, err)
+		require.True(t, ok, entity)
+
+		ok, err = i.IsVisible("space", "specialist", entity, "foreign")
+		require.NoError(t, err)
+		require.False(t, ok, "запись вне области видимости: %v", entity)
+
+		ok, err = i.IsVisible("space", "admin", entity, "foreign")
+		require.NoError(t, err)
+		require.True(t, ok, entity)
+	}
+	_, err := i.IsVisible("space", "specialist", Entity(100), "own")
+	require.Error(t, err)
+}
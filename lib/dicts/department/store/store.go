@@ -10,6 +10,8 @@ type Provider interface {
 	Create(rec dbmodels.Department) (id string, err error)
 	GetByID(spaceID, id string) (rec *dbmodels.Department, err error)
 	FindByCompanyStruct(spaceID, companyStructID string) (list []dbmodels.Department, err error)
+	// GetSubtreeIDs подразделение со всеми дочерними подразделениями
+	GetSubtreeIDs(spaceID, id string) (ids []string, err error)
 	Update(spaceID, id string, updMap map[string]interface{}) error
 	Delete(spaceID, id string) error
 }
@@ -76,6 +78,23 @@ func (i impl) FindByCompanyStruct(spaceID, companyStructID string) (list []dbmod
 	return list, nil
 }
 
+func (i impl) GetSubtreeIDs(spaceID, id string) (ids []string, err error) {
+	ids = []string{}
+	err = i.db.
+		Raw(`WITH RECURSIVE tree AS (
+				SELECT id FROM departments WHERE id = ? AND space_id = ?
+				UNION
+				SELECT d.id FROM departments d JOIN tree ON d.parent_id = tree.id WHERE d.space_id = ?
+			)
+			SELECT id FROM tree`, id, spaceID, spaceID).
+		Scan(&ids).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
 func (i impl) Update(spaceID, id string, updMap map[string]interface{}) error {
 	if len(updMap) == 0 {
 		return nil
@@ -0,0 +1,230 @@
+package emailinbox
+
+import (
+	"bytes"
+	"context"
+	"hr-tools-backend/db"
+	"hr-tools-backend/lib/applicant"
+	applicanthistoryhandler "hr-tools-backend/lib/applicant-history"
+	applicantstore "hr-tools-backend/lib/applicant/store"
+	filestorage "hr-tools-backend/lib/file-storage"
+	pushhandler "hr-tools-backend/lib/space/push/handler"
+	initchecker "hr-tools-backend/lib/utils/init-checker"
+	vacancystore "hr-tools-backend/lib/vacancy/store"
+	"hr-tools-backend/models"
+	applicantapimodels "hr-tools-backend/models/api/applicant"
+	dbmodels "hr-tools-backend/models/db"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+type Provider interface {
+	// HandleMessage обработка письма, полученного в почтовый ящик спейса:
+	// письмо от известного кандидата сохраняется в его историю, иначе по письму создается кандидат на вакансию
+	HandleMessage(ctx context.Context, spaceID string, msg Message) error
+}
+
+var Instance Provider
+
+func NewHandler() {
+	instance := impl{
+		vacancyStore:     vacancystore.NewInstance(db.DB),
+		applicantStore:   applicantstore.NewInstance(db.DB),
+		applicant:        applicant.Instance,
+		applicantHistory: applicanthistoryhandler.Instance,
+		filesStorage:     filestorage.Instance,
+	}
+	initchecker.CheckInit(
+		"vacancyStore", instance.vacancyStore,
+		"applicantStore", instance.applicantStore,
+		"applicant", instance.applicant,
+		"applicantHistory", instance.applicantHistory,
+		"filesStorage", instance.filesStorage,
+	)
+	Instance = instance
+}
+
+type impl struct {
+	vacancyStore     vacancystore.Provider
+	applicantStore   applicantstore.Provider
+	applicant        applicant.Provider
+	applicantHistory applicanthistoryhandler.Provider
+	filesStorage     filestorage.Provider
+}
+
+func (i impl) getLogger(spaceID string, msg Message) *log.Entry {
+	return log.
+		WithField("integration", "EmailInbox").
+		WithField("space_id", spaceID).
+		WithField("message_id", msg.MessageID)
+}
+
+func (i impl) HandleMessage(ctx context.Context, spaceID string, msg Message) error {
+	logger := i.getLogger(spaceID, msg)
+	if msg.From == "" {
+		logger.Warn("в письме не указан отправитель, письмо пропущено")
+		return nil
+	}
+	vacancy, err := i.matchVacancy(spaceID, msg)
+	if err != nil {
+		return err
+	}
+	applicants, err := i.applicantStore.ListByEmail(spaceID, msg.From)
+	if err != nil {
+		return errors.Wrap(err, "ошибка поиска кандидата по емайлу")
+	}
+	for _, rec := range applicants {
+		if vacancy == nil || rec.VacancyID == vacancy.ID {
+			return i.saveReply(ctx, rec, msg)
+		}
+	}
+	if vacancy == nil {
+		logger.Info("не удалось определить вакансию по письму, письмо пропущено")
+		return nil
+	}
+	return i.createApplicant(ctx, *vacancy, msg)
+}
+
+// matchVacancy поиск открытой вакансии по идентификатору из темы письма или адреса получателя
+func (i impl) matchVacancy(spaceID string, msg Message) (*dbmodels.Vacancy, error) {
+	for _, vacancyID := range msg.VacancyIDs() {
+		rec, err := i.vacancyStore.GetByID(spaceID, vacancyID)
+		if err != nil {
+			return nil, errors.Wrap(err, "ошибка получения вакансии")
+		}
+		if rec != nil && rec.Status == models.VacancyStatusOpened {
+			return rec, nil
+		}
+	}
+	return nil, nil
+}
+
+// saveReply сохранение письма от существующего кандидата в историю
+func (i impl) saveReply(ctx context.Context, rec dbmodels.Applicant, msg Message) error {
+	logger := i.getLogger(rec.SpaceID, msg).
+		WithField("applicant_id", rec.ID)
+	attachments := make([]string, 0, len(msg.Attachments))
+	for _, attachment := range msg.Attachments {
+		err := i.upload(ctx, rec.SpaceID, rec.ID, attachment, dbmodels.ApplicantDoc)
+		if err != nil {
+			logger.WithError(err).Error("ошибка сохранения вложения из письма кандидата")
+			continue
+		}
+		attachments = append(attachments, attachment.FileName)
+	}
+	changes := applicanthistoryhandler.GetMailReceivedChange(msg.Subject, msg.Text, attachments)
+	i.applicantHistory.Save(rec.SpaceID, rec.ID, rec.VacancyID, "", dbmodels.HistoryTypeEmailIn, changes)
+	logger.Info("получено письмо от кандидата")
+	return nil
+}
+
+func (i impl) createApplicant(ctx context.Context, vacancy dbmodels.Vacancy, msg Message) error {
+	logger := i.getLogger(vacancy.SpaceID, msg).
+		WithField("vacancy_id", vacancy.ID)
+	if msg.MessageID != "" {
+		found, err := i.applicantStore.IsExistNegotiationID(vacancy.SpaceID, msg.MessageID, models.ApplicantSourceEmail)
+		if err != nil {
+			return errors.Wrap(err, "ошибка проверки наличия отклика")
+		}
+		if found {
+			return nil
+		}
+	}
+	firstName, lastName := splitName(msg.FromName, msg.From)
+	applicantData := applicantapimodels.ApplicantData{
+		VacancyID: vacancy.ID,
+		Source:    models.ApplicantSourceEmail,
+		FirstName: firstName,
+		LastName:  lastName,
+		Email:     msg.From,
+		Params: dbmodels.ApplicantParams{
+			Employments:        []models.Employment{},
+			Schedules:          []models.Schedule{},
+			Languages:          []dbmodels.Language{},
+			DriverLicenseTypes: []models.DriverLicenseType{},
+		},
+	}
+	applicantID, hMsg, err := i.applicant.CreateApplicant(vacancy.SpaceID, "", applicantData)
+	if err != nil {
+		return errors.Wrap(err, "ошибка создания кандидата по отклику из почты")
+	}
+	if hMsg != "" {
+		return errors.Errorf("ошибка создания кандидата по отклику из почты: %v", hMsg)
+	}
+	logger = logger.WithField("applicant_id", applicantID)
+
+	updMap := map[string]interface{}{
+		"negotiation_date": time.Now(),
+	}
+	if msg.MessageID != "" {
+		updMap["negotiation_id"] = msg.MessageID
+	}
+	err = i.applicantStore.Update(applicantID, updMap)
+	if err != nil {
+		logger.WithError(err).Error("ошибка сохранения данных отклика")
+	}
+
+	resumeIndex := msg.ResumeIndex()
+	attachments := make([]string, 0, len(msg.Attachments))
+	for k, attachment := range msg.Attachments {
+		fileType := dbmodels.ApplicantDoc
+		if k == resumeIndex {
+			fileType = dbmodels.ApplicantResume
+		}
+		err = i.upload(ctx, vacancy.SpaceID, applicantID, attachment, fileType)
+		if err != nil {
+			logger.WithError(err).Error("ошибка сохранения вложения из письма кандидата")
+			continue
+		}
+		attachments = append(attachments, attachment.FileName)
+	}
+	changes := applicanthistoryhandler.GetEmailNegotiation(msg.Subject, msg.Text, attachments)
+	i.applicantHistory.Save(vacancy.SpaceID, applicantID, vacancy.ID, "", dbmodels.HistoryTypeNegotiation, changes)
+
+	fio := strings.TrimSpace(strings.Join([]string{lastName, firstName}, " "))
+	notification := models.GetPushApplicantNegotiation(vacancy.VacancyName, fio).WithEntity(applicantID)
+	go i.sendNotification(vacancy, notification)
+	logger.Info("получен отклик по электронной почте")
+	return nil
+}
+
+func (i impl) upload(ctx context.Context, spaceID, applicantID string, attachment Attachment, fileType dbmodels.FileType) error {
+	fileInfo := dbmodels.UploadFileInfo{
+		SpaceID:     spaceID,
+		ApplicantID: applicantID,
+		FileName:    attachment.FileName,
+		FileType:    fileType,
+		ContentType: attachment.ContentType,
+	}
+	_, err := i.filesStorage.UploadObject(ctx, fileInfo, bytes.NewReader(attachment.Body), len(attachment.Body))
+	return err
+}
+
+func (i impl) sendNotification(rec dbmodels.Vacancy, data models.NotificationData) {
+	//отправляем автору
+	pushhandler.Instance.SendNotification(rec.AuthorID, data)
+	for _, teamMember := range rec.VacancyTeam {
+		//отправляем команде
+		if rec.AuthorID == teamMember.UserID {
+			continue
+		}
+		pushhandler.Instance.SendNotification(teamMember.UserID, data)
+	}
+}
+
+// splitName имя и фамилия кандидата из имени отправителя, если имя не указано - используется емайл
+func splitName(name, email string) (firstName, lastName string) {
+	parts := strings.Fields(name)
+	switch len(parts) {
+	case 0:
+		local, _, _ := strings.Cut(email, "@")
+		return local, ""
+	case 1:
+		return parts[0], ""
+	default:
+		return parts[0], strings.Join(parts[1:], " ")
+	}
+}
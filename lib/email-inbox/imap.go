@@ -0,0 +1,101 @@
+package emailinbox
+
+import (
+	"bytes"
+	"io"
+	"slices"
+
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/client"
+	"github.com/pkg/errors"
+)
+
+// MailboxConfig - параметры подключения к почтовому ящику спейса
+type MailboxConfig struct {
+	Addr     string // host:port
+	Login    string
+	Password string
+	Mailbox  string
+	TLS      bool
+}
+
+// максимальное кол-во писем, обрабатываемых за один запуск
+const fetchLimit = 50
+
+// FetchUnseen получает непрочитанные письма из почтового ящика,
+// письмо помечается прочитанным, если handle завершился без ошибки
+func FetchUnseen(cfg MailboxConfig, handle func(body io.Reader) error) (processed int, err error) {
+	var c *client.Client
+	if cfg.TLS {
+		c, err = client.DialTLS(cfg.Addr, nil)
+	} else {
+		c, err = client.Dial(cfg.Addr)
+	}
+	if err != nil {
+		return 0, errors.Wrap(err, "ошибка подключения к IMAP серверу")
+	}
+	defer c.Logout()
+
+	err = c.Login(cfg.Login, cfg.Password)
+	if err != nil {
+		return 0, errors.Wrap(err, "ошибка авторизации на IMAP сервере")
+	}
+	mailbox := cfg.Mailbox
+	if mailbox == "" {
+		mailbox = "INBOX"
+	}
+	_, err = c.Select(mailbox, false)
+	if err != nil {
+		return 0, errors.Wrapf(err, "ошибка открытия папки %v", mailbox)
+	}
+
+	criteria := imap.NewSearchCriteria()
+	criteria.WithoutFlags = []string{imap.SeenFlag}
+	uids, err := c.UidSearch(criteria)
+	if err != nil {
+		return 0, errors.Wrap(err, "ошибка поиска непрочитанных писем")
+	}
+	if len(uids) == 0 {
+		return 0, nil
+	}
+	slices.Sort(uids)
+	if len(uids) > fetchLimit {
+		uids = uids[:fetchLimit]
+	}
+
+	// письма вычитываются полностью до обработки, т.к. во время FETCH нельзя выполнять другие команды
+	seqSet := new(imap.SeqSet)
+	seqSet.AddNum(uids...)
+	section := &imap.BodySectionName{Peek: true}
+	messages := make(chan *imap.Message, len(uids))
+	err = c.UidFetch(seqSet, []imap.FetchItem{imap.FetchUid, section.FetchItem()}, messages)
+	if err != nil {
+		return 0, errors.Wrap(err, "ошибка получения писем")
+	}
+
+	seen := new(imap.SeqSet)
+	for msg := range messages {
+		literal := msg.GetBody(section)
+		if literal == nil {
+			continue
+		}
+		body, err := io.ReadAll(literal)
+		if err != nil {
+			return processed, errors.Wrap(err, "ошибка чтения письма")
+		}
+		err = handle(bytes.NewReader(body))
+		if err != nil {
+			continue
+		}
+		seen.AddNum(msg.Uid)
+		processed++
+	}
+	if seen.Empty() {
+		return processed, nil
+	}
+	err = c.UidStore(seen, imap.FormatFlagsOp(imap.AddFlags, true), []interface{}{imap.SeenFlag}, nil)
+	if err != nil {
+		return processed, errors.Wrap(err, "ошибка пометки писем прочитанными")
+	}
+	return processed, nil
+}
@@ -0,0 +1,125 @@
+package emailinbox
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emersion/go-imap/backend/memory"
+	"github.com/emersion/go-imap/client"
+	"github.com/emersion/go-imap/server"
+	"github.com/stretchr/testify/require"
+)
+
+const testVacancyID = "0b6c7f5e-3f53-4f7e-9a44-6f1f0c1f6a11"
+
+const testMessage = "From: =?utf-8?B?0JjQstCw0L0g0J/QtdGC0YDQvtCy?= <Ivan.Petrov@example.org>\r\n" +
+	"To: hr+" + testVacancyID + "@example.com\r\n" +
+	"Subject: Resume\r\n" +
+	"Message-ID: <resume-1@example.org>\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=sep\r\n" +
+	"\r\n" +
+	"--sep\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"\r\n" +
+	"Добрый день, направляю резюме\r\n" +
+	"--sep\r\n" +
+	"Content-Type: image/png\r\n" +
+	"Content-Disposition: attachment; filename=photo.png\r\n" +
+	"\r\n" +
+	"png\r\n" +
+	"--sep\r\n" +
+	"Content-Type: application/octet-stream\r\n" +
+	"Content-Disposition: attachment; filename=cv.pdf\r\n" +
+	"\r\n" +
+	"pdf\r\n" +
+	"--sep--\r\n"
+
+func startTestServer(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := server.New(memory.New())
+	s.AllowInsecureAuth = true
+	go s.Serve(listener)
+	t.Cleanup(func() { s.Close() })
+	return listener.Addr().String()
+}
+
+func appendMessage(t *testing.T, addr, body string) {
+	c, err := client.Dial(addr)
+	require.NoError(t, err)
+	defer c.Logout()
+	require.NoError(t, c.Login("username", "password"))
+	require.NoError(t, c.Append("INBOX", nil, time.Now(), bytes.NewBufferString(body)))
+}
+
+func TestFetchUnseen(t *testing.T) {
+	addr := startTestServer(t)
+	appendMessage(t, addr, testMessage)
+	cfg := MailboxConfig{
+		Addr:     addr,
+		Login:    "username",
+		Password: "password",
+	}
+
+	t.Run(`письмо разбирается и помечается прочитанным`, func(t *testing.T) {
+		list := []Message{}
+		processed, err := FetchUnseen(cfg, func(body io.Reader) error {
+			msg, err := ParseMessage(body)
+			if err != nil {
+				return err
+			}
+			list = append(list, *msg)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, processed)
+		require.Len(t, list, 1)
+
+		msg := list[0]
+		require.Equal(t, "resume-1@example.org", msg.MessageID)
+		require.Equal(t, "ivan.petrov@example.org", msg.From)
+		require.Equal(t, "Иван Петров", msg.FromName)
+		require.Equal(t, "Добрый день, направляю резюме", msg.Text)
+		require.Equal(t, []string{testVacancyID}, msg.VacancyIDs())
+		require.Len(t, msg.Attachments, 2)
+		require.Equal(t, 1, msg.ResumeIndex())
+		require.Equal(t, "application/pdf", msg.Attachments[1].ContentType)
+
+		processed, err = FetchUnseen(cfg, func(body io.Reader) error { return nil })
+		require.NoError(t, err)
+		require.Equal(t, 0, processed)
+	})
+
+	t.Run(`письмо с ошибкой обработки остается непрочитанным`, func(t *testing.T) {
+		appendMessage(t, addr, strings.Replace(testMessage, "resume-1", "resume-2", 1))
+		processed, err := FetchUnseen(cfg, func(body io.Reader) error { return io.ErrUnexpectedEOF })
+		require.NoError(t, err)
+		require.Equal(t, 0, processed)
+
+		processed, err = FetchUnseen(cfg, func(body io.Reader) error { return nil })
+		require.NoError(t, err)
+		require.Equal(t, 1, processed)
+	})
+}
+
+func TestVacancyIDs(t *testing.T) {
+	t.Run(`идентификатор вакансии в теме письма`, func(t *testing.T) {
+		msg := Message{
+			Subject: "Отклик на вакансию [" + strings.ToUpper(testVacancyID) + "]",
+			To:      []string{"hr@example.com"},
+		}
+		require.Equal(t, []string{testVacancyID}, msg.VacancyIDs())
+	})
+	t.Run(`письмо без идентификатора вакансии`, func(t *testing.T) {
+		msg := Message{
+			Subject: "Резюме",
+			To:      []string{"hr+jobs@example.com"},
+		}
+		require.Empty(t, msg.VacancyIDs())
+	})
+}
@@ -0,0 +1,157 @@
+package emailinbox
+
+import (
+	"io"
+	"mime"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	_ "github.com/emersion/go-message/charset" // поддержка кодировок koi8-r, windows-1251 и т.п.
+	"github.com/emersion/go-message/mail"
+	"github.com/pkg/errors"
+)
+
+// Message - входящее письмо
+type Message struct {
+	MessageID   string
+	InReplyTo   []string
+	From        string   // емайл отправителя
+	FromName    string   // имя отправителя
+	To          []string // емайлы получателей (To, Cc, Delivered-To)
+	Subject     string
+	Text        string
+	Attachments []Attachment
+}
+
+type Attachment struct {
+	FileName    string
+	ContentType string
+	Body        []byte
+}
+
+const maxAttachmentSize = 20 * 1024 * 1024
+
+var (
+	uuidRegexp = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+	tagsRegexp = regexp.MustCompile(`<[^>]*>`)
+
+	resumeExtensions = []string{".pdf", ".doc", ".docx", ".rtf", ".odt", ".txt"}
+)
+
+// ParseMessage разбор письма в формате RFC 5322
+func ParseMessage(r io.Reader) (*Message, error) {
+	mr, err := mail.CreateReader(r)
+	if err != nil && mr == nil {
+		return nil, errors.Wrap(err, "ошибка чтения письма")
+	}
+	defer mr.Close()
+
+	result := Message{
+		Attachments: []Attachment{},
+	}
+	result.MessageID, _ = mr.Header.MessageID()
+	result.InReplyTo, _ = mr.Header.MsgIDList("In-Reply-To")
+	result.Subject, _ = mr.Header.Subject()
+	result.Subject = strings.TrimSpace(result.Subject)
+
+	from, _ := mr.Header.AddressList("Reply-To")
+	if len(from) == 0 {
+		from, _ = mr.Header.AddressList("From")
+	}
+	if len(from) != 0 {
+		result.From = strings.ToLower(from[0].Address)
+		result.FromName = strings.TrimSpace(from[0].Name)
+	}
+	for _, key := range []string{"To", "Cc", "Delivered-To"} {
+		list, _ := mr.Header.AddressList(key)
+		for _, address := range list {
+			result.To = append(result.To, strings.ToLower(address.Address))
+		}
+	}
+
+	htmlText := ""
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "ошибка чтения части письма")
+		}
+		switch h := part.Header.(type) {
+		case *mail.InlineHeader:
+			contentType, _, _ := h.ContentType()
+			body, err := io.ReadAll(part.Body)
+			if err != nil {
+				return nil, errors.Wrap(err, "ошибка чтения текста письма")
+			}
+			if contentType == "text/plain" && result.Text == "" {
+				result.Text = strings.TrimSpace(string(body))
+			} else if contentType == "text/html" && htmlText == "" {
+				htmlText = strings.TrimSpace(tagsRegexp.ReplaceAllString(string(body), " "))
+			}
+		case *mail.AttachmentHeader:
+			fileName, _ := h.Filename()
+			contentType, _, _ := h.ContentType()
+			if fileName == "" {
+				continue
+			}
+			body, err := io.ReadAll(io.LimitReader(part.Body, maxAttachmentSize+1))
+			if err != nil {
+				return nil, errors.Wrap(err, "ошибка чтения вложения")
+			}
+			if len(body) > maxAttachmentSize {
+				continue
+			}
+			if contentType == "" || contentType == "application/octet-stream" {
+				if byExt := mime.TypeByExtension(filepath.Ext(fileName)); byExt != "" {
+					contentType = byExt
+				}
+			}
+			result.Attachments = append(result.Attachments, Attachment{
+				FileName:    fileName,
+				ContentType: contentType,
+				Body:        body,
+			})
+		}
+	}
+	if result.Text == "" {
+		result.Text = htmlText
+	}
+	return &result, nil
+}
+
+// VacancyIDs идентификаторы вакансий, указанные в теме письма или в адресе получателя (hr+<vacancy_id>@example.com)
+func (m Message) VacancyIDs() []string {
+	result := []string{}
+	for _, id := range uuidRegexp.FindAllString(m.Subject, -1) {
+		result = appendUnique(result, strings.ToLower(id))
+	}
+	for _, address := range m.To {
+		local, _, ok := strings.Cut(address, "@")
+		if !ok {
+			continue
+		}
+		_, tag, ok := strings.Cut(local, "+")
+		if ok && uuidRegexp.MatchString(tag) {
+			result = appendUnique(result, uuidRegexp.FindString(tag))
+		}
+	}
+	return result
+}
+
+// ResumeIndex индекс вложения, которое сохраняется как резюме, -1 если подходящего вложения нет
+func (m Message) ResumeIndex() int {
+	return slices.IndexFunc(m.Attachments, func(a Attachment) bool {
+		return slices.Contains(resumeExtensions, strings.ToLower(filepath.Ext(a.FileName)))
+	})
+}
+
+func appendUnique(list []string, value string) []string {
+	if slices.Contains(list, value) {
+		return list
+	}
+	return append(list, value)
+}
@@ -0,0 +1,123 @@
+package emailinboxworker
+
+import (
+	"context"
+	"hr-tools-backend/db"
+	emailinbox "hr-tools-backend/lib/email-inbox"
+	spacesettingsstore "hr-tools-backend/lib/space/settings/store"
+	spacestore "hr-tools-backend/lib/space/store"
+	baseworker "hr-tools-backend/lib/utils/base-worker"
+	"hr-tools-backend/lib/utils/helpers"
+	"hr-tools-backend/models"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Задача получения откликов кандидатов из почтовых ящиков спейсов
+func StartWorker(ctx context.Context) {
+	i := &impl{
+		BaseImpl:      *baseworker.NewInstance("EmailInboxWorker", 20*time.Second, 5*time.Minute),
+		spaceStore:    spacestore.NewInstance(db.DB),
+		settingsStore: spacesettingsstore.NewInstance(db.DB),
+	}
+	go i.Run(ctx, i.handle)
+}
+
+type impl struct {
+	baseworker.BaseImpl
+	spaceStore    spacestore.Provider
+	settingsStore spacesettingsstore.Provider
+}
+
+func (i impl) handle(ctx context.Context) {
+	logger := i.GetLogger()
+	ids, err := i.spaceStore.GetActiveIds()
+	if err != nil {
+		logger.WithError(err).Error("ошибка получения списка спейсов")
+		return
+	}
+	for _, spaceID := range ids {
+		if helpers.IsContextDone(ctx) {
+			break
+		}
+		cfg, err := i.getMailboxConfig(spaceID)
+		if err != nil {
+			logger.WithError(err).
+				WithField("space_id", spaceID).
+				Error("ошибка получения настроек почты для откликов")
+			continue
+		}
+		if cfg == nil {
+			continue
+		}
+		processed, err := emailinbox.FetchUnseen(*cfg, func(body io.Reader) error {
+			return i.handleMessage(ctx, spaceID, body)
+		})
+		if err != nil {
+			logger.WithError(err).
+				WithField("space_id", spaceID).
+				Error("ошибка получения писем из почты для откликов")
+		}
+		if processed != 0 {
+			logger.
+				WithField("space_id", spaceID).
+				Infof("обработано писем: %v", processed)
+		}
+	}
+}
+
+func (i impl) handleMessage(ctx context.Context, spaceID string, body io.Reader) error {
+	logger := i.GetLogger().
+		WithField("space_id", spaceID)
+	msg, err := emailinbox.ParseMessage(body)
+	if err != nil {
+		// письмо не удастся разобрать и при повторной попытке, помечаем прочитанным
+		logger.WithError(err).Warn("ошибка разбора письма")
+		return nil
+	}
+	err = emailinbox.Instance.HandleMessage(ctx, spaceID, *msg)
+	if err != nil {
+		logger.WithError(err).
+			WithField("message_id", msg.MessageID).
+			Error("ошибка обработки письма")
+		return err
+	}
+	return nil
+}
+
+// getMailboxConfig настройки почтового ящика спейса, nil если почта для откликов не настроена
+func (i impl) getMailboxConfig(spaceID string) (*emailinbox.MailboxConfig, error) {
+	addr, err := i.settingsStore.GetValueByCode(spaceID, models.ImapHostSetting)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения адреса IMAP сервера")
+	}
+	if addr == "" {
+		return nil, nil
+	}
+	login, err := i.settingsStore.GetValueByCode(spaceID, models.ImapLoginSetting)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения логина почты")
+	}
+	password, err := i.settingsStore.GetValueByCode(spaceID, models.ImapPasswordSetting)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения пароля почты")
+	}
+	mailbox, err := i.settingsStore.GetValueByCode(spaceID, models.ImapMailboxSetting)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения папки почты")
+	}
+	useTLS, err := i.settingsStore.GetValueByCode(spaceID, models.ImapTLSSetting)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения настройки TLS")
+	}
+	return &emailinbox.MailboxConfig{
+		Addr:     addr,
+		Login:    login,
+		Password: password,
+		Mailbox:  mailbox,
+		TLS:      strings.ToLower(useTLS) != "false",
+	}, nil
+}
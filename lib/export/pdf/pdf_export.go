@@ -17,6 +17,16 @@ func GenerateOffer(pdfOfferTemplate string, tplData models.TemplateData) (pdfFil
 		}
 	}()
 	pdf := fpdf.New("P", "mm", "A4", "static/font/")
+	if tplData.OfferNumber != "" {
+		pdf.SetTitle(fmt.Sprintf("Оффер № %v", tplData.OfferNumber), true)
+		pdf.SetSubject(tplData.VacancyName, true)
+		pdf.SetAuthor(tplData.CompanyName, true)
+		pdf.SetFooterFunc(func() {
+			pdf.SetY(-15)
+			pdf.SetFont("Arial", "I", 8)
+			pdf.CellFormat(0, 10, fmt.Sprintf("Оффер № %v, стр. %v", tplData.OfferNumber, pdf.PageNo()), "", 0, "C", false, 0, "")
+		})
+	}
 	pdf.AddPage()
 	pdf.AddUTF8Font("Arial", "", "Arial.ttf")
 	pdf.AddUTF8Font("Arial", "B", "Arial Bold.ttf")
@@ -85,6 +95,15 @@ func GenerateOffer(pdfOfferTemplate string, tplData models.TemplateData) (pdfFil
 		pdf.Image(tplData.Files.Sign.FileName, pageX-50, posY, 30, 0, false, "", 0, "")
 	}
 
+	// блок для подписи кандидата
+	if tplData.OfferNumber != "" {
+		pdf.SetY(posY + 40)
+		html = pdf.HTMLBasicNew()
+		html.Write(lineHt, "С условиями предложения ознакомлен(а) и согласен(на):<br><br>"+
+			fmt.Sprintf("_______________________ / %v /<br><br>", tplData.ApplicantFIO)+
+			"Дата: «____» _______________ 20___ г.")
+	}
+
 	buf = new(bytes.Buffer)
 	err = pdf.Output(buf)
 	if err != nil {
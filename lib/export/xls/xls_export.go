@@ -7,6 +7,7 @@ import (
 	dbmodels "hr-tools-backend/models/db"
 	"math"
 	"strconv"
+	"strings"
 
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -14,7 +15,8 @@ import (
 )
 
 type Provider interface {
-	ExportApplicantList(list []dbmodels.ApplicantWithJob) (*bytes.Buffer, error)
+	// ExportApplicantList выгрузка кандидатов, fields - пользовательские поля в отдельных колонках, values - их значения для отображения в порядке list
+	ExportApplicantList(list []dbmodels.ApplicantWithJob, scorecards []dbmodels.Scorecard, fields []dbmodels.CustomField, values []map[string]string) (*bytes.Buffer, error)
 	ExportSource(data applicantapimodels.ApplicantSourceData) (*bytes.Buffer, error)
 }
 
@@ -26,9 +28,11 @@ func NewHandler() {
 
 type impl struct{}
 
-var applicantHeaders = []string{"ФИО", "Контакты", "Вакансия", "Должность", "Источник кандидата", "Дата отбора", "Желаемая ЗП", "Дата выхода", "Статус"}
+var applicantHeaders = []string{"ФИО", "Контакты", "Вакансия", "Должность", "Источник кандидата", "Дата отбора", "Желаемая ЗП", "Дата выхода", "Статус", "Оценка интервьюеров"}
 
-func (i impl) ExportApplicantList(list []dbmodels.ApplicantWithJob) (*bytes.Buffer, error) {
+var scorecardHeaders = []string{"Кандидат", "Вакансия", "Этап", "Интервьюер", "Средняя оценка", "Рекомендация", "Оценки", "Комментарий", "Дата"}
+
+func (i impl) ExportApplicantList(list []dbmodels.ApplicantWithJob, scorecards []dbmodels.Scorecard, fields []dbmodels.CustomField, values []map[string]string) (*bytes.Buffer, error) {
 	f := excelize.NewFile()
 	defer func() {
 		if err := f.Close(); err != nil {
@@ -37,17 +41,35 @@ func (i impl) ExportApplicantList(list []dbmodels.ApplicantWithJob) (*bytes.Buff
 	}()
 	sheet := "Sheet1"
 	row := 0
-	row, err := writeHeader(f, sheet, row, applicantHeaders)
+	headers := append([]string{}, applicantHeaders...)
+	for _, field := range fields {
+		headers = append(headers, field.Name)
+	}
+	row, err := writeHeader(f, sheet, row, headers)
 	if err != nil {
 		return nil, errors.Wrap(err, "ошибка формирования заголовка в xlsx")
 	}
 	if len(list) != 0 {
-		row, err = writeApplicantData(f, sheet, list, row)
+		row, err = writeApplicantData(f, sheet, list, scorecards, fields, values, row)
 		if err != nil {
 			return nil, errors.Wrap(err, "ошибка формирования таблицы с данными в xlsx")
 		}
 	}
 	f.SetSheetName(sheet, "Кандидаты")
+	if len(scorecards) != 0 {
+		scorecardSheet := "Оценочные карты"
+		if _, err = f.NewSheet(scorecardSheet); err != nil {
+			return nil, errors.Wrap(err, "ошибка создания листа с оценочными картами в xlsx")
+		}
+		row, err = writeHeader(f, scorecardSheet, 0, scorecardHeaders)
+		if err != nil {
+			return nil, errors.Wrap(err, "ошибка формирования заголовка оценочных карт в xlsx")
+		}
+		_, err = writeScorecardData(f, scorecardSheet, list, scorecards, row)
+		if err != nil {
+			return nil, errors.Wrap(err, "ошибка формирования таблицы с оценочными картами в xlsx")
+		}
+	}
 	return f.WriteToBuffer()
 }
 
@@ -66,11 +88,13 @@ func (i impl) ExportSource(source applicantapimodels.ApplicantSourceData) (*byte
 	return f.WriteToBuffer()
 }
 
-func writeApplicantData(f *excelize.File, sheet string, list []dbmodels.ApplicantWithJob, row int) (int, error) {
-	if err := applyDataCellStyle(f, sheet, 1, row+1, len(applicantHeaders), len(list)+1); err != nil {
+func writeApplicantData(f *excelize.File, sheet string, list []dbmodels.ApplicantWithJob, scorecards []dbmodels.Scorecard,
+	fields []dbmodels.CustomField, values []map[string]string, row int) (int, error) {
+	if err := applyDataCellStyle(f, sheet, 1, row+1, len(applicantHeaders)+len(fields), len(list)+1); err != nil {
 		return row, err
 	}
-	for _, item := range list {
+	averages := getScorecardAverages(scorecards)
+	for k, item := range list {
 		row++
 		// "ФИО"
 		col := 1
@@ -131,10 +155,131 @@ func writeApplicantData(f *excelize.File, sheet string, list []dbmodels.Applican
 		if err := writeColumn(f, sheet, col, row, item.Status); err != nil {
 			return row, err
 		}
+
+		// "Оценка интервьюеров"
+		col++
+		if average, ok := averages[item.ID]; ok {
+			if err := writeColumn(f, sheet, col, row, average); err != nil {
+				return row, err
+			}
+		}
+
+		// пользовательские поля
+		for _, field := range fields {
+			col++
+			if k >= len(values) || values[k][field.Code] == "" {
+				continue
+			}
+			if err := writeColumn(f, sheet, col, row, values[k][field.Code]); err != nil {
+				return row, err
+			}
+		}
 	}
 	return row, nil
 }
 
+func writeScorecardData(f *excelize.File, sheet string, list []dbmodels.ApplicantWithJob, scorecards []dbmodels.Scorecard, row int) (int, error) {
+	if err := applyDataCellStyle(f, sheet, 1, row+1, len(scorecardHeaders), len(scorecards)+1); err != nil {
+		return row, err
+	}
+	applicants := make(map[string]dbmodels.ApplicantWithJob, len(list))
+	for _, item := range list {
+		applicants[item.ID] = item
+	}
+	for _, item := range scorecards {
+		row++
+		applicant := applicants[item.ApplicantID]
+		// "Кандидат"
+		col := 1
+		if err := writeColumn(f, sheet, col, row, applicant.GetFIO()); err != nil {
+			return row, err
+		}
+
+		// "Вакансия"
+		col++
+		if applicant.Vacancy != nil {
+			if err := writeColumn(f, sheet, col, row, applicant.Vacancy.VacancyName); err != nil {
+				return row, err
+			}
+		}
+
+		// "Этап"
+		col++
+		if item.SelectionStage != nil {
+			if err := writeColumn(f, sheet, col, row, item.SelectionStage.Name); err != nil {
+				return row, err
+			}
+		}
+
+		// "Интервьюер"
+		col++
+		if item.SpaceUser != nil {
+			if err := writeColumn(f, sheet, col, row, item.SpaceUser.GetFullName()); err != nil {
+				return row, err
+			}
+		}
+
+		// "Средняя оценка"
+		col++
+		if err := writeColumn(f, sheet, col, row, math.Round(item.Ratings.Average()*100)/100); err != nil {
+			return row, err
+		}
+
+		// "Рекомендация"
+		col++
+		if err := writeColumn(f, sheet, col, row, dbmodels.RecommendationNames[item.Recommendation]); err != nil {
+			return row, err
+		}
+
+		// "Оценки"
+		col++
+		ratings := make([]string, 0, len(item.Ratings))
+		for _, rating := range item.Ratings {
+			if rating.Rating == 0 {
+				continue
+			}
+			ratings = append(ratings, fmt.Sprintf("%v: %v", rating.Name, rating.Rating))
+		}
+		if err := writeColumn(f, sheet, col, row, strings.Join(ratings, "\r")); err != nil {
+			return row, err
+		}
+
+		// "Комментарий"
+		col++
+		if err := writeColumn(f, sheet, col, row, item.Comment); err != nil {
+			return row, err
+		}
+
+		// "Дата"
+		col++
+		if item.SubmittedAt != nil {
+			if err := writeColumn(f, sheet, col, row, item.SubmittedAt.Format("02.01.2006")); err != nil {
+				return row, err
+			}
+		}
+	}
+	return row, nil
+}
+
+// getScorecardAverages средняя оценка кандидатов по отправленным оценочным картам
+func getScorecardAverages(scorecards []dbmodels.Scorecard) map[string]float64 {
+	sums := map[string]float64{}
+	counts := map[string]int{}
+	for _, item := range scorecards {
+		average := item.Ratings.Average()
+		if average == 0 {
+			continue
+		}
+		sums[item.ApplicantID] += average
+		counts[item.ApplicantID]++
+	}
+	result := make(map[string]float64, len(sums))
+	for applicantID, sum := range sums {
+		result[applicantID] = math.Round(sum/float64(counts[applicantID])*100) / 100
+	}
+	return result
+}
+
 func putSoureChart(f *excelize.File, sheet string, row int, chartName string, sourceData []applicantapimodels.SourceItem, sourceTotal int) (int, error) {
 	// Заголовок
 	row++
@@ -17,6 +17,7 @@ import (
 	"hr-tools-backend/lib/utils/helpers"
 	initchecker "hr-tools-backend/lib/utils/init-checker"
 	vacancystore "hr-tools-backend/lib/vacancy/store"
+	webhookevent "hr-tools-backend/lib/webhook/event"
 	"hr-tools-backend/models"
 	avitoapimodels "hr-tools-backend/models/api/avito"
 	negotiationapimodels "hr-tools-backend/models/api/negotiation"
@@ -55,6 +56,20 @@ func NewHandler() {
 		"applicantHistory", instance.applicantHistory,
 	)
 	Instance = instance
+	externalservices.RegisterConnector(externalservices.Connector{
+		Code:   ConnectorCode,
+		Name:   "Avito",
+		Source: ApplicantSourceAvito,
+		Capabilities: []externalservices.Capability{
+			externalservices.CapabilityOAuth,
+			externalservices.CapabilityPublish,
+			externalservices.CapabilityClose,
+			externalservices.CapabilityNegotiations,
+			externalservices.CapabilityChat,
+		},
+		ChatIDRequired: true,
+		Provider:       instance,
+	})
 }
 
 type impl struct {
@@ -71,9 +86,11 @@ type impl struct {
 }
 
 const (
-	TokenCode              = "AVITO_TOKEN"
-	LastApplicationDateTpl = "AVITO_LAST_APPL_DATE:%v"
-	AvitoUserID            = "AVITO_USERID"
+	ConnectorCode                                 = "avito"
+	ApplicantSourceAvito   models.ApplicantSource = "Avito"
+	TokenCode                                     = "AVITO_TOKEN"
+	LastApplicationDateTpl                        = "AVITO_LAST_APPL_DATE:%v"
+	AvitoUserID                                   = "AVITO_USERID"
 )
 
 func (i *impl) getLogger(spaceID, vacancyID string) *log.Entry {
@@ -297,6 +314,14 @@ func (i *impl) VacancyAttach(ctx context.Context, spaceID, vacancyID string, ext
 	return "", nil
 }
 
+func (i *impl) CanClose(rec dbmodels.Vacancy) bool {
+	return rec.AvitoID != 0 && (rec.AvitoStatus == models.VacancyPubStatusModeration || rec.AvitoStatus != models.VacancyPubStatusPublished)
+}
+
+func (i *impl) GetVacancyUri(rec dbmodels.Vacancy) string {
+	return rec.AvitoUri
+}
+
 func (i *impl) GetVacancyInfo(ctx context.Context, spaceID, vacancyID string) (*vacancyapimodels.ExtVacancyInfo, error) {
 	rec, err := i.vacancyStore.GetByID(spaceID, vacancyID)
 	if err != nil {
@@ -346,7 +371,7 @@ func (i *impl) HandleNegotiations(ctx context.Context, data dbmodels.Vacancy) er
 	ids := []string{}
 	for _, applie := range idsResp.Applies {
 		negotiationID := applie.ID
-		found, err := i.applicantStore.IsExistNegotiationID(data.SpaceID, negotiationID, models.ApplicantSourceAvito)
+		found, err := i.applicantStore.IsExistNegotiationID(data.SpaceID, negotiationID, ApplicantSourceAvito)
 		if err != nil {
 			logger.WithError(err).Error("не удалось проверить наличие отклика")
 			continue
@@ -466,7 +491,7 @@ func (i *impl) storeApplicant(resume *avitoapimodels.Resume, apply avitoapimodel
 		ChatID:          apply.Contacts.Chat.Value,
 		ExtApplicantID:  apply.Applicant.ID,
 		ResumeID:        strconv.Itoa(resume.ID),
-		Source:          models.ApplicantSourceAvito,
+		Source:          ApplicantSourceAvito,
 		NegotiationDate: time.Now(),
 		Status:          models.ApplicantStatusNegotiation,
 		FirstName:       apply.Applicant.Data.FullName.FirstName,
@@ -516,11 +541,18 @@ func (i *impl) storeApplicant(resume *avitoapimodels.Resume, apply avitoapimodel
 	applicantID, err := i.applicantStore.Create(applicantData)
 	if err != nil {
 		logger.WithError(err).Error("ошибка сохранения кандидата по отклику")
+		return
 	}
 	changes := applicanthistoryhandler.GetCreateChanges("Кандидат добавлен с работного сайта на вакансию", applicantData)
 	i.applicantHistory.Save(applicantData.SpaceID, applicantID, applicantData.VacancyID, "", dbmodels.HistoryTypeNegotiation, changes)
-
-	notification := models.GetPushApplicantNegotiation(data.VacancyName, applicantData.GetFIO())
+	webhookevent.Publish(webhookevent.Event{
+		Type:        dbmodels.WebhookEventApplicantCreated,
+		SpaceID:     applicantData.SpaceID,
+		ApplicantID: applicantID,
+		VacancyID:   applicantData.VacancyID,
+	})
+
+	notification := models.GetPushApplicantNegotiation(data.VacancyName, applicantData.GetFIO()).WithEntity(applicantID)
 	go i.sendNotification(data, notification)
 }
 
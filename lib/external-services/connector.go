@@ -0,0 +1,121 @@
+package externalservices
+
+import (
+	"fmt"
+	"hr-tools-backend/models"
+	dbmodels "hr-tools-backend/models/db"
+	"slices"
+	"sync"
+)
+
+type Capability string
+
+const (
+	CapabilityOAuth        Capability = "oauth"        // подключение через OAuth
+	CapabilityPublish      Capability = "publish"      // публикация вакансий и проверка статусов публикации
+	CapabilityClose        Capability = "close"        // снятие вакансий с публикации
+	CapabilityNegotiations Capability = "negotiations" // получение откликов
+	CapabilityChat         Capability = "chat"         // переписка с кандидатом
+)
+
+// Connector - описание коннектора к работному сайту
+type Connector struct {
+	Code           string                 // код коннектора, используется в маршрутах (hh, avito)
+	Name           string                 // наименование работного сайта
+	Source         models.ApplicantSource // источник кандидатов, полученных через коннектор
+	Capabilities   []Capability           // поддерживаемые возможности
+	ChatIDRequired bool                   // для переписки необходим идентификатор чата во внешней системе
+	Provider       JobSiteProvider
+}
+
+func (c Connector) Has(capability Capability) bool {
+	return slices.Contains(c.Capabilities, capability)
+}
+
+// PublicationInfo - сведения о размещении вакансии на работном сайте
+type PublicationInfo interface {
+	CanClose(rec dbmodels.Vacancy) bool // вакансия размещена и может быть снята с публикации
+	GetVacancyUri(rec dbmodels.Vacancy) string
+}
+
+var (
+	connectorsMx    sync.RWMutex
+	connectors      = map[string]Connector{}
+	connectorsOrder []string
+)
+
+// RegisterConnector регистрирует коннектор, вызывается из NewHandler коннектора
+func RegisterConnector(connector Connector) {
+	if connector.Code == "" {
+		panic("RegisterConnector: не указан код коннектора")
+	}
+	if connector.Provider == nil {
+		panic(fmt.Sprintf("RegisterConnector: не указан обработчик коннектора %v", connector.Code))
+	}
+	connectorsMx.Lock()
+	defer connectorsMx.Unlock()
+	if _, ok := connectors[connector.Code]; !ok {
+		connectorsOrder = append(connectorsOrder, connector.Code)
+	}
+	connectors[connector.Code] = connector
+}
+
+func GetConnector(code string) (Connector, bool) {
+	connectorsMx.RLock()
+	defer connectorsMx.RUnlock()
+	connector, ok := connectors[code]
+	return connector, ok
+}
+
+func GetConnectorBySource(source models.ApplicantSource) (Connector, bool) {
+	connectorsMx.RLock()
+	defer connectorsMx.RUnlock()
+	for _, code := range connectorsOrder {
+		if connectors[code].Source == source {
+			return connectors[code], true
+		}
+	}
+	return Connector{}, false
+}
+
+// ListConnectors список коннекторов в порядке регистрации, поддерживающих все указанные возможности
+func ListConnectors(capabilities ...Capability) []Connector {
+	connectorsMx.RLock()
+	defer connectorsMx.RUnlock()
+	result := make([]Connector, 0, len(connectorsOrder))
+	for _, code := range connectorsOrder {
+		connector := connectors[code]
+		supported := true
+		for _, capability := range capabilities {
+			if !connector.Has(capability) {
+				supported = false
+				break
+			}
+		}
+		if supported {
+			result = append(result, connector)
+		}
+	}
+	return result
+}
+
+// ConnectorSources источники кандидатов коннекторов, поддерживающих все указанные возможности
+func ConnectorSources(capabilities ...Capability) []models.ApplicantSource {
+	list := ListConnectors(capabilities...)
+	result := make([]models.ApplicantSource, 0, len(list))
+	for _, connector := range list {
+		result = append(result, connector.Source)
+	}
+	return result
+}
+
+// ApplicantSources все источники кандидатов: общие источники и источники зарегистрированных коннекторов
+func ApplicantSources() []models.ApplicantSource {
+	result := slices.Clone(models.ApplicantSources)
+	for _, source := range ConnectorSources() {
+		if !slices.Contains(result, source) {
+			result = append(result, source)
+		}
+	}
+	return result
+}
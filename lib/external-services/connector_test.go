@@ -0,0 +1,64 @@
+package externalservices
+
+import (
+	"hr-tools-backend/models"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnectorRegistry(t *testing.T) {
+	var provider JobSiteProvider = struct{ JobSiteProvider }{}
+	RegisterConnector(Connector{
+		Code:         "test_full",
+		Source:       models.ApplicantSource("test_full"),
+		Capabilities: []Capability{CapabilityOAuth, CapabilityNegotiations, CapabilityChat},
+		Provider:     provider,
+	})
+	RegisterConnector(Connector{
+		Code:         "test_publish",
+		Source:       models.ApplicantSource("test_publish"),
+		Capabilities: []Capability{CapabilityPublish},
+		Provider:     provider,
+	})
+
+	t.Run(`GetConnector check`, func(t *testing.T) {
+		connector, ok := GetConnector("test_full")
+		require.True(t, ok)
+		require.True(t, connector.Has(CapabilityChat))
+		require.False(t, connector.Has(CapabilityPublish))
+
+		_, ok = GetConnector("unknown")
+		require.False(t, ok)
+	})
+
+	t.Run(`GetConnectorBySource check`, func(t *testing.T) {
+		connector, ok := GetConnectorBySource("test_publish")
+		require.True(t, ok)
+		require.Equal(t, "test_publish", connector.Code)
+
+		_, ok = GetConnectorBySource(models.ApplicantSourceManual)
+		require.False(t, ok)
+	})
+
+	t.Run(`ListConnectors check`, func(t *testing.T) {
+		require.Len(t, ListConnectors(), 2)
+		list := ListConnectors(CapabilityNegotiations, CapabilityChat)
+		require.Len(t, list, 1)
+		require.Equal(t, "test_full", list[0].Code)
+		require.Empty(t, ListConnectors(CapabilityClose))
+		require.Equal(t, []models.ApplicantSource{"test_publish"}, ConnectorSources(CapabilityPublish))
+	})
+
+	t.Run(`ApplicantSources check`, func(t *testing.T) {
+		sources := ApplicantSources()
+		require.Equal(t, models.ApplicantSources, sources[:len(models.ApplicantSources)])
+		require.Contains(t, sources, models.ApplicantSource("test_full"))
+		require.Contains(t, sources, models.ApplicantSource("test_publish"))
+	})
+
+	t.Run(`RegisterConnector validation check`, func(t *testing.T) {
+		require.Panics(t, func() { RegisterConnector(Connector{Provider: provider}) })
+		require.Panics(t, func() { RegisterConnector(Connector{Code: "test_nil"}) })
+	})
+}
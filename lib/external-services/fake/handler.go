@@ -0,0 +1,408 @@
+package fakehandler
+
+import (
+	"context"
+	"fmt"
+	"hr-tools-backend/db"
+	applicanthistoryhandler "hr-tools-backend/lib/applicant-history"
+	applicantstore "hr-tools-backend/lib/applicant/store"
+	externalservices "hr-tools-backend/lib/external-services"
+	pushhandler "hr-tools-backend/lib/space/push/handler"
+	"hr-tools-backend/lib/utils/helpers"
+	initchecker "hr-tools-backend/lib/utils/init-checker"
+	vacancystore "hr-tools-backend/lib/vacancy/store"
+	webhookevent "hr-tools-backend/lib/webhook/event"
+	"hr-tools-backend/models"
+	negotiationapimodels "hr-tools-backend/models/api/negotiation"
+	vacancyapimodels "hr-tools-backend/models/api/vacancy"
+	dbmodels "hr-tools-backend/models/db"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// Тестовый работный сайт, данные которого хранятся в файлах.
+// Используется для разработки и тестирования без обращения к реальным работным сайтам.
+
+var Instance externalservices.JobSiteProvider
+
+const (
+	ConnectorCode                               = "fake"
+	ConnectorName                               = "Тестовый работный сайт"
+	ApplicantSourceFake  models.ApplicantSource = "Тестовый работный сайт"
+	VacancyUriTpl                               = "https://fake-job-site.local/vacancy/%v"
+	NotConnectedMsg                             = "Тестовый работный сайт не подключен"
+	NotPublishedMsg                             = "вакансия еще не опубликованна"
+	DraftNotSupportedMsg                        = "черновики не поддерживаются"
+	connectCode                                 = "fake-code"
+)
+
+func NewHandler(dir, callbackUri string) {
+	instance := &impl{
+		storage:          storage{dir: dir},
+		callbackUri:      callbackUri,
+		vacancyStore:     vacancystore.NewInstance(db.DB),
+		applicantStore:   applicantstore.NewInstance(db.DB),
+		applicantHistory: applicanthistoryhandler.Instance,
+	}
+	initchecker.CheckInit(
+		"vacancyStore", instance.vacancyStore,
+		"applicantStore", instance.applicantStore,
+		"applicantHistory", instance.applicantHistory,
+	)
+	Instance = instance
+	externalservices.RegisterConnector(externalservices.Connector{
+		Code:   ConnectorCode,
+		Name:   ConnectorName,
+		Source: ApplicantSourceFake,
+		Capabilities: []externalservices.Capability{
+			externalservices.CapabilityOAuth,
+			externalservices.CapabilityPublish,
+			externalservices.CapabilityClose,
+			externalservices.CapabilityNegotiations,
+			externalservices.CapabilityChat,
+		},
+		Provider: instance,
+	})
+}
+
+type impl struct {
+	storage          storage
+	callbackUri      string
+	vacancyStore     vacancystore.Provider
+	applicantStore   applicantstore.Provider
+	applicantHistory applicanthistoryhandler.Provider
+}
+
+func (i *impl) getLogger(spaceID, vacancyID string) *log.Entry {
+	logger := log.WithField("integration", ConnectorName)
+	if spaceID != "" {
+		logger = logger.WithField("space_id", spaceID)
+	}
+	if vacancyID != "" {
+		logger = logger.WithField("vacancy_id", vacancyID)
+	}
+	return logger
+}
+
+func (i *impl) GetConnectUri(spaceID string) (uri string, err error) {
+	values := url.Values{}
+	values.Set("state", spaceID)
+	values.Set("code", connectCode)
+	return i.callbackUri + "?" + values.Encode(), nil
+}
+
+func (i *impl) RequestToken(spaceID, code string) {
+	err := i.storage.connect(spaceID, code)
+	if err != nil {
+		i.getLogger(spaceID, "").WithError(err).Error("ошибка сохранения токена")
+	}
+}
+
+func (i *impl) RemoveToken(spaceID string) (err error) {
+	return i.storage.disconnect(spaceID)
+}
+
+func (i *impl) CheckConnected(ctx context.Context, spaceID string) bool {
+	return i.storage.isConnected(spaceID)
+}
+
+func (i *impl) VacancyPublish(ctx context.Context, spaceID, vacancyID string) (hMsg string, err error) {
+	if !i.storage.isConnected(spaceID) {
+		return NotConnectedMsg, nil
+	}
+	rec, err := i.vacancyStore.GetByID(spaceID, vacancyID)
+	if err != nil {
+		return "", err
+	}
+	if rec == nil {
+		return "вакансия не найдена", nil
+	}
+	if models.VacancyStatusOpened != rec.Status {
+		return fmt.Sprintf("неподходящей статус вакансии %v, для публикации", rec.Status), nil
+	}
+	pub, err := i.storage.getPublication(spaceID, vacancyID)
+	if err != nil {
+		return "", err
+	}
+	if pub != nil && pub.Status != models.VacancyPubStatusNone && pub.Status != models.VacancyPubStatusClosed {
+		return "вакансия уже размещена", nil
+	}
+	return "", i.storage.setPublication(spaceID, Publication{
+		VacancyID: vacancyID,
+		Status:    models.VacancyPubStatusModeration,
+		Url:       fmt.Sprintf(VacancyUriTpl, vacancyID),
+	})
+}
+
+func (i *impl) VacancyDraft(ctx context.Context, spaceID, vacancyID string) (hMsg string, err error) {
+	return DraftNotSupportedMsg, nil
+}
+
+func (i *impl) VacancyUpdate(ctx context.Context, spaceID, vacancyID string) (hMsg string, err error) {
+	pub, hMsg, err := i.getPublication(spaceID, vacancyID)
+	if err != nil || hMsg != "" {
+		return hMsg, err
+	}
+	pub.Status = models.VacancyPubStatusModeration
+	return "", i.storage.setPublication(spaceID, *pub)
+}
+
+func (i *impl) VacancyClose(ctx context.Context, spaceID, vacancyID string) (hMsg string, err error) {
+	pub, hMsg, err := i.getPublication(spaceID, vacancyID)
+	if err != nil || hMsg != "" {
+		return hMsg, err
+	}
+	pub.Status = models.VacancyPubStatusClosed
+	return "", i.storage.setPublication(spaceID, *pub)
+}
+
+func (i *impl) VacancyDeleteDraft(ctx context.Context, spaceID, vacancyID string) (hMsg string, err error) {
+	return DraftNotSupportedMsg, nil
+}
+
+func (i *impl) VacancyAttach(ctx context.Context, spaceID, vacancyID string, extID string) (hMsg string, err error) {
+	if !i.storage.isConnected(spaceID) {
+		return NotConnectedMsg, nil
+	}
+	pub, err := i.storage.getPublication(spaceID, vacancyID)
+	if err != nil {
+		return "", err
+	}
+	if pub != nil {
+		return "ссылка на вакансию уже добавлена", nil
+	}
+	return "", i.storage.setPublication(spaceID, Publication{
+		VacancyID: vacancyID,
+		Status:    models.VacancyPubStatusPublished,
+		Url:       fmt.Sprintf(VacancyUriTpl, extID),
+	})
+}
+
+func (i *impl) GetVacancyInfo(ctx context.Context, spaceID, vacancyID string) (*vacancyapimodels.ExtVacancyInfo, error) {
+	pub, err := i.storage.getPublication(spaceID, vacancyID)
+	if err != nil {
+		return nil, err
+	}
+	result := vacancyapimodels.ExtVacancyInfo{
+		Status: models.VacancyPubStatusNone,
+	}
+	if pub == nil {
+		return &result, nil
+	}
+	result.Url = pub.Url
+	result.Status = pub.Status
+	result.Reason = pub.Reason
+	return &result, nil
+}
+
+func (i *impl) GetCheckList(ctx context.Context, spaceID string, status models.VacancyPubStatus) ([]dbmodels.Vacancy, error) {
+	list, err := i.storage.listPublications(spaceID, status)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]dbmodels.Vacancy, 0, len(list))
+	for _, pub := range list {
+		rec, err := i.vacancyStore.GetByID(spaceID, pub.VacancyID)
+		if err != nil {
+			return nil, err
+		}
+		if rec != nil {
+			result = append(result, *rec)
+		}
+	}
+	return result, nil
+}
+
+func (i *impl) CheckIsModerationDone(ctx context.Context, spaceID string, list []dbmodels.Vacancy) error {
+	// модерация на тестовом сайте проходит сразу
+	for _, rec := range list {
+		pub, err := i.storage.getPublication(spaceID, rec.ID)
+		if err != nil {
+			return err
+		}
+		if pub == nil || pub.Status != models.VacancyPubStatusModeration {
+			continue
+		}
+		pub.Status = models.VacancyPubStatusPublished
+		err = i.storage.setPublication(spaceID, *pub)
+		if err != nil {
+			return err
+		}
+		notification := models.GetPushVacancyPublished(rec.VacancyName, ConnectorName)
+		go i.sendNotification(rec, notification)
+	}
+	return nil
+}
+
+func (i *impl) CheckIsActivePublications(ctx context.Context, spaceID string, list []dbmodels.Vacancy) error {
+	return nil
+}
+
+func (i *impl) HandleNegotiations(ctx context.Context, data dbmodels.Vacancy) error {
+	list, err := i.storage.listNegotiations(data.SpaceID, data.ID)
+	if err != nil {
+		return err
+	}
+	for _, item := range list {
+		if helpers.IsContextDone(ctx) {
+			return nil
+		}
+		logger := i.getLogger(data.SpaceID, data.ID).WithField("negotiation_id", item.ID)
+		found, err := i.applicantStore.IsExistNegotiationID(data.SpaceID, item.ID, ApplicantSourceFake)
+		if err != nil {
+			logger.WithError(err).Error("не удалось проверить наличие отклика")
+			continue
+		}
+		if found {
+			continue
+		}
+		applicantData := dbmodels.Applicant{
+			BaseSpaceModel: dbmodels.BaseSpaceModel{
+				SpaceID: data.SpaceID,
+			},
+			VacancyID:       data.ID,
+			NegotiationID:   item.ID,
+			ResumeID:        item.ResumeID,
+			Source:          ApplicantSourceFake,
+			NegotiationDate: time.Now(),
+			Status:          models.ApplicantStatusNegotiation,
+			FirstName:       item.FirstName,
+			LastName:        item.LastName,
+			MiddleName:      item.MiddleName,
+			Email:           item.Email,
+			Phone:           item.Phone,
+			ResumeTitle:     item.ResumeTitle,
+			Salary:          item.Salary,
+			Address:         item.Address,
+			Params: dbmodels.ApplicantParams{
+				Employments:        []models.Employment{},
+				Schedules:          []models.Schedule{},
+				Languages:          []dbmodels.Language{},
+				DriverLicenseTypes: []models.DriverLicenseType{},
+			},
+		}
+		for _, stage := range data.SelectionStages {
+			if stage.Name == dbmodels.NegotiationStage {
+				applicantData.SelectionStageID = stage.ID
+				break
+			}
+		}
+		applicantID, err := i.applicantStore.Create(applicantData)
+		if err != nil {
+			logger.WithError(err).Error("ошибка сохранения кандидата по отклику")
+			continue
+		}
+		changes := applicanthistoryhandler.GetCreateChanges("Кандидат добавлен с работного сайта на вакансию", applicantData)
+		i.applicantHistory.Save(applicantData.SpaceID, applicantID, applicantData.VacancyID, "", dbmodels.HistoryTypeNegotiation, changes)
+		webhookevent.Publish(webhookevent.Event{
+			Type:        dbmodels.WebhookEventApplicantCreated,
+			SpaceID:     applicantData.SpaceID,
+			ApplicantID: applicantID,
+			VacancyID:   applicantData.VacancyID,
+		})
+
+		notification := models.GetPushApplicantNegotiation(data.VacancyName, applicantData.GetFIO()).WithEntity(applicantID)
+		go i.sendNotification(data, notification)
+	}
+	return nil
+}
+
+func (i *impl) SendMessage(ctx context.Context, data dbmodels.Applicant, msg string) error {
+	if !i.storage.isConnected(data.SpaceID) {
+		return errors.New(NotConnectedMsg)
+	}
+	return i.storage.addMessage(data.SpaceID, data.NegotiationID, Message{
+		ID:          uuid.NewString(),
+		Text:        msg,
+		SelfMessage: true,
+		CreatedAt:   time.Now(),
+	})
+}
+
+func (i *impl) GetMessages(ctx context.Context, user dbmodels.SpaceUser, data dbmodels.Applicant) ([]negotiationapimodels.MessageItem, error) {
+	list, err := i.storage.getMessages(data.SpaceID, data.NegotiationID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]negotiationapimodels.MessageItem, 0, len(list))
+	for _, item := range list {
+		msg := convertMessage(item, data)
+		if msg.SelfMessage {
+			msg.AuthorFullName = user.GetFullName()
+		}
+		result = append(result, msg)
+	}
+	return result, nil
+}
+
+func (i *impl) GetLastInMessage(ctx context.Context, data dbmodels.Applicant) (*negotiationapimodels.MessageItem, error) {
+	list, err := i.storage.getMessages(data.SpaceID, data.NegotiationID)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+	msg := convertMessage(list[len(list)-1], data)
+	return &msg, nil
+}
+
+func (i *impl) CanClose(rec dbmodels.Vacancy) bool {
+	pub, err := i.storage.getPublication(rec.SpaceID, rec.ID)
+	if err != nil {
+		i.getLogger(rec.SpaceID, rec.ID).WithError(err).Error("ошибка получения размещения вакансии")
+		return false
+	}
+	return pub != nil && pub.Status != models.VacancyPubStatusClosed
+}
+
+func (i *impl) GetVacancyUri(rec dbmodels.Vacancy) string {
+	pub, err := i.storage.getPublication(rec.SpaceID, rec.ID)
+	if err != nil || pub == nil {
+		return ""
+	}
+	return pub.Url
+}
+
+func (i *impl) getPublication(spaceID, vacancyID string) (pub *Publication, hMsg string, err error) {
+	if !i.storage.isConnected(spaceID) {
+		return nil, NotConnectedMsg, nil
+	}
+	pub, err = i.storage.getPublication(spaceID, vacancyID)
+	if err != nil {
+		return nil, "", err
+	}
+	if pub == nil {
+		return nil, NotPublishedMsg, nil
+	}
+	return pub, "", nil
+}
+
+func (i *impl) sendNotification(rec dbmodels.Vacancy, data models.NotificationData) {
+	//отправляем автору
+	pushhandler.Instance.SendNotification(rec.AuthorID, data)
+	for _, teamMember := range rec.VacancyTeam {
+		//отправляем команде
+		if rec.AuthorID == teamMember.UserID {
+			continue
+		}
+		pushhandler.Instance.SendNotification(teamMember.UserID, data)
+	}
+}
+
+func convertMessage(item Message, data dbmodels.Applicant) negotiationapimodels.MessageItem {
+	msg := negotiationapimodels.MessageItem{
+		ID:              item.ID,
+		MessageDateTime: item.CreatedAt,
+		SelfMessage:     item.SelfMessage,
+		Text:            item.Text,
+	}
+	if !msg.SelfMessage {
+		msg.AuthorFullName = data.GetFIO()
+	}
+	return msg
+}
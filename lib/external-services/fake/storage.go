@@ -0,0 +1,239 @@
+package fakehandler
+
+import (
+	"encoding/json"
+	"hr-tools-backend/models"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// Файловое хранилище тестового работного сайта:
+//
+//	<dir>/<spaceID>/token                                 - признак подключения спейса
+//	<dir>/<spaceID>/published/<vacancyID>.json            - размещение вакансии (Publication)
+//	<dir>/<spaceID>/negotiations/<vacancyID>/<id>.json    - отклики по вакансии (Negotiation), имя файла - ид отклика
+//	<dir>/<spaceID>/chats/<negotiationID>.json            - переписка по отклику ([]Message)
+type storage struct {
+	dir string
+}
+
+type Publication struct {
+	VacancyID string                  `json:"vacancy_id"`
+	Status    models.VacancyPubStatus `json:"status"`
+	Url       string                  `json:"url"`
+	Reason    string                  `json:"reason"`
+}
+
+type Negotiation struct {
+	ID          string `json:"-"` // ид отклика, имя файла без расширения
+	ResumeID    string `json:"resume_id"`
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name"`
+	MiddleName  string `json:"middle_name"`
+	Email       string `json:"email"`
+	Phone       string `json:"phone"`
+	ResumeTitle string `json:"resume_title"`
+	Salary      int    `json:"salary"`
+	Address     string `json:"address"`
+}
+
+type Message struct {
+	ID          string    `json:"id"`
+	Text        string    `json:"text"`
+	SelfMessage bool      `json:"self_message"` // сообщение от работодателя
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// spaceDir каталог спейса. Ид спейса приходит в том числе из state OAuth без авторизации,
+// поэтому допускается только UUID (защита от выхода за пределы каталога хранилища)
+func (s storage) spaceDir(spaceID string) (string, error) {
+	id, err := uuid.Parse(spaceID)
+	if err != nil {
+		return "", errors.Errorf("некорректный ид спейса: %q", spaceID)
+	}
+	return filepath.Join(s.dir, id.String()), nil
+}
+
+// spacePath путь к файлу в каталоге спейса
+func (s storage) spacePath(spaceID string, elem ...string) (string, error) {
+	dir, err := s.spaceDir(spaceID)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(append([]string{dir}, elem...)...), nil
+}
+
+func (s storage) isConnected(spaceID string) bool {
+	path, err := s.spacePath(spaceID, "token")
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+func (s storage) connect(spaceID, code string) error {
+	dir, err := s.spaceDir(spaceID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "token"), []byte(code), 0o644)
+}
+
+func (s storage) disconnect(spaceID string) error {
+	path, err := s.spacePath(spaceID, "token")
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (s storage) getPublication(spaceID, vacancyID string) (*Publication, error) {
+	path, err := s.spacePath(spaceID, "published", vacancyID+".json")
+	if err != nil {
+		return nil, err
+	}
+	rec := Publication{}
+	ok, err := readJson(path, &rec)
+	if err != nil || !ok {
+		return nil, err
+	}
+	rec.VacancyID = vacancyID
+	return &rec, nil
+}
+
+func (s storage) setPublication(spaceID string, rec Publication) error {
+	path, err := s.spacePath(spaceID, "published", rec.VacancyID+".json")
+	if err != nil {
+		return err
+	}
+	return writeJson(path, rec)
+}
+
+func (s storage) listPublications(spaceID string, status models.VacancyPubStatus) ([]Publication, error) {
+	dir, err := s.spacePath(spaceID, "published")
+	if err != nil {
+		return nil, err
+	}
+	names, err := listJson(dir)
+	if err != nil {
+		return nil, err
+	}
+	result := []Publication{}
+	for _, name := range names {
+		rec, err := s.getPublication(spaceID, name)
+		if err != nil {
+			return nil, err
+		}
+		if rec != nil && rec.Status == status {
+			result = append(result, *rec)
+		}
+	}
+	return result, nil
+}
+
+func (s storage) listNegotiations(spaceID, vacancyID string) ([]Negotiation, error) {
+	dir, err := s.spacePath(spaceID, "negotiations", vacancyID)
+	if err != nil {
+		return nil, err
+	}
+	names, err := listJson(dir)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Negotiation, 0, len(names))
+	for _, name := range names {
+		rec := Negotiation{}
+		_, err = readJson(filepath.Join(dir, name+".json"), &rec)
+		if err != nil {
+			return nil, errors.Wrapf(err, "ошибка чтения отклика %v", name)
+		}
+		rec.ID = name
+		result = append(result, rec)
+	}
+	return result, nil
+}
+
+func (s storage) getMessages(spaceID, negotiationID string) ([]Message, error) {
+	path, err := s.spacePath(spaceID, "chats", negotiationID+".json")
+	if err != nil {
+		return nil, err
+	}
+	result := []Message{}
+	_, err = readJson(path, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s storage) addMessage(spaceID, negotiationID string, msg Message) error {
+	list, err := s.getMessages(spaceID, negotiationID)
+	if err != nil {
+		return err
+	}
+	list = append(list, msg)
+	path, err := s.spacePath(spaceID, "chats", negotiationID+".json")
+	if err != nil {
+		return err
+	}
+	return writeJson(path, list)
+}
+
+func readJson(path string, v any) (found bool, err error) {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if err = json.Unmarshal(body, v); err != nil {
+		return false, errors.Wrapf(err, "ошибка разбора файла %v", path)
+	}
+	return true, nil
+}
+
+func writeJson(path string, v any) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	body, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, body, 0o644)
+}
+
+// listJson имена json файлов каталога без расширения, в алфавитном порядке
+func listJson(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+	result := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		result = append(result, strings.TrimSuffix(entry.Name(), ".json"))
+	}
+	sort.Strings(result)
+	return result, nil
+}
@@ -0,0 +1,95 @@
+package fakehandler
+
+import (
+	"hr-tools-backend/models"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorage(t *testing.T) {
+	spaceID := "0d2b1c2e-5d52-4a39-9b0b-6f5a4a3c1e7a"
+	vacancyID := "vacancy"
+
+	t.Run(`invalid space id check`, func(t *testing.T) {
+		root := t.TempDir()
+		s := storage{dir: filepath.Join(root, "store")}
+		for _, id := range []string{"", "..", "../outside", "space/../../outside"} {
+			require.Error(t, s.connect(id, "code"))
+			require.False(t, s.isConnected(id))
+			_, err := s.getMessages(id, "n1")
+			require.Error(t, err)
+		}
+		entries, err := os.ReadDir(root)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+
+	t.Run(`connect/disconnect check`, func(t *testing.T) {
+		s := storage{dir: t.TempDir()}
+		require.False(t, s.isConnected(spaceID))
+		require.NoError(t, s.connect(spaceID, "code"))
+		require.True(t, s.isConnected(spaceID))
+		require.NoError(t, s.disconnect(spaceID))
+		require.False(t, s.isConnected(spaceID))
+		require.NoError(t, s.disconnect(spaceID))
+	})
+
+	t.Run(`publications check`, func(t *testing.T) {
+		s := storage{dir: t.TempDir()}
+		pub, err := s.getPublication(spaceID, vacancyID)
+		require.NoError(t, err)
+		require.Nil(t, pub)
+
+		require.NoError(t, s.setPublication(spaceID, Publication{VacancyID: vacancyID, Status: models.VacancyPubStatusModeration}))
+		require.NoError(t, s.setPublication(spaceID, Publication{VacancyID: "other", Status: models.VacancyPubStatusPublished}))
+
+		list, err := s.listPublications(spaceID, models.VacancyPubStatusModeration)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		require.Equal(t, vacancyID, list[0].VacancyID)
+	})
+
+	t.Run(`negotiations parse check`, func(t *testing.T) {
+		s := storage{dir: t.TempDir()}
+		list, err := s.listNegotiations(spaceID, vacancyID)
+		require.NoError(t, err)
+		require.Empty(t, list)
+
+		dir := filepath.Join(s.dir, spaceID, "negotiations", vacancyID)
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		body := `{"first_name":"Иван","last_name":"Иванов","email":"ivan@mail.ru","salary":100000}`
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "n2.json"), []byte(body), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "n1.json"), []byte(`{"first_name":"Петр"}`), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "readme.txt"), []byte("skip"), 0o644))
+
+		list, err = s.listNegotiations(spaceID, vacancyID)
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		require.Equal(t, "n1", list[0].ID)
+		require.Equal(t, "n2", list[1].ID)
+		require.Equal(t, "Иванов", list[1].LastName)
+		require.Equal(t, 100000, list[1].Salary)
+
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "n3.json"), []byte("{"), 0o644))
+		_, err = s.listNegotiations(spaceID, vacancyID)
+		require.Error(t, err)
+	})
+
+	t.Run(`messages check`, func(t *testing.T) {
+		s := storage{dir: t.TempDir()}
+		list, err := s.getMessages(spaceID, "n1")
+		require.NoError(t, err)
+		require.Empty(t, list)
+
+		require.NoError(t, s.addMessage(spaceID, "n1", Message{ID: "1", Text: "Здравствуйте", SelfMessage: true, CreatedAt: time.Now()}))
+		require.NoError(t, s.addMessage(spaceID, "n1", Message{ID: "2", Text: "Добрый день", CreatedAt: time.Now()}))
+		list, err = s.getMessages(spaceID, "n1")
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		require.False(t, list[1].SelfMessage)
+	})
+}
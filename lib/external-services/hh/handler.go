@@ -18,6 +18,7 @@ import (
 	initchecker "hr-tools-backend/lib/utils/init-checker"
 	"hr-tools-backend/lib/utils/lock"
 	vacancystore "hr-tools-backend/lib/vacancy/store"
+	webhookevent "hr-tools-backend/lib/webhook/event"
 	"hr-tools-backend/models"
 	hhapimodels "hr-tools-backend/models/api/hh"
 	negotiationapimodels "hr-tools-backend/models/api/negotiation"
@@ -57,6 +58,19 @@ func NewHandler() {
 		"applicantHistory", instance.applicantHistory,
 	)
 	Instance = instance
+	externalservices.RegisterConnector(externalservices.Connector{
+		Code:   ConnectorCode,
+		Name:   "HeadHunter",
+		Source: ApplicantSourceHh,
+		Capabilities: []externalservices.Capability{
+			externalservices.CapabilityOAuth,
+			externalservices.CapabilityPublish,
+			externalservices.CapabilityClose,
+			externalservices.CapabilityNegotiations,
+			externalservices.CapabilityChat,
+		},
+		Provider: instance,
+	})
 }
 
 type impl struct {
@@ -73,9 +87,11 @@ type impl struct {
 }
 
 const (
-	TokenCode       = "HH_TOKEN"
-	VacancyUriTpl   = "https://hh.ru/vacancy/%v"
-	NotConnectedMsg = "HeadHunter не подключен"
+	ConnectorCode                            = "hh"
+	ApplicantSourceHh models.ApplicantSource = "HeadHunter"
+	TokenCode                                = "HH_TOKEN"
+	VacancyUriTpl                            = "https://hh.ru/vacancy/%v"
+	NotConnectedMsg                          = "HeadHunter не подключен"
 )
 
 func (i *impl) getLogger(spaceID, vacancyID string) *log.Entry {
@@ -308,6 +324,14 @@ func (i *impl) VacancyAttach(ctx context.Context, spaceID, vacancyID, hhID strin
 	return "", nil
 }
 
+func (i *impl) CanClose(rec dbmodels.Vacancy) bool {
+	return rec.HhID != "" && (rec.HhStatus == models.VacancyPubStatusModeration || rec.HhStatus != models.VacancyPubStatusPublished)
+}
+
+func (i *impl) GetVacancyUri(rec dbmodels.Vacancy) string {
+	return rec.HhUri
+}
+
 func (i *impl) GetVacancyInfo(ctx context.Context, spaceID, vacancyID string) (*vacancyapimodels.ExtVacancyInfo, error) {
 	rec, err := i.vacancyStore.GetByID(spaceID, vacancyID)
 	if err != nil {
@@ -345,7 +369,7 @@ func (i *impl) HandleNegotiations(ctx context.Context, data dbmodels.Vacancy) er
 		logger = logger.
 			WithField("negotiation_id", item.ID).
 			WithField("resume_id", item.Resume.ID)
-		found, err := i.applicantStore.IsExistNegotiationID(data.SpaceID, item.ID, models.ApplicantSourceHh)
+		found, err := i.applicantStore.IsExistNegotiationID(data.SpaceID, item.ID, ApplicantSourceHh)
 		if err != nil {
 			logger.WithError(err).Error("не удалось проверить наличие отклика")
 			continue
@@ -370,7 +394,7 @@ func (i *impl) HandleNegotiations(ctx context.Context, data dbmodels.Vacancy) er
 			NegotiationID:   item.ID,
 			ResumeID:        resume.ID,
 			ExtApplicantID:  resume.Owner.ID,
-			Source:          models.ApplicantSourceHh,
+			Source:          ApplicantSourceHh,
 			NegotiationDate: time.Now(),
 			Status:          models.ApplicantStatusNegotiation,
 			FirstName:       resume.FirstName,
@@ -480,8 +504,14 @@ func (i *impl) HandleNegotiations(ctx context.Context, data dbmodels.Vacancy) er
 		}
 		changes := applicanthistoryhandler.GetCreateChanges("Кандидат добавлен с работного сайта на вакансию", applicantData)
 		i.applicantHistory.Save(applicantData.SpaceID, applicantID, applicantData.VacancyID, "", dbmodels.HistoryTypeNegotiation, changes)
-
-		notification := models.GetPushApplicantNegotiation(data.VacancyName, applicantData.GetFIO())
+		webhookevent.Publish(webhookevent.Event{
+			Type:        dbmodels.WebhookEventApplicantCreated,
+			SpaceID:     applicantData.SpaceID,
+			ApplicantID: applicantID,
+			VacancyID:   applicantData.VacancyID,
+		})
+
+		notification := models.GetPushApplicantNegotiation(data.VacancyName, applicantData.GetFIO()).WithEntity(applicantID)
 		go i.sendNotification(data, notification)
 	}
 	return nil
@@ -5,8 +5,6 @@ import (
 	"hr-tools-backend/db"
 	applicantstore "hr-tools-backend/lib/applicant/store"
 	externalservices "hr-tools-backend/lib/external-services"
-	avitohandler "hr-tools-backend/lib/external-services/avito"
-	hhhandler "hr-tools-backend/lib/external-services/hh"
 	spaceusersstore "hr-tools-backend/lib/space/users/store"
 	initchecker "hr-tools-backend/lib/utils/init-checker"
 	"hr-tools-backend/models"
@@ -48,22 +46,17 @@ func (i impl) IsVailable(spaceID, applicantID string) (negotiationapimodels.Mess
 	if applicant == nil {
 		return negotiationapimodels.MessengerAvailableResponse{}, errors.New("кандидат не найден")
 	}
-	if (applicant.Source != models.ApplicantSourceAvito && applicant.Source != models.ApplicantSourceHh) ||
-		applicant.NegotiationID == "" {
+	connector, ok := getChatConnector(applicant.Source)
+	if !ok || applicant.NegotiationID == "" {
 		return negotiationapimodels.MessengerAvailableResponse{IsAvailable: false}, nil
 	}
 
 	result := negotiationapimodels.MessengerAvailableResponse{IsAvailable: false, Service: string(applicant.Source)}
-	switch applicant.Source {
-	case models.ApplicantSourceAvito:
-		//для авито должен быть идентификатор чата
-		if applicant.ChatID == "" {
-			return result, nil
-		}
-		result.IsAvailable = avitohandler.Instance.CheckConnected(context.TODO(), spaceID)
-	case models.ApplicantSourceHh:
-		result.IsAvailable = hhhandler.Instance.CheckConnected(context.TODO(), spaceID)
+	//для некоторых сайтов (авито) должен быть идентификатор чата
+	if connector.ChatIDRequired && applicant.ChatID == "" {
+		return result, nil
 	}
+	result.IsAvailable = connector.Provider.CheckConnected(context.TODO(), spaceID)
 	return result, nil
 }
 
@@ -75,16 +68,11 @@ func (i impl) SendMessage(spaceID string, req negotiationapimodels.NewMessageReq
 	if applicant == nil {
 		return errors.New("кандидат не найден")
 	}
-	var handler externalservices.JobSiteProvider
-	switch applicant.Source {
-	case models.ApplicantSourceAvito:
-		handler = avitohandler.Instance
-	case models.ApplicantSourceHh:
-		handler = hhhandler.Instance
-	default:
+	connector, ok := getChatConnector(applicant.Source)
+	if !ok {
 		return errors.New("чат не поддерживается")
 	}
-	return handler.SendMessage(context.TODO(), applicant.Applicant, req.Text)
+	return connector.Provider.SendMessage(context.TODO(), applicant.Applicant, req.Text)
 }
 
 func (i impl) GetMessages(spaceID, userID string, req negotiationapimodels.MessageListRequest) (list []negotiationapimodels.MessageItem, err error) {
@@ -103,14 +91,17 @@ func (i impl) GetMessages(spaceID, userID string, req negotiationapimodels.Messa
 	if user == nil {
 		return nil, errors.New("пользователь не найден")
 	}
-	var handler externalservices.JobSiteProvider
-	switch applicant.Source {
-	case models.ApplicantSourceAvito:
-		handler = avitohandler.Instance
-	case models.ApplicantSourceHh:
-		handler = hhhandler.Instance
-	default:
+	connector, ok := getChatConnector(applicant.Source)
+	if !ok {
 		return nil, errors.New("чат не поддерживается")
 	}
-	return handler.GetMessages(context.TODO(), *user, applicant.Applicant)
+	return connector.Provider.GetMessages(context.TODO(), *user, applicant.Applicant)
+}
+
+func getChatConnector(source models.ApplicantSource) (externalservices.Connector, bool) {
+	connector, ok := externalservices.GetConnectorBySource(source)
+	if !ok || !connector.Has(externalservices.CapabilityChat) {
+		return externalservices.Connector{}, false
+	}
+	return connector, true
 }
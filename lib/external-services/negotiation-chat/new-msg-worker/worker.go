@@ -8,8 +8,6 @@ import (
 	"hr-tools-backend/db"
 	applicantstore "hr-tools-backend/lib/applicant/store"
 	externalservices "hr-tools-backend/lib/external-services"
-	avitohandler "hr-tools-backend/lib/external-services/avito"
-	hhhandler "hr-tools-backend/lib/external-services/hh"
 	extservicestore "hr-tools-backend/lib/external-services/store"
 	pushhandler "hr-tools-backend/lib/space/push/handler"
 	vacancystore "hr-tools-backend/lib/vacancy/store"
@@ -21,14 +19,13 @@ import (
 
 func StartWorker(ctx context.Context) {
 	i := &impl{
-		avito:          avitohandler.Instance.(externalservices.JobSiteProvider),
-		hh:             hhhandler.Instance.(externalservices.JobSiteProvider),
 		applicantStore: applicantstore.NewInstance(db.DB),
 		extStore:       extservicestore.NewInstance(db.DB),
 		vacancyStore:   vacancystore.NewInstance(db.DB),
 	}
-	go i.run(ctx, "HeadHunter", i.hh)
-	go i.run(ctx, "Avito", i.avito)
+	for _, connector := range externalservices.ListConnectors(externalservices.CapabilityChat) {
+		go i.run(ctx, connector)
+	}
 }
 
 const (
@@ -36,8 +33,6 @@ const (
 )
 
 type impl struct {
-	avito          externalservices.JobSiteProvider
-	hh             externalservices.JobSiteProvider
 	applicantStore applicantstore.Provider
 	extStore       extservicestore.Provider
 	vacancyStore   vacancystore.Provider
@@ -50,7 +45,8 @@ func (i impl) getLogger(integrationName string) *log.Entry {
 	return logger
 }
 
-func (i impl) run(ctx context.Context, integrationName string, provider externalservices.JobSiteProvider) {
+func (i impl) run(ctx context.Context, connector externalservices.Connector) {
+	integrationName := connector.Name
 	defer func() {
 		if r := recover(); r != nil {
 			i.getLogger(integrationName).
@@ -68,16 +64,18 @@ func (i impl) run(ctx context.Context, integrationName string, provider external
 			return
 		case <-time.After(period):
 			logger.Info("Задача запущена")
-			i.handle(ctx, integrationName, provider)
+			i.handle(ctx, connector)
 			logger.Info("Задача выполнена")
 		}
 		period = handlePeriod
 	}
 }
 
-func (i impl) handle(ctx context.Context, integrationName string, provider externalservices.JobSiteProvider) {
+func (i impl) handle(ctx context.Context, connector externalservices.Connector) {
+	integrationName := connector.Name
+	provider := connector.Provider
 	logger := i.getLogger(integrationName)
-	list, err := i.applicantStore.ListOfActiveApplicants()
+	list, err := i.applicantStore.ListOfActiveApplicants([]models.ApplicantSource{connector.Source})
 	if err != nil {
 		logger.WithError(err).Error("ошибка получения списка активных кандидатов")
 		return
@@ -92,6 +90,9 @@ func (i impl) handle(ctx context.Context, integrationName string, provider exter
 		if !isConnected {
 			continue
 		}
+		if connector.ChatIDRequired && applicant.ChatID == "" {
+			continue
+		}
 		msg, err := provider.GetLastInMessage(ctx, applicant)
 		if err != nil {
 			logger.WithError(err).
@@ -122,7 +123,7 @@ func (i impl) handle(ctx context.Context, integrationName string, provider exter
 		binary.LittleEndian.PutUint64(b, uint64(msg.MessageDateTime.Unix()))
 		i.extStore.Set(applicant.SpaceID, getChatKey(applicant.ID), b)
 
-		notification := models.GetPushApplicantMsg(applicant.Vacancy.VacancyName, applicant.GetFIO(), integrationName)
+		notification := models.GetPushApplicantMsg(applicant.Vacancy.VacancyName, applicant.GetFIO(), integrationName).WithEntity(applicant.ID)
 		go func(spaceID, vacancyID, integrationName string, nData models.NotificationData) {
 			i.newMsg(spaceID, vacancyID, integrationName, nData)
 		}(applicant.SpaceID, applicant.VacancyID, integrationName, notification)
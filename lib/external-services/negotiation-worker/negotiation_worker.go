@@ -4,8 +4,7 @@ import (
 	"context"
 	log "github.com/sirupsen/logrus"
 	"hr-tools-backend/db"
-	avitohandler "hr-tools-backend/lib/external-services/avito"
-	hhhandler "hr-tools-backend/lib/external-services/hh"
+	externalservices "hr-tools-backend/lib/external-services"
 	spacestore "hr-tools-backend/lib/space/store"
 	"hr-tools-backend/lib/utils/helpers"
 	"hr-tools-backend/models"
@@ -22,12 +21,16 @@ type NegotiationCheckJob interface {
 
 func StartWorker(ctx context.Context) {
 	i := &impl{
-		avito:      avitohandler.Instance.(NegotiationCheckJob),
-		hh:         hhhandler.Instance.(NegotiationCheckJob),
 		spaceStore: spacestore.NewInstance(db.DB),
 	}
-	go i.run(ctx, "HeadHunter", i.hh)
-	go i.run(ctx, "Avito", i.avito)
+	for _, connector := range externalservices.ListConnectors(externalservices.CapabilityNegotiations) {
+		jobHandler, ok := connector.Provider.(NegotiationCheckJob)
+		if !ok {
+			i.getLogger(connector.Name).Warn("коннектор не поддерживает задачу, пропускаем")
+			continue
+		}
+		go i.run(ctx, connector.Name, jobHandler)
+	}
 }
 
 const (
@@ -35,8 +38,6 @@ const (
 )
 
 type impl struct {
-	avito      NegotiationCheckJob
-	hh         NegotiationCheckJob
 	spaceStore spacestore.Provider
 }
 
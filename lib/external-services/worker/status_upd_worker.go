@@ -4,8 +4,7 @@ import (
 	"context"
 	log "github.com/sirupsen/logrus"
 	"hr-tools-backend/db"
-	avitohandler "hr-tools-backend/lib/external-services/avito"
-	hhhandler "hr-tools-backend/lib/external-services/hh"
+	externalservices "hr-tools-backend/lib/external-services"
 	spacestore "hr-tools-backend/lib/space/store"
 	"hr-tools-backend/lib/utils/helpers"
 	"hr-tools-backend/models"
@@ -23,12 +22,16 @@ type StatusCheckJob interface {
 
 func StartWorker(ctx context.Context) {
 	i := &impl{
-		avito:      avitohandler.Instance.(StatusCheckJob),
-		hh:         hhhandler.Instance.(StatusCheckJob),
 		spaceStore: spacestore.NewInstance(db.DB),
 	}
-	go i.run(ctx, "HeadHunter", i.hh)
-	go i.run(ctx, "Avito", i.avito)
+	for _, connector := range externalservices.ListConnectors(externalservices.CapabilityPublish) {
+		jobHandler, ok := connector.Provider.(StatusCheckJob)
+		if !ok {
+			i.getLogger(connector.Name).Warn("коннектор не поддерживает задачу, пропускаем")
+			continue
+		}
+		go i.run(ctx, connector.Name, jobHandler)
+	}
 }
 
 const (
@@ -36,8 +39,6 @@ const (
 )
 
 type impl struct {
-	avito      StatusCheckJob
-	hh         StatusCheckJob
 	spaceStore spacestore.Provider
 }
 
@@ -8,10 +8,13 @@ import (
 
 type Provider interface {
 	GetByID(id string) (rec *dbmodels.FileStorage, err error)
+	// IsVisible файл кандидата виден, если виден кандидат, остальные файлы спейса видны всем пользователям спейса
+	IsVisible(spaceID, id string, scope *dbmodels.DataScope) (bool, error)
 	SaveFile(rec dbmodels.FileStorage) (id string, err error)
 	DeleteFile(id, spaceID string) (ok bool, err error)
 	GetFileIDByType(applicantID string, fileType dbmodels.FileType) (rec *dbmodels.FileStorage, err error)
 	GetFileListByType(applicantID string, fileType dbmodels.FileType) (list []dbmodels.FileStorage, err error)
+	GetFileList(applicantID string) (list []dbmodels.FileStorage, err error)
 }
 
 func NewInstance(db *gorm.DB) Provider {
@@ -38,6 +41,20 @@ func (i impl) GetByID(id string) (rec *dbmodels.FileStorage, err error) {
 	return rec, nil
 }
 
+func (i impl) IsVisible(spaceID, id string, scope *dbmodels.DataScope) (bool, error) {
+	var exists bool
+	tx := i.db.
+		Model(&dbmodels.FileStorage{}).
+		Select("count(*) > 0").
+		Where("id = ?", id).
+		Where("space_id = ?", spaceID)
+	if query, args := scope.ApplicantRefCondition("file_storages.applicant_id"); query != "" {
+		tx.Where("(file_storages.applicant_id = '' OR "+query+")", args)
+	}
+	err := tx.Find(&exists).Error
+	return exists, err
+}
+
 func (i impl) GetFileListByType(applicantID string, fileType dbmodels.FileType) (list []dbmodels.FileStorage, err error) {
 	err = i.db.
 		Model(&dbmodels.FileStorage{}).
@@ -50,6 +67,18 @@ func (i impl) GetFileListByType(applicantID string, fileType dbmodels.FileType)
 	return list, nil
 }
 
+func (i impl) GetFileList(applicantID string) (list []dbmodels.FileStorage, err error) {
+	err = i.db.
+		Model(&dbmodels.FileStorage{}).
+		Where("applicant_id = ?", applicantID).
+		Find(&list).
+		Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	return list, nil
+}
+
 func (i impl) GetFileIDByType(applicantID string, fileType dbmodels.FileType) (rec *dbmodels.FileStorage, err error) {
 	rec = new(dbmodels.FileStorage)
 	err = i.db.
@@ -0,0 +1,57 @@
+package gpthandler
+
+import (
+	"encoding/json"
+	"fmt"
+	dbmodels "hr-tools-backend/models/db"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	ResumeParseSysPromt = "Ты — нейросеть, помогаешь HR-специалистам заполнять профиль кандидата по тексту резюме."
+	ResumeParseTemplate = `{
+  "resume":%q,
+  "instruction":"Извлеки из резюме данные кандидата. total_experience - общий опыт работы в месяцах. Если значение не указано в резюме - оставь пустую строку, 0 или пустой список. Формат ответа: только JSON, смотри в answer_format",
+"answer_format":{
+  "first_name":"", "last_name":"", "middle_name":"",
+  "phone":"", "email":"",
+  "birth_date":"ДД.ММ.ГГГГ",
+  "address":"город проживания", "citizenship":"",
+  "salary":0,
+  "total_experience":0,
+  "education":"одно из: secondary, special_secondary, unfinished_higher, higher, bachelor, master, candidate, doctor",
+  "languages":[{"name":"Английский","language_level":"одно из: a1, a2, b1, b2, c1, c2, l1 (родной)"}],
+  "employments":["из списка: full, partial, temporary, internship, volunteer, probation"],
+  "schedules":["из списка: fullDay, partTime, shift, flexible, flyInFlyOut"],
+  "driver_license_types":["из списка: A, B, C, D, E, BE, CE, DE, TM, TB"]
+}
+}
+`
+)
+
+func (i impl) ParseResume(spaceID, applicantID, text string) (data dbmodels.ResumeData, err error) {
+	userPromt := fmt.Sprintf(ResumeParseTemplate, text)
+	answer, err := i.getYaClient().
+		GenerateByPromtAndText(ResumeParseSysPromt, userPromt)
+	if err != nil {
+		log.
+			WithField("space_id", spaceID).
+			WithField("applicant_id", applicantID).
+			WithError(err).
+			Error("ошибка извлечения данных из резюме через GPT")
+		return data, err
+	}
+	i.saveLog(spaceID, "", ResumeParseSysPromt, userPromt, answer, dbmodels.AiParseResumeType)
+
+	answer = strings.TrimSpace(answer)
+	answer = strings.TrimPrefix(answer, "```json")
+	answer = strings.Trim(answer, "`\n ")
+	err = json.Unmarshal([]byte(answer), &data)
+	if err != nil {
+		return data, errors.Wrapf(err, "ошибка декодирования json в структуру данных резюме, json: %v", answer)
+	}
+	return data, nil
+}
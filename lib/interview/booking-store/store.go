@@ -0,0 +1,77 @@
+package interviewbookingstore
+
+import (
+	dbmodels "hr-tools-backend/models/db"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+type Provider interface {
+	Create(rec dbmodels.InterviewBooking) (id string, err error)
+	GetByID(id string) (*dbmodels.InterviewBooking, error)
+	// SetInterview привязка интервью к ссылке, false - по ссылке уже есть запись
+	SetInterview(id, interviewID string) (bool, error)
+	ListByApplicant(spaceID, applicantID string) ([]dbmodels.InterviewBooking, error)
+}
+
+func NewInstance(DB *gorm.DB) Provider {
+	return &impl{
+		db: DB,
+	}
+}
+
+type impl struct {
+	db *gorm.DB
+}
+
+func (i impl) Create(rec dbmodels.InterviewBooking) (id string, err error) {
+	err = i.db.
+		Save(&rec).
+		Error
+	if err != nil {
+		return "", err
+	}
+	return rec.ID, nil
+}
+
+func (i impl) GetByID(id string) (*dbmodels.InterviewBooking, error) {
+	rec := dbmodels.InterviewBooking{}
+	err := i.db.
+		Where("id = ?", id).
+		First(&rec).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (i impl) SetInterview(id, interviewID string) (bool, error) {
+	tx := i.db.
+		Model(&dbmodels.InterviewBooking{}).
+		Where("id = ?", id).
+		Where("interview_id is null").
+		Update("interview_id", interviewID)
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+	return tx.RowsAffected != 0, nil
+}
+
+func (i impl) ListByApplicant(spaceID, applicantID string) ([]dbmodels.InterviewBooking, error) {
+	list := []dbmodels.InterviewBooking{}
+	err := i.db.
+		Where("space_id = ?", spaceID).
+		Where("applicant_id = ?", applicantID).
+		Order("created_at").
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
@@ -0,0 +1,786 @@
+package interview
+
+import (
+	"fmt"
+	"hr-tools-backend/config"
+	"hr-tools-backend/db"
+	applicanthistoryhandler "hr-tools-backend/lib/applicant-history"
+	applicantstore "hr-tools-backend/lib/applicant/store"
+	"hr-tools-backend/lib/calendar"
+	datascope "hr-tools-backend/lib/data-scope"
+	interviewbookingstore "hr-tools-backend/lib/interview/booking-store"
+	interviewslotstore "hr-tools-backend/lib/interview/slot-store"
+	interviewstore "hr-tools-backend/lib/interview/store"
+	messagetemplate "hr-tools-backend/lib/message-template"
+	"hr-tools-backend/lib/smtp"
+	pushhandler "hr-tools-backend/lib/space/push/handler"
+	spacestore "hr-tools-backend/lib/space/store"
+	spaceusersstore "hr-tools-backend/lib/space/users/store"
+	initchecker "hr-tools-backend/lib/utils/init-checker"
+	selectionstagestore "hr-tools-backend/lib/vacancy/selection-stage-store"
+	teamstore "hr-tools-backend/lib/vacancy/team-store"
+	"hr-tools-backend/models"
+	calendarapimodels "hr-tools-backend/models/api/calendar"
+	interviewapimodels "hr-tools-backend/models/api/interview"
+	dbmodels "hr-tools-backend/models/db"
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type Provider interface {
+	Create(spaceID, userID string, data interviewapimodels.CreateInterviewRequest) (id, hMsg string, err error)
+	Update(spaceID, id, userID string, data interviewapimodels.InterviewData) (hMsg string, err error)
+	Cancel(spaceID, id, userID string) (hMsg string, err error)
+	GetByID(spaceID, id string) (*interviewapimodels.InterviewView, error)
+	// List интервью, в которых пользователь участвует или кандидат которых ему виден
+	List(spaceID, userID string, filter interviewapimodels.InterviewFilter) ([]interviewapimodels.InterviewView, int64, error)
+	CreateSlot(spaceID, userID string, data interviewapimodels.SlotData) (id, hMsg string, err error)
+	ListSlots(spaceID string, filter interviewapimodels.SlotFilter) ([]interviewapimodels.SlotView, error)
+	DeleteSlot(spaceID, id string) (hMsg string, err error)
+	CreateBooking(spaceID, userID string, data interviewapimodels.BookingRequest) (view *interviewapimodels.BookingView, hMsg string, err error)
+	GetPublicBooking(id string) (view *interviewapimodels.PublicBookingView, hMsg string, err error)
+	Book(id string, data interviewapimodels.PublicBookRequest) (hMsg string, err error)
+	// Remind напоминание участникам и кандидату о предстоящем интервью
+	Remind(spaceID, id string)
+	// Reschedule перенос интервью по изменению события в календаре участника
+	Reschedule(spaceID, id, userID string, startAt, endAt time.Time) (hMsg string, err error)
+}
+
+var Instance Provider
+
+func NewHandler() {
+	instance := impl{
+		store:               interviewstore.NewInstance(db.DB),
+		slotStore:           interviewslotstore.NewInstance(db.DB),
+		bookingStore:        interviewbookingstore.NewInstance(db.DB),
+		applicantStore:      applicantstore.NewInstance(db.DB),
+		teamStore:           teamstore.NewInstance(db.DB),
+		selectionStageStore: selectionstagestore.NewInstance(db.DB),
+		spaceUserStore:      spaceusersstore.NewInstance(db.DB),
+		spaceStore:          spacestore.NewInstance(db.DB),
+		applicantHistory:    applicanthistoryhandler.Instance,
+		messageTemplate:     messagetemplate.Instance,
+		calendar:            calendar.Instance,
+	}
+	initchecker.CheckInit(
+		"store", instance.store,
+		"slotStore", instance.slotStore,
+		"bookingStore", instance.bookingStore,
+		"applicantStore", instance.applicantStore,
+		"teamStore", instance.teamStore,
+		"selectionStageStore", instance.selectionStageStore,
+		"spaceUserStore", instance.spaceUserStore,
+		"spaceStore", instance.spaceStore,
+		"applicantHistory", instance.applicantHistory,
+		"messageTemplate", instance.messageTemplate,
+		"calendar", instance.calendar,
+	)
+	Instance = instance
+}
+
+type impl struct {
+	store               interviewstore.Provider
+	slotStore           interviewslotstore.Provider
+	bookingStore        interviewbookingstore.Provider
+	applicantStore      applicantstore.Provider
+	teamStore           teamstore.Provider
+	selectionStageStore selectionstagestore.Provider
+	spaceUserStore      spaceusersstore.Provider
+	spaceStore          spacestore.Provider
+	applicantHistory    applicanthistoryhandler.Provider
+	messageTemplate     messagetemplate.Provider
+	calendar            calendar.Provider
+}
+
+const (
+	InterviewNotFoundMsg  = "интервью не найдено"
+	InterviewCanceledMsg  = "интервью отменено"
+	SlotNotFoundMsg       = "слот не найден"
+	SlotNotAvailableMsg   = "выбранное время недоступно, выберите другое"
+	BookingNotFoundMsg    = "ссылка для записи на интервью не найдена"
+	BookingExpiredMsg     = "срок действия ссылки для записи на интервью истек"
+	BookingAlreadyUsedMsg = "запись на интервью по ссылке уже выполнена"
+
+	timeFormat = "02.01.2006 15:04"
+)
+
+var errSlotNotAvailable = errors.New(SlotNotAvailableMsg)
+
+func (i impl) getLogger(spaceID, interviewID string) *log.Entry {
+	return log.
+		WithField("space_id", spaceID).
+		WithField("interview_id", interviewID)
+}
+
+func (i impl) Create(spaceID, userID string, data interviewapimodels.CreateInterviewRequest) (id, hMsg string, err error) {
+	applicant, hMsg, err := i.getApplicant(spaceID, userID, data.ApplicantID)
+	if err != nil || hMsg != "" {
+		return "", hMsg, err
+	}
+	stageID, hMsg, err := i.getStageID(spaceID, applicant.Applicant, data.SelectionStageID)
+	if err != nil || hMsg != "" {
+		return "", hMsg, err
+	}
+	participants := uniqueIDs(data.Participants)
+	hMsg, err = i.checkParticipants(spaceID, applicant.VacancyID, participants)
+	if err != nil || hMsg != "" {
+		return "", hMsg, err
+	}
+	hMsg, err = i.checkConflicts(spaceID, "", applicant.ID, participants, data.StartAt, data.EndAt)
+	if err != nil || hMsg != "" {
+		return "", hMsg, err
+	}
+	rec := dbmodels.Interview{
+		BaseSpaceModel: dbmodels.BaseSpaceModel{
+			SpaceID: spaceID,
+		},
+		ApplicantID:      applicant.ID,
+		VacancyID:        applicant.VacancyID,
+		SelectionStageID: stageID,
+		AuthorID:         userID,
+		StartAt:          data.StartAt,
+		EndAt:            data.EndAt,
+		Location:         data.Location,
+		Comment:          data.Comment,
+		Status:           dbmodels.InterviewScheduled,
+	}
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		store := interviewstore.NewInstance(tx)
+		id, err = store.Create(rec)
+		if err != nil {
+			return err
+		}
+		return store.SetParticipants(spaceID, id, participants)
+	})
+	if err != nil {
+		return "", "", errors.Wrap(err, "ошибка создания интервью")
+	}
+	changes := applicanthistoryhandler.GetInterviewChange("Назначено интервью", i.formatTime(spaceID, rec.StartAt), rec.Location)
+	i.applicantHistory.Save(spaceID, applicant.ID, applicant.VacancyID, userID, dbmodels.HistoryTypeInterview, changes)
+	go i.notify(spaceID, id, icsMethodRequest, nil)
+	return id, "", nil
+}
+
+func (i impl) Update(spaceID, id, userID string, data interviewapimodels.InterviewData) (hMsg string, err error) {
+	rec, err := i.store.GetByID(spaceID, id)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения интервью")
+	}
+	if rec == nil {
+		return InterviewNotFoundMsg, nil
+	}
+	if rec.Status == dbmodels.InterviewCanceled {
+		return InterviewCanceledMsg, nil
+	}
+	stageID := rec.SelectionStageID
+	if data.SelectionStageID != "" && data.SelectionStageID != stageID {
+		stage, err := i.selectionStageStore.GetByID(spaceID, rec.VacancyID, data.SelectionStageID)
+		if err != nil {
+			return "", errors.Wrap(err, "ошибка получения этапа подбора")
+		}
+		if stage == nil {
+			return "этап подбора не найден", nil
+		}
+		stageID = stage.ID
+	}
+	participants := uniqueIDs(data.Participants)
+	hMsg, err = i.checkParticipants(spaceID, rec.VacancyID, participants)
+	if err != nil || hMsg != "" {
+		return hMsg, err
+	}
+	hMsg, err = i.checkConflicts(spaceID, rec.ID, rec.ApplicantID, participants, data.StartAt, data.EndAt)
+	if err != nil || hMsg != "" {
+		return hMsg, err
+	}
+	removed := make([]string, 0, len(rec.Participants))
+	for _, participant := range rec.Participants {
+		if !slices.Contains(participants, participant.UserID) {
+			removed = append(removed, participant.UserID)
+		}
+	}
+	updMap := map[string]interface{}{
+		"selection_stage_id": stageID,
+		"start_at":           data.StartAt,
+		"end_at":             data.EndAt,
+		"location":           data.Location,
+		"comment":            data.Comment,
+		"sequence":           rec.Sequence + 1,
+	}
+	if !rec.StartAt.Equal(data.StartAt) {
+		updMap["reminder_sent"] = false
+	}
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		store := interviewstore.NewInstance(tx)
+		err := store.Update(spaceID, id, updMap)
+		if err != nil {
+			return err
+		}
+		if !rec.StartAt.Equal(data.StartAt) || !rec.EndAt.Equal(data.EndAt) {
+			// время изменено вручную, слот интервьюера больше не занят этим интервью
+			err = interviewslotstore.NewInstance(tx).Release(spaceID, id)
+			if err != nil {
+				return err
+			}
+		}
+		return store.SetParticipants(spaceID, id, participants)
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка изменения интервью")
+	}
+	changes := applicanthistoryhandler.GetInterviewChange("Изменено интервью", i.formatTime(spaceID, data.StartAt), data.Location)
+	i.applicantHistory.Save(spaceID, rec.ApplicantID, rec.VacancyID, userID, dbmodels.HistoryTypeInterview, changes)
+	go i.notify(spaceID, id, icsMethodRequest, removed)
+	return "", nil
+}
+
+func (i impl) Cancel(spaceID, id, userID string) (hMsg string, err error) {
+	rec, err := i.store.GetByID(spaceID, id)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения интервью")
+	}
+	if rec == nil {
+		return InterviewNotFoundMsg, nil
+	}
+	if rec.Status == dbmodels.InterviewCanceled {
+		return "", nil
+	}
+	updMap := map[string]interface{}{
+		"status":   dbmodels.InterviewCanceled,
+		"sequence": rec.Sequence + 1,
+	}
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		err := interviewstore.NewInstance(tx).Update(spaceID, id, updMap)
+		if err != nil {
+			return err
+		}
+		return interviewslotstore.NewInstance(tx).Release(spaceID, id)
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка отмены интервью")
+	}
+	changes := applicanthistoryhandler.GetInterviewChange("Отменено интервью", i.formatTime(spaceID, rec.StartAt), rec.Location)
+	i.applicantHistory.Save(spaceID, rec.ApplicantID, rec.VacancyID, userID, dbmodels.HistoryTypeInterview, changes)
+	go i.notify(spaceID, id, icsMethodCancel, nil)
+	return "", nil
+}
+
+func (i impl) GetByID(spaceID, id string) (*interviewapimodels.InterviewView, error) {
+	rec, err := i.store.GetByID(spaceID, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения интервью")
+	}
+	if rec == nil {
+		return nil, nil
+	}
+	result := interviewapimodels.InterviewConvert(*rec)
+	return &result, nil
+}
+
+func (i impl) List(spaceID, userID string, filter interviewapimodels.InterviewFilter) ([]interviewapimodels.InterviewView, int64, error) {
+	scope, err := datascope.Instance.GetScope(spaceID, userID)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "ошибка получения области видимости")
+	}
+	rowCount, err := i.store.ListCount(spaceID, scope, filter)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "ошибка получения количества интервью")
+	}
+	page, limit := filter.GetPage()
+	offset := (page - 1) * limit
+	if int64(offset) > rowCount {
+		return []interviewapimodels.InterviewView{}, rowCount, nil
+	}
+	list, err := i.store.List(spaceID, scope, filter)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "ошибка получения списка интервью")
+	}
+	result := make([]interviewapimodels.InterviewView, 0, len(list))
+	for _, rec := range list {
+		result = append(result, interviewapimodels.InterviewConvert(rec))
+	}
+	return result, rowCount, nil
+}
+
+func (i impl) CreateSlot(spaceID, userID string, data interviewapimodels.SlotData) (id, hMsg string, err error) {
+	if data.UserID != "" {
+		userID = data.UserID
+	}
+	user, err := i.spaceUserStore.GetByID(userID)
+	if err != nil {
+		return "", "", errors.Wrap(err, "ошибка получения пользователя")
+	}
+	if user == nil || user.SpaceID != spaceID {
+		return "", "пользователь не найден", nil
+	}
+	overlap, err := i.slotStore.HasOverlap(spaceID, userID, data.StartAt, data.EndAt)
+	if err != nil {
+		return "", "", errors.Wrap(err, "ошибка проверки пересечения слотов")
+	}
+	if overlap {
+		return "", "слот пересекается с другим слотом интервьюера", nil
+	}
+	rec := dbmodels.InterviewSlot{
+		BaseSpaceModel: dbmodels.BaseSpaceModel{
+			SpaceID: spaceID,
+		},
+		UserID:  userID,
+		StartAt: data.StartAt,
+		EndAt:   data.EndAt,
+	}
+	id, err = i.slotStore.Create(rec)
+	if err != nil {
+		return "", "", errors.Wrap(err, "ошибка создания слота")
+	}
+	return id, "", nil
+}
+
+func (i impl) ListSlots(spaceID string, filter interviewapimodels.SlotFilter) ([]interviewapimodels.SlotView, error) {
+	list, err := i.slotStore.List(spaceID, filter.ToDbFilter())
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения списка слотов")
+	}
+	if filter.FreeOnly {
+		list = i.excludeBusy(spaceID, list)
+	}
+	result := make([]interviewapimodels.SlotView, 0, len(list))
+	for _, rec := range list {
+		result = append(result, interviewapimodels.SlotConvert(rec))
+	}
+	return result, nil
+}
+
+func (i impl) DeleteSlot(spaceID, id string) (hMsg string, err error) {
+	rec, err := i.slotStore.GetByID(spaceID, id)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения слота")
+	}
+	if rec == nil {
+		return SlotNotFoundMsg, nil
+	}
+	if rec.InterviewID != nil {
+		return "на слот назначено интервью, для удаления слота отмените интервью", nil
+	}
+	err = i.slotStore.Delete(spaceID, id)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка удаления слота")
+	}
+	return "", nil
+}
+
+func (i impl) CreateBooking(spaceID, userID string, data interviewapimodels.BookingRequest) (view *interviewapimodels.BookingView, hMsg string, err error) {
+	applicant, hMsg, err := i.getApplicant(spaceID, userID, data.ApplicantID)
+	if err != nil || hMsg != "" {
+		return nil, hMsg, err
+	}
+	stageID, hMsg, err := i.getStageID(spaceID, applicant.Applicant, data.SelectionStageID)
+	if err != nil || hMsg != "" {
+		return nil, hMsg, err
+	}
+	participants := uniqueIDs(data.Participants)
+	hMsg, err = i.checkParticipants(spaceID, applicant.VacancyID, participants)
+	if err != nil || hMsg != "" {
+		return nil, hMsg, err
+	}
+	expireDays := data.ExpiresInDays
+	if expireDays == 0 {
+		expireDays = config.Conf.Interview.BookingExpireDays
+	}
+	rec := dbmodels.InterviewBooking{
+		BaseSpaceModel: dbmodels.BaseSpaceModel{
+			SpaceID: spaceID,
+		},
+		ApplicantID:      applicant.ID,
+		VacancyID:        applicant.VacancyID,
+		SelectionStageID: stageID,
+		AuthorID:         userID,
+		UserIDs:          participants,
+		Location:         data.Location,
+		ExpiresAt:        time.Now().AddDate(0, 0, expireDays),
+	}
+	id, err := i.bookingStore.Create(rec)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "ошибка создания ссылки для записи на интервью")
+	}
+	return &interviewapimodels.BookingView{
+		ID:        id,
+		Link:      config.Conf.UIParams.InterviewPath + id,
+		ExpiresAt: rec.ExpiresAt,
+	}, "", nil
+}
+
+func (i impl) GetPublicBooking(id string) (view *interviewapimodels.PublicBookingView, hMsg string, err error) {
+	booking, hMsg, err := i.getBooking(id)
+	if err != nil || hMsg != "" {
+		return nil, hMsg, err
+	}
+	applicant, err := i.applicantStore.GetByID(booking.SpaceID, booking.ApplicantID)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "ошибка получения кандидата")
+	}
+	if applicant == nil {
+		return nil, BookingNotFoundMsg, nil
+	}
+	result := interviewapimodels.PublicBookingView{
+		FirstName: applicant.FirstName,
+		ExpiresAt: booking.ExpiresAt,
+		Slots:     []interviewapimodels.PublicInterviewView{},
+	}
+	if applicant.Vacancy != nil {
+		result.VacancyName = applicant.Vacancy.VacancyName
+	}
+	if booking.InterviewID != nil {
+		rec, err := i.store.GetByID(booking.SpaceID, *booking.InterviewID)
+		if err != nil {
+			return nil, "", errors.Wrap(err, "ошибка получения интервью")
+		}
+		if rec != nil && rec.Status == dbmodels.InterviewScheduled {
+			result.Interview = &interviewapimodels.PublicInterviewView{
+				ID:       rec.ID,
+				StartAt:  rec.StartAt,
+				EndAt:    rec.EndAt,
+				Location: rec.Location,
+			}
+		}
+		return &result, "", nil
+	}
+	if booking.ExpiresAt.Before(time.Now()) {
+		return nil, BookingExpiredMsg, nil
+	}
+	filter := dbmodels.InterviewSlotFilter{
+		UserIDs:  booking.UserIDs,
+		From:     time.Now(),
+		FreeOnly: true,
+	}
+	slots, err := i.slotStore.List(booking.SpaceID, filter)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "ошибка получения списка слотов")
+	}
+	slots = i.excludeBusy(booking.SpaceID, slots)
+	for _, slot := range slots {
+		result.Slots = append(result.Slots, interviewapimodels.PublicInterviewView{
+			ID:       slot.ID,
+			StartAt:  slot.StartAt,
+			EndAt:    slot.EndAt,
+			Location: booking.Location,
+		})
+	}
+	return &result, "", nil
+}
+
+func (i impl) Book(id string, data interviewapimodels.PublicBookRequest) (hMsg string, err error) {
+	booking, hMsg, err := i.getBooking(id)
+	if err != nil || hMsg != "" {
+		return hMsg, err
+	}
+	if booking.InterviewID != nil {
+		return BookingAlreadyUsedMsg, nil
+	}
+	if booking.ExpiresAt.Before(time.Now()) {
+		return BookingExpiredMsg, nil
+	}
+	spaceID := booking.SpaceID
+	slot, err := i.slotStore.GetByID(spaceID, data.SlotID)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения слота")
+	}
+	if slot == nil || slot.InterviewID != nil || !slot.StartAt.After(time.Now()) || !slices.Contains(booking.UserIDs, slot.UserID) {
+		return SlotNotAvailableMsg, nil
+	}
+	participants := []string{slot.UserID}
+	hMsg, err = i.checkConflicts(spaceID, "", booking.ApplicantID, participants, slot.StartAt, slot.EndAt)
+	if err != nil {
+		return "", err
+	}
+	if hMsg != "" {
+		return SlotNotAvailableMsg, nil
+	}
+	rec := dbmodels.Interview{
+		BaseSpaceModel: dbmodels.BaseSpaceModel{
+			SpaceID: spaceID,
+		},
+		ApplicantID:      booking.ApplicantID,
+		VacancyID:        booking.VacancyID,
+		SelectionStageID: booking.SelectionStageID,
+		AuthorID:         booking.AuthorID,
+		StartAt:          slot.StartAt,
+		EndAt:            slot.EndAt,
+		Location:         booking.Location,
+		Status:           dbmodels.InterviewScheduled,
+	}
+	var interviewID string
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		store := interviewstore.NewInstance(tx)
+		id, err := store.Create(rec)
+		if err != nil {
+			return err
+		}
+		interviewID = id
+		err = store.SetParticipants(spaceID, interviewID, participants)
+		if err != nil {
+			return err
+		}
+		ok, err := interviewslotstore.NewInstance(tx).Book(spaceID, slot.ID, interviewID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errSlotNotAvailable
+		}
+		ok, err = interviewbookingstore.NewInstance(tx).SetInterview(booking.ID, interviewID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.New(BookingAlreadyUsedMsg)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, errSlotNotAvailable) {
+			return SlotNotAvailableMsg, nil
+		}
+		return "", errors.Wrap(err, "ошибка записи на интервью")
+	}
+	changes := applicanthistoryhandler.GetInterviewChange("Кандидат записался на интервью", i.formatTime(spaceID, rec.StartAt), rec.Location)
+	i.applicantHistory.Save(spaceID, booking.ApplicantID, booking.VacancyID, "", dbmodels.HistoryTypeInterview, changes)
+	go i.notify(spaceID, interviewID, icsMethodRequest, nil)
+	return "", nil
+}
+
+func (i impl) Remind(spaceID, id string) {
+	logger := i.getLogger(spaceID, id)
+	rec, err := i.store.GetByID(spaceID, id)
+	if err != nil {
+		logger.WithError(err).Error("ошибка получения интервью")
+		return
+	}
+	if rec == nil || rec.Status != dbmodels.InterviewScheduled {
+		return
+	}
+	// признак выставляется до отправки, чтобы не дублировать напоминания при ошибках отправки
+	err = i.store.Update(spaceID, id, map[string]interface{}{"reminder_sent": true})
+	if err != nil {
+		logger.WithError(err).Error("ошибка сохранения признака отправки напоминания")
+		return
+	}
+	applicantFIO, vacancyName := getNames(*rec)
+	startAt := i.formatTime(spaceID, rec.StartAt)
+	data := models.GetPushInterviewReminder(vacancyName, applicantFIO, startAt).WithEntity(rec.ApplicantID)
+	for _, participant := range rec.Participants {
+		pushhandler.Instance.SendNotification(participant.UserID, data)
+	}
+	if rec.Applicant == nil || rec.Applicant.Email == "" {
+		return
+	}
+	emailFrom, err := i.messageTemplate.GetSenderEmail(spaceID)
+	if err != nil || emailFrom == "" {
+		logger.WithError(err).Warn("напоминание кандидату не отправлено, не указана почта для отправки")
+		return
+	}
+	title := fmt.Sprintf("Напоминание об интервью: %v", vacancyName)
+	msg := getEmailMessage("Напоминаем о предстоящем интервью.", vacancyName, startAt, rec.Location)
+	err = smtp.Instance.SendHtmlEMail(emailFrom, rec.Applicant.Email, msg, title, nil)
+	if err != nil {
+		logger.WithError(err).Warn("ошибка отправки напоминания об интервью кандидату")
+	}
+}
+
+func (i impl) Reschedule(spaceID, id, userID string, startAt, endAt time.Time) (hMsg string, err error) {
+	if err = interviewapimodels.ValidatePeriod(startAt, endAt); err != nil {
+		return err.Error(), nil
+	}
+	rec, err := i.store.GetByID(spaceID, id)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения интервью")
+	}
+	if rec == nil {
+		return InterviewNotFoundMsg, nil
+	}
+	if rec.Status == dbmodels.InterviewCanceled {
+		return InterviewCanceledMsg, nil
+	}
+	if rec.StartAt.Equal(startAt) && rec.EndAt.Equal(endAt) {
+		return "", nil
+	}
+	updMap := map[string]interface{}{
+		"start_at": startAt,
+		"end_at":   endAt,
+		"sequence": rec.Sequence + 1,
+	}
+	if !rec.StartAt.Equal(startAt) {
+		updMap["reminder_sent"] = false
+	}
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		err := interviewstore.NewInstance(tx).Update(spaceID, id, updMap)
+		if err != nil {
+			return err
+		}
+		return interviewslotstore.NewInstance(tx).Release(spaceID, id)
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка переноса интервью")
+	}
+	changes := applicanthistoryhandler.GetInterviewChange("Интервью перенесено в календаре участника", i.formatTime(spaceID, startAt), rec.Location)
+	i.applicantHistory.Save(spaceID, rec.ApplicantID, rec.VacancyID, userID, dbmodels.HistoryTypeInterview, changes)
+	go i.notify(spaceID, id, icsMethodRequest, nil)
+	return "", nil
+}
+
+// getApplicant кандидат в области видимости пользователя
+func (i impl) getApplicant(spaceID, userID, applicantID string) (*dbmodels.ApplicantExt, string, error) {
+	scope, err := datascope.Instance.GetScope(spaceID, userID)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "ошибка получения области видимости")
+	}
+	visible, err := i.applicantStore.IsVisible(spaceID, applicantID, scope)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "ошибка получения кандидата")
+	}
+	if !visible {
+		return nil, "кандидат не найден", nil
+	}
+	applicant, err := i.applicantStore.GetByID(spaceID, applicantID)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "ошибка получения кандидата")
+	}
+	if applicant == nil {
+		return nil, "кандидат не найден", nil
+	}
+	return applicant, "", nil
+}
+
+// getStageID этап подбора интервью, по умолчанию - текущий этап кандидата
+func (i impl) getStageID(spaceID string, applicant dbmodels.Applicant, stageID string) (string, string, error) {
+	if stageID == "" {
+		return applicant.SelectionStageID, "", nil
+	}
+	stage, err := i.selectionStageStore.GetByID(spaceID, applicant.VacancyID, stageID)
+	if err != nil {
+		return "", "", errors.Wrap(err, "ошибка получения этапа подбора")
+	}
+	if stage == nil {
+		return "", "этап подбора не найден", nil
+	}
+	return stage.ID, "", nil
+}
+
+// checkParticipants участниками интервью могут быть только члены команды вакансии
+func (i impl) checkParticipants(spaceID, vacancyID string, userIDs []string) (string, error) {
+	for _, userID := range userIDs {
+		member, err := i.teamStore.GetByID(spaceID, vacancyID, userID)
+		if err != nil {
+			return "", errors.Wrap(err, "ошибка получения команды вакансии")
+		}
+		if member == nil {
+			return "участник интервью не состоит в команде вакансии", nil
+		}
+	}
+	return "", nil
+}
+
+// checkConflicts проверка, что у участников и кандидата нет других интервью в это время
+func (i impl) checkConflicts(spaceID, excludeID, applicantID string, userIDs []string, startAt, endAt time.Time) (string, error) {
+	list, err := i.store.ListConflicts(spaceID, excludeID, applicantID, userIDs, startAt, endAt)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка проверки пересечения интервью")
+	}
+	for _, rec := range list {
+		conflictTime := i.formatTime(spaceID, rec.StartAt)
+		if rec.ApplicantID == applicantID {
+			return fmt.Sprintf("у кандидата уже назначено интервью на %v", conflictTime), nil
+		}
+		for _, participant := range rec.Participants {
+			if !slices.Contains(userIDs, participant.UserID) {
+				continue
+			}
+			name := participant.UserID
+			if participant.SpaceUser != nil {
+				name = participant.SpaceUser.GetFullName()
+			}
+			return fmt.Sprintf("у участника %v уже назначено интервью на %v", name, conflictTime), nil
+		}
+	}
+	return "", nil
+}
+
+func (i impl) getBooking(id string) (*dbmodels.InterviewBooking, string, error) {
+	booking, err := i.bookingStore.GetByID(id)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "ошибка получения ссылки для записи на интервью")
+	}
+	if booking == nil {
+		return nil, BookingNotFoundMsg, nil
+	}
+	return booking, "", nil
+}
+
+// formatTime время в часовом поясе организации
+func (i impl) formatTime(spaceID string, t time.Time) string {
+	location := time.Local
+	space, err := i.spaceStore.GetByID(spaceID)
+	if err == nil && space != nil && space.TimeZone != "" {
+		if spaceLocation, err := time.LoadLocation(space.TimeZone); err == nil {
+			location = spaceLocation
+		}
+	}
+	return t.In(location).Format(timeFormat)
+}
+
+func uniqueIDs(ids []string) []string {
+	result := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id != "" && !slices.Contains(result, id) {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+func getNames(rec dbmodels.Interview) (applicantFIO, vacancyName string) {
+	if rec.Applicant != nil {
+		applicantFIO = rec.Applicant.GetFIO()
+	}
+	if rec.Vacancy != nil {
+		vacancyName = rec.Vacancy.VacancyName
+	}
+	return applicantFIO, vacancyName
+}
+
+// excludeBusy исключение слотов, пересекающихся с занятостью интервьюеров в подключенных календарях
+func (i impl) excludeBusy(spaceID string, slots []dbmodels.InterviewSlot) []dbmodels.InterviewSlot {
+	if len(slots) == 0 {
+		return slots
+	}
+	userIDs := []string{}
+	from, to := slots[0].StartAt, slots[0].EndAt
+	for _, slot := range slots {
+		if !slices.Contains(userIDs, slot.UserID) {
+			userIDs = append(userIDs, slot.UserID)
+		}
+		if slot.StartAt.Before(from) {
+			from = slot.StartAt
+		}
+		if slot.EndAt.After(to) {
+			to = slot.EndAt
+		}
+	}
+	busy, err := i.calendar.GetBusy(spaceID, userIDs, from, to)
+	if err != nil {
+		log.WithField("space_id", spaceID).WithError(err).Warn("ошибка получения занятости интервьюеров из календарей")
+		return slots
+	}
+	result := make([]dbmodels.InterviewSlot, 0, len(slots))
+	for _, slot := range slots {
+		isBusy := slices.ContainsFunc(busy, func(period calendarapimodels.BusyView) bool {
+			return period.UserID == slot.UserID && period.StartAt.Before(slot.EndAt) && period.EndAt.After(slot.StartAt)
+		})
+		if !isBusy {
+			result = append(result, slot)
+		}
+	}
+	return result
+}
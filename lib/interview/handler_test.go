@@ -0,0 +1,56 @@
+package interview
+
+import (
+	applicantstore "hr-tools-backend/lib/applicant/store"
+	datascopefake "hr-tools-backend/lib/data-scope/fake"
+	interviewstore "hr-tools-backend/lib/interview/store"
+	interviewapimodels "hr-tools-backend/models/api/interview"
+	dbmodels "hr-tools-backend/models/db"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeApplicantStore struct {
+	applicantstore.Provider
+}
+
+func (s fakeApplicantStore) IsVisible(spaceID, id string, scope *dbmodels.DataScope) (bool, error) {
+	return false, nil
+}
+
+type fakeStore struct {
+	interviewstore.Provider
+	scope *dbmodels.DataScope
+}
+
+func (s *fakeStore) ListCount(spaceID string, scope *dbmodels.DataScope, filter interviewapimodels.InterviewFilter) (int64, error) {
+	s.scope = scope
+	return 0, nil
+}
+
+func (s *fakeStore) List(spaceID string, scope *dbmodels.DataScope, filter interviewapimodels.InterviewFilter) ([]dbmodels.Interview, error) {
+	return []dbmodels.Interview{}, nil
+}
+
+func TestInterviewOutsideScope(t *testing.T) {
+	datascopefake.Set(t, datascopefake.DataScope{})
+	store := &fakeStore{}
+	i := impl{
+		store:          store,
+		applicantStore: fakeApplicantStore{},
+	}
+
+	_, hMsg, err := i.Create("space", "user", interviewapimodels.CreateInterviewRequest{ApplicantID: "other"})
+	require.NoError(t, err)
+	require.Equal(t, "кандидат не найден", hMsg)
+
+	_, hMsg, err = i.CreateBooking("space", "user", interviewapimodels.BookingRequest{ApplicantID: "other"})
+	require.NoError(t, err)
+	require.Equal(t, "кандидат не найден", hMsg)
+
+	list, _, err := i.List("space", "user", interviewapimodels.InterviewFilter{})
+	require.NoError(t, err)
+	require.Empty(t, list)
+	require.Equal(t, "user", store.scope.UserID)
+}
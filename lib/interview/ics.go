@@ -0,0 +1,108 @@
+package interview
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	icsMethodRequest = "REQUEST"
+	icsMethodCancel  = "CANCEL"
+
+	icsTimeFormat = "20060102T150405Z"
+	icsProductID  = "-//HR-Tools//Interview//RU"
+	icsMaxLineLen = 75
+)
+
+// ICSEvent данные приглашения в календарь
+type ICSEvent struct {
+	UID         string
+	Sequence    int
+	Method      string // REQUEST - приглашение/изменение, CANCEL - отмена, пусто - событие для размещения в календаре (CalDAV)
+	StartAt     time.Time
+	EndAt       time.Time
+	Summary     string
+	Description string
+	Location    string
+	Organizer   string   // email организатора
+	Attendees   []string // email участников
+}
+
+// BuildICS формирование приглашения в формате iCalendar (RFC 5545)
+func BuildICS(event ICSEvent, now time.Time) []byte {
+	status := "CONFIRMED"
+	if event.Method == icsMethodCancel {
+		status = "CANCELLED"
+	}
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:" + icsProductID,
+		"CALSCALE:GREGORIAN",
+	}
+	if event.Method != "" {
+		lines = append(lines, "METHOD:"+event.Method)
+	}
+	lines = append(lines,
+		"BEGIN:VEVENT",
+		"UID:"+event.UID,
+		fmt.Sprintf("SEQUENCE:%d", event.Sequence),
+		"DTSTAMP:"+now.UTC().Format(icsTimeFormat),
+		"DTSTART:"+event.StartAt.UTC().Format(icsTimeFormat),
+		"DTEND:"+event.EndAt.UTC().Format(icsTimeFormat),
+		"SUMMARY:"+escapeICSText(event.Summary),
+		"STATUS:"+status,
+	)
+	if event.Description != "" {
+		lines = append(lines, "DESCRIPTION:"+escapeICSText(event.Description))
+	}
+	if event.Location != "" {
+		lines = append(lines, "LOCATION:"+escapeICSText(event.Location))
+	}
+	if event.Organizer != "" {
+		lines = append(lines, "ORGANIZER:mailto:"+event.Organizer)
+	}
+	for _, email := range event.Attendees {
+		lines = append(lines, "ATTENDEE;ROLE=REQ-PARTICIPANT;RSVP=TRUE:mailto:"+email)
+	}
+	lines = append(lines, "END:VEVENT", "END:VCALENDAR")
+
+	sb := strings.Builder{}
+	for _, line := range lines {
+		sb.WriteString(foldICSLine(line))
+		sb.WriteString("\r\n")
+	}
+	return []byte(sb.String())
+}
+
+func escapeICSText(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(text)
+}
+
+// foldICSLine перенос строк длиннее 75 байт, без разрыва многобайтовых символов
+func foldICSLine(line string) string {
+	if len(line) <= icsMaxLineLen {
+		return line
+	}
+	sb := strings.Builder{}
+	lineLen := 0
+	for _, r := range line {
+		size := utf8.RuneLen(r)
+		if lineLen+size > icsMaxLineLen {
+			sb.WriteString("\r\n ")
+			// пробел в начале строки продолжения учитывается в длине
+			lineLen = 1
+		}
+		sb.WriteRune(r)
+		lineLen += size
+	}
+	return sb.String()
+}
@@ -0,0 +1,82 @@
+package interview
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildICS(t *testing.T) {
+	msk := time.FixedZone("MSK", 3*60*60)
+	event := ICSEvent{
+		UID:         "id@hr-tools",
+		Sequence:    2,
+		Method:      icsMethodRequest,
+		StartAt:     time.Date(2024, 5, 10, 12, 0, 0, 0, msk),
+		EndAt:       time.Date(2024, 5, 10, 13, 0, 0, 0, msk),
+		Summary:     "Интервью: Go; backend, senior",
+		Description: "строка 1\nстрока 2",
+		Location:    "Москва",
+		Organizer:   "hr@example.com",
+		Attendees:   []string{"a@example.com", "b@example.com"},
+	}
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	t.Run("request", func(t *testing.T) {
+		body := string(BuildICS(event, now))
+		require.True(t, strings.HasPrefix(body, "BEGIN:VCALENDAR\r\n"))
+		require.True(t, strings.HasSuffix(body, "END:VEVENT\r\nEND:VCALENDAR\r\n"))
+		require.Contains(t, body, "METHOD:REQUEST\r\n")
+		require.Contains(t, body, "SEQUENCE:2\r\n")
+		require.Contains(t, body, "DTSTAMP:20240501T090000Z\r\n")
+		require.Contains(t, body, "DTSTART:20240510T090000Z\r\n")
+		require.Contains(t, body, "DTEND:20240510T100000Z\r\n")
+		require.Contains(t, body, "STATUS:CONFIRMED\r\n")
+		require.Contains(t, body, `DESCRIPTION:строка 1\nстрока 2`)
+		require.Contains(t, body, "ORGANIZER:mailto:hr@example.com\r\n")
+		require.Contains(t, body, "ATTENDEE;ROLE=REQ-PARTICIPANT;RSVP=TRUE:mailto:b@example.com\r\n")
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		cancelEvent := event
+		cancelEvent.Method = icsMethodCancel
+		cancelEvent.Description = ""
+		body := string(BuildICS(cancelEvent, now))
+		require.Contains(t, body, "METHOD:CANCEL\r\n")
+		require.Contains(t, body, "STATUS:CANCELLED\r\n")
+		require.NotContains(t, body, "DESCRIPTION:")
+	})
+
+	t.Run("calendar", func(t *testing.T) {
+		calendarEvent := event
+		calendarEvent.Method = ""
+		body := string(BuildICS(calendarEvent, now))
+		require.NotContains(t, body, "METHOD:")
+		require.Contains(t, body, "STATUS:CONFIRMED\r\n")
+	})
+}
+
+func TestEscapeICSText(t *testing.T) {
+	require.Equal(t, `a\;b\,c\\d\ne`, escapeICSText("a;b,c\\d\r\ne"))
+}
+
+func TestFoldICSLine(t *testing.T) {
+	short := "SUMMARY:short"
+	require.Equal(t, short, foldICSLine(short))
+
+	long := "SUMMARY:" + strings.Repeat("интервью ", 20)
+	folded := foldICSLine(long)
+	parts := strings.Split(folded, "\r\n")
+	require.Greater(t, len(parts), 1)
+	for k, part := range parts {
+		require.LessOrEqual(t, len(part), icsMaxLineLen)
+		require.True(t, utf8.ValidString(part))
+		if k > 0 {
+			require.True(t, strings.HasPrefix(part, " "))
+		}
+	}
+	require.Equal(t, long, strings.ReplaceAll(folded, "\r\n ", ""))
+}
@@ -0,0 +1,185 @@
+package interview
+
+import (
+	"fmt"
+	"hr-tools-backend/lib/calendar"
+	"hr-tools-backend/lib/smtp"
+	pushhandler "hr-tools-backend/lib/space/push/handler"
+	"hr-tools-backend/models"
+	dbmodels "hr-tools-backend/models/db"
+	"html"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type recipient struct {
+	userID string // пусто для кандидата
+	email  string
+}
+
+// notify уведомление об интервью: пуш участникам, приглашение в календарь (ics) участникам и кандидату на email,
+// removedUserIDs - исключенные из интервью участники, им отправляется отмена
+func (i impl) notify(spaceID, id, method string, removedUserIDs []string) {
+	logger := i.getLogger(spaceID, id)
+	rec, err := i.store.GetByID(spaceID, id)
+	if err != nil {
+		logger.WithError(err).Error("ошибка получения интервью для отправки уведомлений")
+		return
+	}
+	if rec == nil {
+		return
+	}
+	applicantFIO, vacancyName := getNames(*rec)
+	startAt := i.formatTime(spaceID, rec.StartAt)
+
+	recipients := make([]recipient, 0, len(rec.Participants)+1)
+	if rec.Applicant != nil && rec.Applicant.Email != "" {
+		recipients = append(recipients, recipient{email: rec.Applicant.Email})
+	}
+	for _, participant := range rec.Participants {
+		item := recipient{userID: participant.UserID}
+		if participant.SpaceUser != nil {
+			item.email = participant.SpaceUser.Email
+		}
+		recipients = append(recipients, item)
+	}
+	removed := make([]recipient, 0, len(removedUserIDs))
+	for _, userID := range removedUserIDs {
+		item := recipient{userID: userID}
+		user, err := i.spaceUserStore.GetByID(userID)
+		if err != nil {
+			logger.WithError(err).Warn("ошибка получения пользователя для отправки отмены интервью")
+		} else if user != nil {
+			item.email = user.Email
+		}
+		removed = append(removed, item)
+	}
+
+	data := models.GetPushInterviewScheduled(vacancyName, applicantFIO, startAt).WithEntity(rec.ApplicantID)
+	if method == icsMethodCancel {
+		data = models.GetPushInterviewCanceled(vacancyName, applicantFIO, startAt).WithEntity(rec.ApplicantID)
+	}
+	for _, item := range recipients {
+		if item.userID != "" {
+			pushhandler.Instance.SendNotification(item.userID, data)
+		}
+	}
+	canceledData := models.GetPushInterviewCanceled(vacancyName, applicantFIO, startAt).WithEntity(rec.ApplicantID)
+	for _, item := range removed {
+		pushhandler.Instance.SendNotification(item.userID, canceledData)
+	}
+	summary := fmt.Sprintf("Интервью: %v - %v", vacancyName, applicantFIO)
+	i.pushToCalendars(*rec, method, summary)
+
+	if !smtp.Instance.IsConfigured() {
+		return
+	}
+	emailFrom, err := i.messageTemplate.GetSenderEmail(spaceID)
+	if err != nil {
+		logger.WithError(err).Warn("ошибка получения почты компании для отправки приглашения на интервью")
+		return
+	}
+	if emailFrom == "" {
+		logger.Warn("приглашение на интервью не отправлено, не указана почта для отправки")
+		return
+	}
+	event := ICSEvent{
+		UID:         getEventUID(rec.ID),
+		Sequence:    rec.Sequence,
+		Method:      method,
+		StartAt:     rec.StartAt,
+		EndAt:       rec.EndAt,
+		Summary:     summary,
+		Description: rec.Comment,
+		Location:    rec.Location,
+		Organizer:   emailFrom,
+		Attendees:   getEmails(recipients),
+	}
+	title := fmt.Sprintf("Приглашение на интервью: %v", vacancyName)
+	text := "Приглашаем вас на интервью."
+	if method == icsMethodCancel {
+		title = fmt.Sprintf("Интервью отменено: %v", vacancyName)
+		text = "Интервью отменено."
+	} else if rec.Sequence != 0 {
+		title = fmt.Sprintf("Интервью изменено: %v", vacancyName)
+		text = "Время или место интервью изменено."
+	}
+	i.sendInvites(logger, emailFrom, recipients, event, title, getEmailMessage(text, vacancyName, startAt, rec.Location))
+
+	if len(removed) != 0 {
+		event.Method = icsMethodCancel
+		event.Attendees = getEmails(removed)
+		title = fmt.Sprintf("Интервью отменено: %v", vacancyName)
+		i.sendInvites(logger, emailFrom, removed, event, title, getEmailMessage("Вы исключены из участников интервью.", vacancyName, startAt, rec.Location))
+	}
+}
+
+// pushToCalendars размещение события интервью в подключенных календарях участников,
+// без организатора и участников, чтобы сервер календаря не рассылал собственные приглашения
+func (i impl) pushToCalendars(rec dbmodels.Interview, method, summary string) {
+	event := ICSEvent{
+		UID:         getEventUID(rec.ID),
+		Sequence:    rec.Sequence,
+		StartAt:     rec.StartAt,
+		EndAt:       rec.EndAt,
+		Summary:     summary,
+		Description: rec.Comment,
+		Location:    rec.Location,
+	}
+	userIDs := make([]string, 0, len(rec.Participants))
+	for _, participant := range rec.Participants {
+		userIDs = append(userIDs, participant.UserID)
+	}
+	i.calendar.PushInterview(rec.SpaceID, calendar.InterviewEvent{
+		InterviewID: rec.ID,
+		UID:         event.UID,
+		UserIDs:     userIDs,
+		StartAt:     rec.StartAt,
+		EndAt:       rec.EndAt,
+		Canceled:    method == icsMethodCancel,
+		Data:        BuildICS(event, time.Now()),
+	})
+}
+
+func (i impl) sendInvites(logger *log.Entry, emailFrom string, recipients []recipient, event ICSEvent, title, msg string) {
+	attachment := &models.File{
+		FileName:    "invite.ics",
+		ContentType: fmt.Sprintf("text/calendar; charset=utf-8; method=%v", event.Method),
+		Body:        BuildICS(event, time.Now()),
+	}
+	for _, item := range recipients {
+		if item.email == "" {
+			continue
+		}
+		err := smtp.Instance.SendHtmlEMail(emailFrom, item.email, msg, title, attachment)
+		if err != nil {
+			logger.
+				WithError(err).
+				WithField("email", item.email).
+				Warn("ошибка отправки приглашения на интервью")
+		}
+	}
+}
+
+func getEventUID(interviewID string) string {
+	return interviewID + "@hr-tools"
+}
+
+func getEmails(recipients []recipient) []string {
+	result := make([]string, 0, len(recipients))
+	for _, item := range recipients {
+		if item.email != "" {
+			result = append(result, item.email)
+		}
+	}
+	return result
+}
+
+func getEmailMessage(text, vacancyName, startAt, location string) string {
+	msg := fmt.Sprintf("<p>%v</p><p>Вакансия: %v<br>Время: %v", html.EscapeString(text), html.EscapeString(vacancyName), startAt)
+	if location != "" {
+		msg += fmt.Sprintf("<br>Место проведения: %v", html.EscapeString(location))
+	}
+	return msg + "</p>"
+}
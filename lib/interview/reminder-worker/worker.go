@@ -0,0 +1,42 @@
+package interviewreminderworker
+
+import (
+	"context"
+	"hr-tools-backend/config"
+	"hr-tools-backend/db"
+	"hr-tools-backend/lib/interview"
+	interviewstore "hr-tools-backend/lib/interview/store"
+	baseworker "hr-tools-backend/lib/utils/base-worker"
+	"hr-tools-backend/lib/utils/helpers"
+	"time"
+)
+
+// Задача отправки напоминаний о предстоящих интервью
+func StartWorker(ctx context.Context) {
+	i := &impl{
+		BaseImpl: *baseworker.NewInstance("InterviewReminderWorker", 30*time.Second, time.Minute),
+		store:    interviewstore.NewInstance(db.DB),
+	}
+	go i.Run(ctx, i.handle)
+}
+
+type impl struct {
+	baseworker.BaseImpl
+	store interviewstore.Provider
+}
+
+func (i impl) handle(ctx context.Context) {
+	logger := i.GetLogger()
+	before := time.Now().Add(time.Duration(config.Conf.Interview.ReminderBeforeMin) * time.Minute)
+	list, err := i.store.ListForReminder(before)
+	if err != nil {
+		logger.WithError(err).Error("ошибка получения списка интервью для напоминания")
+		return
+	}
+	for _, rec := range list {
+		if helpers.IsContextDone(ctx) {
+			break
+		}
+		interview.Instance.Remind(rec.SpaceID, rec.ID)
+	}
+}
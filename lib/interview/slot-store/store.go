@@ -0,0 +1,131 @@
+package interviewslotstore
+
+import (
+	dbmodels "hr-tools-backend/models/db"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Provider interface {
+	Create(rec dbmodels.InterviewSlot) (id string, err error)
+	GetByID(spaceID, id string) (*dbmodels.InterviewSlot, error)
+	Delete(spaceID, id string) error
+	List(spaceID string, filter dbmodels.InterviewSlotFilter) ([]dbmodels.InterviewSlot, error)
+	HasOverlap(spaceID, userID string, startAt, endAt time.Time) (bool, error)
+	// Book занять свободный слот интервью, false - слот уже занят
+	Book(spaceID, id, interviewID string) (bool, error)
+	// Release освободить слоты, занятые интервью
+	Release(spaceID, interviewID string) error
+}
+
+func NewInstance(DB *gorm.DB) Provider {
+	return &impl{
+		db: DB,
+	}
+}
+
+type impl struct {
+	db *gorm.DB
+}
+
+func (i impl) Create(rec dbmodels.InterviewSlot) (id string, err error) {
+	err = i.db.
+		Omit(clause.Associations).
+		Save(&rec).
+		Error
+	if err != nil {
+		return "", err
+	}
+	return rec.ID, nil
+}
+
+func (i impl) GetByID(spaceID, id string) (*dbmodels.InterviewSlot, error) {
+	rec := dbmodels.InterviewSlot{}
+	err := i.db.
+		Where("space_id = ?", spaceID).
+		Where("id = ?", id).
+		Preload(clause.Associations).
+		First(&rec).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (i impl) Delete(spaceID, id string) error {
+	return i.db.
+		Where("space_id = ?", spaceID).
+		Where("id = ?", id).
+		Delete(&dbmodels.InterviewSlot{}).
+		Error
+}
+
+func (i impl) List(spaceID string, filter dbmodels.InterviewSlotFilter) ([]dbmodels.InterviewSlot, error) {
+	list := []dbmodels.InterviewSlot{}
+	tx := i.db.
+		Model(&dbmodels.InterviewSlot{}).
+		Where("space_id = ?", spaceID)
+	if len(filter.UserIDs) != 0 {
+		tx.Where("user_id in (?)", filter.UserIDs)
+	}
+	if !filter.From.IsZero() {
+		tx.Where("start_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		tx.Where("start_at <= ?", filter.To)
+	}
+	if filter.FreeOnly {
+		tx.Where("interview_id is null")
+	}
+	err := tx.
+		Order("start_at").
+		Preload(clause.Associations).
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (i impl) HasOverlap(spaceID, userID string, startAt, endAt time.Time) (bool, error) {
+	var exists bool
+	err := i.db.
+		Model(&dbmodels.InterviewSlot{}).
+		Select("count(*) > 0").
+		Where("space_id = ?", spaceID).
+		Where("user_id = ?", userID).
+		Where("start_at < ? and end_at > ?", endAt, startAt).
+		Find(&exists).
+		Error
+	return exists, err
+}
+
+func (i impl) Book(spaceID, id, interviewID string) (bool, error) {
+	tx := i.db.
+		Model(&dbmodels.InterviewSlot{}).
+		Where("space_id = ?", spaceID).
+		Where("id = ?", id).
+		Where("interview_id is null").
+		Update("interview_id", interviewID)
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+	return tx.RowsAffected != 0, nil
+}
+
+func (i impl) Release(spaceID, interviewID string) error {
+	return i.db.
+		Model(&dbmodels.InterviewSlot{}).
+		Where("space_id = ?", spaceID).
+		Where("interview_id = ?", interviewID).
+		Update("interview_id", nil).
+		Error
+}
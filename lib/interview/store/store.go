@@ -0,0 +1,252 @@
+package interviewstore
+
+import (
+	interviewapimodels "hr-tools-backend/models/api/interview"
+	dbmodels "hr-tools-backend/models/db"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Provider interface {
+	Create(rec dbmodels.Interview) (id string, err error)
+	Update(spaceID, id string, updMap map[string]interface{}) error
+	SetParticipants(spaceID, id string, userIDs []string) error
+	GetByID(spaceID, id string) (*dbmodels.Interview, error)
+	// IsVisible интервью видно участнику и пользователям, которым виден кандидат
+	IsVisible(spaceID, id string, scope *dbmodels.DataScope) (bool, error)
+	List(spaceID string, scope *dbmodels.DataScope, filter interviewapimodels.InterviewFilter) ([]dbmodels.Interview, error)
+	ListCount(spaceID string, scope *dbmodels.DataScope, filter interviewapimodels.InterviewFilter) (int64, error)
+	// ListConflicts назначенные интервью участников или кандидата, пересекающиеся с периодом
+	ListConflicts(spaceID, excludeID, applicantID string, userIDs []string, startAt, endAt time.Time) ([]dbmodels.Interview, error)
+	ListForReminder(before time.Time) ([]dbmodels.Interview, error)
+	// ListByApplicant все интервью кандидата без учета прав и пагинации (выгрузка ПД)
+	ListByApplicant(spaceID, applicantID string) ([]dbmodels.Interview, error)
+}
+
+func NewInstance(DB *gorm.DB) Provider {
+	return &impl{
+		db: DB,
+	}
+}
+
+type impl struct {
+	db *gorm.DB
+}
+
+func (i impl) Create(rec dbmodels.Interview) (id string, err error) {
+	err = i.db.
+		Omit(clause.Associations).
+		Save(&rec).
+		Error
+	if err != nil {
+		return "", err
+	}
+	return rec.ID, nil
+}
+
+func (i impl) Update(spaceID, id string, updMap map[string]interface{}) error {
+	if len(updMap) == 0 {
+		return nil
+	}
+	tx := i.db.
+		Model(&dbmodels.Interview{}).
+		Where("space_id = ?", spaceID).
+		Where("id = ?", id).
+		Updates(updMap)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return errors.New("интервью не найдено")
+	}
+	return nil
+}
+
+func (i impl) SetParticipants(spaceID, id string, userIDs []string) error {
+	err := i.db.
+		Where("space_id = ?", spaceID).
+		Where("interview_id = ?", id).
+		Delete(&dbmodels.InterviewParticipant{}).
+		Error
+	if err != nil {
+		return err
+	}
+	for _, userID := range userIDs {
+		rec := dbmodels.InterviewParticipant{
+			BaseSpaceModel: dbmodels.BaseSpaceModel{
+				SpaceID: spaceID,
+			},
+			InterviewID: id,
+			UserID:      userID,
+		}
+		err = i.db.
+			Omit(clause.Associations).
+			Save(&rec).
+			Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i impl) GetByID(spaceID, id string) (*dbmodels.Interview, error) {
+	rec := dbmodels.Interview{}
+	err := i.db.
+		Where("space_id = ?", spaceID).
+		Where("id = ?", id).
+		Preload(clause.Associations).
+		Preload("Participants.SpaceUser").
+		First(&rec).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (i impl) IsVisible(spaceID, id string, scope *dbmodels.DataScope) (bool, error) {
+	var exists bool
+	tx := i.db.
+		Model(&dbmodels.Interview{}).
+		Select("count(*) > 0").
+		Where("id = ?", id).
+		Where("space_id = ?", spaceID)
+	i.addScope(tx, scope)
+	err := tx.Find(&exists).Error
+	return exists, err
+}
+
+func (i impl) List(spaceID string, scope *dbmodels.DataScope, filter interviewapimodels.InterviewFilter) ([]dbmodels.Interview, error) {
+	list := []dbmodels.Interview{}
+	tx := i.db.
+		Model(&dbmodels.Interview{}).
+		Where("space_id = ?", spaceID)
+	i.addScope(tx, scope)
+	i.addFilter(tx, filter)
+	page, limit := filter.GetPage()
+	err := tx.
+		Order("start_at").
+		Limit(limit).
+		Offset((page - 1) * limit).
+		Preload(clause.Associations).
+		Preload("Participants.SpaceUser").
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (i impl) ListByApplicant(spaceID, applicantID string) ([]dbmodels.Interview, error) {
+	list := []dbmodels.Interview{}
+	err := i.db.
+		Where("space_id = ?", spaceID).
+		Where("applicant_id = ?", applicantID).
+		Order("start_at").
+		Preload(clause.Associations).
+		Preload("Participants.SpaceUser").
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (i impl) ListCount(spaceID string, scope *dbmodels.DataScope, filter interviewapimodels.InterviewFilter) (int64, error) {
+	var count int64
+	tx := i.db.
+		Model(&dbmodels.Interview{}).
+		Where("space_id = ?", spaceID)
+	i.addScope(tx, scope)
+	i.addFilter(tx, filter)
+	err := tx.Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (i impl) ListConflicts(spaceID, excludeID, applicantID string, userIDs []string, startAt, endAt time.Time) ([]dbmodels.Interview, error) {
+	list := []dbmodels.Interview{}
+	participants := i.db.
+		Model(&dbmodels.InterviewParticipant{}).
+		Select("interview_id").
+		Where("space_id = ?", spaceID).
+		Where("user_id in (?)", userIDs)
+	tx := i.db.
+		Model(&dbmodels.Interview{}).
+		Where("space_id = ?", spaceID).
+		Where("status = ?", dbmodels.InterviewScheduled).
+		Where("start_at < ? and end_at > ?", endAt, startAt).
+		Where(i.db.Where("applicant_id = ?", applicantID).Or("id in (?)", participants))
+	if excludeID != "" {
+		tx.Where("id <> ?", excludeID)
+	}
+	err := tx.
+		Preload("Participants.SpaceUser").
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (i impl) ListForReminder(before time.Time) ([]dbmodels.Interview, error) {
+	list := []dbmodels.Interview{}
+	err := i.db.
+		Model(&dbmodels.Interview{}).
+		Where("status = ?", dbmodels.InterviewScheduled).
+		Where("reminder_sent = ?", false).
+		Where("start_at > ?", time.Now()).
+		Where("start_at <= ?", before).
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (i impl) addScope(tx *gorm.DB, scope *dbmodels.DataScope) {
+	query, args := scope.ApplicantRefCondition("interviews.applicant_id")
+	if query == "" {
+		return
+	}
+	participantQuery := "EXISTS (SELECT 1 FROM interview_participants ip_scope WHERE ip_scope.interview_id = interviews.id AND ip_scope.user_id = @scope_user)"
+	tx.Where("("+query+" OR "+participantQuery+")", args)
+}
+
+func (i impl) addFilter(tx *gorm.DB, filter interviewapimodels.InterviewFilter) {
+	if filter.ApplicantID != "" {
+		tx.Where("applicant_id = ?", filter.ApplicantID)
+	}
+	if filter.VacancyID != "" {
+		tx.Where("vacancy_id = ?", filter.VacancyID)
+	}
+	if filter.UserID != "" {
+		participants := i.db.
+			Model(&dbmodels.InterviewParticipant{}).
+			Select("interview_id").
+			Where("user_id = ?", filter.UserID)
+		tx.Where("id in (?)", participants)
+	}
+	if filter.DateFrom != nil {
+		tx.Where("start_at >= ?", *filter.DateFrom)
+	}
+	if filter.DateTo != nil {
+		tx.Where("start_at <= ?", *filter.DateTo)
+	}
+	if filter.Status != "" {
+		tx.Where("status = ?", filter.Status)
+	}
+}
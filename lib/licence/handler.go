@@ -2,6 +2,7 @@ package licencehandler
 
 import (
 	"hr-tools-backend/db"
+	auditlog "hr-tools-backend/lib/audit-log"
 	licensepaymentstore "hr-tools-backend/lib/licence/payment-store"
 	licenseplanstore "hr-tools-backend/lib/licence/plan-store"
 	licensestore "hr-tools-backend/lib/licence/store"
@@ -152,6 +153,11 @@ func (i *impl) ConfirmPayment(reqest licenseapimodels.LicenseRenewConfirm, userI
 		WithField("payment_id", payRec.ID).
 		WithField("license_id", payRec.LicenseID)
 
+	endAt := time.Now()
+	if licRec.EndsAt != nil {
+		endAt = *licRec.EndsAt
+	}
+	endAt = endAt.Add(time.Hour * 24 * time.Duration(planRec.ExtensionPeriodDays))
 	err = db.DB.Transaction(func(tx *gorm.DB) error {
 		licensePaymentStore := licensepaymentstore.NewInstance(tx)
 		licenseStore := licensestore.NewInstance(db.DB)
@@ -165,11 +171,6 @@ func (i *impl) ConfirmPayment(reqest licenseapimodels.LicenseRenewConfirm, userI
 		if err != nil {
 			return err
 		}
-		endAt := time.Now()
-		if licRec.EndsAt != nil {
-			endAt = *licRec.EndsAt
-		}
-		endAt = endAt.Add(time.Hour * 24 * time.Duration(planRec.ExtensionPeriodDays))
 		licUpdMap := map[string]interface{}{
 			"Status": models.LicenseStatusActive,
 			"EndsAt": endAt,
@@ -180,5 +181,10 @@ func (i *impl) ConfirmPayment(reqest licenseapimodels.LicenseRenewConfirm, userI
 		return "", err
 	}
 	logger.Info("Платеж подтвержден администратором")
+	// подтверждение выполняет администратор платформы, а не пользователь спейса
+	auditlog.Instance.SaveChanges(licRec.SpaceID, "", models.AuditActionUpdate, models.AuditEntityLicense, licRec.ID,
+		"Лицензия продлена, платеж подтвержден администратором платформы",
+		map[string]any{"status": licRec.Status, "ends_at": licRec.EndsAt},
+		map[string]any{"status": models.LicenseStatusActive, "ends_at": &endAt})
 	return "", nil
 }
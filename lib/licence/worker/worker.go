@@ -3,6 +3,7 @@ package licenseworker
 import (
 	"context"
 	"hr-tools-backend/db"
+	auditlog "hr-tools-backend/lib/audit-log"
 	licensestore "hr-tools-backend/lib/licence/store"
 	baseworker "hr-tools-backend/lib/utils/base-worker"
 	"hr-tools-backend/lib/utils/helpers"
@@ -55,5 +56,7 @@ func (i impl) updateStatuses(ctx context.Context, expireTime time.Time, currentS
 				Errorf("Ошибка перевода статуса лицензии в %v", newStatus)
 			continue
 		}
+		auditlog.Instance.SaveChanges(licence.SpaceID, "", models.AuditActionUpdate, models.AuditEntityLicense, licence.ID,
+			"Изменен статус лицензии по сроку действия", map[string]any{"status": currentStatus}, map[string]any{"status": newStatus})
 	}
 }
@@ -10,7 +10,11 @@ import (
 	"hr-tools-backend/db"
 	applicanthistoryhandler "hr-tools-backend/lib/applicant-history"
 	applicantstore "hr-tools-backend/lib/applicant/store"
+	auditlog "hr-tools-backend/lib/audit-log"
+	customfield "hr-tools-backend/lib/custom-field"
+	datascope "hr-tools-backend/lib/data-scope"
 	pdfexport "hr-tools-backend/lib/export/pdf"
+	externalservices "hr-tools-backend/lib/external-services"
 	filestorage "hr-tools-backend/lib/file-storage"
 	messagetemplatestore "hr-tools-backend/lib/message-template/store"
 	"hr-tools-backend/lib/smtp"
@@ -29,12 +33,16 @@ type Provider interface {
 	SendEmailMessage(ctx context.Context, spaceID, templateID, applicantID, userID string) (hMsg string, err error)
 	GetListTemplates(spaceID string) (list []msgtemplateapimodels.MsgTemplateView, err error)
 	MultiSendEmail(ctx context.Context, spaceID, userID string, data applicantapimodels.MultiEmailRequest) (failMails []string, hMsg string, err error)
-	Create(spaceID string, request msgtemplateapimodels.MsgTemplateData) (string, error)
+	Create(spaceID, userID string, request msgtemplateapimodels.MsgTemplateData) (string, error)
 	GetByID(spaceID, id string) (msgtemplateapimodels.MsgTemplateView, error)
-	Update(spaceID, id string, request msgtemplateapimodels.MsgTemplateData) error
-	Delete(spaceID, id string) error
+	Update(spaceID, id, userID string, request msgtemplateapimodels.MsgTemplateData) error
+	Delete(spaceID, id, userID string) error
 	PdfPreview(ctx context.Context, spaceID, tplID, userID string) (body []byte, hMsg string, err error)
+	// BuildOfferPdf формирование pdf оффера кандидату по шаблону с типом "Оффер"
+	BuildOfferPdf(ctx context.Context, spaceID, tplID, applicantID, userID string, offer models.OfferTemplateData) (body []byte, hMsg string, err error)
 	GetSenderEmail(spaceID string) (string, error)
+	// GetVariables переменные шаблона, включая пользовательские поля кандидата и вакансии
+	GetVariables(spaceID string) ([]msgtemplateapimodels.TemplateItem, error)
 }
 
 var Instance Provider
@@ -202,7 +210,11 @@ func (i impl) MultiSendEmail(ctx context.Context, spaceID, userID string, data a
 		textSign = user.TextSign
 	}
 
-	applicantList, err := i.applicantStore.ListOfApplicantByIDs(spaceID, data.IDs, nil)
+	scope, err := datascope.Instance.GetScope(spaceID, userID)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "ошибка получения области видимости")
+	}
+	applicantList, err := i.applicantStore.ListOfApplicantByIDs(spaceID, scope, data.IDs, nil)
 	if err != nil {
 		return nil, "", errors.Wrap(err, "ошибка получения списка кандидатов")
 	}
@@ -260,7 +272,7 @@ func (i impl) MultiSendEmail(ctx context.Context, spaceID, userID string, data a
 	return failMails, "", nil
 }
 
-func (i impl) Create(spaceID string, request msgtemplateapimodels.MsgTemplateData) (string, error) {
+func (i impl) Create(spaceID, userID string, request msgtemplateapimodels.MsgTemplateData) (string, error) {
 	rec := dbmodels.MessageTemplate{
 		BaseSpaceModel: dbmodels.BaseSpaceModel{
 			SpaceID: spaceID,
@@ -271,7 +283,13 @@ func (i impl) Create(spaceID string, request msgtemplateapimodels.MsgTemplateDat
 		TemplateType: request.TemplateType,
 		PdfMessage:   request.PdfMessage,
 	}
-	return i.msgTemplateStore.Create(rec)
+	id, err := i.msgTemplateStore.Create(rec)
+	if err != nil {
+		return "", err
+	}
+	auditlog.Instance.SaveChanges(spaceID, userID, models.AuditActionCreate, models.AuditEntityMsgTemplate, id,
+		"Создан шаблон сообщения", nil, request)
+	return id, nil
 }
 
 func (i impl) GetByID(spaceID, id string) (msgtemplateapimodels.MsgTemplateView, error) {
@@ -282,7 +300,14 @@ func (i impl) GetByID(spaceID, id string) (msgtemplateapimodels.MsgTemplateView,
 	return rec.ToModel(), nil
 }
 
-func (i impl) Update(spaceID, id string, request msgtemplateapimodels.MsgTemplateData) error {
+func (i impl) Update(spaceID, id, userID string, request msgtemplateapimodels.MsgTemplateData) error {
+	rec, err := i.msgTemplateStore.GetByID(spaceID, id)
+	if err != nil {
+		return errors.Wrap(err, "ошибка получения шаблона")
+	}
+	if rec == nil {
+		return errors.New("шаблон не найден")
+	}
 	updMap := map[string]interface{}{
 		"name":          request.Name,
 		"title":         request.Title,
@@ -290,18 +315,56 @@ func (i impl) Update(spaceID, id string, request msgtemplateapimodels.MsgTemplat
 		"template_type": request.TemplateType,
 		"pdf_message":   request.PdfMessage,
 	}
-	err := i.msgTemplateStore.Update(id, updMap)
+	err = i.msgTemplateStore.Update(id, updMap)
 	if err != nil {
 		return err
 	}
+	auditlog.Instance.SaveChanges(spaceID, userID, models.AuditActionUpdate, models.AuditEntityMsgTemplate, id,
+		"Изменен шаблон сообщения", rec.ToModel().MsgTemplateData, request)
 	return nil
 }
 
-func (i impl) Delete(spaceID, id string) error {
-	return i.msgTemplateStore.Delete(spaceID, id)
+func (i impl) Delete(spaceID, id, userID string) error {
+	rec, err := i.msgTemplateStore.GetByID(spaceID, id)
+	if err != nil {
+		return errors.Wrap(err, "ошибка получения шаблона")
+	}
+	err = i.msgTemplateStore.Delete(spaceID, id)
+	if err != nil {
+		return err
+	}
+	if rec != nil {
+		auditlog.Instance.SaveChanges(spaceID, userID, models.AuditActionDelete, models.AuditEntityMsgTemplate, id,
+			"Удален шаблон сообщения", rec.ToModel().MsgTemplateData, nil)
+	}
+	return nil
 }
 
-func GetVariables() []msgtemplateapimodels.TemplateItem {
+func (i impl) GetVariables(spaceID string) ([]msgtemplateapimodels.TemplateItem, error) {
+	result := getBaseVariables()
+	fieldVariables := []struct {
+		entity models.CustomFieldEntity
+		prefix string
+	}{
+		{models.CustomFieldEntityApplicant, "ApplicantFields"},
+		{models.CustomFieldEntityVacancy, "VacancyFields"},
+	}
+	for _, item := range fieldVariables {
+		fields, err := customfield.Instance.GetFields(spaceID, "", item.entity)
+		if err != nil {
+			return nil, err
+		}
+		for _, field := range fields {
+			result = append(result, msgtemplateapimodels.TemplateItem{
+				Name:  fmt.Sprintf("%v: %v", item.entity.ToHuman(), field.Name),
+				Value: fmt.Sprintf("{{.%v.%v}}", item.prefix, field.Code),
+			})
+		}
+	}
+	return result, nil
+}
+
+func getBaseVariables() []msgtemplateapimodels.TemplateItem {
 	return []msgtemplateapimodels.TemplateItem{
 		{
 			Name:  "Название должности",
@@ -355,6 +418,26 @@ func GetVariables() []msgtemplateapimodels.TemplateItem {
 			Name:  "[Контактные данные компании",
 			Value: "{{.CompanyContact}}",
 		},
+		{
+			Name:  "Заработная плата по офферу",
+			Value: "{{.OfferSalary}}",
+		},
+		{
+			Name:  "Дата выхода по офферу",
+			Value: "{{.OfferStartDate}}",
+		},
+		{
+			Name:  "Условия оффера",
+			Value: "{{.OfferConditions}}",
+		},
+		{
+			Name:  "Срок действия оффера",
+			Value: "{{.OfferExpiresAt}}",
+		},
+		{
+			Name:  "Ссылка на оффер",
+			Value: "{{.OfferLink}}",
+		},
 	}
 }
 
@@ -387,12 +470,63 @@ func (i impl) PdfPreview(ctx context.Context, spaceID, tplID, userID string) (bo
 		CompanyContact:      "[Контактные данные компании]",
 		CompanyName:         "[Название компании]",
 		CompanyDirectorName: "[ФИО директора компании]",
+		OfferSalary:         "[Заработная плата]",
+		OfferStartDate:      "[Дата выхода]",
+		OfferConditions:     "[Условия оффера]",
+		OfferExpiresAt:      "[Срок действия оффера]",
+		OfferLink:           "[Ссылка на оффер]",
 		Files:               models.TemplateFiles{},
 	}
+	tplData.ApplicantFields, err = i.getFieldPlaceholders(spaceID, models.CustomFieldEntityApplicant)
+	if err != nil {
+		return nil, "", err
+	}
+	tplData.VacancyFields, err = i.getFieldPlaceholders(spaceID, models.CustomFieldEntityVacancy)
+	if err != nil {
+		return nil, "", err
+	}
 	return i.buildPdf(context.TODO(), spaceID, tplData, msgTemplate)
 
 }
 
+func (i impl) BuildOfferPdf(ctx context.Context, spaceID, tplID, applicantID, userID string, offer models.OfferTemplateData) (body []byte, hMsg string, err error) {
+	msgTemplate, err := i.msgTemplateStore.GetByID(spaceID, tplID)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "ошибка получения шаблона оффера")
+	}
+	if msgTemplate == nil {
+		return nil, "шаблон оффера не найден", nil
+	}
+	if msgTemplate.TemplateType != models.TplOffer {
+		return nil, "шаблон не предусматривает генерацию pdf", nil
+	}
+	applicant, err := i.applicantStore.GetByID(spaceID, applicantID)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "ошибка получения кандидата")
+	}
+	if applicant == nil {
+		return nil, "кандидат не найден", nil
+	}
+	user, err := i.spaceUsersStore.GetByID(userID)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "ошибка получения профиля")
+	}
+	if user == nil {
+		return nil, "пользователь не найден", nil
+	}
+	tplData, err := i.getTlpData(applicant.Applicant, applicant.VacancyID, user)
+	if err != nil {
+		return nil, "", err
+	}
+	tplData.OfferSalary = offer.Salary
+	tplData.OfferStartDate = offer.StartDate
+	tplData.OfferConditions = offer.Conditions
+	tplData.OfferExpiresAt = offer.ExpiresAt
+	tplData.OfferLink = offer.Link
+	tplData.OfferNumber = offer.Number
+	return i.buildPdf(ctx, spaceID, tplData, msgTemplate)
+}
+
 func buildTitle(tmpl string, data models.TemplateData) (string, error) {
 	tpl, err := template.New("msg_title").Parse(tmpl)
 	if err != nil {
@@ -498,12 +632,42 @@ func (i impl) getTlpData(applicant dbmodels.Applicant, vacancyID string, user *d
 		result.CompanyAddress = vacancy.Space.CompanyAddress
 		result.CompanyContact = vacancy.Space.CompanyContact
 	}
-	if applicant.Source == models.ApplicantSourceAvito {
-		result.VacancyLink = vacancy.AvitoUri
-	} else if applicant.Source == models.ApplicantSourceHh {
-		result.VacancyLink = vacancy.HhUri
+	if connector, ok := externalservices.GetConnectorBySource(applicant.Source); ok {
+		if publication, ok := connector.Provider.(externalservices.PublicationInfo); ok {
+			result.VacancyLink = publication.GetVacancyUri(*vacancy)
+		}
+	}
+	result.ApplicantFields, err = i.getFieldValues(applicant.SpaceID, models.CustomFieldEntityApplicant, applicant.CustomFields)
+	if err != nil {
+		return models.TemplateData{}, err
+	}
+	result.VacancyFields, err = i.getFieldValues(applicant.SpaceID, models.CustomFieldEntityVacancy, vacancy.CustomFields)
+	if err != nil {
+		return models.TemplateData{}, err
+	}
+
+	return result, nil
+}
+
+// getFieldValues значения пользовательских полей для подстановки в шаблон
+func (i impl) getFieldValues(spaceID string, entity models.CustomFieldEntity, values dbmodels.CustomFieldValues) (map[string]string, error) {
+	fields, err := customfield.Instance.GetFields(spaceID, "", entity)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения пользовательских полей")
 	}
+	return customfield.Instance.FormatValues(fields, values)[0], nil
+}
 
+// getFieldPlaceholders названия пользовательских полей для предпросмотра шаблона
+func (i impl) getFieldPlaceholders(spaceID string, entity models.CustomFieldEntity) (map[string]string, error) {
+	fields, err := customfield.Instance.GetFields(spaceID, "", entity)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения пользовательских полей")
+	}
+	result := make(map[string]string, len(fields))
+	for _, field := range fields {
+		result[field.Code] = fmt.Sprintf("[%v]", field.Name)
+	}
 	return result, nil
 }
 
@@ -0,0 +1,97 @@
+package messagetemplate
+
+import (
+	"context"
+	applicantstore "hr-tools-backend/lib/applicant/store"
+	datascopefake "hr-tools-backend/lib/data-scope/fake"
+	messagetemplatestore "hr-tools-backend/lib/message-template/store"
+	"hr-tools-backend/lib/smtp"
+	spacesettingsstore "hr-tools-backend/lib/space/settings/store"
+	spaceusersstore "hr-tools-backend/lib/space/users/store"
+	"hr-tools-backend/models"
+	applicantapimodels "hr-tools-backend/models/api/applicant"
+	dbmodels "hr-tools-backend/models/db"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSmtp struct {
+	smtp.Provider
+	sent int
+}
+
+func (s *fakeSmtp) IsConfigured() bool { return true }
+
+func (s *fakeSmtp) SendHtmlEMail(from, to, message, subject string, attachment *models.File) error {
+	s.sent++
+	return nil
+}
+
+type fakeSettingsStore struct {
+	spacesettingsstore.Provider
+}
+
+func (s fakeSettingsStore) GetValueByCode(spaceID string, code models.SpaceSettingCode) (string, error) {
+	return "hr@example.com", nil
+}
+
+type fakeTemplateStore struct {
+	messagetemplatestore.Provider
+}
+
+func (s fakeTemplateStore) GetByID(spaceID, id string) (*dbmodels.MessageTemplate, error) {
+	return &dbmodels.MessageTemplate{Title: "title", Message: "message"}, nil
+}
+
+type fakeUsersStore struct {
+	spaceusersstore.Provider
+}
+
+func (s fakeUsersStore) GetByID(userID string) (*dbmodels.SpaceUser, error) {
+	return &dbmodels.SpaceUser{}, nil
+}
+
+type fakeApplicantStore struct {
+	applicantstore.Provider
+	scope *dbmodels.DataScope
+}
+
+// ListOfApplicantByIDs запрошенные кандидаты не видны пользователю, выборка без ограничения возвращает всех
+func (s *fakeApplicantStore) ListOfApplicantByIDs(spaceID string, scope *dbmodels.DataScope, ids []string, filter *applicantapimodels.ApplicantFilter) ([]dbmodels.ApplicantWithJob, error) {
+	s.scope = scope
+	if scope != nil {
+		return []dbmodels.ApplicantWithJob{}, nil
+	}
+	result := []dbmodels.ApplicantWithJob{}
+	for range ids {
+		result = append(result, dbmodels.ApplicantWithJob{Applicant: dbmodels.Applicant{Email: "applicant@example.com"}})
+	}
+	return result, nil
+}
+
+func TestMultiSendEmailOutsideScope(t *testing.T) {
+	datascopefake.Set(t, datascopefake.DataScope{})
+	smtpClient := &fakeSmtp{}
+	prevSmtp := smtp.Instance
+	smtp.Instance = smtpClient
+	t.Cleanup(func() { smtp.Instance = prevSmtp })
+	applicants := &fakeApplicantStore{}
+	i := impl{
+		msgTemplateStore:   fakeTemplateStore{},
+		applicantStore:     applicants,
+		spaceSettingsStore: fakeSettingsStore{},
+		spaceUsersStore:    fakeUsersStore{},
+	}
+
+	failMails, hMsg, err := i.MultiSendEmail(context.Background(), "space", "user", applicantapimodels.MultiEmailRequest{
+		IDs:           []string{"other"},
+		MsgTemplateID: "template",
+	})
+	require.NoError(t, err)
+	require.Empty(t, hMsg)
+	require.Empty(t, failMails)
+	require.NotNil(t, applicants.scope)
+	require.Equal(t, "user", applicants.scope.UserID)
+	require.Zero(t, smtpClient.sent, "кандидатам вне области видимости письма не отправляются")
+}
@@ -0,0 +1,805 @@
+package offer
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hr-tools-backend/config"
+	"hr-tools-backend/db"
+	"hr-tools-backend/lib/applicant"
+	applicanthistoryhandler "hr-tools-backend/lib/applicant-history"
+	applicantstore "hr-tools-backend/lib/applicant/store"
+	aprovaltaskhandler "hr-tools-backend/lib/aproval-task"
+	approvaltaskstore "hr-tools-backend/lib/aproval-task/store"
+	datascope "hr-tools-backend/lib/data-scope"
+	filestorage "hr-tools-backend/lib/file-storage"
+	messagetemplate "hr-tools-backend/lib/message-template"
+	messagetemplatestore "hr-tools-backend/lib/message-template/store"
+	offerstore "hr-tools-backend/lib/offer/store"
+	offerversionstore "hr-tools-backend/lib/offer/version-store"
+	"hr-tools-backend/lib/smtp"
+	initchecker "hr-tools-backend/lib/utils/init-checker"
+	webhookevent "hr-tools-backend/lib/webhook/event"
+	"hr-tools-backend/models"
+	offerapimodels "hr-tools-backend/models/api/offer"
+	vacancyapimodels "hr-tools-backend/models/api/vacancy"
+	dbmodels "hr-tools-backend/models/db"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type Provider interface {
+	Create(spaceID, userID string, data offerapimodels.CreateOfferRequest) (id, hMsg string, err error)
+	// Update изменение условий оффера, создается новая версия, оффер возвращается в черновик
+	Update(spaceID, id, userID string, data offerapimodels.OfferData) (hMsg string, err error)
+	GetByID(spaceID, id string) (*offerapimodels.OfferView, error)
+	// List офферы кандидата, кандидат вне области видимости пользователя - пустой список
+	List(spaceID, applicantID, userID string) ([]offerapimodels.OfferView, error)
+	Versions(spaceID, id string) ([]offerapimodels.OfferVersionView, error)
+	SaveApprovals(spaceID, id string, stages []vacancyapimodels.ApprovalTaskData) (hMsg string, err error)
+	ApprovalHistory(spaceID, id string) ([]vacancyapimodels.ApprovalHistoryView, error)
+	// SendToApproval отправка на согласование, без согласующих оффер сразу считается согласованным
+	SendToApproval(spaceID, id, userID string) (hMsg string, err error)
+	Approve(spaceID, id, taskID, userID string) (hMsg string, err error)
+	Reject(spaceID, id, taskID, userID string, data vacancyapimodels.ApprovalReject) (hMsg string, err error)
+	// Send отправка согласованного оффера кандидату: pdf во вложении и ссылка для принятия/отклонения
+	Send(ctx context.Context, spaceID, id, userID string) (hMsg string, err error)
+	Cancel(spaceID, id, userID string) (hMsg string, err error)
+	// GetPdf отправленный кандидату документ или предпросмотр документа текущей версии
+	GetPdf(ctx context.Context, spaceID, id, userID string) (body []byte, hMsg string, err error)
+	GetPublic(id string) (view *offerapimodels.PublicOfferView, hMsg string, err error)
+	GetPublicPdf(ctx context.Context, id string) (body []byte, hMsg string, err error)
+	// Accept принятие оффера кандидатом, кандидат переводится на этап "Принят" с датой выхода из оффера
+	Accept(id string) (hMsg string, err error)
+	Decline(id string, data offerapimodels.DeclineRequest) (hMsg string, err error)
+}
+
+var Instance Provider
+
+func NewHandler() {
+	instance := impl{
+		store:             offerstore.NewInstance(db.DB),
+		versionStore:      offerversionstore.NewInstance(db.DB),
+		applicantStore:    applicantstore.NewInstance(db.DB),
+		approvalTaskStore: approvaltaskstore.NewInstance(db.DB),
+		msgTemplateStore:  messagetemplatestore.NewInstance(db.DB),
+		applicant:         applicant.Instance,
+		approvalTask:      aprovaltaskhandler.Instance,
+		messageTemplate:   messagetemplate.Instance,
+		fileStorage:       filestorage.Instance,
+		applicantHistory:  applicanthistoryhandler.Instance,
+	}
+	initchecker.CheckInit(
+		"store", instance.store,
+		"versionStore", instance.versionStore,
+		"applicantStore", instance.applicantStore,
+		"approvalTaskStore", instance.approvalTaskStore,
+		"msgTemplateStore", instance.msgTemplateStore,
+		"applicant", instance.applicant,
+		"approvalTask", instance.approvalTask,
+		"messageTemplate", instance.messageTemplate,
+		"fileStorage", instance.fileStorage,
+		"applicantHistory", instance.applicantHistory,
+	)
+	Instance = instance
+}
+
+type impl struct {
+	store             offerstore.Provider
+	versionStore      offerversionstore.Provider
+	applicantStore    applicantstore.Provider
+	approvalTaskStore approvaltaskstore.Provider
+	msgTemplateStore  messagetemplatestore.Provider
+	applicant         applicant.Provider
+	approvalTask      aprovaltaskhandler.Provider
+	messageTemplate   messagetemplate.Provider
+	fileStorage       filestorage.Provider
+	applicantHistory  applicanthistoryhandler.Provider
+}
+
+const (
+	OfferNotFoundMsg  = "оффер не найден"
+	OfferCanceledMsg  = "оффер отозван компанией"
+	OfferExpiredMsg   = "срок действия оффера истек"
+	OfferAnsweredMsg  = "ответ по офферу уже получен"
+	OfferNotActualMsg = "оффер пересматривается компанией, ожидайте новую версию"
+	dateFormat        = "02.01.2006"
+)
+
+func (i impl) getLogger(spaceID, offerID string) *log.Entry {
+	return log.
+		WithField("space_id", spaceID).
+		WithField("offer_id", offerID)
+}
+
+func (i impl) Create(spaceID, userID string, data offerapimodels.CreateOfferRequest) (id, hMsg string, err error) {
+	visible, err := i.isApplicantVisible(spaceID, data.ApplicantID, userID)
+	if err != nil {
+		return "", "", err
+	}
+	if !visible {
+		return "", "кандидат не найден", nil
+	}
+	applicantRec, err := i.applicantStore.GetByID(spaceID, data.ApplicantID)
+	if err != nil {
+		return "", "", errors.Wrap(err, "ошибка получения кандидата")
+	}
+	if applicantRec == nil {
+		return "", "кандидат не найден", nil
+	}
+	if applicantRec.Status != models.ApplicantStatusInProcess {
+		return "", fmt.Sprintf("оффер можно создать только для кандидата в статусе '%v'", models.ApplicantStatusInProcess), nil
+	}
+	if applicantRec.SelectionStage == nil || applicantRec.SelectionStage.Name != dbmodels.OfferStage {
+		return "", fmt.Sprintf("оффер можно создать только для кандидата на этапе '%v'", dbmodels.OfferStage), nil
+	}
+	hMsg, err = i.checkTemplate(spaceID, data.TemplateID)
+	if err != nil || hMsg != "" {
+		return "", hMsg, err
+	}
+	active, err := i.store.GetActiveByApplicant(spaceID, data.ApplicantID)
+	if err != nil {
+		return "", "", errors.Wrap(err, "ошибка получения оффера кандидата")
+	}
+	if active != nil {
+		return "", "у кандидата уже есть оффер, измените его или отзовите", nil
+	}
+	rec := dbmodels.Offer{
+		BaseSpaceModel: dbmodels.BaseSpaceModel{
+			SpaceID: spaceID,
+		},
+		ApplicantID: applicantRec.ID,
+		VacancyID:   applicantRec.VacancyID,
+		AuthorID:    userID,
+		TemplateID:  data.TemplateID,
+		Version:     1,
+		Salary:      data.Salary,
+		StartDate:   data.StartDate,
+		Conditions:  data.Conditions,
+		ExpiresAt:   data.ExpiresAt,
+		Status:      dbmodels.OfferStatusDraft,
+	}
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		id, err = offerstore.NewInstance(tx).Create(rec)
+		if err != nil {
+			return err
+		}
+		rec.ID = id
+		_, err = offerversionstore.NewInstance(tx).Create(getVersion(rec, userID))
+		return err
+	})
+	if err != nil {
+		return "", "", errors.Wrap(err, "ошибка создания оффера")
+	}
+	changes := applicanthistoryhandler.GetOfferChange("Создан оффер", rec)
+	i.applicantHistory.Save(spaceID, rec.ApplicantID, rec.VacancyID, userID, dbmodels.HistoryTypeOffer, changes)
+	return id, "", nil
+}
+
+func (i impl) Update(spaceID, id, userID string, data offerapimodels.OfferData) (hMsg string, err error) {
+	rec, err := i.getRec(spaceID, id)
+	if err != nil {
+		return "", err
+	}
+	if rec == nil {
+		return OfferNotFoundMsg, nil
+	}
+	if !rec.GetStatus().AllowEdit() {
+		return fmt.Sprintf("изменение оффера в статусе '%v' невозможно", rec.GetStatus()), nil
+	}
+	hMsg, err = i.checkTemplate(spaceID, data.TemplateID)
+	if err != nil || hMsg != "" {
+		return hMsg, err
+	}
+	rec.Version++
+	rec.TemplateID = data.TemplateID
+	rec.Salary = data.Salary
+	rec.StartDate = data.StartDate
+	rec.Conditions = data.Conditions
+	rec.ExpiresAt = data.ExpiresAt
+	updMap := map[string]interface{}{
+		"version":          rec.Version,
+		"template_id":      rec.TemplateID,
+		"salary":           rec.Salary,
+		"start_date":       rec.StartDate,
+		"conditions":       rec.Conditions,
+		"expires_at":       rec.ExpiresAt,
+		"status":           dbmodels.OfferStatusDraft,
+		"decided_at":       nil,
+		"decline_reason":   "",
+		"document_file_id": "",
+		"document_hash":    "",
+	}
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		err = offerstore.NewInstance(tx).Update(spaceID, id, updMap)
+		if err != nil {
+			return err
+		}
+		_, err = offerversionstore.NewInstance(tx).Create(getVersion(*rec, userID))
+		return err
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка изменения оффера")
+	}
+	changes := applicanthistoryhandler.GetOfferChange("Изменены условия оффера", *rec)
+	i.applicantHistory.Save(spaceID, rec.ApplicantID, rec.VacancyID, userID, dbmodels.HistoryTypeOffer, changes)
+	return "", nil
+}
+
+func (i impl) GetByID(spaceID, id string) (*offerapimodels.OfferView, error) {
+	rec, err := i.getRec(spaceID, id)
+	if err != nil {
+		return nil, err
+	}
+	if rec == nil {
+		return nil, nil
+	}
+	result := offerapimodels.OfferConvert(*rec)
+	result.Approvals, err = i.approvalTask.Get(spaceID, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения цепочки согласования оффера")
+	}
+	return &result, nil
+}
+
+func (i impl) List(spaceID, applicantID, userID string) ([]offerapimodels.OfferView, error) {
+	visible, err := i.isApplicantVisible(spaceID, applicantID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return []offerapimodels.OfferView{}, nil
+	}
+	list, err := i.store.ListByApplicant(spaceID, applicantID)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения списка офферов")
+	}
+	result := make([]offerapimodels.OfferView, 0, len(list))
+	for _, rec := range list {
+		result = append(result, offerapimodels.OfferConvert(rec))
+	}
+	return result, nil
+}
+
+func (i impl) Versions(spaceID, id string) ([]offerapimodels.OfferVersionView, error) {
+	list, err := i.versionStore.List(spaceID, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения версий оффера")
+	}
+	result := make([]offerapimodels.OfferVersionView, 0, len(list))
+	for _, rec := range list {
+		result = append(result, offerapimodels.OfferVersionConvert(rec))
+	}
+	return result, nil
+}
+
+func (i impl) SaveApprovals(spaceID, id string, stages []vacancyapimodels.ApprovalTaskData) (hMsg string, err error) {
+	rec, err := i.getRec(spaceID, id)
+	if err != nil {
+		return "", err
+	}
+	if rec == nil {
+		return OfferNotFoundMsg, nil
+	}
+	if !rec.Status.AllowApproval() {
+		return fmt.Sprintf("изменение цепочки согласования оффера в статусе '%v' невозможно", rec.GetStatus()), nil
+	}
+	return i.approvalTask.Save(spaceID, id, stages)
+}
+
+func (i impl) ApprovalHistory(spaceID, id string) ([]vacancyapimodels.ApprovalHistoryView, error) {
+	return i.approvalTask.History(spaceID, id)
+}
+
+func (i impl) SendToApproval(spaceID, id, userID string) (hMsg string, err error) {
+	rec, err := i.getRec(spaceID, id)
+	if err != nil {
+		return "", err
+	}
+	if rec == nil {
+		return OfferNotFoundMsg, nil
+	}
+	if !rec.Status.AllowApproval() {
+		return fmt.Sprintf("отправка на согласование оффера в статусе '%v' невозможна", rec.GetStatus()), nil
+	}
+	taskList, err := i.approvalTaskStore.List(spaceID, id)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения цепочки согласования оффера")
+	}
+	status := dbmodels.OfferStatusInApproval
+	if len(taskList) == 0 {
+		status = dbmodels.OfferStatusApproved
+	}
+	var activated []dbmodels.ApprovalTask
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		// Все предыдущие решения согласующих аннулируются, согласование начинается с первого шага
+		activated, err = aprovaltaskhandler.NewHandlerWithTx(tx).Start(spaceID, id)
+		if err != nil {
+			return err
+		}
+		updMap := map[string]interface{}{
+			"status": status,
+		}
+		return offerstore.NewInstance(tx).Update(spaceID, id, updMap)
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка отправки оффера на согласование")
+	}
+	description := "Оффер отправлен на согласование"
+	if status == dbmodels.OfferStatusApproved {
+		description = "Оффер согласован"
+	}
+	changes := applicanthistoryhandler.GetOfferChange(description, *rec)
+	i.applicantHistory.Save(spaceID, rec.ApplicantID, rec.VacancyID, userID, dbmodels.HistoryTypeOffer, changes)
+	for _, task := range activated {
+		go i.sendPush(*rec, task.AssigneeUserID, models.GetPushOfferApproval)
+	}
+	return "", nil
+}
+
+func (i impl) Approve(spaceID, id, taskID, userID string) (hMsg string, err error) {
+	rec, task, hMsg, err := i.approvalPrepare(spaceID, id, taskID, userID)
+	if err != nil || hMsg != "" {
+		return hMsg, err
+	}
+	allApproved := false
+	var activated []dbmodels.ApprovalTask
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		approvalTaskHandler := aprovaltaskhandler.NewHandlerWithTx(tx)
+		allApproved, activated, err = approvalTaskHandler.Approve(*task)
+		if err != nil {
+			return err
+		}
+		if !allApproved {
+			return nil
+		}
+		//все согласовали, меняем статус оффера
+		offerUpdMap := map[string]interface{}{
+			"status": dbmodels.OfferStatusApproved,
+		}
+		err = offerstore.NewInstance(tx).Update(spaceID, id, offerUpdMap)
+		if err != nil {
+			return err
+		}
+		approvalTaskHandler.AuditCommon(dbmodels.ApprovalHistory{
+			BaseSpaceModel: dbmodels.BaseSpaceModel{SpaceID: spaceID},
+			RequestID:      id,
+			AssigneeUserID: userID,
+			Comment:        "Оффер полностью согласован",
+			Changes: dbmodels.EntityChanges{
+				Description: "Изменен статус оффера",
+				Data: []dbmodels.FieldChanges{
+					{
+						Field:    "Status",
+						OldValue: rec.Status,
+						NewValue: dbmodels.OfferStatusApproved,
+					},
+				},
+			},
+		})
+		return nil
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка согласования оффера")
+	}
+	if allApproved {
+		changes := applicanthistoryhandler.GetOfferChange("Оффер согласован", *rec)
+		i.applicantHistory.Save(spaceID, rec.ApplicantID, rec.VacancyID, userID, dbmodels.HistoryTypeOffer, changes)
+		go i.sendPush(*rec, rec.AuthorID, models.GetPushOfferApproved)
+	}
+	for _, item := range activated {
+		go i.sendPush(*rec, item.AssigneeUserID, models.GetPushOfferApproval)
+	}
+	return "", nil
+}
+
+func (i impl) Reject(spaceID, id, taskID, userID string, data vacancyapimodels.ApprovalReject) (hMsg string, err error) {
+	rec, task, hMsg, err := i.approvalPrepare(spaceID, id, taskID, userID)
+	if err != nil || hMsg != "" {
+		return hMsg, err
+	}
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		updMap := map[string]interface{}{
+			"State":     models.AStateRejected,
+			"Comment":   data.Comment,
+			"DecidedAt": now,
+		}
+		err = approvaltaskstore.NewInstance(tx).Update(spaceID, taskID, updMap)
+		if err != nil {
+			return err
+		}
+		offerUpdMap := map[string]interface{}{
+			"status": dbmodels.OfferStatusRejected,
+		}
+		err = offerstore.NewInstance(tx).Update(spaceID, id, offerUpdMap)
+		if err != nil {
+			return err
+		}
+		// для аудита
+		task.State = models.AStateRejected
+		task.Comment = data.Comment
+		task.DecidedAt = &now
+		aprovaltaskhandler.NewHandlerWithTx(tx).Audit(*task)
+		return nil
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка отклонения оффера")
+	}
+	changes := applicanthistoryhandler.GetOfferChange("Оффер отклонен при согласовании", *rec)
+	i.applicantHistory.Save(spaceID, rec.ApplicantID, rec.VacancyID, userID, dbmodels.HistoryTypeOffer, changes)
+	userName := ""
+	if task.AssigneeUser != nil {
+		userName = task.AssigneeUser.GetFullName()
+	}
+	go i.sendPush(*rec, rec.AuthorID, func(vacancyName, applicantFIO string) models.NotificationData {
+		return models.GetPushOfferRejected(vacancyName, applicantFIO, userName)
+	})
+	return "", nil
+}
+
+func (i impl) Send(ctx context.Context, spaceID, id, userID string) (hMsg string, err error) {
+	rec, err := i.getRec(spaceID, id)
+	if err != nil {
+		return "", err
+	}
+	if rec == nil {
+		return OfferNotFoundMsg, nil
+	}
+	if rec.Status != dbmodels.OfferStatusApproved {
+		return "отправить кандидату можно только согласованный оффер", nil
+	}
+	if !rec.ExpiresAt.After(time.Now()) {
+		return "срок действия оффера истек, измените срок действия", nil
+	}
+	if rec.Applicant == nil || rec.Applicant.Email == "" {
+		return "у кандидата не указана почта", nil
+	}
+	if !smtp.Instance.IsConfigured() {
+		return "отправка почты не настроена", nil
+	}
+	emailFrom, err := i.messageTemplate.GetSenderEmail(spaceID)
+	if err != nil {
+		return "", err
+	}
+	if emailFrom == "" {
+		return "в настройках пространства не указана почта для отправки", nil
+	}
+	body, hMsg, err := i.buildPdf(ctx, *rec, userID)
+	if err != nil || hMsg != "" {
+		return hMsg, err
+	}
+	fileInfo := dbmodels.UploadFileInfo{
+		SpaceID:        spaceID,
+		ApplicantID:    rec.ID,
+		FileName:       fmt.Sprintf("offer_v%v.pdf", rec.Version),
+		FileType:       dbmodels.OfferDocument,
+		ContentType:    "application/pdf",
+		IsUniqueByName: true,
+	}
+	fileID, err := i.fileStorage.UploadObject(ctx, fileInfo, bytes.NewReader(body), len(body))
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка сохранения документа оффера")
+	}
+	hash := sha256.Sum256(body)
+	vacancyName := getVacancyName(*rec)
+	attachment := &models.File{
+		FileName:    "offer.pdf",
+		ContentType: "application/pdf",
+		Body:        body,
+	}
+	title := fmt.Sprintf("Предложение о работе: %v", vacancyName)
+	err = smtp.Instance.SendHtmlEMail(emailFrom, rec.Applicant.Email, getEmailMessage(*rec, vacancyName), title, attachment)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка отправки оффера кандидату")
+	}
+	now := time.Now()
+	updMap := map[string]interface{}{
+		"status":           dbmodels.OfferStatusSent,
+		"sent_at":          now,
+		"document_file_id": fileID,
+		"document_hash":    hex.EncodeToString(hash[:]),
+	}
+	err = i.store.Update(spaceID, id, updMap)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка изменения статуса оффера")
+	}
+	changes := applicanthistoryhandler.GetOfferChange("Оффер отправлен кандидату", *rec)
+	i.applicantHistory.Save(spaceID, rec.ApplicantID, rec.VacancyID, userID, dbmodels.HistoryTypeOffer, changes)
+	return "", nil
+}
+
+func (i impl) Cancel(spaceID, id, userID string) (hMsg string, err error) {
+	rec, err := i.getRec(spaceID, id)
+	if err != nil {
+		return "", err
+	}
+	if rec == nil {
+		return OfferNotFoundMsg, nil
+	}
+	if rec.Status.IsFinal() {
+		return fmt.Sprintf("отзыв оффера в статусе '%v' невозможен", rec.Status), nil
+	}
+	updMap := map[string]interface{}{
+		"status": dbmodels.OfferStatusCanceled,
+	}
+	err = i.store.Update(spaceID, id, updMap)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка отзыва оффера")
+	}
+	changes := applicanthistoryhandler.GetOfferChange("Оффер отозван", *rec)
+	i.applicantHistory.Save(spaceID, rec.ApplicantID, rec.VacancyID, userID, dbmodels.HistoryTypeOffer, changes)
+	return "", nil
+}
+
+func (i impl) GetPdf(ctx context.Context, spaceID, id, userID string) (body []byte, hMsg string, err error) {
+	rec, err := i.getRec(spaceID, id)
+	if err != nil {
+		return nil, "", err
+	}
+	if rec == nil {
+		return nil, OfferNotFoundMsg, nil
+	}
+	if rec.DocumentFileID != "" {
+		body, _, _, err = i.fileStorage.GetFile(ctx, spaceID, rec.DocumentFileID)
+		if err != nil {
+			return nil, "", errors.Wrap(err, "ошибка получения документа оффера")
+		}
+		return body, "", nil
+	}
+	return i.buildPdf(ctx, *rec, userID)
+}
+
+func (i impl) GetPublic(id string) (view *offerapimodels.PublicOfferView, hMsg string, err error) {
+	rec, hMsg, err := i.getPublicRec(id)
+	if err != nil || hMsg != "" {
+		return nil, hMsg, err
+	}
+	result := offerapimodels.PublicOfferView{
+		Number:       rec.GetNumber(),
+		VacancyName:  getVacancyName(*rec),
+		Salary:       rec.Salary,
+		StartDate:    rec.StartDate,
+		Conditions:   rec.Conditions,
+		ExpiresAt:    rec.ExpiresAt,
+		Status:       rec.GetStatus(),
+		DocumentHash: rec.DocumentHash,
+	}
+	if rec.Applicant != nil {
+		result.FirstName = rec.Applicant.FirstName
+	}
+	return &result, "", nil
+}
+
+func (i impl) GetPublicPdf(ctx context.Context, id string) (body []byte, hMsg string, err error) {
+	rec, hMsg, err := i.getPublicRec(id)
+	if err != nil || hMsg != "" {
+		return nil, hMsg, err
+	}
+	body, _, _, err = i.fileStorage.GetFile(ctx, rec.SpaceID, rec.DocumentFileID)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "ошибка получения документа оффера")
+	}
+	return body, "", nil
+}
+
+func (i impl) Accept(id string) (hMsg string, err error) {
+	rec, hMsg, err := i.getPublicRec(id)
+	if err != nil || hMsg != "" {
+		return hMsg, err
+	}
+	hMsg = checkAnswer(*rec)
+	if hMsg != "" {
+		return hMsg, nil
+	}
+	logger := i.getLogger(rec.SpaceID, rec.ID)
+	now := time.Now()
+	updMap := map[string]interface{}{
+		"status":     dbmodels.OfferStatusAccepted,
+		"decided_at": now,
+	}
+	ok, err := i.store.UpdateByStatus(id, dbmodels.OfferStatusSent, updMap)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка принятия оффера")
+	}
+	if !ok {
+		return OfferAnsweredMsg, nil
+	}
+	hMsg, err = i.applicant.Hire(rec.SpaceID, "", rec.ApplicantID, rec.StartDate)
+	if err != nil || hMsg != "" {
+		// возвращаем оффер в ожидание ответа, кандидат сможет повторить
+		revertMap := map[string]interface{}{
+			"status":     dbmodels.OfferStatusSent,
+			"decided_at": nil,
+		}
+		if _, revertErr := i.store.UpdateByStatus(id, dbmodels.OfferStatusAccepted, revertMap); revertErr != nil {
+			logger.WithError(revertErr).Error("ошибка возврата статуса оффера")
+		}
+		if err != nil {
+			return "", err
+		}
+		logger.WithField("reason", hMsg).Warn("не удалось перевести кандидата на этап по принятому офферу")
+		return "принять оффер сейчас невозможно, свяжитесь с представителем компании", nil
+	}
+	i.saveApplicantAnswer(*rec, "Кандидат принял оффер")
+	webhookevent.Publish(webhookevent.Event{
+		Type:        dbmodels.WebhookEventOfferAccepted,
+		SpaceID:     rec.SpaceID,
+		ApplicantID: rec.ApplicantID,
+		VacancyID:   rec.VacancyID,
+		OfferID:     rec.ID,
+	})
+	go i.sendPush(*rec, rec.AuthorID, models.GetPushOfferAccepted)
+	return "", nil
+}
+
+func (i impl) Decline(id string, data offerapimodels.DeclineRequest) (hMsg string, err error) {
+	rec, hMsg, err := i.getPublicRec(id)
+	if err != nil || hMsg != "" {
+		return hMsg, err
+	}
+	hMsg = checkAnswer(*rec)
+	if hMsg != "" {
+		return hMsg, nil
+	}
+	updMap := map[string]interface{}{
+		"status":         dbmodels.OfferStatusDeclined,
+		"decided_at":     time.Now(),
+		"decline_reason": data.Reason,
+	}
+	ok, err := i.store.UpdateByStatus(id, dbmodels.OfferStatusSent, updMap)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка отклонения оффера")
+	}
+	if !ok {
+		return OfferAnsweredMsg, nil
+	}
+	description := "Кандидат отказался от оффера"
+	if data.Reason != "" {
+		description += ": " + data.Reason
+	}
+	i.saveApplicantAnswer(*rec, description)
+	go i.sendPush(*rec, rec.AuthorID, models.GetPushOfferDeclined)
+	return "", nil
+}
+
+func (i impl) isApplicantVisible(spaceID, applicantID, userID string) (bool, error) {
+	scope, err := datascope.Instance.GetScope(spaceID, userID)
+	if err != nil {
+		return false, errors.Wrap(err, "ошибка получения области видимости")
+	}
+	visible, err := i.applicantStore.IsVisible(spaceID, applicantID, scope)
+	if err != nil {
+		return false, errors.Wrap(err, "ошибка получения кандидата")
+	}
+	return visible, nil
+}
+
+func (i impl) getRec(spaceID, id string) (*dbmodels.Offer, error) {
+	rec, err := i.store.GetByID(spaceID, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения оффера")
+	}
+	return rec, nil
+}
+
+// getPublicRec оффер, доступный кандидату по ссылке (отправленный документ текущей версии)
+func (i impl) getPublicRec(id string) (*dbmodels.Offer, string, error) {
+	rec, err := i.store.GetPublic(id)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "ошибка получения оффера")
+	}
+	if rec == nil {
+		return nil, OfferNotFoundMsg, nil
+	}
+	if rec.Status == dbmodels.OfferStatusCanceled {
+		return nil, OfferCanceledMsg, nil
+	}
+	if rec.DocumentFileID == "" {
+		return nil, OfferNotActualMsg, nil
+	}
+	return rec, "", nil
+}
+
+func (i impl) approvalPrepare(spaceID, id, taskID, userID string) (*dbmodels.Offer, *dbmodels.ApprovalTask, string, error) {
+	rec, err := i.getRec(spaceID, id)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if rec == nil {
+		return nil, nil, OfferNotFoundMsg, nil
+	}
+	if rec.Status != dbmodels.OfferStatusInApproval {
+		return nil, nil, fmt.Sprintf("согласование оффера в статусе '%v' невозможно", rec.GetStatus()), nil
+	}
+	task, err := i.approvalTaskStore.GetByID(spaceID, taskID)
+	if err != nil {
+		return nil, nil, "", errors.Wrap(err, "ошибка получения задачи на согласование")
+	}
+	if task == nil || task.RequestID != id {
+		return nil, nil, "Задача на согласование не найдена", nil
+	}
+	if task.AssigneeUserID != userID {
+		return nil, nil, "На данную задачу назначен другой пользователь", nil
+	}
+	if task.State != models.AStatePending {
+		return nil, nil, "Задача не ожидает согласования", nil
+	}
+	return rec, task, "", nil
+}
+
+func (i impl) checkTemplate(spaceID, templateID string) (string, error) {
+	tplRec, err := i.msgTemplateStore.GetByID(spaceID, templateID)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения шаблона оффера")
+	}
+	if tplRec == nil {
+		return "шаблон оффера не найден", nil
+	}
+	if tplRec.TemplateType != models.TplOffer || tplRec.PdfMessage == "" {
+		return "шаблон не предусматривает формирование оффера в pdf", nil
+	}
+	return "", nil
+}
+
+func (i impl) buildPdf(ctx context.Context, rec dbmodels.Offer, userID string) ([]byte, string, error) {
+	data := models.OfferTemplateData{
+		Salary:     fmt.Sprintf("%v", rec.Salary),
+		StartDate:  rec.StartDate.Format(dateFormat),
+		Conditions: rec.Conditions,
+		ExpiresAt:  rec.ExpiresAt.Format(dateFormat),
+		Link:       getLink(rec.ID),
+		Number:     rec.GetNumber(),
+	}
+	body, hMsg, err := i.messageTemplate.BuildOfferPdf(ctx, rec.SpaceID, rec.TemplateID, rec.ApplicantID, userID, data)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "ошибка формирования pdf оффера")
+	}
+	return body, hMsg, nil
+}
+
+func (i impl) saveApplicantAnswer(rec dbmodels.Offer, description string) {
+	userName := ""
+	if rec.Applicant != nil {
+		userName = rec.Applicant.GetFIO()
+	}
+	changes := applicanthistoryhandler.GetOfferChange(description, rec)
+	i.applicantHistory.SaveWithUser(rec.SpaceID, rec.ApplicantID, rec.VacancyID, "", userName, dbmodels.HistoryTypeOffer, changes)
+}
+
+func checkAnswer(rec dbmodels.Offer) string {
+	switch rec.GetStatus() {
+	case dbmodels.OfferStatusSent:
+		return ""
+	case dbmodels.OfferStatusExpired:
+		return OfferExpiredMsg
+	case dbmodels.OfferStatusAccepted, dbmodels.OfferStatusDeclined:
+		return OfferAnsweredMsg
+	}
+	return OfferNotActualMsg
+}
+
+func getVersion(rec dbmodels.Offer, userID string) dbmodels.OfferVersion {
+	return dbmodels.OfferVersion{
+		BaseSpaceModel: dbmodels.BaseSpaceModel{
+			SpaceID: rec.SpaceID,
+		},
+		OfferID:    rec.ID,
+		Version:    rec.Version,
+		AuthorID:   userID,
+		TemplateID: rec.TemplateID,
+		Salary:     rec.Salary,
+		StartDate:  rec.StartDate,
+		Conditions: rec.Conditions,
+		ExpiresAt:  rec.ExpiresAt,
+	}
+}
+
+func getLink(id string) string {
+	return config.Conf.UIParams.OfferPath + id
+}
@@ -0,0 +1,64 @@
+package offer
+
+import (
+	applicantstore "hr-tools-backend/lib/applicant/store"
+	datascopefake "hr-tools-backend/lib/data-scope/fake"
+	offerapimodels "hr-tools-backend/models/api/offer"
+	dbmodels "hr-tools-backend/models/db"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckAnswer(t *testing.T) {
+	future := time.Now().Add(24 * time.Hour)
+	past := time.Now().Add(-24 * time.Hour)
+
+	require.Empty(t, checkAnswer(dbmodels.Offer{Status: dbmodels.OfferStatusSent, ExpiresAt: future}))
+	require.Equal(t, OfferExpiredMsg, checkAnswer(dbmodels.Offer{Status: dbmodels.OfferStatusSent, ExpiresAt: past}))
+	require.Equal(t, OfferAnsweredMsg, checkAnswer(dbmodels.Offer{Status: dbmodels.OfferStatusAccepted, ExpiresAt: future}))
+	require.Equal(t, OfferAnsweredMsg, checkAnswer(dbmodels.Offer{Status: dbmodels.OfferStatusDeclined, ExpiresAt: future}))
+	// оффер пересматривается после изменения условий
+	require.Equal(t, OfferNotActualMsg, checkAnswer(dbmodels.Offer{Status: dbmodels.OfferStatusInApproval, ExpiresAt: future}))
+}
+
+func TestOfferStatus(t *testing.T) {
+	rec := dbmodels.Offer{
+		BaseSpaceModel: dbmodels.BaseSpaceModel{BaseModel: dbmodels.BaseModel{ID: "3f2a9c1e-0000-0000-0000-000000000000"}},
+		Version:        2,
+		Status:         dbmodels.OfferStatusSent,
+		ExpiresAt:      time.Now().Add(-time.Minute),
+	}
+	require.Equal(t, "3F2A9C1E/2", rec.GetNumber())
+	require.Equal(t, dbmodels.OfferStatusExpired, rec.GetStatus())
+	require.True(t, rec.GetStatus().AllowEdit())
+	require.False(t, rec.GetStatus().AllowApproval())
+
+	require.True(t, dbmodels.OfferStatusAccepted.IsFinal())
+	require.False(t, dbmodels.OfferStatusAccepted.AllowEdit())
+	require.True(t, dbmodels.OfferStatusRejected.AllowApproval())
+}
+
+type fakeApplicantStore struct {
+	applicantstore.Provider
+}
+
+func (s fakeApplicantStore) IsVisible(spaceID, id string, scope *dbmodels.DataScope) (bool, error) {
+	return false, nil
+}
+
+func TestOfferOutsideScope(t *testing.T) {
+	datascopefake.Set(t, datascopefake.DataScope{})
+	i := impl{
+		applicantStore: fakeApplicantStore{},
+	}
+
+	_, hMsg, err := i.Create("space", "user", offerapimodels.CreateOfferRequest{ApplicantID: "other"})
+	require.NoError(t, err)
+	require.Equal(t, "кандидат не найден", hMsg)
+
+	list, err := i.List("space", "other", "user")
+	require.NoError(t, err)
+	require.Empty(t, list)
+}
@@ -0,0 +1,40 @@
+package offer
+
+import (
+	"fmt"
+	pushhandler "hr-tools-backend/lib/space/push/handler"
+	"hr-tools-backend/models"
+	dbmodels "hr-tools-backend/models/db"
+	"html"
+)
+
+func (i impl) sendPush(rec dbmodels.Offer, userID string, getData func(vacancyName, applicantFIO string) models.NotificationData) {
+	if userID == "" {
+		return
+	}
+	applicantFIO := ""
+	if rec.Applicant != nil {
+		applicantFIO = rec.Applicant.GetFIO()
+	}
+	pushhandler.Instance.SendNotification(userID, getData(getVacancyName(rec), applicantFIO).WithEntity(rec.ApplicantID))
+}
+
+func getVacancyName(rec dbmodels.Offer) string {
+	if rec.Applicant != nil && rec.Applicant.Vacancy != nil {
+		return rec.Applicant.Vacancy.VacancyName
+	}
+	return ""
+}
+
+func getEmailMessage(rec dbmodels.Offer, vacancyName string) string {
+	firstName := ""
+	if rec.Applicant != nil {
+		firstName = rec.Applicant.FirstName
+	}
+	link := getLink(rec.ID)
+	return fmt.Sprintf("<p>%v, здравствуйте!</p>"+
+		"<p>Мы рады предложить вам работу по вакансии «%v». Условия предложения - во вложении.</p>"+
+		"<p>Принять или отклонить предложение можно по ссылке: <a href=\"%v\">%v</a><br>"+
+		"Предложение действительно до %v.</p>",
+		html.EscapeString(firstName), html.EscapeString(vacancyName), link, link, rec.ExpiresAt.Format(dateFormat))
+}
@@ -0,0 +1,158 @@
+package offerstore
+
+import (
+	dbmodels "hr-tools-backend/models/db"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Provider interface {
+	Create(rec dbmodels.Offer) (id string, err error)
+	Update(spaceID, id string, updMap map[string]interface{}) error
+	// UpdateByStatus изменение оффера, если он находится в указанном статусе, false - статус уже изменен
+	UpdateByStatus(id string, status dbmodels.OfferStatus, updMap map[string]interface{}) (bool, error)
+	GetByID(spaceID, id string) (*dbmodels.Offer, error)
+	// IsVisible оффер виден согласующим и пользователям, которым виден кандидат
+	IsVisible(spaceID, id string, scope *dbmodels.DataScope) (bool, error)
+	// GetPublic получение оффера по ссылке кандидата
+	GetPublic(id string) (*dbmodels.Offer, error)
+	// GetActiveByApplicant последний не отозванный оффер кандидата
+	GetActiveByApplicant(spaceID, applicantID string) (*dbmodels.Offer, error)
+	ListByApplicant(spaceID, applicantID string) ([]dbmodels.Offer, error)
+}
+
+func NewInstance(DB *gorm.DB) Provider {
+	return &impl{
+		db: DB,
+	}
+}
+
+type impl struct {
+	db *gorm.DB
+}
+
+func (i impl) Create(rec dbmodels.Offer) (id string, err error) {
+	err = i.db.
+		Omit(clause.Associations).
+		Save(&rec).
+		Error
+	if err != nil {
+		return "", err
+	}
+	return rec.ID, nil
+}
+
+func (i impl) Update(spaceID, id string, updMap map[string]interface{}) error {
+	if len(updMap) == 0 {
+		return nil
+	}
+	err := i.db.
+		Model(&dbmodels.Offer{}).
+		Where("id = ?", id).
+		Where("space_id = ?", spaceID).
+		Updates(updMap).
+		Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (i impl) UpdateByStatus(id string, status dbmodels.OfferStatus, updMap map[string]interface{}) (bool, error) {
+	tx := i.db.
+		Model(&dbmodels.Offer{}).
+		Where("id = ?", id).
+		Where("status = ?", status).
+		Updates(updMap)
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+	return tx.RowsAffected != 0, nil
+}
+
+func (i impl) GetByID(spaceID, id string) (*dbmodels.Offer, error) {
+	rec := dbmodels.Offer{}
+	err := i.preload().
+		Where("id = ?", id).
+		Where("space_id = ?", spaceID).
+		First(&rec).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (i impl) IsVisible(spaceID, id string, scope *dbmodels.DataScope) (bool, error) {
+	var exists bool
+	tx := i.db.
+		Model(&dbmodels.Offer{}).
+		Select("count(*) > 0").
+		Where("id = ?", id).
+		Where("space_id = ?", spaceID)
+	if query, args := scope.ApplicantRefCondition("offers.applicant_id"); query != "" {
+		approverQuery := "EXISTS (SELECT 1 FROM approval_tasks at_scope WHERE at_scope.request_id = offers.id AND at_scope.assignee_user_id = @scope_user)"
+		tx.Where("("+query+" OR "+approverQuery+")", args)
+	}
+	err := tx.Find(&exists).Error
+	return exists, err
+}
+
+func (i impl) GetPublic(id string) (*dbmodels.Offer, error) {
+	rec := dbmodels.Offer{}
+	err := i.preload().
+		Where("id = ?", id).
+		First(&rec).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (i impl) GetActiveByApplicant(spaceID, applicantID string) (*dbmodels.Offer, error) {
+	rec := dbmodels.Offer{}
+	err := i.db.
+		Where("space_id = ?", spaceID).
+		Where("applicant_id = ?", applicantID).
+		Where("status <> ?", dbmodels.OfferStatusCanceled).
+		Order("created_at desc").
+		First(&rec).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (i impl) ListByApplicant(spaceID, applicantID string) ([]dbmodels.Offer, error) {
+	list := []dbmodels.Offer{}
+	err := i.preload().
+		Where("space_id = ?", spaceID).
+		Where("applicant_id = ?", applicantID).
+		Order("created_at desc").
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (i impl) preload() *gorm.DB {
+	return i.db.
+		Preload("Applicant").
+		Preload("Applicant.Vacancy").
+		Preload("Author")
+}
@@ -0,0 +1,49 @@
+package offerversionstore
+
+import (
+	dbmodels "hr-tools-backend/models/db"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Provider interface {
+	Create(rec dbmodels.OfferVersion) (id string, err error)
+	List(spaceID, offerID string) ([]dbmodels.OfferVersion, error)
+}
+
+func NewInstance(DB *gorm.DB) Provider {
+	return &impl{
+		db: DB,
+	}
+}
+
+type impl struct {
+	db *gorm.DB
+}
+
+func (i impl) Create(rec dbmodels.OfferVersion) (id string, err error) {
+	err = i.db.
+		Omit(clause.Associations).
+		Save(&rec).
+		Error
+	if err != nil {
+		return "", err
+	}
+	return rec.ID, nil
+}
+
+func (i impl) List(spaceID, offerID string) ([]dbmodels.OfferVersion, error) {
+	list := []dbmodels.OfferVersion{}
+	err := i.db.
+		Preload("Author").
+		Where("space_id = ?", spaceID).
+		Where("offer_id = ?", offerID).
+		Order("version desc").
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
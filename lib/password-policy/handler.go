@@ -0,0 +1,147 @@
+package passwordpolicy
+
+import (
+	"bufio"
+	"fmt"
+	"hr-tools-backend/config"
+	authapimodels "hr-tools-backend/models/api/auth"
+	"math"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// ограничение сверху, чтобы длинные пароли не нагружали вычисление хеша
+const maxLength = 128
+
+type Provider interface {
+	// Validate проверка нового пароля на соответствие политике, возвращает описание нарушения
+	Validate(password string) (hMsg string)
+	GetPolicy() authapimodels.PasswordPolicy
+	// CheckLock проверка блокировки входа по паролю, возвращает сообщение для пользователя
+	CheckLock(lockedUntil *time.Time) (hMsg string)
+	// GetLockUntil срок блокировки входа после очередного неверного пароля, nil - блокировка не требуется
+	GetLockUntil(failedAttempts int) *time.Time
+}
+
+var Instance Provider
+
+func NewHandler() {
+	denylist, err := loadDenylist(config.Conf.Password.DenylistFile)
+	if err != nil {
+		panic(err.Error())
+	}
+	instance := impl{
+		policy: authapimodels.PasswordPolicy{
+			MinLength:      max(config.Conf.Password.MinLength, 1),
+			MaxLength:      maxLength,
+			RequireUpper:   config.Conf.Password.RequireUpper,
+			RequireLower:   config.Conf.Password.RequireLower,
+			RequireDigit:   config.Conf.Password.RequireDigit,
+			RequireSpecial: config.Conf.Password.RequireSpecial,
+		},
+		denylist:          denylist,
+		maxFailedAttempts: config.Conf.Password.MaxFailedAttempts,
+		lockDuration:      time.Duration(config.Conf.Password.LockMin) * time.Minute,
+	}
+	Instance = instance
+}
+
+type impl struct {
+	policy   authapimodels.PasswordPolicy
+	denylist map[string]bool
+
+	maxFailedAttempts int
+	lockDuration      time.Duration
+}
+
+func (i impl) Validate(password string) (hMsg string) {
+	length := utf8.RuneCountInString(password)
+	if length < i.policy.MinLength {
+		return fmt.Sprintf("пароль должен содержать не менее %v символов", i.policy.MinLength)
+	}
+	if length > i.policy.MaxLength {
+		return fmt.Sprintf("пароль должен содержать не более %v символов", i.policy.MaxLength)
+	}
+	var hasUpper, hasLower, hasDigit, hasSpecial bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsSpace(r):
+		default:
+			hasSpecial = true
+		}
+	}
+	if i.policy.RequireUpper && !hasUpper {
+		return "пароль должен содержать заглавную букву"
+	}
+	if i.policy.RequireLower && !hasLower {
+		return "пароль должен содержать строчную букву"
+	}
+	if i.policy.RequireDigit && !hasDigit {
+		return "пароль должен содержать цифру"
+	}
+	if i.policy.RequireSpecial && !hasSpecial {
+		return "пароль должен содержать спецсимвол"
+	}
+	if i.denylist[strings.ToLower(password)] {
+		return "пароль встречается в утечках данных, выберите другой пароль"
+	}
+	return ""
+}
+
+func (i impl) GetPolicy() authapimodels.PasswordPolicy {
+	return i.policy
+}
+
+func (i impl) CheckLock(lockedUntil *time.Time) (hMsg string) {
+	if lockedUntil == nil || !lockedUntil.After(time.Now()) {
+		return ""
+	}
+	minutes := int(math.Ceil(time.Until(*lockedUntil).Minutes()))
+	return fmt.Sprintf("слишком много неудачных попыток входа, повторите через %v мин. или восстановите пароль", minutes)
+}
+
+func (i impl) GetLockUntil(failedAttempts int) *time.Time {
+	if i.maxFailedAttempts <= 0 || failedAttempts < i.maxFailedAttempts {
+		return nil
+	}
+	lockedUntil := time.Now().Add(i.lockDuration)
+	return &lockedUntil
+}
+
+// loadDenylist загрузка списка скомпрометированных паролей, сравнение без учета регистра
+func loadDenylist(path string) (map[string]bool, error) {
+	result := map[string]bool{}
+	if path == "" {
+		return result, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка открытия файла скомпрометированных паролей")
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		value := strings.TrimSpace(scanner.Text())
+		if value == "" {
+			continue
+		}
+		result[strings.ToLower(value)] = true
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "ошибка чтения файла скомпрометированных паролей")
+	}
+	log.WithField("count", len(result)).Info("загружен список скомпрометированных паролей")
+	return result, nil
+}
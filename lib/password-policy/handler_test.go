@@ -0,0 +1,65 @@
+package passwordpolicy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	authapimodels "hr-tools-backend/models/api/auth"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "denylist.txt")
+	require.NoError(t, os.WriteFile(path, []byte("Password1\n\n  qwertY123  \n"), 0o600))
+	denylist, err := loadDenylist(path)
+	require.NoError(t, err)
+	require.Len(t, denylist, 2)
+
+	i := impl{
+		policy: authapimodels.PasswordPolicy{
+			MinLength:    8,
+			MaxLength:    maxLength,
+			RequireUpper: true,
+			RequireLower: true,
+			RequireDigit: true,
+		},
+		denylist: denylist,
+	}
+	require.Empty(t, i.Validate("Secret123"))
+	require.Empty(t, i.Validate("Пароль123"))
+	require.Contains(t, i.Validate("Sec123"), "не менее 8")
+	require.Contains(t, i.Validate("secret123"), "заглавную")
+	require.Contains(t, i.Validate("SECRET123"), "строчную")
+	require.Contains(t, i.Validate("SecretPass"), "цифру")
+	require.Contains(t, i.Validate("PASSword1"), "утечках")
+	require.Contains(t, i.Validate("Qwerty123"), "утечках")
+
+	i.policy.RequireSpecial = true
+	require.Contains(t, i.Validate("Secret123"), "спецсимвол")
+	require.Empty(t, i.Validate("Secret-123"))
+
+	t.Run("lock", func(t *testing.T) {
+		i := impl{maxFailedAttempts: 3, lockDuration: 15 * time.Minute}
+		require.Nil(t, i.GetLockUntil(2))
+		lockedUntil := i.GetLockUntil(3)
+		require.NotNil(t, lockedUntil)
+		require.Contains(t, i.CheckLock(lockedUntil), "через 15 мин")
+		require.Empty(t, i.CheckLock(nil))
+		expired := time.Now().Add(-time.Second)
+		require.Empty(t, i.CheckLock(&expired))
+
+		i.maxFailedAttempts = 0
+		require.Nil(t, i.GetLockUntil(100), "блокировка отключена")
+	})
+
+	t.Run("no denylist", func(t *testing.T) {
+		denylist, err := loadDenylist("")
+		require.NoError(t, err)
+		require.Empty(t, denylist)
+		_, err = loadDenylist(filepath.Join(t.TempDir(), "missing.txt"))
+		require.Error(t, err)
+	})
+}
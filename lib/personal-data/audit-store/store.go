@@ -0,0 +1,74 @@
+package pdauditstore
+
+import (
+	personaldataapimodels "hr-tools-backend/models/api/personal-data"
+	dbmodels "hr-tools-backend/models/db"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Provider interface {
+	Create(rec dbmodels.PersonalDataAudit) (id string, err error)
+	ListCount(spaceID string, filter personaldataapimodels.AuditFilter) (int64, error)
+	List(spaceID string, filter personaldataapimodels.AuditFilter) ([]dbmodels.PersonalDataAudit, error)
+}
+
+func NewInstance(DB *gorm.DB) Provider {
+	return &impl{
+		db: DB,
+	}
+}
+
+type impl struct {
+	db *gorm.DB
+}
+
+func (i impl) Create(rec dbmodels.PersonalDataAudit) (id string, err error) {
+	err = i.db.
+		Omit(clause.Associations).
+		Save(&rec).
+		Error
+	if err != nil {
+		return "", err
+	}
+	return rec.ID, nil
+}
+
+func (i impl) ListCount(spaceID string, filter personaldataapimodels.AuditFilter) (int64, error) {
+	var rowCount int64
+	tx := i.db.
+		Model(dbmodels.PersonalDataAudit{}).
+		Where("space_id = ?", spaceID)
+	if filter.ApplicantID != "" {
+		tx.Where("applicant_id = ?", filter.ApplicantID)
+	}
+	err := tx.Count(&rowCount).Error
+	if err != nil {
+		return 0, err
+	}
+	return rowCount, nil
+}
+
+func (i impl) List(spaceID string, filter personaldataapimodels.AuditFilter) ([]dbmodels.PersonalDataAudit, error) {
+	list := []dbmodels.PersonalDataAudit{}
+	tx := i.db.
+		Model(dbmodels.PersonalDataAudit{}).
+		Where("space_id = ?", spaceID)
+	if filter.ApplicantID != "" {
+		tx.Where("applicant_id = ?", filter.ApplicantID)
+	}
+	page, limit := filter.GetPage()
+	offset := (page - 1) * limit
+	err := tx.
+		Limit(limit).
+		Offset(offset).
+		Order("created_at desc").
+		Preload("User").
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
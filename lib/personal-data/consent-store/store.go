@@ -0,0 +1,83 @@
+package pdconsentstore
+
+import (
+	"hr-tools-backend/models"
+	dbmodels "hr-tools-backend/models/db"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Provider interface {
+	Create(rec dbmodels.PersonalDataConsent) (id string, err error)
+	Exist(applicantID string, source models.ConsentSource) (bool, error)
+	List(spaceID string, applicantIDs []string) ([]dbmodels.PersonalDataConsent, error)
+	// Anonymize удаление IP адреса и данных браузера из согласий кандидата
+	Anonymize(spaceID, applicantID string) error
+}
+
+func NewInstance(DB *gorm.DB) Provider {
+	return &impl{
+		db: DB,
+	}
+}
+
+type impl struct {
+	db *gorm.DB
+}
+
+func (i impl) Create(rec dbmodels.PersonalDataConsent) (id string, err error) {
+	err = i.db.
+		Omit(clause.Associations).
+		Save(&rec).
+		Error
+	if err != nil {
+		return "", err
+	}
+	return rec.ID, nil
+}
+
+func (i impl) Exist(applicantID string, source models.ConsentSource) (bool, error) {
+	var rowCount int64
+	err := i.db.
+		Model(&dbmodels.PersonalDataConsent{}).
+		Where("applicant_id = ?", applicantID).
+		Where("source = ?", source).
+		Count(&rowCount).
+		Error
+	if err != nil {
+		return false, err
+	}
+	return rowCount > 0, nil
+}
+
+func (i impl) List(spaceID string, applicantIDs []string) ([]dbmodels.PersonalDataConsent, error) {
+	list := []dbmodels.PersonalDataConsent{}
+	err := i.db.
+		Where("space_id = ?", spaceID).
+		Where("applicant_id in (?)", applicantIDs).
+		Order("created_at").
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (i impl) Anonymize(spaceID, applicantID string) error {
+	updMap := map[string]interface{}{
+		"ip":         "",
+		"user_agent": "",
+	}
+	err := i.db.
+		Model(&dbmodels.PersonalDataConsent{}).
+		Where("space_id = ?", spaceID).
+		Where("applicant_id = ?", applicantID).
+		Updates(updMap).
+		Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,616 @@
+package personaldata
+
+import (
+	"context"
+	"fmt"
+	"hr-tools-backend/db"
+	applicanthistorystore "hr-tools-backend/lib/applicant-history/store"
+	applicantstore "hr-tools-backend/lib/applicant/store"
+	candidatestore "hr-tools-backend/lib/candidate/store"
+	filestorage "hr-tools-backend/lib/file-storage"
+	filesdbstorage "hr-tools-backend/lib/file-storage/storage"
+	interviewbookingstore "hr-tools-backend/lib/interview/booking-store"
+	interviewstore "hr-tools-backend/lib/interview/store"
+	offerstore "hr-tools-backend/lib/offer/store"
+	offerversionstore "hr-tools-backend/lib/offer/version-store"
+	pdauditstore "hr-tools-backend/lib/personal-data/audit-store"
+	pdconsentstore "hr-tools-backend/lib/personal-data/consent-store"
+	pdrequeststore "hr-tools-backend/lib/personal-data/request-store"
+	scorecardstore "hr-tools-backend/lib/scorecard/store"
+	spacesettingsstore "hr-tools-backend/lib/space/settings/store"
+	initchecker "hr-tools-backend/lib/utils/init-checker"
+	"hr-tools-backend/models"
+	applicantapimodels "hr-tools-backend/models/api/applicant"
+	interviewapimodels "hr-tools-backend/models/api/interview"
+	offerapimodels "hr-tools-backend/models/api/offer"
+	personaldataapimodels "hr-tools-backend/models/api/personal-data"
+	scorecardapimodels "hr-tools-backend/models/api/scorecard"
+	dbmodels "hr-tools-backend/models/db"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type Provider interface {
+	// SaveConsent регистрация согласия кандидата на обработку ПД с действующей ссылкой на политику
+	SaveConsent(spaceID, applicantID string, meta personaldataapimodels.ConsentMeta) error
+	HasConsent(applicantID string, source models.ConsentSource) (bool, error)
+	// ListConsents согласия кандидата по всем его вакансиям
+	ListConsents(spaceID, applicantID string) ([]personaldataapimodels.ConsentView, error)
+	CreateRequest(spaceID, userID string, data personaldataapimodels.RequestData) (id, hMsg string, err error)
+	ListRequests(spaceID string, filter personaldataapimodels.RequestFilter) (list []personaldataapimodels.RequestView, rowCount int64, err error)
+	// ExecuteRequest исполнение запроса субъекта ПД: выгрузка данных (export) или обезличивание (erasure)
+	ExecuteRequest(ctx context.Context, spaceID, id, userID string) (export *personaldataapimodels.DataExport, hMsg string, err error)
+	RejectRequest(spaceID, id, userID string, data personaldataapimodels.RejectRequest) (hMsg string, err error)
+	ListAudit(spaceID string, filter personaldataapimodels.AuditFilter) (list []personaldataapimodels.AuditView, rowCount int64, err error)
+	// Anonymize обезличивание кандидата: удаление ПД, файлов и ответов на анкеты, очистка ПД в интервью, офферах и оценочных картах
+	// с сохранением записей для статистики
+	Anonymize(ctx context.Context, spaceID, applicantID string, userID, requestID *string) error
+	// Purge полное удаление кандидата, в журнале ПД остается запись об удалении
+	Purge(ctx context.Context, spaceID, applicantID string) error
+}
+
+var Instance Provider
+
+func NewHandler() {
+	instance := impl{
+		consentStore:      pdconsentstore.NewInstance(db.DB),
+		requestStore:      pdrequeststore.NewInstance(db.DB),
+		auditStore:        pdauditstore.NewInstance(db.DB),
+		applicantStore:    applicantstore.NewInstance(db.DB),
+		candidateStore:    candidatestore.NewInstance(db.DB),
+		historyStore:      applicanthistorystore.NewInstance(db.DB),
+		filesStore:        filesdbstorage.NewInstance(db.DB),
+		settingsStore:     spacesettingsstore.NewInstance(db.DB),
+		interviewStore:    interviewstore.NewInstance(db.DB),
+		bookingStore:      interviewbookingstore.NewInstance(db.DB),
+		offerStore:        offerstore.NewInstance(db.DB),
+		offerVersionStore: offerversionstore.NewInstance(db.DB),
+		scorecardStore:    scorecardstore.NewInstance(db.DB),
+	}
+	initchecker.CheckInit(
+		"consentStore", instance.consentStore,
+		"requestStore", instance.requestStore,
+		"auditStore", instance.auditStore,
+		"applicantStore", instance.applicantStore,
+		"candidateStore", instance.candidateStore,
+		"historyStore", instance.historyStore,
+		"filesStore", instance.filesStore,
+		"settingsStore", instance.settingsStore,
+		"interviewStore", instance.interviewStore,
+		"bookingStore", instance.bookingStore,
+		"offerStore", instance.offerStore,
+		"offerVersionStore", instance.offerVersionStore,
+		"scorecardStore", instance.scorecardStore,
+	)
+	Instance = instance
+}
+
+type impl struct {
+	consentStore      pdconsentstore.Provider
+	requestStore      pdrequeststore.Provider
+	auditStore        pdauditstore.Provider
+	applicantStore    applicantstore.Provider
+	candidateStore    candidatestore.Provider
+	historyStore      applicanthistorystore.Provider
+	filesStore        filesdbstorage.Provider
+	settingsStore     spacesettingsstore.Provider
+	interviewStore    interviewstore.Provider
+	bookingStore      interviewbookingstore.Provider
+	offerStore        offerstore.Provider
+	offerVersionStore offerversionstore.Provider
+	scorecardStore    scorecardstore.Provider
+}
+
+func (i impl) getLogger(spaceID, applicantID string) *log.Entry {
+	logger := log.WithField("space_id", spaceID)
+	if applicantID != "" {
+		logger = logger.WithField("applicant_id", applicantID)
+	}
+	return logger
+}
+
+func (i impl) SaveConsent(spaceID, applicantID string, meta personaldataapimodels.ConsentMeta) error {
+	policyUrl, err := i.settingsStore.GetValueByCode(spaceID, models.PdPolicyUrlSetting)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.Wrap(err, "ошибка получения ссылки на политику обработки ПД")
+	}
+	rec := dbmodels.PersonalDataConsent{
+		BaseSpaceModel: dbmodels.BaseSpaceModel{
+			SpaceID: spaceID,
+		},
+		ApplicantID: applicantID,
+		Source:      meta.Source,
+		PolicyUrl:   policyUrl,
+		IP:          meta.IP,
+		UserAgent:   meta.UserAgent,
+	}
+	_, err = i.consentStore.Create(rec)
+	if err != nil {
+		return errors.Wrap(err, "ошибка сохранения согласия на обработку ПД")
+	}
+	i.audit(spaceID, applicantID, nil, nil, models.PdAuditConsentGiven,
+		fmt.Sprintf("Получено согласие на обработку ПД (%v)", meta.Source))
+	return nil
+}
+
+func (i impl) HasConsent(applicantID string, source models.ConsentSource) (bool, error) {
+	ok, err := i.consentStore.Exist(applicantID, source)
+	if err != nil {
+		return false, errors.Wrap(err, "ошибка проверки согласия на обработку ПД")
+	}
+	return ok, nil
+}
+
+func (i impl) ListConsents(spaceID, applicantID string) ([]personaldataapimodels.ConsentView, error) {
+	applicantIDs, err := i.getSubjectApplicantIDs(spaceID, applicantID)
+	if err != nil {
+		return nil, err
+	}
+	list, err := i.consentStore.List(spaceID, applicantIDs)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения списка согласий")
+	}
+	result := make([]personaldataapimodels.ConsentView, 0, len(list))
+	for _, rec := range list {
+		result = append(result, personaldataapimodels.ConsentConvert(rec))
+	}
+	return result, nil
+}
+
+func (i impl) CreateRequest(spaceID, userID string, data personaldataapimodels.RequestData) (id, hMsg string, err error) {
+	applicant, err := i.applicantStore.GetByID(spaceID, data.ApplicantID)
+	if err != nil {
+		return "", "", errors.Wrap(err, "ошибка получения кандидата")
+	}
+	if applicant == nil {
+		return "", "кандидат не найден", nil
+	}
+	rec := dbmodels.DataSubjectRequest{
+		BaseSpaceModel: dbmodels.BaseSpaceModel{
+			SpaceID: spaceID,
+		},
+		ApplicantID: data.ApplicantID,
+		RequestType: data.RequestType,
+		Status:      models.DataSubjectRequestNew,
+		Comment:     data.Comment,
+		AuthorID:    userID,
+	}
+	id, err = i.requestStore.Create(rec)
+	if err != nil {
+		return "", "", errors.Wrap(err, "ошибка регистрации запроса субъекта ПД")
+	}
+	i.audit(spaceID, data.ApplicantID, &id, &userID, models.PdAuditRequestCreated,
+		fmt.Sprintf("Зарегистрирован запрос субъекта ПД (%v)", data.RequestType))
+	return id, "", nil
+}
+
+func (i impl) ListRequests(spaceID string, filter personaldataapimodels.RequestFilter) (list []personaldataapimodels.RequestView, rowCount int64, err error) {
+	rowCount, err = i.requestStore.ListCount(spaceID, filter)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "ошибка получения количества запросов субъектов ПД")
+	}
+	page, limit := filter.GetPage()
+	offset := (page - 1) * limit
+	if int64(offset) > rowCount {
+		return []personaldataapimodels.RequestView{}, rowCount, nil
+	}
+	recList, err := i.requestStore.List(spaceID, filter)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "ошибка получения списка запросов субъектов ПД")
+	}
+	list = make([]personaldataapimodels.RequestView, 0, len(recList))
+	for _, rec := range recList {
+		list = append(list, personaldataapimodels.RequestConvert(rec))
+	}
+	return list, rowCount, nil
+}
+
+func (i impl) ExecuteRequest(ctx context.Context, spaceID, id, userID string) (export *personaldataapimodels.DataExport, hMsg string, err error) {
+	rec, err := i.requestStore.GetByID(spaceID, id)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "ошибка получения запроса субъекта ПД")
+	}
+	if rec == nil {
+		return nil, "запрос не найден", nil
+	}
+	if rec.Status != models.DataSubjectRequestNew {
+		return nil, "запрос уже обработан", nil
+	}
+	applicantIDs, err := i.getSubjectApplicantIDs(spaceID, rec.ApplicantID)
+	if err != nil {
+		return nil, "", err
+	}
+	switch rec.RequestType {
+	case models.DataSubjectRequestExport:
+		export, err = i.export(spaceID, applicantIDs)
+		if err != nil {
+			return nil, "", err
+		}
+		i.audit(spaceID, rec.ApplicantID, &id, &userID, models.PdAuditExport, "Выгрузка персональных данных по запросу субъекта")
+	case models.DataSubjectRequestErasure:
+		for _, applicantID := range applicantIDs {
+			err = i.Anonymize(ctx, spaceID, applicantID, &userID, &id)
+			if err != nil {
+				return nil, "", err
+			}
+		}
+	default:
+		return nil, "некорректный тип запроса", nil
+	}
+	now := time.Now()
+	updMap := map[string]interface{}{
+		"status":          models.DataSubjectRequestDone,
+		"processed_by_id": userID,
+		"processed_at":    &now,
+	}
+	err = i.requestStore.Update(spaceID, id, updMap)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "ошибка изменения статуса запроса субъекта ПД")
+	}
+	return export, "", nil
+}
+
+func (i impl) RejectRequest(spaceID, id, userID string, data personaldataapimodels.RejectRequest) (hMsg string, err error) {
+	rec, err := i.requestStore.GetByID(spaceID, id)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения запроса субъекта ПД")
+	}
+	if rec == nil {
+		return "запрос не найден", nil
+	}
+	if rec.Status != models.DataSubjectRequestNew {
+		return "запрос уже обработан", nil
+	}
+	now := time.Now()
+	updMap := map[string]interface{}{
+		"status":          models.DataSubjectRequestRejected,
+		"processed_by_id": userID,
+		"processed_at":    &now,
+		"reject_reason":   data.Reason,
+	}
+	err = i.requestStore.Update(spaceID, id, updMap)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка изменения статуса запроса субъекта ПД")
+	}
+	i.audit(spaceID, rec.ApplicantID, &id, &userID, models.PdAuditRequestRejected,
+		fmt.Sprintf("Отказ в исполнении запроса субъекта ПД: %v", data.Reason))
+	return "", nil
+}
+
+func (i impl) ListAudit(spaceID string, filter personaldataapimodels.AuditFilter) (list []personaldataapimodels.AuditView, rowCount int64, err error) {
+	rowCount, err = i.auditStore.ListCount(spaceID, filter)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "ошибка получения количества записей журнала ПД")
+	}
+	page, limit := filter.GetPage()
+	offset := (page - 1) * limit
+	if int64(offset) > rowCount {
+		return []personaldataapimodels.AuditView{}, rowCount, nil
+	}
+	recList, err := i.auditStore.List(spaceID, filter)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "ошибка получения журнала ПД")
+	}
+	list = make([]personaldataapimodels.AuditView, 0, len(recList))
+	for _, rec := range recList {
+		list = append(list, personaldataapimodels.AuditConvert(rec))
+	}
+	return list, rowCount, nil
+}
+
+func (i impl) Anonymize(ctx context.Context, spaceID, applicantID string, userID, requestID *string) error {
+	logger := i.getLogger(spaceID, applicantID)
+	applicant, err := i.applicantStore.GetByID(spaceID, applicantID)
+	if err != nil {
+		return errors.Wrap(err, "ошибка получения кандидата")
+	}
+	if applicant == nil || applicant.AnonymizedAt != nil {
+		return nil
+	}
+	err = i.deleteFiles(ctx, spaceID, applicantID)
+	if err != nil {
+		return err
+	}
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		applicantStore := applicantstore.NewInstance(tx)
+		err := applicantStore.Update(applicantID, getAnonymizeMap(time.Now()))
+		if err != nil {
+			return errors.Wrap(err, "ошибка обезличивания кандидата")
+		}
+		err = applicantStore.DeleteArtifacts(applicantID)
+		if err != nil {
+			return err
+		}
+		err = applicantStore.AnonymizeRelated(applicantID)
+		if err != nil {
+			return err
+		}
+		err = applicanthistorystore.NewInstance(tx).ClearChanges(spaceID, applicantID)
+		if err != nil {
+			return errors.Wrap(err, "ошибка обезличивания истории кандидата")
+		}
+		err = pdconsentstore.NewInstance(tx).Anonymize(spaceID, applicantID)
+		if err != nil {
+			return errors.Wrap(err, "ошибка обезличивания согласий кандидата")
+		}
+		if applicant.CandidateID != nil {
+			return i.deleteCandidateIfEmpty(tx, spaceID, *applicant.CandidateID)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	i.audit(spaceID, applicantID, requestID, userID, models.PdAuditAnonymize, "Персональные данные кандидата обезличены")
+	logger.Info("персональные данные кандидата обезличены")
+	return nil
+}
+
+func (i impl) Purge(ctx context.Context, spaceID, applicantID string) error {
+	logger := i.getLogger(spaceID, applicantID)
+	applicant, err := i.applicantStore.GetByID(spaceID, applicantID)
+	if err != nil {
+		return errors.Wrap(err, "ошибка получения кандидата")
+	}
+	if applicant == nil {
+		return nil
+	}
+	err = i.deleteFiles(ctx, spaceID, applicantID)
+	if err != nil {
+		return err
+	}
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		err := applicantstore.NewInstance(tx).Delete(spaceID, applicantID)
+		if err != nil {
+			return errors.Wrap(err, "ошибка удаления кандидата")
+		}
+		if applicant.CandidateID != nil {
+			return i.deleteCandidateIfEmpty(tx, spaceID, *applicant.CandidateID)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	i.audit(spaceID, applicantID, nil, nil, models.PdAuditPurge, "Кандидат удален по истечении срока хранения ПД")
+	logger.Info("кандидат удален по истечении срока хранения ПД")
+	return nil
+}
+
+// getSubjectApplicantIDs кандидаты на вакансии, относящиеся к тому же субъекту ПД (человеку)
+func (i impl) getSubjectApplicantIDs(spaceID, applicantID string) ([]string, error) {
+	applicant, err := i.applicantStore.GetByID(spaceID, applicantID)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения кандидата")
+	}
+	if applicant == nil {
+		return []string{applicantID}, nil
+	}
+	if applicant.CandidateID == nil {
+		return []string{applicantID}, nil
+	}
+	candidate, err := i.candidateStore.GetByID(spaceID, *applicant.CandidateID)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения кандидата")
+	}
+	if candidate == nil {
+		return []string{applicantID}, nil
+	}
+	result := make([]string, 0, len(candidate.Applicants))
+	for _, rec := range candidate.Applicants {
+		result = append(result, rec.ID)
+	}
+	return result, nil
+}
+
+func (i impl) export(spaceID string, applicantIDs []string) (*personaldataapimodels.DataExport, error) {
+	result := personaldataapimodels.DataExport{
+		ExportedAt: time.Now().Format("02.01.2006 15:04"),
+		Applicants: []applicantapimodels.ApplicantView{},
+	}
+	for _, applicantID := range applicantIDs {
+		applicant, err := i.applicantStore.GetByID(spaceID, applicantID)
+		if err != nil {
+			return nil, errors.Wrap(err, "ошибка получения кандидата")
+		}
+		if applicant == nil {
+			continue
+		}
+		result.Applicants = append(result.Applicants, applicantapimodels.ApplicantConvert(applicant.Applicant))
+		err = i.exportRelated(&result, spaceID, applicantID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	history, err := i.historyStore.ListAll(spaceID, applicantIDs)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения истории кандидата")
+	}
+	for _, rec := range history {
+		result.History = append(result.History, applicantapimodels.Convert(rec))
+	}
+	consents, err := i.consentStore.List(spaceID, applicantIDs)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения списка согласий")
+	}
+	for _, rec := range consents {
+		result.Consents = append(result.Consents, personaldataapimodels.ConsentConvert(rec))
+	}
+	return &result, nil
+}
+
+// exportRelated выгрузка файлов, интервью, офферов и оценочных карт кандидата
+func (i impl) exportRelated(result *personaldataapimodels.DataExport, spaceID, applicantID string) error {
+	files, err := i.getFiles(spaceID, applicantID)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		result.Files = append(result.Files, file.ToModel())
+	}
+	interviews, err := i.interviewStore.ListByApplicant(spaceID, applicantID)
+	if err != nil {
+		return errors.Wrap(err, "ошибка получения списка интервью кандидата")
+	}
+	for _, rec := range interviews {
+		result.Interviews = append(result.Interviews, interviewapimodels.InterviewConvert(rec))
+	}
+	bookings, err := i.bookingStore.ListByApplicant(spaceID, applicantID)
+	if err != nil {
+		return errors.Wrap(err, "ошибка получения ссылок на запись на интервью")
+	}
+	for _, rec := range bookings {
+		result.Bookings = append(result.Bookings, personaldataapimodels.BookingExportConvert(rec))
+	}
+	offers, err := i.offerStore.ListByApplicant(spaceID, applicantID)
+	if err != nil {
+		return errors.Wrap(err, "ошибка получения списка офферов кандидата")
+	}
+	for _, rec := range offers {
+		offer := personaldataapimodels.OfferExportView{
+			OfferView: offerapimodels.OfferConvert(rec),
+			Versions:  []offerapimodels.OfferVersionView{},
+		}
+		versions, err := i.offerVersionStore.List(spaceID, rec.ID)
+		if err != nil {
+			return errors.Wrap(err, "ошибка получения версий оффера")
+		}
+		for _, version := range versions {
+			offer.Versions = append(offer.Versions, offerapimodels.OfferVersionConvert(version))
+		}
+		result.Offers = append(result.Offers, offer)
+	}
+	scorecards, err := i.scorecardStore.ListByApplicant(spaceID, applicantID)
+	if err != nil {
+		return errors.Wrap(err, "ошибка получения оценочных карт кандидата")
+	}
+	for _, rec := range scorecards {
+		result.Scorecards = append(result.Scorecards, scorecardapimodels.ScorecardConvert(rec))
+	}
+	return nil
+}
+
+// getFiles файлы кандидата и документы его офферов (документ оффера хранится с идентификатором оффера)
+func (i impl) getFiles(spaceID, applicantID string) ([]dbmodels.FileStorage, error) {
+	files, err := i.filesStore.GetFileList(applicantID)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения списка файлов кандидата")
+	}
+	offers, err := i.offerStore.ListByApplicant(spaceID, applicantID)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения списка офферов кандидата")
+	}
+	for _, offer := range offers {
+		offerFiles, err := i.filesStore.GetFileList(offer.ID)
+		if err != nil {
+			return nil, errors.Wrap(err, "ошибка получения документов оффера")
+		}
+		files = append(files, offerFiles...)
+	}
+	return files, nil
+}
+
+func (i impl) deleteFiles(ctx context.Context, spaceID, applicantID string) error {
+	files, err := i.getFiles(spaceID, applicantID)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		err = filestorage.Instance.DeleteFile(ctx, spaceID, file.ID)
+		if err != nil {
+			return errors.Wrap(err, "ошибка удаления файла кандидата")
+		}
+	}
+	return nil
+}
+
+// deleteCandidateIfEmpty удаление карточки кандидата (человека), если не осталось его необезличенных вакансий
+func (i impl) deleteCandidateIfEmpty(tx *gorm.DB, spaceID, candidateID string) error {
+	candidateStore := candidatestore.NewInstance(tx)
+	candidate, err := candidateStore.GetByID(spaceID, candidateID)
+	if err != nil {
+		return errors.Wrap(err, "ошибка получения кандидата")
+	}
+	if candidate == nil {
+		return nil
+	}
+	for _, rec := range candidate.Applicants {
+		if rec.AnonymizedAt == nil {
+			return nil
+		}
+	}
+	err = tx.
+		Model(&dbmodels.Applicant{}).
+		Where("candidate_id = ?", candidateID).
+		Update("candidate_id", nil).
+		Error
+	if err != nil {
+		return errors.Wrap(err, "ошибка отвязки кандидата")
+	}
+	err = tx.
+		Where("candidate_id = ?", candidateID).
+		Delete(&dbmodels.TalentPoolCandidate{}).
+		Error
+	if err != nil {
+		return errors.Wrap(err, "ошибка удаления кандидата из кадровых резервов")
+	}
+	err = candidateStore.Delete(spaceID, candidateID)
+	if err != nil {
+		return errors.Wrap(err, "ошибка удаления карточки кандидата")
+	}
+	return nil
+}
+
+func (i impl) audit(spaceID, applicantID string, requestID, userID *string, action models.PdAuditAction, description string) {
+	rec := dbmodels.PersonalDataAudit{
+		BaseSpaceModel: dbmodels.BaseSpaceModel{
+			SpaceID: spaceID,
+		},
+		ApplicantID: applicantID,
+		RequestID:   requestID,
+		UserID:      userID,
+		Action:      action,
+		Description: description,
+	}
+	_, err := i.auditStore.Create(rec)
+	if err != nil {
+		i.getLogger(spaceID, applicantID).
+			WithError(err).
+			WithField("action", action).
+			Error("ошибка записи в журнал ПД")
+	}
+}
+
+// getAnonymizeMap поля кандидата, очищаемые при обезличивании.
+// Идентификатор отклика сохраняется, чтобы отклик не был повторно загружен с джоб-борда
+func getAnonymizeMap(now time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"first_name":       "",
+		"last_name":        "Обезличен",
+		"middle_name":      "",
+		"phone":            "",
+		"email":            "",
+		"salary":           0,
+		"address":          "",
+		"birth_date":       time.Time{},
+		"citizenship":      "",
+		"gender":           "",
+		"relocation":       "",
+		"total_experience": 0,
+		"comment":          "",
+		"params":           dbmodels.ApplicantParams{},
+		"custom_fields":    dbmodels.CustomFieldValues{},
+		"photo_url":        "",
+		"tags":             pq.StringArray{},
+		"resume_id":        "",
+		"ext_applicant_id": "",
+		"chat_id":          "",
+		"not_duplicates":   nil,
+		"candidate_id":     nil,
+		"anonymized_at":    &now,
+	}
+}
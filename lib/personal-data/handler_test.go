@@ -0,0 +1,236 @@
+package personaldata
+
+import (
+	"context"
+	applicanthistorystore "hr-tools-backend/lib/applicant-history/store"
+	applicantstore "hr-tools-backend/lib/applicant/store"
+	filestorage "hr-tools-backend/lib/file-storage"
+	filesdbstorage "hr-tools-backend/lib/file-storage/storage"
+	interviewbookingstore "hr-tools-backend/lib/interview/booking-store"
+	interviewstore "hr-tools-backend/lib/interview/store"
+	offerstore "hr-tools-backend/lib/offer/store"
+	offerversionstore "hr-tools-backend/lib/offer/version-store"
+	pdconsentstore "hr-tools-backend/lib/personal-data/consent-store"
+	scorecardstore "hr-tools-backend/lib/scorecard/store"
+	dbmodels "hr-tools-backend/models/db"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm/schema"
+)
+
+// notPersonalColumns поля кандидата, не содержащие персональных данных и сохраняемые при обезличивании
+var notPersonalColumns = map[string]bool{
+	"vacancy_id":              true,
+	"negotiation_id":          true, // защита от повторной загрузки отклика
+	"resume_title":            true, // желаемая должность
+	"source":                  true,
+	"negotiation_date":        true,
+	"negotiation_accept_date": true,
+	"status":                  true,
+	"negotiation_status":      true,
+	"selection_stage_id":      true,
+	"duplicate_id":            true,
+	"start_date":              true,
+	"reject_reason":           true,
+	"reject_initiator":        true,
+	"stage_deadline":          true,
+	"stage_overdue_at":        true,
+	"stage_escalated_at":      true,
+}
+
+func TestAnonymizeMapCoversApplicantColumns(t *testing.T) {
+	anonymizeMap := getAnonymizeMap(time.Now())
+	naming := schema.NamingStrategy{}
+	applicantType := reflect.TypeOf(dbmodels.Applicant{})
+	for n := 0; n < applicantType.NumField(); n++ {
+		field := applicantType.Field(n)
+		if field.Anonymous {
+			continue
+		}
+		if isRelation(field) {
+			// связанные записи удаляются отдельно
+			continue
+		}
+		column := naming.ColumnName("", field.Name)
+		if notPersonalColumns[column] {
+			continue
+		}
+		_, ok := anonymizeMap[column]
+		require.Truef(t, ok, "поле кандидата %v не очищается при обезличивании", column)
+	}
+}
+
+func isRelation(field reflect.StructField) bool {
+	if strings.Contains(field.Tag.Get("gorm"), "foreignKey") {
+		return true
+	}
+	fieldType := field.Type
+	if fieldType.Kind() == reflect.Ptr || fieldType.Kind() == reflect.Slice {
+		fieldType = fieldType.Elem()
+	}
+	return fieldType.Kind() == reflect.Struct && fieldType != reflect.TypeOf(time.Time{}) && field.Type.Kind() != reflect.Struct
+}
+
+const (
+	testSpaceID     = "space"
+	testApplicantID = "applicant"
+	testOfferID     = "offer"
+)
+
+type fakeApplicantStore struct {
+	applicantstore.Provider
+}
+
+func (s fakeApplicantStore) GetByID(spaceID, id string) (*dbmodels.ApplicantExt, error) {
+	rec := dbmodels.ApplicantExt{}
+	rec.ID = id
+	rec.SpaceID = spaceID
+	return &rec, nil
+}
+
+type fakeHistoryStore struct {
+	applicanthistorystore.Provider
+}
+
+func (s fakeHistoryStore) ListAll(spaceID string, applicantIDs []string) ([]dbmodels.ApplicantHistory, error) {
+	return nil, nil
+}
+
+type fakeConsentStore struct {
+	pdconsentstore.Provider
+}
+
+func (s fakeConsentStore) List(spaceID string, applicantIDs []string) ([]dbmodels.PersonalDataConsent, error) {
+	return nil, nil
+}
+
+// fakeFilesStore резюме кандидата и документ оффера, сохраненный с идентификатором оффера
+type fakeFilesStore struct {
+	filesdbstorage.Provider
+}
+
+func (s fakeFilesStore) GetFileList(applicantID string) ([]dbmodels.FileStorage, error) {
+	switch applicantID {
+	case testApplicantID:
+		return []dbmodels.FileStorage{{BaseSpaceModel: dbmodels.BaseSpaceModel{BaseModel: dbmodels.BaseModel{ID: "resume"}}, ApplicantID: applicantID}}, nil
+	case testOfferID:
+		return []dbmodels.FileStorage{{BaseSpaceModel: dbmodels.BaseSpaceModel{BaseModel: dbmodels.BaseModel{ID: "offer-document"}}, ApplicantID: applicantID}}, nil
+	}
+	return nil, nil
+}
+
+type fakeInterviewStore struct {
+	interviewstore.Provider
+}
+
+func (s fakeInterviewStore) ListByApplicant(spaceID, applicantID string) ([]dbmodels.Interview, error) {
+	return []dbmodels.Interview{{ApplicantID: applicantID, Location: "офис", Comment: "комментарий"}}, nil
+}
+
+type fakeBookingStore struct {
+	interviewbookingstore.Provider
+}
+
+func (s fakeBookingStore) ListByApplicant(spaceID, applicantID string) ([]dbmodels.InterviewBooking, error) {
+	return []dbmodels.InterviewBooking{{ApplicantID: applicantID, Location: "офис"}}, nil
+}
+
+type fakeOfferStore struct {
+	offerstore.Provider
+}
+
+func (s fakeOfferStore) ListByApplicant(spaceID, applicantID string) ([]dbmodels.Offer, error) {
+	rec := dbmodels.Offer{ApplicantID: applicantID, Salary: 100000, DeclineReason: "причина"}
+	rec.ID = testOfferID
+	return []dbmodels.Offer{rec}, nil
+}
+
+type fakeOfferVersionStore struct {
+	offerversionstore.Provider
+}
+
+func (s fakeOfferVersionStore) List(spaceID, offerID string) ([]dbmodels.OfferVersion, error) {
+	return []dbmodels.OfferVersion{{OfferID: offerID, Version: 1, Salary: 90000}}, nil
+}
+
+type fakeScorecardStore struct {
+	scorecardstore.Provider
+}
+
+func (s fakeScorecardStore) ListByApplicant(spaceID, applicantID string) ([]dbmodels.Scorecard, error) {
+	return []dbmodels.Scorecard{{ApplicantID: applicantID, Comment: "комментарий"}}, nil
+}
+
+type fakeFileStorage struct {
+	filestorage.Provider
+	deleted []string
+}
+
+func (f *fakeFileStorage) DeleteFile(ctx context.Context, spaceID, fileID string) error {
+	f.deleted = append(f.deleted, fileID)
+	return nil
+}
+
+func newTestHandler() impl {
+	return impl{
+		applicantStore:    fakeApplicantStore{},
+		historyStore:      fakeHistoryStore{},
+		consentStore:      fakeConsentStore{},
+		filesStore:        fakeFilesStore{},
+		interviewStore:    fakeInterviewStore{},
+		bookingStore:      fakeBookingStore{},
+		offerStore:        fakeOfferStore{},
+		offerVersionStore: fakeOfferVersionStore{},
+		scorecardStore:    fakeScorecardStore{},
+	}
+}
+
+func TestExportIncludesRelatedRecords(t *testing.T) {
+	export, err := newTestHandler().export(testSpaceID, []string{testApplicantID})
+	require.NoError(t, err)
+	require.Len(t, export.Applicants, 1)
+	require.Len(t, export.Interviews, 1)
+	require.Equal(t, "комментарий", export.Interviews[0].Comment)
+	require.Len(t, export.Bookings, 1)
+	require.Len(t, export.Offers, 1)
+	require.Equal(t, "причина", export.Offers[0].DeclineReason)
+	require.Len(t, export.Offers[0].Versions, 1)
+	require.Equal(t, 90000, export.Offers[0].Versions[0].Salary)
+	require.Len(t, export.Scorecards, 1)
+	require.Equal(t, "комментарий", export.Scorecards[0].Comment)
+
+	fileIDs := []string{}
+	for _, file := range export.Files {
+		fileIDs = append(fileIDs, file.ID)
+	}
+	require.ElementsMatch(t, []string{"resume", "offer-document"}, fileIDs)
+}
+
+func TestDeleteFilesIncludesOfferDocuments(t *testing.T) {
+	storage := &fakeFileStorage{}
+	prev := filestorage.Instance
+	filestorage.Instance = storage
+	t.Cleanup(func() { filestorage.Instance = prev })
+
+	err := newTestHandler().deleteFiles(context.Background(), testSpaceID, testApplicantID)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"resume", "offer-document"}, storage.deleted)
+}
+
+func TestScorecardRatingsWithoutComments(t *testing.T) {
+	ratings := dbmodels.ScorecardRatings{
+		{CompetencyID: "1", Rating: 4, Comment: "комментарий"},
+		{CompetencyID: "2", Rating: 5},
+	}
+	result := ratings.WithoutComments()
+	require.Equal(t, dbmodels.ScorecardRatings{
+		{CompetencyID: "1", Rating: 4},
+		{CompetencyID: "2", Rating: 5},
+	}, result)
+	// исходные оценки не изменяются
+	require.Equal(t, "комментарий", ratings[0].Comment)
+}
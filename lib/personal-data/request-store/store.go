@@ -0,0 +1,115 @@
+package pdrequeststore
+
+import (
+	personaldataapimodels "hr-tools-backend/models/api/personal-data"
+	dbmodels "hr-tools-backend/models/db"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Provider interface {
+	Create(rec dbmodels.DataSubjectRequest) (id string, err error)
+	Update(spaceID, id string, updMap map[string]interface{}) error
+	GetByID(spaceID, id string) (*dbmodels.DataSubjectRequest, error)
+	ListCount(spaceID string, filter personaldataapimodels.RequestFilter) (int64, error)
+	List(spaceID string, filter personaldataapimodels.RequestFilter) ([]dbmodels.DataSubjectRequest, error)
+}
+
+func NewInstance(DB *gorm.DB) Provider {
+	return &impl{
+		db: DB,
+	}
+}
+
+type impl struct {
+	db *gorm.DB
+}
+
+func (i impl) Create(rec dbmodels.DataSubjectRequest) (id string, err error) {
+	err = i.db.
+		Omit(clause.Associations).
+		Save(&rec).
+		Error
+	if err != nil {
+		return "", err
+	}
+	return rec.ID, nil
+}
+
+func (i impl) Update(spaceID, id string, updMap map[string]interface{}) error {
+	if len(updMap) == 0 {
+		return nil
+	}
+	err := i.db.
+		Model(&dbmodels.DataSubjectRequest{}).
+		Where("space_id = ?", spaceID).
+		Where("id = ?", id).
+		Updates(updMap).
+		Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (i impl) GetByID(spaceID, id string) (*dbmodels.DataSubjectRequest, error) {
+	rec := dbmodels.DataSubjectRequest{}
+	err := i.db.
+		Where("space_id = ?", spaceID).
+		Where("id = ?", id).
+		Preload(clause.Associations).
+		First(&rec).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (i impl) ListCount(spaceID string, filter personaldataapimodels.RequestFilter) (int64, error) {
+	var rowCount int64
+	tx := i.db.
+		Model(dbmodels.DataSubjectRequest{}).
+		Where("space_id = ?", spaceID)
+	i.addFilter(tx, filter)
+	err := tx.Count(&rowCount).Error
+	if err != nil {
+		return 0, err
+	}
+	return rowCount, nil
+}
+
+func (i impl) List(spaceID string, filter personaldataapimodels.RequestFilter) ([]dbmodels.DataSubjectRequest, error) {
+	list := []dbmodels.DataSubjectRequest{}
+	tx := i.db.
+		Model(dbmodels.DataSubjectRequest{}).
+		Where("space_id = ?", spaceID)
+	i.addFilter(tx, filter)
+	page, limit := filter.GetPage()
+	offset := (page - 1) * limit
+	err := tx.
+		Limit(limit).
+		Offset(offset).
+		Order("created_at desc").
+		Preload(clause.Associations).
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (i impl) addFilter(tx *gorm.DB, filter personaldataapimodels.RequestFilter) {
+	if filter.ApplicantID != "" {
+		tx.Where("applicant_id = ?", filter.ApplicantID)
+	}
+	if filter.Status != "" {
+		tx.Where("status = ?", filter.Status)
+	}
+}
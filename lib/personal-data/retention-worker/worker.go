@@ -0,0 +1,131 @@
+package pdretentionworker
+
+import (
+	"context"
+	"hr-tools-backend/db"
+	applicantstore "hr-tools-backend/lib/applicant/store"
+	personaldata "hr-tools-backend/lib/personal-data"
+	spacesettingsstore "hr-tools-backend/lib/space/settings/store"
+	spacestore "hr-tools-backend/lib/space/store"
+	baseworker "hr-tools-backend/lib/utils/base-worker"
+	"hr-tools-backend/lib/utils/helpers"
+	"hr-tools-backend/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+const batchSize = 100
+
+// Задача обезличивания/удаления отклоненных и архивных кандидатов по истечении срока хранения ПД
+func StartWorker(ctx context.Context) {
+	i := &impl{
+		BaseImpl:       *baseworker.NewInstance("PdRetentionWorker", 50*time.Second, 1*time.Hour),
+		spaceStore:     spacestore.NewInstance(db.DB),
+		settingsStore:  spacesettingsstore.NewInstance(db.DB),
+		applicantStore: applicantstore.NewInstance(db.DB),
+	}
+	go i.Run(ctx, i.handle)
+}
+
+type impl struct {
+	baseworker.BaseImpl
+	spaceStore     spacestore.Provider
+	settingsStore  spacesettingsstore.Provider
+	applicantStore applicantstore.Provider
+}
+
+func (i impl) handle(ctx context.Context) {
+	logger := i.GetLogger()
+	ids, err := i.spaceStore.GetActiveIds()
+	if err != nil {
+		logger.WithError(err).Error("ошибка получения списка спейсов")
+		return
+	}
+	for _, spaceID := range ids {
+		if helpers.IsContextDone(ctx) {
+			break
+		}
+		days, action, err := i.getPolicy(spaceID)
+		if err != nil {
+			logger.WithError(err).
+				WithField("space_id", spaceID).
+				Error("ошибка получения настроек срока хранения ПД")
+			continue
+		}
+		if days == 0 {
+			continue
+		}
+		before := time.Now().AddDate(0, 0, -days)
+		list, err := i.applicantStore.ListForRetention(spaceID, before, batchSize)
+		if err != nil {
+			logger.WithError(err).
+				WithField("space_id", spaceID).
+				Error("ошибка получения списка кандидатов с истекшим сроком хранения ПД")
+			continue
+		}
+		for _, rec := range list {
+			if helpers.IsContextDone(ctx) {
+				break
+			}
+			if action == models.PdRetentionPurge {
+				err = personaldata.Instance.Purge(ctx, spaceID, rec.ID)
+			} else {
+				err = personaldata.Instance.Anonymize(ctx, spaceID, rec.ID, nil, nil)
+			}
+			if err != nil {
+				logger.WithError(err).
+					WithField("space_id", spaceID).
+					WithField("applicant_id", rec.ID).
+					Errorf("ошибка обработки кандидата по истечении срока хранения ПД (%v)", action)
+			}
+		}
+	}
+}
+
+func (i impl) getPolicy(spaceID string) (days int, action models.PdRetentionAction, err error) {
+	daysValue, err := i.getSetting(spaceID, models.PdRetentionDaysSetting)
+	if err != nil {
+		return 0, "", err
+	}
+	actionValue, err := i.getSetting(spaceID, models.PdRetentionActionSetting)
+	if err != nil {
+		return 0, "", err
+	}
+	return parsePolicy(daysValue, actionValue)
+}
+
+func (i impl) getSetting(spaceID string, code models.SpaceSettingCode) (string, error) {
+	value, err := i.settingsStore.GetValueByCode(spaceID, code)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil
+		}
+		return "", errors.Wrapf(err, "ошибка получения настройки %v", code)
+	}
+	return value, nil
+}
+
+// parsePolicy срок хранения в днях (0 - без ограничения) и действие по его истечении, по умолчанию обезличивание
+func parsePolicy(daysValue, actionValue string) (days int, action models.PdRetentionAction, err error) {
+	daysValue = strings.TrimSpace(daysValue)
+	if daysValue == "" {
+		return 0, "", nil
+	}
+	days, err = strconv.Atoi(daysValue)
+	if err != nil || days < 0 {
+		return 0, "", errors.Errorf("некорректный срок хранения ПД: %v", daysValue)
+	}
+	switch models.PdRetentionAction(strings.TrimSpace(actionValue)) {
+	case "", models.PdRetentionAnonymize:
+		action = models.PdRetentionAnonymize
+	case models.PdRetentionPurge:
+		action = models.PdRetentionPurge
+	default:
+		return 0, "", errors.Errorf("некорректное действие по истечении срока хранения ПД: %v", actionValue)
+	}
+	return days, action, nil
+}
@@ -0,0 +1,35 @@
+package pdretentionworker
+
+import (
+	"hr-tools-backend/models"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePolicy(t *testing.T) {
+	t.Run("без ограничения", func(t *testing.T) {
+		days, _, err := parsePolicy("", "purge")
+		require.NoError(t, err)
+		require.Equal(t, 0, days)
+	})
+	t.Run("по умолчанию обезличивание", func(t *testing.T) {
+		days, action, err := parsePolicy(" 180 ", "")
+		require.NoError(t, err)
+		require.Equal(t, 180, days)
+		require.Equal(t, models.PdRetentionAnonymize, action)
+	})
+	t.Run("удаление", func(t *testing.T) {
+		_, action, err := parsePolicy("30", "purge")
+		require.NoError(t, err)
+		require.Equal(t, models.PdRetentionPurge, action)
+	})
+	t.Run("некорректные значения", func(t *testing.T) {
+		_, _, err := parsePolicy("полгода", "")
+		require.Error(t, err)
+		_, _, err = parsePolicy("-1", "")
+		require.Error(t, err)
+		_, _, err = parsePolicy("30", "delete")
+		require.Error(t, err)
+	})
+}
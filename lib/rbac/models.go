@@ -21,13 +21,22 @@ const (
 	ALL    HTTPMethod = "ALL"
 )
 
+// Rule правило доступа к методу API
+type Rule struct {
+	Module     models.Module
+	Permission models.Permission
+	Handler    models.RbacFunc
+	// доступ определяется только ролью, для роли спейса достаточно разрешения в матрице
+	ByRole bool
+}
+
 type PathRule struct {
 	// проверки (от быстрых к медленным)
-	Exact    map[string]models.RbacFunc // Точные совпадения
-	Patterns []PatternRule              // Regexp правила
+	Exact    map[string]Rule // Точные совпадения
+	Patterns []PatternRule   // Regexp правила
 }
 
 type PatternRule struct {
+	Rule
 	Pattern *regexp.Regexp
-	Handler models.RbacFunc
 }
@@ -1,17 +1,41 @@
 package rbac
 
 import (
+	"fmt"
+	"github.com/patrickmn/go-cache"
 	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"hr-tools-backend/db"
+	spacerolestore "hr-tools-backend/lib/space/roles/store"
 	"hr-tools-backend/models"
+	dbmodels "hr-tools-backend/models/db"
 	"regexp"
 	"strings"
 	"slices"
+	"time"
 )
 
+// роль спейса пользователя кешируется, изменения в других экземплярах сервиса вступают в силу в течение этого времени
+const userRoleCacheTTL = time.Minute
+
 type Provider interface {
 	GetRuleFunc(method, path string) (models.RbacFunc, bool)
+	// GetRule правило доступа к методу API с модулем и разрешением, к которым оно относится
+	GetRule(method, path string) (Rule, bool)
 	RegisterRule(module models.Module, permission models.Permission, roles []models.UserRole, swaggerPattern string, handler models.RbacFunc)
+	// GetPermissions разрешения встроенной роли
 	GetPermissions(role models.UserRole) map[models.Module][]models.Permission
+	// GetUserPermissions действующие разрешения пользователя: матрица роли спейса, если она назначена, иначе разрешения встроенной роли
+	GetUserPermissions(userID string, role models.UserRole) map[models.Module][]models.Permission
+	// CheckGrant пользователь не может выдать (роли, ключу) разрешения, которых нет у него самого.
+	// Возвращает сообщение для пользователя, пусто - все разрешения есть у пользователя
+	CheckGrant(userID string, role models.UserRole, permissions map[models.Module][]models.Permission) string
+	// IsAllowed проверка доступа пользователя к методу API, методы без правила доступны
+	IsAllowed(spaceID, userID string, role models.UserRole, method, path string) bool
+	// GetCatalog модули и разрешения, к которым относятся методы API
+	GetCatalog() map[models.Module][]models.Permission
+	// ResetCache сброс кеша ролей спейса после изменения матрицы или назначения роли
+	ResetCache()
 }
 
 var Instance Provider
@@ -20,6 +44,8 @@ func NewHandler() {
 	i := &impl{
 		rules:       map[HTTPMethod]*PathRule{},
 		permissions: map[models.UserRole]map[models.Module][]models.Permission{},
+		roleStore:   spacerolestore.NewInstance(db.DB),
+		cache:       cache.New(userRoleCacheTTL, 5*userRoleCacheTTL),
 	}
 	Instance = i
 	i.initRules()
@@ -28,19 +54,29 @@ func NewHandler() {
 type impl struct {
 	rules       map[HTTPMethod]*PathRule
 	permissions map[models.UserRole]map[models.Module][]models.Permission
+	roleStore   spacerolestore.Provider
+	cache       *cache.Cache
 }
 
 func (i *impl) GetRuleFunc(method, path string) (models.RbacFunc, bool) {
+	rule, found := i.GetRule(method, path)
+	if !found {
+		return nil, false
+	}
+	return rule.Handler, true
+}
+
+func (i *impl) GetRule(method, path string) (Rule, bool) {
 	normalizedPath := normalizePath(path)
 	httpMethod := HTTPMethod(strings.ToUpper(method))
 
 	if pathRule, exists := i.rules[httpMethod]; exists {
-		if handler, found := i.findInPathRule(pathRule, normalizedPath); found {
-			return handler, true
+		if rule, found := i.findInPathRule(pathRule, normalizedPath); found {
+			return rule, true
 		}
 	}
 
-	return nil, false
+	return Rule{}, false
 }
 
 func (i *impl) RegisterRule(module models.Module, permission models.Permission, roles []models.UserRole, swaggerPattern string, handler models.RbacFunc) {
@@ -66,29 +102,36 @@ func (i *impl) RegisterRule(module models.Module, permission models.Permission,
 
 	if _, exists := i.rules[method]; !exists {
 		i.rules[method] = &PathRule{
-			Exact:    make(map[string]models.RbacFunc),
+			Exact:    make(map[string]Rule),
 			Patterns: []PatternRule{},
 		}
 	}
 
 	// Заполняем правила для фильтрации
-	if handler == nil {
+	byRole := handler == nil
+	if byRole {
 		handler = AllowByRoleFunc(roles)
 	}
+	rule := Rule{
+		Module:     module,
+		Permission: permission,
+		Handler:    handler,
+		ByRole:     byRole,
+	}
 	pathRule := i.rules[method]
 	// Определяем тип пути и добавляем в соответствующую категорию
 	if isExactPath(path) {
-		pathRule.Exact[path] = handler
+		pathRule.Exact[path] = rule
 	} else {
 		// Конвертируем путь в regexp
 		pattern := pathToRegex(path)
 		if pattern == nil {
 			// Если не удалось скомпилировать, добавляем как точное совпадение
-			pathRule.Exact[path] = handler
+			pathRule.Exact[path] = rule
 		} else {
 			pathRule.Patterns = append(pathRule.Patterns, PatternRule{
+				Rule:    rule,
 				Pattern: pattern,
-				Handler: handler,
 			})
 		}
 	}
@@ -99,6 +142,97 @@ func (i *impl) GetPermissions(role models.UserRole) map[models.Module][]models.P
 	return i.permissions[role]
 }
 
+func (i *impl) GetUserPermissions(userID string, role models.UserRole) map[models.Module][]models.Permission {
+	spaceRole, err := i.getUserRole(userID)
+	if err != nil {
+		log.WithField("user_id", userID).WithError(err).Error("ошибка получения роли спейса пользователя")
+		return map[models.Module][]models.Permission{}
+	}
+	if spaceRole == nil {
+		return i.GetPermissions(role)
+	}
+	// в матрице могут остаться разрешения, для которых больше нет методов API
+	result := map[models.Module][]models.Permission{}
+	for module, permissions := range i.GetCatalog() {
+		for _, permission := range permissions {
+			if spaceRole.Permissions.Has(module, permission) {
+				result[module] = append(result[module], permission)
+			}
+		}
+	}
+	return result
+}
+
+func (i *impl) CheckGrant(userID string, role models.UserRole, permissions map[models.Module][]models.Permission) string {
+	userPermissions := i.GetUserPermissions(userID, role)
+	for module, list := range permissions {
+		for _, permission := range list {
+			if !slices.Contains(userPermissions[module], permission) {
+				return fmt.Sprintf("нельзя выдать разрешение %v/%v, которого нет у вас", module, permission)
+			}
+		}
+	}
+	return ""
+}
+
+func (i *impl) IsAllowed(spaceID, userID string, role models.UserRole, method, path string) bool {
+	rule, found := i.GetRule(method, path)
+	if !found {
+		return true
+	}
+	spaceRole, err := i.getUserRole(userID)
+	if err != nil {
+		log.WithField("user_id", userID).WithError(err).Error("ошибка получения роли спейса пользователя")
+		return false
+	}
+	if spaceRole == nil {
+		return rule.Handler(spaceID, userID, role, path)
+	}
+	if !spaceRole.Permissions.Has(rule.Module, rule.Permission) {
+		return false
+	}
+	// дополнительные условия правила (например, доступ только к своим записям) действуют и для роли спейса
+	return rule.ByRole || rule.Handler(spaceID, userID, role, path)
+}
+
+func (i *impl) GetCatalog() map[models.Module][]models.Permission {
+	result := map[models.Module][]models.Permission{}
+	add := func(rule Rule) {
+		if !slices.Contains(result[rule.Module], rule.Permission) {
+			result[rule.Module] = append(result[rule.Module], rule.Permission)
+		}
+	}
+	for _, pathRule := range i.rules {
+		for _, rule := range pathRule.Exact {
+			add(rule)
+		}
+		for _, patternRule := range pathRule.Patterns {
+			add(patternRule.Rule)
+		}
+	}
+	for module := range result {
+		slices.Sort(result[module])
+	}
+	return result
+}
+
+func (i *impl) ResetCache() {
+	i.cache.Flush()
+}
+
+// getUserRole роль спейса пользователя, nil - действуют разрешения встроенной роли
+func (i *impl) getUserRole(userID string) (*dbmodels.SpaceRole, error) {
+	if value, found := i.cache.Get(userID); found {
+		return value.(*dbmodels.SpaceRole), nil
+	}
+	rec, err := i.roleStore.GetUserRole(userID)
+	if err != nil {
+		return nil, err
+	}
+	i.cache.SetDefault(userID, rec)
+	return rec, nil
+}
+
 func isExactPath(path string) bool {
 	return !strings.ContainsAny(path, "{")
 }
@@ -125,24 +259,24 @@ func pathToRegex(path string) *regexp.Regexp {
 	return regex
 }
 
-func (i *impl) findInPathRule(pathRule *PathRule, path string) (models.RbacFunc, bool) {
+func (i *impl) findInPathRule(pathRule *PathRule, path string) (Rule, bool) {
 	if pathRule == nil {
-		return nil, false
+		return Rule{}, false
 	}
 
 	// 1. Проверяем точные совпадения
-	if handler, exists := pathRule.Exact[path]; exists {
-		return handler, true
+	if rule, exists := pathRule.Exact[path]; exists {
+		return rule, true
 	}
 
 	// 2. Проверяем regexp паттерны
 	for _, patternRule := range pathRule.Patterns {
 		if patternRule.Pattern.MatchString(path) {
-			return patternRule.Handler, true
+			return patternRule.Rule, true
 		}
 	}
 
-	return nil, false
+	return Rule{}, false
 }
 
 func AllowByRoleFunc(accessRoles []models.UserRole) models.RbacFunc {
@@ -1,8 +1,13 @@
 package rbac
 
 import (
+	spacerolestore "hr-tools-backend/lib/space/roles/store"
+	"hr-tools-backend/models"
+	dbmodels "hr-tools-backend/models/db"
 	"testing"
+	"time"
 
+	"github.com/patrickmn/go-cache"
 	"github.com/stretchr/testify/require"
 )
 
@@ -35,4 +40,127 @@ func TestRbac(t *testing.T) {
 		require.Equal(t, false, isMatch)
 	})
 
+	t.Run(`GetRule check`, func(t *testing.T) {
+		i := &impl{
+			rules:       map[HTTPMethod]*PathRule{},
+			permissions: map[models.UserRole]map[models.Module][]models.Permission{},
+		}
+		i.RegisterRule(models.SecurityModule, models.ManagePermission, AdminRoleSet, "/api/v1/space/api_key/{id}/rotate [put]", nil)
+
+		rule, found := i.GetRule("put", "/api/v1/space/api_key/123-321/rotate/")
+		require.Equal(t, true, found)
+		require.Equal(t, models.SecurityModule, rule.Module)
+		require.Equal(t, models.ManagePermission, rule.Permission)
+		require.NotNil(t, rule.Handler)
+
+		_, found = i.GetRule("get", "/api/v1/space/api_key/123-321/rotate")
+		require.Equal(t, false, found)
+	})
+
+	t.Run(`space role check`, func(t *testing.T) {
+		store := &fakeRoleStore{roles: map[string]*dbmodels.SpaceRole{
+			"custom-user": {
+				BaseRole: models.ManagerRole,
+				Permissions: dbmodels.SpaceRolePermissions{
+					models.SecurityModule: {models.ManagePermission, models.ViewPermission},
+					models.UsersModule:    {models.ViewPermission},
+				},
+			},
+		}}
+		i := &impl{
+			rules:       map[HTTPMethod]*PathRule{},
+			permissions: map[models.UserRole]map[models.Module][]models.Permission{},
+			roleStore:   store,
+			cache:       cache.New(time.Minute, time.Minute),
+		}
+		i.RegisterRule(models.SecurityModule, models.ManagePermission, AdminRoleSet, "/api/v1/space/api_key/{id}/rotate [put]", nil)
+		i.RegisterRule(models.UsersModule, models.ViewPermission, AllRoles, "/api/v1/space/users/{id} [get]", func(spaceID, userID string, role models.UserRole, path string) bool {
+			return false
+		})
+
+		require.Equal(t, map[models.Module][]models.Permission{
+			models.SecurityModule: {models.ManagePermission},
+			models.UsersModule:    {models.ViewPermission},
+		}, i.GetCatalog())
+
+		// встроенная роль
+		require.True(t, i.IsAllowed("space", "admin-user", models.AdminRole, "put", "/api/v1/space/api_key/1/rotate"))
+		require.False(t, i.IsAllowed("space", "manager-user", models.ManagerRole, "put", "/api/v1/space/api_key/1/rotate"))
+		require.True(t, i.IsAllowed("space", "manager-user", models.ManagerRole, "get", "/api/v1/space/unknown"))
+
+		// роль спейса: разрешение из матрицы, условие правила сохраняется
+		require.True(t, i.IsAllowed("space", "custom-user", models.ManagerRole, "put", "/api/v1/space/api_key/1/rotate"))
+		require.False(t, i.IsAllowed("space", "custom-user", models.ManagerRole, "get", "/api/v1/space/users/1"))
+		require.Equal(t, map[models.Module][]models.Permission{
+			models.SecurityModule: {models.ManagePermission},
+			models.UsersModule:    {models.ViewPermission},
+		}, i.GetUserPermissions("custom-user", models.ManagerRole))
+
+		// роль закеширована до сброса
+		store.roles["custom-user"] = &dbmodels.SpaceRole{BaseRole: models.ManagerRole}
+		require.True(t, i.IsAllowed("space", "custom-user", models.ManagerRole, "put", "/api/v1/space/api_key/1/rotate"))
+		i.ResetCache()
+		require.False(t, i.IsAllowed("space", "custom-user", models.ManagerRole, "put", "/api/v1/space/api_key/1/rotate"))
+		require.Empty(t, i.GetUserPermissions("custom-user", models.ManagerRole))
+	})
+
+	t.Run(`CheckGrant check`, func(t *testing.T) {
+		store := &fakeRoleStore{roles: map[string]*dbmodels.SpaceRole{
+			"custom-user": {
+				BaseRole: models.HRRole,
+				Permissions: dbmodels.SpaceRolePermissions{
+					models.UsersModule: {models.ViewPermission},
+				},
+			},
+		}}
+		i := &impl{
+			rules:       map[HTTPMethod]*PathRule{},
+			permissions: map[models.UserRole]map[models.Module][]models.Permission{},
+			roleStore:   store,
+			cache:       cache.New(time.Minute, time.Minute),
+		}
+		i.RegisterRule(models.SecurityModule, models.ManagePermission, AdminRoleSet, "/api/v1/space/roles [post]", nil)
+		i.RegisterRule(models.UsersModule, models.ViewPermission, AllRoles, "/api/v1/users/{id} [get]", nil)
+		i.RegisterRule(models.UsersModule, models.ManagePermission, AdminHrManagerRoleSet, "/api/v1/users/{id} [put]", nil)
+		security := map[models.Module][]models.Permission{models.SecurityModule: {models.ManagePermission}}
+		usersView := map[models.Module][]models.Permission{models.UsersModule: {models.ViewPermission}}
+		usersManage := map[models.Module][]models.Permission{models.UsersModule: {models.ManagePermission}}
+
+		// управление безопасностью по умолчанию есть только у администратора
+		require.Empty(t, i.CheckGrant("admin-user", models.AdminRole, security))
+		require.NotEmpty(t, i.CheckGrant("hr-user", models.HRRole, security))
+		require.Empty(t, i.CheckGrant("hr-user", models.HRRole, usersManage))
+		// для роли спейса действует ее матрица, а не базовая роль
+		require.Empty(t, i.CheckGrant("custom-user", models.HRRole, usersView))
+		require.NotEmpty(t, i.CheckGrant("custom-user", models.HRRole, usersManage))
+	})
+
+	t.Run(`security module check`, func(t *testing.T) {
+		i := &impl{
+			rules:       map[HTTPMethod]*PathRule{},
+			permissions: map[models.UserRole]map[models.Module][]models.Permission{},
+		}
+		i.security()
+		for _, role := range AllRoles {
+			_, ok := i.GetPermissions(role)[models.SecurityModule]
+			require.Equal(t, role == models.AdminRole, ok, role)
+		}
+		for _, path := range []string{"/api/v1/space/sso", "/api/v1/space/roles", "/api/v1/space/api_key", "/api/v1/space/webhook", "/api/v1/space/custom_fields"} {
+			rule, found := i.GetRule("post", path)
+			if !found {
+				rule, found = i.GetRule("put", path)
+			}
+			require.True(t, found, path)
+			require.Equal(t, models.SecurityModule, rule.Module, path)
+		}
+	})
+}
+
+type fakeRoleStore struct {
+	spacerolestore.Provider
+	roles map[string]*dbmodels.SpaceRole
+}
+
+func (s *fakeRoleStore) GetUserRole(userID string) (*dbmodels.SpaceRole, error) {
+	return s.roles[userID], nil
 }
@@ -22,6 +22,7 @@ func (i *impl) initRules() {
 	i.analytics()
 	i.profile()
 	i.companyProfile()
+	i.security()
 	i.dict()
 }
 
@@ -29,6 +30,8 @@ func (i *impl) addUsersRbac() {
 	//VIEW
 	i.RegisterRule(models.UsersModule, models.ViewPermission, AllRoles, "/api/v1/users/list [post]", nil)
 	i.RegisterRule(models.UsersModule, models.ViewPermission, AllRoles, "/api/v1/users/{id} [get]", nil)
+	i.RegisterRule(models.UsersModule, models.ViewPermission, AllRoles, "/api/v1/space/roles/list [get]", nil)
+	i.RegisterRule(models.UsersModule, models.ViewPermission, AllRoles, "/api/v1/space/roles/{id} [get]", nil)
 	//MANAGE
 	i.RegisterRule(models.UsersModule, models.ManagePermission, AdminHrManagerRoleSet, "/api/v1/users [post]", nil)
 	i.RegisterRule(models.UsersModule, models.ManagePermission, AdminHrManagerRoleSet, "/api/v1/users/{id} [delete]", nil)
@@ -50,11 +53,18 @@ func (i *impl) addVacancyReqestRbac() {
 	i.RegisterRule(models.VacancyRequestModule, models.EditPermission, AdminManagerSpecialistRoleSet, "/api/v1/space/vacancy_request/{id}/cancel [put]", selfAllow)
 	i.RegisterRule(models.VacancyRequestModule, models.EditPermission, AdminHrRoleSet, "/api/v1/space/vacancy_request/{id}/publish [put]", nil)
 	i.RegisterRule(models.VacancyRequestModule, models.EditPermission, AdminManagerSpecialistRoleSet, "/api/v1/space/vacancy_request/{id}/approvals [put]", selfAllow)
+	i.RegisterRule(models.VacancyRequestModule, models.EditPermission, AdminManagerSpecialistRoleSet, "/api/v1/space/vacancy_request/{id}/approvals/route [put]", selfAllow)
 	//FLOW
 	flowAllow := vacancyreqhandler.Instance.GetRbacFlowAllow()
 	i.RegisterRule(models.VacancyRequestModule, models.FlowPermission, AdminHrRoleSet, "/api/v1/space/vacancy_request/{id}/approvals/{taskId}/approve [post]", flowAllow)
 	i.RegisterRule(models.VacancyRequestModule, models.FlowPermission, AdminHrRoleSet, "/api/v1/space/vacancy_request/{id}/approvals/{taskId}/request_changes [post]", flowAllow)
 	i.RegisterRule(models.VacancyRequestModule, models.FlowPermission, AdminHrRoleSet, "/api/v1/space/vacancy_request/{id}/approvals/{taskId}/reject [post]", flowAllow)
+	//MANAGE маршруты согласования и заместители согласующих
+	i.RegisterRule(models.VacancyRequestModule, models.ManagePermission, AdminRoleSet, "/api/v1/space/approval_route [post]", nil)
+	i.RegisterRule(models.VacancyRequestModule, models.ManagePermission, AdminRoleSet, "/api/v1/space/approval_route/{id} [put]", nil)
+	i.RegisterRule(models.VacancyRequestModule, models.ManagePermission, AdminRoleSet, "/api/v1/space/approval_route/{id} [delete]", nil)
+	i.RegisterRule(models.VacancyRequestModule, models.ManagePermission, AdminRoleSet, "/api/v1/space/approval_route/delegate [put]", nil)
+	i.RegisterRule(models.VacancyRequestModule, models.ManagePermission, AdminRoleSet, "/api/v1/space/approval_route/delegate/{userId} [delete]", nil)
 }
 
 func (i *impl) addVacancyRbac() {
@@ -70,6 +80,8 @@ func (i *impl) addVacancyRbac() {
 	i.RegisterRule(models.VacancyModule, models.StagesPermission, AdminHrRoleSet, "/api/v1/space/vacancy/{id}/stage/change_order [put]", nil)
 	i.RegisterRule(models.VacancyModule, models.StagesPermission, AdminHrRoleSet, "/api/v1/space/vacancy/{id}/stage [post]", nil)
 	i.RegisterRule(models.VacancyModule, models.StagesPermission, AdminHrRoleSet, "/api/v1/space/vacancy/{id}/stage [delete]", nil)
+	i.RegisterRule(models.VacancyModule, models.StagesPermission, AdminHrRoleSet, "/api/v1/space/scorecard/template [post]", nil)
+	i.RegisterRule(models.VacancyModule, models.StagesPermission, AdminHrRoleSet, "/api/v1/space/scorecard/template/{id} [delete]", nil)
 	//TEAM
 	i.RegisterRule(models.VacancyModule, models.TeamPermission, AdminHrRoleSet, "/api/v1/space/vacancy/{id}/team/{user_id}/invite [put]", nil)
 	i.RegisterRule(models.VacancyModule, models.TeamPermission, AdminHrRoleSet, "/api/v1/space/vacancy/{id}/team/{user_id}/exclude [put]", nil)
@@ -88,6 +100,9 @@ func (i *impl) applicant() {
 	i.RegisterRule(models.ApplicantModule, models.EditPermission, AdminHrRoleSet, "/api/v1/space/applicant/{id}/reject [put]", nil)
 	i.RegisterRule(models.ApplicantModule, models.EditPermission, AdminHrRoleSet, "/api/v1/space/applicant/multi-actions/reject [put]", nil)
 	i.RegisterRule(models.ApplicantModule, models.EditPermission, AdminHrRoleSet, "/api/v1/space/applicant/multi-actions/change_stage [put]", nil)
+	i.RegisterRule(models.ApplicantModule, models.EditPermission, AdminHrRoleSet, "/api/v1/space/applicant/{id}/resume/suggestion [put]", nil)
+	i.RegisterRule(models.ApplicantModule, models.EditPermission, AdminHrRoleSet, "/api/v1/space/applicant/{id}/resume/suggestion [delete]", nil)
+	i.RegisterRule(models.ApplicantModule, models.EditPermission, AdminHrRoleSet, "/api/v1/space/applicant/{id}/consider [post]", nil)
 	//FILES/NOTES
 	i.RegisterRule(models.ApplicantModule, models.FilesPermission, AdminHrRoleSet, "/api/v1/space/applicant/{id}/upload-resume [post]", nil)
 	i.RegisterRule(models.ApplicantModule, models.FilesPermission, AdminHrRoleSet, "/api/v1/space/applicant/{id}/upload-doc [post]", nil)
@@ -96,6 +111,50 @@ func (i *impl) applicant() {
 	i.RegisterRule(models.ApplicantModule, models.FilesPermission, AdminHrRoleSet, "/api/v1/space/applicant/{id}/resume [delete]", nil)
 	i.RegisterRule(models.ApplicantModule, models.FilesPermission, AdminHrRoleSet, "/api/v1/space/applicant/doc/{id} [delete]", nil)
 	i.RegisterRule(models.ApplicantModule, models.NotesPermission, AdminHrRoleSet, "/api/v1/space/applicant/{id}/note [put]", nil)
+	//INTERVIEW
+	i.RegisterRule(models.ApplicantModule, models.ViewPermission, AdminHrManagerRoleSet, "/api/v1/space/interview/list [post]", nil)
+	i.RegisterRule(models.ApplicantModule, models.ViewPermission, AdminHrManagerRoleSet, "/api/v1/space/interview/{id} [get]", nil)
+	i.RegisterRule(models.ApplicantModule, models.EditPermission, AdminHrRoleSet, "/api/v1/space/interview [post]", nil)
+	i.RegisterRule(models.ApplicantModule, models.EditPermission, AdminHrRoleSet, "/api/v1/space/interview/{id} [put]", nil)
+	i.RegisterRule(models.ApplicantModule, models.EditPermission, AdminHrRoleSet, "/api/v1/space/interview/{id}/cancel [put]", nil)
+	i.RegisterRule(models.ApplicantModule, models.EditPermission, AdminHrRoleSet, "/api/v1/space/interview/booking [post]", nil)
+	i.RegisterRule(models.ApplicantModule, models.ViewPermission, AdminHrManagerRoleSet, "/api/v1/space/calendar/busy [post]", nil)
+	i.RegisterRule(models.ApplicantModule, models.ViewPermission, AdminHrManagerRoleSet, "/api/v1/space/offer/list [post]", nil)
+	i.RegisterRule(models.ApplicantModule, models.ViewPermission, AdminHrManagerRoleSet, "/api/v1/space/offer/{id} [get]", nil)
+	i.RegisterRule(models.ApplicantModule, models.EditPermission, AdminHrRoleSet, "/api/v1/space/offer [post]", nil)
+	i.RegisterRule(models.ApplicantModule, models.EditPermission, AdminHrRoleSet, "/api/v1/space/offer/{id} [put]", nil)
+	i.RegisterRule(models.ApplicantModule, models.EditPermission, AdminHrRoleSet, "/api/v1/space/offer/{id}/approvals [put]", nil)
+	i.RegisterRule(models.ApplicantModule, models.EditPermission, AdminHrRoleSet, "/api/v1/space/offer/{id}/on_approval [put]", nil)
+	i.RegisterRule(models.ApplicantModule, models.EditPermission, AdminHrRoleSet, "/api/v1/space/offer/{id}/send [put]", nil)
+	i.RegisterRule(models.ApplicantModule, models.EditPermission, AdminHrRoleSet, "/api/v1/space/offer/{id}/cancel [put]", nil)
+	//CANDIDATE/TALENT POOL
+	i.RegisterRule(models.ApplicantModule, models.ViewPermission, AdminHrManagerRoleSet, "/api/v1/space/candidate/list [post]", nil)
+	i.RegisterRule(models.ApplicantModule, models.ViewPermission, AdminHrManagerRoleSet, "/api/v1/space/candidate/{id} [get]", nil)
+	i.RegisterRule(models.ApplicantModule, models.ViewPermission, AdminHrManagerRoleSet, "/api/v1/space/candidate/{id}/history [post]", nil)
+	i.RegisterRule(models.ApplicantModule, models.ViewPermission, AdminHrManagerRoleSet, "/api/v1/space/candidate/{id}/files [get]", nil)
+	i.RegisterRule(models.ApplicantModule, models.EditPermission, AdminHrRoleSet, "/api/v1/space/candidate/{id} [put]", nil)
+	i.RegisterRule(models.ApplicantModule, models.ViewPermission, AdminHrManagerRoleSet, "/api/v1/space/talent_pool/list [get]", nil)
+	i.RegisterRule(models.ApplicantModule, models.ViewPermission, AdminHrManagerRoleSet, "/api/v1/space/talent_pool/{id} [get]", nil)
+	i.RegisterRule(models.ApplicantModule, models.EditPermission, AdminHrRoleSet, "/api/v1/space/talent_pool [post]", nil)
+	i.RegisterRule(models.ApplicantModule, models.EditPermission, AdminHrRoleSet, "/api/v1/space/talent_pool/{id} [put]", nil)
+	i.RegisterRule(models.ApplicantModule, models.EditPermission, AdminHrRoleSet, "/api/v1/space/talent_pool/{id} [delete]", nil)
+	i.RegisterRule(models.ApplicantModule, models.EditPermission, AdminHrRoleSet, "/api/v1/space/talent_pool/{id}/candidates [post]", nil)
+	i.RegisterRule(models.ApplicantModule, models.EditPermission, AdminHrRoleSet, "/api/v1/space/talent_pool/{id}/candidates/{candidate_id} [delete]", nil)
+	//PERSONAL DATA
+	i.RegisterRule(models.ApplicantModule, models.ViewPermission, AdminHrRoleSet, "/api/v1/space/personal_data/consent/{applicant_id} [get]", nil)
+	i.RegisterRule(models.ApplicantModule, models.ViewPermission, AdminHrRoleSet, "/api/v1/space/personal_data/request/list [post]", nil)
+	i.RegisterRule(models.ApplicantModule, models.ViewPermission, AdminRoleSet, "/api/v1/space/personal_data/audit/list [post]", nil)
+	i.RegisterRule(models.ApplicantModule, models.EditPermission, AdminHrRoleSet, "/api/v1/space/personal_data/request [post]", nil)
+	i.RegisterRule(models.ApplicantModule, models.ManagePermission, AdminRoleSet, "/api/v1/space/personal_data/request/{id}/execute [put]", nil)
+	i.RegisterRule(models.ApplicantModule, models.ManagePermission, AdminRoleSet, "/api/v1/space/personal_data/request/{id}/reject [put]", nil)
+	//AUTOMATION
+	i.RegisterRule(models.ApplicantModule, models.ViewPermission, AdminHrRoleSet, "/api/v1/space/automation_rule/list [get]", nil)
+	i.RegisterRule(models.ApplicantModule, models.ViewPermission, AdminHrRoleSet, "/api/v1/space/automation_rule/{id} [get]", nil)
+	i.RegisterRule(models.ApplicantModule, models.ViewPermission, AdminHrRoleSet, "/api/v1/space/automation_rule/log [post]", nil)
+	i.RegisterRule(models.ApplicantModule, models.ManagePermission, AdminHrRoleSet, "/api/v1/space/automation_rule [post]", nil)
+	i.RegisterRule(models.ApplicantModule, models.ManagePermission, AdminHrRoleSet, "/api/v1/space/automation_rule/{id} [put]", nil)
+	i.RegisterRule(models.ApplicantModule, models.ManagePermission, AdminHrRoleSet, "/api/v1/space/automation_rule/{id} [delete]", nil)
+	i.RegisterRule(models.ApplicantModule, models.ManagePermission, AdminHrRoleSet, "/api/v1/space/automation_rule/{id}/dry_run [post]", nil)
 }
 
 func (i *impl) analytics() {
@@ -111,20 +170,62 @@ func (i *impl) profile() {
 	i.RegisterRule(models.ProfileModule, models.EditPermission, AllRoles, "/api/v1/user_profile/change_password [put]", nil)
 	i.RegisterRule(models.ProfileModule, models.EditPermission, AllRoles, "/api/v1/user_profile/photo [post]", nil)
 	i.RegisterRule(models.ProfileModule, models.EditPermission, AllRoles, "/api/v1/user_profile/photo [get]", nil)
+	i.RegisterRule(models.ProfileModule, models.EditPermission, AllRoles, "/api/v1/user_profile/telegram [get]", nil)
+	i.RegisterRule(models.ProfileModule, models.EditPermission, AllRoles, "/api/v1/user_profile/telegram [delete]", nil)
+	i.RegisterRule(models.ProfileModule, models.EditPermission, AllRoles, "/api/v1/space/calendar [get]", nil)
+	i.RegisterRule(models.ProfileModule, models.EditPermission, AllRoles, "/api/v1/space/calendar [put]", nil)
+	i.RegisterRule(models.ProfileModule, models.EditPermission, AllRoles, "/api/v1/space/calendar [delete]", nil)
 }
 
 func (i *impl) companyProfile() {
 	// VIEW
 	i.RegisterRule(models.CompanyProfileModule, models.ViewPermission, AllRoles, "/api/v1/space/profile [get]", nil)
 	i.RegisterRule(models.CompanyProfileModule, models.ViewPermission, AllRoles, "/api/v1/space/profile/photo [get]", nil)
+	i.RegisterRule(models.CompanyProfileModule, models.ViewPermission, AllRoles, "/api/v1/space/custom_fields/list [get]", nil)
+	i.RegisterRule(models.CompanyProfileModule, models.ViewPermission, AllRoles, "/api/v1/space/custom_fields/{id} [get]", nil)
 	// EDIT
-	i.RegisterRule(models.CompanyProfileModule, models.EditPermission, AdminManagerRoleSet, "/api/v1/space/profile [put]", AllowByRoleFunc(AdminManagerRoleSet))
-	i.RegisterRule(models.CompanyProfileModule, models.EditPermission, AdminManagerRoleSet, "/api/v1/space/profile/photo [post]", AllowByRoleFunc(AdminManagerRoleSet))
-	i.RegisterRule(models.CompanyProfileModule, models.EditPermission, AdminManagerRoleSet, "/api/v1/space/profile/send_license_request [put]", AllowByRoleFunc(AdminManagerRoleSet))
+	i.RegisterRule(models.CompanyProfileModule, models.EditPermission, AdminManagerRoleSet, "/api/v1/space/profile [put]", nil)
+	i.RegisterRule(models.CompanyProfileModule, models.EditPermission, AdminManagerRoleSet, "/api/v1/space/profile/photo [post]", nil)
+	i.RegisterRule(models.CompanyProfileModule, models.EditPermission, AdminManagerRoleSet, "/api/v1/space/profile/send_license_request [put]", nil)
 	i.RegisterRule(models.CompanyProfileModule, models.EditPermission, AdminRoleSet, "/api/v1/space/settings/{code} [put]", nil)
 	i.RegisterRule(models.CompanyProfileModule, models.EditPermission, AdminRoleSet, "/api/v1/space/settings/list [get]", nil)
 }
 
+// security настройки безопасности и интеграций, доступны только администратору
+func (i *impl) security() {
+	//SSO
+	i.RegisterRule(models.SecurityModule, models.ManagePermission, AdminRoleSet, "/api/v1/space/sso [get]", nil)
+	i.RegisterRule(models.SecurityModule, models.ManagePermission, AdminRoleSet, "/api/v1/space/sso [put]", nil)
+	//ROLES
+	i.RegisterRule(models.SecurityModule, models.ManagePermission, AdminRoleSet, "/api/v1/space/roles/catalog [get]", nil)
+	i.RegisterRule(models.SecurityModule, models.ManagePermission, AdminRoleSet, "/api/v1/space/roles [post]", nil)
+	i.RegisterRule(models.SecurityModule, models.ManagePermission, AdminRoleSet, "/api/v1/space/roles/{id} [put]", nil)
+	i.RegisterRule(models.SecurityModule, models.ManagePermission, AdminRoleSet, "/api/v1/space/roles/{id} [delete]", nil)
+	//API KEY
+	i.RegisterRule(models.SecurityModule, models.ManagePermission, AdminRoleSet, "/api/v1/space/api_key/list [get]", nil)
+	i.RegisterRule(models.SecurityModule, models.ManagePermission, AdminRoleSet, "/api/v1/space/api_key/scopes [get]", nil)
+	i.RegisterRule(models.SecurityModule, models.ManagePermission, AdminRoleSet, "/api/v1/space/api_key/{id} [get]", nil)
+	i.RegisterRule(models.SecurityModule, models.ManagePermission, AdminRoleSet, "/api/v1/space/api_key [post]", nil)
+	i.RegisterRule(models.SecurityModule, models.ManagePermission, AdminRoleSet, "/api/v1/space/api_key/{id} [put]", nil)
+	i.RegisterRule(models.SecurityModule, models.ManagePermission, AdminRoleSet, "/api/v1/space/api_key/{id}/rotate [put]", nil)
+	i.RegisterRule(models.SecurityModule, models.ManagePermission, AdminRoleSet, "/api/v1/space/api_key/{id}/revoke [put]", nil)
+	//WEBHOOK
+	i.RegisterRule(models.SecurityModule, models.ManagePermission, AdminRoleSet, "/api/v1/space/webhook/list [get]", nil)
+	i.RegisterRule(models.SecurityModule, models.ManagePermission, AdminRoleSet, "/api/v1/space/webhook/{id} [get]", nil)
+	i.RegisterRule(models.SecurityModule, models.ManagePermission, AdminRoleSet, "/api/v1/space/webhook [post]", nil)
+	i.RegisterRule(models.SecurityModule, models.ManagePermission, AdminRoleSet, "/api/v1/space/webhook/{id} [put]", nil)
+	i.RegisterRule(models.SecurityModule, models.ManagePermission, AdminRoleSet, "/api/v1/space/webhook/{id} [delete]", nil)
+	i.RegisterRule(models.SecurityModule, models.ManagePermission, AdminRoleSet, "/api/v1/space/webhook/delivery/list [post]", nil)
+	i.RegisterRule(models.SecurityModule, models.ManagePermission, AdminRoleSet, "/api/v1/space/webhook/delivery/{id}/redeliver [put]", nil)
+	//AUDIT LOG
+	i.RegisterRule(models.SecurityModule, models.ManagePermission, AdminRoleSet, "/api/v1/space/audit_log/list [post]", nil)
+	i.RegisterRule(models.SecurityModule, models.ManagePermission, AdminRoleSet, "/api/v1/space/audit_log/export_csv [post]", nil)
+	//CUSTOM FIELDS
+	i.RegisterRule(models.SecurityModule, models.ManagePermission, AdminRoleSet, "/api/v1/space/custom_fields [post]", nil)
+	i.RegisterRule(models.SecurityModule, models.ManagePermission, AdminRoleSet, "/api/v1/space/custom_fields/{id} [put]", nil)
+	i.RegisterRule(models.SecurityModule, models.ManagePermission, AdminRoleSet, "/api/v1/space/custom_fields/{id} [delete]", nil)
+}
+
 func (i *impl) dict() {
 	// VIEW
 	i.RegisterRule(models.DictModule, models.ViewPermission, AllRoles, "/api/v1/dict/city/{id} [get]", nil)
@@ -148,6 +249,7 @@ func (i *impl) dict() {
 	i.RegisterRule(models.DictModule, models.ViewPermission, AllRoles, "/api/v1/dict/reject_reason/{id} [get]", nil)
 
 	i.RegisterRule(models.DictModule, models.ViewPermission, AllRoles, "/api/v1/dict/role/list [get]", nil)
+	i.RegisterRule(models.DictModule, models.ViewPermission, AllRoles, "/api/v1/dict/applicant_source/list [get]", nil)
 
 	// CREATE/EDIT
 	i.RegisterRule(models.DictModule, models.CreatePermission, AdminRoleSet, "/api/v1/dict/company [post]", nil)
@@ -0,0 +1,204 @@
+package resumeparser
+
+import (
+	"context"
+	"hr-tools-backend/config"
+	"hr-tools-backend/db"
+	ollamasearchhandler "hr-tools-backend/lib/ai/ollama-search"
+	"hr-tools-backend/lib/applicant"
+	applicantstore "hr-tools-backend/lib/applicant/store"
+	gpthandler "hr-tools-backend/lib/gpt"
+	resumesuggestionstore "hr-tools-backend/lib/resume-parser/suggestion-store"
+	initchecker "hr-tools-backend/lib/utils/init-checker"
+	applicantapimodels "hr-tools-backend/models/api/applicant"
+	dbmodels "hr-tools-backend/models/db"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+type Provider interface {
+	// Parse извлечение данных кандидата из загруженного резюме, результат сохраняется как предложение для проверки
+	Parse(spaceID, applicantID, fileName string, body []byte)
+	GetSuggestion(spaceID, applicantID string) (view *applicantapimodels.ResumeSuggestionView, err error)
+	ApplySuggestion(spaceID, applicantID, userID string, data applicantapimodels.ApplyResumeSuggestionRequest) (hMsg string, err error)
+	DismissSuggestion(spaceID, applicantID string) (hMsg string, err error)
+}
+
+var Instance Provider
+
+func NewHandler(ctx context.Context) {
+	instance := impl{
+		store:          resumesuggestionstore.NewInstance(db.DB),
+		applicantStore: applicantstore.NewInstance(db.DB),
+		applicant:      applicant.Instance,
+	}
+	switch config.Conf.AI.ResumeParseAI {
+	case "Ollama":
+		instance.aiProvider = ollamasearchhandler.GetHandler(ctx)
+	case "YandexGPT":
+		instance.aiProvider = gpthandler.GetHandler(false)
+	}
+	initchecker.CheckInit(
+		"store", instance.store,
+		"applicantStore", instance.applicantStore,
+		"applicant", instance.applicant,
+	)
+	Instance = instance
+}
+
+type impl struct {
+	store          resumesuggestionstore.Provider
+	applicantStore applicantstore.Provider
+	applicant      applicant.Provider
+	aiProvider     applicantapimodels.ResumeAiProvider // не задан - данные извлекаются только по правилам, настройка config.Conf.AI.ResumeParseAI
+}
+
+const (
+	methodRules = "rules"
+
+	// ограничение размера текста резюме для запроса к ИИ
+	maxAiTextLen = 20000
+
+	SuggestionNotFoundMsg = "данные резюме не найдены"
+	SuggestionNotReadyMsg = "данные резюме недоступны для переноса в профиль"
+)
+
+func (i impl) getLogger(spaceID, applicantID string) *log.Entry {
+	return log.
+		WithField("space_id", spaceID).
+		WithField("applicant_id", applicantID)
+}
+
+func (i impl) Parse(spaceID, applicantID, fileName string, body []byte) {
+	logger := i.getLogger(spaceID, applicantID).
+		WithField("file_name", fileName)
+	rec := dbmodels.ApplicantResumeSuggestion{
+		BaseSpaceModel: dbmodels.BaseSpaceModel{
+			SpaceID: spaceID,
+		},
+		ApplicantID: applicantID,
+		FileName:    fileName,
+		Status:      dbmodels.ResumeSuggestionInProgress,
+		Method:      methodRules,
+	}
+	id, err := i.store.Save(rec)
+	if err != nil {
+		logger.WithError(err).Error("ошибка сохранения данных резюме")
+		return
+	}
+	updMap := map[string]interface{}{}
+	data, method, err := i.extract(spaceID, applicantID, fileName, body)
+	if err != nil {
+		logger.WithError(err).Warn("не удалось извлечь данные из резюме")
+		updMap["status"] = dbmodels.ResumeSuggestionFailed
+		updMap["error"] = err.Error()
+	} else {
+		updMap["status"] = dbmodels.ResumeSuggestionReady
+		updMap["method"] = method
+		updMap["data"] = data
+	}
+	err = i.store.Update(spaceID, id, updMap)
+	if err != nil {
+		logger.WithError(err).Error("ошибка сохранения данных резюме")
+		return
+	}
+	logger.Info("обработка резюме завершена")
+}
+
+func (i impl) GetSuggestion(spaceID, applicantID string) (*applicantapimodels.ResumeSuggestionView, error) {
+	rec, err := i.store.GetByApplicantID(spaceID, applicantID)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения данных резюме")
+	}
+	if rec == nil {
+		return nil, nil
+	}
+	applicantRec, err := i.applicantStore.GetByID(spaceID, applicantID)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения кандидата")
+	}
+	if applicantRec == nil {
+		return nil, nil
+	}
+	result := applicantapimodels.ResumeSuggestionConvert(*rec, applicantRec.Applicant)
+	return &result, nil
+}
+
+func (i impl) ApplySuggestion(spaceID, applicantID, userID string, data applicantapimodels.ApplyResumeSuggestionRequest) (hMsg string, err error) {
+	rec, err := i.store.GetByApplicantID(spaceID, applicantID)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения данных резюме")
+	}
+	if rec == nil {
+		return SuggestionNotFoundMsg, nil
+	}
+	if rec.Status != dbmodels.ResumeSuggestionReady {
+		return SuggestionNotReadyMsg, nil
+	}
+	applicantRec, err := i.applicantStore.GetByID(spaceID, applicantID)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения кандидата")
+	}
+	if applicantRec == nil {
+		return "кандидат не найден", nil
+	}
+	applicantData := applicantapimodels.ApplyResumeSuggestion(applicantRec.Applicant, rec.Data, data.Fields)
+	hMsg, err = i.applicant.UpdateApplicant(spaceID, applicantID, userID, applicantData)
+	if err != nil || hMsg != "" {
+		return hMsg, err
+	}
+	updMap := map[string]interface{}{
+		"status": dbmodels.ResumeSuggestionApplied,
+	}
+	err = i.store.Update(spaceID, rec.ID, updMap)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка обновления статуса данных резюме")
+	}
+	return "", nil
+}
+
+func (i impl) DismissSuggestion(spaceID, applicantID string) (hMsg string, err error) {
+	rec, err := i.store.GetByApplicantID(spaceID, applicantID)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения данных резюме")
+	}
+	if rec == nil {
+		return SuggestionNotFoundMsg, nil
+	}
+	updMap := map[string]interface{}{
+		"status": dbmodels.ResumeSuggestionDismissed,
+	}
+	err = i.store.Update(spaceID, rec.ID, updMap)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка обновления статуса данных резюме")
+	}
+	return "", nil
+}
+
+// extract текст резюме разбирается по правилам, при настроенном ИИ - дополнительно через ИИ,
+// значения ИИ имеют приоритет, пустые поля заполняются значениями, найденными по правилам
+func (i impl) extract(spaceID, applicantID, fileName string, body []byte) (data dbmodels.ResumeData, method string, err error) {
+	text, err := ExtractText(fileName, body)
+	if err != nil {
+		return data, "", err
+	}
+	if text == "" {
+		return data, "", errors.New("в резюме не найден текст")
+	}
+	data = ExtractByRules(text)
+	if i.aiProvider == nil {
+		return data, methodRules, nil
+	}
+	if runes := []rune(text); len(runes) > maxAiTextLen {
+		text = string(runes[:maxAiTextLen])
+	}
+	aiData, err := i.aiProvider.ParseResume(spaceID, applicantID, text)
+	if err != nil {
+		i.getLogger(spaceID, applicantID).
+			WithError(err).
+			Warn("ошибка извлечения данных из резюме через ИИ, используются данные, найденные по правилам")
+		return data, methodRules, nil
+	}
+	return merge(Normalize(aiData), data), config.Conf.AI.ResumeParseAI, nil
+}
@@ -0,0 +1,299 @@
+package resumeparser
+
+import (
+	"hr-tools-backend/models"
+	dbmodels "hr-tools-backend/models/db"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	emailRegexp      = regexp.MustCompile(`[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}`)
+	phoneRegexp      = regexp.MustCompile(`(?:\+7|\b8)[\s\-(]*\d{3}[\s\-)]*\d{3}[\s\-]*\d{2}[\s\-]*\d{2}\b`)
+	nameRegexp       = regexp.MustCompile(`^([А-ЯЁ][а-яё]+(?:-[А-ЯЁ][а-яё]+)?)\s+([А-ЯЁ][а-яё]+)(?:\s+([А-ЯЁ][а-яё]+))?$`)
+	dateRegexp       = regexp.MustCompile(`\b(\d{2})\.(\d{2})\.(\d{4})\b`)
+	experienceRegexp = regexp.MustCompile(`(?i)опыт работы\D{0,5}(?:(\d+)\s*(?:год|года|лет))?\s*(?:(\d+)\s*месяц)?`)
+	salaryRegexp     = regexp.MustCompile(`(\d[\d\s\x{00a0}]{2,})\s*(?:руб|₽|rub)`)
+	labelRegexp      = regexp.MustCompile(`^(?i)(гражданство|проживает|адрес|город|место жительства)\s*[:\-—]\s*(.+)$`)
+	languageRegexp   = regexp.MustCompile(`^(Русский|Английский|Немецкий|Французский|Испанский|Итальянский|Китайский|Японский|Корейский|Турецкий|Арабский|Португальский|Казахский|Украинский|Белорусский|Узбекский|Татарский)\s*[—\-–:]\s*(.+)$`)
+	licenseRegexp    = regexp.MustCompile(`\b(BE|CE|DE|TM|TB|A|B|C|D|E)\b`)
+)
+
+var driverLicenseTypes = []models.DriverLicenseType{
+	models.DriverLicenseA, models.DriverLicenseB, models.DriverLicenseC, models.DriverLicenseD, models.DriverLicenseE,
+	models.DriverLicenseBE, models.DriverLicenseCE, models.DriverLicenseDE, models.DriverLicenseTM, models.DriverLicenseTB,
+}
+
+type keyword[T comparable] struct {
+	text  string
+	value T
+}
+
+// порядок важен: более точные формулировки проверяются раньше
+var educationKeywords = []keyword[models.EducationType]{
+	{"доктор наук", models.EducationTypeDoctor},
+	{"кандидат наук", models.EducationTypeCandidate},
+	{"магистр", models.EducationTypeMaster},
+	{"бакалавр", models.EducationTypeBachelor},
+	{"неоконченное высшее", models.EducationTypeUnfinishedHigher},
+	{"высшее", models.EducationTypeHigher},
+	{"среднее специальное", models.EducationTypeSpecialSecondary},
+	{"среднее профессиональное", models.EducationTypeSpecialSecondary},
+	{"среднее", models.EducationTypeSecondary},
+}
+
+var employmentKeywords = []keyword[models.Employment]{
+	{"полная занятость", models.EmploymentFull},
+	{"частичная занятость", models.EmploymentPartial},
+	{"проектная работа", models.EmploymentTemporary},
+	{"временная", models.EmploymentTemporary},
+	{"стажировка", models.EmploymentInternship},
+	{"волонтерство", models.EmploymentVolunteer},
+}
+
+var scheduleKeywords = []keyword[models.Schedule]{
+	{"неполный день", models.SchedulePartTime},
+	{"полный день", models.ScheduleFullDay},
+	{"сменный", models.ScheduleShift},
+	{"гибкий", models.ScheduleFlexible},
+	{"вахт", models.ScheduleFlyInFlyOut},
+}
+
+var languageLevelKeywords = []keyword[models.LanguageLevelType]{
+	{"родной", models.LanguageLevelL1},
+	{"c2", models.LanguageLevelC2},
+	{"c1", models.LanguageLevelC1},
+	{"b2", models.LanguageLevelB2},
+	{"b1", models.LanguageLevelB1},
+	{"a2", models.LanguageLevelA2},
+	{"a1", models.LanguageLevelA1},
+	{"в совершенстве", models.LanguageLevelC2},
+	{"свободно", models.LanguageLevelC1},
+	{"могу проходить интервью", models.LanguageLevelB2},
+	{"читаю профессиональную литературу", models.LanguageLevelB1},
+	{"базовые знания", models.LanguageLevelA1},
+}
+
+// ExtractByRules извлечение данных кандидата из текста резюме по правилам (формат резюме HH и аналогичные)
+func ExtractByRules(text string) dbmodels.ResumeData {
+	result := dbmodels.ResumeData{
+		Email: strings.ToLower(emailRegexp.FindString(text)),
+		Phone: normalizePhone(phoneRegexp.FindString(text)),
+	}
+	lower := strings.ToLower(text)
+	lines := strings.Split(text, "\n")
+	for k, line := range lines {
+		line = strings.TrimSpace(line)
+		lowerLine := strings.ToLower(line)
+		if result.LastName == "" && k < 10 {
+			if match := nameRegexp.FindStringSubmatch(line); match != nil {
+				result.LastName, result.FirstName, result.MiddleName = match[1], match[2], match[3]
+			}
+		}
+		if result.BirthDate == "" && (strings.Contains(lowerLine, "рожд") || strings.Contains(lowerLine, "родил")) {
+			result.BirthDate = findDate(line)
+		}
+		if match := labelRegexp.FindStringSubmatch(line); match != nil {
+			value := strings.TrimSpace(match[2])
+			if strings.EqualFold(match[1], "гражданство") {
+				if result.Citizenship == "" {
+					// формат HH: "Гражданство: Россия, есть разрешение на работу: Россия"
+					citizenship, _, _ := strings.Cut(value, ",")
+					result.Citizenship = strings.TrimSpace(citizenship)
+				}
+			} else if result.Address == "" {
+				result.Address = value
+			}
+		}
+		if match := languageRegexp.FindStringSubmatch(line); match != nil {
+			result.Languages = append(result.Languages, dbmodels.Language{
+				Name:          match[1],
+				LanguageLevel: findLanguageLevel(match[2]),
+			})
+		}
+		if strings.Contains(lowerLine, "занятость") {
+			result.Employments = appendKeywords(result.Employments, lowerLine, employmentKeywords)
+		}
+		if strings.Contains(lowerLine, "график") {
+			result.Schedules = appendKeywords(result.Schedules, lowerLine, scheduleKeywords)
+		}
+		if strings.Contains(lowerLine, "права") && strings.Contains(lowerLine, "категори") {
+			for _, value := range licenseRegexp.FindAllString(line[strings.Index(lowerLine, "категори"):], -1) {
+				license := models.DriverLicenseType(value)
+				if !slices.Contains(result.DriverLicenseTypes, license) {
+					result.DriverLicenseTypes = append(result.DriverLicenseTypes, license)
+				}
+			}
+		}
+		if result.Salary == 0 && (strings.Contains(lowerLine, "зарплата") || strings.Contains(lowerLine, "доход")) {
+			// формат HH: сумма указывается после заголовка и должности
+			result.Salary = findSalary(strings.Join(lines[k:min(k+3, len(lines))], " "))
+		}
+	}
+	if match := experienceRegexp.FindStringSubmatch(text); match != nil {
+		years, _ := strconv.Atoi(match[1])
+		months, _ := strconv.Atoi(match[2])
+		result.TotalExperience = years*12 + months
+	}
+	if idx := strings.Index(lower, "образование"); idx != -1 {
+		result.Education = findEducation(lower[idx:])
+	}
+	if result.Education == "" {
+		result.Education = findEducation(lower)
+	}
+	return result
+}
+
+// Normalize удаление значений, не соответствующих справочникам (для данных, полученных от ИИ)
+func Normalize(data dbmodels.ResumeData) dbmodels.ResumeData {
+	data.Email = strings.ToLower(strings.TrimSpace(data.Email))
+	if data.Email != "" && !emailRegexp.MatchString(data.Email) {
+		data.Email = ""
+	}
+	if data.BirthDate != "" {
+		if _, err := time.Parse("02.01.2006", data.BirthDate); err != nil {
+			data.BirthDate = ""
+		}
+	}
+	if data.Salary < 0 {
+		data.Salary = 0
+	}
+	if data.TotalExperience < 0 {
+		data.TotalExperience = 0
+	}
+	if !hasValue(educationKeywords, data.Education) {
+		data.Education = ""
+	}
+	for k := range data.Languages {
+		data.Languages[k].LanguageLevel = models.LanguageLevelType(strings.ToLower(string(data.Languages[k].LanguageLevel)))
+	}
+	data.Languages = slices.DeleteFunc(data.Languages, func(l dbmodels.Language) bool {
+		return l.Name == "" || !hasValue(languageLevelKeywords, l.LanguageLevel)
+	})
+	data.Employments = slices.DeleteFunc(data.Employments, func(v models.Employment) bool {
+		return !hasValue(employmentKeywords, v) && v != models.EmploymentProbation
+	})
+	data.Schedules = slices.DeleteFunc(data.Schedules, func(v models.Schedule) bool {
+		return !hasValue(scheduleKeywords, v)
+	})
+	data.DriverLicenseTypes = slices.DeleteFunc(data.DriverLicenseTypes, func(v models.DriverLicenseType) bool {
+		return !slices.Contains(driverLicenseTypes, v)
+	})
+	return data
+}
+
+// merge заполнение пустых полей primary значениями из secondary
+func merge(primary, secondary dbmodels.ResumeData) dbmodels.ResumeData {
+	fillString := func(dst *string, src string) {
+		if *dst == "" {
+			*dst = src
+		}
+	}
+	fillString(&primary.FirstName, secondary.FirstName)
+	fillString(&primary.LastName, secondary.LastName)
+	fillString(&primary.MiddleName, secondary.MiddleName)
+	fillString(&primary.Phone, secondary.Phone)
+	fillString(&primary.Email, secondary.Email)
+	fillString(&primary.BirthDate, secondary.BirthDate)
+	fillString(&primary.Address, secondary.Address)
+	fillString(&primary.Citizenship, secondary.Citizenship)
+	if primary.Salary == 0 {
+		primary.Salary = secondary.Salary
+	}
+	if primary.TotalExperience == 0 {
+		primary.TotalExperience = secondary.TotalExperience
+	}
+	if primary.Education == "" {
+		primary.Education = secondary.Education
+	}
+	if len(primary.Languages) == 0 {
+		primary.Languages = secondary.Languages
+	}
+	if len(primary.Employments) == 0 {
+		primary.Employments = secondary.Employments
+	}
+	if len(primary.Schedules) == 0 {
+		primary.Schedules = secondary.Schedules
+	}
+	if len(primary.DriverLicenseTypes) == 0 {
+		primary.DriverLicenseTypes = secondary.DriverLicenseTypes
+	}
+	return primary
+}
+
+func normalizePhone(phone string) string {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, phone)
+	if len(digits) != 11 {
+		return ""
+	}
+	return "+7" + digits[1:]
+}
+
+func findDate(line string) string {
+	match := dateRegexp.FindString(line)
+	if match == "" {
+		return ""
+	}
+	if _, err := time.Parse("02.01.2006", match); err != nil {
+		return ""
+	}
+	return match
+}
+
+func findSalary(text string) int {
+	match := salaryRegexp.FindStringSubmatch(text)
+	if match == nil {
+		return 0
+	}
+	value, _ := strconv.Atoi(strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, match[1]))
+	return value
+}
+
+func findEducation(text string) models.EducationType {
+	for _, item := range educationKeywords {
+		if strings.Contains(text, item.text) {
+			return item.value
+		}
+	}
+	return ""
+}
+
+func findLanguageLevel(text string) models.LanguageLevelType {
+	text = strings.ToLower(text)
+	for _, item := range languageLevelKeywords {
+		if strings.Contains(text, item.text) {
+			return item.value
+		}
+	}
+	return models.LanguageLevelA1
+}
+
+func appendKeywords[T comparable](list []T, text string, keywords []keyword[T]) []T {
+	for _, item := range keywords {
+		if strings.Contains(text, item.text) && !slices.Contains(list, item.value) {
+			list = append(list, item.value)
+		}
+		// "неполный день" не должен давать "полный день"
+		text = strings.ReplaceAll(text, item.text, "")
+	}
+	return list
+}
+
+func hasValue[T comparable](keywords []keyword[T], value T) bool {
+	return slices.ContainsFunc(keywords, func(item keyword[T]) bool {
+		return item.value == value
+	})
+}
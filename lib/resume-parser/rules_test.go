@@ -0,0 +1,99 @@
+package resumeparser
+
+import (
+	"archive/zip"
+	"bytes"
+	"hr-tools-backend/models"
+	dbmodels "hr-tools-backend/models/db"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testResume = `Петров Иван Сергеевич
+Мужчина, 34 года, родился 15.03.1990
++7 (916) 123-45-67
+Ivan.Petrov@Example.org
+Проживает: Москва
+Гражданство: Россия, есть разрешение на работу: Россия
+Желаемая должность и зарплата
+Инженер-программист
+250 000 ₽
+Занятость: полная занятость, проектная работа
+График работы: полный день, гибкий график
+Опыт работы —5 лет 7 месяцев
+Образование
+Высшее
+Знание языков
+Русский — Родной
+Английский — B2 — Средне-продвинутый
+Водительские права категории B, BE`
+
+func TestExtractByRules(t *testing.T) {
+	data := ExtractByRules(testResume)
+	require.Equal(t, "Петров", data.LastName)
+	require.Equal(t, "Иван", data.FirstName)
+	require.Equal(t, "Сергеевич", data.MiddleName)
+	require.Equal(t, "15.03.1990", data.BirthDate)
+	require.Equal(t, "+79161234567", data.Phone)
+	require.Equal(t, "ivan.petrov@example.org", data.Email)
+	require.Equal(t, "Москва", data.Address)
+	require.Equal(t, "Россия", data.Citizenship)
+	require.Equal(t, 250000, data.Salary)
+	require.Equal(t, 67, data.TotalExperience)
+	require.Equal(t, models.EducationTypeHigher, data.Education)
+	require.Equal(t, []dbmodels.Language{
+		{Name: "Русский", LanguageLevel: models.LanguageLevelL1},
+		{Name: "Английский", LanguageLevel: models.LanguageLevelB2},
+	}, data.Languages)
+	require.Equal(t, []models.Employment{models.EmploymentFull, models.EmploymentTemporary}, data.Employments)
+	require.Equal(t, []models.Schedule{models.ScheduleFullDay, models.ScheduleFlexible}, data.Schedules)
+	require.Equal(t, []models.DriverLicenseType{models.DriverLicenseB, models.DriverLicenseBE}, data.DriverLicenseTypes)
+}
+
+func TestNormalize(t *testing.T) {
+	data := Normalize(dbmodels.ResumeData{
+		Email:     " Ivan@Example.org ",
+		BirthDate: "1990-03-15",
+		Salary:    -1,
+		Education: "university",
+		Languages: []dbmodels.Language{
+			{Name: "Английский", LanguageLevel: "B2"},
+			{Name: "Немецкий", LanguageLevel: "fluent"},
+		},
+		Schedules:          []models.Schedule{models.ScheduleFullDay, "night"},
+		DriverLicenseTypes: []models.DriverLicenseType{models.DriverLicenseB, "X"},
+	})
+	require.Equal(t, "ivan@example.org", data.Email)
+	require.Empty(t, data.BirthDate)
+	require.Zero(t, data.Salary)
+	require.Empty(t, data.Education)
+	require.Equal(t, []dbmodels.Language{{Name: "Английский", LanguageLevel: models.LanguageLevelB2}}, data.Languages)
+	require.Equal(t, []models.Schedule{models.ScheduleFullDay}, data.Schedules)
+	require.Equal(t, []models.DriverLicenseType{models.DriverLicenseB}, data.DriverLicenseTypes)
+}
+
+func TestExtractText(t *testing.T) {
+	buf := bytes.Buffer{}
+	archive := zip.NewWriter(&buf)
+	file, err := archive.Create("word/document.xml")
+	require.NoError(t, err)
+	_, err = file.Write([]byte(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		`<w:p><w:r><w:t>Петров Иван</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>Гражданство:</w:t><w:tab/><w:t>Россия</w:t></w:r></w:p>` +
+		`</w:body></w:document>`))
+	require.NoError(t, err)
+	require.NoError(t, archive.Close())
+
+	text, err := ExtractText("cv.DOCX", buf.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, "Петров Иван\nГражданство: Россия", text)
+
+	rtf := `{\rtf1\ansi\ansicpg1251{\fonttbl{\f0 Arial;}}\f0 \'cf\'e5\'f2\'f0\'ee\'e2 \uc1\u1048?\u1074?\u1072?\u1085?\par Email: ivan@example.org}`
+	text, err = ExtractText("cv.rtf", []byte(rtf))
+	require.NoError(t, err)
+	require.Equal(t, "Петров Иван\nEmail: ivan@example.org", text)
+
+	_, err = ExtractText("cv.odt", nil)
+	require.ErrorIs(t, err, ErrUnsupportedFormat)
+}
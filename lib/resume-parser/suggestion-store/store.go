@@ -0,0 +1,77 @@
+package resumesuggestionstore
+
+import (
+	dbmodels "hr-tools-backend/models/db"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+type Provider interface {
+	Save(rec dbmodels.ApplicantResumeSuggestion) (id string, err error)
+	Update(spaceID, id string, updMap map[string]interface{}) error
+	GetByApplicantID(spaceID, applicantID string) (*dbmodels.ApplicantResumeSuggestion, error)
+}
+
+func NewInstance(DB *gorm.DB) Provider {
+	return &impl{
+		db: DB,
+	}
+}
+
+type impl struct {
+	db *gorm.DB
+}
+
+// Save сохранение данных резюме, у кандидата хранятся данные только последнего загруженного резюме
+func (i impl) Save(rec dbmodels.ApplicantResumeSuggestion) (id string, err error) {
+	existedRec, err := i.GetByApplicantID(rec.SpaceID, rec.ApplicantID)
+	if err != nil {
+		return "", err
+	}
+	if existedRec != nil {
+		rec.ID = existedRec.ID
+		rec.CreatedAt = existedRec.CreatedAt
+	}
+	err = i.db.
+		Save(&rec).
+		Error
+	if err != nil {
+		return "", err
+	}
+	return rec.ID, nil
+}
+
+func (i impl) Update(spaceID, id string, updMap map[string]interface{}) error {
+	if len(updMap) == 0 {
+		return nil
+	}
+	tx := i.db.
+		Model(&dbmodels.ApplicantResumeSuggestion{}).
+		Where("space_id = ?", spaceID).
+		Where("id = ?", id).
+		Updates(updMap)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return errors.New("запись не найдена")
+	}
+	return nil
+}
+
+func (i impl) GetByApplicantID(spaceID, applicantID string) (*dbmodels.ApplicantResumeSuggestion, error) {
+	rec := dbmodels.ApplicantResumeSuggestion{}
+	err := i.db.
+		Where("space_id = ?", spaceID).
+		Where("applicant_id = ?", applicantID).
+		First(&rec).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rec, nil
+}
@@ -0,0 +1,267 @@
+package resumeparser
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ledongthuc/pdf"
+	"github.com/pkg/errors"
+	"golang.org/x/text/encoding/charmap"
+)
+
+var ErrUnsupportedFormat = errors.New("формат файла резюме не поддерживается")
+
+// ExtractText извлечение текста из файла резюме (pdf, docx, rtf, txt)
+func ExtractText(fileName string, body []byte) (string, error) {
+	var text string
+	var err error
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".pdf":
+		text, err = extractPdf(body)
+	case ".docx":
+		text, err = extractDocx(body)
+	case ".rtf":
+		text, err = extractRtf(body)
+	case ".txt":
+		text = decodeText(body)
+	default:
+		return "", ErrUnsupportedFormat
+	}
+	if err != nil {
+		return "", err
+	}
+	return normalizeSpaces(text), nil
+}
+
+func extractPdf(body []byte) (text string, err error) {
+	// библиотека паникует на некоторых поврежденных файлах
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("ошибка чтения pdf: %v", r)
+		}
+	}()
+	reader, err := pdf.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка чтения pdf")
+	}
+	textReader, err := reader.GetPlainText()
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка извлечения текста из pdf")
+	}
+	data, err := io.ReadAll(textReader)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка извлечения текста из pdf")
+	}
+	return string(data), nil
+}
+
+func extractDocx(body []byte) (string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка чтения docx")
+	}
+	for _, file := range archive.File {
+		if file.Name != "word/document.xml" {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return "", errors.Wrap(err, "ошибка чтения docx")
+		}
+		defer rc.Close()
+		return parseDocumentXml(rc)
+	}
+	return "", errors.New("ошибка чтения docx: не найден word/document.xml")
+}
+
+// parseDocumentXml текст из word/document.xml: w:t - текст, w:p - абзац, w:tab/w:br - разделители
+func parseDocumentXml(r io.Reader) (string, error) {
+	decoder := xml.NewDecoder(r)
+	sb := strings.Builder{}
+	inText := false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", errors.Wrap(err, "ошибка разбора docx")
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				sb.WriteString("\t")
+			case "br":
+				sb.WriteString("\n")
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				sb.WriteString("\n")
+			}
+		case xml.CharData:
+			if inText {
+				sb.Write(t)
+			}
+		}
+	}
+	return sb.String(), nil
+}
+
+var rtfControlRegexp = regexp.MustCompile(`^\\([a-zA-Z]+)(-?\d+)? ?`)
+
+// служебные группы rtf, текст которых не относится к документу
+var rtfSkipGroups = map[string]bool{
+	"fonttbl": true, "colortbl": true, "stylesheet": true, "info": true,
+	"pict": true, "header": true, "footer": true, "listtable": true, "listoverridetable": true,
+	"themedata": true, "colorschememapping": true, "datastore": true, "latentstyles": true,
+	"generator": true, "xmlnstbl": true, "rsidtbl": true,
+}
+
+func extractRtf(body []byte) (string, error) {
+	data := string(body)
+	if !strings.HasPrefix(data, "{\\rtf") {
+		return "", errors.New("ошибка чтения rtf: некорректный формат файла")
+	}
+	type group struct {
+		skip bool
+		uc   int
+	}
+	stack := []group{{uc: 1}}
+	sb := strings.Builder{}
+	raw := []byte{} // байты \'hh в кодировке cp1251
+	flushRaw := func() {
+		if len(raw) != 0 {
+			decoded, _ := charmap.Windows1251.NewDecoder().Bytes(raw)
+			sb.Write(decoded)
+			raw = raw[:0]
+		}
+	}
+	skipChars := 0
+	for pos := 0; pos < len(data); {
+		current := &stack[len(stack)-1]
+		ch := data[pos]
+		switch {
+		case ch == '{':
+			flushRaw()
+			stack = append(stack, group{skip: current.skip, uc: current.uc})
+			pos++
+		case ch == '}':
+			flushRaw()
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+			pos++
+		case ch == '\\' && pos+1 < len(data):
+			next := data[pos+1]
+			switch {
+			case next == '\'' && pos+3 < len(data):
+				if skipChars > 0 {
+					skipChars--
+				} else if !current.skip {
+					if value, err := strconv.ParseUint(data[pos+2:pos+4], 16, 8); err == nil {
+						raw = append(raw, byte(value))
+					}
+				}
+				pos += 4
+			case next == '*':
+				current.skip = true
+				pos += 2
+			case next == '\\' || next == '{' || next == '}':
+				flushRaw()
+				if !current.skip {
+					sb.WriteByte(next)
+				}
+				pos += 2
+			case next == '\n' || next == '\r':
+				flushRaw()
+				if !current.skip {
+					sb.WriteString("\n")
+				}
+				pos += 2
+			default:
+				flushRaw()
+				match := rtfControlRegexp.FindStringSubmatch(data[pos:])
+				if match == nil {
+					pos += 2
+					continue
+				}
+				pos += len(match[0])
+				word, param := match[1], match[2]
+				switch {
+				case rtfSkipGroups[word]:
+					current.skip = true
+				case word == "uc":
+					current.uc, _ = strconv.Atoi(param)
+				case word == "u":
+					value, _ := strconv.Atoi(param)
+					if value < 0 {
+						value += 65536
+					}
+					if !current.skip {
+						sb.WriteRune(rune(value))
+					}
+					skipChars = current.uc
+				case word == "par" || word == "line" || word == "row":
+					if !current.skip {
+						sb.WriteString("\n")
+					}
+				case word == "tab" || word == "cell":
+					if !current.skip {
+						sb.WriteString("\t")
+					}
+				}
+			}
+		case ch == '\r' || ch == '\n':
+			pos++
+		default:
+			flushRaw()
+			if skipChars > 0 {
+				skipChars--
+			} else if !current.skip {
+				sb.WriteByte(ch)
+			}
+			pos++
+		}
+	}
+	flushRaw()
+	return sb.String(), nil
+}
+
+// decodeText текстовый файл в utf-8 или cp1251
+func decodeText(body []byte) string {
+	if utf8.Valid(body) {
+		return string(body)
+	}
+	decoded, err := charmap.Windows1251.NewDecoder().Bytes(body)
+	if err != nil {
+		return string(body)
+	}
+	return string(decoded)
+}
+
+var spacesRegexp = regexp.MustCompile(`[ \t\x{00a0}]+`)
+
+func normalizeSpaces(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	result := make([]string, 0, len(lines))
+	for _, line := range lines {
+		line = strings.TrimSpace(spacesRegexp.ReplaceAllString(line, " "))
+		if line != "" {
+			result = append(result, line)
+		}
+	}
+	return strings.Join(result, "\n")
+}
@@ -0,0 +1,65 @@
+package scorecard
+
+import (
+	scorecardapimodels "hr-tools-backend/models/api/scorecard"
+	dbmodels "hr-tools-backend/models/db"
+	"math"
+)
+
+// Aggregate сводная оценка кандидата по отправленным оценочным картам этапа
+func Aggregate(template dbmodels.ScorecardTemplate, scorecards []dbmodels.Scorecard) scorecardapimodels.SummaryView {
+	result := scorecardapimodels.SummaryView{
+		Competencies:    make([]scorecardapimodels.CompetencySummaryView, 0, len(template.Competencies)),
+		Recommendations: map[dbmodels.ScorecardRecommendation]int{},
+	}
+	sums := map[string]int{}
+	counts := map[string]int{}
+	failed := map[string]int{}
+	averageSum, averageCount := 0.0, 0
+	for _, rec := range scorecards {
+		if rec.Status != dbmodels.ScorecardSubmitted {
+			continue
+		}
+		if rec.Recommendation != "" {
+			result.Recommendations[rec.Recommendation]++
+		}
+		if average := rec.Ratings.Average(); average != 0 {
+			averageSum += average
+			averageCount++
+		}
+		for _, rating := range rec.Ratings {
+			if rating.Rating == 0 {
+				continue
+			}
+			sums[rating.CompetencyID] += rating.Rating
+			counts[rating.CompetencyID]++
+			if rating.Rating < template.PassRating {
+				failed[rating.CompetencyID]++
+			}
+		}
+	}
+	if averageCount != 0 {
+		result.Average = round(averageSum / float64(averageCount))
+	}
+	for _, competency := range template.Competencies {
+		item := scorecardapimodels.CompetencySummaryView{
+			CompetencyID: competency.ID,
+			Name:         competency.Name,
+			MustHave:     competency.MustHave,
+			Count:        counts[competency.ID],
+			FailedCount:  failed[competency.ID],
+		}
+		if item.Count != 0 {
+			item.Average = round(float64(sums[competency.ID]) / float64(item.Count))
+		}
+		if competency.MustHave && item.Count != 0 && item.Average < float64(template.PassRating) {
+			result.MustHaveFailed = true
+		}
+		result.Competencies = append(result.Competencies, item)
+	}
+	return result
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
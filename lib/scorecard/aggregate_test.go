@@ -0,0 +1,109 @@
+package scorecard
+
+import (
+	dbmodels "hr-tools-backend/models/db"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregate(t *testing.T) {
+	template := dbmodels.ScorecardTemplate{
+		ScaleMin:   1,
+		ScaleMax:   5,
+		PassRating: 3,
+		Competencies: dbmodels.ScorecardCompetencies{
+			{ID: "go", Name: "Go", MustHave: true},
+			{ID: "sql", Name: "SQL"},
+		},
+	}
+	scorecards := []dbmodels.Scorecard{
+		{
+			Status:         dbmodels.ScorecardSubmitted,
+			Recommendation: dbmodels.RecommendationYes,
+			Ratings: dbmodels.ScorecardRatings{
+				{CompetencyID: "go", Rating: 4},
+				{CompetencyID: "sql", Rating: 2},
+			},
+		},
+		{
+			Status:         dbmodels.ScorecardSubmitted,
+			Recommendation: dbmodels.RecommendationNo,
+			Ratings: dbmodels.ScorecardRatings{
+				{CompetencyID: "go", Rating: 1},
+				{CompetencyID: "sql", Rating: 0},
+			},
+		},
+		{
+			Status:         dbmodels.ScorecardDraft,
+			Recommendation: dbmodels.RecommendationStrongNo,
+			Ratings: dbmodels.ScorecardRatings{
+				{CompetencyID: "go", Rating: 1},
+			},
+		},
+	}
+
+	t.Run("summary", func(t *testing.T) {
+		summary := Aggregate(template, scorecards)
+		// (3 + 1) / 2, черновики не учитываются
+		require.Equal(t, 2.0, summary.Average)
+		require.True(t, summary.MustHaveFailed)
+		require.Equal(t, map[dbmodels.ScorecardRecommendation]int{
+			dbmodels.RecommendationYes: 1,
+			dbmodels.RecommendationNo:  1,
+		}, summary.Recommendations)
+		require.Len(t, summary.Competencies, 2)
+
+		goSummary := summary.Competencies[0]
+		require.Equal(t, "go", goSummary.CompetencyID)
+		require.Equal(t, 2.5, goSummary.Average)
+		require.Equal(t, 2, goSummary.Count)
+		require.Equal(t, 1, goSummary.FailedCount)
+
+		sqlSummary := summary.Competencies[1]
+		require.Equal(t, 2.0, sqlSummary.Average)
+		require.Equal(t, 1, sqlSummary.Count)
+		require.Equal(t, 1, sqlSummary.FailedCount)
+	})
+
+	t.Run("must have passed", func(t *testing.T) {
+		summary := Aggregate(template, scorecards[:1])
+		require.False(t, summary.MustHaveFailed)
+		require.Equal(t, 3.0, summary.Average)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		summary := Aggregate(template, nil)
+		require.Zero(t, summary.Average)
+		require.False(t, summary.MustHaveFailed)
+		require.Len(t, summary.Competencies, 2)
+		require.Empty(t, summary.Recommendations)
+	})
+}
+
+func TestCheckRatings(t *testing.T) {
+	template := dbmodels.ScorecardTemplate{
+		ScaleMin: 1,
+		ScaleMax: 5,
+		Competencies: dbmodels.ScorecardCompetencies{
+			{ID: "go", Name: "Go", MustHave: true},
+			{ID: "sql", Name: "SQL"},
+		},
+	}
+
+	ratings, hMsg := checkRatings(template, []dbmodels.ScorecardRating{{CompetencyID: "sql", Rating: 5}}, false)
+	require.Empty(t, hMsg)
+	require.Equal(t, dbmodels.ScorecardRatings{
+		{CompetencyID: "go", Name: "Go"},
+		{CompetencyID: "sql", Name: "SQL", Rating: 5},
+	}, ratings)
+
+	_, hMsg = checkRatings(template, []dbmodels.ScorecardRating{{CompetencyID: "sql", Rating: 5}}, true)
+	require.NotEmpty(t, hMsg)
+
+	_, hMsg = checkRatings(template, []dbmodels.ScorecardRating{{CompetencyID: "go", Rating: 6}}, false)
+	require.NotEmpty(t, hMsg)
+
+	_, hMsg = checkRatings(template, []dbmodels.ScorecardRating{{CompetencyID: "unknown", Rating: 3}}, false)
+	require.NotEmpty(t, hMsg)
+}
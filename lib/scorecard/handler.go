@@ -0,0 +1,357 @@
+package scorecard
+
+import (
+	"hr-tools-backend/db"
+	applicanthistoryhandler "hr-tools-backend/lib/applicant-history"
+	applicantstore "hr-tools-backend/lib/applicant/store"
+	scorecardstore "hr-tools-backend/lib/scorecard/store"
+	scorecardtemplatestore "hr-tools-backend/lib/scorecard/template-store"
+	initchecker "hr-tools-backend/lib/utils/init-checker"
+	selectionstagestore "hr-tools-backend/lib/vacancy/selection-stage-store"
+	teamstore "hr-tools-backend/lib/vacancy/team-store"
+	scorecardapimodels "hr-tools-backend/models/api/scorecard"
+	dbmodels "hr-tools-backend/models/db"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+type Provider interface {
+	// SaveTemplate создание или изменение шаблона оценочной карты этапа подбора
+	SaveTemplate(spaceID string, data scorecardapimodels.TemplateData) (id, hMsg string, err error)
+	GetTemplate(spaceID, id string) (*scorecardapimodels.TemplateView, error)
+	ListTemplates(spaceID, vacancyID string) ([]scorecardapimodels.TemplateView, error)
+	DeleteTemplate(spaceID, id string) (hMsg string, err error)
+	// SaveScorecard сохранение черновика или отправка оценочной карты кандидата участником команды вакансии
+	SaveScorecard(spaceID, applicantID, userID string, data scorecardapimodels.ScorecardData) (hMsg string, err error)
+	// GetApplicantScorecards оценки кандидата по этапам, оценки других участников доступны после отправки своей оценки
+	GetApplicantScorecards(spaceID, applicantID, userID string) (*scorecardapimodels.ApplicantScorecardsView, error)
+}
+
+var Instance Provider
+
+func NewHandler() {
+	instance := impl{
+		store:               scorecardstore.NewInstance(db.DB),
+		templateStore:       scorecardtemplatestore.NewInstance(db.DB),
+		applicantStore:      applicantstore.NewInstance(db.DB),
+		selectionStageStore: selectionstagestore.NewInstance(db.DB),
+		teamStore:           teamstore.NewInstance(db.DB),
+		applicantHistory:    applicanthistoryhandler.Instance,
+	}
+	initchecker.CheckInit(
+		"store", instance.store,
+		"templateStore", instance.templateStore,
+		"applicantStore", instance.applicantStore,
+		"selectionStageStore", instance.selectionStageStore,
+		"teamStore", instance.teamStore,
+		"applicantHistory", instance.applicantHistory,
+	)
+	Instance = instance
+}
+
+type impl struct {
+	store               scorecardstore.Provider
+	templateStore       scorecardtemplatestore.Provider
+	applicantStore      applicantstore.Provider
+	selectionStageStore selectionstagestore.Provider
+	teamStore           teamstore.Provider
+	applicantHistory    applicanthistoryhandler.Provider
+}
+
+const (
+	TemplateNotFoundMsg     = "шаблон оценочной карты не найден"
+	ScorecardSubmittedMsg   = "оценочная карта уже отправлена"
+	NotTeamMemberMsg        = "оценку могут оставить только участники команды вакансии"
+	StageWithoutTemplateMsg = "для этапа подбора не настроена оценочная карта"
+)
+
+func (i impl) SaveTemplate(spaceID string, data scorecardapimodels.TemplateData) (id, hMsg string, err error) {
+	stage, err := i.selectionStageStore.GetByID(spaceID, data.VacancyID, data.SelectionStageID)
+	if err != nil {
+		return "", "", errors.Wrap(err, "ошибка получения этапа подбора")
+	}
+	if stage == nil {
+		return "", "этап подбора не найден", nil
+	}
+	rec, err := i.templateStore.GetByStage(spaceID, stage.ID)
+	if err != nil {
+		return "", "", errors.Wrap(err, "ошибка получения шаблона оценочной карты")
+	}
+	var existing dbmodels.ScorecardCompetencies
+	if rec != nil {
+		existing = rec.Competencies
+	}
+	competencies := make(dbmodels.ScorecardCompetencies, 0, len(data.Competencies))
+	for _, item := range data.Competencies {
+		competencyID := item.ID
+		if competencyID == "" || !slices.ContainsFunc(existing, func(c dbmodels.ScorecardCompetency) bool { return c.ID == competencyID }) {
+			competencyID = uuid.NewString()
+		}
+		competencies = append(competencies, dbmodels.ScorecardCompetency{
+			ID:          competencyID,
+			Name:        strings.TrimSpace(item.Name),
+			Description: item.Description,
+			MustHave:    item.MustHave,
+		})
+	}
+	if rec != nil {
+		updMap := map[string]interface{}{
+			"name":         strings.TrimSpace(data.Name),
+			"scale_min":    data.ScaleMin,
+			"scale_max":    data.ScaleMax,
+			"pass_rating":  data.PassRating,
+			"competencies": competencies,
+		}
+		err = i.templateStore.Update(spaceID, rec.ID, updMap)
+		if err != nil {
+			return "", "", errors.Wrap(err, "ошибка изменения шаблона оценочной карты")
+		}
+		return rec.ID, "", nil
+	}
+	id, err = i.templateStore.Create(dbmodels.ScorecardTemplate{
+		BaseSpaceModel: dbmodels.BaseSpaceModel{
+			SpaceID: spaceID,
+		},
+		VacancyID:        stage.VacancyID,
+		SelectionStageID: stage.ID,
+		Name:             strings.TrimSpace(data.Name),
+		ScaleMin:         data.ScaleMin,
+		ScaleMax:         data.ScaleMax,
+		PassRating:       data.PassRating,
+		Competencies:     competencies,
+	})
+	if err != nil {
+		return "", "", errors.Wrap(err, "ошибка создания шаблона оценочной карты")
+	}
+	return id, "", nil
+}
+
+func (i impl) GetTemplate(spaceID, id string) (*scorecardapimodels.TemplateView, error) {
+	rec, err := i.templateStore.GetByID(spaceID, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения шаблона оценочной карты")
+	}
+	if rec == nil {
+		return nil, nil
+	}
+	result := scorecardapimodels.TemplateConvert(*rec)
+	return &result, nil
+}
+
+func (i impl) ListTemplates(spaceID, vacancyID string) ([]scorecardapimodels.TemplateView, error) {
+	list, err := i.templateStore.List(spaceID, vacancyID)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения списка шаблонов оценочных карт")
+	}
+	result := make([]scorecardapimodels.TemplateView, 0, len(list))
+	for _, rec := range list {
+		result = append(result, scorecardapimodels.TemplateConvert(rec))
+	}
+	return result, nil
+}
+
+func (i impl) DeleteTemplate(spaceID, id string) (hMsg string, err error) {
+	rec, err := i.templateStore.GetByID(spaceID, id)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения шаблона оценочной карты")
+	}
+	if rec == nil {
+		return TemplateNotFoundMsg, nil
+	}
+	exists, err := i.store.ExistsByTemplate(spaceID, id)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка проверки заполненных оценочных карт")
+	}
+	if exists {
+		return "по шаблону уже заполнены оценочные карты, удаление невозможно", nil
+	}
+	err = i.templateStore.Delete(spaceID, id)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка удаления шаблона оценочной карты")
+	}
+	return "", nil
+}
+
+func (i impl) SaveScorecard(spaceID, applicantID, userID string, data scorecardapimodels.ScorecardData) (hMsg string, err error) {
+	applicant, err := i.applicantStore.GetByID(spaceID, applicantID)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения кандидата")
+	}
+	if applicant == nil {
+		return "кандидат не найден", nil
+	}
+	template, err := i.templateStore.GetByStage(spaceID, data.SelectionStageID)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения шаблона оценочной карты")
+	}
+	if template == nil || template.VacancyID != applicant.VacancyID {
+		return StageWithoutTemplateMsg, nil
+	}
+	member, err := i.teamStore.GetByID(spaceID, applicant.VacancyID, userID)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения команды вакансии")
+	}
+	if member == nil {
+		return NotTeamMemberMsg, nil
+	}
+	ratings, hMsg := checkRatings(*template, data.Ratings, data.Submit)
+	if hMsg != "" {
+		return hMsg, nil
+	}
+	rec, err := i.store.GetByUser(spaceID, applicantID, template.SelectionStageID, userID)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения оценочной карты")
+	}
+	if rec != nil && rec.Status == dbmodels.ScorecardSubmitted {
+		return ScorecardSubmittedMsg, nil
+	}
+	status := dbmodels.ScorecardDraft
+	var submittedAt *time.Time
+	if data.Submit {
+		status = dbmodels.ScorecardSubmitted
+		now := time.Now()
+		submittedAt = &now
+	}
+	if rec != nil {
+		updMap := map[string]interface{}{
+			"template_id":    template.ID,
+			"ratings":        ratings,
+			"recommendation": data.Recommendation,
+			"comment":        data.Comment,
+			"status":         status,
+			"submitted_at":   submittedAt,
+		}
+		err = i.store.Update(spaceID, rec.ID, updMap)
+		rec.Ratings = ratings
+		rec.Recommendation = data.Recommendation
+		rec.Comment = data.Comment
+	} else {
+		rec = &dbmodels.Scorecard{
+			BaseSpaceModel: dbmodels.BaseSpaceModel{
+				SpaceID: spaceID,
+			},
+			ApplicantID:      applicantID,
+			VacancyID:        applicant.VacancyID,
+			SelectionStageID: template.SelectionStageID,
+			TemplateID:       template.ID,
+			UserID:           userID,
+			Ratings:          ratings,
+			Recommendation:   data.Recommendation,
+			Comment:          data.Comment,
+			Status:           status,
+			SubmittedAt:      submittedAt,
+		}
+		_, err = i.store.Create(*rec)
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка сохранения оценочной карты")
+	}
+	if data.Submit {
+		stageName := ""
+		if template.SelectionStage != nil {
+			stageName = template.SelectionStage.Name
+		}
+		changes := applicanthistoryhandler.GetScorecardChange(stageName, *rec)
+		i.applicantHistory.Save(spaceID, applicantID, applicant.VacancyID, userID, dbmodels.HistoryTypeScorecard, changes)
+	}
+	return "", nil
+}
+
+func (i impl) GetApplicantScorecards(spaceID, applicantID, userID string) (*scorecardapimodels.ApplicantScorecardsView, error) {
+	applicant, err := i.applicantStore.GetByID(spaceID, applicantID)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения кандидата")
+	}
+	if applicant == nil {
+		return nil, nil
+	}
+	templates, err := i.templateStore.List(spaceID, applicant.VacancyID)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения шаблонов оценочных карт")
+	}
+	list, err := i.store.ListByApplicant(spaceID, applicantID)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения оценочных карт")
+	}
+	member, err := i.teamStore.GetByID(spaceID, applicant.VacancyID, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения команды вакансии")
+	}
+	slices.SortFunc(templates, func(a, b dbmodels.ScorecardTemplate) int {
+		return getStageOrder(a) - getStageOrder(b)
+	})
+	result := scorecardapimodels.ApplicantScorecardsView{
+		Stages: make([]scorecardapimodels.StageScorecardsView, 0, len(templates)),
+	}
+	for _, template := range templates {
+		stage := scorecardapimodels.StageScorecardsView{
+			Template:   scorecardapimodels.TemplateConvert(template),
+			Scorecards: []scorecardapimodels.ScorecardView{},
+		}
+		submitted := make([]dbmodels.Scorecard, 0, len(list))
+		for _, rec := range list {
+			if rec.SelectionStageID != template.SelectionStageID {
+				continue
+			}
+			if rec.UserID == userID {
+				own := scorecardapimodels.ScorecardConvert(rec)
+				stage.Own = &own
+			}
+			if rec.Status == dbmodels.ScorecardSubmitted {
+				submitted = append(submitted, rec)
+			}
+		}
+		stage.SubmittedCount = len(submitted)
+		// участник команды видит оценки других только после отправки своей оценки
+		stage.Hidden = member != nil && (stage.Own == nil || stage.Own.Status != dbmodels.ScorecardSubmitted)
+		if !stage.Hidden {
+			summary := Aggregate(template, submitted)
+			stage.Summary = &summary
+			for _, rec := range submitted {
+				stage.Scorecards = append(stage.Scorecards, scorecardapimodels.ScorecardConvert(rec))
+			}
+		}
+		result.Stages = append(result.Stages, stage)
+	}
+	return &result, nil
+}
+
+// checkRatings проверка оценок по шаблону, названия компетенций сохраняются в оценке
+func checkRatings(template dbmodels.ScorecardTemplate, ratings []dbmodels.ScorecardRating, submit bool) (dbmodels.ScorecardRatings, string) {
+	result := make(dbmodels.ScorecardRatings, 0, len(template.Competencies))
+	for _, competency := range template.Competencies {
+		idx := slices.IndexFunc(ratings, func(r dbmodels.ScorecardRating) bool { return r.CompetencyID == competency.ID })
+		rating := dbmodels.ScorecardRating{
+			CompetencyID: competency.ID,
+			Name:         competency.Name,
+		}
+		if idx != -1 {
+			rating.Rating = ratings[idx].Rating
+			rating.Comment = ratings[idx].Comment
+		}
+		if rating.Rating != 0 && (rating.Rating < template.ScaleMin || rating.Rating > template.ScaleMax) {
+			return nil, "оценка \"" + competency.Name + "\" вне шкалы оценок"
+		}
+		if submit && competency.MustHave && rating.Rating == 0 {
+			return nil, "не указана оценка обязательной компетенции \"" + competency.Name + "\""
+		}
+		result = append(result, rating)
+	}
+	for _, item := range ratings {
+		if !slices.ContainsFunc(template.Competencies, func(c dbmodels.ScorecardCompetency) bool { return c.ID == item.CompetencyID }) {
+			return nil, "компетенция не найдена в оценочной карте"
+		}
+	}
+	return result, ""
+}
+
+func getStageOrder(rec dbmodels.ScorecardTemplate) int {
+	if rec.SelectionStage == nil {
+		return 0
+	}
+	return rec.SelectionStage.StageOrder
+}
@@ -0,0 +1,120 @@
+package scorecardstore
+
+import (
+	dbmodels "hr-tools-backend/models/db"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Provider interface {
+	Create(rec dbmodels.Scorecard) (id string, err error)
+	Update(spaceID, id string, updMap map[string]interface{}) error
+	GetByUser(spaceID, applicantID, stageID, userID string) (*dbmodels.Scorecard, error)
+	ListByApplicant(spaceID, applicantID string) ([]dbmodels.Scorecard, error)
+	// ListSubmittedByApplicants отправленные оценочные карты кандидатов
+	ListSubmittedByApplicants(spaceID string, applicantIDs []string) ([]dbmodels.Scorecard, error)
+	ExistsByTemplate(spaceID, templateID string) (bool, error)
+}
+
+func NewInstance(DB *gorm.DB) Provider {
+	return &impl{
+		db: DB,
+	}
+}
+
+type impl struct {
+	db *gorm.DB
+}
+
+func (i impl) Create(rec dbmodels.Scorecard) (id string, err error) {
+	err = i.db.
+		Omit(clause.Associations).
+		Save(&rec).
+		Error
+	if err != nil {
+		return "", err
+	}
+	return rec.ID, nil
+}
+
+func (i impl) Update(spaceID, id string, updMap map[string]interface{}) error {
+	if len(updMap) == 0 {
+		return nil
+	}
+	return i.db.
+		Model(&dbmodels.Scorecard{}).
+		Where("space_id = ?", spaceID).
+		Where("id = ?", id).
+		Updates(updMap).
+		Error
+}
+
+func (i impl) GetByUser(spaceID, applicantID, stageID, userID string) (*dbmodels.Scorecard, error) {
+	rec := dbmodels.Scorecard{}
+	err := i.db.
+		Where("space_id = ?", spaceID).
+		Where("applicant_id = ?", applicantID).
+		Where("selection_stage_id = ?", stageID).
+		Where("user_id = ?", userID).
+		First(&rec).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (i impl) ListByApplicant(spaceID, applicantID string) ([]dbmodels.Scorecard, error) {
+	list := []dbmodels.Scorecard{}
+	err := i.db.
+		Preload("SpaceUser").
+		Preload("SelectionStage").
+		Where("space_id = ?", spaceID).
+		Where("applicant_id = ?", applicantID).
+		Order("created_at").
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (i impl) ListSubmittedByApplicants(spaceID string, applicantIDs []string) ([]dbmodels.Scorecard, error) {
+	list := []dbmodels.Scorecard{}
+	if len(applicantIDs) == 0 {
+		return list, nil
+	}
+	err := i.db.
+		Preload("SpaceUser").
+		Preload("SelectionStage").
+		Where("space_id = ?", spaceID).
+		Where("applicant_id in (?)", applicantIDs).
+		Where("status = ?", dbmodels.ScorecardSubmitted).
+		Order("applicant_id, submitted_at").
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (i impl) ExistsByTemplate(spaceID, templateID string) (bool, error) {
+	var count int64
+	err := i.db.
+		Model(&dbmodels.Scorecard{}).
+		Where("space_id = ?", spaceID).
+		Where("template_id = ?", templateID).
+		Count(&count).
+		Error
+	if err != nil {
+		return false, err
+	}
+	return count != 0, nil
+}
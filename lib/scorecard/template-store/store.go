@@ -0,0 +1,107 @@
+package scorecardtemplatestore
+
+import (
+	dbmodels "hr-tools-backend/models/db"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Provider interface {
+	Create(rec dbmodels.ScorecardTemplate) (id string, err error)
+	Update(spaceID, id string, updMap map[string]interface{}) error
+	GetByID(spaceID, id string) (*dbmodels.ScorecardTemplate, error)
+	GetByStage(spaceID, stageID string) (*dbmodels.ScorecardTemplate, error)
+	List(spaceID, vacancyID string) ([]dbmodels.ScorecardTemplate, error)
+	Delete(spaceID, id string) error
+}
+
+func NewInstance(DB *gorm.DB) Provider {
+	return &impl{
+		db: DB,
+	}
+}
+
+type impl struct {
+	db *gorm.DB
+}
+
+func (i impl) Create(rec dbmodels.ScorecardTemplate) (id string, err error) {
+	err = i.db.
+		Omit(clause.Associations).
+		Save(&rec).
+		Error
+	if err != nil {
+		return "", err
+	}
+	return rec.ID, nil
+}
+
+func (i impl) Update(spaceID, id string, updMap map[string]interface{}) error {
+	if len(updMap) == 0 {
+		return nil
+	}
+	return i.db.
+		Model(&dbmodels.ScorecardTemplate{}).
+		Where("space_id = ?", spaceID).
+		Where("id = ?", id).
+		Updates(updMap).
+		Error
+}
+
+func (i impl) GetByID(spaceID, id string) (*dbmodels.ScorecardTemplate, error) {
+	rec := dbmodels.ScorecardTemplate{}
+	err := i.db.
+		Preload("SelectionStage").
+		Where("space_id = ?", spaceID).
+		Where("id = ?", id).
+		First(&rec).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (i impl) GetByStage(spaceID, stageID string) (*dbmodels.ScorecardTemplate, error) {
+	rec := dbmodels.ScorecardTemplate{}
+	err := i.db.
+		Preload("SelectionStage").
+		Where("space_id = ?", spaceID).
+		Where("selection_stage_id = ?", stageID).
+		First(&rec).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (i impl) List(spaceID, vacancyID string) ([]dbmodels.ScorecardTemplate, error) {
+	list := []dbmodels.ScorecardTemplate{}
+	err := i.db.
+		Preload("SelectionStage").
+		Where("space_id = ?", spaceID).
+		Where("vacancy_id = ?", vacancyID).
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (i impl) Delete(spaceID, id string) error {
+	return i.db.
+		Where("space_id = ?", spaceID).
+		Where("id = ?", id).
+		Delete(&dbmodels.ScorecardTemplate{}).
+		Error
+}
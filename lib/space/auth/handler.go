@@ -4,11 +4,16 @@ import (
 	"fmt"
 	"hr-tools-backend/config"
 	"hr-tools-backend/db"
+	authsession "hr-tools-backend/lib/auth-session"
 	emailverify "hr-tools-backend/lib/email-verify"
 	licensestore "hr-tools-backend/lib/licence/store"
+	passwordpolicy "hr-tools-backend/lib/password-policy"
 	"hr-tools-backend/lib/rbac"
 	"hr-tools-backend/lib/smtp"
+	spacesettingsstore "hr-tools-backend/lib/space/settings/store"
 	spaceusersstore "hr-tools-backend/lib/space/users/store"
+	ssostore "hr-tools-backend/lib/sso/store"
+	twofactor "hr-tools-backend/lib/two-factor"
 	authhelpers "hr-tools-backend/lib/utils/auth-helpers"
 	authutils "hr-tools-backend/lib/utils/auth-utils"
 	initchecker "hr-tools-backend/lib/utils/init-checker"
@@ -24,15 +29,28 @@ import (
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 )
 
 type Provider interface {
 	SendEmailConfirmation(email string) error
 	VerifyEmail(code string) error
 	CheckEmail(email string) (bool, error)
-	Login(email, password string) (response authapimodels.JWTResponse, err error)
+	// Login вход по паролю, при подключенной двухфакторной аутентификации токены выдаются после LoginTwoFactor
+	Login(email, password string, client authapimodels.ClientInfo) (response authapimodels.JWTResponse, err error)
+	// LoginTwoFactor второй шаг входа по коду TOTP или коду восстановления.
+	// Если двухфакторная аутентификация обязательна и еще не подключена, код подтверждает подключение
+	LoginTwoFactor(challenge, code string, client authapimodels.ClientInfo) (response authapimodels.JWTResponse, err error)
+	// LoginTwoFactorSetup секрет TOTP для подключения при входе, когда двухфакторная аутентификация обязательна
+	LoginTwoFactorSetup(challenge string) (resp authapimodels.TwoFactorSetup, hMsg string, err error)
 	Me(ctx *fiber.Ctx) (spaceUser spaceapimodels.SpaceUserExt, err error)
-	RefreshToken(ctx *fiber.Ctx, refreshToken string) (response authapimodels.JWTResponse, err error)
+	RefreshToken(refreshToken string, client authapimodels.ClientInfo) (response authapimodels.JWTResponse, err error)
+	// IssueTokens выдача JWT и refresh токена в новой сессии входа
+	IssueTokens(user dbmodels.SpaceUser, client authapimodels.ClientInfo) (response authapimodels.JWTResponse, err error)
+	GetTwoFactorStatus(userID string) (authapimodels.TwoFactorStatus, error)
+	// SetupTwoFactor секрет TOTP для подключения двухфакторной аутентификации из профиля
+	SetupTwoFactor(userID string) (resp authapimodels.TwoFactorSetup, hMsg string, err error)
+	DisableTwoFactor(userID, code string) (hMsg string, err error)
 	PasswordRecovery(email string) error
 	PasswordReset(resetCode, newPassword string) error
 }
@@ -44,6 +62,8 @@ func NewHandler() {
 		emailVerify:     emailverify.NewInstance(config.Conf.Smtp.EmailSendVerification),
 		spaceUsersStore: spaceusersstore.NewInstance(db.DB),
 		licenseStore:    licensestore.NewInstance(db.DB),
+		ssoStore:        ssostore.NewInstance(db.DB),
+		settingsStore:   spacesettingsstore.NewInstance(db.DB),
 
 		systemEmail:   config.Conf.Smtp.EmailSendVerification,
 		recoveryTitle: config.Conf.Recovery.MailTitle,
@@ -53,6 +73,8 @@ func NewHandler() {
 		"emailVerify", instance.emailVerify,
 		"spaceUsersStore", instance.spaceUsersStore,
 		"licenseStore", instance.licenseStore,
+		"ssoStore", instance.ssoStore,
+		"settingsStore", instance.settingsStore,
 	)
 	Instance = instance
 }
@@ -61,55 +83,56 @@ type impl struct {
 	emailVerify     emailverify.Provider
 	spaceUsersStore spaceusersstore.Provider
 	licenseStore    licensestore.Provider
+	ssoStore        ssostore.Provider
+	settingsStore   spacesettingsstore.Provider
 	systemEmail     string
 	recoveryTitle   string
 	recoveryBody    string
 }
 
-func (i impl) RefreshToken(ctx *fiber.Ctx, refreshToken string) (response authapimodels.JWTResponse, err error) {
-	token, err := jwt.Parse(refreshToken, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(config.Conf.Auth.JWTSecret), nil
-	})
+func (i impl) RefreshToken(refreshToken string, client authapimodels.ClientInfo) (response authapimodels.JWTResponse, err error) {
+	session, newRefreshToken, err := authsession.Instance.Refresh(models.AuthSpaceUser, refreshToken, client)
 	if err != nil {
 		return authapimodels.JWTResponse{}, err
 	}
+	logger := log.WithField("user_id", session.UserID)
+	user, err := i.spaceUsersStore.GetByID(session.UserID)
+	if err != nil {
+		logger.
+			WithError(err).
+			Error("ошибка поиска пользователя")
+		return authapimodels.JWTResponse{}, err
+	}
+	if user == nil {
+		return authapimodels.JWTResponse{}, errors.New("пользователь не найден")
+	}
+	if !user.IsActive {
+		return authapimodels.JWTResponse{}, errors.New("учетная запись деактивирована")
+	}
+	tokenString, err := authutils.GetToken(user.ID, user.GetFullName(), user.SpaceID, user.Role.IsSpaceAdmin(), user.Role, session.ID)
+	if err != nil {
+		logger.WithError(err).Error("ошибка генерации JWT")
+		return authapimodels.JWTResponse{}, err
+	}
+	return authapimodels.JWTResponse{
+		Token:        tokenString,
+		RefreshToken: newRefreshToken,
+	}, nil
+}
 
-	if claimsReq, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		userID := claimsReq["sub"].(string)
-		user, err := i.spaceUsersStore.GetByID(userID)
-		if err != nil {
-			log.
-				WithField("user_id", userID).
-				WithError(err).
-				Error("ошибка поиска пользователя")
-			return authapimodels.JWTResponse{}, err
-		}
-		if user == nil {
-			return authapimodels.JWTResponse{}, errors.New("пользователь не найден")
-		}
-		if !user.IsActive {
-			return authapimodels.JWTResponse{}, errors.New("учетная запись деактивирована")
-		}
-		tokenString, err := authutils.GetToken(userID, user.GetFullName(), user.SpaceID, user.Role.IsSpaceAdmin(), user.Role)
-		if err != nil {
-			log.WithError(err).Error("ошибка генерации JWT")
-			return authapimodels.JWTResponse{}, err
-		}
-		refreshTokenString, err := authutils.GetRefreshToken(userID, user.GetFullName())
-		if err != nil {
-			log.WithError(err).Error("ошибка генерации refresh JWT")
-			return authapimodels.JWTResponse{}, err
-		}
-		return authapimodels.JWTResponse{
-			Token:        tokenString,
-			RefreshToken: refreshTokenString,
-		}, nil
-
+func (i impl) IssueTokens(user dbmodels.SpaceUser, client authapimodels.ClientInfo) (response authapimodels.JWTResponse, err error) {
+	sessionID, refreshToken, err := authsession.Instance.Create(models.AuthSpaceUser, user.ID, user.SpaceID, client)
+	if err != nil {
+		return authapimodels.JWTResponse{}, err
+	}
+	tokenString, err := authutils.GetToken(user.ID, user.GetFullName(), user.SpaceID, user.Role.IsSpaceAdmin(), user.Role, sessionID)
+	if err != nil {
+		return authapimodels.JWTResponse{}, errors.Wrap(err, "ошибка генерации JWT")
 	}
-	return authapimodels.JWTResponse{}, errors.New("refresh token is not valid")
+	return authapimodels.JWTResponse{
+		Token:        tokenString,
+		RefreshToken: refreshToken,
+	}, nil
 }
 
 func (i impl) Me(ctx *fiber.Ctx) (spaceUser spaceapimodels.SpaceUserExt, err error) {
@@ -148,13 +171,13 @@ func (i impl) Me(ctx *fiber.Ctx) (spaceUser spaceapimodels.SpaceUserExt, err err
 		SpaceUser:       user.ToModel(),
 		LicenseStatus:   licence.Status,
 		LicenseReadOnly: licence.Status.IdReadOnly(),
-		Permissions:     rbac.Instance.GetPermissions(user.Role),
+		Permissions:     rbac.Instance.GetUserPermissions(user.ID, user.Role),
 	}
 	return result, nil
 
 }
 
-func (i impl) Login(email, password string) (response authapimodels.JWTResponse, err error) {
+func (i impl) Login(email, password string, client authapimodels.ClientInfo) (response authapimodels.JWTResponse, err error) {
 	logger := log.WithField("email", email)
 	user, err := i.spaceUsersStore.FindByEmail(email, false)
 	if err != nil {
@@ -167,31 +190,263 @@ func (i impl) Login(email, password string) (response authapimodels.JWTResponse,
 		logger.Debug("пользователь с такой почтой не найден")
 		return authapimodels.JWTResponse{}, errors.New("пользователь с такой почтой не найден")
 	}
-	if authhelpers.GetMD5Hash(password) != user.Password {
+	if hMsg := passwordpolicy.Instance.CheckLock(user.LockedUntil); hMsg != "" {
+		logger.Debug("вход по паролю заблокирован")
+		return authapimodels.JWTResponse{}, errors.New(hMsg)
+	}
+	ok, needRehash := authhelpers.CheckPassword(password, user.Password)
+	if !ok {
 		logger.Debug("пользователь не прошел проверку пароля")
-		return authapimodels.JWTResponse{}, errors.New("пользователь не прошел проверку пароля")
+		return authapimodels.JWTResponse{}, i.addFailedLogin(user.ID)
 	}
+	i.onPasswordLogin(*user, password, needRehash)
 	if !user.IsActive {
 		logger.Debug("пользователь деактивирован")
 		return authapimodels.JWTResponse{}, errors.New("учетная запись деактивирована")
 	}
+	if !user.Role.IsSpaceAdmin() {
+		// администратор спейса сохраняет вход по паролю на случай недоступности IdP
+		ssoSettings, err := i.ssoStore.GetBySpace(user.SpaceID)
+		if err != nil {
+			logger.WithError(err).Error("ошибка получения настроек входа через IdP")
+			return authapimodels.JWTResponse{}, err
+		}
+		if ssoSettings != nil && ssoSettings.IsEnabled && ssoSettings.PasswordLoginDisabled {
+			return authapimodels.JWTResponse{}, errors.New("вход по паролю отключен, войдите через корпоративную учетную запись")
+		}
+	}
 	if smtp.Instance.IsConfigured() && !user.Role.IsSpaceAdmin() && !user.IsEmailVerified {
 		return authapimodels.JWTResponse{}, errors.New("необходимо подтвердить почту")
 	}
-	tokenString, err := authutils.GetToken(user.ID, user.GetFullName(), user.SpaceID, user.Role.IsSpaceAdmin(), user.Role)
+	twoFactorEnabled, err := twofactor.Instance.IsEnabled(models.AuthSpaceUser, user.ID)
 	if err != nil {
-		logger.WithError(err).Error("ошибка генерации JWT")
+		logger.WithError(err).Error("ошибка проверки двухфакторной аутентификации")
 		return authapimodels.JWTResponse{}, err
 	}
-	refresTokenString, err := authutils.GetRefreshToken(user.ID, user.GetFullName())
+	setupRequired := false
+	if !twoFactorEnabled {
+		setupRequired, err = i.isTwoFactorRequired(user.SpaceID)
+		if err != nil {
+			logger.WithError(err).Error("ошибка получения настройки двухфакторной аутентификации")
+			return authapimodels.JWTResponse{}, err
+		}
+	}
+	if twoFactorEnabled || setupRequired {
+		challenge, err := twofactor.Instance.CreateChallenge(models.AuthSpaceUser, user.ID)
+		if err != nil {
+			logger.WithError(err).Error("ошибка генерации токена второго шага входа")
+			return authapimodels.JWTResponse{}, err
+		}
+		return authapimodels.JWTResponse{
+			TwoFactorRequired:      true,
+			TwoFactorSetupRequired: setupRequired,
+			TwoFactorToken:         challenge,
+		}, nil
+	}
+	response, err = i.IssueTokens(*user, client)
 	if err != nil {
-		logger.WithError(err).Error("ошибка генерации refresh JWT")
+		logger.WithError(err).Error("ошибка выдачи токенов")
 		return authapimodels.JWTResponse{}, err
 	}
-	return authapimodels.JWTResponse{
-		Token:        tokenString,
-		RefreshToken: refresTokenString,
-	}, nil
+	return response, nil
+}
+
+func (i impl) LoginTwoFactor(challenge, code string, client authapimodels.ClientInfo) (response authapimodels.JWTResponse, err error) {
+	user, err := i.getChallengeUser(challenge)
+	if err != nil {
+		return authapimodels.JWTResponse{}, err
+	}
+	logger := log.WithField("user_id", user.ID)
+	enabled, err := twofactor.Instance.IsEnabled(models.AuthSpaceUser, user.ID)
+	if err != nil {
+		logger.WithError(err).Error("ошибка проверки двухфакторной аутентификации")
+		return authapimodels.JWTResponse{}, err
+	}
+	var recoveryCodes []string
+	if enabled {
+		hMsg, err := twofactor.Instance.Verify(models.AuthSpaceUser, user.ID, code)
+		if err != nil {
+			logger.WithError(err).Error("ошибка проверки кода")
+			return authapimodels.JWTResponse{}, err
+		}
+		if hMsg != "" {
+			return authapimodels.JWTResponse{}, errors.New(hMsg)
+		}
+	} else {
+		required, err := i.isTwoFactorRequired(user.SpaceID)
+		if err != nil {
+			logger.WithError(err).Error("ошибка получения настройки двухфакторной аутентификации")
+			return authapimodels.JWTResponse{}, err
+		}
+		if !required {
+			return authapimodels.JWTResponse{}, errors.New("двухфакторная аутентификация не подключена")
+		}
+		codes, hMsg, err := twofactor.Instance.Enable(models.AuthSpaceUser, user.ID, code)
+		if err != nil {
+			logger.WithError(err).Error("ошибка подключения двухфакторной аутентификации")
+			return authapimodels.JWTResponse{}, err
+		}
+		if hMsg != "" {
+			return authapimodels.JWTResponse{}, errors.New(hMsg)
+		}
+		recoveryCodes = codes
+	}
+	response, err = i.IssueTokens(*user, client)
+	if err != nil {
+		logger.WithError(err).Error("ошибка выдачи токенов")
+		return authapimodels.JWTResponse{}, err
+	}
+	response.RecoveryCodes = recoveryCodes
+	return response, nil
+}
+
+func (i impl) LoginTwoFactorSetup(challenge string) (resp authapimodels.TwoFactorSetup, hMsg string, err error) {
+	user, err := i.getChallengeUser(challenge)
+	if err != nil {
+		return authapimodels.TwoFactorSetup{}, err.Error(), nil
+	}
+	required, err := i.isTwoFactorRequired(user.SpaceID)
+	if err != nil {
+		return authapimodels.TwoFactorSetup{}, "", errors.Wrap(err, "ошибка получения настройки двухфакторной аутентификации")
+	}
+	if !required {
+		return authapimodels.TwoFactorSetup{}, "двухфакторная аутентификация подключается в профиле после входа", nil
+	}
+	return twofactor.Instance.Setup(models.AuthSpaceUser, user.ID, user.Email)
+}
+
+func (i impl) SetupTwoFactor(userID string) (resp authapimodels.TwoFactorSetup, hMsg string, err error) {
+	user, err := i.spaceUsersStore.GetByID(userID)
+	if err != nil {
+		return authapimodels.TwoFactorSetup{}, "", errors.Wrap(err, "ошибка поиска пользователя")
+	}
+	if user == nil {
+		return authapimodels.TwoFactorSetup{}, "", errors.New("пользователь не найден")
+	}
+	return twofactor.Instance.Setup(models.AuthSpaceUser, user.ID, user.Email)
+}
+
+func (i impl) GetTwoFactorStatus(userID string) (authapimodels.TwoFactorStatus, error) {
+	user, err := i.spaceUsersStore.GetByID(userID)
+	if err != nil {
+		return authapimodels.TwoFactorStatus{}, errors.Wrap(err, "ошибка поиска пользователя")
+	}
+	if user == nil {
+		return authapimodels.TwoFactorStatus{}, errors.New("пользователь не найден")
+	}
+	status, err := twofactor.Instance.GetStatus(models.AuthSpaceUser, userID)
+	if err != nil {
+		return authapimodels.TwoFactorStatus{}, err
+	}
+	status.Required, err = i.isTwoFactorRequired(user.SpaceID)
+	if err != nil {
+		return authapimodels.TwoFactorStatus{}, errors.Wrap(err, "ошибка получения настройки двухфакторной аутентификации")
+	}
+	return status, nil
+}
+
+func (i impl) DisableTwoFactor(userID, code string) (hMsg string, err error) {
+	user, err := i.spaceUsersStore.GetByID(userID)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка поиска пользователя")
+	}
+	if user == nil {
+		return "", errors.New("пользователь не найден")
+	}
+	required, err := i.isTwoFactorRequired(user.SpaceID)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения настройки двухфакторной аутентификации")
+	}
+	if required {
+		return "двухфакторная аутентификация обязательна в организации", nil
+	}
+	return twofactor.Instance.Disable(models.AuthSpaceUser, userID, code)
+}
+
+// addFailedLogin учет неверного пароля, после превышения попыток вход по паролю блокируется
+func (i impl) addFailedLogin(userID string) error {
+	logger := log.WithField("user_id", userID)
+	errWrongPassword := errors.New("пользователь не прошел проверку пароля")
+	attempts, err := i.spaceUsersStore.AddFailedLogin(userID)
+	if err != nil {
+		logger.WithError(err).Error("ошибка учета неверного пароля")
+		return errWrongPassword
+	}
+	lockedUntil := passwordpolicy.Instance.GetLockUntil(attempts)
+	if lockedUntil == nil {
+		return errWrongPassword
+	}
+	updMap := map[string]interface{}{
+		"failed_login_attempts": 0,
+		"locked_until":          lockedUntil,
+	}
+	err = i.spaceUsersStore.Update(userID, updMap)
+	if err != nil {
+		logger.WithError(err).Error("ошибка блокировки входа по паролю")
+		return errWrongPassword
+	}
+	logger.Warn("вход по паролю заблокирован после неудачных попыток")
+	return errors.New(passwordpolicy.Instance.CheckLock(lockedUntil))
+}
+
+// onPasswordLogin сброс счетчика неверных паролей и пересчет хеша устаревшего формата
+func (i impl) onPasswordLogin(user dbmodels.SpaceUser, password string, needRehash bool) {
+	logger := log.WithField("user_id", user.ID)
+	updMap := map[string]interface{}{}
+	if user.FailedLoginAttempts != 0 || user.LockedUntil != nil {
+		updMap["failed_login_attempts"] = 0
+		updMap["locked_until"] = nil
+	}
+	if needRehash {
+		hash, err := authhelpers.HashPassword(password)
+		if err != nil {
+			logger.WithError(err).Error("ошибка пересчета хеша пароля")
+		} else {
+			updMap["password"] = hash
+		}
+	}
+	if len(updMap) == 0 {
+		return
+	}
+	err := i.spaceUsersStore.Update(user.ID, updMap)
+	if err != nil {
+		logger.WithError(err).Error("ошибка обновления данных входа по паролю")
+	}
+}
+
+// getChallengeUser пользователь, прошедший проверку пароля
+func (i impl) getChallengeUser(challenge string) (*dbmodels.SpaceUser, error) {
+	userID, err := twofactor.Instance.ParseChallenge(models.AuthSpaceUser, challenge)
+	if err != nil {
+		return nil, err
+	}
+	user, err := i.spaceUsersStore.GetByID(userID)
+	if err != nil {
+		log.
+			WithField("user_id", userID).
+			WithError(err).
+			Error("ошибка поиска пользователя")
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("пользователь не найден")
+	}
+	if !user.IsActive {
+		return nil, errors.New("учетная запись деактивирована")
+	}
+	return user, nil
+}
+
+// isTwoFactorRequired двухфакторная аутентификация обязательна для сотрудников спейса
+func (i impl) isTwoFactorRequired(spaceID string) (bool, error) {
+	value, err := i.settingsStore.GetValueByCode(spaceID, models.TwoFactorRequiredSetting)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return strings.ToLower(strings.TrimSpace(value)) == "true", nil
 }
 
 func (i impl) CheckEmail(email string) (passed bool, err error) {
@@ -279,10 +534,22 @@ func (i impl) PasswordReset(resetCode, newPassword string) error {
 	if resetCode == "" || user == nil || user.ResetTime.Add(time.Minute*15).Before(time.Now()) {
 		return errors.New("ссылка не найдена или более не актуальна, попробуйте выполнить восстановление пароля еще раз")
 	}
+	if hMsg := passwordpolicy.Instance.Validate(newPassword); hMsg != "" {
+		return errors.New(hMsg)
+	}
+	passwordHash, err := authhelpers.HashPassword(newPassword)
+	if err != nil {
+		logger.
+			WithError(err).
+			Error("ошибка вычисления хеша пароля")
+		return errors.New("не удалось обновить пароль, попробуйте выполнить восстановление пароля немного позже")
+	}
 	updMap := map[string]interface{}{
-		"reset_code": "",
-		"reset_time": time.Now(),
-		"password":   authhelpers.GetMD5Hash(newPassword),
+		"reset_code":            "",
+		"reset_time":            time.Now(),
+		"password":              passwordHash,
+		"failed_login_attempts": 0,
+		"locked_until":          nil,
 	}
 	err = i.spaceUsersStore.Update(user.ID, updMap)
 	if err != nil {
@@ -291,5 +558,11 @@ func (i impl) PasswordReset(resetCode, newPassword string) error {
 			Error("не удалось обновить пароль, ошибка сохранения нового пароля")
 		return errors.New("не удалось обновить пароль, попробуйте выполнить восстановление пароля немного позже")
 	}
+	err = authsession.Instance.RevokeAll(models.AuthSpaceUser, user.ID, "сброс пароля")
+	if err != nil {
+		logger.
+			WithError(err).
+			Error("ошибка отзыва сессий после сброса пароля")
+	}
 	return nil
 }
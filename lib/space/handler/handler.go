@@ -8,6 +8,7 @@ import (
 	filestorage "hr-tools-backend/lib/file-storage"
 	licensestore "hr-tools-backend/lib/licence/store"
 	messagetemplate "hr-tools-backend/lib/message-template"
+	passwordpolicy "hr-tools-backend/lib/password-policy"
 	"hr-tools-backend/lib/smtp"
 	spacesettingsstore "hr-tools-backend/lib/space/settings/store"
 	spacestore "hr-tools-backend/lib/space/store"
@@ -25,7 +26,7 @@ import (
 )
 
 type Provider interface {
-	CreateOrganizationSpace(request spaceapimodels.CreateOrganization) error
+	CreateOrganizationSpace(request spaceapimodels.CreateOrganization) (hMsg string, err error)
 	GetProfile(spaceID string) (spaceapimodels.ProfileData, error)
 	UpdateProfile(spaceID string, data spaceapimodels.ProfileData) error
 	SendLicenseRequest(spaceID, userID, text string) (hMsg string, err error)
@@ -52,9 +53,12 @@ type impl struct {
 	salesEmail     string
 }
 
-func (i impl) CreateOrganizationSpace(request spaceapimodels.CreateOrganization) error {
+func (i impl) CreateOrganizationSpace(request spaceapimodels.CreateOrganization) (hMsg string, err error) {
+	if hMsg = passwordpolicy.Instance.Validate(request.AdminData.Password); hMsg != "" {
+		return hMsg, nil
+	}
 	var spaceID string
-	err := db.DB.Transaction(func(tx *gorm.DB) error {
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
 		var err error
 		// создаем пространство для организации
 		spaceID, err = i.createSpace(tx, request)
@@ -90,9 +94,9 @@ func (i impl) CreateOrganizationSpace(request spaceapimodels.CreateOrganization)
 	})
 
 	if err != nil {
-		return err
+		return "", err
 	}
-	return nil
+	return "", nil
 }
 
 func (i impl) GetProfile(spaceID string) (spaceapimodels.ProfileData, error) {
@@ -177,8 +181,12 @@ func (i impl) createSpace(tx *gorm.DB, request spaceapimodels.CreateOrganization
 }
 
 func (i impl) createAdmin(tx *gorm.DB, spaceID string, adminData spaceapimodels.CreateSpaceAdmin) error {
+	passwordHash, err := authhelpers.HashPassword(adminData.Password)
+	if err != nil {
+		return err
+	}
 	admin := dbmodels.SpaceUser{
-		Password:    authhelpers.GetMD5Hash(adminData.Password),
+		Password:    passwordHash,
 		FirstName:   adminData.FirstName,
 		LastName:    adminData.LastName,
 		Role:        models.AdminRole,
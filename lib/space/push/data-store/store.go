@@ -2,14 +2,21 @@ package pushdatastore
 
 import (
 	dbmodels "hr-tools-backend/models/db"
+	"slices"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type Provider interface {
-	Create(rec dbmodels.PushData) error
-	List(userID string) ([]dbmodels.PushData, error)
+	Create(rec dbmodels.PushData) (id string, err error)
+	// List последние limit событий пользователя, созданные после since, в порядке создания
+	List(userID string, since time.Time, limit int) ([]dbmodels.PushData, error)
 	Delete(ids []string) error
+	// DeleteByUser удаление событий пользователя, полученных клиентом
+	DeleteByUser(userID string, ids []string) error
+	// DeleteBefore удаление неподтвержденных событий, созданных до before
+	DeleteBefore(before time.Time) error
 }
 
 func NewInstance(DB *gorm.DB) Provider {
@@ -22,25 +29,46 @@ type impl struct {
 	db *gorm.DB
 }
 
-func (i impl) Create(rec dbmodels.PushData) error {
-	return i.db.
+func (i impl) Create(rec dbmodels.PushData) (id string, err error) {
+	err = i.db.
 		Save(&rec).
 		Error
+	if err != nil {
+		return "", err
+	}
+	return rec.ID, nil
 }
 
-func (i impl) List(userID string) (list []dbmodels.PushData, err error) {
+func (i impl) List(userID string, since time.Time, limit int) (list []dbmodels.PushData, err error) {
 	tx := i.db.Model(dbmodels.PushData{})
 	err = tx.
 		Where("user_id = ?", userID).
-		Order("created_at").
+		Where("created_at > ?", since).
+		Order("created_at desc").
+		Limit(limit).
 		Find(&list).
 		Error
 	if err != nil {
 		return nil, err
 	}
+	slices.Reverse(list)
 	return list, nil
 }
 
 func (i impl) Delete(ids []string) error {
 	return i.db.Delete(&dbmodels.PushData{}, ids).Error
 }
+
+func (i impl) DeleteByUser(userID string, ids []string) error {
+	return i.db.
+		Where("user_id = ?", userID).
+		Delete(&dbmodels.PushData{}, ids).
+		Error
+}
+
+func (i impl) DeleteBefore(before time.Time) error {
+	return i.db.
+		Where("created_at < ?", before).
+		Delete(&dbmodels.PushData{}).
+		Error
+}
@@ -7,6 +7,8 @@ import (
 	pushdatastore "hr-tools-backend/lib/space/push/data-store"
 	pushsettingsstore "hr-tools-backend/lib/space/push/settings-store"
 	spaceusersstore "hr-tools-backend/lib/space/users/store"
+	"hr-tools-backend/lib/telegram"
+	telegramclient "hr-tools-backend/lib/telegram/client"
 	initchecker "hr-tools-backend/lib/utils/init-checker"
 	vacancyreqstore "hr-tools-backend/lib/vacancy-req/store"
 	connectionhub "hr-tools-backend/lib/ws/hub/connection-hub"
@@ -15,6 +17,7 @@ import (
 	wsmodels "hr-tools-backend/models/ws"
 	"time"
 
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -83,35 +86,55 @@ func (i impl) SendNotification(userID string, data models.NotificationData) {
 		smtp.Instance.IsConfigured() {
 		go i.sendToEmail(user.Email, data)
 	}
-	if pushSetting.TgValue != nil && *pushSetting.TgValue {
-		//send Tg push
+	if pushSetting.TgValue != nil && *pushSetting.TgValue && user.TgChatID != nil &&
+		telegram.Instance.IsConfigured() {
+		go i.sendToTg(userID, *user.TgChatID, data)
 	}
 }
 
 func (i impl) sendToWs(userID string, data models.NotificationData) {
 	logger := i.getLogger(userID, string(data.Code))
-	if connectionhub.Instance.IsConnected(userID) {
-		msg := wsmodels.ServerMessage{
-			ToUserID: userID,
-			Time:     time.Now().Format("02.01.2006 15:04:05"),
-			Code:     string(data.Code),
-			Msg:      data.Msg,
-			Title:    data.Title,
-		}
-		connectionhub.Instance.SendMessage(msg)
-	} else {
-		rec := dbmodels.PushData{
-			UserID: userID,
-			Code:   data.Code,
-			Msg:    data.Msg,
-			Title:  data.Title,
-		}
-		err := i.pushDataStore.Create(rec)
+	// событие хранится до подтверждения получения клиентом,
+	// если пользователь не подключен - отправится при подключении
+	rec := dbmodels.PushData{
+		UserID: userID,
+		Code:   data.Code,
+		Msg:    data.Msg,
+		Title:  data.Title,
+	}
+	id, err := i.pushDataStore.Create(rec)
+	if err != nil {
+		logger.WithError(err).Error("ошибка сохранения данных по событию")
+		return
+	}
+	msg := wsmodels.ServerMessage{
+		ToUserID: userID,
+		ID:       id,
+		Type:     wsmodels.ServerMessagePush,
+		Time:     time.Now().Format("02.01.2006 15:04:05"),
+		Code:     string(data.Code),
+		Msg:      data.Msg,
+		Title:    data.Title,
+	}
+	connectionhub.Instance.SendMessage(msg)
+}
+
+func (i impl) sendToTg(userID string, chatID int64, data models.NotificationData) {
+	logger := i.getLogger(userID, string(data.Code))
+	err := telegram.Instance.SendNotification(chatID, data)
+	if err == nil {
+		return
+	}
+	if errors.Is(err, telegramclient.ErrBlocked) {
+		// пользователь заблокировал бота, отвязываем чат
+		err = i.spaceUserStore.Update(userID, map[string]interface{}{"tg_chat_id": nil})
 		if err != nil {
-			logger.WithError(err).Error("ошибка сохранения данных по событию")
-			return
+			logger.WithError(err).Error("ошибка отвязки чата Telegram")
 		}
+		logger.Info("бот заблокирован пользователем, чат Telegram отвязан")
+		return
 	}
+	logger.WithError(err).Error("ошибка отправки уведомления в Telegram")
 }
 
 func (i impl) sendToEmail(email string, data models.NotificationData) {
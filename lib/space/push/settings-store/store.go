@@ -12,7 +12,8 @@ type Provider interface {
 	Update(spaceID, userID string, code models.SpacePushSettingCode, updMap map[string]interface{}) error
 	List(spaceID, userID string) (settingsList []dbmodels.SpacePushSetting, err error)
 	GetByCode(userID string, code models.SpacePushSettingCode) (*dbmodels.SpacePushSetting, error)
-	GetUsersWithoutSettings() (userList []dbmodels.SpaceUser, err error)
+	// GetUsersWithoutSetting пользователи, у которых нет настройки по событию
+	GetUsersWithoutSetting(code models.SpacePushSettingCode) (userList []dbmodels.SpaceUser, err error)
 }
 
 func NewInstance(DB *gorm.DB) Provider {
@@ -66,9 +67,9 @@ func (i impl) GetByCode(userID string, code models.SpacePushSettingCode) (rec *d
 	return rec, nil
 }
 
-func (i impl) GetUsersWithoutSettings() (userList []dbmodels.SpaceUser, err error) {
+func (i impl) GetUsersWithoutSetting(code models.SpacePushSettingCode) (userList []dbmodels.SpaceUser, err error) {
 	tx := i.db.Model(dbmodels.SpaceUser{})
-	subQuery := i.db.Select("space_user_id").Table("space_push_settings").Group("space_user_id")
+	subQuery := i.db.Select("space_user_id").Table("space_push_settings").Where("code = ?", code)
 	tx.Where("id not in (?)", subQuery)
 	err = tx.
 		Find(&userList).
@@ -0,0 +1,272 @@
+package spacerolehandler
+
+import (
+	"fmt"
+	"hr-tools-backend/db"
+	auditlog "hr-tools-backend/lib/audit-log"
+	"hr-tools-backend/lib/rbac"
+	spacerolestore "hr-tools-backend/lib/space/roles/store"
+	initchecker "hr-tools-backend/lib/utils/init-checker"
+	"hr-tools-backend/models"
+	spaceroleapimodels "hr-tools-backend/models/api/space-role"
+	dbmodels "hr-tools-backend/models/db"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type Provider interface {
+	// GetCatalog модули и разрешения для матрицы роли и разрешения встроенных ролей
+	GetCatalog() spaceroleapimodels.RoleCatalog
+	List(spaceID string) ([]spaceroleapimodels.SpaceRoleView, error)
+	Get(spaceID, id string) (*spaceroleapimodels.SpaceRoleView, error)
+	// Create создание роли, пользователь может выдать роли только те разрешения, которые есть у него самого
+	Create(spaceID, userID string, userRole models.UserRole, data spaceroleapimodels.SpaceRoleData) (id, hMsg string, err error)
+	// Update изменение роли, при смене базовой роли она меняется и у пользователей с этой ролью
+	Update(spaceID, id, userID string, userRole models.UserRole, data spaceroleapimodels.SpaceRoleData) (hMsg string, err error)
+	Delete(spaceID, id, userID string) (hMsg string, err error)
+}
+
+var Instance Provider
+
+func NewHandler() {
+	instance := impl{
+		store: spacerolestore.NewInstance(db.DB),
+	}
+	initchecker.CheckInit(
+		"store", instance.store,
+	)
+	Instance = instance
+}
+
+type impl struct {
+	store spacerolestore.Provider
+}
+
+func (i impl) getLogger(spaceID, id string) *log.Entry {
+	logger := log.WithField("space_id", spaceID)
+	if id != "" {
+		logger = logger.WithField("space_role_id", id)
+	}
+	return logger
+}
+
+func (i impl) GetCatalog() spaceroleapimodels.RoleCatalog {
+	catalog := rbac.Instance.GetCatalog()
+	result := spaceroleapimodels.RoleCatalog{
+		Modules:      make([]spaceroleapimodels.ModulePermissions, 0, len(catalog)),
+		BuiltinRoles: make([]spaceroleapimodels.BuiltinRoleView, 0, len(models.AllAvailableRoles)),
+	}
+	for module, permissions := range catalog {
+		result.Modules = append(result.Modules, spaceroleapimodels.ModulePermissions{
+			Module:      module,
+			Permissions: permissions,
+		})
+	}
+	slices.SortFunc(result.Modules, func(a, b spaceroleapimodels.ModulePermissions) int {
+		return strings.Compare(string(a.Module), string(b.Module))
+	})
+	for _, role := range models.AllAvailableRoles {
+		result.BuiltinRoles = append(result.BuiltinRoles, spaceroleapimodels.BuiltinRoleView{
+			Role:        role,
+			RoleName:    role.ToHuman(),
+			Permissions: rbac.Instance.GetPermissions(role),
+		})
+	}
+	return result
+}
+
+func (i impl) List(spaceID string) ([]spaceroleapimodels.SpaceRoleView, error) {
+	list, err := i.store.List(spaceID)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения списка ролей")
+	}
+	counts, err := i.store.CountUsers(spaceID)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка подсчета пользователей с ролями")
+	}
+	result := make([]spaceroleapimodels.SpaceRoleView, 0, len(list))
+	for _, rec := range list {
+		result = append(result, spaceroleapimodels.SpaceRoleConvert(rec, counts[rec.ID]))
+	}
+	return result, nil
+}
+
+func (i impl) Get(spaceID, id string) (*spaceroleapimodels.SpaceRoleView, error) {
+	rec, err := i.store.GetByID(spaceID, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения роли")
+	}
+	if rec == nil {
+		return nil, nil
+	}
+	counts, err := i.store.CountUsers(spaceID)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка подсчета пользователей с ролями")
+	}
+	result := spaceroleapimodels.SpaceRoleConvert(*rec, counts[rec.ID])
+	return &result, nil
+}
+
+func (i impl) Create(spaceID, userID string, userRole models.UserRole, data spaceroleapimodels.SpaceRoleData) (id, hMsg string, err error) {
+	permissions, hMsg := normalizePermissions(data.Permissions)
+	if hMsg != "" {
+		return "", hMsg, nil
+	}
+	if hMsg = rbac.Instance.CheckGrant(userID, userRole, permissions); hMsg != "" {
+		return "", hMsg, nil
+	}
+	hMsg, err = i.checkName(spaceID, "", data.Name)
+	if err != nil || hMsg != "" {
+		return "", hMsg, err
+	}
+	rec := dbmodels.SpaceRole{
+		BaseSpaceModel: dbmodels.BaseSpaceModel{SpaceID: spaceID},
+		Name:           strings.TrimSpace(data.Name),
+		Description:    data.Description,
+		BaseRole:       data.BaseRole,
+		Permissions:    permissions,
+	}
+	id, err = i.store.Create(rec)
+	if err != nil {
+		return "", "", errors.Wrap(err, "ошибка создания роли")
+	}
+	i.getLogger(spaceID, id).Info("создана роль спейса")
+	auditlog.Instance.SaveChanges(spaceID, userID, models.AuditActionCreate, models.AuditEntityRole, id,
+		"Создана роль", nil, auditData(rec))
+	return id, "", nil
+}
+
+func (i impl) Update(spaceID, id, userID string, userRole models.UserRole, data spaceroleapimodels.SpaceRoleData) (hMsg string, err error) {
+	rec, err := i.store.GetByID(spaceID, id)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения роли")
+	}
+	if rec == nil {
+		return "роль не найдена", nil
+	}
+	permissions, hMsg := normalizePermissions(data.Permissions)
+	if hMsg != "" {
+		return hMsg, nil
+	}
+	if hMsg = rbac.Instance.CheckGrant(userID, userRole, permissions); hMsg != "" {
+		return hMsg, nil
+	}
+	hMsg, err = i.checkName(spaceID, id, data.Name)
+	if err != nil || hMsg != "" {
+		return hMsg, err
+	}
+	updMap := map[string]interface{}{
+		"name":        strings.TrimSpace(data.Name),
+		"description": data.Description,
+		"base_role":   data.BaseRole,
+		"permissions": permissions,
+	}
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		err := spacerolestore.NewInstance(tx).Update(spaceID, id, updMap)
+		if err != nil {
+			return errors.Wrap(err, "ошибка изменения роли")
+		}
+		if rec.BaseRole == data.BaseRole {
+			return nil
+		}
+		err = tx.
+			Model(&dbmodels.SpaceUser{}).
+			Where("space_id = ?", spaceID).
+			Where("space_role_id = ?", id).
+			Update("role", data.BaseRole).
+			Error
+		if err != nil {
+			return errors.Wrap(err, "ошибка изменения базовой роли пользователей")
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	rbac.Instance.ResetCache()
+	i.getLogger(spaceID, id).Info("изменена роль спейса")
+	after := *rec
+	after.Name = strings.TrimSpace(data.Name)
+	after.Description = data.Description
+	after.BaseRole = data.BaseRole
+	after.Permissions = permissions
+	auditlog.Instance.SaveChanges(spaceID, userID, models.AuditActionUpdate, models.AuditEntityRole, id,
+		"Изменена роль", auditData(*rec), auditData(after))
+	return "", nil
+}
+
+func (i impl) Delete(spaceID, id, userID string) (hMsg string, err error) {
+	rec, err := i.store.GetByID(spaceID, id)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения роли")
+	}
+	if rec == nil {
+		return "роль не найдена", nil
+	}
+	counts, err := i.store.CountUsers(spaceID)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка подсчета пользователей с ролями")
+	}
+	if counts[id] > 0 {
+		return fmt.Sprintf("роль назначена пользователям (%v), сначала смените им роль", counts[id]), nil
+	}
+	err = i.store.Delete(spaceID, id)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка удаления роли")
+	}
+	rbac.Instance.ResetCache()
+	i.getLogger(spaceID, id).Info("удалена роль спейса")
+	auditlog.Instance.SaveChanges(spaceID, userID, models.AuditActionDelete, models.AuditEntityRole, id,
+		"Удалена роль", auditData(*rec), nil)
+	return "", nil
+}
+
+// auditData данные роли для журнала аудита
+func auditData(rec dbmodels.SpaceRole) spaceroleapimodels.SpaceRoleData {
+	return spaceroleapimodels.SpaceRoleConvert(rec, 0).SpaceRoleData
+}
+
+func (i impl) checkName(spaceID, id, name string) (hMsg string, err error) {
+	list, err := i.store.List(spaceID)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения списка ролей")
+	}
+	name = strings.TrimSpace(name)
+	for _, rec := range list {
+		if rec.ID != id && strings.EqualFold(rec.Name, name) {
+			return "роль с таким названием уже существует", nil
+		}
+	}
+	for _, role := range models.AllAvailableRoles {
+		if strings.EqualFold(role.ToHuman(), name) {
+			return "название совпадает со встроенной ролью", nil
+		}
+	}
+	return "", nil
+}
+
+// normalizePermissions проверка матрицы по справочнику RBAC, повторы и пустые модули убираются
+func normalizePermissions(data map[models.Module][]models.Permission) (dbmodels.SpaceRolePermissions, string) {
+	catalog := rbac.Instance.GetCatalog()
+	result := dbmodels.SpaceRolePermissions{}
+	for module, permissions := range data {
+		available, ok := catalog[module]
+		if !ok {
+			return nil, fmt.Sprintf("модуль %v отсутствует", module)
+		}
+		for _, permission := range permissions {
+			if !slices.Contains(available, permission) {
+				return nil, fmt.Sprintf("разрешение %v/%v отсутствует", module, permission)
+			}
+			if !result.Has(module, permission) {
+				result[module] = append(result[module], permission)
+			}
+		}
+		slices.Sort(result[module])
+	}
+	return result, ""
+}
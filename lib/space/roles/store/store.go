@@ -0,0 +1,134 @@
+package spacerolestore
+
+import (
+	dbmodels "hr-tools-backend/models/db"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Provider interface {
+	Create(rec dbmodels.SpaceRole) (id string, err error)
+	Update(spaceID, id string, updMap map[string]interface{}) error
+	Delete(spaceID, id string) error
+	GetByID(spaceID, id string) (*dbmodels.SpaceRole, error)
+	List(spaceID string) ([]dbmodels.SpaceRole, error)
+	// CountUsers кол-во пользователей по ролям спейса
+	CountUsers(spaceID string) (map[string]int64, error)
+	// GetUserRole роль спейса, назначенная пользователю, nil - не назначена
+	GetUserRole(userID string) (*dbmodels.SpaceRole, error)
+}
+
+func NewInstance(DB *gorm.DB) Provider {
+	return &impl{
+		db: DB,
+	}
+}
+
+type impl struct {
+	db *gorm.DB
+}
+
+func (i impl) Create(rec dbmodels.SpaceRole) (id string, err error) {
+	err = i.db.
+		Omit(clause.Associations).
+		Save(&rec).
+		Error
+	if err != nil {
+		return "", err
+	}
+	return rec.ID, nil
+}
+
+func (i impl) Update(spaceID, id string, updMap map[string]interface{}) error {
+	if len(updMap) == 0 {
+		return nil
+	}
+	return i.db.
+		Model(&dbmodels.SpaceRole{}).
+		Where("space_id = ?", spaceID).
+		Where("id = ?", id).
+		Updates(updMap).
+		Error
+}
+
+func (i impl) Delete(spaceID, id string) error {
+	return i.db.
+		Where("space_id = ?", spaceID).
+		Where("id = ?", id).
+		Delete(&dbmodels.SpaceRole{}).
+		Error
+}
+
+func (i impl) GetByID(spaceID, id string) (*dbmodels.SpaceRole, error) {
+	rec := dbmodels.SpaceRole{}
+	err := i.db.
+		Where("space_id = ?", spaceID).
+		Where("id = ?", id).
+		First(&rec).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (i impl) List(spaceID string) ([]dbmodels.SpaceRole, error) {
+	list := []dbmodels.SpaceRole{}
+	err := i.db.
+		Where("space_id = ?", spaceID).
+		Order("name").
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (i impl) CountUsers(spaceID string) (map[string]int64, error) {
+	type countRow struct {
+		SpaceRoleID string
+		Count       int64
+	}
+	rows := []countRow{}
+	err := i.db.
+		Model(&dbmodels.SpaceUser{}).
+		Select("space_role_id, count(*) as count").
+		Where("space_id = ?", spaceID).
+		Where("space_role_id IS NOT NULL").
+		Where("deleted_at IS NULL").
+		Group("space_role_id").
+		Scan(&rows).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		result[row.SpaceRoleID] = row.Count
+	}
+	return result, nil
+}
+
+func (i impl) GetUserRole(userID string) (*dbmodels.SpaceRole, error) {
+	rec := dbmodels.SpaceRole{}
+	err := i.db.
+		Model(&dbmodels.SpaceRole{}).
+		Joins("JOIN space_users ON space_users.space_role_id = space_roles.id AND space_users.space_id = space_roles.space_id").
+		Where("space_users.id = ?", userID).
+		Where("space_users.deleted_at IS NULL").
+		First(&rec).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rec, nil
+}
@@ -2,16 +2,18 @@ package spacesettingshandler
 
 import (
 	"hr-tools-backend/db"
+	auditlog "hr-tools-backend/lib/audit-log"
 	spacesettingsstore "hr-tools-backend/lib/space/settings/store"
 	initchecker "hr-tools-backend/lib/utils/init-checker"
 	"hr-tools-backend/models"
 	spaceapimodels "hr-tools-backend/models/api/space"
 
 	"github.com/pkg/errors"
+	"gorm.io/gorm"
 )
 
 type Provider interface {
-	UpdateSettingValue(spaceID, settingCode, settingValue string) error
+	UpdateSettingValue(spaceID, userID, settingCode, settingValue string) error
 	GetList(spaceID string) (settingsList []spaceapimodels.SpaceSettingView, err error)
 }
 
@@ -31,7 +33,7 @@ type impl struct {
 	spaceSettingsStore spacesettingsstore.Provider
 }
 
-func (i impl) UpdateSettingValue(spaceID, settingCode, settingValue string) error {
+func (i impl) UpdateSettingValue(spaceID, userID, settingCode, settingValue string) error {
 	ok, err := i.isUnique(spaceID, settingCode, settingValue)
 	if err != nil {
 		return errors.Wrap(err, "ошибка проверки уникальности настройки")
@@ -39,10 +41,17 @@ func (i impl) UpdateSettingValue(spaceID, settingCode, settingValue string) erro
 	if !ok {
 		return errors.New("значение настройки уже используется в другом спейсе")
 	}
+	oldValue, err := i.spaceSettingsStore.GetValueByCode(spaceID, models.SpaceSettingCode(settingCode))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.Wrap(err, "ошибка получения значения настройки")
+	}
 	err = i.spaceSettingsStore.Update(spaceID, settingCode, settingValue)
 	if err != nil {
 		return err
 	}
+	// значения секретов (пароли, client secret) маскируются в журнале по коду настройки
+	auditlog.Instance.SaveChanges(spaceID, userID, models.AuditActionUpdate, models.AuditEntitySetting, settingCode,
+		"Изменена настройка", map[string]string{settingCode: oldValue}, map[string]string{settingCode: settingValue})
 	return nil
 }
 
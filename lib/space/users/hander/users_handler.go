@@ -2,9 +2,15 @@ package spaceusershander
 
 import (
 	"hr-tools-backend/db"
+	auditlog "hr-tools-backend/lib/audit-log"
+	authsession "hr-tools-backend/lib/auth-session"
+	departmentstore "hr-tools-backend/lib/dicts/department/store"
+	passwordpolicy "hr-tools-backend/lib/password-policy"
+	"hr-tools-backend/lib/rbac"
 	"hr-tools-backend/lib/smtp"
 	spaceauthhandler "hr-tools-backend/lib/space/auth"
 	pushsettingsstore "hr-tools-backend/lib/space/push/settings-store"
+	spacerolestore "hr-tools-backend/lib/space/roles/store"
 	spaceusersstore "hr-tools-backend/lib/space/users/store"
 	authhelpers "hr-tools-backend/lib/utils/auth-helpers"
 	initchecker "hr-tools-backend/lib/utils/init-checker"
@@ -15,14 +21,15 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
 type Provider interface {
-	CreateUser(request spaceapimodels.CreateUser, authorSpaceID string) (id, hMsg string, err error)
-	UpdateUser(userID string, request spaceapimodels.UpdateUser) error
-	UpdateUserStatus(userID string, request spaceapimodels.UpdateUserStatus) (user spaceapimodels.SpaceUser, err error)
-	DeleteUser(userID string) error
+	CreateUser(request spaceapimodels.CreateUser, authorSpaceID, authorID string) (id, hMsg string, err error)
+	UpdateUser(userID, authorID string, request spaceapimodels.UpdateUser) (hMsg string, err error)
+	UpdateUserStatus(userID, authorID string, request spaceapimodels.UpdateUserStatus) (user spaceapimodels.SpaceUser, err error)
+	DeleteUser(userID, authorID string) error
 	GetListUsers(spaceID string, filter spaceapimodels.SpaceUserFilter) (usersList []spaceapimodels.SpaceUser, rowCount int64, err error)
 	GetByID(userID string) (user spaceapimodels.SpaceUser, err error)
 	UpdateUserProfile(userID string, request spaceapimodels.SpaceUserProfileData) error
@@ -40,10 +47,14 @@ func NewHandler() {
 	instance := impl{
 		spaceUserStore:    spaceusersstore.NewInstance(db.DB),
 		pushSettingsStore: pushsettingsstore.NewInstance(db.DB),
+		spaceRoleStore:    spacerolestore.NewInstance(db.DB),
+		departmentStore:   departmentstore.NewInstance(db.DB),
 	}
 	initchecker.CheckInit(
 		"spaceUserStore", instance.spaceUserStore,
 		"pushSettingsStore", instance.pushSettingsStore,
+		"spaceRoleStore", instance.spaceRoleStore,
+		"departmentStore", instance.departmentStore,
 	)
 	Instance = instance
 }
@@ -51,6 +62,8 @@ func NewHandler() {
 type impl struct {
 	spaceUserStore    spaceusersstore.Provider
 	pushSettingsStore pushsettingsstore.Provider
+	spaceRoleStore    spacerolestore.Provider
+	departmentStore   departmentstore.Provider
 }
 
 func (i impl) GetByID(userID string) (user spaceapimodels.SpaceUser, err error) {
@@ -64,7 +77,7 @@ func (i impl) GetByID(userID string) (user spaceapimodels.SpaceUser, err error)
 	return userDB.ToModel(), nil
 }
 
-func (i impl) CreateUser(request spaceapimodels.CreateUser, authorSpaceID string) (id, hMsg string, err error) {
+func (i impl) CreateUser(request spaceapimodels.CreateUser, authorSpaceID, authorID string) (id, hMsg string, err error) {
 	userExist, err := i.spaceUserStore.ExistByEmail(request.Email)
 	if err != nil {
 		return "", "", err
@@ -72,8 +85,15 @@ func (i impl) CreateUser(request spaceapimodels.CreateUser, authorSpaceID string
 	if userExist {
 		return "", "пользователь с такой почтой уже существует", nil
 	}
+	if hMsg = passwordpolicy.Instance.Validate(request.Password); hMsg != "" {
+		return "", hMsg, nil
+	}
+	passwordHash, err := authhelpers.HashPassword(request.Password)
+	if err != nil {
+		return "", "", err
+	}
 	rec := dbmodels.SpaceUser{
-		Password:        authhelpers.GetMD5Hash(request.Password),
+		Password:        passwordHash,
 		FirstName:       request.FirstName,
 		LastName:        request.LastName,
 		Email:           request.Email,
@@ -88,6 +108,29 @@ func (i impl) CreateUser(request spaceapimodels.CreateUser, authorSpaceID string
 	}
 
 	rec.Role = models.UserRole(request.Role)
+	if request.SpaceRoleID != "" {
+		spaceRole, err := i.spaceRoleStore.GetByID(authorSpaceID, request.SpaceRoleID)
+		if err != nil {
+			return "", "", errors.Wrap(err, "ошибка получения роли спейса")
+		}
+		if spaceRole == nil {
+			return "", "роль не найдена", nil
+		}
+		hMsg, err = i.checkRoleGrant(authorID, *spaceRole)
+		if err != nil || hMsg != "" {
+			return "", hMsg, err
+		}
+		rec.SpaceRoleID = &spaceRole.ID
+		rec.Role = spaceRole.BaseRole
+	}
+	rec.DataScope = request.DataScope
+	if request.DepartmentID != "" {
+		hMsg, err = i.checkDepartment(authorSpaceID, request.DepartmentID)
+		if err != nil || hMsg != "" {
+			return "", hMsg, err
+		}
+		rec.DepartmentID = &request.DepartmentID
+	}
 
 	err = db.DB.Transaction(func(tx *gorm.DB) error {
 		spaceUserStore := spaceusersstore.NewInstance(db.DB)
@@ -104,14 +147,41 @@ func (i impl) CreateUser(request spaceapimodels.CreateUser, authorSpaceID string
 	if err != nil {
 		return "", "", err
 	}
+	i.auditChanges(authorSpaceID, authorID, models.AuditActionCreate, id, "Создан пользователь", nil)
 	return id, "", nil
 }
 
-func (i impl) UpdateUser(userID string, request spaceapimodels.UpdateUser) error {
+func (i impl) UpdateUser(userID, authorID string, request spaceapimodels.UpdateUser) (hMsg string, err error) {
 	user, err := i.GetByID(userID)
 	if err != nil {
-		return err
+		return "", err
+	}
+	var spaceRole *dbmodels.SpaceRole
+	if request.SpaceRoleID != nil && *request.SpaceRoleID != "" {
+		spaceRole, err = i.spaceRoleStore.GetByID(user.SpaceID, *request.SpaceRoleID)
+		if err != nil {
+			return "", errors.Wrap(err, "ошибка получения роли спейса")
+		}
+		if spaceRole == nil {
+			return "роль не найдена", nil
+		}
+		hMsg, err = i.checkRoleGrant(authorID, *spaceRole)
+		if err != nil || hMsg != "" {
+			return hMsg, err
+		}
 	}
+	if request.DepartmentID != nil && *request.DepartmentID != "" {
+		hMsg, err = i.checkDepartment(user.SpaceID, *request.DepartmentID)
+		if err != nil || hMsg != "" {
+			return hMsg, err
+		}
+	}
+	if request.Password != nil && *request.Password != "" {
+		if hMsg = passwordpolicy.Instance.Validate(*request.Password); hMsg != "" {
+			return hMsg, nil
+		}
+	}
+	isRoleChanged := request.Role != nil || request.SpaceRoleID != nil
 
 	err = db.DB.Transaction(func(tx *gorm.DB) error {
 		updMap := map[string]interface{}{
@@ -119,11 +189,28 @@ func (i impl) UpdateUser(userID string, request spaceapimodels.UpdateUser) error
 			"last_name":    request.LastName,
 			"phone_number": request.PhoneNumber,
 		}
-		if request.Role != nil {
+		switch {
+		case spaceRole != nil:
+			updMap["space_role_id"] = spaceRole.ID
+			updMap["role"] = spaceRole.BaseRole
+		case request.SpaceRoleID != nil:
+			// роль спейса снята, действует встроенная роль
+			updMap["space_role_id"] = nil
+			if request.Role != nil {
+				updMap["role"] = models.UserRole(*request.Role)
+			}
+		case request.Role != nil && user.SpaceRoleID == "":
+			// при назначенной роли спейса встроенная роль определяется ее базовой ролью
 			updMap["role"] = models.UserRole(*request.Role)
 		}
 		if request.Password != nil && *request.Password != "" {
-			updMap["password"] = authhelpers.GetMD5Hash(*request.Password)
+			passwordHash, err := authhelpers.HashPassword(*request.Password)
+			if err != nil {
+				return err
+			}
+			updMap["password"] = passwordHash
+			updMap["failed_login_attempts"] = 0
+			updMap["locked_until"] = nil
 		}
 
 		if request.JobTitleID != nil && *request.JobTitleID != "" {
@@ -132,6 +219,16 @@ func (i impl) UpdateUser(userID string, request spaceapimodels.UpdateUser) error
 		if request.TextSign != nil {
 			updMap["text_sign"] = *request.TextSign
 		}
+		if request.DataScope != nil {
+			updMap["data_scope"] = *request.DataScope
+		}
+		if request.DepartmentID != nil {
+			if *request.DepartmentID != "" {
+				updMap["department_id"] = *request.DepartmentID
+			} else {
+				updMap["department_id"] = nil
+			}
+		}
 		isEmailChanged := user.Email != request.Email
 		if isEmailChanged {
 			if smtp.Instance.IsConfigured() {
@@ -156,11 +253,22 @@ func (i impl) UpdateUser(userID string, request spaceapimodels.UpdateUser) error
 		}
 		return nil
 	})
-
-	return err
+	if err != nil {
+		return "", err
+	}
+	if isRoleChanged {
+		rbac.Instance.ResetCache()
+	}
+	description := "Изменен пользователь"
+	if request.Password != nil && *request.Password != "" {
+		i.revokeSessions(userID, "смена пароля администратором")
+		description = "Изменен пользователь, пароль изменен администратором"
+	}
+	i.auditChanges(user.SpaceID, authorID, models.AuditActionUpdate, userID, description, &user)
+	return "", nil
 }
 
-func (i impl) UpdateUserStatus(userID string, request spaceapimodels.UpdateUserStatus) (user spaceapimodels.SpaceUser, err error) {
+func (i impl) UpdateUserStatus(userID, authorID string, request spaceapimodels.UpdateUserStatus) (user spaceapimodels.SpaceUser, err error) {
 	userDB, err := i.spaceUserStore.GetByID(userID)
 	if err != nil {
 		return spaceapimodels.SpaceUser{}, err
@@ -183,6 +291,11 @@ func (i impl) UpdateUserStatus(userID string, request spaceapimodels.UpdateUserS
 	if err != nil {
 		return spaceapimodels.SpaceUser{}, err
 	}
+	if userDB.Status != models.UserStatus(request.Status) {
+		i.revokeSessions(userID, "изменение статуса пользователя")
+	}
+	before := userDB.ToModel()
+	i.auditChanges(userDB.SpaceID, authorID, models.AuditActionUpdate, userID, "Изменен статус пользователя", &before)
 
 	// Обновляем поля в уже полученном объекте
 	userDB.Status = models.UserStatus(request.Status)
@@ -197,14 +310,69 @@ func (i impl) UpdateUserStatus(userID string, request spaceapimodels.UpdateUserS
 	return userDB.ToModel(), nil
 }
 
-func (i impl) DeleteUser(userID string) error {
-	err := i.spaceUserStore.Delete(userID)
+func (i impl) DeleteUser(userID, authorID string) error {
+	user, err := i.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	err = i.spaceUserStore.Delete(userID)
 	if err != nil {
 		return err
 	}
+	i.revokeSessions(userID, "удаление пользователя")
+	auditlog.Instance.SaveChanges(user.SpaceID, authorID, models.AuditActionDelete, models.AuditEntityUser, userID,
+		"Удален пользователь", user, nil)
 	return nil
 }
 
+// checkRoleGrant пользователь не может назначить роль спейса с разрешениями, которых нет у него самого
+func (i impl) checkRoleGrant(authorID string, spaceRole dbmodels.SpaceRole) (hMsg string, err error) {
+	author, err := i.spaceUserStore.GetByID(authorID)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения пользователя")
+	}
+	if author == nil {
+		return "пользователь не найден", nil
+	}
+	return rbac.Instance.CheckGrant(authorID, author.Role, spaceRole.Permissions), nil
+}
+
+func (i impl) checkDepartment(spaceID, departmentID string) (hMsg string, err error) {
+	department, err := i.departmentStore.GetByID(spaceID, departmentID)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения подразделения")
+	}
+	if department == nil {
+		return "подразделение не найдено", nil
+	}
+	return "", nil
+}
+
+// auditChanges запись в журнал аудита изменения пользователя: before - данные до изменения (nil при создании),
+// после изменения данные перечитываются
+func (i impl) auditChanges(spaceID, authorID string, action models.AuditAction, userID, description string, before *spaceapimodels.SpaceUser) {
+	after, err := i.GetByID(userID)
+	if err != nil {
+		log.
+			WithField("user_id", userID).
+			WithError(err).
+			Warn("ошибка получения пользователя для журнала аудита")
+		return
+	}
+	auditlog.Instance.SaveChanges(spaceID, authorID, action, models.AuditEntityUser, userID, description, before, after)
+}
+
+// revokeSessions отзыв сессий пользователя, ошибка отзыва не отменяет выполненное изменение
+func (i impl) revokeSessions(userID, reason string) {
+	err := authsession.Instance.RevokeAll(models.AuthSpaceUser, userID, reason)
+	if err != nil {
+		log.
+			WithField("user_id", userID).
+			WithError(err).
+			Error("ошибка отзыва сессий пользователя")
+	}
+}
+
 func (i impl) GetListUsers(spaceID string, filter spaceapimodels.SpaceUserFilter) (usersList []spaceapimodels.SpaceUser, rowCount int64, err error) {
 	rowCount, err = i.spaceUserStore.GetCountList(spaceID, filter)
 	if err != nil {
@@ -294,11 +462,18 @@ func (i impl) ChangePassword(userID string, payload spaceapimodels.PasswordChang
 	if userDB == nil {
 		return "", errors.New("пользователь не найден")
 	}
-	if userDB.Password != authhelpers.GetMD5Hash(payload.CurrentPassword) {
+	if ok, _ := authhelpers.CheckPassword(payload.CurrentPassword, userDB.Password); !ok {
 		return "Текущий пароль указан не верно", nil
 	}
+	if hMsg := passwordpolicy.Instance.Validate(payload.NewPassword); hMsg != "" {
+		return hMsg, nil
+	}
+	passwordHash, err := authhelpers.HashPassword(payload.NewPassword)
+	if err != nil {
+		return "", err
+	}
 	updMap := map[string]interface{}{
-		"password": authhelpers.GetMD5Hash(payload.NewPassword),
+		"password": passwordHash,
 	}
 	err = i.spaceUserStore.Update(userID, updMap)
 	if err != nil {
@@ -23,7 +23,11 @@ type Provider interface {
 	FindByEmail(email string, checkNew bool) (rec *dbmodels.SpaceUser, err error)
 	GetByID(userID string) (rec *dbmodels.SpaceUser, err error)
 	GetByResetCode(code string) (rec *dbmodels.SpaceUser, err error)
+	GetByTgLinkCode(code string) (rec *dbmodels.SpaceUser, err error)
+	GetByTgChatID(chatID int64) (rec *dbmodels.SpaceUser, err error)
 	GetListForVacancy(spaceID, vacancyID string, filter vacancyapimodels.PersonFilter) (userList []dbmodels.SpaceUser, err error)
+	// AddFailedLogin увеличение счетчика неверных паролей, возвращает новое значение
+	AddFailedLogin(userID string) (int, error)
 }
 
 func NewInstance(DB *gorm.DB) Provider {
@@ -41,7 +45,8 @@ func (i impl) GetCountList(spaceID string, filter spaceapimodels.SpaceUserFilter
 	tx := i.db.
 		Model(dbmodels.SpaceUser{}).
 		Where("space_id = ?", spaceID).
-		Where("deleted_at IS NULL")
+		Where("deleted_at IS NULL").
+		Where("is_service = ?", false)
 	// Исключаем уволенных пользователей, если не указан явно фильтр по статусу DISMISSED
 	if filter.Status == nil || *filter.Status != string(models.SpaceDismissedStatus) {
 		tx = tx.Where("status != ?", models.SpaceDismissedStatus)
@@ -59,7 +64,8 @@ func (i impl) GetList(spaceID string, filter spaceapimodels.SpaceUserFilter) (us
 		Model(dbmodels.SpaceUser{}).
 		Select("space_users.*, (last_name || ' ' || first_name) as fio").
 		Where("space_id = ?", spaceID).
-		Where("deleted_at IS NULL")
+		Where("deleted_at IS NULL").
+		Where("is_service = ?", false)
 	// Исключаем уволенных пользователей, если не указан явно фильтр по статусу DISMISSED
 	if filter.Status == nil || *filter.Status != string(models.SpaceDismissedStatus) {
 		tx = tx.Where("status != ?", models.SpaceDismissedStatus)
@@ -102,6 +108,20 @@ func (i impl) Update(userID string, updMap map[string]interface{}) error {
 	return nil
 }
 
+func (i impl) AddFailedLogin(userID string) (int, error) {
+	rec := dbmodels.SpaceUser{}
+	err := i.db.
+		Model(&rec).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "failed_login_attempts"}}}).
+		Where("id = ?", userID).
+		UpdateColumn("failed_login_attempts", gorm.Expr("failed_login_attempts + 1")).
+		Error
+	if err != nil {
+		return 0, err
+	}
+	return rec.FailedLoginAttempts, nil
+}
+
 func (i impl) GetByID(userID string) (rec *dbmodels.SpaceUser, err error) {
 	err = i.db.Model(dbmodels.SpaceUser{}).
 		Where("id = ?", userID).
@@ -177,12 +197,43 @@ func (i impl) GetByResetCode(code string) (rec *dbmodels.SpaceUser, err error) {
 	return rec, nil
 }
 
+func (i impl) GetByTgLinkCode(code string) (rec *dbmodels.SpaceUser, err error) {
+	err = i.db.Model(dbmodels.SpaceUser{}).
+		Where("tg_link_code = ?", code).
+		Where("deleted_at IS NULL").
+		First(&rec).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return rec, nil
+}
+
+func (i impl) GetByTgChatID(chatID int64) (rec *dbmodels.SpaceUser, err error) {
+	err = i.db.Model(dbmodels.SpaceUser{}).
+		Where("tg_chat_id = ?", chatID).
+		Where("deleted_at IS NULL").
+		First(&rec).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return rec, nil
+}
+
 func (i impl) GetListForVacancy(spaceID, vacancyID string, filter vacancyapimodels.PersonFilter) (userList []dbmodels.SpaceUser, err error) {
 	tx := i.db.Model(dbmodels.SpaceUser{})
 	tx = tx.
 		Where("space_id = ?", spaceID).
 		Where("id not in (select user_id from vacancy_teams where vacancy_id = ?)", vacancyID).
 		Where("deleted_at IS NULL").
+		Where("is_service = ?", false).
 		Where("status != ?", models.SpaceDismissedStatus) // Исключаем уволенных пользователей
 	if filter.Search != "" {
 		tx = tx.Where("LOWER(first_name|| ' ' || last_name) like ?", "%"+strings.ToLower(filter.Search)+"%")
@@ -0,0 +1,669 @@
+package sso
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"hr-tools-backend/config"
+	"hr-tools-backend/db"
+	auditlog "hr-tools-backend/lib/audit-log"
+	spaceauthhandler "hr-tools-backend/lib/space/auth"
+	spaceusershander "hr-tools-backend/lib/space/users/hander"
+	spaceusersstore "hr-tools-backend/lib/space/users/store"
+	ssooidc "hr-tools-backend/lib/sso/oidc"
+	ssosaml "hr-tools-backend/lib/sso/saml"
+	ssosessionstore "hr-tools-backend/lib/sso/session-store"
+	ssostore "hr-tools-backend/lib/sso/store"
+	authhelpers "hr-tools-backend/lib/utils/auth-helpers"
+	initchecker "hr-tools-backend/lib/utils/init-checker"
+	safehttp "hr-tools-backend/lib/utils/safe-http"
+	"hr-tools-backend/models"
+	authapimodels "hr-tools-backend/models/api/auth"
+	ssoapimodels "hr-tools-backend/models/api/sso"
+	dbmodels "hr-tools-backend/models/db"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	loginCodeExpire = time.Minute
+	defaultGroups   = "groups"
+	loginFailedMsg  = "ошибка входа через корпоративную учетную запись, попробуйте позже"
+)
+
+type Provider interface {
+	GetSettings(spaceID string) (ssoapimodels.SettingsView, error)
+	UpdateSettings(spaceID, userID string, data ssoapimodels.SettingsData) (hMsg string, err error)
+	// Discover способ входа по почте сотрудника
+	Discover(email string) (ssoapimodels.DiscoverResult, error)
+	// Login адрес перехода на IdP для начала входа
+	Login(ctx context.Context, spaceID string) (redirectURL, hMsg string, err error)
+	// OidcCallback обработка возврата из IdP, возвращает адрес страницы входа UI с кодом входа или ошибкой
+	OidcCallback(ctx context.Context, state, code, idpError string) string
+	// SamlAcs обработка ответа IdP, возвращает адрес страницы входа UI с кодом входа или ошибкой
+	SamlAcs(samlResponse, relayState string) string
+	GetSamlMetadata(spaceID string) ([]byte, error)
+	// ExchangeCode получение токенов по одноразовому коду входа
+	ExchangeCode(code string, client authapimodels.ClientInfo) (authapimodels.JWTResponse, error)
+}
+
+var Instance Provider
+
+func NewHandler() {
+	instance := impl{
+		store:           ssostore.NewInstance(db.DB),
+		sessionStore:    ssosessionstore.NewInstance(db.DB),
+		spaceUsersStore: spaceusersstore.NewInstance(db.DB),
+		// адреса IdP указывает администратор спейса, соединения с внутренней сетью запрещены
+		oidcClient: ssooidc.NewClient(safehttp.NewClient(time.Duration(config.Conf.Sso.TimeoutSec) * time.Second)),
+	}
+	initchecker.CheckInit(
+		"store", instance.store,
+		"sessionStore", instance.sessionStore,
+		"spaceUsersStore", instance.spaceUsersStore,
+		"oidcClient", instance.oidcClient,
+	)
+	Instance = instance
+}
+
+type impl struct {
+	store           ssostore.Provider
+	sessionStore    ssosessionstore.Provider
+	spaceUsersStore spaceusersstore.Provider
+	oidcClient      ssooidc.Provider
+}
+
+// identity данные пользователя, полученные от IdP
+type identity struct {
+	Email     string
+	FirstName string
+	LastName  string
+	Groups    []string
+}
+
+func (i impl) getLogger(spaceID string) *log.Entry {
+	return log.WithField("space_id", spaceID)
+}
+
+func (i impl) GetSettings(spaceID string) (ssoapimodels.SettingsView, error) {
+	rec, err := i.store.GetBySpace(spaceID)
+	if err != nil {
+		return ssoapimodels.SettingsView{}, errors.Wrap(err, "ошибка получения настроек входа через IdP")
+	}
+	if rec == nil {
+		rec = &dbmodels.SpaceSso{SpaceID: spaceID}
+	}
+	result := ssoapimodels.SettingsConvert(*rec)
+	result.OidcRedirectUri = getOidcRedirectUri()
+	sp := getServiceProvider(spaceID)
+	result.SamlEntityID = sp.EntityID
+	result.SamlAcsUrl = sp.AcsURL
+	return result, nil
+}
+
+func (i impl) UpdateSettings(spaceID, userID string, data ssoapimodels.SettingsData) (hMsg string, err error) {
+	if data.SamlIdpCertificate != "" {
+		_, err = ssosaml.ParseCertificate(data.SamlIdpCertificate)
+		if err != nil {
+			return err.Error(), nil
+		}
+	}
+	rec, err := i.store.GetBySpace(spaceID)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения настроек входа через IdP")
+	}
+	secret := ""
+	if data.OidcClientSecret != "" {
+		secret, err = authhelpers.EncryptString(config.Conf.Sso.SecretKey, data.OidcClientSecret)
+		if err != nil {
+			return "", errors.Wrap(err, "ошибка шифрования секрета клиента OIDC")
+		}
+	} else if rec != nil {
+		secret = rec.OidcClientSecret
+	}
+	if data.IsEnabled && data.Protocol == dbmodels.SsoProtocolOidc && secret == "" {
+		return "не указан секрет клиента OIDC", nil
+	}
+	if data.Protocol == dbmodels.SsoProtocolOidc && strings.TrimSpace(data.OidcIssuer) != "" {
+		hMsg, err = safehttp.CheckUrl(context.Background(), strings.TrimSpace(data.OidcIssuer))
+		if err != nil {
+			return "", err
+		}
+		if hMsg != "" {
+			return "адрес издателя OIDC: " + hMsg, nil
+		}
+	}
+	for _, domain := range data.GetDomains() {
+		found, err := i.store.FindByDomain(domain)
+		if err != nil {
+			return "", errors.Wrap(err, "ошибка проверки почтового домена")
+		}
+		if found != nil && found.SpaceID != spaceID {
+			return "почтовый домен " + domain + " уже используется другой организацией", nil
+		}
+	}
+
+	before := getAuditData(rec)
+	if rec == nil {
+		rec = &dbmodels.SpaceSso{SpaceID: spaceID}
+		setSettings(rec, data, secret)
+		_, err = i.store.Create(*rec)
+		if err != nil {
+			return "", errors.Wrap(err, "ошибка сохранения настроек входа через IdP")
+		}
+	} else {
+		setSettings(rec, data, secret)
+		updMap := map[string]interface{}{
+			"is_enabled":              rec.IsEnabled,
+			"protocol":                rec.Protocol,
+			"domains":                 rec.Domains,
+			"password_login_disabled": rec.PasswordLoginDisabled,
+			"auto_provision":          rec.AutoProvision,
+			"default_role":            rec.DefaultRole,
+			"group_roles":             rec.GroupRoles,
+			"groups_claim":            rec.GroupsClaim,
+			"oidc_issuer":             rec.OidcIssuer,
+			"oidc_client_id":          rec.OidcClientID,
+			"oidc_client_secret":      rec.OidcClientSecret,
+			"oidc_scopes":             rec.OidcScopes,
+			"saml_idp_entity_id":      rec.SamlIdpEntityID,
+			"saml_idp_sso_url":        rec.SamlIdpSsoUrl,
+			"saml_idp_certificate":    rec.SamlIdpCertificate,
+		}
+		err = i.store.Update(spaceID, updMap)
+		if err != nil {
+			return "", errors.Wrap(err, "ошибка сохранения настроек входа через IdP")
+		}
+	}
+	i.getLogger(spaceID).
+		WithField("is_enabled", data.IsEnabled).
+		WithField("protocol", data.Protocol).
+		Info("изменены настройки входа через IdP")
+	auditlog.Instance.SaveChanges(spaceID, userID, models.AuditActionUpdate, models.AuditEntitySso, spaceID,
+		"Изменены настройки входа через IdP", before, getAuditData(rec))
+	return "", nil
+}
+
+// getAuditData настройки для журнала аудита, секрет клиента маскируется (отмечается только факт изменения)
+func getAuditData(rec *dbmodels.SpaceSso) *ssoapimodels.SettingsData {
+	if rec == nil {
+		return nil
+	}
+	result := ssoapimodels.SettingsConvert(*rec).SettingsData
+	result.OidcClientSecret = rec.OidcClientSecret
+	return &result
+}
+
+func (i impl) Discover(email string) (ssoapimodels.DiscoverResult, error) {
+	_, domain, found := strings.Cut(strings.ToLower(strings.TrimSpace(email)), "@")
+	if !found || domain == "" {
+		return ssoapimodels.DiscoverResult{}, nil
+	}
+	rec, err := i.store.FindByDomain(domain)
+	if err != nil {
+		return ssoapimodels.DiscoverResult{}, errors.Wrap(err, "ошибка получения настроек входа через IdP")
+	}
+	if rec == nil {
+		return ssoapimodels.DiscoverResult{}, nil
+	}
+	return ssoapimodels.DiscoverResult{
+		SsoEnabled:            true,
+		Protocol:              rec.Protocol,
+		LoginUrl:              getLoginUrl(rec.SpaceID),
+		PasswordLoginDisabled: rec.PasswordLoginDisabled,
+	}, nil
+}
+
+func (i impl) Login(ctx context.Context, spaceID string) (redirectURL, hMsg string, err error) {
+	settings, err := i.store.GetBySpace(spaceID)
+	if err != nil {
+		return "", "", errors.Wrap(err, "ошибка получения настроек входа через IdP")
+	}
+	if settings == nil || !settings.IsEnabled {
+		return "", "вход через корпоративную учетную запись не настроен", nil
+	}
+	now := time.Now()
+	err = i.sessionStore.DeleteExpired(now)
+	if err != nil {
+		i.getLogger(spaceID).WithError(err).Warn("ошибка удаления устаревших сессий входа через IdP")
+	}
+	nonce, err := generateCode()
+	if err != nil {
+		return "", "", err
+	}
+	session := dbmodels.SsoSession{
+		BaseSpaceModel: dbmodels.BaseSpaceModel{SpaceID: spaceID},
+		Protocol:       settings.Protocol,
+		Nonce:          nonce,
+		ExpiresAt:      now.Add(time.Duration(config.Conf.Sso.SessionExpireMin) * time.Minute),
+	}
+	switch settings.Protocol {
+	case dbmodels.SsoProtocolOidc:
+		sessionID, err := i.sessionStore.Create(session)
+		if err != nil {
+			return "", "", errors.Wrap(err, "ошибка создания сессии входа через IdP")
+		}
+		cfg, err := getOidcConfig(*settings)
+		if err != nil {
+			return "", "", err
+		}
+		redirectURL, err = i.oidcClient.GetAuthURL(ctx, cfg, getOidcRedirectUri(), sessionID, nonce)
+		if err != nil {
+			i.getLogger(spaceID).WithError(err).Error("ошибка формирования запроса аутентификации OIDC")
+			return "", "ошибка подключения к IdP, обратитесь к администратору", nil
+		}
+	case dbmodels.SsoProtocolSaml:
+		idp, err := getIdentityProvider(*settings)
+		if err != nil {
+			return "", "", err
+		}
+		session.RequestID = ssosaml.NewRequestID()
+		sessionID, err := i.sessionStore.Create(session)
+		if err != nil {
+			return "", "", errors.Wrap(err, "ошибка создания сессии входа через IdP")
+		}
+		redirectURL, err = ssosaml.GetAuthnRequestURL(getServiceProvider(spaceID), idp, session.RequestID, sessionID, now)
+		if err != nil {
+			return "", "", errors.Wrap(err, "ошибка формирования запроса аутентификации SAML")
+		}
+	default:
+		return "", "вход через корпоративную учетную запись не настроен", nil
+	}
+	return redirectURL, "", nil
+}
+
+func (i impl) OidcCallback(ctx context.Context, state, code, idpError string) string {
+	session, settings, hMsg, err := i.takeSession(state, dbmodels.SsoProtocolOidc)
+	if err != nil || hMsg != "" {
+		return getLoginUIErrorUrl(hMsg, err)
+	}
+	logger := i.getLogger(session.SpaceID)
+	if idpError != "" || code == "" {
+		logger.WithField("idp_error", idpError).Warn("IdP отклонил вход")
+		return getLoginUIErrorUrl("вход отклонен корпоративной учетной записью", nil)
+	}
+	cfg, err := getOidcConfig(*settings)
+	if err != nil {
+		return getLoginUIErrorUrl("", err)
+	}
+	claims, err := i.oidcClient.Exchange(ctx, cfg, getOidcRedirectUri(), code, session.Nonce)
+	if err != nil {
+		logger.WithError(err).Error("ошибка проверки входа OIDC")
+		return getLoginUIErrorUrl("не удалось подтвердить вход в IdP, обратитесь к администратору", nil)
+	}
+	user, hMsg := getOidcIdentity(claims, settings.GroupsClaim)
+	if hMsg != "" {
+		return getLoginUIErrorUrl(hMsg, nil)
+	}
+	return i.finishLogin(*settings, user)
+}
+
+func (i impl) SamlAcs(samlResponse, relayState string) string {
+	session, settings, hMsg, err := i.takeSession(relayState, dbmodels.SsoProtocolSaml)
+	if err != nil || hMsg != "" {
+		return getLoginUIErrorUrl(hMsg, err)
+	}
+	logger := i.getLogger(session.SpaceID)
+	idp, err := getIdentityProvider(*settings)
+	if err != nil {
+		return getLoginUIErrorUrl("", err)
+	}
+	assertion, err := ssosaml.ParseResponse(samlResponse, getServiceProvider(session.SpaceID), idp, session.RequestID, time.Now())
+	if err != nil {
+		logger.WithError(err).Error("ошибка проверки ответа SAML")
+		return getLoginUIErrorUrl("не удалось подтвердить вход в IdP, обратитесь к администратору", nil)
+	}
+	user, hMsg := getSamlIdentity(*assertion, settings.GroupsClaim)
+	if hMsg != "" {
+		return getLoginUIErrorUrl(hMsg, nil)
+	}
+	return i.finishLogin(*settings, user)
+}
+
+func (i impl) GetSamlMetadata(spaceID string) ([]byte, error) {
+	settings, err := i.store.GetBySpace(spaceID)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения настроек входа через IdP")
+	}
+	if settings == nil {
+		return nil, nil
+	}
+	return ssosaml.GetMetadata(getServiceProvider(spaceID)), nil
+}
+
+func (i impl) ExchangeCode(code string, client authapimodels.ClientInfo) (authapimodels.JWTResponse, error) {
+	session, err := i.sessionStore.GetByLoginCode(code)
+	if err != nil {
+		return authapimodels.JWTResponse{}, errors.Wrap(err, "ошибка получения кода входа")
+	}
+	if session == nil || session.UserID == "" || session.ExpiresAt.Before(time.Now()) {
+		return authapimodels.JWTResponse{}, errors.New("код входа не найден или более не актуален")
+	}
+	consumed, err := i.sessionStore.Consume(session.ID)
+	if err != nil {
+		return authapimodels.JWTResponse{}, errors.Wrap(err, "ошибка удаления кода входа")
+	}
+	if !consumed {
+		return authapimodels.JWTResponse{}, errors.New("код входа уже использован")
+	}
+	user, err := i.spaceUsersStore.GetByID(session.UserID)
+	if err != nil {
+		return authapimodels.JWTResponse{}, errors.Wrap(err, "ошибка поиска пользователя")
+	}
+	if user == nil {
+		return authapimodels.JWTResponse{}, errors.New("пользователь не найден")
+	}
+	if !user.IsActive {
+		return authapimodels.JWTResponse{}, errors.New("учетная запись деактивирована")
+	}
+	// второй фактор при входе через IdP проверяет сам IdP
+	response, err := spaceauthhandler.Instance.IssueTokens(*user, client)
+	if err != nil {
+		return authapimodels.JWTResponse{}, err
+	}
+	i.getLogger(user.SpaceID).WithField("user_id", user.ID).Info("вход через IdP")
+	return response, nil
+}
+
+// takeSession получение и удаление сессии входа, сессия используется однократно
+func (i impl) takeSession(id string, protocol dbmodels.SsoProtocol) (*dbmodels.SsoSession, *dbmodels.SpaceSso, string, error) {
+	const expiredMsg = "время входа истекло, повторите вход"
+	if id == "" {
+		return nil, nil, expiredMsg, nil
+	}
+	session, err := i.sessionStore.GetByID(id)
+	if err != nil {
+		return nil, nil, "", errors.Wrap(err, "ошибка получения сессии входа через IdP")
+	}
+	if session == nil || session.UserID != "" || session.Protocol != protocol || session.ExpiresAt.Before(time.Now()) {
+		return nil, nil, expiredMsg, nil
+	}
+	consumed, err := i.sessionStore.Consume(session.ID)
+	if err != nil {
+		return nil, nil, "", errors.Wrap(err, "ошибка удаления сессии входа через IdP")
+	}
+	if !consumed {
+		return nil, nil, expiredMsg, nil
+	}
+	settings, err := i.store.GetBySpace(session.SpaceID)
+	if err != nil {
+		return nil, nil, "", errors.Wrap(err, "ошибка получения настроек входа через IdP")
+	}
+	if settings == nil || !settings.IsEnabled || settings.Protocol != protocol {
+		return nil, nil, "вход через корпоративную учетную запись отключен", nil
+	}
+	return session, settings, "", nil
+}
+
+// finishLogin поиск или создание пользователя и выдача одноразового кода входа
+func (i impl) finishLogin(settings dbmodels.SpaceSso, data identity) string {
+	logger := i.getLogger(settings.SpaceID).WithField("email", data.Email)
+	user, hMsg, err := i.provisionUser(settings, data)
+	if err != nil {
+		logger.WithError(err).Error("ошибка создания пользователя при входе через IdP")
+		return getLoginUIErrorUrl("", err)
+	}
+	if hMsg != "" {
+		logger.WithField("reason", hMsg).Warn("вход через IdP отклонен")
+		return getLoginUIErrorUrl(hMsg, nil)
+	}
+	code, err := generateCode()
+	if err != nil {
+		return getLoginUIErrorUrl("", err)
+	}
+	session := dbmodels.SsoSession{
+		BaseSpaceModel: dbmodels.BaseSpaceModel{SpaceID: settings.SpaceID},
+		Protocol:       settings.Protocol,
+		UserID:         user.ID,
+		LoginCode:      code,
+		ExpiresAt:      time.Now().Add(loginCodeExpire),
+	}
+	_, err = i.sessionStore.Create(session)
+	if err != nil {
+		logger.WithError(err).Error("ошибка сохранения кода входа через IdP")
+		return getLoginUIErrorUrl("", err)
+	}
+	return getLoginUIUrl(url.Values{"code": []string{code}})
+}
+
+// provisionUser пользователь спейса по почте из IdP, при необходимости создается (JIT) или обновляется роль
+func (i impl) provisionUser(settings dbmodels.SpaceSso, data identity) (*dbmodels.SpaceUser, string, error) {
+	if !settings.IsDomainAllowed(data.Email) {
+		return nil, "почтовый домен пользователя не относится к организации", nil
+	}
+	role, mapped := settings.GetRole(data.Groups)
+	user, err := i.spaceUsersStore.FindByEmail(data.Email, false)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "ошибка поиска пользователя по почте")
+	}
+	if user != nil {
+		if user.SpaceID != settings.SpaceID || user.IsService {
+			return nil, "пользователь с такой почтой зарегистрирован в другой организации", nil
+		}
+		if !user.IsActive {
+			return nil, "учетная запись деактивирована", nil
+		}
+		if mapped && user.Role != role {
+			// роль пользователя определяется группами IdP
+			err = i.spaceUsersStore.Update(user.ID, map[string]interface{}{"role": role})
+			if err != nil {
+				return nil, "", errors.Wrap(err, "ошибка изменения роли пользователя")
+			}
+			user.Role = role
+		}
+		return user, "", nil
+	}
+	if !settings.AutoProvision {
+		return nil, "пользователь не найден, обратитесь к администратору", nil
+	}
+	if role == "" {
+		return nil, "группы пользователя в IdP не сопоставлены с ролями, обратитесь к администратору", nil
+	}
+	rec := dbmodels.SpaceUser{
+		FirstName:       data.FirstName,
+		LastName:        data.LastName,
+		Email:           data.Email,
+		IsActive:        true,
+		SpaceID:         settings.SpaceID,
+		Role:            role,
+		IsEmailVerified: true,
+		Status:          models.SpaceWorkingStatus,
+	}
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		rec.ID, err = spaceusersstore.NewInstance(tx).Create(rec)
+		if err != nil {
+			return errors.Wrap(err, "ошибка создания пользователя")
+		}
+		err = spaceusershander.Instance.CreatePushSettings(tx, rec.SpaceID, rec.ID)
+		if err != nil {
+			return errors.Wrap(err, "ошибка создания списка настроек пушей для пользователя")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	i.getLogger(settings.SpaceID).
+		WithField("user_id", rec.ID).
+		WithField("role", role).
+		Info("создан пользователь при входе через IdP")
+	return &rec, "", nil
+}
+
+func setSettings(rec *dbmodels.SpaceSso, data ssoapimodels.SettingsData, secret string) {
+	rec.IsEnabled = data.IsEnabled
+	rec.Protocol = data.Protocol
+	rec.Domains = data.GetDomains()
+	rec.PasswordLoginDisabled = data.PasswordLoginDisabled
+	rec.AutoProvision = data.AutoProvision
+	rec.DefaultRole = data.DefaultRole
+	rec.GroupRoles = data.GroupRoles
+	if rec.GroupRoles == nil {
+		rec.GroupRoles = dbmodels.SsoGroupRoles{}
+	}
+	rec.GroupsClaim = strings.TrimSpace(data.GroupsClaim)
+	rec.OidcIssuer = strings.TrimSpace(data.OidcIssuer)
+	rec.OidcClientID = strings.TrimSpace(data.OidcClientID)
+	rec.OidcClientSecret = secret
+	rec.OidcScopes = data.OidcScopes
+	rec.SamlIdpEntityID = strings.TrimSpace(data.SamlIdpEntityID)
+	rec.SamlIdpSsoUrl = strings.TrimSpace(data.SamlIdpSsoUrl)
+	rec.SamlIdpCertificate = strings.TrimSpace(data.SamlIdpCertificate)
+}
+
+func getOidcConfig(settings dbmodels.SpaceSso) (ssooidc.Config, error) {
+	secret, err := authhelpers.DecryptString(config.Conf.Sso.SecretKey, settings.OidcClientSecret)
+	if err != nil {
+		return ssooidc.Config{}, errors.Wrap(err, "ошибка расшифровки секрета клиента OIDC")
+	}
+	return ssooidc.Config{
+		Issuer:       settings.OidcIssuer,
+		ClientID:     settings.OidcClientID,
+		ClientSecret: secret,
+		Scopes:       settings.OidcScopes,
+	}, nil
+}
+
+func getIdentityProvider(settings dbmodels.SpaceSso) (ssosaml.IdentityProvider, error) {
+	cert, err := ssosaml.ParseCertificate(settings.SamlIdpCertificate)
+	if err != nil {
+		return ssosaml.IdentityProvider{}, err
+	}
+	return ssosaml.IdentityProvider{
+		EntityID:    settings.SamlIdpEntityID,
+		SsoURL:      settings.SamlIdpSsoUrl,
+		Certificate: cert,
+	}, nil
+}
+
+func getApiUrl(path string) string {
+	return strings.TrimSuffix(config.Conf.Sso.ApiHost, "/") + "/api/v1/sso" + path
+}
+
+func getLoginUrl(spaceID string) string {
+	return getApiUrl("/" + spaceID + "/login")
+}
+
+func getOidcRedirectUri() string {
+	return getApiUrl("/oidc/callback")
+}
+
+func getServiceProvider(spaceID string) ssosaml.ServiceProvider {
+	return ssosaml.ServiceProvider{
+		EntityID: getApiUrl("/" + spaceID + "/metadata"),
+		AcsURL:   getApiUrl("/saml/acs"),
+	}
+}
+
+func getLoginUIUrl(query url.Values) string {
+	return config.Conf.Sso.LoginUI + "?" + query.Encode()
+}
+
+func getLoginUIErrorUrl(hMsg string, err error) string {
+	if err != nil {
+		log.WithError(err).Error("ошибка входа через IdP")
+	}
+	if hMsg == "" {
+		hMsg = loginFailedMsg
+	}
+	return getLoginUIUrl(url.Values{"error": []string{hMsg}})
+}
+
+func getOidcIdentity(claims jwt.MapClaims, groupsClaim string) (identity, string) {
+	result := identity{
+		Email:     getClaimString(claims, "email"),
+		FirstName: getClaimString(claims, "given_name"),
+		LastName:  getClaimString(claims, "family_name"),
+	}
+	if result.Email == "" {
+		// Azure AD передает почту в preferred_username, если claim email не настроен
+		if username := getClaimString(claims, "preferred_username"); strings.Contains(username, "@") {
+			result.Email = username
+		}
+	}
+	if verified, ok := claims["email_verified"].(bool); ok && !verified {
+		return identity{}, "почта пользователя не подтверждена в IdP"
+	}
+	if result.FirstName == "" && result.LastName == "" {
+		result.FirstName, result.LastName = splitName(getClaimString(claims, "name"))
+	}
+	if groupsClaim == "" {
+		groupsClaim = defaultGroups
+	}
+	switch value := claims[groupsClaim].(type) {
+	case []interface{}:
+		for _, group := range value {
+			if s, ok := group.(string); ok {
+				result.Groups = append(result.Groups, s)
+			}
+		}
+	case string:
+		result.Groups = []string{value}
+	}
+	return normalizeIdentity(result)
+}
+
+var (
+	samlEmailAttributes     = []string{"email", "mail", "http://schemas.xmlsoap.org/ws/2005/05/identity/claims/emailaddress", "urn:oid:0.9.2342.19200300.100.1.3"}
+	samlFirstNameAttributes = []string{"firstName", "givenName", "given_name", "http://schemas.xmlsoap.org/ws/2005/05/identity/claims/givenname", "urn:oid:2.5.4.42"}
+	samlLastNameAttributes  = []string{"lastName", "sn", "surname", "family_name", "http://schemas.xmlsoap.org/ws/2005/05/identity/claims/surname", "urn:oid:2.5.4.4"}
+	samlGroupsAttributes    = []string{defaultGroups, "Group", "memberOf", "http://schemas.microsoft.com/ws/2008/06/identity/claims/groups", "http://schemas.xmlsoap.org/claims/Group"}
+)
+
+func getSamlIdentity(assertion ssosaml.Assertion, groupsClaim string) (identity, string) {
+	result := identity{
+		Email:     assertion.GetAttribute(samlEmailAttributes...),
+		FirstName: assertion.GetAttribute(samlFirstNameAttributes...),
+		LastName:  assertion.GetAttribute(samlLastNameAttributes...),
+	}
+	if strings.Contains(assertion.NameID, "@") {
+		result.Email = assertion.NameID
+	}
+	groupsAttributes := samlGroupsAttributes
+	if groupsClaim != "" {
+		groupsAttributes = []string{groupsClaim}
+	}
+	for _, name := range groupsAttributes {
+		if values := assertion.Attributes[name]; len(values) != 0 {
+			result.Groups = values
+			break
+		}
+	}
+	return normalizeIdentity(result)
+}
+
+func normalizeIdentity(data identity) (identity, string) {
+	data.Email = strings.ToLower(strings.TrimSpace(data.Email))
+	if data.Email == "" {
+		return identity{}, "IdP не передал почту пользователя, обратитесь к администратору"
+	}
+	if data.FirstName == "" && data.LastName == "" {
+		data.FirstName, _, _ = strings.Cut(data.Email, "@")
+	}
+	return data, ""
+}
+
+func getClaimString(claims jwt.MapClaims, name string) string {
+	value, _ := claims[name].(string)
+	return strings.TrimSpace(value)
+}
+
+func splitName(name string) (firstName, lastName string) {
+	firstName, lastName, _ = strings.Cut(strings.TrimSpace(name), " ")
+	return firstName, strings.TrimSpace(lastName)
+}
+
+func generateCode() (string, error) {
+	buf := make([]byte, 32)
+	_, err := rand.Read(buf)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка генерации кода")
+	}
+	return hex.EncodeToString(buf), nil
+}
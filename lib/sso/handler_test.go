@@ -0,0 +1,44 @@
+package sso
+
+import (
+	spaceusersstore "hr-tools-backend/lib/space/users/store"
+	"hr-tools-backend/models"
+	dbmodels "hr-tools-backend/models/db"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeUsersStore struct {
+	spaceusersstore.Provider
+	user *dbmodels.SpaceUser
+}
+
+func (s fakeUsersStore) FindByEmail(email string, checkNew bool) (*dbmodels.SpaceUser, error) {
+	return s.user, nil
+}
+
+func TestProvisionUserDomain(t *testing.T) {
+	settings := dbmodels.SpaceSso{
+		SpaceID:       "space",
+		Domains:       pq.StringArray{"example.com"},
+		AutoProvision: true,
+		DefaultRole:   models.ManagerRole,
+	}
+	user := &dbmodels.SpaceUser{SpaceID: "space", IsActive: true}
+	i := impl{spaceUsersStore: fakeUsersStore{user: user}}
+
+	// почта вне доменов спейса отклоняется, даже если пользователь с такой почтой уже есть в спейсе
+	for _, email := range []string{"ivan@other.com", "ivan@example.com.other.com", "ivan@sub.example.com", "ivan"} {
+		rec, hMsg, err := i.provisionUser(settings, identity{Email: email})
+		require.NoError(t, err)
+		require.NotEmpty(t, hMsg, email)
+		require.Nil(t, rec)
+	}
+
+	rec, hMsg, err := i.provisionUser(settings, identity{Email: "Ivan@Example.com"})
+	require.NoError(t, err)
+	require.Empty(t, hMsg)
+	require.Equal(t, user, rec)
+}
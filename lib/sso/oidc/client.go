@@ -0,0 +1,224 @@
+package ssooidc
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/MicahParks/keyfunc/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/patrickmn/go-cache"
+	"github.com/pkg/errors"
+)
+
+const (
+	discoveryPath = "/.well-known/openid-configuration"
+	// метаданные и ключи IdP кешируются, чтобы не запрашивать их при каждом входе
+	discoveryCacheTTL = time.Hour
+	maxResponseSize   = 1 << 20
+)
+
+type Provider interface {
+	// GetAuthURL адрес перехода на IdP (authorization code flow)
+	GetAuthURL(ctx context.Context, cfg Config, redirectURI, state, nonce string) (string, error)
+	// Exchange обмен кода авторизации на ID токен и проверка токена
+	Exchange(ctx context.Context, cfg Config, redirectURI, code, nonce string) (jwt.MapClaims, error)
+}
+
+// Config параметры IdP из настроек спейса
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string // пусто - openid email profile
+}
+
+// Metadata метаданные OpenID провайдера
+type Metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JwksURI               string `json:"jwks_uri"`
+}
+
+type tokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func NewClient(client *http.Client) Provider {
+	return &impl{
+		client: client,
+		cache:  cache.New(discoveryCacheTTL, discoveryCacheTTL),
+	}
+}
+
+type impl struct {
+	client *http.Client
+	cache  *cache.Cache
+}
+
+func (i *impl) GetAuthURL(ctx context.Context, cfg Config, redirectURI, state, nonce string) (string, error) {
+	metadata, err := i.getMetadata(ctx, cfg.Issuer)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(metadata.AuthorizationEndpoint)
+	if err != nil {
+		return "", errors.Wrap(err, "некорректный адрес авторизации IdP")
+	}
+	scopes := cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"openid", "email", "profile"}
+	}
+	query := u.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", cfg.ClientID)
+	query.Set("redirect_uri", redirectURI)
+	query.Set("scope", strings.Join(scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+func (i *impl) Exchange(ctx context.Context, cfg Config, redirectURI, code, nonce string) (jwt.MapClaims, error) {
+	metadata, err := i.getMetadata(ctx, cfg.Issuer)
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", redirectURI)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка формирования запроса токена")
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(cfg.ClientID), url.QueryEscape(cfg.ClientSecret))
+
+	var token tokenResponse
+	statusCode, err := i.doJSON(req, &token)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения токена IdP")
+	}
+	if statusCode != http.StatusOK || token.Error != "" {
+		return nil, errors.Errorf("IdP отклонил запрос токена (%v): %v %v", statusCode, token.Error, token.ErrorDescription)
+	}
+	if token.IDToken == "" {
+		return nil, errors.New("IdP не вернул ID токен")
+	}
+	return i.verifyIDToken(ctx, cfg, metadata, token.IDToken, nonce)
+}
+
+func (i *impl) verifyIDToken(ctx context.Context, cfg Config, metadata Metadata, idToken, nonce string) (jwt.MapClaims, error) {
+	jwks, err := i.getJWKS(ctx, metadata.JwksURI)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := parseIDToken(idToken, jwks, metadata.Issuer, cfg.ClientID)
+	if err != nil && errors.Is(err, jwt.ErrTokenUnverifiable) {
+		// ключи IdP могли смениться, повторная проверка по свежему набору ключей
+		i.cache.Delete(metadata.JwksURI)
+		jwks, err = i.getJWKS(ctx, metadata.JwksURI)
+		if err != nil {
+			return nil, err
+		}
+		claims, err = parseIDToken(idToken, jwks, metadata.Issuer, cfg.ClientID)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "ID токен не прошел проверку")
+	}
+	if claims["nonce"] != nonce {
+		return nil, errors.New("ID токен выпущен для другого запроса аутентификации")
+	}
+	return claims, nil
+}
+
+func parseIDToken(idToken string, jwks *keyfunc.JWKS, issuer, clientID string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(idToken, claims, jwks.Keyfunc,
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512", "PS256"}),
+		jwt.WithIssuer(issuer),
+		jwt.WithAudience(clientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	return claims, err
+}
+
+func (i *impl) getMetadata(ctx context.Context, issuer string) (Metadata, error) {
+	issuer = strings.TrimSuffix(issuer, "/")
+	if value, found := i.cache.Get(issuer); found {
+		return value.(Metadata), nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, issuer+discoveryPath, nil)
+	if err != nil {
+		return Metadata{}, errors.Wrap(err, "некорректный адрес IdP")
+	}
+	var metadata Metadata
+	code, err := i.doJSON(req, &metadata)
+	if err != nil {
+		return Metadata{}, errors.Wrap(err, "ошибка получения метаданных IdP")
+	}
+	if code != http.StatusOK {
+		return Metadata{}, errors.Errorf("ошибка получения метаданных IdP, код ответа: %v", code)
+	}
+	// издатель в метаданных должен совпадать с настроенным (OpenID Connect Discovery 1.0, п. 4.3)
+	if strings.TrimSuffix(metadata.Issuer, "/") != issuer {
+		return Metadata{}, errors.Errorf("издатель в метаданных IdP не совпадает с настройками: %v", metadata.Issuer)
+	}
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JwksURI == "" {
+		return Metadata{}, errors.New("метаданные IdP не содержат адреса авторизации, токена или ключей")
+	}
+	i.cache.SetDefault(issuer, metadata)
+	return metadata, nil
+}
+
+func (i *impl) getJWKS(ctx context.Context, jwksURI string) (*keyfunc.JWKS, error) {
+	if value, found := i.cache.Get(jwksURI); found {
+		return value.(*keyfunc.JWKS), nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURI, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "некорректный адрес ключей IdP")
+	}
+	var raw json.RawMessage
+	code, err := i.doJSON(req, &raw)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения ключей IdP")
+	}
+	if code != http.StatusOK {
+		return nil, errors.Errorf("ошибка получения ключей IdP, код ответа: %v", code)
+	}
+	jwks, err := keyfunc.NewJSON(raw)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка разбора ключей IdP")
+	}
+	i.cache.SetDefault(jwksURI, jwks)
+	return jwks, nil
+}
+
+func (i *impl) doJSON(req *http.Request, result interface{}) (int, error) {
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return resp.StatusCode, err
+	}
+	err = json.Unmarshal(body, result)
+	if err != nil && resp.StatusCode == http.StatusOK {
+		return resp.StatusCode, errors.Wrap(err, "некорректный ответ IdP")
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,216 @@
+package ssooidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	safehttp "hr-tools-backend/lib/utils/safe-http"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testClientID     = "hr-tools"
+	testClientSecret = "secret:value"
+	testRedirectURI  = "https://api.example.com/api/v1/sso/oidc/callback"
+	testCode         = "auth-code"
+	testNonce        = "nonce-1"
+)
+
+// mockIdP локальный OpenID провайдер: discovery, ключи и выдача ID токена по коду
+type mockIdP struct {
+	server   *httptest.Server
+	mu       sync.Mutex
+	key      *rsa.PrivateKey
+	kid      string
+	claims   jwt.MapClaims
+	jwksHits int
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	m := &mockIdP{}
+	m.rotateKey(t, "key-1")
+	mux := http.NewServeMux()
+	mux.HandleFunc(discoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, Metadata{
+			Issuer:                m.server.URL,
+			AuthorizationEndpoint: m.server.URL + "/auth",
+			TokenEndpoint:         m.server.URL + "/token",
+			JwksURI:               m.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.jwksHits++
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"use": "sig",
+				"alg": "RS256",
+				"kid": m.kid,
+				"n":   base64.RawURLEncoding.EncodeToString(m.key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(m.key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		clientID, clientSecret, ok := r.BasicAuth()
+		if !ok || clientID != testClientID || clientSecret != url.QueryEscape(testClientSecret) {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+			return
+		}
+		if r.PostFormValue("grant_type") != "authorization_code" || r.PostFormValue("code") != testCode ||
+			r.PostFormValue("redirect_uri") != testRedirectURI {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+			return
+		}
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, m.claims)
+		token.Header["kid"] = m.kid
+		idToken, err := token.SignedString(m.key)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"id_token": idToken, "token_type": "Bearer"})
+	})
+	m.server = httptest.NewServer(mux)
+	t.Cleanup(m.server.Close)
+	m.setClaims(nil)
+	return m
+}
+
+func (m *mockIdP) rotateKey(t *testing.T, kid string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.key = key
+	m.kid = kid
+}
+
+// setClaims утверждения ID токена, override заменяет значения по умолчанию
+func (m *mockIdP) setClaims(override jwt.MapClaims) {
+	claims := jwt.MapClaims{
+		"iss":    m.server.URL,
+		"sub":    "user-1",
+		"aud":    testClientID,
+		"exp":    time.Now().Add(5 * time.Minute).Unix(),
+		"iat":    time.Now().Unix(),
+		"nonce":  testNonce,
+		"email":  "ivan.petrov@example.com",
+		"groups": []string{"hr-team"},
+	}
+	for k, v := range override {
+		claims[k] = v
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.claims = claims
+}
+
+func (m *mockIdP) config() Config {
+	return Config{
+		Issuer:       m.server.URL + "/",
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func TestGetAuthURL(t *testing.T) {
+	idp := newMockIdP(t)
+	client := NewClient(idp.server.Client())
+	authURL, err := client.GetAuthURL(context.Background(), idp.config(), testRedirectURI, "state-1", testNonce)
+	require.NoError(t, err)
+
+	u, err := url.Parse(authURL)
+	require.NoError(t, err)
+	require.Equal(t, idp.server.URL+"/auth", u.Scheme+"://"+u.Host+u.Path)
+	query := u.Query()
+	require.Equal(t, "code", query.Get("response_type"))
+	require.Equal(t, testClientID, query.Get("client_id"))
+	require.Equal(t, testRedirectURI, query.Get("redirect_uri"))
+	require.Equal(t, "openid email profile", query.Get("scope"))
+	require.Equal(t, "state-1", query.Get("state"))
+	require.Equal(t, testNonce, query.Get("nonce"))
+
+	t.Run("internal address", func(t *testing.T) {
+		_, err := NewClient(safehttp.NewClient(time.Second)).GetAuthURL(context.Background(), idp.config(), testRedirectURI, "state-1", testNonce)
+		require.ErrorIs(t, err, safehttp.ErrDeniedAddress)
+	})
+
+	t.Run("issuer mismatch", func(t *testing.T) {
+		cfg := idp.config()
+		cfg.Issuer = idp.server.URL + "/realms/other"
+		_, err := NewClient(idp.server.Client()).GetAuthURL(context.Background(), cfg, testRedirectURI, "state-1", testNonce)
+		require.Error(t, err)
+	})
+}
+
+func TestExchange(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		idp := newMockIdP(t)
+		claims, err := NewClient(idp.server.Client()).Exchange(ctx, idp.config(), testRedirectURI, testCode, testNonce)
+		require.NoError(t, err)
+		require.Equal(t, "ivan.petrov@example.com", claims["email"])
+		require.Equal(t, []interface{}{"hr-team"}, claims["groups"])
+	})
+
+	t.Run("invalid code", func(t *testing.T) {
+		idp := newMockIdP(t)
+		_, err := NewClient(idp.server.Client()).Exchange(ctx, idp.config(), testRedirectURI, "other-code", testNonce)
+		require.ErrorContains(t, err, "invalid_grant")
+	})
+
+	t.Run("other nonce", func(t *testing.T) {
+		idp := newMockIdP(t)
+		_, err := NewClient(idp.server.Client()).Exchange(ctx, idp.config(), testRedirectURI, testCode, "nonce-2")
+		require.ErrorContains(t, err, "другого запроса")
+	})
+
+	t.Run("other audience", func(t *testing.T) {
+		idp := newMockIdP(t)
+		idp.setClaims(jwt.MapClaims{"aud": "other-client"})
+		_, err := NewClient(idp.server.Client()).Exchange(ctx, idp.config(), testRedirectURI, testCode, testNonce)
+		require.ErrorContains(t, err, "не прошел проверку")
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		idp := newMockIdP(t)
+		idp.setClaims(jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()})
+		_, err := NewClient(idp.server.Client()).Exchange(ctx, idp.config(), testRedirectURI, testCode, testNonce)
+		require.ErrorContains(t, err, "не прошел проверку")
+	})
+
+	t.Run("key rotation", func(t *testing.T) {
+		idp := newMockIdP(t)
+		client := NewClient(idp.server.Client())
+		_, err := client.Exchange(ctx, idp.config(), testRedirectURI, testCode, testNonce)
+		require.NoError(t, err)
+
+		idp.rotateKey(t, "key-2")
+		_, err = client.Exchange(ctx, idp.config(), testRedirectURI, testCode, testNonce)
+		require.NoError(t, err)
+		require.Equal(t, 2, idp.jwksHits)
+	})
+}
@@ -0,0 +1,315 @@
+package ssosaml
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	nsProtocol  = "urn:oasis:names:tc:SAML:2.0:protocol"
+	nsAssertion = "urn:oasis:names:tc:SAML:2.0:assertion"
+	nsMetadata  = "urn:oasis:names:tc:SAML:2.0:metadata"
+
+	bindingPost   = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+	statusSuccess = "urn:oasis:names:tc:SAML:2.0:status:Success"
+	nameIDEmail   = "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress"
+	timeLayout    = "2006-01-02T15:04:05Z"
+
+	// допустимое расхождение часов SP и IdP
+	clockSkew = 3 * time.Minute
+)
+
+// ServiceProvider параметры SP спейса
+type ServiceProvider struct {
+	EntityID string // идентификатор SP (адрес метаданных)
+	AcsURL   string // адрес приема ответа IdP (HTTP-POST)
+}
+
+// IdentityProvider параметры IdP из настроек спейса
+type IdentityProvider struct {
+	EntityID    string // пусто - издатель ответа не проверяется
+	SsoURL      string
+	Certificate *x509.Certificate
+}
+
+// Assertion проверенные данные пользователя из ответа IdP
+type Assertion struct {
+	NameID     string
+	Attributes map[string][]string
+}
+
+// GetAttribute первое значение атрибута по одному из имен
+func (a Assertion) GetAttribute(names ...string) string {
+	for _, name := range names {
+		if values := a.Attributes[name]; len(values) != 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+// ParseCertificate сертификат IdP в формате PEM или base64 DER (как в метаданных IdP)
+func ParseCertificate(value string) (*x509.Certificate, error) {
+	value = strings.TrimSpace(value)
+	var der []byte
+	if block, _ := pem.Decode([]byte(value)); block != nil {
+		der = block.Bytes
+	} else {
+		var err error
+		der, err = decodeBase64(value)
+		if err != nil {
+			return nil, errors.New("сертификат IdP должен быть в формате PEM или base64")
+		}
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка разбора сертификата IdP")
+	}
+	return cert, nil
+}
+
+// NewRequestID идентификатор запроса аутентификации, ответ IdP должен ссылаться на него в InResponseTo
+func NewRequestID() string {
+	// ID должен начинаться с буквы или подчеркивания (xsd:ID)
+	return "_" + uuid.New().String()
+}
+
+// GetAuthnRequestURL адрес перехода на IdP с запросом аутентификации (HTTP-Redirect binding)
+func GetAuthnRequestURL(sp ServiceProvider, idp IdentityProvider, requestID, relayState string, now time.Time) (string, error) {
+	request := fmt.Sprintf(`<samlp:AuthnRequest xmlns:samlp="%v" xmlns:saml="%v" ID="%v" Version="2.0" IssueInstant="%v" Destination="%v" ProtocolBinding="%v" AssertionConsumerServiceURL="%v"><saml:Issuer>%v</saml:Issuer><samlp:NameIDPolicy Format="%v" AllowCreate="true"/></samlp:AuthnRequest>`,
+		nsProtocol, nsAssertion, requestID, now.UTC().Format(timeLayout), escapeAttr(idp.SsoURL), bindingPost, escapeAttr(sp.AcsURL), escapeText(sp.EntityID), nameIDEmail)
+
+	var buf bytes.Buffer
+	writer, err := flate.NewWriter(&buf, flate.BestCompression)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка сжатия запроса SAML")
+	}
+	_, err = writer.Write([]byte(request))
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка сжатия запроса SAML")
+	}
+	err = writer.Close()
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка сжатия запроса SAML")
+	}
+
+	u, err := url.Parse(idp.SsoURL)
+	if err != nil {
+		return "", errors.Wrap(err, "некорректный адрес IdP")
+	}
+	query := u.Query()
+	query.Set("SAMLRequest", base64.StdEncoding.EncodeToString(buf.Bytes()))
+	query.Set("RelayState", relayState)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// GetMetadata метаданные SP для регистрации в IdP
+func GetMetadata(sp ServiceProvider) []byte {
+	return []byte(fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<md:EntityDescriptor xmlns:md="%v" entityID="%v">
+  <md:SPSSODescriptor AuthnRequestsSigned="false" WantAssertionsSigned="true" protocolSupportEnumeration="%v">
+    <md:NameIDFormat>%v</md:NameIDFormat>
+    <md:AssertionConsumerService Binding="%v" Location="%v" index="0" isDefault="true"/>
+  </md:SPSSODescriptor>
+</md:EntityDescriptor>
+`, nsMetadata, escapeAttr(sp.EntityID), nsProtocol, nameIDEmail, bindingPost, escapeAttr(sp.AcsURL)))
+}
+
+// ParseResponse проверка ответа IdP (SAMLResponse из HTTP-POST) и получение данных пользователя.
+// Должен быть подписан ответ целиком или утверждение, данные берутся только из подписанного элемента
+func ParseResponse(samlResponse string, sp ServiceProvider, idp IdentityProvider, requestID string, now time.Time) (*Assertion, error) {
+	data, err := decodeBase64(samlResponse)
+	if err != nil {
+		return nil, errors.New("некорректный SAMLResponse")
+	}
+	response, err := parseXML(data)
+	if err != nil {
+		return nil, err
+	}
+	if !response.Is(nsProtocol, "Response") {
+		return nil, errors.New("SAMLResponse не содержит Response")
+	}
+	if destination := response.Attr("Destination"); destination != "" && destination != sp.AcsURL {
+		return nil, errors.Errorf("ответ IdP предназначен для другого адреса: %v", destination)
+	}
+	if inResponseTo := response.Attr("InResponseTo"); inResponseTo != "" && inResponseTo != requestID {
+		return nil, errors.New("ответ IdP не соответствует запросу аутентификации")
+	}
+	status := response.Child(nsProtocol, "Status")
+	if status == nil {
+		return nil, errors.New("ответ IdP не содержит статус")
+	}
+	statusCode := status.Child(nsProtocol, "StatusCode")
+	if statusCode == nil || statusCode.Attr("Value") != statusSuccess {
+		message := ""
+		if statusMessage := status.Child(nsProtocol, "StatusMessage"); statusMessage != nil {
+			message = statusMessage.Text()
+		}
+		return nil, errors.Errorf("IdP отклонил аутентификацию: %v", message)
+	}
+	if response.Child(nsAssertion, "EncryptedAssertion") != nil {
+		return nil, errors.New("зашифрованные утверждения не поддерживаются, отключите шифрование в IdP")
+	}
+	if len(response.ChildList(nsAssertion, "Assertion")) != 1 {
+		return nil, errors.New("ответ IdP должен содержать одно утверждение")
+	}
+	assertion, err := getSignedAssertion(data, idp.Certificate, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if idp.EntityID != "" {
+		issuer := assertion.Child(nsAssertion, "Issuer")
+		if issuer == nil || issuer.Text() != idp.EntityID {
+			return nil, errors.New("утверждение выпущено другим IdP")
+		}
+	}
+	err = checkConditions(assertion, sp, now)
+	if err != nil {
+		return nil, err
+	}
+	subject := assertion.Child(nsAssertion, "Subject")
+	if subject == nil {
+		return nil, errors.New("утверждение не содержит Subject")
+	}
+	err = checkSubjectConfirmation(subject, sp, requestID, now)
+	if err != nil {
+		return nil, err
+	}
+	nameID := subject.Child(nsAssertion, "NameID")
+	if nameID == nil || nameID.Text() == "" {
+		return nil, errors.New("утверждение не содержит NameID")
+	}
+
+	result := &Assertion{
+		NameID:     nameID.Text(),
+		Attributes: map[string][]string{},
+	}
+	for _, statement := range assertion.ChildList(nsAssertion, "AttributeStatement") {
+		for _, attribute := range statement.ChildList(nsAssertion, "Attribute") {
+			name := attribute.Attr("Name")
+			for _, value := range attribute.ChildList(nsAssertion, "AttributeValue") {
+				result.Attributes[name] = append(result.Attributes[name], value.Text())
+			}
+		}
+	}
+	return result, nil
+}
+
+// getSignedAssertion утверждение из подписанного ответа или подписанное утверждение
+func getSignedAssertion(data []byte, cert *x509.Certificate, now time.Time) (*element, error) {
+	doc := etree.NewDocument()
+	err := doc.ReadFromBytes(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка разбора XML")
+	}
+	root := doc.Root()
+	signedResponse, err := verifySigned(root, cert, now)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка проверки подписи ответа")
+	}
+	if signedResponse != nil {
+		root = signedResponse
+	}
+	assertions, err := findChildren(root, nsAssertion, "Assertion")
+	if err != nil {
+		return nil, err
+	}
+	if len(assertions) != 1 {
+		return nil, errors.New("ответ IdP должен содержать одно утверждение")
+	}
+	assertion := assertions[0]
+	signedAssertion, err := verifySigned(assertion, cert, now)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка проверки подписи утверждения")
+	}
+	if signedResponse == nil && signedAssertion == nil {
+		return nil, errors.New("ответ IdP не подписан")
+	}
+	if signedAssertion != nil {
+		assertion = signedAssertion
+	}
+	return toElement(assertion)
+}
+
+func checkConditions(assertion *element, sp ServiceProvider, now time.Time) error {
+	conditions := assertion.Child(nsAssertion, "Conditions")
+	if conditions == nil {
+		return nil
+	}
+	err := checkTimeRange(conditions.Attr("NotBefore"), conditions.Attr("NotOnOrAfter"), now)
+	if err != nil {
+		return err
+	}
+	for _, restriction := range conditions.ChildList(nsAssertion, "AudienceRestriction") {
+		found := false
+		for _, audience := range restriction.ChildList(nsAssertion, "Audience") {
+			if audience.Text() == sp.EntityID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errors.New("утверждение выпущено для другого SP")
+		}
+	}
+	return nil
+}
+
+func checkSubjectConfirmation(subject *element, sp ServiceProvider, requestID string, now time.Time) error {
+	for _, confirmation := range subject.ChildList(nsAssertion, "SubjectConfirmation") {
+		if confirmation.Attr("Method") != "urn:oasis:names:tc:SAML:2.0:cm:bearer" {
+			continue
+		}
+		data := confirmation.Child(nsAssertion, "SubjectConfirmationData")
+		if data == nil {
+			continue
+		}
+		if recipient := data.Attr("Recipient"); recipient != "" && recipient != sp.AcsURL {
+			continue
+		}
+		// вход только по запросу SP, утверждения без запроса (IdP-initiated) не принимаются
+		if data.Attr("InResponseTo") != requestID {
+			continue
+		}
+		if checkTimeRange(data.Attr("NotBefore"), data.Attr("NotOnOrAfter"), now) != nil {
+			continue
+		}
+		return nil
+	}
+	return errors.New("утверждение не содержит действующего подтверждения субъекта")
+}
+
+func checkTimeRange(notBefore, notOnOrAfter string, now time.Time) error {
+	if notBefore != "" {
+		value, err := time.Parse(time.RFC3339, notBefore)
+		if err != nil {
+			return errors.Wrap(err, "некорректная дата NotBefore")
+		}
+		if now.Add(clockSkew).Before(value) {
+			return errors.New("срок действия утверждения еще не наступил")
+		}
+	}
+	if notOnOrAfter != "" {
+		value, err := time.Parse(time.RFC3339, notOnOrAfter)
+		if err != nil {
+			return errors.Wrap(err, "некорректная дата NotOnOrAfter")
+		}
+		if !now.Add(-clockSkew).Before(value) {
+			return errors.New("срок действия утверждения истек")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+package ssosaml
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math/big"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testIdpEntityID = "https://idp.example.com/realms/corp"
+	testRequestID   = "_request-1"
+)
+
+var testSP = ServiceProvider{
+	EntityID: "https://api.example.com/api/v1/sso/space-1/metadata",
+	AcsURL:   "https://api.example.com/api/v1/sso/saml/acs",
+}
+
+// mockIdP локальный IdP: ключ и самоподписанный сертификат для подписи ответов
+type mockIdP struct {
+	key  *rsa.PrivateKey
+	cert *x509.Certificate
+}
+
+func newMockIdP(t *testing.T) mockIdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "mock-idp"},
+		NotBefore:    time.Now().Add(-24 * time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return mockIdP{key: key, cert: cert}
+}
+
+func (m mockIdP) provider() IdentityProvider {
+	return IdentityProvider{
+		EntityID:    testIdpEntityID,
+		SsoURL:      "https://idp.example.com/realms/corp/protocol/saml",
+		Certificate: m.cert,
+	}
+}
+
+// sign подпись элемента, canonical - элемент в каноническом виде (exc-c14n) без подписи,
+// подпись вставляется после первого Issuer
+func (m mockIdP) sign(t *testing.T, canonical, id string) string {
+	digest := sha256.Sum256([]byte(canonical))
+	signedInfo := `<ds:SignedInfo xmlns:ds="http://www.w3.org/2000/09/xmldsig#">` +
+		`<ds:CanonicalizationMethod Algorithm="http://www.w3.org/2001/10/xml-exc-c14n#"></ds:CanonicalizationMethod>` +
+		`<ds:SignatureMethod Algorithm="http://www.w3.org/2001/04/xmldsig-more#rsa-sha256"></ds:SignatureMethod>` +
+		`<ds:Reference URI="#` + id + `"><ds:Transforms>` +
+		`<ds:Transform Algorithm="http://www.w3.org/2000/09/xmldsig#enveloped-signature"></ds:Transform>` +
+		`<ds:Transform Algorithm="http://www.w3.org/2001/10/xml-exc-c14n#"></ds:Transform>` +
+		`</ds:Transforms><ds:DigestMethod Algorithm="http://www.w3.org/2001/04/xmlenc#sha256"></ds:DigestMethod>` +
+		`<ds:DigestValue>` + base64.StdEncoding.EncodeToString(digest[:]) + `</ds:DigestValue></ds:Reference></ds:SignedInfo>`
+	hash := sha256.Sum256([]byte(signedInfo))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, m.key, crypto.SHA256, hash[:])
+	require.NoError(t, err)
+	// значение подписи с переносами строк, как у большинства IdP
+	value := base64.StdEncoding.EncodeToString(signature)
+	value = value[:64] + "\n" + value[64:]
+	element := `<ds:Signature xmlns:ds="http://www.w3.org/2000/09/xmldsig#">` + signedInfo +
+		`<ds:SignatureValue>` + value + `</ds:SignatureValue></ds:Signature>`
+	return strings.Replace(canonical, "</saml:Issuer>", "</saml:Issuer>"+element, 1)
+}
+
+// testAssertion утверждение и ответ IdP сразу в каноническом виде, поэтому объявления
+// пространств имен находятся на элементах, которые их используют
+func testAssertion(now time.Time, email string) string {
+	return fmt.Sprintf(`<saml:Assertion xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion" ID="_assertion-1" IssueInstant="%v" Version="2.0">`+
+		`<saml:Issuer>%v</saml:Issuer>`+
+		`<saml:Subject><saml:NameID Format="urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress">%v</saml:NameID>`+
+		`<saml:SubjectConfirmation Method="urn:oasis:names:tc:SAML:2.0:cm:bearer">`+
+		`<saml:SubjectConfirmationData InResponseTo="%v" NotOnOrAfter="%v" Recipient="%v"></saml:SubjectConfirmationData>`+
+		`</saml:SubjectConfirmation></saml:Subject>`+
+		`<saml:Conditions NotBefore="%v" NotOnOrAfter="%v"><saml:AudienceRestriction><saml:Audience>%v</saml:Audience></saml:AudienceRestriction></saml:Conditions>`+
+		`<saml:AttributeStatement>`+
+		`<saml:Attribute Name="groups"><saml:AttributeValue>hr-team</saml:AttributeValue><saml:AttributeValue>all-staff</saml:AttributeValue></saml:Attribute>`+
+		`<saml:Attribute Name="givenName"><saml:AttributeValue>Иван</saml:AttributeValue></saml:Attribute>`+
+		`</saml:AttributeStatement></saml:Assertion>`,
+		now.UTC().Format(timeLayout), testIdpEntityID, email,
+		testRequestID, now.Add(5*time.Minute).UTC().Format(timeLayout), testSP.AcsURL,
+		now.Add(-time.Minute).UTC().Format(timeLayout), now.Add(5*time.Minute).UTC().Format(timeLayout), testSP.EntityID)
+}
+
+func testResponse(now time.Time, assertion string) string {
+	return fmt.Sprintf(`<samlp:Response xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" Destination="%v" ID="_response-1" InResponseTo="%v" IssueInstant="%v" Version="2.0">`+
+		`<saml:Issuer xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion">%v</saml:Issuer>`+
+		`<samlp:Status><samlp:StatusCode Value="urn:oasis:names:tc:SAML:2.0:status:Success"></samlp:StatusCode></samlp:Status>`+
+		`%v</samlp:Response>`,
+		testSP.AcsURL, testRequestID, now.UTC().Format(timeLayout), testIdpEntityID, assertion)
+}
+
+// getElement первый элемент с указанным именем из документа
+func getElement(document, name string) string {
+	start := strings.Index(document, "<"+name)
+	end := strings.Index(document, "</"+name+">") + len("</"+name+">")
+	return document[start:end]
+}
+
+func encode(value string) string {
+	return base64.StdEncoding.EncodeToString([]byte(value))
+}
+
+func TestParseXML(t *testing.T) {
+	_, err := parseXML([]byte(`<!DOCTYPE root [<!ENTITY x "y">]><root>&x;</root>`))
+	require.Error(t, err)
+}
+
+func TestGetAuthnRequestURL(t *testing.T) {
+	idp := newMockIdP(t).provider()
+	redirectURL, err := GetAuthnRequestURL(testSP, idp, testRequestID, "session-1", time.Now())
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(redirectURL, idp.SsoURL+"?"))
+
+	u, err := url.Parse(redirectURL)
+	require.NoError(t, err)
+	require.Equal(t, "session-1", u.Query().Get("RelayState"))
+	compressed, err := base64.StdEncoding.DecodeString(u.Query().Get("SAMLRequest"))
+	require.NoError(t, err)
+	request, err := io.ReadAll(flate.NewReader(bytes.NewReader(compressed)))
+	require.NoError(t, err)
+
+	root, err := parseXML(request)
+	require.NoError(t, err)
+	require.True(t, root.Is(nsProtocol, "AuthnRequest"))
+	require.Equal(t, testRequestID, root.Attr("ID"))
+	require.Equal(t, testSP.AcsURL, root.Attr("AssertionConsumerServiceURL"))
+	require.Equal(t, testSP.EntityID, root.Child(nsAssertion, "Issuer").Text())
+}
+
+func TestParseResponse(t *testing.T) {
+	idp := newMockIdP(t)
+	now := time.Now()
+	const email = "ivan.petrov@example.com"
+
+	t.Run("signed assertion", func(t *testing.T) {
+		assertion := idp.sign(t, testAssertion(now, email), "_assertion-1")
+		result, err := ParseResponse(encode(testResponse(now, assertion)), testSP, idp.provider(), testRequestID, now)
+		require.NoError(t, err)
+		require.Equal(t, email, result.NameID)
+		require.Equal(t, []string{"hr-team", "all-staff"}, result.Attributes["groups"])
+		require.Equal(t, "Иван", result.GetAttribute("firstName", "givenName"))
+	})
+
+	t.Run("signed response", func(t *testing.T) {
+		response := idp.sign(t, testResponse(now, testAssertion(now, email)), "_response-1")
+		result, err := ParseResponse(encode(response), testSP, idp.provider(), testRequestID, now)
+		require.NoError(t, err)
+		require.Equal(t, email, result.NameID)
+	})
+
+	t.Run("namespace declared on response", func(t *testing.T) {
+		const nsDecl = ` xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion"`
+		assertion := strings.Replace(idp.sign(t, testAssertion(now, email), "_assertion-1"), nsDecl, "", 1)
+		response := strings.Replace(testResponse(now, assertion), "<samlp:Response", "<samlp:Response"+nsDecl, 1)
+		result, err := ParseResponse(encode(response), testSP, idp.provider(), testRequestID, now)
+		require.NoError(t, err)
+		require.Equal(t, email, result.NameID)
+	})
+
+	t.Run("tampered assertion", func(t *testing.T) {
+		assertion := idp.sign(t, testAssertion(now, email), "_assertion-1")
+		assertion = strings.Replace(assertion, email, "admin@example.com", 1)
+		_, err := ParseResponse(encode(testResponse(now, assertion)), testSP, idp.provider(), testRequestID, now)
+		require.ErrorContains(t, err, "подпись не прошла проверку")
+	})
+
+	t.Run("other idp certificate", func(t *testing.T) {
+		assertion := newMockIdP(t).sign(t, testAssertion(now, email), "_assertion-1")
+		_, err := ParseResponse(encode(testResponse(now, assertion)), testSP, idp.provider(), testRequestID, now)
+		require.ErrorContains(t, err, "подпись не прошла проверку")
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		_, err := ParseResponse(encode(testResponse(now, testAssertion(now, email))), testSP, idp.provider(), testRequestID, now)
+		require.ErrorContains(t, err, "не подписан")
+	})
+
+	t.Run("injected assertion", func(t *testing.T) {
+		assertion := idp.sign(t, testAssertion(now, email), "_assertion-1")
+		injected := strings.Replace(testAssertion(now, "admin@example.com"), "_assertion-1", "_assertion-2", 1)
+		_, err := ParseResponse(encode(testResponse(now, injected+assertion)), testSP, idp.provider(), testRequestID, now)
+		require.Error(t, err)
+	})
+
+	t.Run("wrapped assertion with copied signature", func(t *testing.T) {
+		// подписанное утверждение перенесено в Advice, на его месте утверждение с тем же ID и копией подписи
+		signed := idp.sign(t, testAssertion(now, email), "_assertion-1")
+		signature := getElement(signed, "ds:Signature")
+		evil := strings.Replace(testAssertion(now, "admin@example.com"), "</saml:Issuer>", "</saml:Issuer>"+signature, 1)
+		evil = strings.Replace(evil, "</saml:Assertion>", "<saml:Advice>"+signed+"</saml:Advice></saml:Assertion>", 1)
+		_, err := ParseResponse(encode(testResponse(now, evil)), testSP, idp.provider(), testRequestID, now)
+		require.ErrorContains(t, err, "подпись не прошла проверку")
+	})
+
+	t.Run("signed assertion in extensions", func(t *testing.T) {
+		signed := idp.sign(t, testAssertion(now, email), "_assertion-1")
+		evil := strings.Replace(testAssertion(now, "admin@example.com"), "_assertion-1", "_assertion-2", 1)
+		response := strings.Replace(testResponse(now, evil), "<samlp:Status>", "<samlp:Extensions>"+signed+"</samlp:Extensions><samlp:Status>", 1)
+		_, err := ParseResponse(encode(response), testSP, idp.provider(), testRequestID, now)
+		require.ErrorContains(t, err, "не подписан")
+	})
+
+	t.Run("comment in name id", func(t *testing.T) {
+		// комментарий не входит в подписанные данные, NameID читается целиком
+		const signedEmail = "admin@example.com.evil.com"
+		assertion := idp.sign(t, testAssertion(now, signedEmail), "_assertion-1")
+		assertion = strings.Replace(assertion, "admin@example.com", "admin@example.com<!---->", 1)
+		result, err := ParseResponse(encode(testResponse(now, assertion)), testSP, idp.provider(), testRequestID, now)
+		require.NoError(t, err)
+		require.Equal(t, signedEmail, result.NameID)
+	})
+
+	t.Run("multiple signatures", func(t *testing.T) {
+		signed := idp.sign(t, testAssertion(now, email), "_assertion-1")
+		signature := getElement(signed, "ds:Signature")
+		assertion := strings.Replace(signed, "</saml:Issuer>", "</saml:Issuer>"+signature, 1)
+		_, err := ParseResponse(encode(testResponse(now, assertion)), testSP, idp.provider(), testRequestID, now)
+		require.ErrorContains(t, err, "одну подпись")
+	})
+
+	t.Run("multiple references", func(t *testing.T) {
+		signed := idp.sign(t, testAssertion(now, email), "_assertion-1")
+		reference := getElement(signed, "ds:Reference")
+		other := strings.Replace(reference, "#_assertion-1", "#_other", 1)
+		assertion := strings.Replace(signed, "</ds:Reference>", "</ds:Reference>"+other, 1)
+		_, err := ParseResponse(encode(testResponse(now, assertion)), testSP, idp.provider(), testRequestID, now)
+		require.ErrorContains(t, err, "одну ссылку")
+	})
+
+	t.Run("reference to other element", func(t *testing.T) {
+		assertion := idp.sign(t, testAssertion(now, email), "_other")
+		_, err := ParseResponse(encode(testResponse(now, assertion)), testSP, idp.provider(), testRequestID, now)
+		require.ErrorContains(t, err, "ссылка подписи не соответствует")
+	})
+
+	t.Run("other request", func(t *testing.T) {
+		assertion := idp.sign(t, testAssertion(now, email), "_assertion-1")
+		_, err := ParseResponse(encode(testResponse(now, assertion)), testSP, idp.provider(), "_request-2", now)
+		require.Error(t, err)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		assertion := idp.sign(t, testAssertion(now, email), "_assertion-1")
+		_, err := ParseResponse(encode(testResponse(now, assertion)), testSP, idp.provider(), testRequestID, now.Add(time.Hour))
+		require.ErrorContains(t, err, "истек")
+	})
+
+	t.Run("other audience", func(t *testing.T) {
+		assertion := idp.sign(t, testAssertion(now, email), "_assertion-1")
+		sp := ServiceProvider{EntityID: "https://other.example.com", AcsURL: testSP.AcsURL}
+		_, err := ParseResponse(encode(testResponse(now, assertion)), sp, idp.provider(), testRequestID, now)
+		require.ErrorContains(t, err, "другого SP")
+	})
+}
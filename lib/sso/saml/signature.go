@@ -0,0 +1,111 @@
+package ssosaml
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/pkg/errors"
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/russellhaering/goxmldsig/etreeutils"
+)
+
+// verifySigned проверка подписи элемента (enveloped signature, дочерний элемент ds:Signature) библиотекой goxmldsig.
+// Возвращает подписанный элемент без подписи, nil - элемент не подписан.
+// Данные пользователя берутся только из возвращенного элемента (защита от XML signature wrapping)
+func verifySigned(el *etree.Element, cert *x509.Certificate, now time.Time) (*etree.Element, error) {
+	// элемент отделяется от документа с объявлениями пространств имен предков
+	ctx, err := etreeutils.NSBuildParentContext(el)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка разбора пространств имен")
+	}
+	detached, err := etreeutils.NSDetatch(ctx, el)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка разбора пространств имен")
+	}
+	signatures, err := findChildren(detached, dsig.Namespace, dsig.SignatureTag)
+	if err != nil {
+		return nil, err
+	}
+	switch len(signatures) {
+	case 0:
+		return nil, nil
+	case 1:
+	default:
+		return nil, errors.New("элемент должен содержать одну подпись")
+	}
+	if detached.SelectAttrValue("ID", "") == "" {
+		return nil, errors.New("подписанный элемент не содержит ID")
+	}
+	signedInfo, err := findChildren(signatures[0], dsig.Namespace, dsig.SignedInfoTag)
+	if err != nil {
+		return nil, err
+	}
+	if len(signedInfo) != 1 {
+		return nil, errors.New("подпись должна содержать один SignedInfo")
+	}
+	references, err := findChildren(signedInfo[0], dsig.Namespace, dsig.ReferenceTag)
+	if err != nil {
+		return nil, err
+	}
+	if len(references) != 1 {
+		return nil, errors.New("подпись должна содержать одну ссылку")
+	}
+	if references[0].SelectAttrValue("URI", "") != "#"+detached.SelectAttrValue("ID", "") {
+		return nil, errors.New("ссылка подписи не соответствует подписанному элементу")
+	}
+
+	validation := dsig.NewDefaultValidationContext(&dsig.MemoryX509CertificateStore{
+		Roots: []*x509.Certificate{cert},
+	})
+	validation.Clock = dsig.NewFakeClockAt(now)
+	verified, err := validation.Validate(detached)
+	if err != nil {
+		return nil, errors.Wrap(err, "подпись не прошла проверку сертификатом IdP")
+	}
+	return verified, nil
+}
+
+// findChildren дочерние элементы с указанным пространством имен
+func findChildren(el *etree.Element, namespace, tag string) ([]*etree.Element, error) {
+	ctx, err := etreeutils.NSBuildParentContext(el)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка разбора пространств имен")
+	}
+	var result []*etree.Element
+	err = etreeutils.NSFindChildrenIterateCtx(ctx, el, namespace, tag, func(_ etreeutils.NSContext, child *etree.Element) error {
+		result = append(result, child)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка разбора пространств имен")
+	}
+	return result, nil
+}
+
+// toElement преобразование элемента etree в дерево элементов для чтения данных
+func toElement(el *etree.Element) (*element, error) {
+	ctx, err := etreeutils.NSBuildParentContext(el)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка разбора пространств имен")
+	}
+	detached, err := etreeutils.NSDetatch(ctx, el)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка разбора пространств имен")
+	}
+	doc := etree.NewDocument()
+	doc.SetRoot(detached)
+	data, err := doc.WriteToBytes()
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка формирования XML")
+	}
+	return parseXML(data)
+}
+
+// decodeBase64 значения в XML могут содержать переносы строк
+func decodeBase64(value string) ([]byte, error) {
+	value = strings.Join(strings.Fields(value), "")
+	return base64.StdEncoding.DecodeString(value)
+}
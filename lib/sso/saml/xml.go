@@ -0,0 +1,160 @@
+package ssosaml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const xmlnsPrefix = "xmlns"
+
+// element узел XML документа с сохранением префиксов и объявлений пространств имен
+type element struct {
+	Prefix   string
+	Local    string
+	Attrs    []xml.Attr // атрибуты без объявлений пространств имен
+	NsDecl   map[string]string
+	Children []node
+	Parent   *element
+}
+
+// node дочерний узел: элемент или текст
+type node struct {
+	Element *element
+	Text    string
+}
+
+// parseXML разбор документа в дерево элементов, комментарии и инструкции обработки отбрасываются
+func parseXML(data []byte) (*element, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var root, current *element
+	for {
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "ошибка разбора XML")
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			el := &element{
+				Prefix: t.Name.Space,
+				Local:  t.Name.Local,
+				NsDecl: map[string]string{},
+				Parent: current,
+			}
+			for _, attr := range t.Attr {
+				switch {
+				case attr.Name.Space == "" && attr.Name.Local == xmlnsPrefix:
+					el.NsDecl[""] = attr.Value
+				case attr.Name.Space == xmlnsPrefix:
+					el.NsDecl[attr.Name.Local] = attr.Value
+				default:
+					el.Attrs = append(el.Attrs, attr)
+				}
+			}
+			if current == nil {
+				if root != nil {
+					return nil, errors.New("ошибка разбора XML: несколько корневых элементов")
+				}
+				root = el
+			} else {
+				current.Children = append(current.Children, node{Element: el})
+			}
+			current = el
+		case xml.EndElement:
+			if current == nil || current.Prefix != t.Name.Space || current.Local != t.Name.Local {
+				return nil, errors.New("ошибка разбора XML: некорректная вложенность элементов")
+			}
+			current = current.Parent
+		case xml.CharData:
+			if current != nil {
+				current.Children = append(current.Children, node{Text: string(t)})
+			}
+		case xml.Directive:
+			// DTD не допускается, защита от подстановки сущностей
+			return nil, errors.New("ошибка разбора XML: DTD не поддерживается")
+		}
+	}
+	if root == nil || current != nil {
+		return nil, errors.New("ошибка разбора XML: документ не завершен")
+	}
+	return root, nil
+}
+
+// lookupNs пространство имен префикса в области видимости элемента
+func (e *element) lookupNs(prefix string) string {
+	if prefix == "xml" {
+		return "http://www.w3.org/XML/1998/namespace"
+	}
+	for el := e; el != nil; el = el.Parent {
+		if uri, ok := el.NsDecl[prefix]; ok {
+			return uri
+		}
+	}
+	return ""
+}
+
+func (e *element) Namespace() string {
+	return e.lookupNs(e.Prefix)
+}
+
+func (e *element) Is(namespace, local string) bool {
+	return e.Local == local && e.Namespace() == namespace
+}
+
+func (e *element) Attr(local string) string {
+	for _, attr := range e.Attrs {
+		if attr.Name.Space == "" && attr.Name.Local == local {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// Child первый дочерний элемент с указанным именем
+func (e *element) Child(namespace, local string) *element {
+	for _, child := range e.Children {
+		if child.Element != nil && child.Element.Is(namespace, local) {
+			return child.Element
+		}
+	}
+	return nil
+}
+
+func (e *element) ChildList(namespace, local string) []*element {
+	var result []*element
+	for _, child := range e.Children {
+		if child.Element != nil && child.Element.Is(namespace, local) {
+			result = append(result, child.Element)
+		}
+	}
+	return result
+}
+
+// Text текстовое содержимое элемента
+func (e *element) Text() string {
+	var sb strings.Builder
+	for _, child := range e.Children {
+		if child.Element == nil {
+			sb.WriteString(child.Text)
+		}
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+var textReplacer = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#xD;")
+
+var attrReplacer = strings.NewReplacer("&", "&amp;", "<", "&lt;", `"`, "&quot;", "\t", "&#x9;", "\n", "&#xA;", "\r", "&#xD;")
+
+func escapeText(text string) string {
+	return textReplacer.Replace(text)
+}
+
+func escapeAttr(text string) string {
+	return attrReplacer.Replace(text)
+}
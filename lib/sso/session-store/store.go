@@ -0,0 +1,99 @@
+package ssosessionstore
+
+import (
+	dbmodels "hr-tools-backend/models/db"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Provider interface {
+	Create(rec dbmodels.SsoSession) (id string, err error)
+	Update(id string, updMap map[string]interface{}) error
+	GetByID(id string) (*dbmodels.SsoSession, error)
+	GetByLoginCode(code string) (*dbmodels.SsoSession, error)
+	// Consume удаление использованной сессии, false - сессия уже использована другим запросом
+	Consume(id string) (bool, error)
+	DeleteExpired(now time.Time) error
+}
+
+func NewInstance(DB *gorm.DB) Provider {
+	return &impl{
+		db: DB,
+	}
+}
+
+type impl struct {
+	db *gorm.DB
+}
+
+func (i impl) Create(rec dbmodels.SsoSession) (id string, err error) {
+	err = i.db.
+		Omit(clause.Associations).
+		Save(&rec).
+		Error
+	if err != nil {
+		return "", err
+	}
+	return rec.ID, nil
+}
+
+func (i impl) Update(id string, updMap map[string]interface{}) error {
+	if len(updMap) == 0 {
+		return nil
+	}
+	return i.db.
+		Model(&dbmodels.SsoSession{}).
+		Where("id = ?", id).
+		Updates(updMap).
+		Error
+}
+
+func (i impl) GetByID(id string) (*dbmodels.SsoSession, error) {
+	rec := dbmodels.SsoSession{}
+	err := i.db.
+		Where("id = ?", id).
+		First(&rec).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (i impl) GetByLoginCode(code string) (*dbmodels.SsoSession, error) {
+	rec := dbmodels.SsoSession{}
+	err := i.db.
+		Where("login_code = ?", code).
+		First(&rec).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (i impl) Consume(id string) (bool, error) {
+	tx := i.db.
+		Where("id = ?", id).
+		Delete(&dbmodels.SsoSession{})
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+	return tx.RowsAffected == 1, nil
+}
+
+func (i impl) DeleteExpired(now time.Time) error {
+	return i.db.
+		Where("expires_at < ?", now).
+		Delete(&dbmodels.SsoSession{}).
+		Error
+}
@@ -0,0 +1,81 @@
+package ssostore
+
+import (
+	dbmodels "hr-tools-backend/models/db"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Provider interface {
+	Create(rec dbmodels.SpaceSso) (id string, err error)
+	Update(spaceID string, updMap map[string]interface{}) error
+	GetBySpace(spaceID string) (*dbmodels.SpaceSso, error)
+	// FindByDomain включенные настройки спейса с указанным почтовым доменом
+	FindByDomain(domain string) (*dbmodels.SpaceSso, error)
+}
+
+func NewInstance(DB *gorm.DB) Provider {
+	return &impl{
+		db: DB,
+	}
+}
+
+type impl struct {
+	db *gorm.DB
+}
+
+func (i impl) Create(rec dbmodels.SpaceSso) (id string, err error) {
+	err = i.db.
+		Omit(clause.Associations).
+		Save(&rec).
+		Error
+	if err != nil {
+		return "", err
+	}
+	return rec.ID, nil
+}
+
+func (i impl) Update(spaceID string, updMap map[string]interface{}) error {
+	if len(updMap) == 0 {
+		return nil
+	}
+	return i.db.
+		Model(&dbmodels.SpaceSso{}).
+		Where("space_id = ?", spaceID).
+		Updates(updMap).
+		Error
+}
+
+func (i impl) GetBySpace(spaceID string) (*dbmodels.SpaceSso, error) {
+	rec := dbmodels.SpaceSso{}
+	err := i.db.
+		Where("space_id = ?", spaceID).
+		First(&rec).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (i impl) FindByDomain(domain string) (*dbmodels.SpaceSso, error) {
+	rec := dbmodels.SpaceSso{}
+	err := i.db.
+		Where("is_enabled = ?", true).
+		Where("? = ANY(domains)", domain).
+		Order("created_at").
+		First(&rec).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rec, nil
+}
@@ -7,12 +7,15 @@ import (
 	"hr-tools-backend/lib/applicant"
 	applicanthistoryhandler "hr-tools-backend/lib/applicant-history"
 	applicantstore "hr-tools-backend/lib/applicant/store"
+	automationevent "hr-tools-backend/lib/automation/event"
 	gpthandler "hr-tools-backend/lib/gpt"
+	personaldata "hr-tools-backend/lib/personal-data"
 	applicantsurveystore "hr-tools-backend/lib/survey/applicant-survey-store"
 	vacancysurveystore "hr-tools-backend/lib/survey/vacancy-survey-store"
 	initchecker "hr-tools-backend/lib/utils/init-checker"
 	vacancystore "hr-tools-backend/lib/vacancy/store"
 	"hr-tools-backend/models"
+	personaldataapimodels "hr-tools-backend/models/api/personal-data"
 	surveyapimodels "hr-tools-backend/models/api/survey"
 	dbmodels "hr-tools-backend/models/db"
 	"sort"
@@ -28,7 +31,7 @@ type Provider interface {
 	GetApplicantSurvey(spaceID, vacancyID, applicantID string) (*surveyapimodels.ApplicantSurveyView, error)
 	GenApplicantSurvey(spaceID, vacancyID, applicantID string) (ok bool, err error)
 	GetPublicApplicantSurvey(id string) (*surveyapimodels.ApplicantSurveyView, error)
-	AnswerPublicApplicantSurvey(id string, answers []surveyapimodels.ApplicantSurveyAnswer) (hMsg string, err error)
+	AnswerPublicApplicantSurvey(id string, answers []surveyapimodels.ApplicantSurveyAnswer, consent personaldataapimodels.ConsentMeta) (hMsg string, err error)
 	AIScore(applicantSurveyRec dbmodels.ApplicantSurvey) (ok bool, err error)
 }
 
@@ -239,7 +242,7 @@ func (i impl) GetPublicApplicantSurvey(id string) (*surveyapimodels.ApplicantSur
 	return &result, nil
 }
 
-func (i impl) AnswerPublicApplicantSurvey(id string, answers []surveyapimodels.ApplicantSurveyAnswer) (hMsg string, err error) {
+func (i impl) AnswerPublicApplicantSurvey(id string, answers []surveyapimodels.ApplicantSurveyAnswer, consent personaldataapimodels.ConsentMeta) (hMsg string, err error) {
 	rec, err := i.aSurveyStore.GetByID(id)
 	if err != nil {
 		return "", errors.Wrap(err, "ошибка получения анкеты кандидата")
@@ -283,6 +286,10 @@ func (i impl) AnswerPublicApplicantSurvey(id string, answers []surveyapimodels.A
 	if err != nil {
 		return "", errors.Wrap(err, "ошибка сохранения ответов в анкету кандидата")
 	}
+	err = personaldata.Instance.SaveConsent(rec.SpaceID, rec.ApplicantID, consent)
+	if err != nil {
+		return "", err
+	}
 	return "", nil
 }
 
@@ -374,6 +381,12 @@ func (i impl) AIScore(applicantSurveyRec dbmodels.ApplicantSurvey) (ok bool, err
 	if err != nil {
 		return false, err
 	}
+	automationevent.Publish(automationevent.Event{
+		Trigger:     dbmodels.AutomationTriggerSurveyScored,
+		SpaceID:     applicantRec.SpaceID,
+		ApplicantID: applicantRec.ID,
+		VacancyID:   vacancy.ID,
+	})
 	return true, nil
 }
 
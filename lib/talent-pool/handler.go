@@ -0,0 +1,178 @@
+package talentpool
+
+import (
+	"hr-tools-backend/db"
+	candidatestore "hr-tools-backend/lib/candidate/store"
+	datascope "hr-tools-backend/lib/data-scope"
+	talentpoolstore "hr-tools-backend/lib/talent-pool/store"
+	initchecker "hr-tools-backend/lib/utils/init-checker"
+	candidateapimodels "hr-tools-backend/models/api/candidate"
+	dbmodels "hr-tools-backend/models/db"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+type Provider interface {
+	Create(spaceID, userID string, data candidateapimodels.TalentPoolData) (id string, err error)
+	Update(spaceID, id string, data candidateapimodels.TalentPoolData) (hMsg string, err error)
+	Get(spaceID, id string) (*candidateapimodels.TalentPoolView, error)
+	List(spaceID string) ([]candidateapimodels.TalentPoolView, error)
+	Delete(spaceID, id string) error
+	// AddCandidates добавление кандидатов, входящих в область видимости пользователя
+	AddCandidates(spaceID, id, userID string, data candidateapimodels.TalentPoolAddRequest) (hMsg string, err error)
+	RemoveCandidate(spaceID, id, candidateID, userID string) (hMsg string, err error)
+}
+
+var Instance Provider
+
+func NewHandler() {
+	instance := impl{
+		store:          talentpoolstore.NewInstance(db.DB),
+		candidateStore: candidatestore.NewInstance(db.DB),
+	}
+	initchecker.CheckInit(
+		"store", instance.store,
+		"candidateStore", instance.candidateStore,
+	)
+	Instance = instance
+}
+
+type impl struct {
+	store          talentpoolstore.Provider
+	candidateStore candidatestore.Provider
+}
+
+func (i impl) getLogger(spaceID, poolID string) *log.Entry {
+	logger := log.WithField("space_id", spaceID)
+	if poolID != "" {
+		logger = logger.WithField("talent_pool_id", poolID)
+	}
+	return logger
+}
+
+func (i impl) Create(spaceID, userID string, data candidateapimodels.TalentPoolData) (id string, err error) {
+	rec := dbmodels.TalentPool{
+		BaseSpaceModel: dbmodels.BaseSpaceModel{
+			SpaceID: spaceID,
+		},
+		Name:        strings.TrimSpace(data.Name),
+		Description: data.Description,
+		AuthorID:    userID,
+	}
+	id, err = i.store.Create(rec)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка создания кадрового резерва")
+	}
+	i.getLogger(spaceID, id).WithField("user_id", userID).Info("создан кадровый резерв")
+	return id, nil
+}
+
+func (i impl) Update(spaceID, id string, data candidateapimodels.TalentPoolData) (hMsg string, err error) {
+	rec, err := i.store.GetByID(spaceID, id)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения кадрового резерва")
+	}
+	if rec == nil {
+		return "кадровый резерв не найден", nil
+	}
+	updMap := map[string]interface{}{
+		"name":        strings.TrimSpace(data.Name),
+		"description": data.Description,
+	}
+	err = i.store.Update(spaceID, id, updMap)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка изменения кадрового резерва")
+	}
+	return "", nil
+}
+
+func (i impl) Get(spaceID, id string) (*candidateapimodels.TalentPoolView, error) {
+	rec, err := i.store.GetByID(spaceID, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения кадрового резерва")
+	}
+	if rec == nil {
+		return nil, nil
+	}
+	result := candidateapimodels.TalentPoolConvert(*rec)
+	return &result, nil
+}
+
+func (i impl) List(spaceID string) ([]candidateapimodels.TalentPoolView, error) {
+	list, err := i.store.List(spaceID)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения списка кадровых резервов")
+	}
+	result := make([]candidateapimodels.TalentPoolView, 0, len(list))
+	for _, rec := range list {
+		result = append(result, candidateapimodels.TalentPoolConvert(rec))
+	}
+	return result, nil
+}
+
+func (i impl) Delete(spaceID, id string) error {
+	err := i.store.Delete(spaceID, id)
+	if err != nil {
+		return errors.Wrap(err, "ошибка удаления кадрового резерва")
+	}
+	i.getLogger(spaceID, id).Info("удален кадровый резерв")
+	return nil
+}
+
+func (i impl) AddCandidates(spaceID, id, userID string, data candidateapimodels.TalentPoolAddRequest) (hMsg string, err error) {
+	pool, err := i.store.GetByID(spaceID, id)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения кадрового резерва")
+	}
+	if pool == nil {
+		return "кадровый резерв не найден", nil
+	}
+	scope, err := datascope.Instance.GetScope(spaceID, userID)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения области видимости")
+	}
+	for _, candidateID := range data.CandidateIDs {
+		visible, err := i.candidateStore.IsVisible(spaceID, candidateID, scope)
+		if err != nil {
+			return "", errors.Wrap(err, "ошибка получения кандидата")
+		}
+		if !visible {
+			return "кандидат не найден", nil
+		}
+		rec := dbmodels.TalentPoolCandidate{
+			BaseSpaceModel: dbmodels.BaseSpaceModel{
+				SpaceID: spaceID,
+			},
+			TalentPoolID: id,
+			CandidateID:  candidateID,
+			AddedBy:      userID,
+			Comment:      data.Comment,
+		}
+		err = i.store.AddCandidate(rec)
+		if err != nil {
+			return "", errors.Wrap(err, "ошибка добавления кандидата в кадровый резерв")
+		}
+	}
+	return "", nil
+}
+
+func (i impl) RemoveCandidate(spaceID, id, candidateID, userID string) (hMsg string, err error) {
+	scope, err := datascope.Instance.GetScope(spaceID, userID)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения области видимости")
+	}
+	visible, err := i.candidateStore.IsVisible(spaceID, candidateID, scope)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения кандидата")
+	}
+	if !visible {
+		return "кандидат не найден", nil
+	}
+	err = i.store.RemoveCandidate(spaceID, id, candidateID)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка исключения кандидата из кадрового резерва")
+	}
+	return "", nil
+}
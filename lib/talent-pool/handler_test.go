@@ -0,0 +1,64 @@
+package talentpool
+
+import (
+	candidatestore "hr-tools-backend/lib/candidate/store"
+	datascopefake "hr-tools-backend/lib/data-scope/fake"
+	talentpoolstore "hr-tools-backend/lib/talent-pool/store"
+	candidateapimodels "hr-tools-backend/models/api/candidate"
+	dbmodels "hr-tools-backend/models/db"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	talentpoolstore.Provider
+	added   []string
+	removed []string
+}
+
+func (s *fakeStore) GetByID(spaceID, id string) (*dbmodels.TalentPoolExt, error) {
+	return &dbmodels.TalentPoolExt{}, nil
+}
+
+func (s *fakeStore) AddCandidate(rec dbmodels.TalentPoolCandidate) error {
+	s.added = append(s.added, rec.CandidateID)
+	return nil
+}
+
+func (s *fakeStore) RemoveCandidate(spaceID, poolID, candidateID string) error {
+	s.removed = append(s.removed, candidateID)
+	return nil
+}
+
+type fakeCandidateStore struct {
+	candidatestore.Provider
+}
+
+func (s fakeCandidateStore) IsVisible(spaceID, id string, scope *dbmodels.DataScope) (bool, error) {
+	return id == "own", nil
+}
+
+func TestPoolCandidatesOutsideScope(t *testing.T) {
+	datascopefake.Set(t, datascopefake.DataScope{})
+	store := &fakeStore{}
+	i := impl{
+		store:          store,
+		candidateStore: fakeCandidateStore{},
+	}
+
+	hMsg, err := i.AddCandidates("space", "pool", "user", candidateapimodels.TalentPoolAddRequest{CandidateIDs: []string{"other"}})
+	require.NoError(t, err)
+	require.Equal(t, "кандидат не найден", hMsg)
+	require.Empty(t, store.added)
+
+	hMsg, err = i.RemoveCandidate("space", "pool", "other", "user")
+	require.NoError(t, err)
+	require.Equal(t, "кандидат не найден", hMsg)
+	require.Empty(t, store.removed)
+
+	hMsg, err = i.AddCandidates("space", "pool", "user", candidateapimodels.TalentPoolAddRequest{CandidateIDs: []string{"own"}})
+	require.NoError(t, err)
+	require.Empty(t, hMsg)
+	require.Equal(t, []string{"own"}, store.added)
+}
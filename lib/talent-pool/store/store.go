@@ -0,0 +1,154 @@
+package talentpoolstore
+
+import (
+	dbmodels "hr-tools-backend/models/db"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Provider interface {
+	Create(rec dbmodels.TalentPool) (id string, err error)
+	Update(spaceID, id string, updMap map[string]interface{}) error
+	Delete(spaceID, id string) error
+	GetByID(spaceID, id string) (*dbmodels.TalentPoolExt, error)
+	List(spaceID string) ([]dbmodels.TalentPoolExt, error)
+	ListByCandidate(spaceID, candidateID string) ([]dbmodels.TalentPool, error)
+	AddCandidate(rec dbmodels.TalentPoolCandidate) error
+	RemoveCandidate(spaceID, poolID, candidateID string) error
+}
+
+func NewInstance(DB *gorm.DB) Provider {
+	return &impl{
+		db: DB,
+	}
+}
+
+type impl struct {
+	db *gorm.DB
+}
+
+const selectExt = "talent_pools.*, (select count(*) from talent_pool_candidates c where c.talent_pool_id = talent_pools.id) as candidate_count"
+
+func (i impl) Create(rec dbmodels.TalentPool) (id string, err error) {
+	err = i.db.
+		Omit(clause.Associations).
+		Save(&rec).
+		Error
+	if err != nil {
+		return "", err
+	}
+	return rec.ID, nil
+}
+
+func (i impl) Update(spaceID, id string, updMap map[string]interface{}) error {
+	if len(updMap) == 0 {
+		return nil
+	}
+	err := i.db.
+		Model(&dbmodels.TalentPool{}).
+		Where("space_id = ?", spaceID).
+		Where("id = ?", id).
+		Updates(updMap).
+		Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (i impl) Delete(spaceID, id string) error {
+	return i.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Where("space_id = ?", spaceID).
+			Where("talent_pool_id = ?", id).
+			Delete(&dbmodels.TalentPoolCandidate{}).
+			Error
+		if err != nil {
+			return err
+		}
+		return tx.
+			Where("space_id = ?", spaceID).
+			Where("id = ?", id).
+			Delete(&dbmodels.TalentPool{}).
+			Error
+	})
+}
+
+func (i impl) GetByID(spaceID, id string) (*dbmodels.TalentPoolExt, error) {
+	rec := dbmodels.TalentPoolExt{}
+	err := i.db.
+		Model(&dbmodels.TalentPool{}).
+		Select(selectExt).
+		Where("space_id = ?", spaceID).
+		Where("id = ?", id).
+		Preload("Author").
+		First(&rec).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (i impl) List(spaceID string) ([]dbmodels.TalentPoolExt, error) {
+	list := []dbmodels.TalentPoolExt{}
+	err := i.db.
+		Model(&dbmodels.TalentPool{}).
+		Select(selectExt).
+		Where("space_id = ?", spaceID).
+		Preload("Author").
+		Order("name").
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (i impl) ListByCandidate(spaceID, candidateID string) ([]dbmodels.TalentPool, error) {
+	list := []dbmodels.TalentPool{}
+	err := i.db.
+		Where("space_id = ?", spaceID).
+		Where("id in (?)", i.db.Model(&dbmodels.TalentPoolCandidate{}).
+			Select("talent_pool_id").
+			Where("candidate_id = ?", candidateID)).
+		Order("name").
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// AddCandidate добавление кандидата в кадровый резерв, повторное добавление игнорируется
+func (i impl) AddCandidate(rec dbmodels.TalentPoolCandidate) error {
+	err := i.db.
+		Omit(clause.Associations).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&rec).
+		Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (i impl) RemoveCandidate(spaceID, poolID, candidateID string) error {
+	err := i.db.
+		Where("space_id = ?", spaceID).
+		Where("talent_pool_id = ?", poolID).
+		Where("candidate_id = ?", candidateID).
+		Delete(&dbmodels.TalentPoolCandidate{}).
+		Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
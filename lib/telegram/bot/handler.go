@@ -0,0 +1,274 @@
+package telegrambot
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"hr-tools-backend/config"
+	"hr-tools-backend/db"
+	approvaltaskstore "hr-tools-backend/lib/aproval-task/store"
+	spaceusersstore "hr-tools-backend/lib/space/users/store"
+	"hr-tools-backend/lib/telegram"
+	telegramclient "hr-tools-backend/lib/telegram/client"
+	initchecker "hr-tools-backend/lib/utils/init-checker"
+	vacancyreqhandler "hr-tools-backend/lib/vacancy-req"
+	spaceapimodels "hr-tools-backend/models/api/space"
+	telegramapimodels "hr-tools-backend/models/api/telegram"
+	vacancyapimodels "hr-tools-backend/models/api/vacancy"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const rejectComment = "Отклонено через Telegram"
+
+type Provider interface {
+	IsConfigured() bool
+	// IsWebhookMode обновления принимаются через webhook, иначе - через long polling
+	IsWebhookMode() bool
+	CreateLink(userID string) (view *spaceapimodels.TelegramLinkView, hMsg string, err error)
+	Unlink(userID string) error
+	CheckSecret(secret string) bool
+	HandleUpdate(ctx context.Context, update telegramapimodels.Update)
+}
+
+var Instance Provider
+
+func NewHandler() {
+	conf := config.Conf.Telegram
+	if conf.Token != "" && conf.WebhookURL != "" && conf.WebhookSecret == "" {
+		// без секрета любой может отправить в webhook обновление от имени согласующего
+		panic("не указан секрет webhook Telegram (TELEGRAM_WEBHOOK_SECRET)")
+	}
+	instance := impl{
+		token:          conf.Token,
+		botName:        conf.BotName,
+		webhookURL:     conf.WebhookURL,
+		webhookSecret:  conf.WebhookSecret,
+		linkExpire:     time.Duration(conf.LinkExpireMin) * time.Minute,
+		client:         telegram.GetClient(),
+		userStore:      spaceusersstore.NewInstance(db.DB),
+		taskStore:      approvaltaskstore.NewInstance(db.DB),
+		vacancyRequest: vacancyreqhandler.Instance,
+	}
+	initchecker.CheckInit(
+		"userStore", instance.userStore,
+		"taskStore", instance.taskStore,
+		"vacancyRequest", instance.vacancyRequest,
+	)
+	Instance = instance
+}
+
+type impl struct {
+	token          string
+	botName        string
+	webhookURL     string
+	webhookSecret  string
+	linkExpire     time.Duration
+	client         *telegramclient.Client
+	userStore      spaceusersstore.Provider
+	taskStore      approvaltaskstore.Provider
+	vacancyRequest vacancyreqhandler.Provider
+}
+
+func (i impl) getLogger(chatID int64) *log.Entry {
+	return log.WithField("tg_chat_id", chatID)
+}
+
+func (i impl) IsConfigured() bool {
+	return i.token != ""
+}
+
+func (i impl) IsWebhookMode() bool {
+	return i.IsConfigured() && i.webhookURL != ""
+}
+
+func (i impl) CreateLink(userID string) (view *spaceapimodels.TelegramLinkView, hMsg string, err error) {
+	if !i.IsConfigured() || i.botName == "" {
+		return nil, "Уведомления в Telegram не настроены", nil
+	}
+	code := uuid.New().String()
+	now := time.Now()
+	updMap := map[string]interface{}{
+		"tg_link_code":    code,
+		"tg_link_code_at": now,
+	}
+	err = i.userStore.Update(userID, updMap)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "ошибка сохранения кода привязки Telegram")
+	}
+	return &spaceapimodels.TelegramLinkView{
+		Link:     fmt.Sprintf("https://t.me/%v?start=%v", i.botName, code),
+		ExpireAt: now.Add(i.linkExpire).Format("02.01.2006 15:04"),
+	}, "", nil
+}
+
+func (i impl) Unlink(userID string) error {
+	updMap := map[string]interface{}{
+		"tg_chat_id":   nil,
+		"tg_link_code": "",
+	}
+	err := i.userStore.Update(userID, updMap)
+	if err != nil {
+		return errors.Wrap(err, "ошибка отвязки Telegram")
+	}
+	return nil
+}
+
+func (i impl) CheckSecret(secret string) bool {
+	if i.webhookSecret == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(secret), []byte(i.webhookSecret)) == 1
+}
+
+func (i impl) HandleUpdate(ctx context.Context, update telegramapimodels.Update) {
+	if update.CallbackQuery != nil {
+		i.handleCallback(ctx, *update.CallbackQuery)
+		return
+	}
+	if update.Message == nil || update.Message.Chat.Type != "private" {
+		return
+	}
+	msg := *update.Message
+	command, arg, _ := strings.Cut(strings.TrimSpace(msg.Text), " ")
+	switch command {
+	case "/start":
+		i.reply(ctx, msg.Chat.ID, i.link(msg.Chat.ID, strings.TrimSpace(arg)))
+	case "/stop":
+		i.reply(ctx, msg.Chat.ID, i.unlinkChat(msg.Chat.ID))
+	}
+}
+
+// link привязка чата к пользователю по коду из профиля
+func (i impl) link(chatID int64, code string) string {
+	logger := i.getLogger(chatID)
+	if code == "" {
+		return "Для получения уведомлений перейдите по ссылке из профиля пользователя HR-Tools."
+	}
+	user, err := i.userStore.GetByTgLinkCode(code)
+	if err != nil {
+		logger.WithError(err).Error("ошибка поиска пользователя по коду привязки Telegram")
+		return "Произошла ошибка, попробуйте позже."
+	}
+	if user == nil || user.TgLinkCodeAt.Add(i.linkExpire).Before(time.Now()) {
+		return "Ссылка не найдена или более не актуальна, получите новую ссылку в профиле пользователя."
+	}
+	// чат может быть привязан только к одному пользователю
+	prevUser, err := i.userStore.GetByTgChatID(chatID)
+	if err != nil {
+		logger.WithError(err).Error("ошибка поиска пользователя по чату Telegram")
+		return "Произошла ошибка, попробуйте позже."
+	}
+	if prevUser != nil && prevUser.ID != user.ID {
+		err = i.userStore.Update(prevUser.ID, map[string]interface{}{"tg_chat_id": nil})
+		if err != nil {
+			logger.WithError(err).Error("ошибка отвязки чата Telegram от предыдущего пользователя")
+			return "Произошла ошибка, попробуйте позже."
+		}
+	}
+	updMap := map[string]interface{}{
+		"tg_chat_id":   chatID,
+		"tg_link_code": "",
+	}
+	err = i.userStore.Update(user.ID, updMap)
+	if err != nil {
+		logger.WithError(err).Error("ошибка привязки чата Telegram")
+		return "Произошла ошибка, попробуйте позже."
+	}
+	logger.WithField("user_id", user.ID).Info("чат Telegram привязан к пользователю")
+	return fmt.Sprintf("%v, уведомления HR-Tools будут приходить в этот чат. Выбрать события можно в настройках уведомлений профиля. Отключить - команда /stop.", user.GetFullName())
+}
+
+func (i impl) unlinkChat(chatID int64) string {
+	logger := i.getLogger(chatID)
+	user, err := i.userStore.GetByTgChatID(chatID)
+	if err != nil {
+		logger.WithError(err).Error("ошибка поиска пользователя по чату Telegram")
+		return "Произошла ошибка, попробуйте позже."
+	}
+	if user == nil {
+		return "Чат не привязан к пользователю HR-Tools."
+	}
+	err = i.Unlink(user.ID)
+	if err != nil {
+		logger.WithError(err).Error("ошибка отвязки чата Telegram")
+		return "Произошла ошибка, попробуйте позже."
+	}
+	return "Уведомления HR-Tools отключены."
+}
+
+// handleCallback решение по задаче согласования заявки кнопкой под уведомлением
+func (i impl) handleCallback(ctx context.Context, query telegramapimodels.CallbackQuery) {
+	logger := i.getLogger(query.From.ID).WithField("callback_data", query.Data)
+	answer, done := i.decide(query)
+	err := i.client.AnswerCallbackQuery(ctx, query.ID, answer)
+	if err != nil {
+		logger.WithError(err).Warn("ошибка ответа на нажатие кнопки Telegram")
+	}
+	if !done || query.Message == nil {
+		return
+	}
+	// решение принято, кнопки больше не нужны
+	err = i.client.EditMessageReplyMarkup(ctx, query.Message.Chat.ID, query.Message.MessageID, nil)
+	if err != nil {
+		logger.WithError(err).Warn("ошибка удаления кнопок сообщения Telegram")
+	}
+}
+
+func (i impl) decide(query telegramapimodels.CallbackQuery) (answer string, done bool) {
+	logger := i.getLogger(query.From.ID).WithField("callback_data", query.Data)
+	action, taskID, ok := telegramapimodels.ParseCallbackData(query.Data)
+	if !ok {
+		return "Действие не поддерживается", false
+	}
+	user, err := i.userStore.GetByTgChatID(query.From.ID)
+	if err != nil {
+		logger.WithError(err).Error("ошибка поиска пользователя по чату Telegram")
+		return "Произошла ошибка, попробуйте позже", false
+	}
+	if user == nil {
+		return "Чат не привязан к пользователю HR-Tools", false
+	}
+	logger = logger.WithField("user_id", user.ID)
+	task, err := i.taskStore.GetByID(user.SpaceID, taskID)
+	if err != nil {
+		logger.WithError(err).Error("ошибка получения задачи на согласование")
+		return "Произошла ошибка, попробуйте позже", false
+	}
+	if task == nil {
+		return "Задача на согласование не найдена", true
+	}
+	if task.AssigneeUserID != user.ID {
+		return "На данную задачу назначен другой пользователь", true
+	}
+	var hMsg string
+	switch action {
+	case telegramapimodels.CallbackVRApprove:
+		hMsg, err = i.vacancyRequest.Approve(user.SpaceID, task.RequestID, task.ID, user.ID)
+		answer = "Заявка согласована"
+	case telegramapimodels.CallbackVRReject:
+		hMsg, err = i.vacancyRequest.Reject(user.SpaceID, task.RequestID, task.ID, user.ID, vacancyapimodels.ApprovalReject{Comment: rejectComment})
+		answer = "Заявка отклонена"
+	}
+	if err != nil {
+		logger.WithError(err).Error("ошибка решения по задаче на согласование из Telegram")
+		return "Произошла ошибка, попробуйте позже", false
+	}
+	if hMsg != "" {
+		// задача уже решена или заявка в другом статусе - повторное нажатие бесполезно
+		return hMsg, true
+	}
+	logger.WithField("task_id", task.ID).Info("решение по задаче на согласование принято через Telegram")
+	return answer, true
+}
+
+func (i impl) reply(ctx context.Context, chatID int64, text string) {
+	_, err := i.client.SendMessage(ctx, chatID, text, nil)
+	if err != nil {
+		i.getLogger(chatID).WithError(err).Warn("ошибка отправки ответа в Telegram")
+	}
+}
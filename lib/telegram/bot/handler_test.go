@@ -0,0 +1,23 @@
+package telegrambot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckSecret(t *testing.T) {
+	i := impl{token: "token", webhookURL: "https://example.com/webhook", webhookSecret: "secret"}
+	require.True(t, i.IsWebhookMode())
+	require.True(t, i.CheckSecret("secret"))
+	require.False(t, i.CheckSecret("wrong"))
+	require.False(t, i.CheckSecret(""))
+
+	// без секрета обновления через webhook не принимаются
+	i.webhookSecret = ""
+	require.False(t, i.CheckSecret(""))
+
+	// в режиме long polling webhook не используется
+	i.webhookURL = ""
+	require.False(t, i.IsWebhookMode())
+}
@@ -0,0 +1,140 @@
+package telegramclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	telegramapimodels "hr-tools-backend/models/api/telegram"
+
+	"github.com/pkg/errors"
+)
+
+const maxResponseSize = 10 * 1024 * 1024
+
+var ErrBlocked = errors.New("бот заблокирован пользователем")
+
+var allowedUpdates = []string{"message", "callback_query"}
+
+// Client клиент Telegram Bot API
+type Client struct {
+	apiURL     string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient apiURL - адрес Bot API (https://api.telegram.org или локальный сервер)
+func NewClient(apiURL, token string, timeout time.Duration) *Client {
+	return &Client{
+		apiURL:     strings.TrimSuffix(apiURL, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// SendMessage отправка сообщения в чат, markup - кнопки под сообщением (может быть nil)
+func (c Client) SendMessage(ctx context.Context, chatID int64, text string, markup *telegramapimodels.InlineKeyboardMarkup) (messageID int64, err error) {
+	req := telegramapimodels.SendMessageRequest{
+		ChatID:      chatID,
+		Text:        text,
+		ReplyMarkup: markup,
+	}
+	var resp telegramapimodels.MessageResponse
+	err = c.call(ctx, "sendMessage", req, &resp, &resp.Response)
+	if err != nil {
+		return 0, err
+	}
+	return resp.Result.MessageID, nil
+}
+
+// EditMessageReplyMarkup изменение кнопок отправленного сообщения, markup nil - удаление кнопок
+func (c Client) EditMessageReplyMarkup(ctx context.Context, chatID, messageID int64, markup *telegramapimodels.InlineKeyboardMarkup) error {
+	req := telegramapimodels.EditMessageReplyMarkupRequest{
+		ChatID:      chatID,
+		MessageID:   messageID,
+		ReplyMarkup: markup,
+	}
+	var resp telegramapimodels.Response
+	return c.call(ctx, "editMessageReplyMarkup", req, &resp, &resp)
+}
+
+// AnswerCallbackQuery ответ на нажатие кнопки, text - всплывающее уведомление
+func (c Client) AnswerCallbackQuery(ctx context.Context, callbackQueryID, text string) error {
+	req := telegramapimodels.AnswerCallbackQueryRequest{
+		CallbackQueryID: callbackQueryID,
+		Text:            text,
+	}
+	var resp telegramapimodels.Response
+	return c.call(ctx, "answerCallbackQuery", req, &resp, &resp)
+}
+
+// GetUpdates получение обновлений (long polling), timeout - время ожидания обновлений в секундах
+func (c Client) GetUpdates(ctx context.Context, offset int64, timeout int) ([]telegramapimodels.Update, error) {
+	req := telegramapimodels.GetUpdatesRequest{
+		Offset:         offset,
+		Timeout:        timeout,
+		AllowedUpdates: allowedUpdates,
+	}
+	var resp telegramapimodels.UpdatesResponse
+	err := c.call(ctx, "getUpdates", req, &resp, &resp.Response)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Result, nil
+}
+
+// SetWebhook регистрация адреса для получения обновлений, secret передается ботом в заголовке X-Telegram-Bot-Api-Secret-Token
+func (c Client) SetWebhook(ctx context.Context, url, secret string) error {
+	req := telegramapimodels.SetWebhookRequest{
+		URL:            url,
+		SecretToken:    secret,
+		AllowedUpdates: allowedUpdates,
+	}
+	var resp telegramapimodels.Response
+	return c.call(ctx, "setWebhook", req, &resp, &resp)
+}
+
+// DeleteWebhook отключение webhook, требуется для получения обновлений через getUpdates
+func (c Client) DeleteWebhook(ctx context.Context) error {
+	var resp telegramapimodels.Response
+	return c.call(ctx, "deleteWebhook", struct{}{}, &resp, &resp)
+}
+
+func (c Client) call(ctx context.Context, method string, req interface{}, result interface{}, status *telegramapimodels.Response) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return errors.Wrap(err, "ошибка формирования запроса")
+	}
+	url := fmt.Sprintf("%v/bot%v/%v", c.apiURL, c.token, method)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "ошибка формирования запроса")
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		// токен в адресе запроса не должен попасть в лог
+		return errors.Errorf("ошибка запроса %v к Telegram", method)
+	}
+	defer httpResp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(httpResp.Body, maxResponseSize))
+	if err != nil {
+		return errors.Wrap(err, "ошибка чтения ответа")
+	}
+	err = json.Unmarshal(data, result)
+	if err != nil {
+		return errors.Errorf("некорректный ответ Telegram на запрос %v, статус: %v", method, httpResp.StatusCode)
+	}
+	if !status.Ok {
+		if status.ErrorCode == http.StatusForbidden {
+			return ErrBlocked
+		}
+		return errors.Errorf("ошибка Telegram на запрос %v: %v (%v)", method, status.Description, status.ErrorCode)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package telegramclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	telegramapimodels "hr-tools-backend/models/api/telegram"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBotAPI локальная замена Telegram Bot API для бота с токеном "token"
+type fakeBotAPI struct {
+	mu        sync.Mutex
+	messages  []telegramapimodels.SendMessageRequest
+	updates   []telegramapimodels.Update
+	answered  []string
+	edited    []telegramapimodels.EditMessageReplyMarkupRequest
+	webhook   telegramapimodels.SetWebhookRequest
+	blockedID int64
+}
+
+func (f *fakeBotAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	method, found := strings.CutPrefix(r.URL.Path, "/bottoken/")
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(telegramapimodels.Response{ErrorCode: 404, Description: "Not Found"})
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var result interface{} = true
+	switch method {
+	case "sendMessage":
+		var req telegramapimodels.SendMessageRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.ChatID == f.blockedID {
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(telegramapimodels.Response{ErrorCode: 403, Description: "Forbidden: bot was blocked by the user"})
+			return
+		}
+		f.messages = append(f.messages, req)
+		result = telegramapimodels.Message{MessageID: int64(len(f.messages)), Chat: telegramapimodels.Chat{ID: req.ChatID}, Text: req.Text}
+	case "getUpdates":
+		var req telegramapimodels.GetUpdatesRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		list := []telegramapimodels.Update{}
+		for _, update := range f.updates {
+			if update.UpdateID >= req.Offset {
+				list = append(list, update)
+			}
+		}
+		result = list
+	case "answerCallbackQuery":
+		var req telegramapimodels.AnswerCallbackQueryRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		f.answered = append(f.answered, req.CallbackQueryID)
+	case "editMessageReplyMarkup":
+		var req telegramapimodels.EditMessageReplyMarkupRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		f.edited = append(f.edited, req)
+	case "setWebhook":
+		_ = json.NewDecoder(r.Body).Decode(&f.webhook)
+	case "deleteWebhook":
+		f.webhook = telegramapimodels.SetWebhookRequest{}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(telegramapimodels.Response{ErrorCode: 404, Description: "Not Found"})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"ok": true, "result": result})
+}
+
+func TestClient(t *testing.T) {
+	fake := &fakeBotAPI{blockedID: 13}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	ctx := context.Background()
+	client := NewClient(server.URL+"/", "token", time.Second)
+
+	t.Run("send message", func(t *testing.T) {
+		markup := &telegramapimodels.InlineKeyboardMarkup{
+			InlineKeyboard: [][]telegramapimodels.InlineKeyboardButton{{
+				{Text: "Согласовать", CallbackData: telegramapimodels.MakeCallbackData(telegramapimodels.CallbackVRApprove, "task")},
+			}},
+		}
+		messageID, err := client.SendMessage(ctx, 42, "Заявка ожидает согласования", markup)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), messageID)
+		require.Len(t, fake.messages, 1)
+		require.Equal(t, int64(42), fake.messages[0].ChatID)
+		require.Equal(t, "vra:task", fake.messages[0].ReplyMarkup.InlineKeyboard[0][0].CallbackData)
+
+		_, err = client.SendMessage(ctx, 13, "text", nil)
+		require.ErrorIs(t, err, ErrBlocked)
+
+		_, err = NewClient(server.URL, "wrong", time.Second).SendMessage(ctx, 42, "text", nil)
+		require.Error(t, err)
+		require.NotContains(t, err.Error(), "wrong")
+	})
+
+	t.Run("updates", func(t *testing.T) {
+		fake.updates = []telegramapimodels.Update{
+			{UpdateID: 10, Message: &telegramapimodels.Message{Chat: telegramapimodels.Chat{ID: 42}, Text: "/start code"}},
+			{UpdateID: 11, CallbackQuery: &telegramapimodels.CallbackQuery{ID: "cb", Data: "vrr:task"}},
+		}
+		updates, err := client.GetUpdates(ctx, 0, 0)
+		require.NoError(t, err)
+		require.Len(t, updates, 2)
+		require.Equal(t, "/start code", updates[0].Message.Text)
+
+		updates, err = client.GetUpdates(ctx, 11, 0)
+		require.NoError(t, err)
+		require.Len(t, updates, 1)
+		require.Equal(t, "vrr:task", updates[0].CallbackQuery.Data)
+
+		require.NoError(t, client.AnswerCallbackQuery(ctx, "cb", "Заявка отклонена"))
+		require.Equal(t, []string{"cb"}, fake.answered)
+		require.NoError(t, client.EditMessageReplyMarkup(ctx, 42, 1, nil))
+		require.Len(t, fake.edited, 1)
+		require.Nil(t, fake.edited[0].ReplyMarkup)
+	})
+
+	t.Run("webhook", func(t *testing.T) {
+		require.NoError(t, client.SetWebhook(ctx, "https://example.com/api/v1/telegram/webhook", "secret"))
+		require.Equal(t, "secret", fake.webhook.SecretToken)
+		require.NoError(t, client.DeleteWebhook(ctx))
+		require.Empty(t, fake.webhook.URL)
+	})
+}
+
+func TestParseCallbackData(t *testing.T) {
+	action, id, ok := telegramapimodels.ParseCallbackData(telegramapimodels.MakeCallbackData(telegramapimodels.CallbackVRReject, "task-id"))
+	require.True(t, ok)
+	require.Equal(t, telegramapimodels.CallbackVRReject, action)
+	require.Equal(t, "task-id", id)
+
+	_, _, ok = telegramapimodels.ParseCallbackData("unknown:task-id")
+	require.False(t, ok)
+	_, _, ok = telegramapimodels.ParseCallbackData("vra:")
+	require.False(t, ok)
+	_, _, ok = telegramapimodels.ParseCallbackData("vra")
+	require.False(t, ok)
+}
@@ -0,0 +1,85 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"hr-tools-backend/config"
+	telegramclient "hr-tools-backend/lib/telegram/client"
+	"hr-tools-backend/models"
+	telegramapimodels "hr-tools-backend/models/api/telegram"
+	"strings"
+	"time"
+)
+
+type Provider interface {
+	IsConfigured() bool
+	SendNotification(chatID int64, data models.NotificationData) error
+}
+
+var Instance Provider
+
+func NewHandler() {
+	instance := impl{
+		token:              config.Conf.Telegram.Token,
+		client:             GetClient(),
+		applicantPath:      config.Conf.UIParams.ApplicantPath,
+		vacancyRequestPath: config.Conf.UIParams.VacancyRequestPath,
+	}
+	Instance = instance
+}
+
+// GetClient клиент Bot API по настройкам сервиса
+func GetClient() *telegramclient.Client {
+	timeout := time.Duration(config.Conf.Telegram.TimeoutSec) * time.Second
+	return telegramclient.NewClient(config.Conf.Telegram.ApiURL, config.Conf.Telegram.Token, timeout)
+}
+
+type impl struct {
+	token              string
+	client             *telegramclient.Client
+	applicantPath      string
+	vacancyRequestPath string
+}
+
+func (i impl) IsConfigured() bool {
+	return i.token != ""
+}
+
+func (i impl) SendNotification(chatID int64, data models.NotificationData) error {
+	text := fmt.Sprintf("%v\n\n%v", data.Title, data.Msg)
+	_, err := i.client.SendMessage(context.Background(), chatID, text, i.getKeyboard(data))
+	return err
+}
+
+// getKeyboard кнопки под уведомлением: решение по задаче согласования заявки и переход к заявке / кандидату
+func (i impl) getKeyboard(data models.NotificationData) *telegramapimodels.InlineKeyboardMarkup {
+	if data.EntityID == "" {
+		return nil
+	}
+	rows := [][]telegramapimodels.InlineKeyboardButton{}
+	code := string(data.Code)
+	switch {
+	case strings.HasPrefix(code, "PushVR"):
+		if data.Code == models.PushVRApprovalTask && data.TaskID != "" {
+			rows = append(rows, []telegramapimodels.InlineKeyboardButton{
+				{Text: "Согласовать", CallbackData: telegramapimodels.MakeCallbackData(telegramapimodels.CallbackVRApprove, data.TaskID)},
+				{Text: "Отклонить", CallbackData: telegramapimodels.MakeCallbackData(telegramapimodels.CallbackVRReject, data.TaskID)},
+			})
+		}
+		if i.vacancyRequestPath != "" {
+			rows = append(rows, []telegramapimodels.InlineKeyboardButton{
+				{Text: "Открыть заявку", URL: i.vacancyRequestPath + data.EntityID},
+			})
+		}
+	case strings.HasPrefix(code, "PushApplicant"), strings.HasPrefix(code, "PushInterview"), strings.HasPrefix(code, "PushOffer"):
+		if i.applicantPath != "" {
+			rows = append(rows, []telegramapimodels.InlineKeyboardButton{
+				{Text: "Открыть кандидата", URL: i.applicantPath + data.EntityID},
+			})
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	return &telegramapimodels.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
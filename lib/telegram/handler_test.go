@@ -0,0 +1,54 @@
+package telegram
+
+import (
+	"hr-tools-backend/models"
+	telegramapimodels "hr-tools-backend/models/api/telegram"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetKeyboard(t *testing.T) {
+	i := impl{
+		applicantPath:      "https://ui/applicants/",
+		vacancyRequestPath: "https://ui/vacancy-requests/",
+	}
+
+	t.Run("задача согласования заявки", func(t *testing.T) {
+		keyboard := i.getKeyboard(models.GetPushVRApprovalTask("Разработчик").WithEntity("vr").WithTask("task"))
+		require.NotNil(t, keyboard)
+		require.Len(t, keyboard.InlineKeyboard, 2)
+		require.Equal(t, "vra:task", keyboard.InlineKeyboard[0][0].CallbackData)
+		require.Equal(t, "vrr:task", keyboard.InlineKeyboard[0][1].CallbackData)
+		require.Equal(t, "https://ui/vacancy-requests/vr", keyboard.InlineKeyboard[1][0].URL)
+
+		// без задачи (эскалация) только переход к заявке
+		keyboard = i.getKeyboard(models.GetPushVRApprovalTask("Разработчик").WithEntity("vr"))
+		require.Len(t, keyboard.InlineKeyboard, 1)
+		require.Empty(t, keyboard.InlineKeyboard[0][0].CallbackData)
+	})
+
+	t.Run("кандидат", func(t *testing.T) {
+		for _, data := range []models.NotificationData{
+			models.GetPushApplicantNegotiation("Разработчик", "Иванов Иван"),
+			models.GetPushInterviewReminder("Разработчик", "Иванов Иван", "10:00"),
+			models.GetPushOfferAccepted("Разработчик", "Иванов Иван"),
+		} {
+			keyboard := i.getKeyboard(data.WithEntity("applicant"))
+			require.NotNil(t, keyboard, data.Code)
+			require.Equal(t, "https://ui/applicants/applicant", keyboard.InlineKeyboard[0][0].URL)
+		}
+	})
+
+	t.Run("без кнопок", func(t *testing.T) {
+		require.Nil(t, i.getKeyboard(models.GetPushApplicantNegotiation("Разработчик", "Иванов Иван")))
+		require.Nil(t, i.getKeyboard(models.GetPushVacancyNewStatus("Разработчик", "opened").WithEntity("vacancy")))
+		require.Nil(t, impl{}.getKeyboard(models.GetPushApplicantNote("Разработчик", "Иванов Иван", "Петров Петр").WithEntity("applicant")))
+	})
+}
+
+func TestCallbackDataLimit(t *testing.T) {
+	// идентификатор задачи - uuid, данные кнопки не должны превышать 64 байта
+	data := telegramapimodels.MakeCallbackData(telegramapimodels.CallbackVRApprove, "7f1c1f3e-5b5e-4e43-9a0b-6a4b7e1f9c11")
+	require.LessOrEqual(t, len(data), 64)
+}
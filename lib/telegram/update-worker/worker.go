@@ -0,0 +1,81 @@
+package telegramupdateworker
+
+import (
+	"context"
+	"hr-tools-backend/config"
+	"hr-tools-backend/lib/telegram"
+	telegrambot "hr-tools-backend/lib/telegram/bot"
+	telegramclient "hr-tools-backend/lib/telegram/client"
+	baseworker "hr-tools-backend/lib/utils/base-worker"
+	"hr-tools-backend/lib/utils/helpers"
+	"time"
+)
+
+// Задача получения обновлений бота Telegram: регистрация webhook или long polling, если адрес webhook не указан
+func StartWorker(ctx context.Context) {
+	if !telegrambot.Instance.IsConfigured() {
+		return
+	}
+	i := &impl{
+		BaseImpl:   *baseworker.NewInstance("TelegramUpdateWorker", 10*time.Second, 10*time.Second),
+		client:     telegram.GetClient(),
+		webhookURL: config.Conf.Telegram.WebhookURL,
+		secret:     config.Conf.Telegram.WebhookSecret,
+		timeout:    config.Conf.Telegram.TimeoutSec,
+	}
+	go i.Run(ctx, i.handle)
+}
+
+type impl struct {
+	baseworker.BaseImpl
+	client     *telegramclient.Client
+	webhookURL string
+	secret     string
+	timeout    int
+	registered bool
+	offset     int64
+}
+
+func (i *impl) handle(ctx context.Context) {
+	logger := i.GetLogger()
+	if i.webhookURL != "" {
+		if i.registered {
+			return
+		}
+		err := i.client.SetWebhook(ctx, i.webhookURL, i.secret)
+		if err != nil {
+			logger.WithError(err).Error("ошибка регистрации webhook Telegram")
+			return
+		}
+		i.registered = true
+		logger.Info("webhook Telegram зарегистрирован")
+		return
+	}
+	if !i.registered {
+		// при активном webhook getUpdates недоступен
+		err := i.client.DeleteWebhook(ctx)
+		if err != nil {
+			logger.WithError(err).Error("ошибка отключения webhook Telegram")
+			return
+		}
+		i.registered = true
+	}
+	// таймаут ожидания обновлений меньше таймаута http клиента
+	pollTimeout := i.timeout - 5
+	if pollTimeout < 0 {
+		pollTimeout = 0
+	}
+	for !helpers.IsContextDone(ctx) {
+		updates, err := i.client.GetUpdates(ctx, i.offset, pollTimeout)
+		if err != nil {
+			if !helpers.IsContextDone(ctx) {
+				logger.WithError(err).Error("ошибка получения обновлений Telegram")
+			}
+			return
+		}
+		for _, update := range updates {
+			i.offset = update.UpdateID + 1
+			telegrambot.Instance.HandleUpdate(ctx, update)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package twofactor
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hr-tools-backend/config"
+	"hr-tools-backend/db"
+	twofactorstore "hr-tools-backend/lib/two-factor/store"
+	authhelpers "hr-tools-backend/lib/utils/auth-helpers"
+	initchecker "hr-tools-backend/lib/utils/init-checker"
+	"hr-tools-backend/models"
+	authapimodels "hr-tools-backend/models/api/auth"
+	dbmodels "hr-tools-backend/models/db"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const recoveryCodesCount = 10
+
+type Provider interface {
+	GetStatus(kind models.AuthUserKind, userID string) (authapimodels.TwoFactorStatus, error)
+	IsEnabled(kind models.AuthUserKind, userID string) (bool, error)
+	// Setup выпуск нового секрета TOTP, вход с ним начинает работать после подтверждения кодом (Enable)
+	Setup(kind models.AuthUserKind, userID, account string) (resp authapimodels.TwoFactorSetup, hMsg string, err error)
+	Enable(kind models.AuthUserKind, userID, code string) (recoveryCodes []string, hMsg string, err error)
+	Disable(kind models.AuthUserKind, userID, code string) (hMsg string, err error)
+	RegenerateRecoveryCodes(kind models.AuthUserKind, userID, code string) (recoveryCodes []string, hMsg string, err error)
+	// Verify проверка кода TOTP или кода восстановления при входе
+	Verify(kind models.AuthUserKind, userID, code string) (hMsg string, err error)
+	// CreateChallenge токен второго шага входа, выдается после проверки пароля
+	CreateChallenge(kind models.AuthUserKind, userID string) (string, error)
+	ParseChallenge(kind models.AuthUserKind, token string) (userID string, err error)
+}
+
+var Instance Provider
+
+func NewHandler() {
+	instance := impl{
+		store: twofactorstore.NewInstance(db.DB),
+	}
+	initchecker.CheckInit(
+		"store", instance.store,
+	)
+	Instance = instance
+}
+
+type impl struct {
+	store twofactorstore.Provider
+}
+
+func (i impl) GetStatus(kind models.AuthUserKind, userID string) (authapimodels.TwoFactorStatus, error) {
+	rec, err := i.store.GetByUser(kind, userID)
+	if err != nil {
+		return authapimodels.TwoFactorStatus{}, errors.Wrap(err, "ошибка получения настроек двухфакторной аутентификации")
+	}
+	if rec == nil || !rec.IsEnabled {
+		return authapimodels.TwoFactorStatus{}, nil
+	}
+	return authapimodels.TwoFactorStatus{
+		Enabled:           true,
+		RecoveryCodesLeft: len(rec.RecoveryCodes),
+	}, nil
+}
+
+func (i impl) IsEnabled(kind models.AuthUserKind, userID string) (bool, error) {
+	rec, err := i.store.GetByUser(kind, userID)
+	if err != nil {
+		return false, errors.Wrap(err, "ошибка получения настроек двухфакторной аутентификации")
+	}
+	return rec != nil && rec.IsEnabled, nil
+}
+
+func (i impl) Setup(kind models.AuthUserKind, userID, account string) (resp authapimodels.TwoFactorSetup, hMsg string, err error) {
+	rec, err := i.store.GetByUser(kind, userID)
+	if err != nil {
+		return authapimodels.TwoFactorSetup{}, "", errors.Wrap(err, "ошибка получения настроек двухфакторной аутентификации")
+	}
+	if rec != nil && rec.IsEnabled {
+		return authapimodels.TwoFactorSetup{}, "двухфакторная аутентификация уже подключена", nil
+	}
+	secret, err := authhelpers.GenerateTotpSecret()
+	if err != nil {
+		return authapimodels.TwoFactorSetup{}, "", err
+	}
+	encrypted, err := authhelpers.EncryptString(config.Conf.TwoFactor.SecretKey, secret)
+	if err != nil {
+		return authapimodels.TwoFactorSetup{}, "", errors.Wrap(err, "ошибка шифрования секрета")
+	}
+	if rec == nil {
+		_, err = i.store.Create(dbmodels.UserTwoFactor{
+			UserKind: kind,
+			UserID:   userID,
+			Secret:   encrypted,
+		})
+	} else {
+		err = i.store.Update(rec.ID, map[string]interface{}{
+			"secret":    encrypted,
+			"last_step": 0,
+		})
+	}
+	if err != nil {
+		return authapimodels.TwoFactorSetup{}, "", errors.Wrap(err, "ошибка сохранения секрета")
+	}
+	return authapimodels.TwoFactorSetup{
+		Secret: secret,
+		Uri:    authhelpers.GetTotpURI(config.Conf.TwoFactor.Issuer, account, secret),
+	}, "", nil
+}
+
+func (i impl) Enable(kind models.AuthUserKind, userID, code string) (recoveryCodes []string, hMsg string, err error) {
+	rec, err := i.store.GetByUser(kind, userID)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "ошибка получения настроек двухфакторной аутентификации")
+	}
+	if rec == nil || rec.Secret == "" {
+		return nil, "сначала получите секрет для приложения-аутентификатора", nil
+	}
+	if rec.IsEnabled {
+		return nil, "двухфакторная аутентификация уже подключена", nil
+	}
+	hMsg, err = i.checkCode(*rec, code, false)
+	if err != nil || hMsg != "" {
+		return nil, hMsg, err
+	}
+	recoveryCodes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, "", err
+	}
+	updMap := map[string]interface{}{
+		"is_enabled":     true,
+		"enabled_at":     time.Now(),
+		"recovery_codes": hashes,
+	}
+	err = i.store.Update(rec.ID, updMap)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "ошибка подключения двухфакторной аутентификации")
+	}
+	log.
+		WithField("user_id", userID).
+		WithField("user_kind", kind).
+		Info("подключена двухфакторная аутентификация")
+	return recoveryCodes, "", nil
+}
+
+func (i impl) Disable(kind models.AuthUserKind, userID, code string) (hMsg string, err error) {
+	rec, hMsg, err := i.getEnabled(kind, userID)
+	if err != nil || hMsg != "" {
+		return hMsg, err
+	}
+	hMsg, err = i.checkCode(*rec, code, true)
+	if err != nil || hMsg != "" {
+		return hMsg, err
+	}
+	updMap := map[string]interface{}{
+		"is_enabled":     false,
+		"enabled_at":     nil,
+		"secret":         "",
+		"recovery_codes": pq.StringArray{},
+	}
+	err = i.store.Update(rec.ID, updMap)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка отключения двухфакторной аутентификации")
+	}
+	log.
+		WithField("user_id", userID).
+		WithField("user_kind", kind).
+		Info("отключена двухфакторная аутентификация")
+	return "", nil
+}
+
+func (i impl) RegenerateRecoveryCodes(kind models.AuthUserKind, userID, code string) (recoveryCodes []string, hMsg string, err error) {
+	rec, hMsg, err := i.getEnabled(kind, userID)
+	if err != nil || hMsg != "" {
+		return nil, hMsg, err
+	}
+	hMsg, err = i.checkCode(*rec, code, true)
+	if err != nil || hMsg != "" {
+		return nil, hMsg, err
+	}
+	recoveryCodes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, "", err
+	}
+	err = i.store.Update(rec.ID, map[string]interface{}{"recovery_codes": hashes})
+	if err != nil {
+		return nil, "", errors.Wrap(err, "ошибка сохранения кодов восстановления")
+	}
+	return recoveryCodes, "", nil
+}
+
+func (i impl) Verify(kind models.AuthUserKind, userID, code string) (hMsg string, err error) {
+	rec, hMsg, err := i.getEnabled(kind, userID)
+	if err != nil || hMsg != "" {
+		return hMsg, err
+	}
+	return i.checkCode(*rec, code, true)
+}
+
+func (i impl) CreateChallenge(kind models.AuthUserKind, userID string) (string, error) {
+	claims := jwt.MapClaims{
+		"sub":  userID,
+		"kind": string(kind),
+		"exp":  time.Now().Add(time.Second * time.Duration(config.Conf.TwoFactor.ChallengeExpireSec)).Unix(),
+		"iat":  time.Now().Unix(),
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(config.Conf.TwoFactor.SecretKey))
+}
+
+func (i impl) ParseChallenge(kind models.AuthUserKind, token string) (userID string, err error) {
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(config.Conf.TwoFactor.SecretKey), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return "", errors.New("время входа истекло, повторите вход")
+	}
+	userID, _ = claims["sub"].(string)
+	if claims["kind"] != string(kind) || userID == "" {
+		return "", errors.New("некорректный токен второго шага входа")
+	}
+	return userID, nil
+}
+
+func (i impl) getEnabled(kind models.AuthUserKind, userID string) (*dbmodels.UserTwoFactor, string, error) {
+	rec, err := i.store.GetByUser(kind, userID)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "ошибка получения настроек двухфакторной аутентификации")
+	}
+	if rec == nil || !rec.IsEnabled {
+		return nil, "двухфакторная аутентификация не подключена", nil
+	}
+	return rec, "", nil
+}
+
+// checkCode проверка кода с блокировкой после нескольких неверных попыток подряд
+func (i impl) checkCode(rec dbmodels.UserTwoFactor, code string, allowRecovery bool) (hMsg string, err error) {
+	now := time.Now()
+	if rec.IsLocked(now) {
+		return "слишком много неверных кодов, повторите попытку позже", nil
+	}
+	secret, err := authhelpers.DecryptString(config.Conf.TwoFactor.SecretKey, rec.Secret)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка расшифровки секрета")
+	}
+	if step, ok := authhelpers.ValidateTotp(secret, code, now, rec.LastStep); ok {
+		accepted, err := i.store.UpdateStep(rec.ID, step)
+		if err != nil {
+			return "", errors.Wrap(err, "ошибка сохранения кода")
+		}
+		if accepted {
+			return "", nil
+		}
+	} else if allowRecovery {
+		used, err := i.store.UseRecoveryCode(rec.ID, getRecoveryCodeHash(code))
+		if err != nil {
+			return "", errors.Wrap(err, "ошибка проверки кода восстановления")
+		}
+		if used {
+			log.
+				WithField("user_id", rec.UserID).
+				WithField("user_kind", rec.UserKind).
+				Info("использован код восстановления")
+			return "", nil
+		}
+	}
+
+	attempts, err := i.store.AddFailedAttempt(rec.ID)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка сохранения неверной попытки")
+	}
+	if attempts >= config.Conf.TwoFactor.MaxAttempts {
+		updMap := map[string]interface{}{
+			"failed_attempts": 0,
+			"locked_until":    now.Add(time.Minute * time.Duration(config.Conf.TwoFactor.LockMin)),
+		}
+		err = i.store.Update(rec.ID, updMap)
+		if err != nil {
+			return "", errors.Wrap(err, "ошибка блокировки проверки кодов")
+		}
+		log.
+			WithField("user_id", rec.UserID).
+			WithField("user_kind", rec.UserKind).
+			Warn("проверка кодов заблокирована после неверных попыток")
+	}
+	return "неверный код", nil
+}
+
+// generateRecoveryCodes коды восстановления вида xxxxx-xxxxx и их хеши для хранения
+func generateRecoveryCodes() (codes []string, hashes pq.StringArray, err error) {
+	for len(codes) < recoveryCodesCount {
+		value := make([]byte, 5)
+		if _, err = rand.Read(value); err != nil {
+			return nil, nil, errors.Wrap(err, "ошибка генерации кодов восстановления")
+		}
+		code := hex.EncodeToString(value)
+		code = fmt.Sprintf("%s-%s", code[:5], code[5:])
+		codes = append(codes, code)
+		hashes = append(hashes, getRecoveryCodeHash(code))
+	}
+	return codes, hashes, nil
+}
+
+func getRecoveryCodeHash(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	code = strings.ReplaceAll(code, "-", "")
+	code = strings.ReplaceAll(code, " ", "")
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
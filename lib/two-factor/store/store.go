@@ -0,0 +1,116 @@
+package twofactorstore
+
+import (
+	"hr-tools-backend/models"
+	dbmodels "hr-tools-backend/models/db"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Provider interface {
+	Create(rec dbmodels.UserTwoFactor) (id string, err error)
+	GetByUser(kind models.AuthUserKind, userID string) (*dbmodels.UserTwoFactor, error)
+	Update(id string, updMap map[string]interface{}) error
+	// UpdateStep фиксация принятого интервала TOTP, false - код этого интервала уже принят другим запросом
+	UpdateStep(id string, step int64) (bool, error)
+	// UseRecoveryCode удаление использованного кода восстановления, false - код уже использован
+	UseRecoveryCode(id, codeHash string) (bool, error)
+	// AddFailedAttempt увеличение счетчика неверных кодов, возвращает новое значение
+	AddFailedAttempt(id string) (int, error)
+}
+
+func NewInstance(DB *gorm.DB) Provider {
+	return &impl{
+		db: DB,
+	}
+}
+
+type impl struct {
+	db *gorm.DB
+}
+
+func (i impl) Create(rec dbmodels.UserTwoFactor) (id string, err error) {
+	err = i.db.
+		Omit(clause.Associations).
+		Save(&rec).
+		Error
+	if err != nil {
+		return "", err
+	}
+	return rec.ID, nil
+}
+
+func (i impl) GetByUser(kind models.AuthUserKind, userID string) (*dbmodels.UserTwoFactor, error) {
+	rec := dbmodels.UserTwoFactor{}
+	err := i.db.
+		Where("user_kind = ?", kind).
+		Where("user_id = ?", userID).
+		First(&rec).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (i impl) Update(id string, updMap map[string]interface{}) error {
+	if len(updMap) == 0 {
+		return nil
+	}
+	return i.db.
+		Model(&dbmodels.UserTwoFactor{}).
+		Where("id = ?", id).
+		Updates(updMap).
+		Error
+}
+
+func (i impl) UpdateStep(id string, step int64) (bool, error) {
+	tx := i.db.
+		Model(&dbmodels.UserTwoFactor{}).
+		Where("id = ?", id).
+		Where("last_step < ?", step).
+		Updates(map[string]interface{}{
+			"last_step":       step,
+			"failed_attempts": 0,
+			"locked_until":    nil,
+		})
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+	return tx.RowsAffected == 1, nil
+}
+
+func (i impl) UseRecoveryCode(id, codeHash string) (bool, error) {
+	tx := i.db.
+		Model(&dbmodels.UserTwoFactor{}).
+		Where("id = ?", id).
+		Where("? = ANY(recovery_codes)", codeHash).
+		Updates(map[string]interface{}{
+			"recovery_codes":  gorm.Expr("array_remove(recovery_codes, ?)", codeHash),
+			"failed_attempts": 0,
+			"locked_until":    nil,
+		})
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+	return tx.RowsAffected == 1, nil
+}
+
+func (i impl) AddFailedAttempt(id string) (int, error) {
+	rec := dbmodels.UserTwoFactor{}
+	err := i.db.
+		Model(&rec).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "failed_attempts"}}}).
+		Where("id = ?", id).
+		UpdateColumn("failed_attempts", gorm.Expr("failed_attempts + 1")).
+		Error
+	if err != nil {
+		return 0, err
+	}
+	return rec.FailedAttempts, nil
+}
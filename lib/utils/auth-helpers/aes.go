@@ -0,0 +1,66 @@
+package authhelpers
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// EncryptString шифрование строки AES-GCM, ключ шифрования - sha256 от secret
+func EncryptString(secret, text string) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+	gcm, err := newGCM(secret)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", errors.Wrap(err, "ошибка генерации nonce")
+	}
+	data := gcm.Seal(nonce, nonce, []byte(text), nil)
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// DecryptString расшифровка строки, зашифрованной EncryptString
+func DecryptString(secret, text string) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+	data, err := base64.StdEncoding.DecodeString(text)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка декодирования зашифрованной строки")
+	}
+	gcm, err := newGCM(secret)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < gcm.NonceSize() {
+		return "", errors.New("некорректная зашифрованная строка")
+	}
+	nonce, body := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	result, err := gcm.Open(nil, nonce, body, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка расшифровки строки")
+	}
+	return string(result), nil
+}
+
+func newGCM(secret string) (cipher.AEAD, error) {
+	key := sha256.Sum256([]byte(secret))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка инициализации шифрования")
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка инициализации шифрования")
+	}
+	return gcm, nil
+}
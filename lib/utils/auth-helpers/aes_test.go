@@ -0,0 +1,24 @@
+package authhelpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptString(t *testing.T) {
+	encrypted, err := EncryptString("key", "пароль")
+	require.NoError(t, err)
+	require.NotContains(t, encrypted, "пароль")
+
+	decrypted, err := DecryptString("key", encrypted)
+	require.NoError(t, err)
+	require.Equal(t, "пароль", decrypted)
+
+	_, err = DecryptString("other-key", encrypted)
+	require.Error(t, err)
+
+	encrypted, err = EncryptString("key", "")
+	require.NoError(t, err)
+	require.Empty(t, encrypted)
+}
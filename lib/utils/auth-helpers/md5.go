@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 )
 
+// GetMD5Hash хеш MD5 без соли, которым пароли хранились до перехода на Argon2id, используется только для их проверки
 func GetMD5Hash(text string) string {
 	hash := md5.Sum([]byte(text))
 	return hex.EncodeToString(hash[:])
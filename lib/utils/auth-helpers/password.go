@@ -0,0 +1,112 @@
+package authhelpers
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/argon2"
+)
+
+// параметры Argon2id (рекомендация OWASP), при изменении хеши пересчитываются при следующем входе
+const (
+	argon2Memory  uint32 = 19 * 1024
+	argon2Time    uint32 = 2
+	argon2Threads uint8  = 1
+	argon2KeyLen  uint32 = 32
+	argon2SaltLen        = 16
+)
+
+const argon2Prefix = "$argon2id$"
+
+var passwordEncoding = base64.RawStdEncoding
+
+// HashPassword хеш пароля в формате PHC: $argon2id$v=19$m=...,t=...,p=...$salt$hash
+func HashPassword(password string) (string, error) {
+	salt := make([]byte, argon2SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", errors.Wrap(err, "ошибка генерации соли")
+	}
+	key := argon2.IDKey([]byte(password), salt, argon2Time, argon2Memory, argon2Threads, argon2KeyLen)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2Prefix, argon2.Version, argon2Memory, argon2Time, argon2Threads,
+		passwordEncoding.EncodeToString(salt), passwordEncoding.EncodeToString(key)), nil
+}
+
+// CheckPassword проверка пароля по хешу.
+// needRehash - хеш устаревшего формата (MD5) или с другими параметрами, его следует пересчитать через HashPassword
+func CheckPassword(password, hash string) (ok, needRehash bool) {
+	if strings.HasPrefix(hash, argon2Prefix) {
+		params, salt, key, err := parseArgon2Hash(hash)
+		if err != nil {
+			return false, false
+		}
+		calculated := argon2.IDKey([]byte(password), salt, params.time, params.memory, params.threads, uint32(len(key)))
+		if subtle.ConstantTimeCompare(calculated, key) != 1 {
+			return false, false
+		}
+		return true, params != currentArgon2Params()
+	}
+	if isLegacyHash(hash) {
+		ok = subtle.ConstantTimeCompare([]byte(GetMD5Hash(password)), []byte(strings.ToLower(hash))) == 1
+		return ok, ok
+	}
+	return false, false
+}
+
+type argon2Params struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+	keyLen  uint32
+}
+
+func currentArgon2Params() argon2Params {
+	return argon2Params{
+		memory:  argon2Memory,
+		time:    argon2Time,
+		threads: argon2Threads,
+		keyLen:  argon2KeyLen,
+	}
+}
+
+func parseArgon2Hash(hash string) (params argon2Params, salt, key []byte, err error) {
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, hash
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return params, nil, nil, errors.New("некорректный формат хеша")
+	}
+	var version int
+	if _, err = fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, errors.New("неподдерживаемая версия argon2")
+	}
+	if _, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.time, &params.threads); err != nil {
+		return params, nil, nil, errors.Wrap(err, "некорректные параметры хеша")
+	}
+	if params.memory == 0 || params.time == 0 || params.threads == 0 {
+		return params, nil, nil, errors.New("некорректные параметры хеша")
+	}
+	salt, err = passwordEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, errors.Wrap(err, "некорректная соль")
+	}
+	key, err = passwordEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, errors.New("некорректный хеш")
+	}
+	params.keyLen = uint32(len(key))
+	return params, salt, key, nil
+}
+
+// isLegacyHash хеш MD5 без соли, которым пароли хранились ранее
+func isLegacyHash(hash string) bool {
+	if len(hash) != 32 {
+		return false
+	}
+	_, err := hex.DecodeString(hash)
+	return err == nil
+}
@@ -0,0 +1,68 @@
+package authhelpers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/argon2"
+)
+
+func TestCheckPassword(t *testing.T) {
+	hash, err := HashPassword("Secret-123")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=19456,t=2,p=1$"))
+	require.LessOrEqual(t, len(hash), 128)
+
+	ok, needRehash := CheckPassword("Secret-123", hash)
+	require.True(t, ok)
+	require.False(t, needRehash)
+	ok, _ = CheckPassword("Secret-124", hash)
+	require.False(t, ok)
+
+	t.Run("unique salt", func(t *testing.T) {
+		other, err := HashPassword("Secret-123")
+		require.NoError(t, err)
+		require.NotEqual(t, hash, other)
+	})
+
+	t.Run("legacy md5", func(t *testing.T) {
+		legacy := GetMD5Hash("Secret-123")
+		ok, needRehash := CheckPassword("Secret-123", legacy)
+		require.True(t, ok)
+		require.True(t, needRehash)
+		ok, needRehash = CheckPassword("Secret-123", strings.ToUpper(legacy))
+		require.True(t, ok)
+		require.True(t, needRehash)
+		ok, needRehash = CheckPassword("Secret-124", legacy)
+		require.False(t, ok)
+		require.False(t, needRehash)
+	})
+
+	t.Run("outdated params", func(t *testing.T) {
+		salt := []byte("0123456789abcdef")
+		key := argon2.IDKey([]byte("Secret-123"), salt, 1, 8*1024, 1, 32)
+		outdated := "$argon2id$v=19$m=8192,t=1,p=1$" + passwordEncoding.EncodeToString(salt) + "$" + passwordEncoding.EncodeToString(key)
+		ok, needRehash := CheckPassword("Secret-123", outdated)
+		require.True(t, ok)
+		require.True(t, needRehash)
+
+		ok, _ = CheckPassword("Secret-123", strings.Replace(outdated, "t=1", "t=2", 1))
+		require.False(t, ok, "параметры входят в вычисление хеша")
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		for _, value := range []string{
+			"",
+			"Secret-123",
+			"$argon2id$v=19$m=19456,t=2,p=1$",
+			"$argon2id$v=18$m=19456,t=2,p=1$c2FsdA$aGFzaA",
+			"$argon2id$v=19$m=0,t=2,p=1$c2FsdA$aGFzaA",
+			"$argon2id$v=19$m=19456,t=2,p=1$!!$aGFzaA",
+		} {
+			ok, needRehash := CheckPassword("Secret-123", value)
+			require.False(t, ok, value)
+			require.False(t, needRehash, value)
+		}
+	})
+}
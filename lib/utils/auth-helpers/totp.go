@@ -0,0 +1,84 @@
+package authhelpers
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// TOTP по RFC 6238 с параметрами, которые поддерживают все приложения-аутентификаторы:
+// HMAC-SHA1, 6 цифр, интервал 30 секунд
+const (
+	totpPeriod = 30
+	totpDigits = 6
+	// допустимое расхождение часов клиента - один интервал в каждую сторону
+	totpSkew = 1
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateTotpSecret новый секрет TOTP в base32
+func GenerateTotpSecret() (string, error) {
+	secret := make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		return "", errors.Wrap(err, "ошибка генерации секрета")
+	}
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+// GetTotpURI ссылка otpauth:// для QR кода приложения-аутентификатора
+func GetTotpURI(issuer, account, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(totpPeriod))
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(account)
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// ValidateTotp проверка кода, возвращает принятый интервал.
+// Коды интервалов не позже lastStep не принимаются, чтобы перехваченный код нельзя было использовать повторно
+func ValidateTotp(secret, code string, now time.Time, lastStep int64) (step int64, ok bool) {
+	code = strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+	if len(code) != totpDigits {
+		return 0, false
+	}
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return 0, false
+	}
+	current := now.Unix() / totpPeriod
+	for i := int64(-totpSkew); i <= totpSkew; i++ {
+		step = current + i
+		if step <= lastStep {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(getTotpCode(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+func getTotpCode(key []byte, step int64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+	// динамическое усечение, RFC 4226 п. 5.3
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000)
+}
@@ -0,0 +1,65 @@
+package authhelpers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTotp(t *testing.T) {
+	// тестовые значения RFC 6238 (SHA1), секрет "12345678901234567890", последние 6 цифр
+	secret := totpEncoding.EncodeToString([]byte("12345678901234567890"))
+	vectors := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+	for unix, code := range vectors {
+		step, ok := ValidateTotp(secret, code, time.Unix(unix, 0), 0)
+		require.True(t, ok, unix)
+		require.Equal(t, unix/totpPeriod, step)
+	}
+
+	now := time.Unix(1234567890, 0)
+	t.Run("clock skew", func(t *testing.T) {
+		_, ok := ValidateTotp(secret, "005924", now.Add(totpPeriod*time.Second), 0)
+		require.True(t, ok)
+		_, ok = ValidateTotp(secret, "005924", now.Add(3*totpPeriod*time.Second), 0)
+		require.False(t, ok)
+	})
+
+	t.Run("replay", func(t *testing.T) {
+		step, ok := ValidateTotp(secret, "005924", now, 0)
+		require.True(t, ok)
+		_, ok = ValidateTotp(secret, "005924", now, step)
+		require.False(t, ok)
+	})
+
+	t.Run("invalid code", func(t *testing.T) {
+		_, ok := ValidateTotp(secret, "005925", now, 0)
+		require.False(t, ok)
+		_, ok = ValidateTotp(secret, "5924", now, 0)
+		require.False(t, ok)
+		_, ok = ValidateTotp("not base32!", "005924", now, 0)
+		require.False(t, ok)
+	})
+}
+
+func TestGenerateTotpSecret(t *testing.T) {
+	secret, err := GenerateTotpSecret()
+	require.NoError(t, err)
+	require.Len(t, secret, 32)
+
+	step := time.Now().Unix() / totpPeriod
+	key, err := totpEncoding.DecodeString(secret)
+	require.NoError(t, err)
+	_, ok := ValidateTotp(secret, getTotpCode(key, step), time.Now(), 0)
+	require.True(t, ok)
+
+	uri := GetTotpURI("HR-Tools", "ivan@example.com", secret)
+	require.True(t, strings.HasPrefix(uri, "otpauth://totp/HR-Tools:ivan@example.com?"))
+	require.Contains(t, uri, "secret="+secret)
+}
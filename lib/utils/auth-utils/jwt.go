@@ -8,13 +8,15 @@ import (
 	"time"
 )
 
-func GetToken(userID, name, spaceID string, isAdmin bool, role models.UserRole) (tokenString string, err error) {
+// GetToken JWT сотрудника спейса, sessionID - сессия входа, токен действует, пока она не отозвана
+func GetToken(userID, name, spaceID string, isAdmin bool, role models.UserRole, sessionID string) (tokenString string, err error) {
 	claims := jwt.MapClaims{
 		"name":  name,
 		"sub":   userID,
 		"space": spaceID,
 		"admin": isAdmin,
 		"role":  string(role),
+		"sid":   sessionID,
 		"exp":   time.Now().Add(time.Second * time.Duration(config.Conf.Auth.JWTExpireInSec)).Unix(),
 		"iat":   time.Now().Unix(),
 	}
@@ -22,15 +24,34 @@ func GetToken(userID, name, spaceID string, isAdmin bool, role models.UserRole)
 	return token.SignedString([]byte(config.Conf.Auth.JWTSecret))
 }
 
-func GetRefreshToken(userID, name string) (tokenString string, err error) {
+// GetAdminPanelToken JWT пользователя админки
+func GetAdminPanelToken(userID, name string, role models.UserRole, sessionID string) (tokenString string, err error) {
 	claims := jwt.MapClaims{
 		"name": name,
 		"sub":  userID,
-		"exp":  time.Now().Add(time.Second * time.Duration(config.Conf.Auth.JWTRefreshExpireInSec)).Unix(),
+		"role": role,
+		"sid":  sessionID,
+		"exp":  time.Now().Add(time.Second * time.Duration(config.Conf.AdminPanelAuth.JWTExpireInSec)).Unix(),
 		"iat":  time.Now().Unix(),
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(config.Conf.Auth.JWTSecret))
+	return token.SignedString([]byte(config.Conf.AdminPanelAuth.JWTSecret))
+}
+
+// GetApiKeyToken токен запроса по API ключу, заполняется теми же данными, что и JWT пользователя
+func GetApiKeyToken(userID, name, spaceID string, role models.UserRole, apiKeyID string) *jwt.Token {
+	claims := jwt.MapClaims{
+		"name":    name,
+		"sub":     userID,
+		"space":   spaceID,
+		"admin":   role.IsSpaceAdmin(),
+		"role":    string(role),
+		"api_key": apiKeyID,
+	}
+	return &jwt.Token{
+		Claims: claims,
+		Valid:  true,
+	}
 }
 
 func GetClaims(ctx *fiber.Ctx) jwt.MapClaims {
@@ -40,3 +61,12 @@ func GetClaims(ctx *fiber.Ctx) jwt.MapClaims {
 	}
 	return token.Claims.(jwt.MapClaims)
 }
+
+// GetSessionID сессия входа, к которой относится токен запроса
+func GetSessionID(ctx *fiber.Ctx) string {
+	claims := GetClaims(ctx)
+	if sessionID, ok := claims["sid"].(string); ok {
+		return sessionID
+	}
+	return ""
+}
@@ -0,0 +1,116 @@
+package safehttp
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	resolveTimeout  = 5 * time.Second
+	DeniedAddrMsg   = "адрес указывает на внутреннюю сеть"
+	unresolvableMsg = "не удалось определить адрес"
+	invalidUrlMsg   = "некорректный адрес"
+)
+
+// ErrDeniedAddress попытка соединения с адресом внутренней сети
+var ErrDeniedAddress = errors.New(DeniedAddrMsg)
+
+// deniedNetworks сети, не входящие в net.IP.IsPrivate/IsLoopback/IsLinkLocal*, но недоступные извне
+var deniedNetworks = parseNetworks(
+	"0.0.0.0/8",     // текущая сеть
+	"100.64.0.0/10", // CGNAT
+	"192.0.0.0/24",  // служебные адреса IETF
+	"198.18.0.0/15", // тестирование производительности сетей
+	"240.0.0.0/4",   // зарезервировано
+)
+
+func parseNetworks(cidrs ...string) []*net.IPNet {
+	result := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err.Error())
+		}
+		result = append(result, network)
+	}
+	return result
+}
+
+// IsDeniedIP адрес внутренней сети: loopback, частные сети, link-local (в т.ч. метаданные облака 169.254.169.254)
+func IsDeniedIP(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return true
+	}
+	for _, network := range deniedNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckUrl проверка адреса, указанного пользователем, при сохранении: допускаются только http(s) адреса,
+// все адреса хоста должны быть внешними
+func CheckUrl(ctx context.Context, rawUrl string) (hMsg string, err error) {
+	u, err := url.Parse(rawUrl)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return invalidUrlMsg, nil
+	}
+	host := u.Hostname()
+	if ip := net.ParseIP(host); ip != nil {
+		if IsDeniedIP(ip) {
+			return DeniedAddrMsg, nil
+		}
+		return "", nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, resolveTimeout)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		return unresolvableMsg, nil
+	}
+	for _, addr := range addrs {
+		if IsDeniedIP(addr.IP) {
+			return DeniedAddrMsg, nil
+		}
+	}
+	return "", nil
+}
+
+// NewClient http клиент, не устанавливающий соединения с адресами внутренней сети.
+// Проверка выполняется при подключении, поэтому учитывает перенаправления и смену DNS записи после сохранения адреса
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || IsDeniedIP(ip) {
+				return ErrDeniedAddress
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
@@ -0,0 +1,52 @@
+package safehttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckUrl(t *testing.T) {
+	for _, rawUrl := range []string{
+		"http://127.0.0.1:8080/hook",
+		"http://localhost/hook",
+		"http://10.1.2.3/hook",
+		"http://192.168.0.1/hook",
+		"http://172.16.0.1/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://100.64.0.1/hook",
+		"http://0.0.0.0/hook",
+		"http://[::1]/hook",
+		"http://[fd00::1]/hook",
+		"http://[::ffff:127.0.0.1]/hook",
+	} {
+		hMsg, err := CheckUrl(context.Background(), rawUrl)
+		require.NoError(t, err)
+		require.NotEmptyf(t, hMsg, "адрес %v должен быть отклонен", rawUrl)
+	}
+	hMsg, err := CheckUrl(context.Background(), "https://8.8.8.8/hook")
+	require.NoError(t, err)
+	require.Empty(t, hMsg)
+}
+
+func TestCheckUrlScheme(t *testing.T) {
+	for _, rawUrl := range []string{"ftp://8.8.8.8/file", "file:///etc/passwd", "gopher://8.8.8.8", "8.8.8.8/hook"} {
+		hMsg, err := CheckUrl(context.Background(), rawUrl)
+		require.NoError(t, err)
+		require.NotEmptyf(t, hMsg, "адрес %v должен быть отклонен", rawUrl)
+	}
+}
+
+func TestClientDeniesInternalAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	_, err := NewClient(time.Second).Get(server.URL)
+	require.ErrorIs(t, err, ErrDeniedAddress)
+}
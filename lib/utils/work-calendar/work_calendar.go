@@ -0,0 +1,95 @@
+package workcalendar
+
+import (
+	"hr-tools-backend/models"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const dateLayout = "02.01.2006"
+
+// Calendar производственный календарь: суббота и воскресенье выходные,
+// праздничные дни и перенесенные рабочие дни задаются в настройках спейса
+type Calendar struct {
+	holidays map[string]bool
+	workdays map[string]bool
+}
+
+// New календарь по спискам дат ДД.ММ.ГГГГ через запятую
+func New(holidays, workdays string) (Calendar, error) {
+	result := Calendar{}
+	var err error
+	result.holidays, err = parseDates(holidays)
+	if err != nil {
+		return Calendar{}, errors.Wrap(err, "некорректный список праздничных дней")
+	}
+	result.workdays, err = parseDates(workdays)
+	if err != nil {
+		return Calendar{}, errors.Wrap(err, "некорректный список перенесенных рабочих дней")
+	}
+	return result, nil
+}
+
+func (c Calendar) IsWorkday(t time.Time) bool {
+	key := t.Format(dateLayout)
+	if c.workdays[key] {
+		return true
+	}
+	if c.holidays[key] {
+		return false
+	}
+	weekday := t.Weekday()
+	return weekday != time.Saturday && weekday != time.Sunday
+}
+
+// AddLimit срок окончания лимита, отсчитанного от start без учета нерабочих дней.
+// День лимита - сутки рабочего дня, неделя - 5 рабочих дней
+func (c Calendar) AddLimit(start time.Time, value int64, limitType models.LimitType) time.Time {
+	var duration time.Duration
+	switch limitType {
+	case models.LimitTypeMin:
+		duration = time.Duration(value) * time.Minute
+	case models.LimitTypeHour:
+		duration = time.Duration(value) * time.Hour
+	case models.LimitTypDay:
+		duration = time.Duration(value) * 24 * time.Hour
+	case models.LimitTypeWeek:
+		duration = time.Duration(value) * 5 * 24 * time.Hour
+	}
+	return c.Add(start, duration)
+}
+
+// Add прибавление длительности, время нерабочих дней не учитывается
+func (c Calendar) Add(start time.Time, duration time.Duration) time.Time {
+	current := start
+	for duration > 0 {
+		dayEnd := time.Date(current.Year(), current.Month(), current.Day()+1, 0, 0, 0, 0, current.Location())
+		if c.IsWorkday(current) {
+			available := dayEnd.Sub(current)
+			if duration <= available {
+				return current.Add(duration)
+			}
+			duration -= available
+		}
+		current = dayEnd
+	}
+	return current
+}
+
+func parseDates(value string) (map[string]bool, error) {
+	result := map[string]bool{}
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		date, err := time.Parse(dateLayout, item)
+		if err != nil {
+			return nil, errors.Errorf("дата %v", item)
+		}
+		result[date.Format(dateLayout)] = true
+	}
+	return result, nil
+}
@@ -0,0 +1,38 @@
+package workcalendar
+
+import (
+	"hr-tools-backend/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddLimit(t *testing.T) {
+	calendar, err := New("", "")
+	require.NoError(t, err)
+	// пятница 15:00 + 1 день = понедельник 15:00
+	friday := time.Date(2025, 3, 7, 15, 0, 0, 0, time.UTC)
+	require.Equal(t, time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC), calendar.AddLimit(friday, 1, models.LimitTypDay))
+	// пятница 23:00 + 2 часа = понедельник 01:00
+	require.Equal(t, time.Date(2025, 3, 10, 1, 0, 0, 0, time.UTC),
+		calendar.AddLimit(time.Date(2025, 3, 7, 23, 0, 0, 0, time.UTC), 2, models.LimitTypeHour))
+	// начало в выходной отсчитывается с понедельника
+	saturday := time.Date(2025, 3, 8, 12, 0, 0, 0, time.UTC)
+	require.Equal(t, time.Date(2025, 3, 10, 0, 30, 0, 0, time.UTC), calendar.AddLimit(saturday, 30, models.LimitTypeMin))
+	// неделя - 5 рабочих дней
+	require.Equal(t, time.Date(2025, 3, 14, 15, 0, 0, 0, time.UTC), calendar.AddLimit(friday, 1, models.LimitTypeWeek))
+}
+
+func TestHolidays(t *testing.T) {
+	// 10.03 - праздник, 08.03 (суббота) - рабочий день
+	calendar, err := New("10.03.2025", "08.03.2025")
+	require.NoError(t, err)
+	require.True(t, calendar.IsWorkday(time.Date(2025, 3, 8, 0, 0, 0, 0, time.UTC)))
+	require.False(t, calendar.IsWorkday(time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)))
+	friday := time.Date(2025, 3, 7, 15, 0, 0, 0, time.UTC)
+	require.Equal(t, time.Date(2025, 3, 11, 15, 0, 0, 0, time.UTC), calendar.AddLimit(friday, 2, models.LimitTypDay))
+
+	_, err = New("2025-03-10", "")
+	require.Error(t, err)
+}
@@ -0,0 +1,46 @@
+package vacancyreqescalationworker
+
+import (
+	"context"
+	"hr-tools-backend/db"
+	approvaltaskstore "hr-tools-backend/lib/aproval-task/store"
+	baseworker "hr-tools-backend/lib/utils/base-worker"
+	"hr-tools-backend/lib/utils/helpers"
+	vacancyreqhandler "hr-tools-backend/lib/vacancy-req"
+	"time"
+)
+
+// Задача эскалации просроченных задач согласования заявок
+func StartWorker(ctx context.Context) {
+	i := &impl{
+		BaseImpl: *baseworker.NewInstance("VacancyRequestEscalationWorker", 30*time.Second, 5*time.Minute),
+		store:    approvaltaskstore.NewInstance(db.DB),
+	}
+	go i.Run(ctx, i.handle)
+}
+
+type impl struct {
+	baseworker.BaseImpl
+	store approvaltaskstore.Provider
+}
+
+func (i impl) handle(ctx context.Context) {
+	logger := i.GetLogger()
+	list, err := i.store.ListOverdue(time.Now())
+	if err != nil {
+		logger.WithError(err).Error("ошибка получения списка просроченных задач согласования")
+		return
+	}
+	for _, task := range list {
+		if helpers.IsContextDone(ctx) {
+			break
+		}
+		err = vacancyreqhandler.Instance.EscalateApproval(task)
+		if err != nil {
+			logger.
+				WithError(err).
+				WithField("task_id", task.ID).
+				Error("ошибка эскалации задачи согласования")
+		}
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"hr-tools-backend/db"
 	aprovaltaskhandler "hr-tools-backend/lib/aproval-task"
 	approvaltaskstore "hr-tools-backend/lib/aproval-task/store"
+	customfield "hr-tools-backend/lib/custom-field"
+	datascope "hr-tools-backend/lib/data-scope"
 	citystore "hr-tools-backend/lib/dicts/city/store"
 	companyprovider "hr-tools-backend/lib/dicts/company"
 	companystructprovider "hr-tools-backend/lib/dicts/company-struct"
@@ -16,6 +18,7 @@ import (
 	initchecker "hr-tools-backend/lib/utils/init-checker"
 	vacancyhandler "hr-tools-backend/lib/vacancy"
 	vacancyreqstore "hr-tools-backend/lib/vacancy-req/store"
+	webhookevent "hr-tools-backend/lib/webhook/event"
 	"hr-tools-backend/models"
 	vacancyapimodels "hr-tools-backend/models/api/vacancy"
 	dbmodels "hr-tools-backend/models/db"
@@ -30,8 +33,8 @@ import (
 
 type Provider interface {
 	Create(spaceID, userID string, data vacancyapimodels.VacancyRequestCreateData) (id, hMsg string, err error)
-	GetByID(spaceID, id string) (item vacancyapimodels.VacancyRequestView, err error)
-	Update(spaceID, id string, data vacancyapimodels.VacancyRequestEditData) (hMsg string, err error)
+	GetByID(spaceID, id, userID string) (item vacancyapimodels.VacancyRequestView, err error)
+	Update(spaceID, id, userID string, data vacancyapimodels.VacancyRequestEditData) (hMsg string, err error)
 	Delete(spaceID, id string) error
 	List(spaceID, userID string, filter vacancyapimodels.VrFilter) (list []vacancyapimodels.VacancyRequestView, rowCount int64, err error)
 	ChangeStatus(spaceID, id, userID string, status models.VRStatus) (hMsh string, err error)
@@ -40,6 +43,8 @@ type Provider interface {
 	ToFavorite(id, userID string, isSet bool) error
 	AddComment(spaceID, id string, data vacancyapimodels.Comment) error
 	//согласование заявок
+	ApplyRoute(spaceID, id string, data vacancyapimodels.ApplyRouteRequest) (hMsg string, err error)
+	EscalateApproval(task dbmodels.ApprovalTask) error
 	Approve(spaceID, requestID, taskID, userID string) (hMsh string, err error)
 	RequestChanges(spaceID, requestID, taskID, userID string, data vacancyapimodels.ApprovalRequestChanges) (hMsh string, err error)
 	Reject(spaceID, requestID, taskID, userID string, data vacancyapimodels.ApprovalReject) (hMsh string, err error)
@@ -133,6 +138,10 @@ func (i impl) Create(spaceID, userID string, data vacancyapimodels.VacancyReques
 	if err != nil {
 		return "", "", err
 	}
+	customFields, hMsg, err := customfield.Instance.PrepareValues(spaceID, userID, models.CustomFieldEntityVacancyRequest, data.CustomFields, nil)
+	if err != nil || hMsg != "" {
+		return "", hMsg, err
+	}
 	rec := dbmodels.VacancyRequest{
 		BaseSpaceModel: dbmodels.BaseSpaceModel{
 			SpaceID: spaceID,
@@ -155,6 +164,8 @@ func (i impl) Create(spaceID, userID string, data vacancyapimodels.VacancyReques
 		Employment:      data.Employment,
 		Experience:      data.Experience,
 		Schedule:        data.Schedule,
+		Salary:          data.Salary,
+		CustomFields:    customFields,
 	}
 	if data.AsTemplate {
 		rec.Status = models.VRStatusDraft
@@ -189,6 +200,18 @@ func (i impl) Create(spaceID, userID string, data vacancyapimodels.VacancyReques
 		if err != nil {
 			return err
 		}
+		if len(data.ApprovalTasks.ApprovalTasks) == 0 && !data.AsTemplate {
+			// цепочка не указана, формируем по подходящему маршруту согласования
+			rec.ID = id
+			routeMsg, err := aprovalStagesHandler.ApplyRoute(spaceID, rec, "")
+			if err != nil {
+				return err
+			}
+			if routeMsg != "" {
+				logger.WithField("rec_id", id).Info(routeMsg)
+			}
+			return nil
+		}
 		hMsg, err = aprovalStagesHandler.Save(spaceID, id, data.ApprovalTasks.ApprovalTasks)
 		return err
 	})
@@ -204,17 +227,31 @@ func (i impl) Create(spaceID, userID string, data vacancyapimodels.VacancyReques
 	return id, "", nil
 }
 
-func (i impl) GetByID(spaceID, id string) (item vacancyapimodels.VacancyRequestView, err error) {
+func (i impl) GetByID(spaceID, id, userID string) (item vacancyapimodels.VacancyRequestView, err error) {
 	rec, err := i.getRec(spaceID, id)
 	if err != nil {
 		return vacancyapimodels.VacancyRequestView{}, err
 	}
-	return vacancyapimodels.VacancyRequestConvert(*rec), nil
+	fields, err := customfield.Instance.GetFields(spaceID, userID, models.CustomFieldEntityVacancyRequest)
+	if err != nil {
+		return vacancyapimodels.VacancyRequestView{}, err
+	}
+	result := vacancyapimodels.VacancyRequestConvert(*rec)
+	result.CustomFields = rec.CustomFields.Only(fields)
+	return result, nil
 }
 
-func (i impl) Update(spaceID, id string, data vacancyapimodels.VacancyRequestEditData) (hMsg string, err error) {
+func (i impl) Update(spaceID, id, userID string, data vacancyapimodels.VacancyRequestEditData) (hMsg string, err error) {
 	logger := log.WithField("space_id", spaceID).
 		WithField("rec_id", id)
+	rec, err := i.getRec(spaceID, id)
+	if err != nil {
+		return "", err
+	}
+	data.CustomFields, hMsg, err = customfield.Instance.PrepareValues(spaceID, userID, models.CustomFieldEntityVacancyRequest, data.CustomFields, rec.CustomFields)
+	if err != nil || hMsg != "" {
+		return hMsg, err
+	}
 	err = db.DB.Transaction(func(tx *gorm.DB) error {
 		if data.CompanyID == "" && data.CompanyName != "" {
 			companyID, err := createCompany(tx, spaceID, data.CompanyName)
@@ -257,7 +294,11 @@ func (i impl) Delete(spaceID, id string) error {
 }
 
 func (i impl) List(spaceID, userID string, filter vacancyapimodels.VrFilter) (list []vacancyapimodels.VacancyRequestView, rowCount int64, err error) {
-	rowCount, err = i.store.ListCount(spaceID, userID, filter)
+	scope, err := datascope.Instance.GetScope(spaceID, userID)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "ошибка получения области видимости")
+	}
+	rowCount, err = i.store.ListCount(spaceID, userID, scope, filter)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -268,13 +309,19 @@ func (i impl) List(spaceID, userID string, filter vacancyapimodels.VrFilter) (li
 		return []vacancyapimodels.VacancyRequestView{}, rowCount, nil
 	}
 
-	recList, err := i.store.List(spaceID, userID, filter)
+	recList, err := i.store.List(spaceID, userID, scope, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	fields, err := customfield.Instance.GetFields(spaceID, userID, models.CustomFieldEntityVacancyRequest)
 	if err != nil {
 		return nil, 0, err
 	}
 	result := make([]vacancyapimodels.VacancyRequestView, 0, len(recList))
 	for _, rec := range recList {
-		result = append(result, vacancyapimodels.VacancyRequestConvert(rec))
+		item := vacancyapimodels.VacancyRequestConvert(rec)
+		item.CustomFields = rec.CustomFields.Only(fields)
+		result = append(result, item)
 	}
 	return result, rowCount, nil
 }
@@ -295,6 +342,7 @@ func (i impl) ChangeStatus(spaceID, id, userID string, status models.VRStatus) (
 	if oldStatus == status {
 		return "", nil
 	}
+	var activated []dbmodels.ApprovalTask
 	err = db.DB.Transaction(func(tx *gorm.DB) error {
 		store := vacancyreqstore.NewInstance(tx)
 		updMap := map[string]any{
@@ -305,21 +353,11 @@ func (i impl) ChangeStatus(spaceID, id, userID string, status models.VRStatus) (
 			return err
 		}
 		if status == models.VRStatusInApproval {
-			// Все предыдущие решения согласующих аннулируются
-			approvalTaskStore := approvaltaskstore.NewInstance(tx)
-			taskList, err := approvalTaskStore.List(spaceID, id)
+			// Все предыдущие решения согласующих аннулируются, согласование начинается с первого шага
+			activated, err = aprovaltaskhandler.NewHandlerWithTx(tx).Start(spaceID, id)
 			if err != nil {
 				return err
 			}
-			taskUpdMap := map[string]interface{}{
-				"State":     models.AStatePending,
-				"Comment":   "",
-				"DecidedAt": nil,
-			}
-
-			for _, task := range taskList {
-				approvalTaskStore.Update(spaceID, task.ID, taskUpdMap)
-			}
 		}
 		return nil
 	})
@@ -327,6 +365,9 @@ func (i impl) ChangeStatus(spaceID, id, userID string, status models.VRStatus) (
 		return "", err
 	}
 	logger.Info("статус заявки обновлен")
+	if len(activated) != 0 {
+		go i.sendApprovalTasks(*rec, activated)
+	}
 	if status == models.VRStatusCancelled {
 		err = i.cancelVacancies(spaceID, id, userID)
 		if err != nil {
@@ -375,38 +416,15 @@ func (i impl) Approve(spaceID, requestID, taskID, userID string) (hMsh string, e
 		return fmt.Sprintf("невозможно согласовать заявку в текущем статусе: %v", rec.Status), nil
 	}
 
+	var activated []dbmodels.ApprovalTask
+	var completed bool
 	err = db.DB.Transaction(func(tx *gorm.DB) error {
-		aprovalStagesHandler := aprovaltaskhandler.NewHandlerWithTx(tx)
-		//меняем статус задачи согласования
-		updMap := map[string]interface{}{
-			"State":     models.AStateApproved,
-			"Comment":   "",
-			"DecidedAt": nil,
-		}
-		approvalTaskStore := approvaltaskstore.NewInstance(tx)
-		err = approvalTaskStore.Update(spaceID, taskID, updMap)
+		//меняем статус задачи согласования, при наборе кворума шага активируется следующий шаг
+		completed, activated, err = aprovaltaskhandler.NewHandlerWithTx(tx).Approve(*taskRec)
 		if err != nil {
 			return err
 		}
-		if taskRec != nil {
-			// для аудита
-			taskRec.State = models.AStateApproved
-			taskRec.Comment = ""
-			taskRec.DecidedAt = nil
-			aprovalStagesHandler.Audit(*taskRec)
-		}
-		taskList, err := approvalTaskStore.List(spaceID, requestID)
-		if err != nil {
-			return err
-		}
-		allAprove := true
-		for _, task := range taskList {
-			if task.State != models.AStateApproved {
-				allAprove = false
-				break
-			}
-		}
-		if allAprove {
+		if completed {
 			//все согласовали, меняем статус заявки
 			hMsh, err = i.ChangeStatus(spaceID, requestID, userID, models.VRStatusApproved)
 			if err != nil {
@@ -439,6 +457,14 @@ func (i impl) Approve(spaceID, requestID, taskID, userID string) (hMsh string, e
 	if err != nil {
 		return "", err
 	}
+	if completed {
+		webhookevent.Publish(webhookevent.Event{
+			Type:             dbmodels.WebhookEventVacancyReqApproved,
+			SpaceID:          spaceID,
+			VacancyRequestID: requestID,
+			Status:           string(models.VRStatusApproved),
+		})
+	}
 
 	go func(rec dbmodels.VacancyRequest) {
 		code := models.PushVRApproved
@@ -457,9 +483,62 @@ func (i impl) Approve(spaceID, requestID, taskID, userID string) (hMsh string, e
 		notification := models.GetPushVRApproved(rec.VacancyName, user.GetFullName())
 		i.sendNotification(rec, notification)
 	}(*rec)
+	if len(activated) != 0 {
+		go i.sendApprovalTasks(*rec, activated)
+	}
 	return "", nil
 }
 
+func (i impl) ApplyRoute(spaceID, id string, data vacancyapimodels.ApplyRouteRequest) (hMsg string, err error) {
+	rec, err := i.getRec(spaceID, id)
+	if err != nil {
+		return "", err
+	}
+	if rec.Status == models.VRStatusInApproval {
+		return "невозможно изменить цепочку согласования заявки, находящейся на согласовании", nil
+	}
+	hMsg, err = i.aprovalTaskHandler.ApplyRoute(spaceID, *rec, data.RouteID)
+	if err != nil || hMsg != "" {
+		return hMsg, err
+	}
+	log.WithField("space_id", spaceID).
+		WithField("rec_id", id).
+		Info("цепочка согласования заявки сформирована по маршруту")
+	return "", nil
+}
+
+func (i impl) EscalateApproval(task dbmodels.ApprovalTask) error {
+	logger := log.WithField("space_id", task.SpaceID).
+		WithField("rec_id", task.RequestID).
+		WithField("task_id", task.ID)
+	rec, err := i.store.GetByID(task.SpaceID, task.RequestID)
+	if err != nil {
+		return errors.Wrap(err, "ошибка получения заявки")
+	}
+	if rec == nil || rec.Status != models.VRStatusInApproval {
+		// согласование завершено, эскалация не требуется
+		updMap := map[string]interface{}{
+			"EscalatedAt": time.Now(),
+		}
+		return i.approvalTaskStore.Update(task.SpaceID, task.ID, updMap)
+	}
+	newAssigneeID, err := i.aprovalTaskHandler.Escalate(task)
+	if err != nil {
+		return errors.Wrap(err, "ошибка эскалации задачи согласования")
+	}
+	logger.WithField("new_assignee_id", newAssigneeID).Info("истек срок согласования заявки")
+
+	assigneeName := ""
+	if task.AssigneeUser != nil {
+		assigneeName = task.AssigneeUser.GetFullName()
+	}
+	pushhandler.Instance.SendNotification(rec.AuthorID, models.GetPushVRApprovalOverdue(rec.VacancyName, assigneeName).WithEntity(rec.ID))
+	if newAssigneeID != "" {
+		pushhandler.Instance.SendNotification(newAssigneeID, models.GetPushVRApprovalTask(rec.VacancyName).WithEntity(rec.ID))
+	}
+	return nil
+}
+
 func (i impl) RequestChanges(spaceID, requestID, taskID, userID string, data vacancyapimodels.ApprovalRequestChanges) (hMsh string, err error) {
 	rec, taskRec, hMsh, err := i.approvalPrepare(spaceID, requestID, taskID, userID)
 	if hMsh != "" || err != nil {
@@ -593,6 +672,9 @@ func (i impl) approvalPrepare(spaceID, requestID, taskID, userID string) (vrRec
 	if task.AssigneeUserID != userID {
 		return nil, nil, "На данную задачу назначен другой пользователь", nil
 	}
+	if task.State != models.AStatePending {
+		return nil, nil, "Задача не ожидает согласования", nil
+	}
 	return vacancyRequest, task, "", nil
 }
 
@@ -643,6 +725,8 @@ func (i impl) updateVr(store vacancyreqstore.Provider, spaceID, id string, data
 		"Employment":      data.Employment,
 		"Experience":      data.Experience,
 		"Schedule":        data.Schedule,
+		"Salary":          data.Salary,
+		"CustomFields":    data.CustomFields,
 	}
 	err = store.Update(spaceID, id, updMap)
 	if err != nil {
@@ -683,6 +767,8 @@ func (i impl) publish(spaceID, id, userID string) error {
 		Employment:       rec.Employment,
 		Experience:       rec.Experience,
 		Schedule:         rec.Schedule,
+		// в вакансию переносятся значения полей заявки с совпадающим кодом
+		CustomFields: rec.CustomFields,
 	}
 	err = data.Validate(true)
 	if err != nil {
@@ -773,6 +859,7 @@ func createCompany(tx *gorm.DB, spaceID, name string) (string, error) {
 }
 
 func (i impl) sendNotification(rec dbmodels.VacancyRequest, data models.NotificationData) {
+	data = data.WithEntity(rec.ID)
 	//отправляем автору
 	pushhandler.Instance.SendNotification(rec.AuthorID, data)
 	approvalTasks, err := i.approvalTaskStore.List(rec.SpaceID, rec.ID)
@@ -792,6 +879,13 @@ func (i impl) sendNotification(rec dbmodels.VacancyRequest, data models.Notifica
 	}
 }
 
+func (i impl) sendApprovalTasks(rec dbmodels.VacancyRequest, tasks []dbmodels.ApprovalTask) {
+	notification := models.GetPushVRApprovalTask(rec.VacancyName).WithEntity(rec.ID)
+	for _, task := range tasks {
+		pushhandler.Instance.SendNotification(task.AssigneeUserID, notification.WithTask(task.ID))
+	}
+}
+
 func (i impl) GetRbacSelfAllow() models.RbacFunc {
 	return func(spaceID, userID string, role models.UserRole, uri string) bool {
 		recID := extractUriRecID(uri)
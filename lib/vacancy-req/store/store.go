@@ -17,8 +17,10 @@ type Provider interface {
 	GetByID(spaceID, id string) (rec *dbmodels.VacancyRequest, err error)
 	Update(spaceID, id string, updMap map[string]interface{}) error
 	Delete(spaceID, id string) error
-	ListCount(spaceID, userID string, filter vacancyapimodels.VrFilter) (count int64, err error)
-	List(spaceID, userID string, filter vacancyapimodels.VrFilter) (list []dbmodels.VacancyRequest, err error)
+	ListCount(spaceID, userID string, scope *dbmodels.DataScope, filter vacancyapimodels.VrFilter) (count int64, err error)
+	List(spaceID, userID string, scope *dbmodels.DataScope, filter vacancyapimodels.VrFilter) (list []dbmodels.VacancyRequest, err error)
+	// IsVisible заявка видна в области видимости пользователя
+	IsVisible(spaceID, id string, scope *dbmodels.DataScope) (bool, error)
 	SetPin(id, userID string) error
 	RemovePin(id, userID string) error
 	SetFavorite(id, userID string) error
@@ -101,13 +103,14 @@ func (i impl) Delete(spaceID, id string) error {
 	return nil
 }
 
-func (i impl) ListCount(spaceID, userID string, filter vacancyapimodels.VrFilter) (count int64, err error) {
+func (i impl) ListCount(spaceID, userID string, scope *dbmodels.DataScope, filter vacancyapimodels.VrFilter) (count int64, err error) {
 	var rowCount int64
 	tx := i.db.
 		Model(dbmodels.VacancyRequest{}).
 		Joins("left join vr_favorites as f on vacancy_requests.id = f.vacancy_request_id and f.space_user_id = ?", userID).
 		Joins("left join vr_pinneds as p on vacancy_requests.id = p.vacancy_request_id and p.space_user_id = ?", userID).
-		Where("space_id = ?", spaceID)
+		Where("vacancy_requests.space_id = ?", spaceID)
+	i.addScope(tx, scope)
 	i.addFilter(tx, filter)
 	err = tx.Count(&rowCount).Error
 	if err != nil {
@@ -117,14 +120,15 @@ func (i impl) ListCount(spaceID, userID string, filter vacancyapimodels.VrFilter
 	return rowCount, nil
 }
 
-func (i impl) List(spaceID, userID string, filter vacancyapimodels.VrFilter) (list []dbmodels.VacancyRequest, err error) {
+func (i impl) List(spaceID, userID string, scope *dbmodels.DataScope, filter vacancyapimodels.VrFilter) (list []dbmodels.VacancyRequest, err error) {
 	list = []dbmodels.VacancyRequest{}
 	tx := i.db.
 		Model(dbmodels.VacancyRequest{}).
 		Select("vacancy_requests.*, f.selected as favorite, p.selected as pinned").
 		Joins("left join vr_favorites as f on vacancy_requests.id = f.vacancy_request_id and f.space_user_id = ?", userID).
 		Joins("left join vr_pinneds as p on vacancy_requests.id = p.vacancy_request_id and p.space_user_id = ?", userID).
-		Where("space_id = ?", spaceID)
+		Where("vacancy_requests.space_id = ?", spaceID)
+	i.addScope(tx, scope)
 	i.addFilter(tx, filter)
 	page, limit := filter.GetPage()
 	i.setPage(tx, page, limit)
@@ -141,6 +145,18 @@ func (i impl) List(spaceID, userID string, filter vacancyapimodels.VrFilter) (li
 	return list, nil
 }
 
+func (i impl) IsVisible(spaceID, id string, scope *dbmodels.DataScope) (bool, error) {
+	var exists bool
+	tx := i.db.
+		Model(&dbmodels.VacancyRequest{}).
+		Select("count(*) > 0").
+		Where("vacancy_requests.id = ?", id).
+		Where("vacancy_requests.space_id = ?", spaceID)
+	i.addScope(tx, scope)
+	err := tx.Find(&exists).Error
+	return exists, err
+}
+
 func (i impl) SetPin(id, userID string) error {
 	rec := dbmodels.VrPinned{
 		VacancyRequestID: id,
@@ -217,6 +233,12 @@ func (i impl) addSort(tx *gorm.DB, sort vacancyapimodels.VrSort) {
 	}
 }
 
+func (i impl) addScope(tx *gorm.DB, scope *dbmodels.DataScope) {
+	if query, args := scope.VacancyRequestCondition("vacancy_requests"); query != "" {
+		tx.Where(query, args)
+	}
+}
+
 func (i impl) addFilter(tx *gorm.DB, filter vacancyapimodels.VrFilter) {
 	if filter.Favorite {
 		tx = tx.Where("f.selected = true")
@@ -7,14 +7,16 @@ import (
 	applicanthistoryhandler "hr-tools-backend/lib/applicant-history"
 	applicantstore "hr-tools-backend/lib/applicant/store"
 	aprovaltaskhandler "hr-tools-backend/lib/aproval-task"
+	auditlog "hr-tools-backend/lib/audit-log"
+	customfield "hr-tools-backend/lib/custom-field"
+	datascope "hr-tools-backend/lib/data-scope"
 	citystore "hr-tools-backend/lib/dicts/city/store"
 	companyprovider "hr-tools-backend/lib/dicts/company"
 	companystructprovider "hr-tools-backend/lib/dicts/company-struct"
 	companystore "hr-tools-backend/lib/dicts/company/store"
 	departmentprovider "hr-tools-backend/lib/dicts/department"
 	jobtitleprovider "hr-tools-backend/lib/dicts/job-title"
-	avitohandler "hr-tools-backend/lib/external-services/avito"
-	hhhandler "hr-tools-backend/lib/external-services/hh"
+	externalservices "hr-tools-backend/lib/external-services"
 	pushhandler "hr-tools-backend/lib/space/push/handler"
 	spaceusersstore "hr-tools-backend/lib/space/users/store"
 	initchecker "hr-tools-backend/lib/utils/init-checker"
@@ -22,6 +24,7 @@ import (
 	selectionstagestore "hr-tools-backend/lib/vacancy/selection-stage-store"
 	vacancystore "hr-tools-backend/lib/vacancy/store"
 	teamstore "hr-tools-backend/lib/vacancy/team-store"
+	webhookevent "hr-tools-backend/lib/webhook/event"
 	"hr-tools-backend/models"
 	apimodels "hr-tools-backend/models/api"
 	applicantapimodels "hr-tools-backend/models/api/applicant"
@@ -38,9 +41,10 @@ import (
 
 type Provider interface {
 	Create(spaceID, userID string, data vacancyapimodels.VacancyData) (id, hMsg string, err error)
-	GetByID(spaceID, id string) (item vacancyapimodels.VacancyView, err error)
-	Update(spaceID, id string, data vacancyapimodels.VacancyData) error
-	Delete(spaceID, id string) error
+	// GetByID вакансия по ИД, userID пусто - со всеми пользовательскими полями
+	GetByID(spaceID, id, userID string) (item vacancyapimodels.VacancyView, err error)
+	Update(spaceID, id, userID string, data vacancyapimodels.VacancyData) (hMsg string, err error)
+	Delete(spaceID, id, userID string) error
 	List(spaceID, userID string, filter vacancyapimodels.VacancyFilter) (list []vacancyapimodels.VacancyView, rowCount int64, err error)
 	ToPin(id, userID string, isSet bool) error
 	ToFavorite(id, userID string, isSet bool) error
@@ -146,6 +150,10 @@ func (i impl) Create(spaceID, userID string, data vacancyapimodels.VacancyData)
 	if err != nil {
 		return "", "", err
 	}
+	customFields, hMsg, err := customfield.Instance.PrepareValues(spaceID, userID, models.CustomFieldEntityVacancy, data.CustomFields, nil)
+	if err != nil || hMsg != "" {
+		return "", hMsg, err
+	}
 	vrChanges := []dbmodels.FieldChanges{}
 	recID := ""
 	err = db.DB.Transaction(func(tx *gorm.DB) error {
@@ -167,11 +175,12 @@ func (i impl) Create(spaceID, userID string, data vacancyapimodels.VacancyData)
 				ByResult: data.Salary.ByResult,
 				InHand:   data.Salary.InHand,
 			},
-			AuthorID:   userID,
-			Status:     models.VacancyStatusOpened,
-			Employment: data.Employment,
-			Experience: data.Experience,
-			Schedule:   data.Schedule,
+			AuthorID:     userID,
+			Status:       models.VacancyStatusOpened,
+			Employment:   data.Employment,
+			Experience:   data.Experience,
+			Schedule:     data.Schedule,
+			CustomFields: customFields,
 		}
 		if data.VacancyRequestID != "" {
 			vrStore := vacancyreqstore.NewInstance(tx)
@@ -273,7 +282,7 @@ func (i impl) Create(spaceID, userID string, data vacancyapimodels.VacancyData)
 	return recID, "", nil
 }
 
-func (i impl) GetByID(spaceID, id string) (item vacancyapimodels.VacancyView, err error) {
+func (i impl) GetByID(spaceID, id, userID string) (item vacancyapimodels.VacancyView, err error) {
 	rec, err := i.store.GetByID(spaceID, id)
 	if err != nil {
 		return vacancyapimodels.VacancyView{}, err
@@ -281,19 +290,36 @@ func (i impl) GetByID(spaceID, id string) (item vacancyapimodels.VacancyView, er
 	if rec == nil {
 		return vacancyapimodels.VacancyView{}, errors.New("вакансия не найдена")
 	}
+	fields, err := customfield.Instance.GetFields(spaceID, userID, models.CustomFieldEntityVacancy)
+	if err != nil {
+		return vacancyapimodels.VacancyView{}, err
+	}
 	recExt := dbmodels.VacancyExt{
 		Vacancy:  *rec,
 		Favorite: false,
 		Pinned:   false,
 	}
-	return vacancyapimodels.VacancyConvert(recExt), nil
+	result := vacancyapimodels.VacancyConvert(recExt)
+	result.CustomFields = rec.CustomFields.Only(fields)
+	return result, nil
 }
 
-func (i impl) Update(spaceID, id string, data vacancyapimodels.VacancyData) error {
-	logger := i.getLogger(spaceID, id, "")
-	err := i.checkDependency(spaceID, data)
+func (i impl) Update(spaceID, id, userID string, data vacancyapimodels.VacancyData) (hMsg string, err error) {
+	logger := i.getLogger(spaceID, id, userID)
+	err = i.checkDependency(spaceID, data)
 	if err != nil {
-		return err
+		return "", err
+	}
+	before, err := i.store.GetByID(spaceID, id)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения вакансии")
+	}
+	if before == nil {
+		return "вакансия не найдена", nil
+	}
+	customFields, hMsg, err := customfield.Instance.PrepareValues(spaceID, userID, models.CustomFieldEntityVacancy, data.CustomFields, before.CustomFields)
+	if err != nil || hMsg != "" {
+		return hMsg, err
 	}
 	err = db.DB.Transaction(func(tx *gorm.DB) error {
 		if data.CompanyID == "" && data.CompanyName != "" {
@@ -326,6 +352,7 @@ func (i impl) Update(spaceID, id string, data vacancyapimodels.VacancyData) erro
 			"Employment":      data.Employment,
 			"Experience":      data.Experience,
 			"Schedule":        data.Schedule,
+			"CustomFields":    customFields,
 		}
 		store := vacancystore.NewInstance(tx)
 		err = store.Update(spaceID, id, updMap)
@@ -335,25 +362,46 @@ func (i impl) Update(spaceID, id string, data vacancyapimodels.VacancyData) erro
 		return nil
 	})
 	if err != nil {
-		return err
+		return "", err
 	}
 	logger.Info("обновлена вакансия")
-	return nil
+	after, err := i.store.GetByID(spaceID, id)
+	if err != nil {
+		logger.WithError(err).Warn("ошибка получения вакансии для журнала аудита")
+		return "", nil
+	}
+	auditlog.Instance.SaveChanges(spaceID, userID, models.AuditActionUpdate, models.AuditEntityVacancy, id,
+		"Изменена вакансия", auditVacancyData(before), auditVacancyData(after))
+	return "", nil
 }
 
-func (i impl) Delete(spaceID, id string) error {
-	logger := i.getLogger(spaceID, id, "")
-	err := i.store.Delete(spaceID, id)
+func (i impl) Delete(spaceID, id, userID string) error {
+	logger := i.getLogger(spaceID, id, userID)
+	rec, err := i.store.GetByID(spaceID, id)
+	if err != nil {
+		return errors.Wrap(err, "ошибка получения вакансии")
+	}
+	err = i.store.Delete(spaceID, id)
 	if err != nil {
 		return err
 	}
 	logger.Info("удалена вакансия")
+	auditlog.Instance.SaveChanges(spaceID, userID, models.AuditActionDelete, models.AuditEntityVacancy, id,
+		"Удалена вакансия", auditVacancyData(rec), nil)
 	return nil
 }
 
 func (i impl) List(spaceID, userID string, filter vacancyapimodels.VacancyFilter) (list []vacancyapimodels.VacancyView, rowCount int64, err error) {
 	logger := i.getLogger(spaceID, "", userID)
-	rowCount, err = i.store.ListCount(spaceID, userID, filter)
+	scope, err := datascope.Instance.GetScope(spaceID, userID)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "ошибка получения области видимости")
+	}
+	filter.CustomFields, err = customfield.Instance.PrepareFilter(spaceID, userID, models.CustomFieldEntityVacancy, filter.CustomFields)
+	if err != nil {
+		return nil, 0, err
+	}
+	rowCount, err = i.store.ListCount(spaceID, userID, scope, filter)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -364,7 +412,7 @@ func (i impl) List(spaceID, userID string, filter vacancyapimodels.VacancyFilter
 		return []vacancyapimodels.VacancyView{}, rowCount, nil
 	}
 
-	recList, err := i.store.List(spaceID, userID, filter)
+	recList, err := i.store.List(spaceID, userID, scope, filter)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -392,9 +440,14 @@ func (i impl) List(spaceID, userID string, filter vacancyapimodels.VacancyFilter
 			stagesMap[stage.VacancyID] = list
 		}
 	}
+	fields, err := customfield.Instance.GetFields(spaceID, userID, models.CustomFieldEntityVacancy)
+	if err != nil {
+		return nil, 0, err
+	}
 	result := make([]vacancyapimodels.VacancyView, 0, len(list))
 	for _, rec := range recList {
 		item := vacancyapimodels.VacancyConvert(rec)
+		item.CustomFields = rec.CustomFields.Only(fields)
 		if stages, ok := stagesMap[rec.ID]; ok {
 			for k, selectionStage := range item.SelectionStages {
 				for _, stage := range stages {
@@ -579,7 +632,7 @@ func (i impl) StatusChange(spaceID, vacancyID, userID string, status models.Vaca
 				Limit: 100,
 			},
 		}
-		list, err := applicantStore.ListOfApplicant(spaceID, filter)
+		list, err := applicantStore.ListOfApplicant(spaceID, nil, filter)
 		if err != nil {
 			return errors.Wrap(err, "ошибка получения списка кандидатов по вакансии")
 		}
@@ -614,7 +667,15 @@ func (i impl) StatusChange(spaceID, vacancyID, userID string, status models.Vaca
 	}
 
 	logger.Info("обновлен статус вакансии")
+	auditlog.Instance.SaveChanges(spaceID, userID, models.AuditActionUpdate, models.AuditEntityVacancy, vacancyID,
+		"Изменен статус вакансии", map[string]any{"status": rec.Status}, map[string]any{"status": status})
 	rec.Status = status
+	webhookevent.Publish(webhookevent.Event{
+		Type:      dbmodels.WebhookEventVacancyStatusChanged,
+		SpaceID:   spaceID,
+		VacancyID: vacancyID,
+		Status:    string(status),
+	})
 	go func(rec dbmodels.Vacancy) {
 		notification := models.GetPushVacancyNewStatus(rec.VacancyName, string(status))
 		i.sendNotification(rec, notification)
@@ -786,6 +847,15 @@ func (i impl) initSelectionStages(tx *gorm.DB, spaceID, vacancyID string) error
 	return nil
 }
 
+// auditVacancyData данные вакансии для журнала аудита
+func auditVacancyData(rec *dbmodels.Vacancy) *vacancyapimodels.VacancyData {
+	if rec == nil {
+		return nil
+	}
+	view := vacancyapimodels.VacancyConvert(dbmodels.VacancyExt{Vacancy: *rec})
+	return &view.VacancyData
+}
+
 func (i *impl) getLogger(spaceID, vacancyID, userID string) *log.Entry {
 	logger := log.WithField("space_id", spaceID)
 	if vacancyID != "" {
@@ -799,28 +869,20 @@ func (i *impl) getLogger(spaceID, vacancyID, userID string) *log.Entry {
 
 func (i impl) cancelJobSite(rec dbmodels.Vacancy, logger log.Entry) error {
 	errorList := []string{}
-	if rec.AvitoID != 0 && (rec.AvitoStatus == models.VacancyPubStatusModeration || rec.AvitoStatus != models.VacancyPubStatusPublished) {
-		hMsg, err := avitohandler.Instance.VacancyClose(context.TODO(), rec.SpaceID, rec.ID)
-		if err != nil || hMsg != "" {
-			logger.
-				WithError(err).
-				WithField("reason", hMsg).
-				Error("не удалось снять вакансию с публикации на Avito")
-			errorList = append(errorList, "не удалось снять вакансию с публикации на Avito")
-		} else {
-			logger.Info("вакансия снята с публикации на Avito")
+	for _, connector := range externalservices.ListConnectors(externalservices.CapabilityClose) {
+		publication, ok := connector.Provider.(externalservices.PublicationInfo)
+		if !ok || !publication.CanClose(rec) {
+			continue
 		}
-	}
-	if rec.HhID != "" && (rec.HhStatus == models.VacancyPubStatusModeration || rec.HhStatus != models.VacancyPubStatusPublished) {
-		hMsg, err := hhhandler.Instance.VacancyClose(context.TODO(), rec.SpaceID, rec.ID)
+		hMsg, err := connector.Provider.VacancyClose(context.TODO(), rec.SpaceID, rec.ID)
 		if err != nil || hMsg != "" {
 			logger.
 				WithError(err).
 				WithField("reason", hMsg).
-				Error("не удалось снять вакансию с публикации на HeadHunter")
-			errorList = append(errorList, "не удалось снять вакансию с публикации на HeadHunter")
+				Errorf("не удалось снять вакансию с публикации на %v", connector.Name)
+			errorList = append(errorList, fmt.Sprintf("не удалось снять вакансию с публикации на %v", connector.Name))
 		} else {
-			logger.Info("вакансия снята с публикации на HeadHunter")
+			logger.Infof("вакансия снята с публикации на %v", connector.Name)
 		}
 	}
 	if len(errorList) != 0 {
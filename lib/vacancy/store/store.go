@@ -17,8 +17,10 @@ type Provider interface {
 	GetByID(spaceID, id string) (rec *dbmodels.Vacancy, err error)
 	Update(spaceID, id string, updMap map[string]interface{}) error
 	Delete(spaceID, id string) error
-	ListCount(spaceID, userID string, filter vacancyapimodels.VacancyFilter) (count int64, err error)
-	List(spaceID, userID string, filter vacancyapimodels.VacancyFilter) (list []dbmodels.VacancyExt, err error)
+	ListCount(spaceID, userID string, scope *dbmodels.DataScope, filter vacancyapimodels.VacancyFilter) (count int64, err error)
+	List(spaceID, userID string, scope *dbmodels.DataScope, filter vacancyapimodels.VacancyFilter) (list []dbmodels.VacancyExt, err error)
+	// IsVisible вакансия видна в области видимости пользователя
+	IsVisible(spaceID, id string, scope *dbmodels.DataScope) (bool, error)
 	SetPin(vacancyID, userID string) error
 	RemovePin(vacancyID, userID string) error
 	SetFavorite(vacancyID, userID string) error
@@ -26,6 +28,7 @@ type Provider interface {
 	ListAvitoByStatus(spaceID string, status models.VacancyPubStatus) (list []dbmodels.Vacancy, err error)
 	ListHhByStatus(spaceID string, status models.VacancyPubStatus) (list []dbmodels.Vacancy, err error)
 	AddComment(data dbmodels.VacancyComment) error
+	ListOpened(spaceID string) (list []dbmodels.Vacancy, err error)
 }
 
 func NewInstance(DB *gorm.DB) Provider {
@@ -103,7 +106,7 @@ func (i impl) Delete(spaceID, id string) error {
 	return nil
 }
 
-func (i impl) ListCount(spaceID, userID string, filter vacancyapimodels.VacancyFilter) (count int64, err error) {
+func (i impl) ListCount(spaceID, userID string, scope *dbmodels.DataScope, filter vacancyapimodels.VacancyFilter) (count int64, err error) {
 	var rowCount int64
 	tx := i.db.
 		Model(dbmodels.Vacancy{}).
@@ -111,6 +114,7 @@ func (i impl) ListCount(spaceID, userID string, filter vacancyapimodels.VacancyF
 		Joins("left join pinneds as p on vacancies.id = p.vacancy_id and p.space_user_id = ?", userID).
 		Joins("left join vacancy_teams as vt on vacancies.id = vt.vacancy_id and vt.responsible = true").
 		Where("vacancies.space_id = ?", spaceID)
+	i.addScope(tx, scope)
 	i.addFilter(tx, filter, userID)
 	err = tx.Count(&rowCount).Error
 	if err != nil {
@@ -120,7 +124,7 @@ func (i impl) ListCount(spaceID, userID string, filter vacancyapimodels.VacancyF
 	return rowCount, nil
 }
 
-func (i impl) List(spaceID, userID string, filter vacancyapimodels.VacancyFilter) (list []dbmodels.VacancyExt, err error) {
+func (i impl) List(spaceID, userID string, scope *dbmodels.DataScope, filter vacancyapimodels.VacancyFilter) (list []dbmodels.VacancyExt, err error) {
 	list = []dbmodels.VacancyExt{}
 	tx := i.db.
 		Model(dbmodels.Vacancy{}).
@@ -129,6 +133,7 @@ func (i impl) List(spaceID, userID string, filter vacancyapimodels.VacancyFilter
 		Joins("left join pinneds as p on vacancies.id = p.vacancy_id and p.space_user_id = ?", userID).
 		Joins("left join vacancy_teams as vt on vacancies.id = vt.vacancy_id and vt.responsible = true").
 		Where("vacancies.space_id = ?", spaceID)
+	i.addScope(tx, scope)
 	i.addFilter(tx, filter, userID)
 	page, limit := filter.GetPage()
 	i.setPage(tx, page, limit)
@@ -145,6 +150,18 @@ func (i impl) List(spaceID, userID string, filter vacancyapimodels.VacancyFilter
 	return list, nil
 }
 
+func (i impl) IsVisible(spaceID, id string, scope *dbmodels.DataScope) (bool, error) {
+	var exists bool
+	tx := i.db.
+		Model(&dbmodels.Vacancy{}).
+		Select("count(*) > 0").
+		Where("vacancies.id = ?", id).
+		Where("vacancies.space_id = ?", spaceID)
+	i.addScope(tx, scope)
+	err := tx.Find(&exists).Error
+	return exists, err
+}
+
 func (i impl) SetPin(vacancyID, userID string) error {
 	rec := dbmodels.Pinned{
 		VacancyID:   vacancyID,
@@ -255,6 +272,12 @@ func (i impl) addSort(tx *gorm.DB, sort vacancyapimodels.VacancySort) {
 	}
 }
 
+func (i impl) addScope(tx *gorm.DB, scope *dbmodels.DataScope) {
+	if query, args := scope.VacancyCondition("vacancies"); query != "" {
+		tx.Where(query, args)
+	}
+}
+
 func (i impl) addFilter(tx *gorm.DB, filter vacancyapimodels.VacancyFilter, userID string) {
 	if filter.Tab > 0 {
 		switch filter.Tab {
@@ -306,6 +329,9 @@ func (i impl) addFilter(tx *gorm.DB, filter vacancyapimodels.VacancyFilter, user
 	if filter.ResponsibleSearch != "" {
 		tx.Where("vt.id in (select id from space_users where LOWER(first_name|| ' ' || last_name) like ?)", "%"+strings.ToLower(filter.ResponsibleSearch)+"%")
 	}
+	if query, args := filter.CustomFields.Condition("vacancies.custom_fields"); query != "" {
+		tx.Where(query, args)
+	}
 	i.addSort(tx, filter.Sort)
 }
 
@@ -313,3 +339,22 @@ func (i impl) setPage(tx *gorm.DB, page, limit int) {
 	offset := (page - 1) * limit
 	tx.Limit(limit).Offset(offset)
 }
+
+func (i impl) ListOpened(spaceID string) (list []dbmodels.Vacancy, err error) {
+	list = []dbmodels.Vacancy{}
+	err = i.db.
+		Model(dbmodels.Vacancy{}).
+		Where("space_id = ?", spaceID).
+		Where("status = ?", models.VacancyStatusOpened).
+		Preload("Space").
+		Preload("Company").
+		Preload("City").
+		Preload("JobTitle").
+		Order("created_at desc").
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
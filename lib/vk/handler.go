@@ -10,11 +10,13 @@ import (
 	ollamasearchhandler "hr-tools-backend/lib/ai/ollama-search"
 	"hr-tools-backend/lib/applicant"
 	applicantstore "hr-tools-backend/lib/applicant/store"
+	automationevent "hr-tools-backend/lib/automation/event"
 	companystore "hr-tools-backend/lib/dicts/company/store"
 	negotiationchathandler "hr-tools-backend/lib/external-services/negotiation-chat"
 	filestorage "hr-tools-backend/lib/file-storage"
 	gpthandler "hr-tools-backend/lib/gpt"
 	messagetemplate "hr-tools-backend/lib/message-template"
+	personaldata "hr-tools-backend/lib/personal-data"
 	"hr-tools-backend/lib/smtp"
 	spacesettingsstore "hr-tools-backend/lib/space/settings/store"
 	"hr-tools-backend/lib/utils/helpers"
@@ -24,8 +26,10 @@ import (
 	applicantvkstore "hr-tools-backend/lib/vk/applicant-vk-store"
 	questionhistorystore "hr-tools-backend/lib/vk/question-history-store"
 	vkvideoanalyzestore "hr-tools-backend/lib/vk/vk-video-analyze-store"
+	webhookevent "hr-tools-backend/lib/webhook/event"
 	"hr-tools-backend/models"
 	negotiationapimodels "hr-tools-backend/models/api/negotiation"
+	personaldataapimodels "hr-tools-backend/models/api/personal-data"
 	surveyapimodels "hr-tools-backend/models/api/survey"
 	dbmodels "hr-tools-backend/models/db"
 	"io"
@@ -43,14 +47,16 @@ import (
 
 type Provider interface {
 	RunStep0(applicant dbmodels.Applicant) (ok bool, err error)
-	GetSurveyStep0(id string) (*surveyapimodels.VkStep0SurveyView, error)                                                              // анкета для фронта
-	HandleSurveyStep0(id string, answers surveyapimodels.VkStep0SurveyAnswers) (result surveyapimodels.VkStep0SurveyResult, err error) // ответы от фронта, сохранение в бд, анализ проходит или нет
+	GetSurveyStep0(id string) (*surveyapimodels.VkStep0SurveyView, error)                                                                                                         // анкета для фронта
+	HandleSurveyStep0(id string, answers surveyapimodels.VkStep0SurveyAnswers, consent personaldataapimodels.ConsentMeta) (result surveyapimodels.VkStep0SurveyResult, err error) // ответы от фронта, сохранение в бд, анализ проходит или нет
 	Step1GetData(spaceID, applicantID string) (aiData surveyapimodels.AiData, err error)
 	RunStep1(applicant dbmodels.Applicant) (ok bool, err error)
 	UpdateStep1(spaceID, applicantID string, stepData surveyapimodels.VkStep1Update) (hMsg string, err error)
 	RegenStep1(spaceID, applicantID string, stepData surveyapimodels.VkStep1Regen) (hMsg string, err error)
 	RunRegenStep1(applicant dbmodels.Applicant) (ok bool, err error)
 	GetVideoSurvey(id string) (*surveyapimodels.VkStep1SurveyView, error)
+	// VideoInterviewConsent согласие кандидата на обработку ПД (видеозаписи) перед прохождением видео-интервью
+	VideoInterviewConsent(id string, consent personaldataapimodels.ConsentMeta) error
 	UploadVideoAnswer(ctx context.Context, id, questionID string, fileHeader *multipart.FileHeader) error
 	GetVideoAnswer(ctx context.Context, id, questionID string) (reader io.Reader, err error)
 	ScoreAnswer(videoSurveyRec dbmodels.ApplicantVkVideoSurvey) (err error)
@@ -205,7 +211,7 @@ func (i impl) GetSurveyStep0(id string) (*surveyapimodels.VkStep0SurveyView, err
 	return &result, nil
 }
 
-func (i impl) HandleSurveyStep0(id string, request surveyapimodels.VkStep0SurveyAnswers) (result surveyapimodels.VkStep0SurveyResult, err error) {
+func (i impl) HandleSurveyStep0(id string, request surveyapimodels.VkStep0SurveyAnswers, consent personaldataapimodels.ConsentMeta) (result surveyapimodels.VkStep0SurveyResult, err error) {
 	rec, err := i.vkStore.GetByID(id)
 	if err != nil {
 		return result, errors.Wrap(err, "ошибка получения анкеты кандидата")
@@ -239,6 +245,10 @@ func (i impl) HandleSurveyStep0(id string, request surveyapimodels.VkStep0Survey
 	if err != nil {
 		return result, errors.Wrap(err, "ошибка сохранения анкеты")
 	}
+	err = personaldata.Instance.SaveConsent(rec.SpaceID, rec.ApplicantID, consent)
+	if err != nil {
+		return result, err
+	}
 
 	isSucess := false
 	points := i.step0CalcPoints(vacancyRec, request)
@@ -255,6 +265,7 @@ func (i impl) HandleSurveyStep0(id string, request surveyapimodels.VkStep0Survey
 	if err != nil {
 		return result, errors.Wrap(err, "ошибка сохранения анкеты")
 	}
+	publishVkStep(*rec, vacancyRec.ID)
 	if isSucess {
 		result = surveyapimodels.VkStep0SurveyResult{
 			Success: true,
@@ -586,6 +597,28 @@ func (i impl) GetVideoSurvey(id string) (*surveyapimodels.VkStep1SurveyView, err
 	return &result, nil
 }
 
+func (i impl) VideoInterviewConsent(id string, consent personaldataapimodels.ConsentMeta) error {
+	rec, err := i.vkStore.GetByID(id)
+	if err != nil {
+		return errors.Wrap(err, "ошибка получения анкеты кандидата")
+	}
+	if rec == nil {
+		return errors.New("анкета не найдена")
+	}
+	return personaldata.Instance.SaveConsent(rec.SpaceID, rec.ApplicantID, consent)
+}
+
+func (i impl) checkVideoConsent(applicantID string) error {
+	ok, err := personaldata.Instance.HasConsent(applicantID, models.ConsentSourceVideoInterview)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New(models.ConsentRequiredMsg)
+	}
+	return nil
+}
+
 func (i impl) UploadVideoAnswer(ctx context.Context, id, questionID string, fileHeader *multipart.FileHeader) error {
 	rec, err := i.vkStore.GetByID(id)
 	if err != nil {
@@ -594,6 +627,10 @@ func (i impl) UploadVideoAnswer(ctx context.Context, id, questionID string, file
 	if rec == nil {
 		return errors.New("анкета не найдена")
 	}
+	err = i.checkVideoConsent(rec.ApplicantID)
+	if err != nil {
+		return err
+	}
 	if answer, ok := rec.VideoInterview.Answers[questionID]; ok && answer.FileID != "" {
 		return errors.New("ответ уже сохранен")
 	}
@@ -650,6 +687,10 @@ func (i impl) UploadStreamVideoAnswer(ctx context.Context, id, questionID string
 	if rec == nil {
 		return minio.UploadInfo{}, errors.New("анкета не найдена")
 	}
+	err = i.checkVideoConsent(rec.ApplicantID)
+	if err != nil {
+		return minio.UploadInfo{}, err
+	}
 	if answer, ok := rec.VideoInterview.Answers[questionID]; ok && answer.FileID != "" {
 		return minio.UploadInfo{}, errors.New("ответ уже сохранен")
 	}
@@ -843,6 +884,7 @@ func (i impl) GenerateReport(vkRec dbmodels.ApplicantVkStep) (ok bool, err error
 	if err != nil {
 		return false, errors.Wrap(err, "ошибка сохранения анкеты")
 	}
+	publishVkStep(vkRec, vacancy.ID)
 	return true, nil
 }
 
@@ -1186,4 +1228,23 @@ func updateInterviewStatusOnUpload(rec *dbmodels.ApplicantVkStep) {
 		rec.VideoInterview.EndTime = &now
 		rec.VideoInterview.Status = models.VideoInterviewStatusProcessing
 	}
-}
\ No newline at end of file
+}
+
+// publishVkStep передача изменения статуса шага ВК в правила автоматизации и подписки вебхуков (готовность отчета)
+func publishVkStep(rec dbmodels.ApplicantVkStep, vacancyID string) {
+	automationevent.Publish(automationevent.Event{
+		Trigger:     dbmodels.AutomationTriggerVkStep,
+		SpaceID:     rec.SpaceID,
+		ApplicantID: rec.ApplicantID,
+		VacancyID:   vacancyID,
+		VkStatus:    rec.Status,
+	})
+	if rec.Status == dbmodels.VkStep11Report {
+		webhookevent.Publish(webhookevent.Event{
+			Type:        dbmodels.WebhookEventVkReportReady,
+			SpaceID:     rec.SpaceID,
+			ApplicantID: rec.ApplicantID,
+			VacancyID:   vacancyID,
+		})
+	}
+}
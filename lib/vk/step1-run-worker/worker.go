@@ -4,6 +4,7 @@ import (
 	"context"
 	"hr-tools-backend/db"
 	applicantstore "hr-tools-backend/lib/applicant/store"
+	externalservices "hr-tools-backend/lib/external-services"
 	baseworker "hr-tools-backend/lib/utils/base-worker"
 	botnotify "hr-tools-backend/lib/utils/bot-notify"
 	"hr-tools-backend/lib/utils/helpers"
@@ -33,7 +34,7 @@ type impl struct {
 func (i impl) handle(ctx context.Context) {
 	logger := i.GetLogger()
 	// Получаем список анкет кандидатов для отправки типовых вопросов
-	list, err := i.applicantStore.ListOfActiveApplicants()
+	list, err := i.applicantStore.ListOfActiveApplicants(externalservices.ConnectorSources(externalservices.CapabilityNegotiations))
 	if err != nil {
 		logger.WithError(err).Error("ВК. Шаг 1. ошибка получения списка анкет кандидатов для генерации черновика скрипта")
 		return
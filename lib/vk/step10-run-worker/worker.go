@@ -6,6 +6,7 @@ import (
 	"hr-tools-backend/db"
 	applicanthistoryhandler "hr-tools-backend/lib/applicant-history"
 	applicantstore "hr-tools-backend/lib/applicant/store"
+	automationevent "hr-tools-backend/lib/automation/event"
 	baseworker "hr-tools-backend/lib/utils/base-worker"
 	"hr-tools-backend/lib/utils/helpers"
 	selectionstagestore "hr-tools-backend/lib/vacancy/selection-stage-store"
@@ -95,6 +96,12 @@ func (i impl) Scoring(rec dbmodels.ApplicantVkStep) error {
 	if err != nil {
 		return err
 	}
+	automationevent.Publish(automationevent.Event{
+		Trigger:     dbmodels.AutomationTriggerVkStep,
+		SpaceID:     rec.SpaceID,
+		ApplicantID: rec.ApplicantID,
+		VkStatus:    rec.Status,
+	})
 	return nil
 }
 
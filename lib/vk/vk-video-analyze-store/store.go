@@ -10,6 +10,8 @@ import (
 type Provider interface {
 	Save(rec dbmodels.ApplicantVkVideoSurvey) (id string, err error)
 	GetByID(analyzeID string) (*dbmodels.ApplicantVkVideoSurvey, error)
+	// IsVisible анализ видео ответа виден, если виден кандидат
+	IsVisible(spaceID, analyzeID string, scope *dbmodels.DataScope) (bool, error)
 	GetByStepQuestion(applicantVkStepID, questionID string) (*dbmodels.ApplicantVkVideoSurvey, error)
 	GetByApplicantVkStep(applicantVkStepID string) ([]dbmodels.ApplicantVkVideoSurvey, error)
 	GetForScore() ([]dbmodels.ApplicantVkVideoSurvey, error)
@@ -58,6 +60,25 @@ func (i impl) GetByID(analyzeID string) (*dbmodels.ApplicantVkVideoSurvey, error
 	return &rec, nil
 }
 
+func (i impl) IsVisible(spaceID, analyzeID string, scope *dbmodels.DataScope) (bool, error) {
+	var exists bool
+	steps := i.db.
+		Model(&dbmodels.ApplicantVkStep{}).
+		Select("applicant_vk_steps.id").
+		Where("applicant_vk_steps.space_id = ?", spaceID)
+	if query, args := scope.ApplicantRefCondition("applicant_vk_steps.applicant_id"); query != "" {
+		steps.Where(query, args)
+	}
+	err := i.db.
+		Model(&dbmodels.ApplicantVkVideoSurvey{}).
+		Select("count(*) > 0").
+		Where("id = ?", analyzeID).
+		Where("applicant_vk_step_id in (?)", steps).
+		Find(&exists).
+		Error
+	return exists, err
+}
+
 func (i impl) GetByStepQuestion(applicantVkStepID, questionID string) (*dbmodels.ApplicantVkVideoSurvey, error) {
 	rec := dbmodels.ApplicantVkVideoSurvey{}
 	err := i.db.
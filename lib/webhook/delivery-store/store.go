@@ -0,0 +1,158 @@
+package webhookdeliverystore
+
+import (
+	webhookapimodels "hr-tools-backend/models/api/webhook"
+	dbmodels "hr-tools-backend/models/db"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Provider interface {
+	Create(rec dbmodels.WebhookDelivery) (id string, err error)
+	Update(id string, updMap map[string]interface{}) error
+	GetByID(spaceID, id string) (*dbmodels.WebhookDelivery, error)
+	ListCount(spaceID string, filter webhookapimodels.DeliveryFilter) (int64, error)
+	List(spaceID string, filter webhookapimodels.DeliveryFilter) ([]dbmodels.WebhookDelivery, error)
+	// ClaimDue захват доставок по активным подпискам, время повторной отправки которых наступило.
+	// Время следующей попытки захваченных доставок переносится на leaseUntil, поэтому другие экземпляры
+	// приложения их не получат, а при сбое экземпляра доставки будут повторены после leaseUntil
+	ClaimDue(now, leaseUntil time.Time, limit int) ([]dbmodels.WebhookDelivery, error)
+}
+
+func NewInstance(DB *gorm.DB) Provider {
+	return &impl{
+		db: DB,
+	}
+}
+
+type impl struct {
+	db *gorm.DB
+}
+
+func (i impl) Create(rec dbmodels.WebhookDelivery) (id string, err error) {
+	err = i.db.
+		Omit(clause.Associations).
+		Save(&rec).
+		Error
+	if err != nil {
+		return "", err
+	}
+	return rec.ID, nil
+}
+
+func (i impl) Update(id string, updMap map[string]interface{}) error {
+	if len(updMap) == 0 {
+		return nil
+	}
+	return i.db.
+		Model(&dbmodels.WebhookDelivery{}).
+		Where("id = ?", id).
+		Updates(updMap).
+		Error
+}
+
+func (i impl) GetByID(spaceID, id string) (*dbmodels.WebhookDelivery, error) {
+	rec := dbmodels.WebhookDelivery{}
+	err := i.db.
+		Preload("Subscription").
+		Where("space_id = ?", spaceID).
+		Where("id = ?", id).
+		First(&rec).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (i impl) ListCount(spaceID string, filter webhookapimodels.DeliveryFilter) (int64, error) {
+	var rowCount int64
+	err := i.filter(spaceID, filter).
+		Count(&rowCount).
+		Error
+	if err != nil {
+		return 0, err
+	}
+	return rowCount, nil
+}
+
+func (i impl) List(spaceID string, filter webhookapimodels.DeliveryFilter) ([]dbmodels.WebhookDelivery, error) {
+	list := []dbmodels.WebhookDelivery{}
+	tx := i.filter(spaceID, filter)
+	page, limit := filter.GetPage()
+	offset := (page - 1) * limit
+	err := tx.
+		Preload("Subscription").
+		Order("created_at desc").
+		Limit(limit).
+		Offset(offset).
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (i impl) ClaimDue(now, leaseUntil time.Time, limit int) ([]dbmodels.WebhookDelivery, error) {
+	list := []dbmodels.WebhookDelivery{}
+	err := i.db.Transaction(func(tx *gorm.DB) error {
+		dueQuery := tx.
+			Model(&dbmodels.WebhookDelivery{}).
+			Select("webhook_deliveries.id").
+			Joins("join webhook_subscriptions ws on ws.id = webhook_deliveries.subscription_id").
+			Where("ws.is_active = ?", true).
+			Where("webhook_deliveries.status = ?", dbmodels.WebhookDeliveryPending).
+			Where("webhook_deliveries.next_attempt_at <= ?", now).
+			Order("webhook_deliveries.next_attempt_at").
+			Limit(limit).
+			Clauses(clause.Locking{
+				Strength: "UPDATE",
+				Table:    clause.Table{Name: "webhook_deliveries"},
+				Options:  "SKIP LOCKED",
+			})
+		ids := []string{}
+		err := tx.
+			Raw("UPDATE webhook_deliveries SET next_attempt_at = ? WHERE id IN (?) RETURNING id", leaseUntil, dueQuery).
+			Scan(&ids).
+			Error
+		if err != nil {
+			return errors.Wrap(err, "ошибка захвата доставок")
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		return tx.
+			Preload("Subscription").
+			Where("id in (?)", ids).
+			Order("created_at").
+			Find(&list).
+			Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (i impl) filter(spaceID string, filter webhookapimodels.DeliveryFilter) *gorm.DB {
+	tx := i.db.
+		Model(&dbmodels.WebhookDelivery{}).
+		Where("space_id = ?", spaceID)
+	if filter.SubscriptionID != "" {
+		tx = tx.Where("subscription_id = ?", filter.SubscriptionID)
+	}
+	if filter.Event != "" {
+		tx = tx.Where("event = ?", filter.Event)
+	}
+	if filter.Status != "" {
+		tx = tx.Where("status = ?", filter.Status)
+	}
+	return tx
+}
@@ -0,0 +1,49 @@
+package webhookevent
+
+import (
+	dbmodels "hr-tools-backend/models/db"
+	"sync"
+	"time"
+)
+
+// Event доменное событие спейса для отправки во внешние системы по подпискам
+type Event struct {
+	Type             dbmodels.WebhookEvent
+	SpaceID          string
+	OccurredAt       time.Time
+	ApplicantID      string
+	VacancyID        string
+	VacancyRequestID string
+	OfferID          string
+	StageName        string // этап, на который переведен кандидат
+	Status           string // новый статус вакансии / заявки
+	Reason           string // причина отказа
+}
+
+type Handler func(event Event)
+
+var (
+	handlerMx sync.RWMutex
+	handler   Handler
+)
+
+// SetHandler регистрирует обработчик событий, вызывается из NewHandler вебхуков
+func SetHandler(h Handler) {
+	handlerMx.Lock()
+	defer handlerMx.Unlock()
+	handler = h
+}
+
+// Publish асинхронная передача события обработчику, без обработчика событие отбрасывается
+func Publish(event Event) {
+	handlerMx.RLock()
+	h := handler
+	handlerMx.RUnlock()
+	if h == nil {
+		return
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+	go h(event)
+}
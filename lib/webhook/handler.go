@@ -0,0 +1,477 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hr-tools-backend/config"
+	"hr-tools-backend/db"
+	auditlog "hr-tools-backend/lib/audit-log"
+	authhelpers "hr-tools-backend/lib/utils/auth-helpers"
+	"hr-tools-backend/lib/utils/helpers"
+	initchecker "hr-tools-backend/lib/utils/init-checker"
+	safehttp "hr-tools-backend/lib/utils/safe-http"
+	webhookdeliverystore "hr-tools-backend/lib/webhook/delivery-store"
+	webhookevent "hr-tools-backend/lib/webhook/event"
+	webhookstore "hr-tools-backend/lib/webhook/store"
+	"hr-tools-backend/models"
+	webhookapimodels "hr-tools-backend/models/api/webhook"
+	dbmodels "hr-tools-backend/models/db"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	retryBaseDelay     = time.Minute
+	retryMaxDelay      = 6 * time.Hour
+	responseBodyLimit  = 1024
+	pendingBatchLimit  = 100
+	pendingClaimLease  = 30 * time.Minute // больше времени отправки пачки, по истечении доставка повторяется другим экземпляром
+	signatureHeader    = "X-Webhook-Signature"
+	timestampHeader    = "X-Webhook-Timestamp"
+	eventHeader        = "X-Webhook-Event"
+	deliveryHeader     = "X-Webhook-Delivery"
+	signaturePrefix    = "sha256="
+	disabledReasonText = "подписка отключена после %v неудачных доставок подряд"
+)
+
+type Provider interface {
+	// Create создание подписки, адрес подписки должен указывать на внешнюю сеть
+	Create(spaceID, userID string, data webhookapimodels.SubscriptionData) (id, hMsg string, err error)
+	Update(spaceID, id, userID string, data webhookapimodels.SubscriptionData) (hMsg string, err error)
+	Get(spaceID, id string) (*webhookapimodels.SubscriptionView, error)
+	List(spaceID string) ([]webhookapimodels.SubscriptionView, error)
+	Delete(spaceID, id, userID string) error
+	ListDeliveries(spaceID string, filter webhookapimodels.DeliveryFilter) (list []webhookapimodels.DeliveryView, rowCount int64, err error)
+	// Redeliver повторная отправка события из журнала доставки
+	Redeliver(ctx context.Context, spaceID, id string) (result *webhookapimodels.DeliveryView, hMsg string, err error)
+	// HandleEvent регистрация доставок события по подпискам спейса и первая попытка отправки
+	HandleEvent(event webhookevent.Event)
+	// SendPending повторная отправка событий, время доставки которых наступило
+	SendPending(ctx context.Context)
+}
+
+var Instance Provider
+
+func NewHandler() {
+	instance := &impl{
+		store:         webhookstore.NewInstance(db.DB),
+		deliveryStore: webhookdeliverystore.NewInstance(db.DB),
+		client:        safehttp.NewClient(time.Duration(config.Conf.Webhook.TimeoutSec) * time.Second),
+	}
+	initchecker.CheckInit(
+		"store", instance.store,
+		"deliveryStore", instance.deliveryStore,
+	)
+	Instance = instance
+	webhookevent.SetHandler(instance.HandleEvent)
+}
+
+type impl struct {
+	store         webhookstore.Provider
+	deliveryStore webhookdeliverystore.Provider
+	client        *http.Client
+}
+
+func (i *impl) getLogger(spaceID, subscriptionID string) *log.Entry {
+	logger := log.WithField("space_id", spaceID)
+	if subscriptionID != "" {
+		logger = logger.WithField("subscription_id", subscriptionID)
+	}
+	return logger
+}
+
+func (i *impl) Create(spaceID, userID string, data webhookapimodels.SubscriptionData) (id, hMsg string, err error) {
+	hMsg, err = safehttp.CheckUrl(context.Background(), data.Url)
+	if err != nil || hMsg != "" {
+		return "", hMsg, err
+	}
+	secret, err := authhelpers.EncryptString(config.Conf.Webhook.SecretKey, data.Secret)
+	if err != nil {
+		return "", "", errors.Wrap(err, "ошибка шифрования ключа подписи")
+	}
+	rec := dbmodels.WebhookSubscription{
+		BaseSpaceModel: dbmodels.BaseSpaceModel{SpaceID: spaceID},
+		Name:           data.Name,
+		Url:            data.Url,
+		Secret:         secret,
+		Events:         getEvents(data.Events),
+		IsActive:       data.IsActive,
+		AuthorID:       userID,
+	}
+	id, err = i.store.Create(rec)
+	if err != nil {
+		return "", "", errors.Wrap(err, "ошибка создания подписки")
+	}
+	i.getLogger(spaceID, id).Info("создана подписка на события")
+	rec.ID = id
+	auditlog.Instance.SaveChanges(spaceID, userID, models.AuditActionCreate, models.AuditEntityWebhook, id,
+		"Создана подписка на события", nil, getAuditData(rec))
+	return id, "", nil
+}
+
+func (i *impl) Update(spaceID, id, userID string, data webhookapimodels.SubscriptionData) (hMsg string, err error) {
+	rec, err := i.store.GetByID(spaceID, id)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка получения подписки")
+	}
+	if rec == nil {
+		return "подписка не найдена", nil
+	}
+	hMsg, err = safehttp.CheckUrl(context.Background(), data.Url)
+	if err != nil || hMsg != "" {
+		return hMsg, err
+	}
+	updMap := map[string]interface{}{
+		"name":      data.Name,
+		"url":       data.Url,
+		"events":    getEvents(data.Events),
+		"is_active": data.IsActive,
+	}
+	if data.Secret != "" {
+		updMap["secret"], err = authhelpers.EncryptString(config.Conf.Webhook.SecretKey, data.Secret)
+		if err != nil {
+			return "", errors.Wrap(err, "ошибка шифрования ключа подписи")
+		}
+	}
+	if data.IsActive && !rec.IsActive {
+		// повторное включение после исправления на стороне внешней системы
+		updMap["failure_count"] = 0
+		updMap["disabled_at"] = nil
+		updMap["disabled_reason"] = ""
+	}
+	err = i.store.Update(spaceID, id, updMap)
+	if err != nil {
+		return "", errors.Wrap(err, "ошибка изменения подписки")
+	}
+	i.getLogger(spaceID, id).Info("изменена подписка на события")
+	i.auditUpdate(spaceID, id, userID, *rec)
+	return "", nil
+}
+
+func (i *impl) Get(spaceID, id string) (*webhookapimodels.SubscriptionView, error) {
+	rec, err := i.store.GetByID(spaceID, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения подписки")
+	}
+	if rec == nil {
+		return nil, nil
+	}
+	result := webhookapimodels.SubscriptionConvert(*rec)
+	return &result, nil
+}
+
+func (i *impl) List(spaceID string) ([]webhookapimodels.SubscriptionView, error) {
+	list, err := i.store.List(spaceID)
+	if err != nil {
+		return nil, errors.Wrap(err, "ошибка получения списка подписок")
+	}
+	result := make([]webhookapimodels.SubscriptionView, 0, len(list))
+	for _, rec := range list {
+		result = append(result, webhookapimodels.SubscriptionConvert(rec))
+	}
+	return result, nil
+}
+
+func (i *impl) Delete(spaceID, id, userID string) error {
+	rec, err := i.store.GetByID(spaceID, id)
+	if err != nil {
+		return errors.Wrap(err, "ошибка получения подписки")
+	}
+	err = i.store.Delete(spaceID, id)
+	if err != nil {
+		return errors.Wrap(err, "ошибка удаления подписки")
+	}
+	i.getLogger(spaceID, id).Info("удалена подписка на события")
+	if rec != nil {
+		auditlog.Instance.SaveChanges(spaceID, userID, models.AuditActionDelete, models.AuditEntityWebhook, id,
+			"Удалена подписка на события", getAuditData(*rec), nil)
+	}
+	return nil
+}
+
+// auditSubscription данные подписки для журнала аудита, ключ подписи маскируется (отмечается только факт изменения)
+type auditSubscription struct {
+	webhookapimodels.SubscriptionView
+	Secret string `json:"secret"`
+}
+
+func getAuditData(rec dbmodels.WebhookSubscription) auditSubscription {
+	return auditSubscription{
+		SubscriptionView: webhookapimodels.SubscriptionConvert(rec),
+		Secret:           rec.Secret,
+	}
+}
+
+func (i *impl) auditUpdate(spaceID, id, userID string, before dbmodels.WebhookSubscription) {
+	after, err := i.store.GetByID(spaceID, id)
+	if err != nil || after == nil {
+		i.getLogger(spaceID, id).WithError(err).Warn("ошибка получения подписки для журнала аудита")
+		return
+	}
+	auditlog.Instance.SaveChanges(spaceID, userID, models.AuditActionUpdate, models.AuditEntityWebhook, id,
+		"Изменена подписка на события", getAuditData(before), getAuditData(*after))
+}
+
+func (i *impl) ListDeliveries(spaceID string, filter webhookapimodels.DeliveryFilter) (list []webhookapimodels.DeliveryView, rowCount int64, err error) {
+	rowCount, err = i.deliveryStore.ListCount(spaceID, filter)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "ошибка получения количества доставок")
+	}
+	page, limit := filter.GetPage()
+	offset := (page - 1) * limit
+	if int64(offset) > rowCount {
+		return []webhookapimodels.DeliveryView{}, rowCount, nil
+	}
+	recList, err := i.deliveryStore.List(spaceID, filter)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "ошибка получения журнала доставки")
+	}
+	list = make([]webhookapimodels.DeliveryView, 0, len(recList))
+	for _, rec := range recList {
+		list = append(list, webhookapimodels.DeliveryConvert(rec))
+	}
+	return list, rowCount, nil
+}
+
+func (i *impl) Redeliver(ctx context.Context, spaceID, id string) (result *webhookapimodels.DeliveryView, hMsg string, err error) {
+	rec, err := i.deliveryStore.GetByID(spaceID, id)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "ошибка получения доставки")
+	}
+	if rec == nil {
+		return nil, "доставка не найдена", nil
+	}
+	if rec.Subscription == nil {
+		return nil, "подписка не найдена", nil
+	}
+	i.getLogger(spaceID, rec.SubscriptionID).
+		WithField("delivery_id", id).
+		Info("повторная отправка события вручную")
+	updated := i.deliver(ctx, *rec.Subscription, *rec)
+	view := webhookapimodels.DeliveryConvert(updated)
+	return &view, "", nil
+}
+
+func (i *impl) HandleEvent(event webhookevent.Event) {
+	logger := i.getLogger(event.SpaceID, "").WithField("event", event.Type)
+	list, err := i.store.ListActive(event.SpaceID, event.Type)
+	if err != nil {
+		logger.WithError(err).Error("ошибка получения подписок на событие")
+		return
+	}
+	for _, subscription := range list {
+		nextAttemptAt := time.Now().Add(getRetryDelay(1))
+		rec := dbmodels.WebhookDelivery{
+			BaseSpaceModel: dbmodels.BaseSpaceModel{SpaceID: event.SpaceID},
+			SubscriptionID: subscription.ID,
+			Event:          event.Type,
+			Status:         dbmodels.WebhookDeliveryPending,
+			NextAttemptAt:  &nextAttemptAt,
+		}
+		rec.ID, err = i.deliveryStore.Create(rec)
+		if err != nil {
+			logger.WithError(err).WithField("subscription_id", subscription.ID).Error("ошибка регистрации доставки события")
+			continue
+		}
+		// идентификатор доставки входит в тело запроса, поэтому тело формируется после создания записи
+		rec.Payload, err = getPayload(rec.ID, event)
+		if err != nil {
+			logger.WithError(err).Error("ошибка формирования тела события")
+			continue
+		}
+		err = i.deliveryStore.Update(rec.ID, map[string]interface{}{"payload": rec.Payload})
+		if err != nil {
+			logger.WithError(err).WithField("delivery_id", rec.ID).Error("ошибка сохранения тела события")
+			continue
+		}
+		i.deliver(context.Background(), subscription, rec)
+	}
+}
+
+func (i *impl) SendPending(ctx context.Context) {
+	now := time.Now()
+	list, err := i.deliveryStore.ClaimDue(now, now.Add(pendingClaimLease), pendingBatchLimit)
+	if err != nil {
+		log.WithError(err).Error("ошибка получения событий для повторной отправки")
+		return
+	}
+	for _, rec := range list {
+		if helpers.IsContextDone(ctx) {
+			return
+		}
+		if rec.Subscription == nil {
+			continue
+		}
+		i.deliver(ctx, *rec.Subscription, rec)
+	}
+}
+
+// deliver попытка отправки события, результат записывается в журнал доставки
+func (i *impl) deliver(ctx context.Context, subscription dbmodels.WebhookSubscription, rec dbmodels.WebhookDelivery) dbmodels.WebhookDelivery {
+	logger := i.getLogger(subscription.SpaceID, subscription.ID).
+		WithField("delivery_id", rec.ID).
+		WithField("event", rec.Event)
+	now := time.Now()
+	rec.Attempts++
+	rec.LastAttemptAt = &now
+	rec.Error = ""
+	secret, err := authhelpers.DecryptString(config.Conf.Webhook.SecretKey, subscription.Secret)
+	if err == nil {
+		rec.ResponseCode, rec.ResponseBody, err = i.send(ctx, subscription.Url, secret, rec)
+	} else {
+		err = errors.Wrap(err, "ошибка расшифровки ключа подписи")
+	}
+
+	if err == nil {
+		rec.Status = dbmodels.WebhookDeliverySuccess
+		rec.NextAttemptAt = nil
+	} else {
+		rec.Error = err.Error()
+		if rec.Attempts >= config.Conf.Webhook.MaxAttempts {
+			rec.Status = dbmodels.WebhookDeliveryFailed
+			rec.NextAttemptAt = nil
+		} else {
+			rec.Status = dbmodels.WebhookDeliveryPending
+			nextAttemptAt := now.Add(getRetryDelay(rec.Attempts))
+			rec.NextAttemptAt = &nextAttemptAt
+		}
+	}
+	updMap := map[string]interface{}{
+		"status":          rec.Status,
+		"attempts":        rec.Attempts,
+		"next_attempt_at": rec.NextAttemptAt,
+		"last_attempt_at": rec.LastAttemptAt,
+		"response_code":   rec.ResponseCode,
+		"response_body":   rec.ResponseBody,
+		"error":           rec.Error,
+	}
+	if updErr := i.deliveryStore.Update(rec.ID, updMap); updErr != nil {
+		logger.WithError(updErr).Error("ошибка сохранения результата доставки")
+	}
+
+	if err == nil {
+		if subscription.FailureCount > 0 {
+			updErr := i.store.Update(subscription.SpaceID, subscription.ID, map[string]interface{}{"failure_count": 0})
+			if updErr != nil {
+				logger.WithError(updErr).Error("ошибка сброса счетчика неудачных доставок")
+			}
+		}
+		return rec
+	}
+	logger.WithError(err).
+		WithField("attempts", rec.Attempts).
+		Warn("ошибка доставки события")
+	i.registerFailure(subscription, logger)
+	return rec
+}
+
+// registerFailure учет неудачной доставки, подписка отключается после заданного кол-ва неудачных доставок подряд
+func (i *impl) registerFailure(subscription dbmodels.WebhookSubscription, logger *log.Entry) {
+	failureCount, err := i.store.IncFailureCount(subscription.ID)
+	if err != nil {
+		logger.WithError(err).Error("ошибка учета неудачной доставки")
+		return
+	}
+	limit := config.Conf.Webhook.DisableAfterFailures
+	if !subscription.IsActive || limit <= 0 || failureCount < limit {
+		return
+	}
+	updMap := map[string]interface{}{
+		"is_active":       false,
+		"disabled_at":     time.Now(),
+		"disabled_reason": fmt.Sprintf(disabledReasonText, failureCount),
+	}
+	err = i.store.Update(subscription.SpaceID, subscription.ID, updMap)
+	if err != nil {
+		logger.WithError(err).Error("ошибка отключения подписки")
+		return
+	}
+	logger.WithField("failure_count", failureCount).Warn("подписка отключена после неудачных доставок")
+}
+
+// send отправка подписанного события, успешной считается доставка с кодом ответа 2xx
+func (i *impl) send(ctx context.Context, url, secret string, rec dbmodels.WebhookDelivery) (code int, body string, err error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBufferString(rec.Payload))
+	if err != nil {
+		return 0, "", errors.Wrap(err, "ошибка формирования запроса")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(eventHeader, string(rec.Event))
+	req.Header.Set(deliveryHeader, rec.ID)
+	req.Header.Set(timestampHeader, timestamp)
+	req.Header.Set(signatureHeader, GetSignature(secret, timestamp, []byte(rec.Payload)))
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return 0, "", errors.Wrap(err, "ошибка отправки запроса")
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, responseBodyLimit))
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return resp.StatusCode, string(respBody), errors.Errorf("код ответа %v", resp.StatusCode)
+	}
+	return resp.StatusCode, string(respBody), nil
+}
+
+// GetSignature подпись события: HMAC-SHA256 от "<timestamp>.<тело запроса>" в hex с префиксом sha256=
+func GetSignature(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// getRetryDelay задержка перед следующей попыткой после attempts неудачных: 1м, 2м, 4м... но не более retryMaxDelay
+func getRetryDelay(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for n := 1; n < attempts; n++ {
+		delay *= 2
+		if delay >= retryMaxDelay {
+			return retryMaxDelay
+		}
+	}
+	return delay
+}
+
+func getPayload(deliveryID string, event webhookevent.Event) (string, error) {
+	payload := webhookapimodels.Payload{
+		ID:         deliveryID,
+		Event:      event.Type,
+		SpaceID:    event.SpaceID,
+		OccurredAt: event.OccurredAt,
+		Data: webhookapimodels.EventData{
+			ApplicantID:      event.ApplicantID,
+			VacancyID:        event.VacancyID,
+			VacancyRequestID: event.VacancyRequestID,
+			OfferID:          event.OfferID,
+			StageName:        event.StageName,
+			Status:           event.Status,
+			Reason:           event.Reason,
+		},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+func getEvents(events []dbmodels.WebhookEvent) pq.StringArray {
+	result := pq.StringArray{}
+	for _, event := range events {
+		result = append(result, string(event))
+	}
+	return result
+}
@@ -0,0 +1,75 @@
+package webhook
+
+import (
+	"context"
+	safehttp "hr-tools-backend/lib/utils/safe-http"
+	dbmodels "hr-tools-backend/models/db"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSignature(t *testing.T) {
+	// echo -n '1700000000.{"id":"1"}' | openssl dgst -sha256 -hmac "secret-key-0123456"
+	signature := GetSignature("secret-key-0123456", "1700000000", []byte(`{"id":"1"}`))
+	require.Equal(t, "sha256=df4d54459e9b3fd2ebae1136572b5db4618cd58568d8fc7132fa4e3834a73064", signature)
+	require.NotEqual(t, signature, GetSignature("secret-key-0123457", "1700000000", []byte(`{"id":"1"}`)))
+	require.NotEqual(t, signature, GetSignature("secret-key-0123456", "1700000001", []byte(`{"id":"1"}`)))
+}
+
+func TestGetRetryDelay(t *testing.T) {
+	require.Equal(t, time.Minute, getRetryDelay(1))
+	require.Equal(t, 2*time.Minute, getRetryDelay(2))
+	require.Equal(t, 4*time.Minute, getRetryDelay(3))
+	require.Equal(t, 64*time.Minute, getRetryDelay(7))
+	require.Equal(t, retryMaxDelay, getRetryDelay(20))
+}
+
+func TestSend(t *testing.T) {
+	const secret = "secret-key-0123456"
+	var code int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp := r.Header.Get(timestampHeader)
+		require.Equal(t, GetSignature(secret, timestamp, body), r.Header.Get(signatureHeader))
+		require.Equal(t, string(dbmodels.WebhookEventOfferAccepted), r.Header.Get(eventHeader))
+		require.Equal(t, "delivery", r.Header.Get(deliveryHeader))
+		w.WriteHeader(code)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	i := impl{client: server.Client()}
+	rec := dbmodels.WebhookDelivery{
+		Event:   dbmodels.WebhookEventOfferAccepted,
+		Payload: `{"id":"delivery"}`,
+	}
+	rec.ID = "delivery"
+
+	code = http.StatusOK
+	respCode, respBody, err := i.send(context.Background(), server.URL, secret, rec)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, respCode)
+	require.Equal(t, "ok", respBody)
+
+	code = http.StatusInternalServerError
+	respCode, _, err = i.send(context.Background(), server.URL, secret, rec)
+	require.Error(t, err)
+	require.Equal(t, http.StatusInternalServerError, respCode)
+}
+
+func TestClientDeniesInternalAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	i := impl{client: safehttp.NewClient(time.Second)}
+	rec := dbmodels.WebhookDelivery{Event: dbmodels.WebhookEventOfferAccepted, Payload: `{}`}
+	_, _, err := i.send(context.Background(), server.URL, "secret-key-0123456", rec)
+	require.ErrorContains(t, err, safehttp.DeniedAddrMsg)
+}
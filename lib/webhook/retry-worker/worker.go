@@ -0,0 +1,24 @@
+package webhookretryworker
+
+import (
+	"context"
+	baseworker "hr-tools-backend/lib/utils/base-worker"
+	"hr-tools-backend/lib/webhook"
+	"time"
+)
+
+// Задача повторной отправки событий по подпискам с экспоненциальной задержкой
+func StartWorker(ctx context.Context) {
+	i := &impl{
+		BaseImpl: *baseworker.NewInstance("WebhookRetryWorker", 30*time.Second, time.Minute),
+	}
+	go i.Run(ctx, i.handle)
+}
+
+type impl struct {
+	baseworker.BaseImpl
+}
+
+func (i impl) handle(ctx context.Context) {
+	webhook.Instance.SendPending(ctx)
+}
@@ -0,0 +1,129 @@
+package webhookstore
+
+import (
+	dbmodels "hr-tools-backend/models/db"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Provider interface {
+	Create(rec dbmodels.WebhookSubscription) (id string, err error)
+	Update(spaceID, id string, updMap map[string]interface{}) error
+	GetByID(spaceID, id string) (*dbmodels.WebhookSubscription, error)
+	List(spaceID string) ([]dbmodels.WebhookSubscription, error)
+	ListActive(spaceID string, event dbmodels.WebhookEvent) ([]dbmodels.WebhookSubscription, error)
+	// IncFailureCount увеличение счетчика неудачных доставок подряд, возвращает новое значение
+	IncFailureCount(id string) (int, error)
+	Delete(spaceID, id string) error
+}
+
+func NewInstance(DB *gorm.DB) Provider {
+	return &impl{
+		db: DB,
+	}
+}
+
+type impl struct {
+	db *gorm.DB
+}
+
+func (i impl) Create(rec dbmodels.WebhookSubscription) (id string, err error) {
+	err = i.db.
+		Omit(clause.Associations).
+		Save(&rec).
+		Error
+	if err != nil {
+		return "", err
+	}
+	return rec.ID, nil
+}
+
+func (i impl) Update(spaceID, id string, updMap map[string]interface{}) error {
+	if len(updMap) == 0 {
+		return nil
+	}
+	return i.db.
+		Model(&dbmodels.WebhookSubscription{}).
+		Where("space_id = ?", spaceID).
+		Where("id = ?", id).
+		Updates(updMap).
+		Error
+}
+
+func (i impl) GetByID(spaceID, id string) (*dbmodels.WebhookSubscription, error) {
+	rec := dbmodels.WebhookSubscription{}
+	err := i.db.
+		Where("space_id = ?", spaceID).
+		Where("id = ?", id).
+		First(&rec).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (i impl) List(spaceID string) ([]dbmodels.WebhookSubscription, error) {
+	list := []dbmodels.WebhookSubscription{}
+	err := i.db.
+		Where("space_id = ?", spaceID).
+		Order("name").
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (i impl) ListActive(spaceID string, event dbmodels.WebhookEvent) ([]dbmodels.WebhookSubscription, error) {
+	list := []dbmodels.WebhookSubscription{}
+	err := i.db.
+		Where("space_id = ?", spaceID).
+		Where("is_active = ?", true).
+		Where("? = ANY(events)", string(event)).
+		Order("created_at").
+		Find(&list).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (i impl) IncFailureCount(id string) (int, error) {
+	rec := dbmodels.WebhookSubscription{}
+	err := i.db.
+		Model(&rec).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "failure_count"}}}).
+		Where("id = ?", id).
+		UpdateColumn("failure_count", gorm.Expr("failure_count + 1")).
+		Error
+	if err != nil {
+		return 0, err
+	}
+	return rec.FailureCount, nil
+}
+
+func (i impl) Delete(spaceID, id string) error {
+	return i.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Where("space_id = ?", spaceID).
+			Where("subscription_id = ?", id).
+			Delete(&dbmodels.WebhookDelivery{}).
+			Error
+		if err != nil {
+			return err
+		}
+		return tx.
+			Where("space_id = ?", spaceID).
+			Where("id = ?", id).
+			Delete(&dbmodels.WebhookSubscription{}).
+			Error
+	})
+}
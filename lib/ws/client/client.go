@@ -2,24 +2,24 @@ package wsclient
 
 import (
 	"fmt"
-	// connectionhub "hr-tools-backend/lib/ws/hub/connection-hub"
-	// wsmodels "hr-tools-backend/models/ws"
-	// "time"
+	connectionhub "hr-tools-backend/lib/ws/hub/connection-hub"
 
 	"github.com/gofiber/contrib/websocket"
 	log "github.com/sirupsen/logrus"
 )
 
-func NewClient(userID string, c *websocket.Conn) *WsClient {
+func NewClient(userID, sessionID string, c *websocket.Conn) *WsClient {
 	return &WsClient{
-		conn:   c,
-		userID: userID,
+		conn:      c,
+		userID:    userID,
+		sessionID: sessionID,
 	}
 }
 
 type WsClient struct {
-	conn   *websocket.Conn
-	userID string
+	conn      *websocket.Conn
+	userID    string
+	sessionID string
 }
 
 type Message struct {
@@ -48,5 +48,6 @@ func (c *WsClient) Dispatch() {
 			break
 		}
 		log.WithField("ws_message", fmt.Sprintf("%+v", data)).Debug("ws-msg")
+		connectionhub.Instance.HandleMessage(c.userID, c.sessionID, data)
 	}
 }
@@ -20,7 +20,9 @@ func InitWs(app *fiber.App) {
 
 // @Summary Системные пуши
 // @Tags Websocket Системные пуши
-// @Description Системные пуши
+// @Description Системные пуши. Сообщения с type=push требуют подтверждения получения: {"type":"ack","ids":["<id>"]},
+// @Description неподтвержденные события повторно отправляются при следующем подключении.
+// @Description Проверка соединения: {"type":"ping"}, ответ - сообщение с type=pong
 // @Param   Authorization		header		string		true		"Authorization token"
 // @Success 200 {object} wsmodels.ServerMessage
 // @Failure 400
@@ -30,10 +32,10 @@ func InitWs(app *fiber.App) {
 func supportHandler(c *websocket.Conn) {
 
 	userID := c.Locals("userID").(string)
-	client := wsclient.NewClient(userID, c)
-	connectionhub.Instance.AddClient(userID, c)
+	sessionID := connectionhub.Instance.AddClient(userID, c)
+	client := wsclient.NewClient(userID, sessionID, c)
 	defer func() {
-		connectionhub.Instance.DeleteClient(userID)
+		connectionhub.Instance.DeleteClient(userID, sessionID)
 	}()
 	client.Dispatch()
 }
@@ -0,0 +1,66 @@
+package connectionhub
+
+import (
+	"context"
+	wsmodels "hr-tools-backend/models/ws"
+	"sync"
+)
+
+const (
+	BackendPostgres = "postgres" // рассылка между экземплярами через LISTEN/NOTIFY
+	BackendLocal    = "local"    // рассылка только в текущем экземпляре
+)
+
+// Backend транспорт рассылки событий хаба между экземплярами приложения
+type Backend interface {
+	// Publish отправка события всем экземплярам, включая текущий
+	Publish(payload []byte) error
+	// Listen подписка на события до завершения ctx
+	Listen(ctx context.Context, handler func(payload []byte)) error
+}
+
+type hubEventType string
+
+const (
+	hubEventMessage hubEventType = "message" // отправка сообщения во все соединения пользователя
+	hubEventClose   hubEventType = "close"   // закрытие всех соединений пользователя
+)
+
+type hubEvent struct {
+	Type    hubEventType            `json:"type"`
+	UserID  string                  `json:"user_id"`
+	Message *wsmodels.ServerMessage `json:"message,omitempty"`
+}
+
+// NewLocalBackend транспорт в пределах текущего экземпляра, для запуска в одном экземпляре
+func NewLocalBackend() Backend {
+	return &localBackend{}
+}
+
+type localBackend struct {
+	mu      sync.RWMutex
+	handler func(payload []byte)
+}
+
+func (b *localBackend) Publish(payload []byte) error {
+	b.mu.RLock()
+	handler := b.handler
+	b.mu.RUnlock()
+	if handler != nil {
+		handler(payload)
+	}
+	return nil
+}
+
+func (b *localBackend) Listen(ctx context.Context, handler func(payload []byte)) error {
+	b.mu.Lock()
+	b.handler = handler
+	b.mu.Unlock()
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		b.handler = nil
+		b.mu.Unlock()
+	}()
+	return nil
+}
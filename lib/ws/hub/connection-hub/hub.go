@@ -1,104 +1,274 @@
 package connectionhub
 
 import (
+	"context"
+	"encoding/json"
+	"hr-tools-backend/config"
 	"hr-tools-backend/db"
 	pushdatastore "hr-tools-backend/lib/space/push/data-store"
 	wsmodels "hr-tools-backend/models/ws"
+	"sync"
+	"time"
 
 	"github.com/gofiber/contrib/websocket"
 	log "github.com/sirupsen/logrus"
 )
 
 type Provider interface {
-	AddClient(userID string, conn *websocket.Conn)
-	DeleteClient(userID string)
+	// AddClient регистрация соединения, у пользователя может быть несколько соединений (вкладки, устройства)
+	AddClient(userID string, conn *websocket.Conn) (sessionID string)
+	DeleteClient(userID, sessionID string)
+	// SendMessage отправка сообщения во все соединения пользователя на всех экземплярах приложения
 	SendMessage(msg wsmodels.ServerMessage)
+	// SendClose закрытие всех соединений пользователя на всех экземплярах приложения
 	SendClose(userID string)
+	// IsConnected есть соединения пользователя на текущем экземпляре
 	IsConnected(userID string) bool
+	// HandleMessage обработка сообщения клиента: подтверждение получения событий, heartbeat
+	HandleMessage(userID, sessionID string, data []byte)
 }
 
 var Instance Provider
 
-func Init() {
-	Instance = &impl{
-		clients: map[string]clientSession{},
-		store:   pushdatastore.NewInstance(db.DB),
+func Init(ctx context.Context) {
+	conf := config.Conf.WsHub
+	backend := NewLocalBackend()
+	if conf.Backend != BackendLocal {
+		connString := db.ConnString(config.Conf.Database.Host, config.Conf.Database.Port, config.Conf.Database.Name,
+			config.Conf.Database.User, config.Conf.Database.Password)
+		backend = NewPgBackend(db.DB, connString, conf.Channel)
+	}
+	instance := newHub(backend, pushdatastore.NewInstance(db.DB),
+		time.Duration(conf.PingIntervalSec)*time.Second, time.Duration(conf.PongTimeoutSec)*time.Second,
+		time.Duration(conf.PendingTTLHours)*time.Hour, conf.PendingMax)
+	err := backend.Listen(ctx, instance.onEvent)
+	if err != nil {
+		log.WithError(err).Error("ошибка подписки на события хаба websocket, события доставляются только в текущем экземпляре")
+		instance.backend = NewLocalBackend()
+		_ = instance.backend.Listen(ctx, instance.onEvent)
+	}
+	go instance.cleanupPending(ctx)
+	Instance = instance
+}
+
+// pendingCleanupInterval период удаления неподтвержденных событий с истекшим сроком хранения
+const pendingCleanupInterval = time.Hour
+
+func newHub(backend Backend, store pushdatastore.Provider, pingInterval, pongTimeout, pendingTTL time.Duration, pendingMax int) *impl {
+	return &impl{
+		clients:      map[string]map[string]*clientSession{},
+		store:        store,
+		backend:      backend,
+		pingInterval: pingInterval,
+		pongTimeout:  pongTimeout,
+		pendingTTL:   pendingTTL,
+		pendingMax:   pendingMax,
 	}
 }
 
 type impl struct {
-	clients map[string]clientSession //map[userID]
-	store   pushdatastore.Provider
+	mu           sync.RWMutex
+	clients      map[string]map[string]*clientSession //map[userID]map[sessionID]
+	store        pushdatastore.Provider
+	backend      Backend
+	pingInterval time.Duration
+	pongTimeout  time.Duration
+	pendingTTL   time.Duration // срок хранения неподтвержденных событий
+	pendingMax   int           // количество последних неподтвержденных событий, отправляемых при подключении
+}
+
+func (i *impl) AddClient(userID string, conn *websocket.Conn) string {
+	return i.addSession(userID, conn)
 }
 
-func (i *impl) DeleteClient(userID string) {
-	sess, ok := i.clients[userID]
+func (i *impl) addSession(userID string, conn wsConn) string {
+	sess := newSession(conn, i.pingInterval, i.pongTimeout)
+	i.mu.Lock()
+	sessions, ok := i.clients[userID]
 	if !ok {
-		return
+		sessions = map[string]*clientSession{}
+		i.clients[userID] = sessions
 	}
-	delete(i.clients, userID)
-	sess.stop()
-	close(sess.sendCh)
+	sessions[sess.id] = sess
+	i.mu.Unlock()
+	go i.sendDelayedMessages(userID, sess)
+	return sess.id
 }
 
-func (i *impl) AddClient(userID string, conn *websocket.Conn) {
-	oldSess, ok := i.clients[userID]
+func (i *impl) DeleteClient(userID, sessionID string) {
+	i.mu.Lock()
+	sess, ok := i.clients[userID][sessionID]
+	if ok {
+		delete(i.clients[userID], sessionID)
+		if len(i.clients[userID]) == 0 {
+			delete(i.clients, userID)
+		}
+	}
+	i.mu.Unlock()
 	if ok {
-		oldSess.stop()
+		sess.stop()
 	}
-	i.clients[userID] = newSession(conn)
-	go i.sendDelayedMessages(userID)
 }
 
 func (i *impl) SendMessage(msg wsmodels.ServerMessage) {
-	userID := msg.ToUserID
-	sess, ok := i.clients[userID]
-	if ok {
-		sess.sendCh <- msg
-	}
+	i.publish(hubEvent{
+		Type:    hubEventMessage,
+		UserID:  msg.ToUserID,
+		Message: &msg,
+	})
 }
 
 func (i *impl) SendClose(userID string) {
-	sess, ok := i.clients[userID]
-	if ok {
-		sess.stop()
-	}
+	i.publish(hubEvent{
+		Type:   hubEventClose,
+		UserID: userID,
+	})
 }
 
 func (i *impl) IsConnected(userID string) bool {
-	sess, ok := i.clients[userID]
-	if !ok || sess.conn == nil || sess.conn.Conn == nil {
-		return false
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return len(i.clients[userID]) != 0
+}
+
+func (i *impl) HandleMessage(userID, sessionID string, data []byte) {
+	logger := log.
+		WithField("user_id", userID).
+		WithField("session_id", sessionID)
+	sess := i.getSession(userID, sessionID)
+	if sess == nil {
+		return
+	}
+	sess.touch()
+	var msg wsmodels.ClientMessage
+	err := json.Unmarshal(data, &msg)
+	if err != nil {
+		logger.WithError(err).Debug("некорректное сообщение клиента")
+		return
+	}
+	switch msg.Type {
+	case wsmodels.ClientMessagePing:
+		sess.push(wsmodels.ServerMessage{
+			ToUserID: userID,
+			Type:     wsmodels.ServerMessagePong,
+			Time:     time.Now().Format("02.01.2006 15:04:05"),
+		})
+	case wsmodels.ClientMessageAck:
+		if len(msg.IDs) == 0 {
+			return
+		}
+		err = i.store.DeleteByUser(userID, msg.IDs)
+		if err != nil {
+			logger.WithError(err).Error("ошибка удаления полученных событий")
+		}
+	}
+}
+
+// publish рассылка события через транспорт, при ошибке транспорта - доставка в текущем экземпляре
+func (i *impl) publish(event hubEvent) {
+	logger := log.
+		WithField("user_id", event.UserID).
+		WithField("event_type", event.Type)
+	payload, err := json.Marshal(event)
+	if err != nil {
+		logger.WithError(err).Error("ошибка формирования события хаба websocket")
+		return
+	}
+	err = i.backend.Publish(payload)
+	if err != nil {
+		logger.WithError(err).Error("ошибка рассылки события хаба websocket, событие доставляется только в текущем экземпляре")
+		i.dispatch(event)
+	}
+}
+
+func (i *impl) onEvent(payload []byte) {
+	var event hubEvent
+	err := json.Unmarshal(payload, &event)
+	if err != nil {
+		log.WithError(err).Error("некорректное событие хаба websocket")
+		return
 	}
-	return true
+	i.dispatch(event)
 }
 
-func (i *impl) sendDelayedMessages(userID string) {
+// dispatch доставка события в соединения пользователя на текущем экземпляре
+func (i *impl) dispatch(event hubEvent) {
+	sessions := i.getSessions(event.UserID)
+	switch event.Type {
+	case hubEventMessage:
+		if event.Message == nil {
+			return
+		}
+		msg := *event.Message
+		msg.ToUserID = event.UserID
+		for _, sess := range sessions {
+			sess.push(msg)
+		}
+	case hubEventClose:
+		for _, sess := range sessions {
+			sess.stop()
+		}
+	}
+}
+
+func (i *impl) getSessions(userID string) []*clientSession {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	result := make([]*clientSession, 0, len(i.clients[userID]))
+	for _, sess := range i.clients[userID] {
+		result = append(result, sess)
+	}
+	return result
+}
+
+func (i *impl) getSession(userID, sessionID string) *clientSession {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.clients[userID][sessionID]
+}
+
+// sendDelayedMessages отправка последних неподтвержденных событий в новое соединение,
+// события удаляются после подтверждения получения клиентом или по истечении срока хранения
+func (i *impl) sendDelayedMessages(userID string, sess *clientSession) {
 	logger := log.WithField("user_id", userID)
-	list, err := i.store.List(userID)
+	list, err := i.store.List(userID, time.Now().Add(-i.pendingTTL), i.pendingMax)
 	if err != nil {
 		logger.WithError(err).Error("ошибка получения списка не отправленных событий")
 		return
 	}
-	sendedIDs := []string{}
 	for _, item := range list {
-		if i.IsConnected(userID) {
-			msg := wsmodels.ServerMessage{
-				ToUserID: userID,
-				Time:     item.CreatedAt.Format("02.01.2006 15:04:05"),
-				Code:     string(item.Code),
-				Msg:      item.Msg,
-				Title:    item.Title,
-			}
-			i.SendMessage(msg)
-			sendedIDs = append(sendedIDs, item.ID)
+		msg := wsmodels.ServerMessage{
+			ToUserID: userID,
+			ID:       item.ID,
+			Type:     wsmodels.ServerMessagePush,
+			Time:     item.CreatedAt.Format("02.01.2006 15:04:05"),
+			Code:     string(item.Code),
+			Msg:      item.Msg,
+			Title:    item.Title,
+		}
+		if !sess.pushWait(msg) {
+			return
 		}
 	}
-	if len(sendedIDs) > 0 {
-		err = i.store.Delete(sendedIDs)
-		if err != nil {
-			logger.WithError(err).Error("ошибка удаления отправленных событий")
+}
+
+// cleanupPending периодическое удаление неподтвержденных событий с истекшим сроком хранения
+func (i *impl) cleanupPending(ctx context.Context) {
+	ticker := time.NewTicker(pendingCleanupInterval)
+	defer ticker.Stop()
+	for {
+		i.deleteExpiredPending()
+		select {
+		case <-ctx.Done():
 			return
+		case <-ticker.C:
 		}
 	}
 }
+
+func (i *impl) deleteExpiredPending() {
+	err := i.store.DeleteBefore(time.Now().Add(-i.pendingTTL))
+	if err != nil {
+		log.WithError(err).Error("ошибка удаления неподтвержденных событий с истекшим сроком хранения")
+	}
+}
@@ -0,0 +1,221 @@
+package connectionhub
+
+import (
+	"context"
+	dbmodels "hr-tools-backend/models/db"
+	wsmodels "hr-tools-backend/models/ws"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeConn struct {
+	mu     sync.Mutex
+	msgs   []wsmodels.ServerMessage
+	closed bool
+}
+
+func (c *fakeConn) WriteJSON(v interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.msgs = append(c.msgs, v.(wsmodels.ServerMessage))
+	return nil
+}
+
+func (c *fakeConn) WriteControl(messageType int, data []byte, deadline time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if messageType == websocket.CloseMessage {
+		c.closed = true
+	}
+	return nil
+}
+
+func (c *fakeConn) SetReadDeadline(t time.Time) error { return nil }
+
+func (c *fakeConn) SetPongHandler(h func(appData string) error) {}
+
+func (c *fakeConn) messages() []wsmodels.ServerMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]wsmodels.ServerMessage{}, c.msgs...)
+}
+
+func (c *fakeConn) isClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+type fakeStore struct {
+	mu           sync.Mutex
+	list         []dbmodels.PushData
+	deleted      map[string][]string
+	deleteBefore time.Time
+}
+
+func (s *fakeStore) Create(rec dbmodels.PushData) (string, error) { return rec.ID, nil }
+
+func (s *fakeStore) List(userID string, since time.Time, limit int) ([]dbmodels.PushData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := []dbmodels.PushData{}
+	for _, item := range s.list {
+		if item.UserID == userID && item.CreatedAt.After(since) {
+			result = append(result, item)
+		}
+	}
+	if len(result) > limit {
+		result = result[len(result)-limit:]
+	}
+	return result, nil
+}
+
+func (s *fakeStore) Delete(ids []string) error { return nil }
+
+func (s *fakeStore) DeleteByUser(userID string, ids []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deleted[userID] = append(s.deleted[userID], ids...)
+	return nil
+}
+
+func (s *fakeStore) DeleteBefore(before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deleteBefore = before
+	return nil
+}
+
+func newTestHub(t *testing.T, store *fakeStore) *impl {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	backend := NewLocalBackend()
+	hub := newHub(backend, store, time.Hour, time.Minute, 24*time.Hour, 2)
+	require.NoError(t, backend.Listen(ctx, hub.onEvent))
+	return hub
+}
+
+func TestHubMultiSession(t *testing.T) {
+	store := &fakeStore{
+		list:    []dbmodels.PushData{{BaseModel: dbmodels.BaseModel{ID: "pending", CreatedAt: time.Now()}, UserID: "user", Msg: "old"}},
+		deleted: map[string][]string{},
+	}
+	hub := newTestHub(t, store)
+	conn1, conn2, other := &fakeConn{}, &fakeConn{}, &fakeConn{}
+	sess1 := hub.addSession("user", conn1)
+	hub.addSession("user", conn2)
+	hub.addSession("other", other)
+	require.True(t, hub.IsConnected("user"))
+
+	// неподтвержденное событие получают оба соединения
+	require.Eventually(t, func() bool {
+		return len(conn1.messages()) == 1 && len(conn2.messages()) == 1
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, "pending", conn1.messages()[0].ID)
+	require.Equal(t, wsmodels.ServerMessagePush, conn1.messages()[0].Type)
+
+	hub.SendMessage(wsmodels.ServerMessage{ToUserID: "user", ID: "new", Type: wsmodels.ServerMessagePush, Msg: "new"})
+	require.Eventually(t, func() bool {
+		return len(conn1.messages()) == 2 && len(conn2.messages()) == 2
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, "new", conn2.messages()[1].ID)
+	require.Empty(t, other.messages())
+
+	hub.HandleMessage("user", sess1, []byte(`{"type":"ack","ids":["pending","new"]}`))
+	require.Equal(t, []string{"pending", "new"}, store.deleted["user"])
+
+	hub.HandleMessage("user", sess1, []byte(`{"type":"ping"}`))
+	require.Eventually(t, func() bool {
+		return len(conn1.messages()) == 3
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, wsmodels.ServerMessagePong, conn1.messages()[2].Type)
+
+	hub.DeleteClient("user", sess1)
+	require.Eventually(t, conn1.isClosed, time.Second, 10*time.Millisecond)
+	require.True(t, hub.IsConnected("user"))
+
+	hub.SendClose("user")
+	require.Eventually(t, conn2.isClosed, time.Second, 10*time.Millisecond)
+	require.False(t, other.isClosed())
+}
+
+// blockingConn соединение, запись в которое не завершается до release
+type blockingConn struct {
+	fakeConn
+	release chan struct{}
+}
+
+func (c *blockingConn) WriteJSON(v interface{}) error {
+	<-c.release
+	return c.fakeConn.WriteJSON(v)
+}
+
+func TestHubSlowSession(t *testing.T) {
+	hub := newTestHub(t, &fakeStore{deleted: map[string][]string{}})
+	slow := &blockingConn{release: make(chan struct{})}
+	fast := &fakeConn{}
+	slowID := hub.addSession("user", slow)
+	hub.addSession("user", fast)
+	slowSess := hub.getSession("user", slowID)
+
+	// рассылка не ожидает соединение, не успевающее принимать сообщения
+	for n := 1; n <= sendBufferSize+2; n++ {
+		done := make(chan struct{})
+		go func() {
+			hub.SendMessage(wsmodels.ServerMessage{ToUserID: "user", Type: wsmodels.ServerMessagePush})
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("рассылка заблокирована медленным соединением")
+		}
+		require.Eventually(t, func() bool {
+			return len(fast.messages()) == n
+		}, time.Second, time.Millisecond)
+	}
+
+	// переполненное соединение закрывается
+	select {
+	case <-slowSess.ctx.Done():
+	default:
+		t.Fatal("медленное соединение не закрыто")
+	}
+	close(slow.release)
+	require.Eventually(t, slow.isClosed, time.Second, 10*time.Millisecond)
+}
+
+func TestHubPendingLimits(t *testing.T) {
+	now := time.Now()
+	pending := func(id string, createdAt time.Time) dbmodels.PushData {
+		return dbmodels.PushData{BaseModel: dbmodels.BaseModel{ID: id, CreatedAt: createdAt}, UserID: "user"}
+	}
+	store := &fakeStore{
+		list: []dbmodels.PushData{
+			pending("expired", now.Add(-48*time.Hour)),
+			pending("first", now.Add(-3*time.Hour)),
+			pending("second", now.Add(-2*time.Hour)),
+			pending("third", now.Add(-time.Hour)),
+		},
+		deleted: map[string][]string{},
+	}
+	hub := newTestHub(t, store)
+	conn := &fakeConn{}
+	hub.addSession("user", conn)
+
+	// при подключении отправляются только последние события в пределах срока хранения
+	require.Eventually(t, func() bool {
+		return len(conn.messages()) == 2
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, "second", conn.messages()[0].ID)
+	require.Equal(t, "third", conn.messages()[1].ID)
+
+	hub.deleteExpiredPending()
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	require.WithinDuration(t, now.Add(-24*time.Hour), store.deleteBefore, time.Minute)
+}
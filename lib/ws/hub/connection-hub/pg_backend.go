@@ -0,0 +1,79 @@
+package connectionhub
+
+import (
+	"context"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	pgMinReconnectInterval = time.Second
+	pgMaxReconnectInterval = time.Minute
+	pgPingInterval         = 90 * time.Second
+)
+
+// NewPgBackend транспорт через Postgres LISTEN/NOTIFY, connString - строка подключения для отдельного соединения LISTEN.
+// Размер события ограничен размером payload NOTIFY (8000 байт)
+func NewPgBackend(DB *gorm.DB, connString, channel string) Backend {
+	return &pgBackend{
+		db:         DB,
+		connString: connString,
+		channel:    channel,
+	}
+}
+
+type pgBackend struct {
+	db         *gorm.DB
+	connString string
+	channel    string
+}
+
+func (b *pgBackend) Publish(payload []byte) error {
+	return b.db.
+		Exec("SELECT pg_notify(?, ?)", b.channel, string(payload)).
+		Error
+}
+
+func (b *pgBackend) Listen(ctx context.Context, handler func(payload []byte)) error {
+	logger := log.WithField("channel", b.channel)
+	listener := pq.NewListener(b.connString, pgMinReconnectInterval, pgMaxReconnectInterval,
+		func(event pq.ListenerEventType, err error) {
+			switch event {
+			case pq.ListenerEventDisconnected, pq.ListenerEventConnectionAttemptFailed:
+				logger.WithError(err).Warn("потеряно соединение LISTEN хаба websocket")
+			case pq.ListenerEventReconnected:
+				logger.Info("восстановлено соединение LISTEN хаба websocket")
+			}
+		})
+	if err := listener.Listen(b.channel); err != nil {
+		listener.Close()
+		return errors.Wrap(err, "ошибка подписки на канал хаба websocket")
+	}
+	go func() {
+		defer listener.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case notification := <-listener.Notify:
+				// nil - соединение восстановлено, события за время разрыва не получены,
+				// недоставленные события отправятся при следующем подключении клиента
+				if notification == nil {
+					continue
+				}
+				handler([]byte(notification.Extra))
+			case <-time.After(pgPingInterval):
+				go func() {
+					if err := listener.Ping(); err != nil {
+						logger.WithError(err).Warn("ошибка проверки соединения LISTEN хаба websocket")
+					}
+				}()
+			}
+		}
+	}()
+	return nil
+}
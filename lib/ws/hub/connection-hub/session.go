@@ -2,67 +2,120 @@ package connectionhub
 
 import (
 	"context"
+	wsmodels "hr-tools-backend/models/ws"
+	"time"
+
 	"github.com/gofiber/contrib/websocket"
+	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
-	"time"
 )
 
+const (
+	sendBufferSize = 16
+	writeWait      = 10 * time.Second
+)
+
+// wsConn операции соединения, используемые сессией
+type wsConn interface {
+	WriteJSON(v interface{}) error
+	WriteControl(messageType int, data []byte, deadline time.Time) error
+	SetReadDeadline(t time.Time) error
+	SetPongHandler(h func(appData string) error)
+}
+
+// clientSession одно соединение пользователя (вкладка, устройство)
 type clientSession struct {
-	conn *websocket.Conn
+	id   string
+	conn wsConn
 
 	// Outbound mesages, buffered.
-	// The content must be serialized in format suitable for the session.
-	sendCh chan any
-	stop   func()
+	sendCh      chan wsmodels.ServerMessage
+	ctx         context.Context
+	stop        func()
+	pongTimeout time.Duration
 }
 
-func newSession(conn *websocket.Conn) clientSession {
-	ctx, cancelFn := context.WithCancel(context.TODO())
-	sess := clientSession{
-		stop:   cancelFn,
-		conn:   conn,
-		sendCh: make(chan any, 1), // buffered,
+func newSession(conn wsConn, pingInterval, pongTimeout time.Duration) *clientSession {
+	ctx, cancelFn := context.WithCancel(context.Background())
+	sess := &clientSession{
+		id:          uuid.NewString(),
+		conn:        conn,
+		sendCh:      make(chan wsmodels.ServerMessage, sendBufferSize),
+		ctx:         ctx,
+		stop:        cancelFn,
+		pongTimeout: pongTimeout,
 	}
-	go sess.startSend(ctx)
+	sess.touch()
+	conn.SetPongHandler(func(string) error {
+		sess.touch()
+		return nil
+	})
+	go sess.startSend(pingInterval)
 	return sess
 }
 
-func (s clientSession) startSend(ctx context.Context) {
+// touch продление ожидания ответа клиента, при отсутствии pong или сообщений чтение завершается ошибкой
+func (s *clientSession) touch() {
+	err := s.conn.SetReadDeadline(time.Now().Add(s.pongTimeout))
+	if err != nil {
+		log.WithError(err).Debug("ошибка установки времени ожидания сообщений")
+	}
+}
+
+// push постановка сообщения в очередь отправки без ожидания.
+// Соединение, не успевающее принимать сообщения, закрывается: клиент переподключается и получает неподтвержденные события
+func (s *clientSession) push(msg wsmodels.ServerMessage) bool {
+	select {
+	case s.sendCh <- msg:
+		return true
+	case <-s.ctx.Done():
+		return false
+	default:
+		log.WithField("session_id", s.id).Warn("очередь отправки сообщений переполнена, соединение закрывается")
+		s.stop()
+		return false
+	}
+}
+
+// pushWait постановка сообщения в очередь отправки с ожиданием места в очереди до закрытия соединения
+func (s *clientSession) pushWait(msg wsmodels.ServerMessage) bool {
+	select {
+	case s.sendCh <- msg:
+		return true
+	case <-s.ctx.Done():
+		return false
+	}
+}
+
+func (s *clientSession) startSend(pingInterval time.Duration) {
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
 	for {
 		select {
-		case <-ctx.Done():
+		case <-s.ctx.Done():
 			s.close()
 			return
-		case msg, opened := <-s.sendCh:
-			if !opened {
-				return
-			}
-			_, err := s.send(s.conn, msg)
+		case msg := <-s.sendCh:
+			err := s.conn.WriteJSON(msg)
 			if err != nil {
 				log.WithError(err).Error("ошибка отправки сообщения")
+				s.stop()
+				continue
+			}
+			log.Infof("отправлено сообщение: %+v", msg)
+		case <-ticker.C:
+			err := s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait))
+			if err != nil {
+				log.WithError(err).Debug("ошибка отправки ping")
+				s.stop()
 			}
 		}
 	}
 }
 
-func (s clientSession) send(conn *websocket.Conn, msg interface{}) (bool, error) {
-	if conn.Conn == nil {
-		return false, nil
-	}
-	err := conn.WriteJSON(msg)
-	if err != nil {
-		return false, err
-	}
-	log.Infof("отправлено сообщение: %s", msg)
-	return true, nil
-}
-
-func (s clientSession) close() {
-	if s.conn == nil || s.conn.Conn == nil {
-		return
-	}
+func (s *clientSession) close() {
 	err := s.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Millisecond))
 	if err != nil {
-		log.WithError(err).Error("cant close")
+		log.WithError(err).Debug("cant close")
 	}
 }
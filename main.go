@@ -67,7 +67,7 @@ func main() {
 	apiV1.Use(fiberlog.New(*initializers.LoggerConfig))
 	app.Mount("/api/v1", apiV1)
 	apiV1.Use(cors.New(cors.Config{
-		AllowHeaders:  "Origin, Content-Type, Accept, Authorization, Content-Disposition, X-Filename",
+		AllowHeaders:  "Origin, Content-Type, Accept, Authorization, Content-Disposition, X-Filename, X-Api-Key",
 		AllowMethods:  "GET, POST, PATCH, DELETE, PUT",
 		ExposeHeaders: "Content-Disposition",
 	}))
@@ -77,6 +77,7 @@ func main() {
 	apiv1.InitSpaceUserRouters(apiV1)
 	apiv1.InitGptApiRouters(apiV1)
 	apiv1.InitOAuthApiRouters(apiV1)
+	apiv1.InitSsoApiRouters(apiV1)
 	apiv1.InitAIRouters(apiV1)
 
 	//dict
@@ -92,27 +93,44 @@ func main() {
 	dict.InitRejectReasonDictApiRouters(dicts)
 	dict.InitLangDictApiRouters(dicts)
 	dict.InitRoleDictApiRouters(dicts)
+	dict.InitApplicantSourceDictApiRouters(dicts)
 
 	//space
 	space := fiber.New()
 	apiV1.Mount("/space", space)
 	space.Use(middleware.AuthorizationRequired())
+	space.Use(middleware.AuditLog())
 	apiv1.InitVacancyRequestApiRouters(space)
+	apiv1.InitApprovalRouteApiRouters(space)
+	apiv1.InitAutomationApiRouters(space)
 	apiv1.InitVacancyApiRouters(space)
 	apiv1.InitSpaceSettingRouters(space)
+	apiv1.InitWebhookApiRouters(space)
+	apiv1.InitApiKeyApiRouters(space)
+	apiv1.InitSpaceRoleApiRouters(space)
+	apiv1.InitAuditLogApiRouters(space)
+	apiv1.InitCustomFieldApiRouters(space)
+	apiv1.InitSsoSettingsApiRouters(space)
 	apiv1.InitSpaceProfileRouters(space)
 	apiv1.InitMsgTemplateApiRouters(space)
 	apiv1.InitNegotiationApiRouters(space)
 	apiv1.InitApplicantApiRouters(space)
+	apiv1.InitCandidateApiRouters(space)
+	apiv1.InitPersonalDataApiRouters(space)
 	apiv1.InitAnalyticsApiRouters(space)
 	apiv1.InitMessengerApiRouters(space)
 	apiv1.InitSupersetApiRouters(space)
 	apiv1.InitBillingApiRouters(space)
+	apiv1.InitInterviewApiRouters(space)
+	apiv1.InitCalendarApiRouters(space)
+	apiv1.InitScorecardApiRouters(space)
+	apiv1.InitOfferApiRouters(space)
 
 	ext := fiber.New()
 	space.Mount("/ext", ext)
 	externalapiv1.InitHHApiRouters(ext)
 	externalapiv1.InitAvitoApiRouters(ext)
+	externalapiv1.InitConnectorApiRouters(ext)
 
 	//админка
 	adminPanel := fiber.New()
@@ -123,6 +141,10 @@ func main() {
 	public := fiber.New()
 	apiV1.Mount("/public", public)
 	publicapi.InitPublicSurveyApiRouters(public)
+	publicapi.InitCareerSiteApiRouters(public)
+	publicapi.InitPublicInterviewApiRouters(public)
+	publicapi.InitPublicOfferApiRouters(public)
+	publicapi.InitPublicTelegramApiRouters(public)
 
 	app.Hooks().OnShutdown()
 
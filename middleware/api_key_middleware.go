@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	apikey "hr-tools-backend/lib/api-key"
+	"hr-tools-backend/lib/rbac"
+	authutils "hr-tools-backend/lib/utils/auth-utils"
+	apimodels "hr-tools-backend/models/api"
+
+	"github.com/gofiber/fiber/v2"
+	log "github.com/sirupsen/logrus"
+)
+
+const ApiKeyHeader = "X-Api-Key"
+
+// apiKeyAuth авторизация запроса по API ключу: метод должен входить в области доступа ключа,
+// далее запрос проходит те же проверки RBAC, что и запрос пользователя, от служебной учетной записи ключа
+func apiKeyAuth(ctx *fiber.Ctx, key string) error {
+	rec, err := apikey.Instance.Authenticate(key, ctx.IP())
+	if err != nil {
+		log.WithError(err).Error("ошибка проверки API ключа")
+		return ctx.Status(fiber.StatusInternalServerError).JSON(apimodels.NewError("Ошибка проверки API ключа"))
+	}
+	if rec == nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(apimodels.NewError("Недействительный API ключ"))
+	}
+	rule, found := rbac.Instance.GetRule(ctx.Method(), ctx.Path())
+	if !found || !rec.Scopes.Has(rule.Module, rule.Permission) {
+		return ctx.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "RBAC_FORBIDDEN",
+		})
+	}
+	ctx.Locals("user", authutils.GetApiKeyToken(rec.ServiceUserID, rec.ServiceUser.GetFullName(), rec.SpaceID, rec.Role, rec.ID))
+	return ctx.Next()
+}
+
+// UserRequired метод доступен только пользователю, запросы по API ключу отклоняются
+func UserRequired() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		if GetApiKeyID(ctx) != "" {
+			return ctx.Status(fiber.StatusForbidden).JSON(apimodels.NewError("операция недоступна для API ключа"))
+		}
+		return ctx.Next()
+	}
+}
+
+func GetApiKeyID(ctx *fiber.Ctx) string {
+	claims := authutils.GetClaims(ctx)
+	if id, exist := claims["api_key"]; exist {
+		if stringID, ok := id.(string); ok {
+			return stringID
+		}
+	}
+	return ""
+}
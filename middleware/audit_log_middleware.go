@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	auditlog "hr-tools-backend/lib/audit-log"
+	authutils "hr-tools-backend/lib/utils/auth-utils"
+	"hr-tools-backend/models"
+	dbmodels "hr-tools-backend/models/db"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pkg/errors"
+)
+
+const apiPrefix = "/api/v1/"
+
+// AuditLog запись изменяющих запросов пользователей спейса в журнал аудита: инициатор, метод, адрес, код ответа, IP и браузер.
+// Чтение, а также поиск и списки (POST .../list, .../find) не записываются
+func AuditLog() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		err := ctx.Next()
+		method := ctx.Method()
+		if method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions {
+			return err
+		}
+		parts := strings.Split(ctx.Path(), "/")
+		if noLicenseRequiredMap[parts[len(parts)-1]] == method {
+			return err
+		}
+		spaceID := GetUserSpace(ctx)
+		if spaceID == "" {
+			return err
+		}
+		rec := dbmodels.AuditLog{
+			BaseSpaceModel: dbmodels.BaseSpaceModel{SpaceID: spaceID},
+			ActorType:      models.AuditActorUser,
+			ActorID:        GetUserID(ctx),
+			ActorName:      getUserName(ctx),
+			Action:         models.AuditActionRequest,
+			EntityType:     models.AuditEntity(getApiSection(ctx)),
+			EntityID:       ctx.Params("id"),
+			Method:         method,
+			Path:           ctx.Path(),
+			Status:         getResponseStatus(ctx, err),
+			IP:             ctx.IP(),
+			UserAgent:      ctx.Get(fiber.HeaderUserAgent),
+		}
+		if GetApiKeyID(ctx) != "" {
+			rec.ActorType = models.AuditActorApiKey
+		}
+		auditlog.Instance.Save(rec)
+		return err
+	}
+}
+
+// getApiSection раздел API (первый сегмент адреса маршрута после /api/v1/ и /api/v1/space/)
+func getApiSection(ctx *fiber.Ctx) string {
+	path := ctx.Path()
+	if route := ctx.Route(); route != nil {
+		path = route.Path
+	}
+	path = strings.TrimPrefix(path, apiPrefix)
+	path = strings.TrimPrefix(path, "space/")
+	return strings.Split(strings.Trim(path, "/"), "/")[0]
+}
+
+// getResponseStatus код ответа, для ошибки - код, который вернет обработчик ошибок fiber
+func getResponseStatus(ctx *fiber.Ctx, err error) int {
+	if err == nil {
+		return ctx.Response().StatusCode()
+	}
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return fiberErr.Code
+	}
+	return fiber.StatusInternalServerError
+}
+
+func getUserName(ctx *fiber.Ctx) string {
+	claims := authutils.GetClaims(ctx)
+	if name, ok := claims["name"].(string); ok {
+		return name
+	}
+	return ""
+}
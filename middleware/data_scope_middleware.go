@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	datascope "hr-tools-backend/lib/data-scope"
+
+	"github.com/gofiber/fiber/v2"
+	log "github.com/sirupsen/logrus"
+)
+
+// DataScopeRequired проверка, что запись из параметра :id входит в область видимости пользователя.
+// Запись вне области видимости не раскрывается: ответ как для отсутствующей записи
+func DataScopeRequired(entity datascope.Entity) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		id := ctx.Params("id")
+		if id == "" {
+			return ctx.Next()
+		}
+		spaceID := GetUserSpace(ctx)
+		userID := GetUserID(ctx)
+		visible, err := datascope.Instance.IsVisible(spaceID, userID, entity, id)
+		if err != nil {
+			log.
+				WithField("space_id", spaceID).
+				WithField("user_id", userID).
+				WithField("rec_id", id).
+				WithError(err).
+				Error("ошибка проверки области видимости")
+			return ctx.SendStatus(fiber.StatusInternalServerError)
+		}
+		if !visible {
+			return ctx.SendStatus(fiber.StatusNotFound)
+		}
+		return ctx.Next()
+	}
+}
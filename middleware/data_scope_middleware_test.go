@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	datascope "hr-tools-backend/lib/data-scope"
+	datascopefake "hr-tools-backend/lib/data-scope/fake"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataScopeRequired(t *testing.T) {
+	datascopefake.Set(t, datascopefake.DataScope{Visible: map[datascope.Entity][]string{
+		datascope.EntityFile:         {"own-file"},
+		datascope.EntityVideoAnalyze: {"own-analyze"},
+		datascope.EntityCandidate:    {"own-candidate"},
+	}})
+	app := fiber.New()
+	app.Use(func(ctx *fiber.Ctx) error {
+		ctx.Locals("user", &jwt.Token{Claims: jwt.MapClaims{"space": "space", "sub": "user"}})
+		return ctx.Next()
+	})
+	ok := func(ctx *fiber.Ctx) error { return ctx.SendStatus(fiber.StatusOK) }
+	app.Get("doc/:id", DataScopeRequired(datascope.EntityFile), ok)
+	app.Put("analyze-skip/video/:id", DataScopeRequired(datascope.EntityVideoAnalyze), ok)
+	app.Route("candidate/:id", func(idRoute fiber.Router) {
+		idRoute.Use(DataScopeRequired(datascope.EntityCandidate))
+		idRoute.Get("files", ok)
+	})
+
+	cases := []struct {
+		method, path string
+		status       int
+	}{
+		{fiber.MethodGet, "/doc/own-file", fiber.StatusOK},
+		{fiber.MethodGet, "/doc/other-file", fiber.StatusNotFound},
+		{fiber.MethodPut, "/analyze-skip/video/own-analyze", fiber.StatusOK},
+		{fiber.MethodPut, "/analyze-skip/video/other-analyze", fiber.StatusNotFound},
+		{fiber.MethodGet, "/candidate/own-candidate/files", fiber.StatusOK},
+		{fiber.MethodGet, "/candidate/other-candidate/files", fiber.StatusNotFound},
+	}
+	for _, tc := range cases {
+		resp, err := app.Test(httptest.NewRequest(tc.method, tc.path, nil))
+		require.NoError(t, err)
+		require.Equal(t, tc.status, resp.StatusCode, tc.path)
+	}
+}
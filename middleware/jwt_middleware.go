@@ -4,17 +4,29 @@ import (
 	jwtware "github.com/gofiber/contrib/jwt"
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
+	log "github.com/sirupsen/logrus"
 	"hr-tools-backend/config"
+	authsession "hr-tools-backend/lib/auth-session"
+	authutils "hr-tools-backend/lib/utils/auth-utils"
+	apimodels "hr-tools-backend/models/api"
 )
 
+// AuthorizationRequired авторизация по JWT пользователя или по API ключу в заголовке X-Api-Key
 func AuthorizationRequired() fiber.Handler {
-	return jwtware.New(jwtware.Config{
+	jwtHandler := jwtware.New(jwtware.Config{
 		Claims: jwt.MapClaims{},
 		SigningKey: jwtware.SigningKey{
 			JWTAlg: "HS256",
 			Key:    []byte(config.Conf.Auth.JWTSecret),
 		},
+		SuccessHandler: sessionRequired,
 	})
+	return func(ctx *fiber.Ctx) error {
+		if key := ctx.Get(ApiKeyHeader); key != "" {
+			return apiKeyAuth(ctx, key)
+		}
+		return jwtHandler(ctx)
+	}
 }
 
 func AdminPanelAuthorizationRequired() fiber.Handler {
@@ -24,5 +36,23 @@ func AdminPanelAuthorizationRequired() fiber.Handler {
 			JWTAlg: "HS256",
 			Key:    []byte(config.Conf.AdminPanelAuth.JWTSecret),
 		},
+		SuccessHandler: sessionRequired,
 	})
 }
+
+// sessionRequired JWT действует, пока не отозвана сессия входа, в которой он выдан
+func sessionRequired(ctx *fiber.Ctx) error {
+	sessionID := authutils.GetSessionID(ctx)
+	if sessionID == "" {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(apimodels.NewError("сессия завершена, войдите повторно"))
+	}
+	active, err := authsession.Instance.IsActive(sessionID)
+	if err != nil {
+		log.WithError(err).Error("ошибка проверки сессии")
+		return ctx.Status(fiber.StatusInternalServerError).JSON(apimodels.NewError("Ошибка проверки сессии"))
+	}
+	if !active {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(apimodels.NewError("сессия завершена, войдите повторно"))
+	}
+	return ctx.Next()
+}
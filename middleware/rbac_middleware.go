@@ -23,14 +23,8 @@ func RbacMiddleware() fiber.Handler {
 			})
 		}
 
-		// Ищем обработчик
-		handler, found := rbac.Instance.GetRuleFunc(ctx.Method(), ctx.Path())
-		if !found {
-			return ctx.Next()
-		}
-
-		// Выполняем проверку
-		if !handler(spaceID, userID, userRole, ctx.Path()) {
+		// Выполняем проверку по правилу метода с учетом роли спейса пользователя
+		if !rbac.Instance.IsAllowed(spaceID, userID, userRole, ctx.Method(), ctx.Path()) {
 			return ctx.Status(fiber.StatusForbidden).JSON(fiber.Map{
 				"error": "RBAC_FORBIDDEN",
 			})
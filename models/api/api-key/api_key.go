@@ -0,0 +1,83 @@
+package apikeyapimodels
+
+import (
+	"hr-tools-backend/models"
+	dbmodels "hr-tools-backend/models/db"
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type ApiKeyData struct {
+	Name      string                `json:"name"`       // Название ключа (интеграции)
+	Role      models.UserRole       `json:"role"`       // Роль служебной учетной записи ключа
+	Scopes    dbmodels.ApiKeyScopes `json:"scopes"`     // Области доступа: пары модуль/разрешение RBAC
+	ExpiresAt *time.Time            `json:"expires_at"` // Срок действия, пусто - бессрочный
+}
+
+func (r ApiKeyData) Validate() error {
+	if r.Name == "" {
+		return errors.New("не указано название ключа")
+	}
+	if r.Role == "" {
+		return errors.New("не указана роль ключа")
+	}
+	if !slices.Contains(models.AllAvailableRoles, r.Role) {
+		return errors.New("некорректная роль ключа")
+	}
+	if len(r.Scopes) == 0 {
+		return errors.New("не указаны области доступа ключа")
+	}
+	if r.ExpiresAt != nil && !r.ExpiresAt.After(time.Now()) {
+		return errors.New("срок действия ключа должен быть в будущем")
+	}
+	return nil
+}
+
+type ApiKeyView struct {
+	ApiKeyData
+	ID            string     `json:"id"`
+	Prefix        string     `json:"prefix"`          // Начало ключа
+	IsValid       bool       `json:"is_valid"`        // Ключ действует (не отозван и не истек)
+	LastUsedAt    *time.Time `json:"last_used_at"`    // Дата последнего использования
+	LastUsedIP    string     `json:"last_used_ip"`    // IP последнего использования
+	RotatedAt     *time.Time `json:"rotated_at"`      // Дата последней ротации
+	RevokedAt     *time.Time `json:"revoked_at"`      // Дата отзыва
+	ServiceUserID string     `json:"service_user_id"` // Служебная учетная запись, от которой выполняются запросы
+	AuthorName    string     `json:"author_name"`     // Создатель ключа
+	CreatedAt     time.Time  `json:"created_at"`      // Дата создания
+}
+
+func ApiKeyConvert(rec dbmodels.ApiKey) ApiKeyView {
+	result := ApiKeyView{
+		ApiKeyData: ApiKeyData{
+			Name:      rec.Name,
+			Role:      rec.Role,
+			Scopes:    rec.Scopes,
+			ExpiresAt: rec.ExpiresAt,
+		},
+		ID:            rec.ID,
+		Prefix:        rec.Prefix,
+		IsValid:       rec.IsValid(time.Now()),
+		LastUsedAt:    rec.LastUsedAt,
+		LastUsedIP:    rec.LastUsedIP,
+		RotatedAt:     rec.RotatedAt,
+		RevokedAt:     rec.RevokedAt,
+		ServiceUserID: rec.ServiceUserID,
+		CreatedAt:     rec.CreatedAt,
+	}
+	if rec.Author != nil {
+		result.AuthorName = rec.Author.GetFullName()
+	}
+	if result.Scopes == nil {
+		result.Scopes = dbmodels.ApiKeyScopes{}
+	}
+	return result
+}
+
+// ApiKeySecret ключ, возвращается только при создании и ротации
+type ApiKeySecret struct {
+	ID  string `json:"id"`
+	Key string `json:"key"` // Ключ для заголовка X-Api-Key
+}
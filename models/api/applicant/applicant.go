@@ -13,6 +13,7 @@ import (
 type ApplicantView struct {
 	ApplicantData
 	ID                 string                 `json:"id"`                   // Идентификатор кандидата
+	CandidateID        string                 `json:"candidate_id"`         // Идентификатор кандидата (человека), общий для всех вакансий
 	NegotiationID      string                 `json:"negotiation_id"`       // Идентификатор отклика во внешней системе
 	NegotiationDate    string                 `json:"negotiation_date"`     // Дата отклика во внешней системе ДД.ММ.ГГГГ
 	AcceptDate         string                 `json:"accept_date"`          // Дата добавления
@@ -23,6 +24,8 @@ type ApplicantView struct {
 	SelectionStageID   string                 `json:"selection_stage_id"`   // Идентификатор этапа подбора кандидата
 	SelectionStageName string                 `json:"selection_stage_name"` // Название этапа
 	StageTime          string                 `json:"stage_time"`           // Время на этапе
+	StageDeadline      string                 `json:"stage_deadline"`       // Срок нахождения на этапе ДД.ММ.ГГГГ ЧЧ:ММ
+	StageOverdue       bool                   `json:"stage_overdue"`        // Превышен лимит времени на этапе
 	VacancyName        string                 `json:"vacancy_name"`         // Название вакансии
 	FIO                string                 `json:"fio"`                  // ФИО кандидата
 	Age                int                    `json:"age"`                  // возраст
@@ -75,26 +78,73 @@ type ApplicantViewExt struct {
 type ApplicantDuplicate struct {
 	Found         bool                 `json:"found"`          // Найден
 	DuplicateID   string               `json:"duplicate_id"`   // Идентификатор кандидата
-	DuplicateType models.DuplicateType `json:"duplicate_type"` // Тип дубля (По автору резюме/По контактным данным)
+	DuplicateType models.DuplicateType `json:"duplicate_type"` // Тип дубля (По автору резюме/По резюме/По контактным данным/По ФИО)
+	Confidence    float64              `json:"confidence"`     // Уверенность от 0 до 1
+}
+
+// DuplicateSuggestion возможный дубликат кандидата
+type DuplicateSuggestion struct {
+	ApplicantID   string               `json:"applicant_id"`   // Идентификатор кандидата
+	FIO           string               `json:"fio"`            // ФИО
+	Phone         string               `json:"phone"`          // Телефон
+	Email         string               `json:"email"`          // Email
+	DuplicateType models.DuplicateType `json:"duplicate_type"` // Тип дубля (По автору резюме/По резюме/По контактным данным/По ФИО)
+	Confidence    float64              `json:"confidence"`     // Уверенность от 0 до 1
+	Reasons       []string             `json:"reasons"`        // Совпадения
+}
+
+// MergeFields поля, значения которых могут быть перенесены из дубликата при объединении
+var MergeFields = map[string]bool{
+	"first_name":       true,
+	"last_name":        true,
+	"middle_name":      true,
+	"phone":            true,
+	"email":            true,
+	"birth_date":       true,
+	"citizenship":      true,
+	"gender":           true,
+	"relocation":       true,
+	"address":          true,
+	"salary":           true,
+	"total_experience": true,
+	"comment":          true,
+}
+
+type MergeRequest struct {
+	DuplicateID string   `json:"duplicate_id"` // Идентификатор кандидата - дубликата
+	Fields      []string `json:"fields"`       // Поля, значения которых берутся из дубликата (first_name, last_name, middle_name, phone, email, birth_date, citizenship, gender, relocation, address, salary, total_experience, comment), остальные остаются от основного кандидата
+}
+
+func (m MergeRequest) Validate() error {
+	if m.DuplicateID == "" {
+		return errors.New("не указан идентификатор кандидата - дубликата")
+	}
+	for _, field := range m.Fields {
+		if !MergeFields[field] {
+			return errors.Errorf("поле %v не может быть перенесено из дубликата", field)
+		}
+	}
+	return nil
 }
 
 type ApplicantData struct {
-	VacancyID       string                   `json:"vacancy_id"`       // Идентификатор вакансии
-	Source          models.ApplicantSource   `json:"source"`           // Источник кандидата
-	FirstName       string                   `json:"first_name"`       // Имя
-	LastName        string                   `json:"last_name"`        // Фамилия
-	MiddleName      string                   `json:"middle_name"`      // Отчество
-	Phone           string                   `json:"phone"`            // Телефон
-	Email           string                   `json:"email"`            // Емайл
-	Salary          int                      `json:"salary"`           // Желаемая ЗП
-	Address         string                   `json:"address"`          // Адрес
-	BirthDate       string                   `json:"birth_date"`       // Дата рождения ДД.ММ.ГГГГ
-	Citizenship     string                   `json:"citizenship"`      // Гражданство
-	Gender          models.GenderType        `json:"gender"`           // Пол кандидата
-	Relocation      models.RelocationType    `json:"relocation"`       // Готовность к переезду
-	TotalExperience int                      `json:"total_experience"` // Опыт работ в месяцах
-	Comment         string                   `json:"comment"`          // Коментарий
-	Params          dbmodels.ApplicantParams `json:"params"`           // Доподнительные параметры
+	VacancyID       string                     `json:"vacancy_id"`       // Идентификатор вакансии
+	Source          models.ApplicantSource     `json:"source"`           // Источник кандидата
+	FirstName       string                     `json:"first_name"`       // Имя
+	LastName        string                     `json:"last_name"`        // Фамилия
+	MiddleName      string                     `json:"middle_name"`      // Отчество
+	Phone           string                     `json:"phone"`            // Телефон
+	Email           string                     `json:"email"`            // Емайл
+	Salary          int                        `json:"salary"`           // Желаемая ЗП
+	Address         string                     `json:"address"`          // Адрес
+	BirthDate       string                     `json:"birth_date"`       // Дата рождения ДД.ММ.ГГГГ
+	Citizenship     string                     `json:"citizenship"`      // Гражданство
+	Gender          models.GenderType          `json:"gender"`           // Пол кандидата
+	Relocation      models.RelocationType      `json:"relocation"`       // Готовность к переезду
+	TotalExperience int                        `json:"total_experience"` // Опыт работ в месяцах
+	Comment         string                     `json:"comment"`          // Коментарий
+	Params          dbmodels.ApplicantParams   `json:"params"`           // Доподнительные параметры
+	CustomFields    dbmodels.CustomFieldValues `json:"custom_fields"`    // Значения пользовательских полей: код поля - значение
 	//PhotoUrl        string                `json:"photo_url"` //todo s3 photo
 }
 
@@ -147,6 +197,7 @@ func ApplicantConvert(rec dbmodels.Applicant) ApplicantView {
 			TotalExperience: rec.TotalExperience,
 			Comment:         rec.Comment,
 			Params:          rec.Params,
+			CustomFields:    rec.CustomFields,
 		},
 		ID:                 rec.ID,
 		NegotiationID:      rec.NegotiationID,
@@ -167,6 +218,9 @@ func ApplicantConvert(rec dbmodels.Applicant) ApplicantView {
 	if rec.SelectionStage != nil {
 		result.SelectionStageName = rec.SelectionStage.Name
 	}
+	if rec.CandidateID != nil {
+		result.CandidateID = *rec.CandidateID
+	}
 	if !rec.NegotiationDate.IsZero() {
 		result.NegotiationDate = rec.NegotiationDate.Format("02.01.2006")
 	}
@@ -176,6 +230,10 @@ func ApplicantConvert(rec dbmodels.Applicant) ApplicantView {
 	if !rec.StartDate.IsZero() {
 		result.StartDate = rec.StartDate.Format("02.01.2006")
 	}
+	if rec.StageDeadline != nil {
+		result.StageDeadline = rec.StageDeadline.Format("02.01.2006 15:04")
+	}
+	result.StageOverdue = rec.StageOverdueAt != nil
 	jobTitle := ""
 	if rec.Vacancy != nil {
 		result.VacancyName = rec.Vacancy.VacancyName
@@ -216,26 +274,28 @@ func ApplicantConvert(rec dbmodels.Applicant) ApplicantView {
 
 type ApplicantFilter struct {
 	apimodels.Pagination
-	VacancyID           string                    `json:"vacancy_id"`            // Идентификатор вакансии
-	VacancyName         string                    `json:"vacancy_name"`          // Название вакансии
-	Search              string                    `json:"search"`                // Поиск по ФИО/телефон/емайл/тег
-	Relocation          *models.RelocationType    `json:"relocation"`            // Готовность к переезду
-	AgeFrom             int                       `json:"age_from"`              // Возраст "от"
-	AgeTo               int                       `json:"age_to"`                // Возраст "до"
-	TotalExperienceFrom int                       `json:"total_experience_from"` // Опыт работ в месяцах "от"
-	TotalExperienceTo   int                       `json:"total_experience_to"`   // Опыт работ в месяцах "до"
-	City                string                    `json:"city"`                  // Город проживания
-	StageName           string                    `json:"stage_name"`            // Этап
-	Status              *models.ApplicantStatus   `json:"status"`                // Статус кандидата
-	Source              *models.ApplicantSource   `json:"source"`                // Источник
-	Tag                 string                    `json:"tag"`                   // Тэг
-	AddedPeriod         *models.ApAddedPeriodType `json:"added_period"`          // Период добавления кандидата
-	AddedDay            string                    `json:"added_day"`             // Дата добавления кандидата ДД.ММ.ГГГГ
-	AddedType           *models.AddedType         `json:"added_type"`            // Тип добавления
-	Schedule            models.Schedule           `json:"schedule"`              // График работы
-	Language            string                    `json:"language"`              // Знание языков
-	Gender              models.GenderType         `json:"gender"`                // Пол кандидата
-	Sort                ApplicantSort             `json:"sort"`
+	VacancyID           string                      `json:"vacancy_id"`            // Идентификатор вакансии
+	VacancyName         string                      `json:"vacancy_name"`          // Название вакансии
+	Search              string                      `json:"search"`                // Поиск по ФИО/телефон/емайл/тег
+	Relocation          *models.RelocationType      `json:"relocation"`            // Готовность к переезду
+	AgeFrom             int                         `json:"age_from"`              // Возраст "от"
+	AgeTo               int                         `json:"age_to"`                // Возраст "до"
+	TotalExperienceFrom int                         `json:"total_experience_from"` // Опыт работ в месяцах "от"
+	TotalExperienceTo   int                         `json:"total_experience_to"`   // Опыт работ в месяцах "до"
+	City                string                      `json:"city"`                  // Город проживания
+	StageName           string                      `json:"stage_name"`            // Этап
+	Status              *models.ApplicantStatus     `json:"status"`                // Статус кандидата
+	Source              *models.ApplicantSource     `json:"source"`                // Источник
+	Tag                 string                      `json:"tag"`                   // Тэг
+	AddedPeriod         *models.ApAddedPeriodType   `json:"added_period"`          // Период добавления кандидата
+	AddedDay            string                      `json:"added_day"`             // Дата добавления кандидата ДД.ММ.ГГГГ
+	AddedType           *models.AddedType           `json:"added_type"`            // Тип добавления
+	Schedule            models.Schedule             `json:"schedule"`              // График работы
+	Language            string                      `json:"language"`              // Знание языков
+	Gender              models.GenderType           `json:"gender"`                // Пол кандидата
+	StageOverdue        *bool                       `json:"stage_overdue"`         // Превышен лимит времени на этапе
+	CustomFields        dbmodels.CustomFieldFilters `json:"custom_fields"`         // Фильтры по пользовательским полям
+	Sort                ApplicantSort               `json:"sort"`
 }
 
 func (a ApplicantFilter) Validate() error {
@@ -267,6 +327,7 @@ type ApplicantSort struct {
 	ResumeTitleDesc     *bool `json:"resume_title_desc,omitempty"`     // Должность, порядок сортировки false = ASC / true = DESC / nil = нет
 	SourceDesc          *bool `json:"source_desc,omitempty"`           // Источник, порядок сортировки false = ASC / true = DESC / nil = нет
 	StatusDesc          *bool `json:"status_desc,omitempty"`           // Статус, порядок сортировки false = ASC / true = DESC / nil = нет
+	StageDeadlineDesc   *bool `json:"stage_deadline_desc,omitempty"`   // Срок нахождения на этапе, порядок сортировки false = ASC / true = DESC / nil = нет
 }
 
 type ApplicantNote struct {
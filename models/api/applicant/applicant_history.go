@@ -13,6 +13,7 @@ type ApplicantHistoryFilter struct {
 type ApplicantHistoryView struct {
 	Date        string                    `json:"date"`         // дата записи dd.mm.yyyy
 	Time        string                    `json:"time"`         // время записи HH:mm
+	ApplicantID string                    `json:"applicant_id"` // Идентификатор кандидата на вакансии
 	VacancyID   string                    `json:"vacancy_id"`   // Идентификатор вакансии
 	VacancyName string                    `json:"vacancy_name"` // Название вакансии
 	UserID      string                    `json:"user_id"`      // Идентификатор сотрудника
@@ -25,6 +26,7 @@ func Convert(rec dbmodels.ApplicantHistory) ApplicantHistoryView {
 	result := ApplicantHistoryView{
 		Date:        rec.CreatedAt.Format("02.01.2006"),
 		Time:        rec.CreatedAt.Format("15:04"),
+		ApplicantID: rec.ApplicantID,
 		VacancyID:   rec.VacancyID,
 		VacancyName: "",
 		UserID:      "",
@@ -0,0 +1,254 @@
+package applicantapimodels
+
+import (
+	"hr-tools-backend/models"
+	dbmodels "hr-tools-backend/models/db"
+	"reflect"
+	"slices"
+
+	"github.com/pkg/errors"
+)
+
+// ResumeAiProvider - извлечение данных кандидата из текста резюме с помощью ИИ
+type ResumeAiProvider interface {
+	ParseResume(spaceID, applicantID, text string) (data dbmodels.ResumeData, err error)
+}
+
+type ResumeSuggestionView struct {
+	ID       string                          `json:"id"`
+	FileName string                          `json:"file_name"` // Имя файла резюме
+	Status   dbmodels.ResumeSuggestionStatus `json:"status"`    // Статус обработки: in_progress, ready, failed, applied, dismissed
+	Method   string                          `json:"method"`    // Способ извлечения данных
+	Error    string                          `json:"error"`     // Причина ошибки обработки
+	Fields   []ResumeSuggestionField         `json:"fields"`    // Найденные в резюме поля, отличающиеся от профиля кандидата
+}
+
+type ResumeSuggestionField struct {
+	Field          string `json:"field"`           // Код поля, указывается при переносе в профиль
+	Name           string `json:"name"`            // Наименование поля
+	CurrentValue   any    `json:"current_value"`   // Значение в профиле кандидата
+	SuggestedValue any    `json:"suggested_value"` // Значение из резюме
+}
+
+type ApplyResumeSuggestionRequest struct {
+	Fields []string `json:"fields"` // Коды полей для переноса в профиль кандидата
+}
+
+func (r ApplyResumeSuggestionRequest) Validate() error {
+	if len(r.Fields) == 0 {
+		return errors.New("не указаны поля для переноса в профиль кандидата")
+	}
+	for _, field := range r.Fields {
+		if !slices.ContainsFunc(resumeFields, func(f resumeField) bool { return f.code == field }) {
+			return errors.Errorf("неизвестное поле: %v", field)
+		}
+	}
+	return nil
+}
+
+type resumeField struct {
+	code      string
+	name      string
+	current   func(rec dbmodels.Applicant) any
+	suggested func(data dbmodels.ResumeData) any
+	apply     func(applicant *ApplicantData, data dbmodels.ResumeData)
+}
+
+var resumeFields = []resumeField{
+	{
+		code:      "last_name",
+		name:      "Фамилия",
+		current:   func(rec dbmodels.Applicant) any { return rec.LastName },
+		suggested: func(data dbmodels.ResumeData) any { return data.LastName },
+		apply:     func(a *ApplicantData, data dbmodels.ResumeData) { a.LastName = data.LastName },
+	},
+	{
+		code:      "first_name",
+		name:      "Имя",
+		current:   func(rec dbmodels.Applicant) any { return rec.FirstName },
+		suggested: func(data dbmodels.ResumeData) any { return data.FirstName },
+		apply:     func(a *ApplicantData, data dbmodels.ResumeData) { a.FirstName = data.FirstName },
+	},
+	{
+		code:      "middle_name",
+		name:      "Отчество",
+		current:   func(rec dbmodels.Applicant) any { return rec.MiddleName },
+		suggested: func(data dbmodels.ResumeData) any { return data.MiddleName },
+		apply:     func(a *ApplicantData, data dbmodels.ResumeData) { a.MiddleName = data.MiddleName },
+	},
+	{
+		code:      "phone",
+		name:      "Телефон",
+		current:   func(rec dbmodels.Applicant) any { return rec.Phone },
+		suggested: func(data dbmodels.ResumeData) any { return data.Phone },
+		apply:     func(a *ApplicantData, data dbmodels.ResumeData) { a.Phone = data.Phone },
+	},
+	{
+		code:      "email",
+		name:      "Email",
+		current:   func(rec dbmodels.Applicant) any { return rec.Email },
+		suggested: func(data dbmodels.ResumeData) any { return data.Email },
+		apply:     func(a *ApplicantData, data dbmodels.ResumeData) { a.Email = data.Email },
+	},
+	{
+		code: "birth_date",
+		name: "Дата рождения",
+		current: func(rec dbmodels.Applicant) any {
+			if rec.BirthDate.IsZero() {
+				return ""
+			}
+			return rec.BirthDate.Format("02.01.2006")
+		},
+		suggested: func(data dbmodels.ResumeData) any { return data.BirthDate },
+		apply:     func(a *ApplicantData, data dbmodels.ResumeData) { a.BirthDate = data.BirthDate },
+	},
+	{
+		code:      "address",
+		name:      "Адрес",
+		current:   func(rec dbmodels.Applicant) any { return rec.Address },
+		suggested: func(data dbmodels.ResumeData) any { return data.Address },
+		apply:     func(a *ApplicantData, data dbmodels.ResumeData) { a.Address = data.Address },
+	},
+	{
+		code:      "citizenship",
+		name:      "Гражданство",
+		current:   func(rec dbmodels.Applicant) any { return rec.Citizenship },
+		suggested: func(data dbmodels.ResumeData) any { return data.Citizenship },
+		apply:     func(a *ApplicantData, data dbmodels.ResumeData) { a.Citizenship = data.Citizenship },
+	},
+	{
+		code:      "salary",
+		name:      "Желаемая ЗП",
+		current:   func(rec dbmodels.Applicant) any { return rec.Salary },
+		suggested: func(data dbmodels.ResumeData) any { return data.Salary },
+		apply:     func(a *ApplicantData, data dbmodels.ResumeData) { a.Salary = data.Salary },
+	},
+	{
+		code:      "total_experience",
+		name:      "Опыт работ в месяцах",
+		current:   func(rec dbmodels.Applicant) any { return rec.TotalExperience },
+		suggested: func(data dbmodels.ResumeData) any { return data.TotalExperience },
+		apply:     func(a *ApplicantData, data dbmodels.ResumeData) { a.TotalExperience = data.TotalExperience },
+	},
+	{
+		code:      "education",
+		name:      "Образование",
+		current:   func(rec dbmodels.Applicant) any { return rec.Params.Education },
+		suggested: func(data dbmodels.ResumeData) any { return data.Education },
+		apply:     func(a *ApplicantData, data dbmodels.ResumeData) { a.Params.Education = data.Education },
+	},
+	{
+		code:      "languages",
+		name:      "Знание языков",
+		current:   func(rec dbmodels.Applicant) any { return rec.Params.Languages },
+		suggested: func(data dbmodels.ResumeData) any { return data.Languages },
+		apply:     func(a *ApplicantData, data dbmodels.ResumeData) { a.Params.Languages = data.Languages },
+	},
+	{
+		code:      "employments",
+		name:      "Занятость",
+		current:   func(rec dbmodels.Applicant) any { return rec.Params.Employments },
+		suggested: func(data dbmodels.ResumeData) any { return data.Employments },
+		apply:     func(a *ApplicantData, data dbmodels.ResumeData) { a.Params.Employments = data.Employments },
+	},
+	{
+		code:      "schedules",
+		name:      "График работы",
+		current:   func(rec dbmodels.Applicant) any { return rec.Params.Schedules },
+		suggested: func(data dbmodels.ResumeData) any { return data.Schedules },
+		apply:     func(a *ApplicantData, data dbmodels.ResumeData) { a.Params.Schedules = data.Schedules },
+	},
+	{
+		code:      "driver_license_types",
+		name:      "Водительские права",
+		current:   func(rec dbmodels.Applicant) any { return rec.Params.DriverLicenseTypes },
+		suggested: func(data dbmodels.ResumeData) any { return data.DriverLicenseTypes },
+		apply: func(a *ApplicantData, data dbmodels.ResumeData) {
+			a.Params.DriverLicenseTypes = data.DriverLicenseTypes
+		},
+	},
+}
+
+func ResumeSuggestionConvert(rec dbmodels.ApplicantResumeSuggestion, applicant dbmodels.Applicant) ResumeSuggestionView {
+	result := ResumeSuggestionView{
+		ID:       rec.ID,
+		FileName: rec.FileName,
+		Status:   rec.Status,
+		Method:   rec.Method,
+		Error:    rec.Error,
+		Fields:   []ResumeSuggestionField{},
+	}
+	if rec.Status != dbmodels.ResumeSuggestionReady {
+		return result
+	}
+	for _, field := range resumeFields {
+		suggested := field.suggested(rec.Data)
+		current := field.current(applicant)
+		if isEmptyValue(suggested) || reflect.DeepEqual(current, suggested) {
+			continue
+		}
+		result.Fields = append(result.Fields, ResumeSuggestionField{
+			Field:          field.code,
+			Name:           field.name,
+			CurrentValue:   current,
+			SuggestedValue: suggested,
+		})
+	}
+	return result
+}
+
+// ApplyResumeSuggestion данные для обновления кандидата с перенесенными из резюме полями
+func ApplyResumeSuggestion(applicant dbmodels.Applicant, data dbmodels.ResumeData, fields []string) ApplicantData {
+	result := ApplicantDataConvert(applicant)
+	for _, field := range resumeFields {
+		if slices.Contains(fields, field.code) && !isEmptyValue(field.suggested(data)) {
+			field.apply(&result, data)
+		}
+	}
+	return result
+}
+
+func ApplicantDataConvert(rec dbmodels.Applicant) ApplicantData {
+	result := ApplicantData{
+		VacancyID:       rec.VacancyID,
+		Source:          rec.Source,
+		FirstName:       rec.FirstName,
+		LastName:        rec.LastName,
+		MiddleName:      rec.MiddleName,
+		Phone:           rec.Phone,
+		Email:           rec.Email,
+		Salary:          rec.Salary,
+		Address:         rec.Address,
+		Citizenship:     rec.Citizenship,
+		Gender:          rec.Gender,
+		Relocation:      rec.Relocation,
+		TotalExperience: rec.TotalExperience,
+		Comment:         rec.Comment,
+		Params:          rec.Params,
+		CustomFields:    rec.CustomFields,
+	}
+	if !rec.BirthDate.IsZero() {
+		result.BirthDate = rec.BirthDate.Format("02.01.2006")
+	}
+	if result.Params.Languages == nil {
+		result.Params.Languages = []dbmodels.Language{}
+	}
+	if result.Params.Employments == nil {
+		result.Params.Employments = []models.Employment{}
+	}
+	if result.Params.Schedules == nil {
+		result.Params.Schedules = []models.Schedule{}
+	}
+	if result.Params.DriverLicenseTypes == nil {
+		result.Params.DriverLicenseTypes = []models.DriverLicenseType{}
+	}
+	return result
+}
+
+func isEmptyValue(value any) bool {
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Slice {
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
@@ -0,0 +1,59 @@
+package auditlogapimodels
+
+import (
+	"hr-tools-backend/models"
+	apimodels "hr-tools-backend/models/api"
+	dbmodels "hr-tools-backend/models/db"
+	"time"
+)
+
+type Filter struct {
+	apimodels.Pagination
+	ActorType  models.AuditActorType `json:"actor_type"`  // Инициатор (USER/API_KEY/SYSTEM)
+	ActorID    string                `json:"actor_id"`    // Идентификатор пользователя
+	Action     models.AuditAction    `json:"action"`      // Действие (request/create/update/delete)
+	EntityType models.AuditEntity    `json:"entity_type"` // Тип сущности
+	EntityID   string                `json:"entity_id"`   // Идентификатор сущности
+	DateFrom   *time.Time            `json:"date_from"`   // Дата "от"
+	DateTo     *time.Time            `json:"date_to"`     // Дата "до"
+}
+
+type View struct {
+	ID            string                 `json:"id"`
+	CreatedAt     time.Time              `json:"created_at"`      // Дата действия
+	ActorType     models.AuditActorType  `json:"actor_type"`      // Инициатор (USER/API_KEY/SYSTEM)
+	ActorTypeName string                 `json:"actor_type_name"` // Инициатор
+	ActorID       string                 `json:"actor_id"`        // Идентификатор пользователя
+	ActorName     string                 `json:"actor_name"`      // Имя пользователя
+	Action        models.AuditAction     `json:"action"`          // Действие (request/create/update/delete)
+	ActionName    string                 `json:"action_name"`     // Действие
+	EntityType    models.AuditEntity     `json:"entity_type"`     // Тип сущности
+	EntityID      string                 `json:"entity_id"`       // Идентификатор сущности
+	Method        string                 `json:"method"`          // HTTP метод
+	Path          string                 `json:"path"`            // Адрес запроса
+	Status        int                    `json:"status"`          // Код ответа
+	Changes       dbmodels.EntityChanges `json:"changes"`         // Изменения
+	IP            string                 `json:"ip"`              // IP адрес
+	UserAgent     string                 `json:"user_agent"`      // Браузер
+}
+
+func Convert(rec dbmodels.AuditLog) View {
+	return View{
+		ID:            rec.ID,
+		CreatedAt:     rec.CreatedAt,
+		ActorType:     rec.ActorType,
+		ActorTypeName: rec.ActorType.ToHuman(),
+		ActorID:       rec.ActorID,
+		ActorName:     rec.ActorName,
+		Action:        rec.Action,
+		ActionName:    rec.Action.ToHuman(),
+		EntityType:    rec.EntityType,
+		EntityID:      rec.EntityID,
+		Method:        rec.Method,
+		Path:          rec.Path,
+		Status:        rec.Status,
+		Changes:       rec.Changes,
+		IP:            rec.IP,
+		UserAgent:     rec.UserAgent,
+	}
+}
@@ -0,0 +1,11 @@
+package authapimodels
+
+// PasswordPolicy требования к новому паролю
+type PasswordPolicy struct {
+	MinLength      int  `json:"min_length"`
+	MaxLength      int  `json:"max_length"`
+	RequireUpper   bool `json:"require_upper"`   // заглавная буква
+	RequireLower   bool `json:"require_lower"`   // строчная буква
+	RequireDigit   bool `json:"require_digit"`   // цифра
+	RequireSpecial bool `json:"require_special"` // спецсимвол
+}
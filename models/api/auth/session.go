@@ -0,0 +1,21 @@
+package authapimodels
+
+import (
+	"time"
+)
+
+// ClientInfo данные клиента, сохраняемые в сессии
+type ClientInfo struct {
+	UserAgent string
+	IP        string
+}
+
+type SessionView struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"user_agent"` // устройство/браузер
+	IP         string    `json:"ip"`         // адрес последнего обращения
+	CreatedAt  time.Time `json:"created_at"` // дата входа
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	IsCurrent  bool      `json:"is_current"` // текущая сессия
+}
@@ -6,8 +6,12 @@ import (
 )
 
 type JWTResponse struct {
-	Token        string `json:"token"`
-	RefreshToken string `json:"refresh_token"`
+	Token                  string   `json:"token"`
+	RefreshToken           string   `json:"refresh_token"`
+	TwoFactorRequired      bool     `json:"two_factor_required,omitempty"`       // требуется код второго фактора, токены не выданы
+	TwoFactorSetupRequired bool     `json:"two_factor_setup_required,omitempty"` // двухфакторная аутентификация обязательна, но еще не подключена
+	TwoFactorToken         string   `json:"two_factor_token,omitempty"`          // токен второго шага входа
+	RecoveryCodes          []string `json:"recovery_codes,omitempty"`            // коды восстановления, если двухфакторная аутентификация подключена при входе
 }
 
 type JWTRefreshRequest struct {
@@ -0,0 +1,56 @@
+package authapimodels
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type TwoFactorStatus struct {
+	Enabled           bool `json:"enabled"`             // двухфакторная аутентификация подключена
+	Required          bool `json:"required"`            // обязательна, отключить нельзя
+	RecoveryCodesLeft int  `json:"recovery_codes_left"` // неиспользованных кодов восстановления
+}
+
+type TwoFactorSetup struct {
+	Secret string `json:"secret"` // секрет для ручного ввода в приложение-аутентификатор
+	Uri    string `json:"uri"`    // otpauth:// ссылка для QR кода
+}
+
+type RecoveryCodesView struct {
+	RecoveryCodes []string `json:"recovery_codes"` // показываются один раз
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code"` // код из приложения-аутентификатора или код восстановления
+}
+
+func (r TwoFactorCodeRequest) Validate() error {
+	if strings.TrimSpace(r.Code) == "" {
+		return errors.New("не указан код")
+	}
+	return nil
+}
+
+type TwoFactorChallengeRequest struct {
+	TwoFactorToken string `json:"two_factor_token"` // токен второго шага входа из ответа на вход
+}
+
+func (r TwoFactorChallengeRequest) Validate() error {
+	if strings.TrimSpace(r.TwoFactorToken) == "" {
+		return errors.New("не указан токен второго шага входа")
+	}
+	return nil
+}
+
+type TwoFactorLoginRequest struct {
+	TwoFactorChallengeRequest
+	TwoFactorCodeRequest
+}
+
+func (r TwoFactorLoginRequest) Validate() error {
+	if err := r.TwoFactorChallengeRequest.Validate(); err != nil {
+		return err
+	}
+	return r.TwoFactorCodeRequest.Validate()
+}
@@ -0,0 +1,178 @@
+package automationapimodels
+
+import (
+	apimodels "hr-tools-backend/models/api"
+	dbmodels "hr-tools-backend/models/db"
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type AutomationRuleData struct {
+	Name        string                        `json:"name"`          // Название правила
+	IsActive    bool                          `json:"is_active"`     // Правило активно
+	DryRun      bool                          `json:"dry_run"`       // Тестовый режим: действия не выполняются, только записываются в журнал
+	VacancyID   string                        `json:"vacancy_id"`    // Идентификатор вакансии, пусто - все вакансии спейса
+	Trigger     dbmodels.AutomationTrigger    `json:"trigger"`       // Событие: STAGE_CHANGE, NEGOTIATION, SURVEY_SCORED, VK_STEP, TIME_IN_STAGE
+	StageName   string                        `json:"stage_name"`    // Этап подбора для STAGE_CHANGE и TIME_IN_STAGE, пусто - любой
+	VkStatus    *dbmodels.StepStatus          `json:"vk_status"`     // Статус шага ВК для VK_STEP, пусто - любой
+	DaysInStage int                           `json:"days_in_stage"` // Срок нахождения на этапе в днях для TIME_IN_STAGE
+	Conditions  dbmodels.AutomationConditions `json:"conditions"`    // Условия по данным кандидата
+	Actions     dbmodels.AutomationActions    `json:"actions"`       // Действия
+}
+
+func (r AutomationRuleData) Validate() error {
+	if r.Name == "" {
+		return errors.New("не указано название правила")
+	}
+	if !r.Trigger.IsValid() {
+		return errors.New("некорректное событие правила")
+	}
+	if r.Trigger == dbmodels.AutomationTriggerTimeInStage && r.DaysInStage <= 0 {
+		return errors.New("не указан срок нахождения на этапе")
+	}
+	for _, condition := range r.Conditions {
+		if !slices.Contains(dbmodels.AutomationConditionFields, condition.Field) {
+			return errors.Errorf("некорректное поле условия: %v", condition.Field)
+		}
+		switch condition.Operator {
+		case dbmodels.AutomationOpEq, dbmodels.AutomationOpNe, dbmodels.AutomationOpContains,
+			dbmodels.AutomationOpGt, dbmodels.AutomationOpGte, dbmodels.AutomationOpLt, dbmodels.AutomationOpLte:
+		default:
+			return errors.Errorf("некорректный оператор условия: %v", condition.Operator)
+		}
+	}
+	if len(r.Actions) == 0 {
+		return errors.New("не указаны действия правила")
+	}
+	for idx, action := range r.Actions {
+		err := validateAction(action)
+		if err != nil {
+			return errors.Wrapf(err, "действие %v", idx+1)
+		}
+	}
+	return nil
+}
+
+func validateAction(action dbmodels.AutomationAction) error {
+	switch action.Type {
+	case dbmodels.AutomationActionSendTemplate:
+		if action.TemplateID == "" {
+			return errors.New("не указан шаблон письма")
+		}
+	case dbmodels.AutomationActionChangeStage:
+		if action.StageName == "" {
+			return errors.New("не указан этап подбора")
+		}
+	case dbmodels.AutomationActionReject:
+		if action.RejectReason == "" {
+			return errors.New("не указана причина отказа")
+		}
+		if action.RejectInitiator == "" {
+			return errors.New("не указан инициатор отказа")
+		}
+		return action.RejectInitiator.IsValid()
+	case dbmodels.AutomationActionAddTag:
+		if action.Tag == "" {
+			return errors.New("не указан тег")
+		}
+	case dbmodels.AutomationActionNotifyTeam:
+		if action.Message == "" {
+			return errors.New("не указан текст уведомления")
+		}
+	case dbmodels.AutomationActionVkStep0:
+	default:
+		return errors.Errorf("некорректный тип действия: %v", action.Type)
+	}
+	return nil
+}
+
+type AutomationRuleView struct {
+	ID          string    `json:"id"`
+	VacancyName string    `json:"vacancy_name"` // Название вакансии
+	CreatedAt   time.Time `json:"created_at"`   // Дата создания
+	AutomationRuleData
+}
+
+func AutomationRuleConvert(rec dbmodels.AutomationRule) AutomationRuleView {
+	result := AutomationRuleView{
+		ID:        rec.ID,
+		CreatedAt: rec.CreatedAt,
+		AutomationRuleData: AutomationRuleData{
+			Name:        rec.Name,
+			IsActive:    rec.IsActive,
+			DryRun:      rec.DryRun,
+			Trigger:     rec.Trigger,
+			StageName:   rec.StageName,
+			VkStatus:    rec.VkStatus,
+			DaysInStage: rec.DaysInStage,
+			Conditions:  rec.Conditions,
+			Actions:     rec.Actions,
+		},
+	}
+	if rec.VacancyID != nil {
+		result.VacancyID = *rec.VacancyID
+	}
+	if rec.Vacancy != nil {
+		result.VacancyName = rec.Vacancy.VacancyName
+	}
+	if result.Conditions == nil {
+		result.Conditions = dbmodels.AutomationConditions{}
+	}
+	if result.Actions == nil {
+		result.Actions = dbmodels.AutomationActions{}
+	}
+	return result
+}
+
+type DryRunRequest struct {
+	ApplicantID string `json:"applicant_id"` // Идентификатор кандидата
+}
+
+func (r DryRunRequest) Validate() error {
+	if r.ApplicantID == "" {
+		return errors.New("не указан кандидат")
+	}
+	return nil
+}
+
+type DryRunResult struct {
+	MatchConditions bool                             `json:"match_conditions"` // Кандидат удовлетворяет условиям правила
+	Executed        bool                             `json:"executed"`         // Правило уже выполнялось для кандидата по текущему этапу/статусу
+	Actions         dbmodels.AutomationActionResults `json:"actions"`          // Действия, которые будут выполнены
+}
+
+type LogFilter struct {
+	apimodels.Pagination
+	RuleID      string `json:"rule_id"`      // Идентификатор правила
+	ApplicantID string `json:"applicant_id"` // Идентификатор кандидата
+}
+
+type AutomationLogView struct {
+	ID          string                           `json:"id"`
+	RuleID      string                           `json:"rule_id"`      // Идентификатор правила
+	RuleName    string                           `json:"rule_name"`    // Название правила
+	ApplicantID string                           `json:"applicant_id"` // Идентификатор кандидата
+	VacancyID   string                           `json:"vacancy_id"`   // Идентификатор вакансии
+	Trigger     dbmodels.AutomationTrigger       `json:"trigger"`      // Событие
+	DryRun      bool                             `json:"dry_run"`      // Тестовый режим, действия не выполнялись
+	Success     bool                             `json:"success"`      // Все действия выполнены успешно
+	Results     dbmodels.AutomationActionResults `json:"results"`      // Результаты действий
+	CreatedAt   time.Time                        `json:"created_at"`   // Дата выполнения
+}
+
+func AutomationLogConvert(rec dbmodels.AutomationLog) AutomationLogView {
+	return AutomationLogView{
+		ID:          rec.ID,
+		RuleID:      rec.RuleID,
+		RuleName:    rec.RuleName,
+		ApplicantID: rec.ApplicantID,
+		VacancyID:   rec.VacancyID,
+		Trigger:     rec.Trigger,
+		DryRun:      rec.DryRun,
+		Success:     rec.Success,
+		Results:     rec.Results,
+		CreatedAt:   rec.CreatedAt,
+	}
+}
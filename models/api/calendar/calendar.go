@@ -0,0 +1,69 @@
+package calendarapimodels
+
+import (
+	dbmodels "hr-tools-backend/models/db"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// максимальный интервал запроса занятости
+const maxBusyPeriod = 31 * 24 * time.Hour
+
+type ConnectionData struct {
+	Type     dbmodels.CalendarType `json:"type"`     // Тип подключения: caldav - CalDAV календарь, ics - ссылка на календарь в формате iCalendar (только чтение)
+	URL      string                `json:"url"`      // Адрес коллекции календаря CalDAV / ссылка на ics
+	Login    string                `json:"login"`    // Логин
+	Password string                `json:"password"` // Пароль (пароль приложения)
+}
+
+func (r ConnectionData) Validate() error {
+	if r.Type != dbmodels.CalendarTypeCalDAV && r.Type != dbmodels.CalendarTypeICS {
+		return errors.New("некорректный тип подключения календаря")
+	}
+	if r.URL == "" {
+		return errors.New("не указан адрес календаря")
+	}
+	u, err := url.Parse(r.URL)
+	if err != nil || u.Host == "" {
+		return errors.New("некорректный адрес календаря")
+	}
+	if r.Type == dbmodels.CalendarTypeCalDAV && r.Login == "" {
+		return errors.New("не указан логин календаря")
+	}
+	return nil
+}
+
+type ConnectionView struct {
+	Type       dbmodels.CalendarType `json:"type"`         // Тип подключения
+	Host       string                `json:"host"`         // Сервер календаря
+	Login      string                `json:"login"`        // Логин
+	LastSyncAt *time.Time            `json:"last_sync_at"` // Время последней синхронизации
+	LastError  string                `json:"last_error"`   // Ошибка последней синхронизации
+}
+
+type BusyRequest struct {
+	UserIDs  []string  `json:"user_ids"`  // Идентификаторы пользователей
+	DateFrom time.Time `json:"date_from"` // Начало интервала
+	DateTo   time.Time `json:"date_to"`   // Окончание интервала
+}
+
+func (r BusyRequest) Validate() error {
+	if len(r.UserIDs) == 0 {
+		return errors.New("не указаны пользователи")
+	}
+	if r.DateFrom.IsZero() || r.DateTo.IsZero() || !r.DateFrom.Before(r.DateTo) {
+		return errors.New("некорректный интервал")
+	}
+	if r.DateTo.Sub(r.DateFrom) > maxBusyPeriod {
+		return errors.New("интервал не должен превышать 31 день")
+	}
+	return nil
+}
+
+type BusyView struct {
+	UserID  string    `json:"user_id"`  // Идентификатор пользователя
+	StartAt time.Time `json:"start_at"` // Начало занятого интервала
+	EndAt   time.Time `json:"end_at"`   // Окончание занятого интервала
+}
@@ -0,0 +1,132 @@
+package candidateapimodels
+
+import (
+	"hr-tools-backend/models"
+	apimodels "hr-tools-backend/models/api"
+	dbmodels "hr-tools-backend/models/db"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type CandidateData struct {
+	FirstName   string                `json:"first_name"`  // Имя
+	LastName    string                `json:"last_name"`   // Фамилия
+	MiddleName  string                `json:"middle_name"` // Отчество
+	Phone       string                `json:"phone"`       // Телефон
+	Email       string                `json:"email"`       // Email
+	BirthDate   string                `json:"birth_date"`  // Дата рождения ДД.ММ.ГГГГ
+	Citizenship string                `json:"citizenship"` // Гражданство
+	Gender      models.GenderType     `json:"gender"`      // Пол кандидата
+	Relocation  models.RelocationType `json:"relocation"`  // Готовность к переезду
+	Address     string                `json:"address"`     // Адрес
+	Comment     string                `json:"comment"`     // Комментарий
+}
+
+func (c CandidateData) Validate() error {
+	if strings.TrimSpace(c.FirstName) == "" && strings.TrimSpace(c.LastName) == "" {
+		return errors.New("не указано имя кандидата")
+	}
+	_, err := c.GetBirthDate()
+	if err != nil {
+		return errors.New("некоректный формат даты рождения")
+	}
+	return nil
+}
+
+func (c CandidateData) GetBirthDate() (time.Time, error) {
+	if c.BirthDate == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse("02.01.2006", c.BirthDate)
+}
+
+type CandidateFilter struct {
+	apimodels.Pagination
+	Search       string `json:"search"`         // Поиск по ФИО/телефон/email
+	TalentPoolID string `json:"talent_pool_id"` // Кандидаты кадрового резерва
+}
+
+type CandidateView struct {
+	CandidateData
+	ID               string `json:"id"`                // Идентификатор кандидата
+	FIO              string `json:"fio"`               // ФИО кандидата
+	ApplicationCount int    `json:"application_count"` // Количество вакансий, на которые рассматривался кандидат
+	CreatedAt        string `json:"created_at"`        // Дата создания ДД.ММ.ГГГГ
+}
+
+type CandidateViewExt struct {
+	CandidateView
+	Applications []ApplicationView `json:"applications"` // Участие в подборе по вакансиям
+	TalentPools  []TalentPoolShort `json:"talent_pools"` // Кадровые резервы кандидата
+}
+
+// ApplicationView участие кандидата в подборе на вакансию
+type ApplicationView struct {
+	ApplicantID        string                 `json:"applicant_id"`         // Идентификатор кандидата на вакансии
+	VacancyID          string                 `json:"vacancy_id"`           // Идентификатор вакансии
+	VacancyName        string                 `json:"vacancy_name"`         // Название вакансии
+	Status             models.ApplicantStatus `json:"status"`               // Статус кандидата
+	SelectionStageName string                 `json:"selection_stage_name"` // Этап подбора
+	Source             models.ApplicantSource `json:"source"`               // Источник
+	AcceptDate         string                 `json:"accept_date"`          // Дата добавления ДД.ММ.ГГГГ
+}
+
+type TalentPoolShort struct {
+	ID   string `json:"id"`   // Идентификатор кадрового резерва
+	Name string `json:"name"` // Название
+}
+
+func CandidateConvert(rec dbmodels.Candidate) CandidateView {
+	result := CandidateView{
+		CandidateData: CandidateData{
+			FirstName:   rec.FirstName,
+			LastName:    rec.LastName,
+			MiddleName:  rec.MiddleName,
+			Phone:       rec.Phone,
+			Email:       rec.Email,
+			Citizenship: rec.Citizenship,
+			Gender:      rec.Gender,
+			Relocation:  rec.Relocation,
+			Address:     rec.Address,
+			Comment:     rec.Comment,
+		},
+		ID:               rec.ID,
+		FIO:              rec.GetFIO(),
+		ApplicationCount: len(rec.Applicants),
+		CreatedAt:        rec.CreatedAt.Format("02.01.2006"),
+	}
+	if !rec.BirthDate.IsZero() {
+		result.BirthDate = rec.BirthDate.Format("02.01.2006")
+	}
+	return result
+}
+
+func ApplicationConvert(rec dbmodels.Applicant) ApplicationView {
+	result := ApplicationView{
+		ApplicantID: rec.ID,
+		VacancyID:   rec.VacancyID,
+		Status:      rec.Status,
+		Source:      rec.Source,
+		AcceptDate:  rec.NegotiationAcceptDate.Format("02.01.2006"),
+	}
+	if rec.Vacancy != nil {
+		result.VacancyName = rec.Vacancy.VacancyName
+	}
+	if rec.SelectionStage != nil {
+		result.SelectionStageName = rec.SelectionStage.Name
+	}
+	return result
+}
+
+type ConsiderRequest struct {
+	VacancyID string `json:"vacancy_id"` // Вакансия, на которую рассматривается кандидат
+}
+
+func (r ConsiderRequest) Validate() error {
+	if r.VacancyID == "" {
+		return errors.New("не указана вакансия")
+	}
+	return nil
+}
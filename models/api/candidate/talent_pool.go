@@ -0,0 +1,64 @@
+package candidateapimodels
+
+import (
+	dbmodels "hr-tools-backend/models/db"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type TalentPoolData struct {
+	Name        string `json:"name"`        // Название
+	Description string `json:"description"` // Описание
+}
+
+func (t TalentPoolData) Validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return errors.New("не указано название кадрового резерва")
+	}
+	if len([]rune(t.Name)) > 255 {
+		return errors.New("название кадрового резерва не должно превышать 255 символов")
+	}
+	if len([]rune(t.Description)) > 1000 {
+		return errors.New("описание кадрового резерва не должно превышать 1000 символов")
+	}
+	return nil
+}
+
+type TalentPoolView struct {
+	TalentPoolData
+	ID             string `json:"id"`              // Идентификатор кадрового резерва
+	AuthorID       string `json:"author_id"`       // Автор
+	AuthorName     string `json:"author_name"`     // Имя автора
+	CandidateCount int64  `json:"candidate_count"` // Количество кандидатов
+	CreatedAt      string `json:"created_at"`      // Дата создания ДД.ММ.ГГГГ
+}
+
+func TalentPoolConvert(rec dbmodels.TalentPoolExt) TalentPoolView {
+	result := TalentPoolView{
+		TalentPoolData: TalentPoolData{
+			Name:        rec.Name,
+			Description: rec.Description,
+		},
+		ID:             rec.ID,
+		AuthorID:       rec.AuthorID,
+		CandidateCount: rec.CandidateCount,
+		CreatedAt:      rec.CreatedAt.Format("02.01.2006"),
+	}
+	if rec.Author != nil {
+		result.AuthorName = rec.Author.GetFullName()
+	}
+	return result
+}
+
+type TalentPoolAddRequest struct {
+	CandidateIDs []string `json:"candidate_ids"` // Кандидаты
+	Comment      string   `json:"comment"`       // Комментарий
+}
+
+func (r TalentPoolAddRequest) Validate() error {
+	if len(r.CandidateIDs) == 0 {
+		return errors.New("не указаны кандидаты")
+	}
+	return nil
+}
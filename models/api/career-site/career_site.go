@@ -0,0 +1,222 @@
+package careersiteapimodels
+
+import (
+	"encoding/xml"
+	"fmt"
+	"hr-tools-backend/models"
+	dbmodels "hr-tools-backend/models/db"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type VacancyView struct {
+	ID           string            `json:"id"`
+	VacancyName  string            `json:"vacancy_name"`   // Название вакансии
+	CompanyName  string            `json:"company_name"`   // Название компании
+	JobTitleName string            `json:"job_title_name"` // Должность
+	City         string            `json:"city"`           // Город
+	PlaceOfWork  string            `json:"place_of_work"`  // Адрес места работы
+	Requirements string            `json:"requirements"`   // Описание/требования
+	SalaryFrom   int               `json:"salary_from"`    // Зарплата от
+	SalaryTo     int               `json:"salary_to"`      // Зарплата до
+	SalaryInHand int               `json:"salary_in_hand"` // Зарплата на руки
+	Employment   models.Employment `json:"employment"`     // Занятость
+	Experience   models.Experience `json:"experience"`     // Опыт работы
+	Schedule     models.Schedule   `json:"schedule"`       // Режим работы
+	Url          string            `json:"url"`            // Ссылка на вакансию на карьерном сайте
+	CreatedAt    time.Time         `json:"created_at"`     // Дата создания
+}
+
+func VacancyConvert(rec dbmodels.Vacancy, url string) VacancyView {
+	result := VacancyView{
+		ID:           rec.ID,
+		VacancyName:  rec.VacancyName,
+		PlaceOfWork:  rec.PlaceOfWork,
+		Requirements: rec.Requirements,
+		SalaryFrom:   rec.Salary.From,
+		SalaryTo:     rec.Salary.To,
+		SalaryInHand: rec.Salary.InHand,
+		Employment:   rec.Employment,
+		Experience:   rec.Experience,
+		Schedule:     rec.Schedule,
+		Url:          url,
+		CreatedAt:    rec.CreatedAt,
+	}
+	if rec.Company != nil {
+		result.CompanyName = rec.Company.Name
+	}
+	if rec.JobTitle != nil {
+		result.JobTitleName = rec.JobTitle.Name
+	}
+	if rec.City != nil {
+		result.City = rec.City.City
+	}
+	return result
+}
+
+type ApplyRequest struct {
+	FirstName   string `json:"first_name"`   // Имя
+	LastName    string `json:"last_name"`    // Фамилия
+	MiddleName  string `json:"middle_name"`  // Отчество
+	Phone       string `json:"phone"`        // Телефон
+	Email       string `json:"email"`        // Емайл
+	Salary      int    `json:"salary"`       // Желаемая ЗП
+	CoverLetter string `json:"cover_letter"` // Сопроводительное письмо
+	PdConsent   bool   `json:"pd_consent"`   // Согласие на обработку персональных данных
+}
+
+func (r ApplyRequest) Validate() error {
+	if strings.TrimSpace(r.FirstName) == "" {
+		return errors.New("не указано имя")
+	}
+	if strings.TrimSpace(r.LastName) == "" {
+		return errors.New("не указана фамилия")
+	}
+	if strings.TrimSpace(r.Phone) == "" && strings.TrimSpace(r.Email) == "" {
+		return errors.New("необходимо указать телефон или емайл")
+	}
+	if r.Email != "" {
+		if _, err := mail.ParseAddress(r.Email); err != nil {
+			return errors.New("некоректный емайл")
+		}
+	}
+	if r.Salary < 0 {
+		return errors.New("некоректная желаемая ЗП")
+	}
+	if !r.PdConsent {
+		return errors.New(models.ConsentRequiredMsg)
+	}
+	return nil
+}
+
+type ApplyResponse struct {
+	ApplicantID string `json:"applicant_id"` // Идентификатор кандидата
+}
+
+// Фид вакансий в формате Яндекс.Работы (совместим с XML-фидом HeadHunter)
+
+const FeedTimeLayout = "2006-01-02 15:04:05 GMT-07"
+
+type Feed struct {
+	XMLName      xml.Name      `xml:"source"`
+	CreationTime string        `xml:"creation-time,attr"`
+	Host         string        `xml:"host,attr"`
+	Vacancies    []FeedVacancy `xml:"vacancies>vacancy"`
+}
+
+type FeedVacancy struct {
+	Url          string          `xml:"url"`
+	CreationDate string          `xml:"creation-date"`
+	UpdateDate   string          `xml:"update-date"`
+	Salary       string          `xml:"salary,omitempty"`
+	Currency     string          `xml:"currency,omitempty"`
+	JobName      string          `xml:"job-name"`
+	Employment   string          `xml:"employment,omitempty"`
+	Schedule     string          `xml:"schedule,omitempty"`
+	Description  string          `xml:"description"`
+	Requirement  FeedRequirement `xml:"requirement"`
+	Addresses    []FeedAddress   `xml:"addresses>address,omitempty"`
+	Company      FeedCompany     `xml:"company"`
+}
+
+type FeedRequirement struct {
+	Experience string `xml:"experience,omitempty"`
+}
+
+type FeedAddress struct {
+	Location string `xml:"location"`
+}
+
+type FeedCompany struct {
+	Name        string `xml:"name"`
+	Description string `xml:"description,omitempty"`
+	Site        string `xml:"site,omitempty"`
+	HrAgency    bool   `xml:"hr-agency"`
+}
+
+func FeedVacancyConvert(rec dbmodels.Vacancy, url string) FeedVacancy {
+	result := FeedVacancy{
+		Url:          url,
+		CreationDate: rec.CreatedAt.Format(FeedTimeLayout),
+		UpdateDate:   rec.UpdatedAt.Format(FeedTimeLayout),
+		Salary:       GetSalaryText(rec.Salary),
+		JobName:      rec.VacancyName,
+		Employment:   rec.Employment.ToString(),
+		Schedule:     rec.Schedule.ToString(),
+		Description:  rec.Requirements,
+		Requirement: FeedRequirement{
+			Experience: rec.Experience.ToString(),
+		},
+	}
+	if result.Salary != "" {
+		result.Currency = "RUR"
+	}
+	location := rec.PlaceOfWork
+	if location == "" && rec.City != nil {
+		location = rec.City.City
+	}
+	if location != "" {
+		result.Addresses = []FeedAddress{{Location: location}}
+	}
+	if rec.Company != nil {
+		result.Company.Name = rec.Company.Name
+	}
+	if rec.Space != nil {
+		if result.Company.Name == "" {
+			result.Company.Name = rec.Space.OrganizationName
+		}
+		result.Company.Description = rec.Space.Description
+		result.Company.Site = rec.Space.Web
+	}
+	return result
+}
+
+func GetSalaryText(salary dbmodels.Salary) string {
+	switch {
+	case salary.InHand != 0:
+		return fmt.Sprintf("%v", salary.InHand)
+	case salary.From != 0 && salary.To != 0:
+		return fmt.Sprintf("от %v до %v", salary.From, salary.To)
+	case salary.From != 0:
+		return fmt.Sprintf("от %v", salary.From)
+	case salary.To != 0:
+		return fmt.Sprintf("до %v", salary.To)
+	}
+	return ""
+}
+
+// RSS 2.0
+
+type Rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel RssChannel `xml:"channel"`
+}
+
+type RssChannel struct {
+	Title       string    `xml:"title"`
+	Link        string    `xml:"link"`
+	Description string    `xml:"description"`
+	Items       []RssItem `xml:"item"`
+}
+
+type RssItem struct {
+	Guid        string `xml:"guid"`
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Description string `xml:"description"`
+	PubDate     string `xml:"pubDate"`
+}
+
+func RssItemConvert(rec dbmodels.Vacancy, url string) RssItem {
+	return RssItem{
+		Guid:        rec.ID,
+		Title:       rec.VacancyName,
+		Link:        url,
+		Description: rec.Requirements,
+		PubDate:     rec.CreatedAt.Format(time.RFC1123Z),
+	}
+}
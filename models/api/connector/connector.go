@@ -0,0 +1,24 @@
+package connectorapimodels
+
+import (
+	externalservices "hr-tools-backend/lib/external-services"
+	"hr-tools-backend/models"
+)
+
+type ConnectorView struct {
+	Code         string                        `json:"code"`         // код коннектора
+	Name         string                        `json:"name"`         // наименование работного сайта
+	Source       models.ApplicantSource        `json:"source"`       // источник кандидатов
+	Capabilities []externalservices.Capability `json:"capabilities"` // поддерживаемые возможности
+	Connected    bool                          `json:"connected"`    // подключен ли спейс к работному сайту
+}
+
+func ConnectorConvert(rec externalservices.Connector, connected bool) ConnectorView {
+	return ConnectorView{
+		Code:         rec.Code,
+		Name:         rec.Name,
+		Source:       rec.Source,
+		Capabilities: rec.Capabilities,
+		Connected:    connected,
+	}
+}
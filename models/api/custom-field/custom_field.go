@@ -0,0 +1,101 @@
+package customfieldapimodels
+
+import (
+	"hr-tools-backend/models"
+	dbmodels "hr-tools-backend/models/db"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var codeRegexp = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+type CustomFieldData struct {
+	Entity       models.CustomFieldEntity `json:"entity"`        // Сущность (APPLICANT, VACANCY, VACANCY_REQUEST), после создания не меняется
+	Code         string                   `json:"code"`          // Код поля (латиница в нижнем регистре, цифры, подчеркивание), ключ в custom_fields и переменная шаблонов, после создания не меняется
+	Name         string                   `json:"name"`          // Название
+	Type         models.CustomFieldType   `json:"type"`          // Тип (TEXT, NUMBER, DATE, ENUM, MULTI_ENUM, BOOLEAN, USER), после создания не меняется
+	Options      []string                 `json:"options"`       // Варианты значений для ENUM и MULTI_ENUM
+	Required     bool                     `json:"required"`      // Обязательное
+	VisibleRoles []string                 `json:"visible_roles"` // Встроенные роли и ID ролей спейса, которым поле видно и доступно для изменения, пусто - всем
+	Order        int                      `json:"order"`         // Порядок отображения
+}
+
+func (r CustomFieldData) Validate() error {
+	if err := r.Entity.Validate(); err != nil {
+		return err
+	}
+	if !codeRegexp.MatchString(r.Code) || len(r.Code) > 100 {
+		return errors.New("код поля должен начинаться с латинской буквы и содержать только латинские буквы в нижнем регистре, цифры и подчеркивание")
+	}
+	if strings.TrimSpace(r.Name) == "" {
+		return errors.New("не указано название поля")
+	}
+	if len([]rune(r.Name)) > 255 {
+		return errors.New("название поля слишком длинное")
+	}
+	if err := r.Type.Validate(); err != nil {
+		return err
+	}
+	if r.Type.HasOptions() {
+		if len(r.Options) == 0 {
+			return errors.New("не указаны варианты значений")
+		}
+		for k, option := range r.Options {
+			if strings.TrimSpace(option) == "" {
+				return errors.New("вариант значения не может быть пустым")
+			}
+			if slices.Contains(r.Options[:k], option) {
+				return errors.Errorf("вариант значения '%v' повторяется", option)
+			}
+		}
+	} else if len(r.Options) != 0 {
+		return errors.New("варианты значений указываются только для списков")
+	}
+	for k, role := range r.VisibleRoles {
+		if strings.TrimSpace(role) == "" {
+			return errors.New("роль не может быть пустой")
+		}
+		if slices.Contains(r.VisibleRoles[:k], role) {
+			return errors.Errorf("роль %v повторяется", role)
+		}
+	}
+	return nil
+}
+
+type CustomFieldView struct {
+	CustomFieldData
+	ID         string    `json:"id"`
+	EntityName string    `json:"entity_name"` // Название сущности
+	TypeName   string    `json:"type_name"`   // Название типа
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func CustomFieldConvert(rec dbmodels.CustomField) CustomFieldView {
+	result := CustomFieldView{
+		CustomFieldData: CustomFieldData{
+			Entity:       rec.Entity,
+			Code:         rec.Code,
+			Name:         rec.Name,
+			Type:         rec.Type,
+			Options:      rec.Options,
+			Required:     rec.Required,
+			VisibleRoles: rec.VisibleRoles,
+			Order:        rec.Order,
+		},
+		ID:         rec.ID,
+		EntityName: rec.Entity.ToHuman(),
+		TypeName:   rec.Type.ToHuman(),
+		CreatedAt:  rec.CreatedAt,
+	}
+	if result.Options == nil {
+		result.Options = []string{}
+	}
+	if result.VisibleRoles == nil {
+		result.VisibleRoles = []string{}
+	}
+	return result
+}
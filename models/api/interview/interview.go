@@ -0,0 +1,234 @@
+package interviewapimodels
+
+import (
+	apimodels "hr-tools-backend/models/api"
+	dbmodels "hr-tools-backend/models/db"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// максимальная длительность интервью/слота
+const maxDuration = 12 * time.Hour
+
+type InterviewData struct {
+	SelectionStageID string    `json:"selection_stage_id"` // Идентификатор этапа подбора, по умолчанию - текущий этап кандидата
+	StartAt          time.Time `json:"start_at"`           // Начало интервью
+	EndAt            time.Time `json:"end_at"`             // Окончание интервью
+	Participants     []string  `json:"participants"`       // Идентификаторы участников из команды вакансии
+	Location         string    `json:"location"`           // Место проведения / ссылка на видеовстречу
+	Comment          string    `json:"comment"`            // Комментарий
+}
+
+func (r InterviewData) Validate() error {
+	if len(r.Participants) == 0 {
+		return errors.New("не указаны участники интервью")
+	}
+	return ValidatePeriod(r.StartAt, r.EndAt)
+}
+
+type CreateInterviewRequest struct {
+	ApplicantID string `json:"applicant_id"` // Идентификатор кандидата
+	InterviewData
+}
+
+func (r CreateInterviewRequest) Validate() error {
+	if r.ApplicantID == "" {
+		return errors.New("не указан кандидат")
+	}
+	return r.InterviewData.Validate()
+}
+
+type InterviewFilter struct {
+	apimodels.Pagination
+	ApplicantID string                   `json:"applicant_id"` // Идентификатор кандидата
+	VacancyID   string                   `json:"vacancy_id"`   // Идентификатор вакансии
+	UserID      string                   `json:"user_id"`      // Идентификатор участника
+	DateFrom    *time.Time               `json:"date_from"`    // Начало интервью "от"
+	DateTo      *time.Time               `json:"date_to"`      // Начало интервью "до"
+	Status      dbmodels.InterviewStatus `json:"status"`       // Статус
+}
+
+type InterviewView struct {
+	ID                 string                   `json:"id"`
+	ApplicantID        string                   `json:"applicant_id"`         // Идентификатор кандидата
+	ApplicantFIO       string                   `json:"applicant_fio"`        // ФИО кандидата
+	VacancyID          string                   `json:"vacancy_id"`           // Идентификатор вакансии
+	VacancyName        string                   `json:"vacancy_name"`         // Название вакансии
+	SelectionStageID   string                   `json:"selection_stage_id"`   // Идентификатор этапа подбора
+	SelectionStageName string                   `json:"selection_stage_name"` // Название этапа подбора
+	StartAt            time.Time                `json:"start_at"`             // Начало интервью
+	EndAt              time.Time                `json:"end_at"`               // Окончание интервью
+	Location           string                   `json:"location"`             // Место проведения / ссылка на видеовстречу
+	Comment            string                   `json:"comment"`              // Комментарий
+	Status             dbmodels.InterviewStatus `json:"status"`               // Статус
+	AuthorID           string                   `json:"author_id"`            // Идентификатор автора
+	Participants       []ParticipantView        `json:"participants"`         // Участники
+	CreatedAt          time.Time                `json:"created_at"`           // Дата создания
+}
+
+type ParticipantView struct {
+	UserID   string `json:"user_id"`   // Идентификатор пользователя
+	FullName string `json:"full_name"` // ФИО
+	Email    string `json:"email"`     // Email
+}
+
+func InterviewConvert(rec dbmodels.Interview) InterviewView {
+	result := InterviewView{
+		ID:               rec.ID,
+		ApplicantID:      rec.ApplicantID,
+		VacancyID:        rec.VacancyID,
+		SelectionStageID: rec.SelectionStageID,
+		StartAt:          rec.StartAt,
+		EndAt:            rec.EndAt,
+		Location:         rec.Location,
+		Comment:          rec.Comment,
+		Status:           rec.Status,
+		AuthorID:         rec.AuthorID,
+		Participants:     make([]ParticipantView, 0, len(rec.Participants)),
+		CreatedAt:        rec.CreatedAt,
+	}
+	if rec.Applicant != nil {
+		result.ApplicantFIO = rec.Applicant.GetFIO()
+	}
+	if rec.Vacancy != nil {
+		result.VacancyName = rec.Vacancy.VacancyName
+	}
+	if rec.SelectionStage != nil {
+		result.SelectionStageName = rec.SelectionStage.Name
+	}
+	for _, participant := range rec.Participants {
+		view := ParticipantView{
+			UserID: participant.UserID,
+		}
+		if participant.SpaceUser != nil {
+			view.FullName = participant.SpaceUser.GetFullName()
+			view.Email = participant.SpaceUser.Email
+		}
+		result.Participants = append(result.Participants, view)
+	}
+	return result
+}
+
+type SlotData struct {
+	UserID  string    `json:"user_id"`  // Идентификатор интервьюера, по умолчанию - текущий пользователь
+	StartAt time.Time `json:"start_at"` // Начало слота
+	EndAt   time.Time `json:"end_at"`   // Окончание слота
+}
+
+func (r SlotData) Validate() error {
+	return ValidatePeriod(r.StartAt, r.EndAt)
+}
+
+type SlotFilter struct {
+	UserID   string     `json:"user_id"`   // Идентификатор интервьюера
+	DateFrom *time.Time `json:"date_from"` // Начало слота "от"
+	DateTo   *time.Time `json:"date_to"`   // Начало слота "до"
+	FreeOnly bool       `json:"free_only"` // Только свободные слоты, с учетом занятости в подключенных календарях интервьюеров
+}
+
+func (r SlotFilter) ToDbFilter() dbmodels.InterviewSlotFilter {
+	result := dbmodels.InterviewSlotFilter{
+		FreeOnly: r.FreeOnly,
+	}
+	if r.UserID != "" {
+		result.UserIDs = []string{r.UserID}
+	}
+	if r.DateFrom != nil {
+		result.From = *r.DateFrom
+	}
+	if r.DateTo != nil {
+		result.To = *r.DateTo
+	}
+	return result
+}
+
+type SlotView struct {
+	ID          string    `json:"id"`
+	UserID      string    `json:"user_id"`      // Идентификатор интервьюера
+	UserName    string    `json:"user_name"`    // ФИО интервьюера
+	StartAt     time.Time `json:"start_at"`     // Начало слота
+	EndAt       time.Time `json:"end_at"`       // Окончание слота
+	InterviewID *string   `json:"interview_id"` // Идентификатор интервью, если слот занят
+}
+
+func SlotConvert(rec dbmodels.InterviewSlot) SlotView {
+	result := SlotView{
+		ID:          rec.ID,
+		UserID:      rec.UserID,
+		StartAt:     rec.StartAt,
+		EndAt:       rec.EndAt,
+		InterviewID: rec.InterviewID,
+	}
+	if rec.SpaceUser != nil {
+		result.UserName = rec.SpaceUser.GetFullName()
+	}
+	return result
+}
+
+type BookingRequest struct {
+	ApplicantID      string   `json:"applicant_id"`       // Идентификатор кандидата
+	SelectionStageID string   `json:"selection_stage_id"` // Идентификатор этапа подбора, по умолчанию - текущий этап кандидата
+	Participants     []string `json:"participants"`       // Интервьюеры из команды вакансии, свободные слоты которых доступны кандидату
+	Location         string   `json:"location"`           // Место проведения / ссылка на видеовстречу
+	ExpiresInDays    int      `json:"expires_in_days"`    // Срок действия ссылки в днях, по умолчанию 7
+}
+
+func (r BookingRequest) Validate() error {
+	if r.ApplicantID == "" {
+		return errors.New("не указан кандидат")
+	}
+	if len(r.Participants) == 0 {
+		return errors.New("не указаны интервьюеры")
+	}
+	if r.ExpiresInDays < 0 {
+		return errors.New("некорректный срок действия ссылки")
+	}
+	return nil
+}
+
+type BookingView struct {
+	ID        string    `json:"id"`         // Идентификатор ссылки
+	Link      string    `json:"link"`       // Ссылка для записи кандидата
+	ExpiresAt time.Time `json:"expires_at"` // Срок действия ссылки
+}
+
+type PublicBookingView struct {
+	VacancyName string                `json:"vacancy_name"` // Название вакансии
+	FirstName   string                `json:"first_name"`   // Имя кандидата
+	ExpiresAt   time.Time             `json:"expires_at"`   // Срок действия ссылки
+	Interview   *PublicInterviewView  `json:"interview"`    // Назначенное интервью, если кандидат уже записался
+	Slots       []PublicInterviewView `json:"slots"`        // Свободные слоты
+}
+
+type PublicInterviewView struct {
+	ID       string    `json:"id"`       // Идентификатор слота
+	StartAt  time.Time `json:"start_at"` // Начало
+	EndAt    time.Time `json:"end_at"`   // Окончание
+	Location string    `json:"location"` // Место проведения / ссылка на видеовстречу
+}
+
+type PublicBookRequest struct {
+	SlotID string `json:"slot_id"` // Идентификатор выбранного слота
+}
+
+func (r PublicBookRequest) Validate() error {
+	if r.SlotID == "" {
+		return errors.New("не указан слот")
+	}
+	return nil
+}
+
+// ValidatePeriod проверка времени интервью/слота
+func ValidatePeriod(startAt, endAt time.Time) error {
+	if startAt.IsZero() || endAt.IsZero() {
+		return errors.New("не указано время начала или окончания")
+	}
+	if !endAt.After(startAt) {
+		return errors.New("время окончания должно быть позже времени начала")
+	}
+	if endAt.Sub(startAt) > maxDuration {
+		return errors.New("слишком большая продолжительность")
+	}
+	return nil
+}
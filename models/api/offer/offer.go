@@ -0,0 +1,151 @@
+package offerapimodels
+
+import (
+	vacancyapimodels "hr-tools-backend/models/api/vacancy"
+	dbmodels "hr-tools-backend/models/db"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type OfferData struct {
+	TemplateID string    `json:"template_id"` // Идентификатор шаблона сообщения с типом "Оффер" для формирования pdf
+	Salary     int       `json:"salary"`      // Заработная плата
+	StartDate  time.Time `json:"start_date"`  // Дата выхода
+	Conditions string    `json:"conditions"`  // Условия
+	ExpiresAt  time.Time `json:"expires_at"`  // Срок действия
+}
+
+func (r OfferData) Validate() error {
+	if r.TemplateID == "" {
+		return errors.New("не указан шаблон оффера")
+	}
+	if r.Salary <= 0 {
+		return errors.New("не указана заработная плата")
+	}
+	if r.StartDate.IsZero() {
+		return errors.New("не указана дата выхода")
+	}
+	if r.ExpiresAt.IsZero() {
+		return errors.New("не указан срок действия оффера")
+	}
+	if !r.ExpiresAt.After(time.Now()) {
+		return errors.New("срок действия оффера должен быть в будущем")
+	}
+	return nil
+}
+
+type CreateOfferRequest struct {
+	ApplicantID string `json:"applicant_id"` // Идентификатор кандидата
+	OfferData
+}
+
+func (r CreateOfferRequest) Validate() error {
+	if r.ApplicantID == "" {
+		return errors.New("не указан кандидат")
+	}
+	return r.OfferData.Validate()
+}
+
+type ListRequest struct {
+	ApplicantID string `json:"applicant_id"` // Идентификатор кандидата
+}
+
+type DeclineRequest struct {
+	Reason string `json:"reason"` // Причина отказа
+}
+
+type OfferView struct {
+	ID            string                              `json:"id"`
+	ApplicantID   string                              `json:"applicant_id"`   // Идентификатор кандидата
+	ApplicantFIO  string                              `json:"applicant_fio"`  // ФИО кандидата
+	VacancyID     string                              `json:"vacancy_id"`     // Идентификатор вакансии
+	VacancyName   string                              `json:"vacancy_name"`   // Название вакансии
+	AuthorID      string                              `json:"author_id"`      // Идентификатор автора
+	AuthorName    string                              `json:"author_name"`    // ФИО автора
+	Number        string                              `json:"number"`         // Номер документа
+	Version       int                                 `json:"version"`        // Версия
+	Status        dbmodels.OfferStatus                `json:"status"`         // Статус: draft, in_approval, approved, rejected, sent, expired, accepted, declined, canceled
+	SentAt        *time.Time                          `json:"sent_at"`        // Дата отправки кандидату
+	DecidedAt     *time.Time                          `json:"decided_at"`     // Дата ответа кандидата
+	DeclineReason string                              `json:"decline_reason"` // Причина отказа кандидата
+	DocumentHash  string                              `json:"document_hash"`  // Хеш SHA-256 отправленного кандидату pdf
+	Approvals     []vacancyapimodels.ApprovalTaskView `json:"approvals"`      // Цепочка согласования
+	CreatedAt     time.Time                           `json:"created_at"`     // Дата создания
+	OfferData
+}
+
+func OfferConvert(rec dbmodels.Offer) OfferView {
+	result := OfferView{
+		ID:            rec.ID,
+		ApplicantID:   rec.ApplicantID,
+		VacancyID:     rec.VacancyID,
+		AuthorID:      rec.AuthorID,
+		Number:        rec.GetNumber(),
+		Version:       rec.Version,
+		Status:        rec.GetStatus(),
+		SentAt:        rec.SentAt,
+		DecidedAt:     rec.DecidedAt,
+		DeclineReason: rec.DeclineReason,
+		DocumentHash:  rec.DocumentHash,
+		Approvals:     []vacancyapimodels.ApprovalTaskView{},
+		CreatedAt:     rec.CreatedAt,
+		OfferData: OfferData{
+			TemplateID: rec.TemplateID,
+			Salary:     rec.Salary,
+			StartDate:  rec.StartDate,
+			Conditions: rec.Conditions,
+			ExpiresAt:  rec.ExpiresAt,
+		},
+	}
+	if rec.Applicant != nil {
+		result.ApplicantFIO = rec.Applicant.GetFIO()
+		if rec.Applicant.Vacancy != nil {
+			result.VacancyName = rec.Applicant.Vacancy.VacancyName
+		}
+	}
+	if rec.Author != nil {
+		result.AuthorName = rec.Author.GetFullName()
+	}
+	return result
+}
+
+type OfferVersionView struct {
+	Version    int       `json:"version"`     // Версия
+	AuthorID   string    `json:"author_id"`   // Идентификатор автора изменений
+	AuthorName string    `json:"author_name"` // ФИО автора изменений
+	CreatedAt  time.Time `json:"created_at"`  // Дата изменения
+	OfferData
+}
+
+func OfferVersionConvert(rec dbmodels.OfferVersion) OfferVersionView {
+	result := OfferVersionView{
+		Version:   rec.Version,
+		AuthorID:  rec.AuthorID,
+		CreatedAt: rec.CreatedAt,
+		OfferData: OfferData{
+			TemplateID: rec.TemplateID,
+			Salary:     rec.Salary,
+			StartDate:  rec.StartDate,
+			Conditions: rec.Conditions,
+			ExpiresAt:  rec.ExpiresAt,
+		},
+	}
+	if rec.Author != nil {
+		result.AuthorName = rec.Author.GetFullName()
+	}
+	return result
+}
+
+// PublicOfferView оффер для кандидата
+type PublicOfferView struct {
+	Number       string               `json:"number"`        // Номер документа
+	FirstName    string               `json:"first_name"`    // Имя кандидата
+	VacancyName  string               `json:"vacancy_name"`  // Название вакансии
+	Salary       int                  `json:"salary"`        // Заработная плата
+	StartDate    time.Time            `json:"start_date"`    // Дата выхода
+	Conditions   string               `json:"conditions"`    // Условия
+	ExpiresAt    time.Time            `json:"expires_at"`    // Срок действия
+	Status       dbmodels.OfferStatus `json:"status"`        // Статус: sent - ожидает ответа, expired, accepted, declined
+	DocumentHash string               `json:"document_hash"` // Хеш SHA-256 документа
+}
@@ -0,0 +1,204 @@
+package personaldataapimodels
+
+import (
+	"hr-tools-backend/models"
+	apimodels "hr-tools-backend/models/api"
+	applicantapimodels "hr-tools-backend/models/api/applicant"
+	filesapimodels "hr-tools-backend/models/api/files"
+	interviewapimodels "hr-tools-backend/models/api/interview"
+	offerapimodels "hr-tools-backend/models/api/offer"
+	scorecardapimodels "hr-tools-backend/models/api/scorecard"
+	dbmodels "hr-tools-backend/models/db"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ConsentMeta данные о получении согласия на обработку ПД в публичной точке входа
+type ConsentMeta struct {
+	Source    models.ConsentSource
+	IP        string
+	UserAgent string
+}
+
+type ConsentRequest struct {
+	PdConsent bool `json:"pd_consent"` // Согласие на обработку персональных данных
+}
+
+func (c ConsentRequest) Validate() error {
+	if !c.PdConsent {
+		return errors.New(models.ConsentRequiredMsg)
+	}
+	return nil
+}
+
+type ConsentView struct {
+	ID          string               `json:"id"`           // Идентификатор согласия
+	ApplicantID string               `json:"applicant_id"` // Идентификатор кандидата
+	Source      models.ConsentSource `json:"source"`       // Точка получения согласия (career_site/survey/vk_step0/video_interview)
+	PolicyUrl   string               `json:"policy_url"`   // Политика обработки ПД на момент согласия
+	IP          string               `json:"ip"`           // IP адрес
+	UserAgent   string               `json:"user_agent"`   // Браузер
+	CreatedAt   string               `json:"created_at"`   // Дата согласия ДД.ММ.ГГГГ ЧЧ:ММ
+}
+
+func ConsentConvert(rec dbmodels.PersonalDataConsent) ConsentView {
+	return ConsentView{
+		ID:          rec.ID,
+		ApplicantID: rec.ApplicantID,
+		Source:      rec.Source,
+		PolicyUrl:   rec.PolicyUrl,
+		IP:          rec.IP,
+		UserAgent:   rec.UserAgent,
+		CreatedAt:   rec.CreatedAt.Format("02.01.2006 15:04"),
+	}
+}
+
+type RequestData struct {
+	ApplicantID string                        `json:"applicant_id"` // Идентификатор кандидата
+	RequestType models.DataSubjectRequestType `json:"request_type"` // Тип запроса (export - выгрузка, erasure - удаление)
+	Comment     string                        `json:"comment"`      // Комментарий (способ обращения, реквизиты запроса)
+}
+
+func (r RequestData) Validate() error {
+	if r.ApplicantID == "" {
+		return errors.New("не указан кандидат")
+	}
+	if r.RequestType != models.DataSubjectRequestExport && r.RequestType != models.DataSubjectRequestErasure {
+		return errors.New("некорректный тип запроса")
+	}
+	return nil
+}
+
+type RequestFilter struct {
+	apimodels.Pagination
+	ApplicantID string                          `json:"applicant_id"` // Идентификатор кандидата
+	Status      models.DataSubjectRequestStatus `json:"status"`       // Статус (new/done/rejected)
+}
+
+type RequestView struct {
+	RequestData
+	ID              string                          `json:"id"`                // Идентификатор запроса
+	Status          models.DataSubjectRequestStatus `json:"status"`            // Статус (new/done/rejected)
+	AuthorID        string                          `json:"author_id"`         // Сотрудник, зарегистрировавший запрос
+	AuthorName      string                          `json:"author_name"`       // Имя сотрудника
+	ProcessedByID   string                          `json:"processed_by_id"`   // Сотрудник, обработавший запрос
+	ProcessedByName string                          `json:"processed_by_name"` // Имя сотрудника
+	ProcessedAt     string                          `json:"processed_at"`      // Дата обработки ДД.ММ.ГГГГ ЧЧ:ММ
+	RejectReason    string                          `json:"reject_reason"`     // Причина отказа
+	CreatedAt       string                          `json:"created_at"`        // Дата регистрации ДД.ММ.ГГГГ ЧЧ:ММ
+}
+
+func RequestConvert(rec dbmodels.DataSubjectRequest) RequestView {
+	result := RequestView{
+		RequestData: RequestData{
+			ApplicantID: rec.ApplicantID,
+			RequestType: rec.RequestType,
+			Comment:     rec.Comment,
+		},
+		ID:           rec.ID,
+		Status:       rec.Status,
+		AuthorID:     rec.AuthorID,
+		RejectReason: rec.RejectReason,
+		CreatedAt:    rec.CreatedAt.Format("02.01.2006 15:04"),
+	}
+	if rec.Author != nil {
+		result.AuthorName = rec.Author.GetFullName()
+	}
+	if rec.ProcessedByID != nil {
+		result.ProcessedByID = *rec.ProcessedByID
+	}
+	if rec.ProcessedBy != nil {
+		result.ProcessedByName = rec.ProcessedBy.GetFullName()
+	}
+	if rec.ProcessedAt != nil {
+		result.ProcessedAt = rec.ProcessedAt.Format("02.01.2006 15:04")
+	}
+	return result
+}
+
+type RejectRequest struct {
+	Reason string `json:"reason"` // Причина отказа
+}
+
+func (r RejectRequest) Validate() error {
+	if r.Reason == "" {
+		return errors.New("не указана причина отказа")
+	}
+	return nil
+}
+
+type AuditFilter struct {
+	apimodels.Pagination
+	ApplicantID string `json:"applicant_id"` // Идентификатор кандидата
+}
+
+type AuditView struct {
+	ApplicantID string               `json:"applicant_id"` // Идентификатор кандидата
+	RequestID   string               `json:"request_id"`   // Идентификатор запроса субъекта ПД
+	UserID      string               `json:"user_id"`      // Сотрудник, пусто - система
+	UserName    string               `json:"user_name"`    // Имя сотрудника
+	Action      models.PdAuditAction `json:"action"`       // Действие
+	Description string               `json:"description"`  // Описание
+	CreatedAt   string               `json:"created_at"`   // Дата ДД.ММ.ГГГГ ЧЧ:ММ
+}
+
+func AuditConvert(rec dbmodels.PersonalDataAudit) AuditView {
+	result := AuditView{
+		ApplicantID: rec.ApplicantID,
+		Action:      rec.Action,
+		Description: rec.Description,
+		CreatedAt:   rec.CreatedAt.Format("02.01.2006 15:04"),
+	}
+	if rec.RequestID != nil {
+		result.RequestID = *rec.RequestID
+	}
+	if rec.UserID != nil {
+		result.UserID = *rec.UserID
+	}
+	if rec.User != nil {
+		result.UserName = rec.User.GetFullName()
+	}
+	return result
+}
+
+// DataExport выгрузка персональных данных субъекта по всем вакансиям
+type DataExport struct {
+	ExportedAt string                                    `json:"exported_at"` // Дата выгрузки ДД.ММ.ГГГГ ЧЧ:ММ
+	Applicants []applicantapimodels.ApplicantView        `json:"applicants"`  // Данные кандидата по вакансиям
+	History    []applicantapimodels.ApplicantHistoryView `json:"history"`     // История действий
+	Consents   []ConsentView                             `json:"consents"`    // Согласия на обработку ПД
+	Files      []filesapimodels.FileView                 `json:"files"`       // Файлы кандидата и отправленные ему документы офферов
+	Interviews []interviewapimodels.InterviewView        `json:"interviews"`  // Интервью
+	Bookings   []BookingExportView                       `json:"bookings"`    // Ссылки для записи на интервью
+	Offers     []OfferExportView                         `json:"offers"`      // Офферы с историей изменений
+	Scorecards []scorecardapimodels.ScorecardView        `json:"scorecards"`  // Оценочные карты
+}
+
+type BookingExportView struct {
+	ID          string    `json:"id"`           // Идентификатор ссылки
+	ApplicantID string    `json:"applicant_id"` // Идентификатор кандидата
+	Location    string    `json:"location"`     // Место проведения / ссылка на видеовстречу
+	ExpiresAt   time.Time `json:"expires_at"`   // Срок действия ссылки
+	InterviewID string    `json:"interview_id"` // Интервью, на которое записался кандидат
+	CreatedAt   time.Time `json:"created_at"`   // Дата создания
+}
+
+func BookingExportConvert(rec dbmodels.InterviewBooking) BookingExportView {
+	result := BookingExportView{
+		ID:          rec.ID,
+		ApplicantID: rec.ApplicantID,
+		Location:    rec.Location,
+		ExpiresAt:   rec.ExpiresAt,
+		CreatedAt:   rec.CreatedAt,
+	}
+	if rec.InterviewID != nil {
+		result.InterviewID = *rec.InterviewID
+	}
+	return result
+}
+
+type OfferExportView struct {
+	offerapimodels.OfferView
+	Versions []offerapimodels.OfferVersionView `json:"versions"` // Предыдущие версии оффера
+}
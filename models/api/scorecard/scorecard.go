@@ -0,0 +1,193 @@
+package scorecardapimodels
+
+import (
+	dbmodels "hr-tools-backend/models/db"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultScaleMin = 1
+	defaultScaleMax = 5
+	maxScale        = 10
+)
+
+type TemplateData struct {
+	VacancyID        string           `json:"vacancy_id"`         // Идентификатор вакансии
+	SelectionStageID string           `json:"selection_stage_id"` // Идентификатор этапа подбора
+	Name             string           `json:"name"`               // Название
+	ScaleMin         int              `json:"scale_min"`          // Минимальная оценка, по умолчанию 1
+	ScaleMax         int              `json:"scale_max"`          // Максимальная оценка, по умолчанию 5
+	PassRating       int              `json:"pass_rating"`        // Проходная оценка для обязательных компетенций, по умолчанию - середина шкалы
+	Competencies     []CompetencyData `json:"competencies"`       // Компетенции
+}
+
+type CompetencyData struct {
+	ID          string `json:"id"`          // Идентификатор компетенции, пусто - новая компетенция
+	Name        string `json:"name"`        // Название
+	Description string `json:"description"` // Описание / что проверяется
+	MustHave    bool   `json:"must_have"`   // Обязательная компетенция
+}
+
+func (r *TemplateData) Validate() error {
+	if r.VacancyID == "" {
+		return errors.New("не указана вакансия")
+	}
+	if r.SelectionStageID == "" {
+		return errors.New("не указан этап подбора")
+	}
+	if strings.TrimSpace(r.Name) == "" {
+		return errors.New("не указано название оценочной карты")
+	}
+	if r.ScaleMin == 0 && r.ScaleMax == 0 {
+		r.ScaleMin, r.ScaleMax = defaultScaleMin, defaultScaleMax
+	}
+	if r.ScaleMin < 1 || r.ScaleMax <= r.ScaleMin || r.ScaleMax > maxScale {
+		return errors.Errorf("некорректная шкала оценок, допустимый диапазон от 1 до %v", maxScale)
+	}
+	if r.PassRating == 0 {
+		r.PassRating = (r.ScaleMin + r.ScaleMax + 1) / 2
+	}
+	if r.PassRating < r.ScaleMin || r.PassRating > r.ScaleMax {
+		return errors.New("проходная оценка должна быть в пределах шкалы")
+	}
+	if len(r.Competencies) == 0 {
+		return errors.New("не указаны компетенции")
+	}
+	names := map[string]bool{}
+	for _, item := range r.Competencies {
+		name := strings.ToLower(strings.TrimSpace(item.Name))
+		if name == "" {
+			return errors.New("не указано название компетенции")
+		}
+		if names[name] {
+			return errors.Errorf("компетенция %v указана несколько раз", item.Name)
+		}
+		names[name] = true
+	}
+	return nil
+}
+
+type TemplateListRequest struct {
+	VacancyID string `json:"vacancy_id"` // Идентификатор вакансии
+}
+
+type TemplateView struct {
+	ID                 string                         `json:"id"`
+	VacancyID          string                         `json:"vacancy_id"`           // Идентификатор вакансии
+	SelectionStageID   string                         `json:"selection_stage_id"`   // Идентификатор этапа подбора
+	SelectionStageName string                         `json:"selection_stage_name"` // Название этапа подбора
+	Name               string                         `json:"name"`                 // Название
+	ScaleMin           int                            `json:"scale_min"`            // Минимальная оценка
+	ScaleMax           int                            `json:"scale_max"`            // Максимальная оценка
+	PassRating         int                            `json:"pass_rating"`          // Проходная оценка для обязательных компетенций
+	Competencies       []dbmodels.ScorecardCompetency `json:"competencies"`         // Компетенции
+}
+
+func TemplateConvert(rec dbmodels.ScorecardTemplate) TemplateView {
+	result := TemplateView{
+		ID:               rec.ID,
+		VacancyID:        rec.VacancyID,
+		SelectionStageID: rec.SelectionStageID,
+		Name:             rec.Name,
+		ScaleMin:         rec.ScaleMin,
+		ScaleMax:         rec.ScaleMax,
+		PassRating:       rec.PassRating,
+		Competencies:     rec.Competencies,
+	}
+	if rec.SelectionStage != nil {
+		result.SelectionStageName = rec.SelectionStage.Name
+	}
+	if result.Competencies == nil {
+		result.Competencies = []dbmodels.ScorecardCompetency{}
+	}
+	return result
+}
+
+type ScorecardData struct {
+	SelectionStageID string                           `json:"selection_stage_id"` // Идентификатор этапа подбора
+	Ratings          []dbmodels.ScorecardRating       `json:"ratings"`            // Оценки по компетенциям
+	Recommendation   dbmodels.ScorecardRecommendation `json:"recommendation"`     // Рекомендация: strong_yes, yes, no, strong_no
+	Comment          string                           `json:"comment"`            // Комментарий
+	Submit           bool                             `json:"submit"`             // Отправить оценку, false - сохранить черновик
+}
+
+func (r ScorecardData) Validate() error {
+	if r.SelectionStageID == "" {
+		return errors.New("не указан этап подбора")
+	}
+	if r.Recommendation != "" {
+		if _, ok := dbmodels.RecommendationNames[r.Recommendation]; !ok {
+			return errors.New("некорректная рекомендация")
+		}
+	}
+	if r.Submit && r.Recommendation == "" {
+		return errors.New("не указана рекомендация")
+	}
+	return nil
+}
+
+type ScorecardView struct {
+	ID               string                           `json:"id"`
+	UserID           string                           `json:"user_id"`            // Идентификатор интервьюера
+	UserName         string                           `json:"user_name"`          // ФИО интервьюера
+	SelectionStageID string                           `json:"selection_stage_id"` // Идентификатор этапа подбора
+	Ratings          []dbmodels.ScorecardRating       `json:"ratings"`            // Оценки по компетенциям
+	Average          float64                          `json:"average"`            // Средняя оценка
+	Recommendation   dbmodels.ScorecardRecommendation `json:"recommendation"`     // Рекомендация
+	Comment          string                           `json:"comment"`            // Комментарий
+	Status           dbmodels.ScorecardStatus         `json:"status"`             // Статус: draft - черновик, submitted - отправлена
+	SubmittedAt      *time.Time                       `json:"submitted_at"`       // Дата отправки
+}
+
+func ScorecardConvert(rec dbmodels.Scorecard) ScorecardView {
+	result := ScorecardView{
+		ID:               rec.ID,
+		UserID:           rec.UserID,
+		SelectionStageID: rec.SelectionStageID,
+		Ratings:          rec.Ratings,
+		Average:          rec.Ratings.Average(),
+		Recommendation:   rec.Recommendation,
+		Comment:          rec.Comment,
+		Status:           rec.Status,
+		SubmittedAt:      rec.SubmittedAt,
+	}
+	if rec.SpaceUser != nil {
+		result.UserName = rec.SpaceUser.GetFullName()
+	}
+	if result.Ratings == nil {
+		result.Ratings = []dbmodels.ScorecardRating{}
+	}
+	return result
+}
+
+type ApplicantScorecardsView struct {
+	Stages []StageScorecardsView `json:"stages"` // Оценки по этапам подбора, для которых настроены оценочные карты
+}
+
+type StageScorecardsView struct {
+	Template       TemplateView    `json:"template"`        // Шаблон оценочной карты этапа
+	Own            *ScorecardView  `json:"own"`             // Оценочная карта текущего пользователя
+	Hidden         bool            `json:"hidden"`          // Оценки других участников скрыты до отправки своей оценочной карты
+	SubmittedCount int             `json:"submitted_count"` // Количество отправленных оценочных карт
+	Summary        *SummaryView    `json:"summary"`         // Сводная оценка, не заполняется, если оценки скрыты
+	Scorecards     []ScorecardView `json:"scorecards"`      // Отправленные оценочные карты, пусто, если оценки скрыты
+}
+
+type SummaryView struct {
+	Average         float64                                  `json:"average"`          // Средняя оценка
+	MustHaveFailed  bool                                     `json:"must_have_failed"` // Есть обязательные компетенции с оценкой ниже проходной
+	Competencies    []CompetencySummaryView                  `json:"competencies"`     // Оценки по компетенциям
+	Recommendations map[dbmodels.ScorecardRecommendation]int `json:"recommendations"`  // Количество рекомендаций
+}
+
+type CompetencySummaryView struct {
+	CompetencyID string  `json:"competency_id"` // Идентификатор компетенции
+	Name         string  `json:"name"`          // Название
+	MustHave     bool    `json:"must_have"`     // Обязательная компетенция
+	Average      float64 `json:"average"`       // Средняя оценка
+	Count        int     `json:"count"`         // Количество оценок
+	FailedCount  int     `json:"failed_count"`  // Количество оценок ниже проходной
+}
@@ -0,0 +1,80 @@
+package spaceroleapimodels
+
+import (
+	"hr-tools-backend/models"
+	dbmodels "hr-tools-backend/models/db"
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type SpaceRoleData struct {
+	Name        string                                `json:"name"`        // Название роли
+	Description string                                `json:"description"` // Описание
+	BaseRole    models.UserRole                       `json:"base_role"`   // Базовая роль (HR, MANAGER, SPECIALIST), определяет поведение вне матрицы разрешений
+	Permissions map[models.Module][]models.Permission `json:"permissions"` // Матрица разрешений: модуль - список разрешений
+}
+
+func (r SpaceRoleData) Validate() error {
+	if r.Name == "" {
+		return errors.New("не указано название роли")
+	}
+	if len([]rune(r.Name)) > 255 {
+		return errors.New("название роли слишком длинное")
+	}
+	if len([]rune(r.Description)) > 1000 {
+		return errors.New("описание роли слишком длинное")
+	}
+	if !slices.Contains(models.AllAvailableRoles, r.BaseRole) {
+		return errors.New("некорректная базовая роль")
+	}
+	if r.BaseRole.IsSpaceAdmin() {
+		return errors.New("администратору доступны все разрешения, базовой ролью может быть HR, MANAGER или SPECIALIST")
+	}
+	return nil
+}
+
+type SpaceRoleView struct {
+	SpaceRoleData
+	ID           string    `json:"id"`
+	BaseRoleName string    `json:"base_role_name"` // Название базовой роли
+	UsersCount   int64     `json:"users_count"`    // Кол-во пользователей с ролью
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func SpaceRoleConvert(rec dbmodels.SpaceRole, usersCount int64) SpaceRoleView {
+	result := SpaceRoleView{
+		SpaceRoleData: SpaceRoleData{
+			Name:        rec.Name,
+			Description: rec.Description,
+			BaseRole:    rec.BaseRole,
+			Permissions: rec.Permissions,
+		},
+		ID:           rec.ID,
+		BaseRoleName: rec.BaseRole.ToHuman(),
+		UsersCount:   usersCount,
+		CreatedAt:    rec.CreatedAt,
+	}
+	if result.Permissions == nil {
+		result.Permissions = map[models.Module][]models.Permission{}
+	}
+	return result
+}
+
+// RoleCatalog справочник для редактора матрицы разрешений
+type RoleCatalog struct {
+	Modules      []ModulePermissions `json:"modules"`       // Модули и доступные в них разрешения
+	BuiltinRoles []BuiltinRoleView   `json:"builtin_roles"` // Разрешения встроенных ролей
+}
+
+type ModulePermissions struct {
+	Module      models.Module       `json:"module"`
+	Permissions []models.Permission `json:"permissions"`
+}
+
+type BuiltinRoleView struct {
+	Role        models.UserRole                       `json:"role"`
+	RoleName    string                                `json:"role_name"`
+	Permissions map[models.Module][]models.Permission `json:"permissions"`
+}
@@ -29,6 +29,8 @@ type SpaceUser struct {
 	Status          string    `json:"status"`            // Статус пользователя
 	StatusChangedAt time.Time `json:"status_changed_at"` // Дата изменения статуса
 	StatusComment   *string   `json:"status_comment"`    // Комментарий к статусу
+	DepartmentName  string    `json:"department_name"`   // Подразделение
+	DataScopeName   string    `json:"data_scope_name"`   // Действующая область видимости данных
 }
 
 type SpaceUserExt struct {
@@ -39,25 +41,31 @@ type SpaceUserExt struct {
 }
 
 type SpaceUserCommonData struct {
-	SpaceID     string          `json:"space_id"`
-	Email       string          `json:"email"` // Email пользователя
-	FirstName   string          `json:"first_name"`
-	LastName    string          `json:"last_name"`
-	PhoneNumber string          `json:"phone_number"`
-	Role        models.UserRole `json:"role"`
-	TextSign    string          `json:"text_sign"`    // Текст подписи
-	JobTitleID  string          `json:"job_title_id"` // Идентификатор должности
+	SpaceID      string           `json:"space_id"`
+	Email        string           `json:"email"` // Email пользователя
+	FirstName    string           `json:"first_name"`
+	LastName     string           `json:"last_name"`
+	PhoneNumber  string           `json:"phone_number"`
+	Role         models.UserRole  `json:"role"`
+	TextSign     string           `json:"text_sign"`     // Текст подписи
+	JobTitleID   string           `json:"job_title_id"`  // Идентификатор должности
+	SpaceRoleID  string           `json:"space_role_id"` // Роль спейса, если указана - роль пользователя берется из ее базовой роли
+	DataScope    models.DataScope `json:"data_scope"`    // Область видимости данных (OWN, TEAM, DEPARTMENT, SPACE), пусто - по роли
+	DepartmentID string           `json:"department_id"` // Подразделение пользователя
 }
 
 type SpaceUserUpdateData struct {
-	SpaceID     string  `json:"space_id"`
-	Email       string  `json:"email"` // Email пользователя
-	FirstName   string  `json:"first_name"`
-	LastName    string  `json:"last_name"`
-	PhoneNumber string  `json:"phone_number"`
-	Role        *string `json:"role"`
-	TextSign    *string `json:"text_sign"`    // Текст подписи
-	JobTitleID  *string `json:"job_title_id"` // Идентификатор должности
+	SpaceID      string            `json:"space_id"`
+	Email        string            `json:"email"` // Email пользователя
+	FirstName    string            `json:"first_name"`
+	LastName     string            `json:"last_name"`
+	PhoneNumber  string            `json:"phone_number"`
+	Role         *string           `json:"role"`
+	TextSign     *string           `json:"text_sign"`     // Текст подписи
+	JobTitleID   *string           `json:"job_title_id"`  // Идентификатор должности
+	SpaceRoleID  *string           `json:"space_role_id"` // Роль спейса, пустая строка - снять роль спейса
+	DataScope    *models.DataScope `json:"data_scope"`    // Область видимости данных, пустая строка - по роли
+	DepartmentID *string           `json:"department_id"` // Подразделение пользователя, пустая строка - снять
 }
 
 func (r SpaceUserUpdateData) Validate() error {
@@ -79,6 +87,9 @@ func (r SpaceUserUpdateData) Validate() error {
 	if r.Role != nil && !slices.Contains(models.AllAvailableRoles, models.UserRole(*r.Role)) {
 		return errors.New("указаная роль отсутсвует")
 	}
+	if r.DataScope != nil && *r.DataScope != "" && !slices.Contains(models.AllDataScopes, *r.DataScope) {
+		return errors.New("указаная область видимости отсутсвует")
+	}
 	return nil
 }
 
@@ -105,9 +116,12 @@ func (r SpaceUserCommonData) Validate() error {
 	if r.LastName == "" {
 		return errors.New("не указана фамилия")
 	}
-	if !slices.Contains(models.AllAvailableRoles, models.UserRole(r.Role)) {
+	if r.SpaceRoleID == "" && !slices.Contains(models.AllAvailableRoles, models.UserRole(r.Role)) {
 		return errors.New("указаная роль отсутсвует")
 	}
+	if r.DataScope != "" && !slices.Contains(models.AllDataScopes, r.DataScope) {
+		return errors.New("указаная область видимости отсутсвует")
+	}
 	return nil
 }
 
@@ -137,9 +151,16 @@ type SpaceUserProfileView struct {
 	ID              string          `json:"id"`   // Идентфикатор пользователя
 	Role            models.UserRole `json:"role"` // Роль
 	RoleName        string          `json:"role_name"`
+	SpaceRoleID     *string         `json:"space_role_id"`     // Роль спейса
 	IsEmailVerified bool            `json:"is_email_verified"` // Email подтвержден
 	NewEmail        string          `json:"new_email"`         // Новый email, который станет основным после подтверждения
 	JobTitleName    string          `json:"job_title_name"`    // Должность
+	TgLinked        bool            `json:"tg_linked"`         // Аккаунт Telegram привязан для уведомлений
+}
+
+type TelegramLinkView struct {
+	Link     string `json:"link"`      // Ссылка для привязки аккаунта Telegram
+	ExpireAt string `json:"expire_at"` // Срок действия ссылки
 }
 
 type PasswordChange struct {
@@ -0,0 +1,161 @@
+package ssoapimodels
+
+import (
+	"hr-tools-backend/models"
+	dbmodels "hr-tools-backend/models/db"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type SettingsData struct {
+	IsEnabled             bool                   `json:"is_enabled"`              // Вход через IdP включен
+	Protocol              dbmodels.SsoProtocol   `json:"protocol"`                // Протокол: oidc, saml
+	Domains               []string               `json:"domains"`                 // Почтовые домены сотрудников, по ним определяется вход через IdP
+	PasswordLoginDisabled bool                   `json:"password_login_disabled"` // Вход по паролю отключен (кроме администраторов спейса)
+	AutoProvision         bool                   `json:"auto_provision"`          // Создавать пользователя при первом входе
+	DefaultRole           models.UserRole        `json:"default_role"`            // Роль, если группы пользователя не сопоставлены, пусто - вход запрещен
+	GroupRoles            dbmodels.SsoGroupRoles `json:"group_roles"`             // Сопоставление групп IdP и ролей, применяется первое подходящее
+	GroupsClaim           string                 `json:"groups_claim"`            // Claim ID токена или атрибут SAML с группами, пусто - groups
+	OidcIssuer            string                 `json:"oidc_issuer"`             // OIDC: адрес издателя (issuer)
+	OidcClientID          string                 `json:"oidc_client_id"`          // OIDC: идентификатор клиента
+	OidcClientSecret      string                 `json:"oidc_client_secret"`      // OIDC: секрет клиента, при изменении пусто - без изменений
+	OidcScopes            []string               `json:"oidc_scopes"`             // OIDC: запрашиваемые scope, пусто - openid email profile
+	SamlIdpEntityID       string                 `json:"saml_idp_entity_id"`      // SAML: идентификатор IdP, пусто - издатель не проверяется
+	SamlIdpSsoUrl         string                 `json:"saml_idp_sso_url"`        // SAML: адрес входа IdP (HTTP-Redirect)
+	SamlIdpCertificate    string                 `json:"saml_idp_certificate"`    // SAML: сертификат подписи IdP (PEM или base64)
+}
+
+func (r SettingsData) Validate() error {
+	if r.Protocol != "" && !r.Protocol.IsValid() {
+		return errors.New("некорректный протокол")
+	}
+	for _, domain := range r.Domains {
+		if domain == "" || strings.ContainsAny(domain, "@ ") {
+			return errors.Errorf("некорректный почтовый домен: %v", domain)
+		}
+	}
+	if r.DefaultRole != "" && !slices.Contains(models.AllAvailableRoles, r.DefaultRole) {
+		return errors.New("некорректная роль по умолчанию")
+	}
+	for _, groupRole := range r.GroupRoles {
+		if groupRole.Group == "" {
+			return errors.New("не указана группа IdP")
+		}
+		if !slices.Contains(models.AllAvailableRoles, groupRole.Role) {
+			return errors.Errorf("некорректная роль для группы %v", groupRole.Group)
+		}
+	}
+	if r.OidcIssuer != "" && !isValidUrl(r.OidcIssuer) {
+		return errors.New("некорректный адрес издателя OIDC")
+	}
+	if r.SamlIdpSsoUrl != "" && !isValidUrl(r.SamlIdpSsoUrl) {
+		return errors.New("некорректный адрес входа IdP")
+	}
+	if !r.IsEnabled {
+		return nil
+	}
+	switch r.Protocol {
+	case dbmodels.SsoProtocolOidc:
+		if r.OidcIssuer == "" || r.OidcClientID == "" {
+			return errors.New("не указаны издатель или идентификатор клиента OIDC")
+		}
+	case dbmodels.SsoProtocolSaml:
+		if r.SamlIdpSsoUrl == "" || r.SamlIdpCertificate == "" {
+			return errors.New("не указаны адрес входа или сертификат IdP")
+		}
+	default:
+		return errors.New("не указан протокол")
+	}
+	return nil
+}
+
+// GetDomains домены в нижнем регистре
+func (r SettingsData) GetDomains() []string {
+	result := make([]string, 0, len(r.Domains))
+	for _, domain := range r.Domains {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if !slices.Contains(result, domain) {
+			result = append(result, domain)
+		}
+	}
+	return result
+}
+
+func isValidUrl(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+type SettingsView struct {
+	SettingsData
+	HasOidcClientSecret bool   `json:"has_oidc_client_secret"` // Секрет клиента OIDC сохранен
+	OidcRedirectUri     string `json:"oidc_redirect_uri"`      // OIDC: адрес возврата для регистрации клиента в IdP
+	SamlEntityID        string `json:"saml_entity_id"`         // SAML: идентификатор SP (адрес метаданных)
+	SamlAcsUrl          string `json:"saml_acs_url"`           // SAML: адрес приема ответа IdP
+}
+
+func SettingsConvert(rec dbmodels.SpaceSso) SettingsView {
+	result := SettingsView{
+		SettingsData: SettingsData{
+			IsEnabled:             rec.IsEnabled,
+			Protocol:              rec.Protocol,
+			Domains:               rec.Domains,
+			PasswordLoginDisabled: rec.PasswordLoginDisabled,
+			AutoProvision:         rec.AutoProvision,
+			DefaultRole:           rec.DefaultRole,
+			GroupRoles:            rec.GroupRoles,
+			GroupsClaim:           rec.GroupsClaim,
+			OidcIssuer:            rec.OidcIssuer,
+			OidcClientID:          rec.OidcClientID,
+			OidcScopes:            rec.OidcScopes,
+			SamlIdpEntityID:       rec.SamlIdpEntityID,
+			SamlIdpSsoUrl:         rec.SamlIdpSsoUrl,
+			SamlIdpCertificate:    rec.SamlIdpCertificate,
+		},
+		HasOidcClientSecret: rec.OidcClientSecret != "",
+	}
+	if result.Domains == nil {
+		result.Domains = []string{}
+	}
+	if result.GroupRoles == nil {
+		result.GroupRoles = dbmodels.SsoGroupRoles{}
+	}
+	if result.OidcScopes == nil {
+		result.OidcScopes = []string{}
+	}
+	return result
+}
+
+type DiscoverRequest struct {
+	Email string `json:"email"`
+}
+
+func (r DiscoverRequest) Validate() error {
+	_, err := mail.ParseAddress(r.Email)
+	if err != nil {
+		return errors.New("почта имеет неправильный формат")
+	}
+	return nil
+}
+
+type DiscoverResult struct {
+	SsoEnabled            bool                 `json:"sso_enabled"`             // Для почты настроен вход через IdP
+	Protocol              dbmodels.SsoProtocol `json:"protocol"`                // Протокол
+	LoginUrl              string               `json:"login_url"`               // Адрес начала входа через IdP
+	PasswordLoginDisabled bool                 `json:"password_login_disabled"` // Вход по паролю отключен
+}
+
+type CodeRequest struct {
+	Code string `json:"code"` // Одноразовый код из адреса возврата на страницу входа
+}
+
+func (r CodeRequest) Validate() error {
+	if strings.TrimSpace(r.Code) == "" {
+		return errors.New("не указан код входа")
+	}
+	return nil
+}
@@ -232,6 +232,14 @@ type ApplicantSurvey struct {
 
 type ApplicantSurveyResponses struct {
 	Responses []ApplicantSurveyAnswer `json:"responses"`
+	PdConsent bool                    `json:"pd_consent"` // Согласие на обработку персональных данных
+}
+
+func (a ApplicantSurveyResponses) Validate() error {
+	if !a.PdConsent {
+		return errors.New(models.ConsentRequiredMsg)
+	}
+	return nil
 }
 
 type ApplicantSurveyAnswer struct {
@@ -72,10 +72,14 @@ type VkStep0Question struct {
 }
 
 type VkStep0SurveyAnswers struct {
-	Answers []VkStep0Answer `json:"answers"`
+	Answers   []VkStep0Answer `json:"answers"`
+	PdConsent bool            `json:"pd_consent"` // Согласие на обработку персональных данных
 }
 
 func (v VkStep0SurveyAnswers) Validate() error {
+	if !v.PdConsent {
+		return errors.New(models.ConsentRequiredMsg)
+	}
 	validAnswerCount := 0
 	for _, answer := range v.Answers {
 		switch answer.QuestionID {
@@ -0,0 +1,118 @@
+package telegramapimodels
+
+import (
+	"strings"
+)
+
+// Response ответ Telegram Bot API
+type Response struct {
+	Ok          bool   `json:"ok"`
+	Description string `json:"description"`
+	ErrorCode   int    `json:"error_code"`
+}
+
+type UpdatesResponse struct {
+	Response
+	Result []Update `json:"result"`
+}
+
+type MessageResponse struct {
+	Response
+	Result Message `json:"result"`
+}
+
+// Update входящее обновление бота (webhook / getUpdates)
+type Update struct {
+	UpdateID      int64          `json:"update_id"`
+	Message       *Message       `json:"message,omitempty"`
+	CallbackQuery *CallbackQuery `json:"callback_query,omitempty"`
+}
+
+type Message struct {
+	MessageID int64  `json:"message_id"`
+	From      *User  `json:"from,omitempty"`
+	Chat      Chat   `json:"chat"`
+	Text      string `json:"text"`
+}
+
+type User struct {
+	ID        int64  `json:"id"`
+	FirstName string `json:"first_name"`
+	Username  string `json:"username"`
+}
+
+type Chat struct {
+	ID   int64  `json:"id"`
+	Type string `json:"type"`
+}
+
+type CallbackQuery struct {
+	ID      string   `json:"id"`
+	From    User     `json:"from"`
+	Message *Message `json:"message,omitempty"`
+	Data    string   `json:"data"`
+}
+
+type InlineKeyboardMarkup struct {
+	InlineKeyboard [][]InlineKeyboardButton `json:"inline_keyboard"`
+}
+
+type InlineKeyboardButton struct {
+	Text         string `json:"text"`
+	URL          string `json:"url,omitempty"`
+	CallbackData string `json:"callback_data,omitempty"`
+}
+
+type SendMessageRequest struct {
+	ChatID      int64                 `json:"chat_id"`
+	Text        string                `json:"text"`
+	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+}
+
+type EditMessageReplyMarkupRequest struct {
+	ChatID      int64                 `json:"chat_id"`
+	MessageID   int64                 `json:"message_id"`
+	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+}
+
+type AnswerCallbackQueryRequest struct {
+	CallbackQueryID string `json:"callback_query_id"`
+	Text            string `json:"text,omitempty"`
+}
+
+type GetUpdatesRequest struct {
+	Offset         int64    `json:"offset"`
+	Timeout        int      `json:"timeout"`
+	AllowedUpdates []string `json:"allowed_updates,omitempty"`
+}
+
+type SetWebhookRequest struct {
+	URL            string   `json:"url"`
+	SecretToken    string   `json:"secret_token,omitempty"`
+	AllowedUpdates []string `json:"allowed_updates,omitempty"`
+}
+
+type CallbackAction string
+
+const (
+	CallbackVRApprove CallbackAction = "vra" // согласовать заявку
+	CallbackVRReject  CallbackAction = "vrr" // отклонить заявку
+)
+
+// MakeCallbackData данные кнопки, ограничение Telegram - 64 байта
+func MakeCallbackData(action CallbackAction, id string) string {
+	return string(action) + ":" + id
+}
+
+func ParseCallbackData(data string) (action CallbackAction, id string, ok bool) {
+	actionStr, id, found := strings.Cut(data, ":")
+	if !found || id == "" {
+		return "", "", false
+	}
+	action = CallbackAction(actionStr)
+	switch action {
+	case CallbackVRApprove, CallbackVRReject:
+		return action, id, true
+	}
+	return "", "", false
+}
@@ -0,0 +1,145 @@
+package vacancyapimodels
+
+import (
+	"fmt"
+	dbmodels "hr-tools-backend/models/db"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type ApprovalRouteData struct {
+	Name            string                       `json:"name"`              // Название маршрута
+	CompanyID       string                       `json:"company_id"`        // Компания, пусто - любая
+	DepartmentID    string                       `json:"department_id"`     // Подразделение, пусто - любое
+	CompanyStructID string                       `json:"company_struct_id"` // Структура компании, пусто - любая
+	IsActive        bool                         `json:"is_active"`         // Маршрут используется при подборе для заявок
+	Steps           []dbmodels.ApprovalRouteStep `json:"steps"`             // Шаги согласования, согласуются последовательно
+}
+
+func (r ApprovalRouteData) Validate() error {
+	if r.Name == "" {
+		return errors.New("не указано название маршрута")
+	}
+	if len(r.Steps) == 0 {
+		return errors.New("не указаны шаги маршрута")
+	}
+	users := map[string]bool{}
+	for idx, step := range r.Steps {
+		stepName := step.Name
+		if stepName == "" {
+			stepName = fmt.Sprintf("%v", idx+1)
+		}
+		if len(step.Approvers) == 0 {
+			return errors.Errorf("не указаны согласующие шага %v", stepName)
+		}
+		if step.Quorum < 0 || step.Quorum > len(step.Approvers) {
+			return errors.Errorf("кворум шага %v должен быть от 0 до %v", stepName, len(step.Approvers))
+		}
+		if step.MinSalary < 0 || step.SlaHours < 0 {
+			return errors.Errorf("некорректные условия шага %v", stepName)
+		}
+		if step.RequestType != "" {
+			if err := step.RequestType.Validate(); err != nil {
+				return err
+			}
+		}
+		for _, userID := range step.Approvers {
+			if userID == "" {
+				return errors.Errorf("не указан согласующий шага %v", stepName)
+			}
+			if users[userID] {
+				return errors.New("сотрудник не может быть указан в маршруте несколько раз")
+			}
+			users[userID] = true
+		}
+	}
+	return nil
+}
+
+type ApprovalRouteView struct {
+	ApprovalRouteData
+	ID                string    `json:"id"`
+	CompanyName       string    `json:"company_name"`
+	DepartmentName    string    `json:"department_name"`
+	CompanyStructName string    `json:"company_struct_name"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+func ApprovalRouteConvert(rec dbmodels.ApprovalRoute) ApprovalRouteView {
+	result := ApprovalRouteView{
+		ApprovalRouteData: ApprovalRouteData{
+			Name:     rec.Name,
+			IsActive: rec.IsActive,
+			Steps:    rec.Steps,
+		},
+		ID:        rec.ID,
+		CreatedAt: rec.CreatedAt,
+	}
+	if result.Steps == nil {
+		result.Steps = []dbmodels.ApprovalRouteStep{}
+	}
+	if rec.CompanyID != nil {
+		result.CompanyID = *rec.CompanyID
+	}
+	if rec.Company != nil {
+		result.CompanyName = rec.Company.Name
+	}
+	if rec.DepartmentID != nil {
+		result.DepartmentID = *rec.DepartmentID
+	}
+	if rec.Department != nil {
+		result.DepartmentName = rec.Department.Name
+	}
+	if rec.CompanyStructID != nil {
+		result.CompanyStructID = *rec.CompanyStructID
+	}
+	if rec.CompanyStruct != nil {
+		result.CompanyStructName = rec.CompanyStruct.Name
+	}
+	return result
+}
+
+type ApplyRouteRequest struct {
+	RouteID string `json:"route_id"` // Маршрут согласования, пусто - подобрать по заявке
+}
+
+type ApprovalDelegateData struct {
+	UserID         string `json:"user_id"`          // Согласующий
+	DelegateUserID string `json:"delegate_user_id"` // Заместитель на время отсутствия
+}
+
+func (r ApprovalDelegateData) Validate() error {
+	if r.UserID == "" {
+		return errors.New("не указан согласующий")
+	}
+	if r.DelegateUserID == "" {
+		return errors.New("не указан заместитель")
+	}
+	if r.UserID == r.DelegateUserID {
+		return errors.New("сотрудник не может быть заместителем самого себя")
+	}
+	return nil
+}
+
+type ApprovalDelegateView struct {
+	ApprovalDelegateData
+	UserName         string `json:"user_name"`
+	DelegateUserName string `json:"delegate_user_name"`
+}
+
+func ApprovalDelegateConvert(rec dbmodels.ApprovalDelegate) ApprovalDelegateView {
+	result := ApprovalDelegateView{
+		ApprovalDelegateData: ApprovalDelegateData{
+			UserID:         rec.UserID,
+			DelegateUserID: rec.DelegateUserID,
+		},
+	}
+	if rec.User != nil {
+		result.UserName = rec.User.GetFullName()
+	}
+	if rec.DelegateUser != nil {
+		result.DelegateUserName = rec.DelegateUser.GetFullName()
+	}
+	return result
+}
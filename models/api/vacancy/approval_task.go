@@ -26,12 +26,16 @@ func (v ApprovalTasks) Validate() error {
 
 type ApprovalTaskData struct {
 	AssigneeUserID string `json:"assignee_user_id"`
+	Step           int    `json:"step"` // Номер шага, шаги согласуются последовательно, согласующие одного шага - параллельно
 }
 
 func (a ApprovalTaskData) Validate() error {
 	if a.AssigneeUserID == "" {
 		return errors.New("отсутсвует идентификатор пользователя")
 	}
+	if a.Step < 0 {
+		return errors.New("некорректный номер шага согласования")
+	}
 	return nil
 }
 
@@ -59,11 +63,15 @@ func (v ApprovalReject) Validate() error {
 
 type ApprovalTaskView struct {
 	ApprovalTaskData
-	ID               string               `json:"id"`
-	AssigneeUserName string               `json:"assignee_user_name"`
-	State            models.ApprovalState `json:"state"`
-	Comment          string               `json:"comment"`
-	DecidedAt        *time.Time           `json:"decided_at"`
+	ID                  string               `json:"id"`
+	AssigneeUserName    string               `json:"assignee_user_name"`
+	State               models.ApprovalState `json:"state"`
+	Comment             string               `json:"comment"`
+	DecidedAt           *time.Time           `json:"decided_at"`
+	StepName            string               `json:"step_name"`              // Название шага маршрута
+	Quorum              int                  `json:"quorum"`                 // Необходимое количество согласований шага, 0 - все
+	DueAt               *time.Time           `json:"due_at"`                 // Срок согласования
+	DelegatedFromUserID string               `json:"delegated_from_user_id"` // Исходный согласующий, если задача передана заместителю или эскалирована
 }
 
 func ApprovalStageConvert(rec dbmodels.ApprovalTask) ApprovalTaskView {
@@ -74,12 +82,17 @@ func ApprovalStageConvert(rec dbmodels.ApprovalTask) ApprovalTaskView {
 	return ApprovalTaskView{
 		ApprovalTaskData: ApprovalTaskData{
 			AssigneeUserID: rec.AssigneeUserID,
+			Step:           rec.Step,
 		},
-		ID:               rec.ID,
-		AssigneeUserName: userName,
-		State:            rec.State,
-		Comment:          rec.Comment,
-		DecidedAt:        rec.DecidedAt,
+		ID:                  rec.ID,
+		AssigneeUserName:    userName,
+		State:               rec.State,
+		Comment:             rec.Comment,
+		DecidedAt:           rec.DecidedAt,
+		StepName:            rec.StepName,
+		Quorum:              rec.Quorum,
+		DueAt:               rec.DueAt,
+		DelegatedFromUserID: rec.DelegatedFromUserID,
 	}
 }
 
@@ -10,25 +10,26 @@ import (
 )
 
 type VacancyData struct {
-	VacancyRequestID string                 `json:"vacancy_request_id"` // ид заявки на вакансию
-	CompanyID        string                 `json:"company_id"`         // ид компании
-	CompanyName      string                 `json:"company_name"`       // название компании
-	DepartmentID     string                 `json:"department_id"`      // ид подразделения
-	JobTitleID       string                 `json:"job_title_id"`       // ид штатной должности
-	CityID           string                 `json:"city_id"`            // ид города
-	CompanyStructID  string                 `json:"company_struct_id"`  // ид структуры компании
-	VacancyName      string                 `json:"vacancy_name"`       // название вакансии
-	OpenedPositions  int                    `json:"opened_positions"`   // кол-во открытых позиций
-	Urgency          models.VRUrgency       `json:"urgency"`            // срочность
-	RequestType      models.VRType          `json:"request_type"`       // тип вакансии
-	SelectionType    models.VRSelectionType `json:"selection_type"`     // вид подбора
-	PlaceOfWork      string                 `json:"place_of_work"`      // адрес места работы
-	ChiefFio         string                 `json:"chief_fio"`          // фио непосредственного руководителя
-	Requirements     string                 `json:"requirements"`       // требования/обязанности/условия
-	Salary           Salary                 `json:"salary"`             // ожидания по зп
-	Employment       models.Employment      `json:"employment"`         // Занятость
-	Experience       models.Experience      `json:"experience"`         // Опыт работы
-	Schedule         models.Schedule        `json:"schedule"`           // Режим работы
+	VacancyRequestID string                     `json:"vacancy_request_id"` // ид заявки на вакансию
+	CompanyID        string                     `json:"company_id"`         // ид компании
+	CompanyName      string                     `json:"company_name"`       // название компании
+	DepartmentID     string                     `json:"department_id"`      // ид подразделения
+	JobTitleID       string                     `json:"job_title_id"`       // ид штатной должности
+	CityID           string                     `json:"city_id"`            // ид города
+	CompanyStructID  string                     `json:"company_struct_id"`  // ид структуры компании
+	VacancyName      string                     `json:"vacancy_name"`       // название вакансии
+	OpenedPositions  int                        `json:"opened_positions"`   // кол-во открытых позиций
+	Urgency          models.VRUrgency           `json:"urgency"`            // срочность
+	RequestType      models.VRType              `json:"request_type"`       // тип вакансии
+	SelectionType    models.VRSelectionType     `json:"selection_type"`     // вид подбора
+	PlaceOfWork      string                     `json:"place_of_work"`      // адрес места работы
+	ChiefFio         string                     `json:"chief_fio"`          // фио непосредственного руководителя
+	Requirements     string                     `json:"requirements"`       // требования/обязанности/условия
+	Salary           Salary                     `json:"salary"`             // ожидания по зп
+	Employment       models.Employment          `json:"employment"`         // Занятость
+	Experience       models.Experience          `json:"experience"`         // Опыт работы
+	Schedule         models.Schedule            `json:"schedule"`           // Режим работы
+	CustomFields     dbmodels.CustomFieldValues `json:"custom_fields"`      // Значения пользовательских полей: код поля - значение
 }
 
 func (v VacancyData) Validate(isFromRequest bool) error {
@@ -139,9 +140,10 @@ func VacancyConvert(rec dbmodels.VacancyExt) VacancyView {
 				ByResult: rec.ByResult,
 				InHand:   rec.InHand,
 			},
-			Employment: rec.Employment,
-			Experience: rec.Experience,
-			Schedule:   rec.Schedule,
+			Employment:   rec.Employment,
+			Experience:   rec.Experience,
+			Schedule:     rec.Schedule,
+			CustomFields: rec.CustomFields,
 		},
 		ID:           rec.ID,
 		CreationDate: rec.CreatedAt,
@@ -236,21 +238,22 @@ type VacancySort struct {
 
 type VacancyFilter struct {
 	apimodels.Pagination
-	VacancyRequestID  string                 `json:"request_id"`         // Идентификатор запроса на вакансию
-	Favorite          bool                   `json:"favorite"`           // Отображать избранные
-	Search            string                 `json:"search"`             // Поиск
-	Statuses          []models.VacancyStatus `json:"statuses"`           // Фильтр по статусам
-	CityID            string                 `json:"city_id"`            // Фильтр по идентификатору города
-	DepartmentID      string                 `json:"department_id"`      // Фильтр по идентификатору подразделения
-	SelectionType     models.VRSelectionType `json:"selection_type"`     // Фильтр по виду подбора
-	RequestType       models.VRType          `json:"request_type"`       // Фильтр по тип вакансии
-	Urgency           models.VRUrgency       `json:"urgency"`            // Фильтр по срочности
-	AuthorID          string                 `json:"author_id"`          // Фильтр по автору вакансии
-	RequestAuthorID   string                 `json:"request_author_id"`  // Фильтр по автору запроса на вкансию
-	Sort              VacancySort            `json:"sort"`               // Сортировка
-	Tab               VacancyTab             `json:"tab"`                // Фильтр по вкладке: 0 - Все, 1 - Мои, 2 - Другие, 3 - Архив
-	AuthorSearch      string                 `json:"author_search"`      // Поиск по ФИО автора
-	ResponsibleSearch string                 `json:"responsible_search"` // Поиск по ФИО ответственного
+	VacancyRequestID  string                      `json:"request_id"`         // Идентификатор запроса на вакансию
+	Favorite          bool                        `json:"favorite"`           // Отображать избранные
+	Search            string                      `json:"search"`             // Поиск
+	Statuses          []models.VacancyStatus      `json:"statuses"`           // Фильтр по статусам
+	CityID            string                      `json:"city_id"`            // Фильтр по идентификатору города
+	DepartmentID      string                      `json:"department_id"`      // Фильтр по идентификатору подразделения
+	SelectionType     models.VRSelectionType      `json:"selection_type"`     // Фильтр по виду подбора
+	RequestType       models.VRType               `json:"request_type"`       // Фильтр по тип вакансии
+	Urgency           models.VRUrgency            `json:"urgency"`            // Фильтр по срочности
+	AuthorID          string                      `json:"author_id"`          // Фильтр по автору вакансии
+	RequestAuthorID   string                      `json:"request_author_id"`  // Фильтр по автору запроса на вкансию
+	Sort              VacancySort                 `json:"sort"`               // Сортировка
+	Tab               VacancyTab                  `json:"tab"`                // Фильтр по вкладке: 0 - Все, 1 - Мои, 2 - Другие, 3 - Архив
+	AuthorSearch      string                      `json:"author_search"`      // Поиск по ФИО автора
+	ResponsibleSearch string                      `json:"responsible_search"` // Поиск по ФИО ответственного
+	CustomFields      dbmodels.CustomFieldFilters `json:"custom_fields"`      // Фильтры по пользовательским полям
 }
 
 type VacancyTab int
@@ -10,29 +10,31 @@ import (
 )
 
 type VacancyRequestData struct {
-	CompanyID       string                 `json:"company_id"`        // ид компании
-	CompanyName     string                 `json:"company_name"`      // название компании
-	DepartmentID    string                 `json:"department_id"`     // ид подразделения
-	JobTitleID      string                 `json:"job_title_id"`      // ид штатной должности
-	CityID          string                 `json:"city_id"`           // ид города
-	CompanyStructID string                 `json:"company_struct_id"` // ид структуры компании
-	VacancyName     string                 `json:"vacancy_name"`      // название вакансии
-	Confidential    bool                   `json:"confidential"`      // конфиденциальная вакансия
-	OpenedPositions int                    `json:"opened_positions"`  // кол-во открытых позиций
-	Urgency         models.VRUrgency       `json:"urgency"`           // срочность
-	RequestType     models.VRType          `json:"request_type"`      // тип вакансии
-	SelectionType   models.VRSelectionType `json:"selection_type"`    // вид подбора
-	PlaceOfWork     string                 `json:"place_of_work"`     // адрес места работы
-	ChiefFio        string                 `json:"chief_fio"`         // фио непосредственного руководителя
-	Requirements    string                 `json:"requirements"`      // требования/обязанности/условия
-	Interviewer     string                 `json:"interviewer"`       // сотрудник проводящий интервью
-	ShortInfo       string                 `json:"short_info"`        // краткая информация о комманде отдела
-	Description     string                 `json:"description"`       // Коментарий к заявке
-	OutInteraction  string                 `json:"out_interaction"`   // внешнее взаимодействие
-	InInteraction   string                 `json:"in_interaction"`    // внутреннее взаимодействие
-	Employment      models.Employment      `json:"employment"`        // Занятость
-	Experience      models.Experience      `json:"experience"`        // Опыт работы
-	Schedule        models.Schedule        `json:"schedule"`          // Режим работы
+	CompanyID       string                     `json:"company_id"`        // ид компании
+	CompanyName     string                     `json:"company_name"`      // название компании
+	DepartmentID    string                     `json:"department_id"`     // ид подразделения
+	JobTitleID      string                     `json:"job_title_id"`      // ид штатной должности
+	CityID          string                     `json:"city_id"`           // ид города
+	CompanyStructID string                     `json:"company_struct_id"` // ид структуры компании
+	VacancyName     string                     `json:"vacancy_name"`      // название вакансии
+	Confidential    bool                       `json:"confidential"`      // конфиденциальная вакансия
+	OpenedPositions int                        `json:"opened_positions"`  // кол-во открытых позиций
+	Urgency         models.VRUrgency           `json:"urgency"`           // срочность
+	RequestType     models.VRType              `json:"request_type"`      // тип вакансии
+	SelectionType   models.VRSelectionType     `json:"selection_type"`    // вид подбора
+	PlaceOfWork     string                     `json:"place_of_work"`     // адрес места работы
+	ChiefFio        string                     `json:"chief_fio"`         // фио непосредственного руководителя
+	Requirements    string                     `json:"requirements"`      // требования/обязанности/условия
+	Interviewer     string                     `json:"interviewer"`       // сотрудник проводящий интервью
+	ShortInfo       string                     `json:"short_info"`        // краткая информация о комманде отдела
+	Description     string                     `json:"description"`       // Коментарий к заявке
+	OutInteraction  string                     `json:"out_interaction"`   // внешнее взаимодействие
+	InInteraction   string                     `json:"in_interaction"`    // внутреннее взаимодействие
+	Employment      models.Employment          `json:"employment"`        // Занятость
+	Experience      models.Experience          `json:"experience"`        // Опыт работы
+	Schedule        models.Schedule            `json:"schedule"`          // Режим работы
+	Salary          int                        `json:"salary"`            // Заработная плата по позиции (верхняя граница), используется в условиях маршрута согласования
+	CustomFields    dbmodels.CustomFieldValues `json:"custom_fields"`     // Значения пользовательских полей: код поля - значение
 }
 
 func (v VacancyRequestData) Validate() error {
@@ -119,6 +121,8 @@ func VacancyRequestConvert(rec dbmodels.VacancyRequest) VacancyRequestView {
 			Employment:      rec.Employment,
 			Experience:      rec.Experience,
 			Schedule:        rec.Schedule,
+			Salary:          rec.Salary,
+			CustomFields:    rec.CustomFields,
 		},
 		ID:           rec.ID,
 		CreationDate: rec.CreatedAt,
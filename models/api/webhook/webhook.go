@@ -0,0 +1,138 @@
+package webhookapimodels
+
+import (
+	apimodels "hr-tools-backend/models/api"
+	dbmodels "hr-tools-backend/models/db"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const minSecretLen = 16
+
+type SubscriptionData struct {
+	Name     string                  `json:"name"`      // Название подписки (внешней системы)
+	Url      string                  `json:"url"`       // Адрес для отправки событий (http/https), адреса внутренней сети не допускаются
+	Secret   string                  `json:"secret"`    // Ключ подписи HMAC-SHA256, при изменении пусто - без изменений
+	Events   []dbmodels.WebhookEvent `json:"events"`    // События: applicant.created, applicant.stage_changed, applicant.rejected, vacancy.status_changed, vacancy_request.approved, vk.report_ready, offer.accepted
+	IsActive bool                    `json:"is_active"` // Подписка активна
+}
+
+func (r SubscriptionData) Validate(isNew bool) error {
+	if r.Name == "" {
+		return errors.New("не указано название подписки")
+	}
+	u, err := url.Parse(r.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("некорректный адрес подписки")
+	}
+	if isNew && r.Secret == "" {
+		return errors.New("не указан ключ подписи")
+	}
+	if r.Secret != "" && len(r.Secret) < minSecretLen {
+		return errors.Errorf("ключ подписи должен быть не короче %v символов", minSecretLen)
+	}
+	if len(r.Events) == 0 {
+		return errors.New("не указаны события подписки")
+	}
+	for _, event := range r.Events {
+		if !event.IsValid() {
+			return errors.Errorf("некорректное событие: %v", event)
+		}
+	}
+	return nil
+}
+
+type SubscriptionView struct {
+	ID             string                  `json:"id"`
+	Name           string                  `json:"name"`            // Название подписки
+	Url            string                  `json:"url"`             // Адрес для отправки событий
+	Events         []dbmodels.WebhookEvent `json:"events"`          // События
+	IsActive       bool                    `json:"is_active"`       // Подписка активна
+	FailureCount   int                     `json:"failure_count"`   // Кол-во неудачных доставок подряд
+	DisabledAt     *time.Time              `json:"disabled_at"`     // Дата автоматического отключения
+	DisabledReason string                  `json:"disabled_reason"` // Причина автоматического отключения
+	CreatedAt      time.Time               `json:"created_at"`      // Дата создания
+}
+
+func SubscriptionConvert(rec dbmodels.WebhookSubscription) SubscriptionView {
+	result := SubscriptionView{
+		ID:             rec.ID,
+		Name:           rec.Name,
+		Url:            rec.Url,
+		Events:         make([]dbmodels.WebhookEvent, 0, len(rec.Events)),
+		IsActive:       rec.IsActive,
+		FailureCount:   rec.FailureCount,
+		DisabledAt:     rec.DisabledAt,
+		DisabledReason: rec.DisabledReason,
+		CreatedAt:      rec.CreatedAt,
+	}
+	for _, event := range rec.Events {
+		result.Events = append(result.Events, dbmodels.WebhookEvent(event))
+	}
+	return result
+}
+
+type DeliveryFilter struct {
+	apimodels.Pagination
+	SubscriptionID string                         `json:"subscription_id"` // Идентификатор подписки
+	Event          dbmodels.WebhookEvent          `json:"event"`           // Событие
+	Status         dbmodels.WebhookDeliveryStatus `json:"status"`          // Статус доставки (pending/success/failed)
+}
+
+type DeliveryView struct {
+	ID               string                         `json:"id"`
+	SubscriptionID   string                         `json:"subscription_id"`   // Идентификатор подписки
+	SubscriptionName string                         `json:"subscription_name"` // Название подписки
+	Event            dbmodels.WebhookEvent          `json:"event"`             // Событие
+	Payload          string                         `json:"payload"`           // Тело запроса
+	Status           dbmodels.WebhookDeliveryStatus `json:"status"`            // Статус доставки (pending/success/failed)
+	Attempts         int                            `json:"attempts"`          // Кол-во попыток
+	NextAttemptAt    *time.Time                     `json:"next_attempt_at"`   // Дата следующей попытки
+	LastAttemptAt    *time.Time                     `json:"last_attempt_at"`   // Дата последней попытки
+	ResponseCode     int                            `json:"response_code"`     // Код ответа
+	ResponseBody     string                         `json:"response_body"`     // Ответ (начало)
+	Error            string                         `json:"error"`             // Ошибка доставки
+	CreatedAt        time.Time                      `json:"created_at"`        // Дата события
+}
+
+func DeliveryConvert(rec dbmodels.WebhookDelivery) DeliveryView {
+	result := DeliveryView{
+		ID:             rec.ID,
+		SubscriptionID: rec.SubscriptionID,
+		Event:          rec.Event,
+		Payload:        rec.Payload,
+		Status:         rec.Status,
+		Attempts:       rec.Attempts,
+		NextAttemptAt:  rec.NextAttemptAt,
+		LastAttemptAt:  rec.LastAttemptAt,
+		ResponseCode:   rec.ResponseCode,
+		ResponseBody:   rec.ResponseBody,
+		Error:          rec.Error,
+		CreatedAt:      rec.CreatedAt,
+	}
+	if rec.Subscription != nil {
+		result.SubscriptionName = rec.Subscription.Name
+	}
+	return result
+}
+
+// Payload тело запроса, отправляемого по подписке
+type Payload struct {
+	ID         string                `json:"id"`          // Идентификатор доставки, повторная отправка выполняется с тем же идентификатором
+	Event      dbmodels.WebhookEvent `json:"event"`       // Событие
+	SpaceID    string                `json:"space_id"`    // Идентификатор спейса
+	OccurredAt time.Time             `json:"occurred_at"` // Время события
+	Data       EventData             `json:"data"`        // Данные события
+}
+
+type EventData struct {
+	ApplicantID      string `json:"applicant_id,omitempty"`       // Идентификатор кандидата
+	VacancyID        string `json:"vacancy_id,omitempty"`         // Идентификатор вакансии
+	VacancyRequestID string `json:"vacancy_request_id,omitempty"` // Идентификатор заявки
+	OfferID          string `json:"offer_id,omitempty"`           // Идентификатор оффера
+	StageName        string `json:"stage_name,omitempty"`         // Этап подбора
+	Status           string `json:"status,omitempty"`             // Новый статус
+	Reason           string `json:"reason,omitempty"`             // Причина отказа
+}
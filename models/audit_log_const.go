@@ -0,0 +1,62 @@
+package models
+
+// AuditActorType инициатор действия в журнале аудита
+type AuditActorType string
+
+const (
+	AuditActorUser   AuditActorType = "USER"    // сотрудник
+	AuditActorApiKey AuditActorType = "API_KEY" // запрос по API ключу (служебная учетная запись ключа)
+	AuditActorSystem AuditActorType = "SYSTEM"  // фоновая задача
+)
+
+func (r AuditActorType) ToHuman() string {
+	switch r {
+	case AuditActorUser:
+		return "Сотрудник"
+	case AuditActorApiKey:
+		return "API ключ"
+	case AuditActorSystem:
+		return "Система"
+	}
+	return string(r)
+}
+
+// AuditAction действие в журнале аудита
+type AuditAction string
+
+const (
+	AuditActionRequest AuditAction = "request" // изменяющий запрос к API
+	AuditActionCreate  AuditAction = "create"
+	AuditActionUpdate  AuditAction = "update"
+	AuditActionDelete  AuditAction = "delete"
+)
+
+func (r AuditAction) ToHuman() string {
+	switch r {
+	case AuditActionRequest:
+		return "Запрос"
+	case AuditActionCreate:
+		return "Создание"
+	case AuditActionUpdate:
+		return "Изменение"
+	case AuditActionDelete:
+		return "Удаление"
+	}
+	return string(r)
+}
+
+// AuditEntity тип сущности в журнале аудита, для записей запросов - раздел API
+type AuditEntity string
+
+const (
+	AuditEntityVacancy     AuditEntity = "vacancy"
+	AuditEntityUser        AuditEntity = "user"
+	AuditEntityRole        AuditEntity = "role"
+	AuditEntitySetting     AuditEntity = "setting"
+	AuditEntityMsgTemplate AuditEntity = "msg_template"
+	AuditEntityWebhook     AuditEntity = "webhook"
+	AuditEntityApiKey      AuditEntity = "api_key"
+	AuditEntitySso         AuditEntity = "sso"
+	AuditEntityLicense     AuditEntity = "license"
+	AuditEntityCustomField AuditEntity = "custom_field"
+)
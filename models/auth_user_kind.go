@@ -0,0 +1,9 @@
+package models
+
+// AuthUserKind тип учетной записи для сессий и двухфакторной аутентификации
+type AuthUserKind string
+
+const (
+	AuthSpaceUser      AuthUserKind = "SPACE"       // сотрудник спейса
+	AuthAdminPanelUser AuthUserKind = "ADMIN_PANEL" // пользователь админки
+)
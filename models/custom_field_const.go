@@ -0,0 +1,82 @@
+package models
+
+import "github.com/pkg/errors"
+
+// CustomFieldType тип пользовательского поля
+type CustomFieldType string
+
+const (
+	CustomFieldText      CustomFieldType = "TEXT"       // строка
+	CustomFieldNumber    CustomFieldType = "NUMBER"     // число
+	CustomFieldDate      CustomFieldType = "DATE"       // дата ДД.ММ.ГГГГ
+	CustomFieldEnum      CustomFieldType = "ENUM"       // один вариант из списка
+	CustomFieldMultiEnum CustomFieldType = "MULTI_ENUM" // несколько вариантов из списка
+	CustomFieldBoolean   CustomFieldType = "BOOLEAN"    // да/нет
+	CustomFieldUser      CustomFieldType = "USER"       // ссылка на пользователя спейса
+)
+
+var AllCustomFieldTypes = []CustomFieldType{CustomFieldText, CustomFieldNumber, CustomFieldDate, CustomFieldEnum,
+	CustomFieldMultiEnum, CustomFieldBoolean, CustomFieldUser}
+
+func (t CustomFieldType) ToHuman() string {
+	switch t {
+	case CustomFieldText:
+		return "Текст"
+	case CustomFieldNumber:
+		return "Число"
+	case CustomFieldDate:
+		return "Дата"
+	case CustomFieldEnum:
+		return "Список"
+	case CustomFieldMultiEnum:
+		return "Множественный выбор"
+	case CustomFieldBoolean:
+		return "Да/Нет"
+	case CustomFieldUser:
+		return "Пользователь"
+	}
+	return string(t)
+}
+
+// HasOptions тип с вариантами значений
+func (t CustomFieldType) HasOptions() bool {
+	return t == CustomFieldEnum || t == CustomFieldMultiEnum
+}
+
+func (t CustomFieldType) Validate() error {
+	for _, item := range AllCustomFieldTypes {
+		if item == t {
+			return nil
+		}
+	}
+	return errors.New("некорректный тип поля")
+}
+
+// CustomFieldEntity сущность, для которой определено пользовательское поле
+type CustomFieldEntity string
+
+const (
+	CustomFieldEntityApplicant      CustomFieldEntity = "APPLICANT"       // кандидат
+	CustomFieldEntityVacancy        CustomFieldEntity = "VACANCY"         // вакансия
+	CustomFieldEntityVacancyRequest CustomFieldEntity = "VACANCY_REQUEST" // заявка на вакансию
+)
+
+func (e CustomFieldEntity) ToHuman() string {
+	switch e {
+	case CustomFieldEntityApplicant:
+		return "Кандидат"
+	case CustomFieldEntityVacancy:
+		return "Вакансия"
+	case CustomFieldEntityVacancyRequest:
+		return "Заявка"
+	}
+	return string(e)
+}
+
+func (e CustomFieldEntity) Validate() error {
+	switch e {
+	case CustomFieldEntityApplicant, CustomFieldEntityVacancy, CustomFieldEntityVacancyRequest:
+		return nil
+	}
+	return errors.New("некорректная сущность поля")
+}
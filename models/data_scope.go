@@ -0,0 +1,39 @@
+package models
+
+// DataScope область видимости вакансий, заявок и кандидатов пользователя
+type DataScope string
+
+const (
+	DataScopeOwn        DataScope = "OWN"        // свои записи: автор, ответственный, согласующий
+	DataScopeTeam       DataScope = "TEAM"       // свои и вакансии, в команде которых состоит пользователь
+	DataScopeDepartment DataScope = "DEPARTMENT" // команда и записи подразделения пользователя с дочерними подразделениями
+	DataScopeSpace      DataScope = "SPACE"      // весь спейс
+)
+
+var AllDataScopes = []DataScope{DataScopeOwn, DataScopeTeam, DataScopeDepartment, DataScopeSpace}
+
+var dataScopeHumanName = map[DataScope]string{
+	DataScopeOwn:        "Свои",
+	DataScopeTeam:       "Команда вакансии",
+	DataScopeDepartment: "Подразделение",
+	DataScopeSpace:      "Весь спейс",
+}
+
+func (s DataScope) ToHuman() string {
+	if human, exist := dataScopeHumanName[s]; exist {
+		return human
+	}
+	return string(s)
+}
+
+// DefaultDataScope область видимости роли, если у пользователя она не задана
+func (r UserRole) DefaultDataScope() DataScope {
+	switch r {
+	case AdminRole, HRRole:
+		return DataScopeSpace
+	case ManagerRole:
+		return DataScopeDepartment
+	default:
+		return DataScopeTeam
+	}
+}
@@ -16,6 +16,10 @@ type AdminPanelUser struct {
 	Email       string          `gorm:"type:varchar(255)"`
 	PhoneNumber string          `gorm:"type:varchar(15)"`
 	LastLogin   time.Time
+	// неверных паролей подряд
+	FailedLoginAttempts int
+	// вход по паролю заблокирован до
+	LockedUntil *time.Time
 }
 
 func (u AdminPanelUser) Validate() error {
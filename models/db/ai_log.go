@@ -26,4 +26,5 @@ const (
 	AiApplicantSurveyType    AiReqestType = "ApplicantSurvey"
 	AiScoreApplicantType     AiReqestType = "ScoreApplicant"
 	AiVideoAnalyze           AiReqestType = "VideoAnalyze"
+	AiParseResumeType        AiReqestType = "ParseResume"
 )
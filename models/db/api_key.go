@@ -0,0 +1,64 @@
+package dbmodels
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"hr-tools-backend/models"
+	"time"
+)
+
+// ApiKey ключ доступа к API спейса для интеграций, запросы по ключу выполняются от служебной учетной записи
+type ApiKey struct {
+	BaseSpaceModel
+	Name          string          `gorm:"type:varchar(255)"`
+	Prefix        string          `gorm:"type:varchar(20)"`             // начало ключа для отображения
+	KeyHash       string          `gorm:"type:varchar(64);uniqueIndex"` // sha256 ключа, сам ключ не хранится
+	Role          models.UserRole `gorm:"type:varchar(50)"`
+	Scopes        ApiKeyScopes    `gorm:"type:jsonb"`
+	ExpiresAt     *time.Time
+	LastUsedAt    *time.Time
+	LastUsedIP    string `gorm:"type:varchar(50)"`
+	RotatedAt     *time.Time
+	RevokedAt     *time.Time
+	ServiceUserID string     `gorm:"type:varchar(36)"`
+	ServiceUser   *SpaceUser `gorm:"foreignKey:ServiceUserID"`
+	AuthorID      string     `gorm:"type:varchar(36)"`
+	Author        *SpaceUser `gorm:"foreignKey:AuthorID"`
+}
+
+// IsValid ключ не отозван и не истек
+func (k ApiKey) IsValid(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || k.ExpiresAt.After(now)
+}
+
+type ApiKeyScopes []ApiKeyScope
+
+// ApiKeyScope область доступа ключа - пара модуль/разрешение RBAC
+type ApiKeyScope struct {
+	Module     models.Module     `json:"module"`     // Модуль RBAC
+	Permission models.Permission `json:"permission"` // Разрешение RBAC
+}
+
+func (s ApiKeyScopes) Has(module models.Module, permission models.Permission) bool {
+	for _, scope := range s {
+		if scope.Module == module && scope.Permission == permission {
+			return true
+		}
+	}
+	return false
+}
+
+func (s ApiKeyScopes) Value() (driver.Value, error) {
+	valueString, err := json.Marshal(s)
+	return string(valueString), err
+}
+
+func (s *ApiKeyScopes) Scan(value interface{}) error {
+	if err := json.Unmarshal(value.([]byte), &s); err != nil {
+		return err
+	}
+	return nil
+}
@@ -15,6 +15,7 @@ type Applicant struct {
 	BaseSpaceModel
 	VacancyID             string                   `gorm:"type:varchar(36)" comment:"Идентификатор вакансии"`
 	Vacancy               *Vacancy                 `gorm:"foreignKey:VacancyID"`
+	CandidateID           *string                  `gorm:"type:varchar(36);index" comment:"Идентификатор кандидата (человека)"`                        // общий для откликов одного человека на разные вакансии
 	NegotiationID         string                   `gorm:"type:varchar(255);index:idx_negotiation" comment:"Идентификатор отклика во внешней системе"` // ид отклика во внешней системе
 	ChatID                string                   `gorm:"type:varchar(255)" comment:"Идентификатор чата во внешней системе"`                          // ид чата во внешней системе
 	ResumeID              string                   `gorm:"index;type:varchar(255)" comment:"Идентификатор резюме во внешней системе"`                  // ид резюме во внешней системе
@@ -38,6 +39,7 @@ type Applicant struct {
 	TotalExperience       int                      `comment:"Опыт работ в месяцах"`
 	Comment               string                   `comment:"Комментарий"`
 	Params                ApplicantParams          `gorm:"type:jsonb"`
+	CustomFields          CustomFieldValues        `gorm:"type:jsonb" comment:"Дополнительные поля"`
 	PhotoUrl              string                   `gorm:"type:varchar(500)"` //todo s3 photo
 	SelectionStageID      string                   `gorm:"type:varchar(36)" comment:"Идентификатор этапа подбора"`
 	SelectionStage        *SelectionStage          `gorm:"foreignKey:SelectionStageID"`
@@ -49,10 +51,23 @@ type Applicant struct {
 	StartDate             time.Time                `comment:"Дата выхода"`
 	RejectReason          string                   `gorm:"type:varchar(255)" comment:"Причина отказа"`
 	RejectInitiator       models.RejectInitiator   `gorm:"type:varchar(255)" comment:"Инициатор отказа"`
+	StageDeadline         *time.Time               `comment:"Срок нахождения на этапе"`          // рассчитывается по лимиту этапа с учетом нерабочих дней
+	StageOverdueAt        *time.Time               `gorm:"index" comment:"Дата просрочки этапа"` // превышен лимит времени на этапе, ответственный уведомлен
+	StageEscalatedAt      *time.Time               `comment:"Дата эскалации просрочки этапа"`    // просрочка этапа эскалирована автору вакансии
+	AnonymizedAt          *time.Time               `comment:"Дата обезличивания"`                // персональные данные обезличены по сроку хранения или запросу субъекта
 	ApplicantSurvey       *ApplicantSurvey
 	ApplicantVkStep       *ApplicantVkStep
 }
 
+// ApplicantStageLimit кандидат на этапе с лимитом времени
+type ApplicantStageLimit struct {
+	Applicant
+	StageName    string
+	LimitValue   int64
+	LimitType    models.LimitType
+	StageStartAt time.Time // дата перевода на текущий этап
+}
+
 type ApplicantWithJob struct {
 	Applicant
 	JobTitleName string
@@ -154,14 +169,6 @@ func (n NegotiationFilter) Validate() error {
 	return nil
 }
 
-type DuplicateApplicantFilter struct {
-	VacancyID      string
-	FIO            string
-	Phone          string
-	Email          string
-	ExtApplicantID string
-}
-
 func (a Applicant) IsMarkAsNotDuplicate(source Applicant) bool {
 	for _, id := range a.NotDuplicates {
 		if id == source.ID {
@@ -51,5 +51,9 @@ const (
 	HistoryTypeArchive     ActionType = "archive"      // Перемещен в архив
 	HistoryTypeReject      ActionType = "reject"       // Кандидат отклонен
 	HistoryTypeEmail       ActionType = "reject"       // email
+	HistoryTypeEmailIn     ActionType = "email_in"     // Получено письмо от кандидата
 	HistoryAIScore         ActionType = "ai_score"     // Оценка ИИ
+	HistoryTypeInterview   ActionType = "interview"    // Назначено/изменено/отменено интервью
+	HistoryTypeScorecard   ActionType = "scorecard"    // Заполнена оценочная карта интервьюера
+	HistoryTypeOffer       ActionType = "offer"        // Создан/изменен/отправлен оффер, ответ кандидата по офферу
 )
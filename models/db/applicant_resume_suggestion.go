@@ -0,0 +1,59 @@
+package dbmodels
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"hr-tools-backend/models"
+)
+
+type ResumeSuggestionStatus string
+
+const (
+	ResumeSuggestionInProgress ResumeSuggestionStatus = "in_progress" // резюме обрабатывается
+	ResumeSuggestionReady      ResumeSuggestionStatus = "ready"       // данные извлечены, ожидают проверки
+	ResumeSuggestionFailed     ResumeSuggestionStatus = "failed"      // не удалось извлечь данные
+	ResumeSuggestionApplied    ResumeSuggestionStatus = "applied"     // данные перенесены в профиль кандидата
+	ResumeSuggestionDismissed  ResumeSuggestionStatus = "dismissed"   // данные отклонены
+)
+
+// ApplicantResumeSuggestion - данные, извлеченные из загруженного резюме, для проверки перед заполнением профиля кандидата
+type ApplicantResumeSuggestion struct {
+	BaseSpaceModel
+	ApplicantID string                 `gorm:"type:varchar(36);index"`
+	FileName    string                 `gorm:"type:varchar(255)"`
+	Status      ResumeSuggestionStatus `gorm:"type:varchar(50)"`
+	Method      string                 `gorm:"type:varchar(50)"` // способ извлечения: rules, Ollama, YandexGPT
+	Error       string
+	Data        ResumeData `gorm:"type:jsonb"`
+}
+
+// ResumeData - поля профиля кандидата, извлеченные из резюме, пустое значение - поле не найдено
+type ResumeData struct {
+	FirstName          string                     `json:"first_name"`
+	LastName           string                     `json:"last_name"`
+	MiddleName         string                     `json:"middle_name"`
+	Phone              string                     `json:"phone"`
+	Email              string                     `json:"email"`
+	BirthDate          string                     `json:"birth_date"` // ДД.ММ.ГГГГ
+	Address            string                     `json:"address"`
+	Citizenship        string                     `json:"citizenship"`
+	Salary             int                        `json:"salary"`
+	TotalExperience    int                        `json:"total_experience"` // в месяцах
+	Education          models.EducationType       `json:"education"`
+	Languages          []Language                 `json:"languages"`
+	Employments        []models.Employment        `json:"employments"`
+	Schedules          []models.Schedule          `json:"schedules"`
+	DriverLicenseTypes []models.DriverLicenseType `json:"driver_license_types"`
+}
+
+func (j ResumeData) Value() (driver.Value, error) {
+	valueString, err := json.Marshal(j)
+	return string(valueString), err
+}
+
+func (j *ResumeData) Scan(value interface{}) error {
+	if err := json.Unmarshal(value.([]byte), &j); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package dbmodels
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"hr-tools-backend/models"
+)
+
+// ApprovalRoute шаблон маршрута согласования заявок на вакансию.
+// Незаполненные компания/подразделение/структура означают "любые",
+// для заявки выбирается наиболее точно совпадающий активный маршрут
+type ApprovalRoute struct {
+	BaseSpaceModel
+	Name            string             `gorm:"type:varchar(255)"`
+	CompanyID       *string            `gorm:"type:varchar(36)"`
+	Company         *Company           `gorm:"foreignKey:CompanyID"`
+	DepartmentID    *string            `gorm:"type:varchar(36)"`
+	Department      *Department        `gorm:"foreignKey:DepartmentID"`
+	CompanyStructID *string            `gorm:"type:varchar(36)"`
+	CompanyStruct   *CompanyStruct     `gorm:"foreignKey:CompanyStructID"`
+	IsActive        bool               `gorm:"index"`
+	Steps           ApprovalRouteSteps `gorm:"type:jsonb"`
+}
+
+// Match степень совпадения маршрута с заявкой, -1 - маршрут не подходит
+func (r ApprovalRoute) Match(rec VacancyRequest) int {
+	score := 0
+	for _, item := range []struct {
+		routeValue *string
+		recValue   *string
+		weight     int
+	}{
+		{r.CompanyStructID, rec.CompanyStructID, 4},
+		{r.DepartmentID, rec.DepartmentID, 2},
+		{r.CompanyID, rec.CompanyID, 1},
+	} {
+		if item.routeValue == nil || *item.routeValue == "" {
+			continue
+		}
+		if item.recValue == nil || *item.recValue != *item.routeValue {
+			return -1
+		}
+		score += item.weight
+	}
+	return score
+}
+
+func (j ApprovalRouteSteps) Value() (driver.Value, error) {
+	valueString, err := json.Marshal(j)
+	return string(valueString), err
+}
+
+func (j *ApprovalRouteSteps) Scan(value interface{}) error {
+	if err := json.Unmarshal(value.([]byte), &j); err != nil {
+		return err
+	}
+	return nil
+}
+
+type ApprovalRouteSteps []ApprovalRouteStep
+
+type ApprovalRouteStep struct {
+	Name             string        `json:"name"`               // Название шага
+	Approvers        []string      `json:"approvers"`          // Согласующие шага, согласуют параллельно
+	Quorum           int           `json:"quorum"`             // Необходимое количество согласований, 0 - все согласующие
+	MinSalary        int           `json:"min_salary"`         // Условие: шаг включается, если зарплата по заявке больше указанной
+	RequestType      models.VRType `json:"request_type"`       // Условие: шаг включается только для заявок указанного типа
+	SlaHours         int           `json:"sla_hours"`          // Срок согласования шага в часах, 0 - без ограничения
+	EscalationUserID string        `json:"escalation_user_id"` // Кому передается задача при нарушении срока
+}
+
+// IsApplicable шаг включается в маршрут заявки
+func (s ApprovalRouteStep) IsApplicable(rec VacancyRequest) bool {
+	if s.MinSalary > 0 && rec.Salary <= s.MinSalary {
+		return false
+	}
+	if s.RequestType != "" && rec.RequestType != s.RequestType {
+		return false
+	}
+	return true
+}
+
+// ApprovalDelegate заместитель согласующего, получает задачи согласования, пока сотрудник не работает
+type ApprovalDelegate struct {
+	BaseSpaceModel
+	UserID         string     `gorm:"type:varchar(36);uniqueIndex"`
+	User           *SpaceUser `gorm:"foreignKey:UserID"`
+	DelegateUserID string     `gorm:"type:varchar(36)"`
+	DelegateUser   *SpaceUser `gorm:"foreignKey:DelegateUserID"`
+}
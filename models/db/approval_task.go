@@ -13,6 +13,23 @@ type ApprovalTask struct {
 	State          models.ApprovalState
 	Comment        string
 	DecidedAt      *time.Time
+	// шаги маршрута согласуются последовательно, согласующие одного шага - параллельно
+	Step                int    // номер шага маршрута
+	StepName            string `gorm:"type:varchar(255)"`
+	Quorum              int    // необходимое количество согласований шага, 0 - все согласующие шага
+	SlaHours            int    // срок согласования в часах, 0 - без ограничения
+	DueAt               *time.Time
+	EscalationUserID    string `gorm:"type:varchar(36)"` // кому передается задача при нарушении срока
+	EscalatedAt         *time.Time
+	DelegatedFromUserID string `gorm:"type:varchar(36)"` // исходный согласующий, если задача передана заместителю или эскалирована
+}
+
+// IsQuorumReached кворум шага набран
+func IsQuorumReached(quorum, approved, total int) bool {
+	if quorum <= 0 || quorum > total {
+		return approved >= total
+	}
+	return approved >= quorum
 }
 
 type ApprovalHistory struct {
@@ -0,0 +1,20 @@
+package dbmodels
+
+import "hr-tools-backend/models"
+
+// AuditLog журнал аудита действий пользователей и системы в спейсе
+type AuditLog struct {
+	BaseSpaceModel
+	ActorType  models.AuditActorType `gorm:"type:varchar(50);index"`
+	ActorID    string                `gorm:"type:varchar(36);index"` // пользователь или служебная учетная запись API ключа, пусто - система
+	ActorName  string                `gorm:"type:varchar(255)"`      // имя на момент действия
+	Action     models.AuditAction    `gorm:"type:varchar(50);index"`
+	EntityType models.AuditEntity    `gorm:"type:varchar(50);index"`
+	EntityID   string                `gorm:"type:varchar(36);index"`
+	Method     string                `gorm:"type:varchar(10)"`
+	Path       string                `gorm:"type:varchar(500)"`
+	Status     int
+	Changes    EntityChanges `gorm:"type:jsonb"`
+	IP         string        `gorm:"type:varchar(50)"`
+	UserAgent  string        `gorm:"type:varchar(500)"`
+}
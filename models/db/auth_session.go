@@ -0,0 +1,41 @@
+package dbmodels
+
+import (
+	"hr-tools-backend/models"
+	authapimodels "hr-tools-backend/models/api/auth"
+	"time"
+)
+
+// AuthSession сессия входа пользователя, refresh токен и выданные по нему JWT действуют, пока сессия не отозвана
+type AuthSession struct {
+	BaseModel
+	UserKind      models.AuthUserKind `gorm:"type:varchar(20);index:idx_auth_session_user"`
+	UserID        string              `gorm:"type:varchar(36);index:idx_auth_session_user"`
+	SpaceID       string              `gorm:"type:varchar(36)"` // пусто для пользователей админки
+	TokenHash     string              `gorm:"type:varchar(64)"` // sha256 секрета действующего refresh токена
+	PrevTokenHash string              `gorm:"type:varchar(64)"` // sha256 секрета замененного refresh токена
+	RotatedAt     time.Time
+	UserAgent     string `gorm:"type:varchar(500)"`
+	IP            string `gorm:"type:varchar(50)"`
+	LastUsedAt    time.Time
+	ExpiresAt     time.Time `gorm:"index"`
+	RevokedAt     *time.Time
+	RevokeReason  string `gorm:"type:varchar(255)"`
+}
+
+// IsActive сессия не отозвана и не истекла
+func (r AuthSession) IsActive(now time.Time) bool {
+	return r.RevokedAt == nil && r.ExpiresAt.After(now)
+}
+
+func (r AuthSession) ToModelView(currentID string) authapimodels.SessionView {
+	return authapimodels.SessionView{
+		ID:         r.ID,
+		UserAgent:  r.UserAgent,
+		IP:         r.IP,
+		CreatedAt:  r.CreatedAt,
+		LastUsedAt: r.LastUsedAt,
+		ExpiresAt:  r.ExpiresAt,
+		IsCurrent:  r.ID == currentID,
+	}
+}
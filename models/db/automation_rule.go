@@ -0,0 +1,286 @@
+package dbmodels
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"hr-tools-backend/models"
+	"strconv"
+	"strings"
+)
+
+type AutomationTrigger string
+
+const (
+	AutomationTriggerStageChange  AutomationTrigger = "STAGE_CHANGE"  // Кандидат переведен на этап
+	AutomationTriggerNegotiation  AutomationTrigger = "NEGOTIATION"   // Получен отклик
+	AutomationTriggerSurveyScored AutomationTrigger = "SURVEY_SCORED" // Анкета кандидата оценена ИИ
+	AutomationTriggerVkStep       AutomationTrigger = "VK_STEP"       // Изменен статус шага ВК
+	AutomationTriggerTimeInStage  AutomationTrigger = "TIME_IN_STAGE" // Кандидат находится на этапе дольше указанного срока
+)
+
+func (t AutomationTrigger) IsValid() bool {
+	switch t {
+	case AutomationTriggerStageChange,
+		AutomationTriggerNegotiation,
+		AutomationTriggerSurveyScored,
+		AutomationTriggerVkStep,
+		AutomationTriggerTimeInStage:
+		return true
+	}
+	return false
+}
+
+type AutomationActionType string
+
+const (
+	AutomationActionSendTemplate AutomationActionType = "SEND_TEMPLATE" // Отправить письмо по шаблону
+	AutomationActionChangeStage  AutomationActionType = "CHANGE_STAGE"  // Перевести на этап
+	AutomationActionReject       AutomationActionType = "REJECT"        // Отклонить кандидата
+	AutomationActionAddTag       AutomationActionType = "ADD_TAG"       // Добавить тег
+	AutomationActionNotifyTeam   AutomationActionType = "NOTIFY_TEAM"   // Уведомить команду вакансии
+	AutomationActionVkStep0      AutomationActionType = "VK_STEP0"      // Запустить шаг 0 ВК (отправка анкеты)
+)
+
+type AutomationOperator string
+
+const (
+	AutomationOpEq       AutomationOperator = "eq"
+	AutomationOpNe       AutomationOperator = "ne"
+	AutomationOpGt       AutomationOperator = "gt"
+	AutomationOpGte      AutomationOperator = "gte"
+	AutomationOpLt       AutomationOperator = "lt"
+	AutomationOpLte      AutomationOperator = "lte"
+	AutomationOpContains AutomationOperator = "contains"
+)
+
+// AutomationRule правило автоматизации воронки подбора спейса
+type AutomationRule struct {
+	BaseSpaceModel
+	Name        string               `gorm:"type:varchar(255)"`
+	IsActive    bool                 `gorm:"index"`
+	DryRun      bool                 // действия не выполняются, только записываются в журнал
+	VacancyID   *string              `gorm:"type:varchar(36)"` // пусто - все вакансии спейса
+	Vacancy     *Vacancy             `gorm:"foreignKey:VacancyID"`
+	Trigger     AutomationTrigger    `gorm:"type:varchar(50);index"`
+	StageName   string               `gorm:"type:varchar(255)"` // этап для STAGE_CHANGE и TIME_IN_STAGE
+	VkStatus    *StepStatus          // статус шага ВК для VK_STEP, пусто - любой
+	DaysInStage int                  // срок нахождения на этапе для TIME_IN_STAGE
+	Conditions  AutomationConditions `gorm:"type:jsonb"`
+	Actions     AutomationActions    `gorm:"type:jsonb"`
+}
+
+// MatchEvent правило срабатывает на событие
+func (r AutomationRule) MatchEvent(trigger AutomationTrigger, vacancyID, stageName string, vkStatus StepStatus) bool {
+	if r.Trigger != trigger {
+		return false
+	}
+	if r.VacancyID != nil && *r.VacancyID != "" && *r.VacancyID != vacancyID {
+		return false
+	}
+	switch r.Trigger {
+	case AutomationTriggerStageChange, AutomationTriggerTimeInStage:
+		return r.StageName == "" || r.StageName == stageName
+	case AutomationTriggerVkStep:
+		return r.VkStatus == nil || *r.VkStatus == vkStatus
+	}
+	return true
+}
+
+// GetEventKey ключ события, правило выполняется для кандидата однократно по каждому ключу
+func (r AutomationRule) GetEventKey(stageName string, vkStatus StepStatus) string {
+	switch r.Trigger {
+	case AutomationTriggerStageChange, AutomationTriggerTimeInStage:
+		return fmt.Sprintf("%v:%v", r.Trigger, stageName)
+	case AutomationTriggerVkStep:
+		return fmt.Sprintf("%v:%v", r.Trigger, vkStatus)
+	}
+	return string(r.Trigger)
+}
+
+// CheckConditions кандидат удовлетворяет всем условиям правила
+func (r AutomationRule) CheckConditions(rec Applicant) bool {
+	for _, condition := range r.Conditions {
+		if !condition.Check(rec) {
+			return false
+		}
+	}
+	return true
+}
+
+func (j AutomationConditions) Value() (driver.Value, error) {
+	valueString, err := json.Marshal(j)
+	return string(valueString), err
+}
+
+func (j *AutomationConditions) Scan(value interface{}) error {
+	if err := json.Unmarshal(value.([]byte), &j); err != nil {
+		return err
+	}
+	return nil
+}
+
+type AutomationConditions []AutomationCondition
+
+type AutomationCondition struct {
+	Field    string             `json:"field"`    // Поле кандидата: source, salary, total_experience, gender, relocation, citizenship, education, search_status, trip_readiness, tag, stage, survey_score, vk_score, vk_pass
+	Operator AutomationOperator `json:"operator"` // Оператор сравнения: eq, ne, gt, gte, lt, lte, contains
+	Value    string             `json:"value"`    // Значение для сравнения
+}
+
+var AutomationConditionFields = []string{
+	"source", "salary", "total_experience", "gender", "relocation", "citizenship", "education",
+	"search_status", "trip_readiness", "tag", "stage", "survey_score", "vk_score", "vk_pass",
+}
+
+func (c AutomationCondition) Check(rec Applicant) bool {
+	if c.Field == "tag" {
+		if c.Operator == AutomationOpNe {
+			// ни один тег кандидата не совпадает
+			return !AutomationCondition{Field: c.Field, Operator: AutomationOpEq, Value: c.Value}.Check(rec)
+		}
+		for _, tag := range rec.Tags {
+			if compareValues(tag, c.Operator, c.Value) {
+				return true
+			}
+		}
+		return false
+	}
+	value, ok := getConditionValue(rec, c.Field)
+	if !ok {
+		return false
+	}
+	return compareValues(value, c.Operator, c.Value)
+}
+
+func getConditionValue(rec Applicant, field string) (string, bool) {
+	switch field {
+	case "source":
+		return string(rec.Source), true
+	case "salary":
+		return strconv.Itoa(rec.Salary), true
+	case "total_experience":
+		return strconv.Itoa(rec.TotalExperience), true
+	case "gender":
+		return string(rec.Gender), true
+	case "relocation":
+		return string(rec.Relocation), true
+	case "citizenship":
+		return rec.Citizenship, true
+	case "education":
+		return string(rec.Params.Education), true
+	case "search_status":
+		return string(rec.Params.SearchStatus), true
+	case "trip_readiness":
+		return string(rec.Params.TripReadiness), true
+	case "stage":
+		if rec.SelectionStage == nil {
+			return "", true
+		}
+		return rec.SelectionStage.Name, true
+	case "survey_score":
+		if rec.ApplicantSurvey == nil || !rec.ApplicantSurvey.IsScored {
+			return "", false
+		}
+		return strconv.Itoa(rec.ApplicantSurvey.ScoreAI.Score), true
+	case "vk_score":
+		if rec.ApplicantVkStep == nil || rec.ApplicantVkStep.Status < VkStep10Filtered {
+			return "", false
+		}
+		return strconv.Itoa(rec.ApplicantVkStep.TotalScore), true
+	case "vk_pass":
+		if rec.ApplicantVkStep == nil || rec.ApplicantVkStep.Status < VkStep10Filtered {
+			return "", false
+		}
+		return strconv.FormatBool(rec.ApplicantVkStep.Pass), true
+	}
+	return "", false
+}
+
+func compareValues(value string, operator AutomationOperator, expected string) bool {
+	switch operator {
+	case AutomationOpEq:
+		return strings.EqualFold(value, expected)
+	case AutomationOpNe:
+		return !strings.EqualFold(value, expected)
+	case AutomationOpContains:
+		return strings.Contains(strings.ToLower(value), strings.ToLower(expected))
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return false
+	}
+	expectedNumber, err := strconv.Atoi(expected)
+	if err != nil {
+		return false
+	}
+	switch operator {
+	case AutomationOpGt:
+		return number > expectedNumber
+	case AutomationOpGte:
+		return number >= expectedNumber
+	case AutomationOpLt:
+		return number < expectedNumber
+	case AutomationOpLte:
+		return number <= expectedNumber
+	}
+	return false
+}
+
+func (j AutomationActions) Value() (driver.Value, error) {
+	valueString, err := json.Marshal(j)
+	return string(valueString), err
+}
+
+func (j *AutomationActions) Scan(value interface{}) error {
+	if err := json.Unmarshal(value.([]byte), &j); err != nil {
+		return err
+	}
+	return nil
+}
+
+type AutomationActions []AutomationAction
+
+type AutomationAction struct {
+	Type            AutomationActionType   `json:"type"`
+	TemplateID      string                 `json:"template_id"`      // SEND_TEMPLATE: шаблон письма
+	StageName       string                 `json:"stage_name"`       // CHANGE_STAGE: этап подбора
+	RejectReason    string                 `json:"reject_reason"`    // REJECT: причина отказа
+	RejectInitiator models.RejectInitiator `json:"reject_initiator"` // REJECT: инициатор отказа
+	Tag             string                 `json:"tag"`              // ADD_TAG: тег
+	Message         string                 `json:"message"`          // NOTIFY_TEAM: текст уведомления
+}
+
+// AutomationLog журнал выполнения правил автоматизации
+type AutomationLog struct {
+	BaseSpaceModel
+	RuleID      string                  `gorm:"type:varchar(36);index"`
+	RuleName    string                  `gorm:"type:varchar(255)"`
+	ApplicantID string                  `gorm:"type:varchar(36);index"`
+	VacancyID   string                  `gorm:"type:varchar(36)"`
+	Trigger     AutomationTrigger       `gorm:"type:varchar(50)"`
+	EventKey    string                  `gorm:"type:varchar(255)"`
+	DryRun      bool                    // действия не выполнялись
+	Success     bool                    // все действия выполнены успешно
+	Results     AutomationActionResults `gorm:"type:jsonb"`
+}
+
+func (j AutomationActionResults) Value() (driver.Value, error) {
+	valueString, err := json.Marshal(j)
+	return string(valueString), err
+}
+
+func (j *AutomationActionResults) Scan(value interface{}) error {
+	if err := json.Unmarshal(value.([]byte), &j); err != nil {
+		return err
+	}
+	return nil
+}
+
+type AutomationActionResults []AutomationActionResult
+
+type AutomationActionResult struct {
+	Type    AutomationActionType `json:"type"`
+	Success bool                 `json:"success"`
+	Message string               `json:"message"` // Результат выполнения или причина ошибки
+}
@@ -0,0 +1,37 @@
+package dbmodels
+
+import (
+	"time"
+)
+
+type CalendarType string
+
+const (
+	CalendarTypeCalDAV CalendarType = "caldav" // CalDAV, чтение занятости и синхронизация интервью
+	CalendarTypeICS    CalendarType = "ics"    // ссылка на календарь в формате iCalendar, только чтение занятости
+)
+
+// CalendarConnection подключение календаря пользователя
+type CalendarConnection struct {
+	BaseSpaceModel
+	UserID     string       `gorm:"type:varchar(36);uniqueIndex"`
+	Type       CalendarType `gorm:"type:varchar(20)"`
+	URL        string       // зашифрован, ссылка на календарь может содержать токен доступа
+	Login      string
+	Password   string // зашифрован
+	LastSyncAt *time.Time
+	LastError  string
+}
+
+// InterviewCalendarEvent событие интервью в календаре участника
+type InterviewCalendarEvent struct {
+	BaseSpaceModel
+	InterviewID  string              `gorm:"type:varchar(36);index"`
+	UserID       string              `gorm:"type:varchar(36);index"`
+	ConnectionID string              `gorm:"type:varchar(36);index"`
+	Connection   *CalendarConnection `gorm:"foreignKey:ConnectionID"`
+	Href         string              // адрес события в календаре
+	ETag         string              // версия события в календаре
+	StartAt      time.Time           // время события при последней синхронизации
+	EndAt        time.Time
+}
@@ -0,0 +1,55 @@
+package dbmodels
+
+import (
+	"fmt"
+	"hr-tools-backend/models"
+	"strings"
+	"time"
+)
+
+// Candidate кандидат (человек), объединяет его отклики/участие в подборе по разным вакансиям
+type Candidate struct {
+	BaseSpaceModel
+	FirstName   string                `gorm:"type:varchar(255)" comment:"Имя"`
+	LastName    string                `gorm:"type:varchar(255)" comment:"Фамилия"`
+	MiddleName  string                `gorm:"type:varchar(255)" comment:"Отчество"`
+	Phone       string                `gorm:"type:varchar(255)" comment:"Телефон"`
+	PhoneDigits string                `gorm:"type:varchar(255);index" comment:"Телефон для поиска"` // только цифры, без кода страны
+	Email       string                `gorm:"type:varchar(255);index" comment:"Email"`              // в нижнем регистре
+	BirthDate   time.Time             `comment:"Дата рождения"`
+	Citizenship string                `gorm:"type:varchar(255)" comment:"Гражданство"`
+	Gender      models.GenderType     `gorm:"type:varchar(50)" comment:"Пол кандидата"`
+	Relocation  models.RelocationType `gorm:"type:varchar(100)" comment:"Готовность к переезду"`
+	Address     string                `comment:"Адрес"`
+	Comment     string                `comment:"Комментарий"`
+	Applicants  []Applicant           `gorm:"foreignKey:CandidateID"` // участие в подборе по вакансиям
+}
+
+func (c Candidate) GetFIO() string {
+	return strings.TrimSpace(fmt.Sprintf("%v %v %v", c.LastName, c.FirstName, c.MiddleName))
+}
+
+// TalentPool кадровый резерв / список кандидатов
+type TalentPool struct {
+	BaseSpaceModel
+	Name        string     `gorm:"type:varchar(255)"`
+	Description string     `gorm:"type:varchar(1000)"`
+	AuthorID    string     `gorm:"type:varchar(36)"`
+	Author      *SpaceUser `gorm:"foreignKey:AuthorID"`
+}
+
+// TalentPoolCandidate кандидат в кадровом резерве
+type TalentPoolCandidate struct {
+	BaseSpaceModel
+	TalentPoolID string     `gorm:"type:varchar(36);uniqueIndex:idx_pool_candidate"`
+	CandidateID  string     `gorm:"type:varchar(36);uniqueIndex:idx_pool_candidate;index"`
+	Candidate    *Candidate `gorm:"foreignKey:CandidateID"`
+	AddedBy      string     `gorm:"type:varchar(36)"`
+	Comment      string     `gorm:"type:varchar(1000)"`
+}
+
+// TalentPoolExt кадровый резерв с количеством кандидатов
+type TalentPoolExt struct {
+	TalentPool
+	CandidateCount int64
+}
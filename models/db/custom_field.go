@@ -0,0 +1,174 @@
+package dbmodels
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"hr-tools-backend/models"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+// CustomField описание пользовательского поля сущности спейса.
+// Значения хранятся в jsonb поле custom_fields сущности по ключу Code
+type CustomField struct {
+	BaseSpaceModel
+	Entity       models.CustomFieldEntity `gorm:"type:varchar(50);index"`
+	Code         string                   `gorm:"type:varchar(100)"` // ключ значения, латиница/цифры/подчеркивание
+	Name         string                   `gorm:"type:varchar(255)"`
+	Type         models.CustomFieldType   `gorm:"type:varchar(50)"`
+	Options      pq.StringArray           `gorm:"type:text[]"` // варианты для списков
+	Required     bool                     // обязательно для заполнения пользователем
+	VisibleRoles pq.StringArray           `gorm:"type:text[]"` // встроенные роли и ID ролей спейса, которым поле доступно, пусто - всем
+	Order        int
+}
+
+// IsVisible поле доступно пользователю. Пользователю с ролью спейса поле доступно, только если указана его роль спейса
+// (базовая роль не учитывается), остальным - по встроенной роли, администратору доступны все поля
+func (f CustomField) IsVisible(role models.UserRole, spaceRoleID string) bool {
+	if len(f.VisibleRoles) == 0 {
+		return true
+	}
+	if spaceRoleID != "" {
+		return slices.Contains(f.VisibleRoles, spaceRoleID)
+	}
+	return role.IsSpaceAdmin() || slices.Contains(f.VisibleRoles, string(role))
+}
+
+// CustomFieldValues значения пользовательских полей сущности: код поля - значение.
+// Текст, дата (ДД.ММ.ГГГГ), список и пользователь хранятся строкой, число - float64, множественный выбор - []string, флаг - bool
+type CustomFieldValues map[string]interface{}
+
+// Only значения только указанных полей
+func (v CustomFieldValues) Only(fields []CustomField) CustomFieldValues {
+	result := CustomFieldValues{}
+	for _, field := range fields {
+		if value, ok := v[field.Code]; ok {
+			result[field.Code] = value
+		}
+	}
+	return result
+}
+
+func (v CustomFieldValues) Value() (driver.Value, error) {
+	if v == nil {
+		return "{}", nil
+	}
+	valueString, err := json.Marshal(v)
+	return string(valueString), err
+}
+
+func (v *CustomFieldValues) Scan(value interface{}) error {
+	if value == nil {
+		*v = CustomFieldValues{}
+		return nil
+	}
+	if err := json.Unmarshal(value.([]byte), &v); err != nil {
+		return err
+	}
+	return nil
+}
+
+// CustomFieldFilter фильтр по пользовательскому полю
+type CustomFieldFilter struct {
+	Code  string                 `json:"code"`  // Код поля
+	Value string                 `json:"value"` // Значение: текст - поиск по вхождению, число/дата(ДД.ММ.ГГГГ)/список/пользователь - совпадение, множественный выбор - содержит вариант, флаг - true/false
+	From  string                 `json:"from"`  // Число/дата(ДД.ММ.ГГГГ) "от"
+	To    string                 `json:"to"`    // Число/дата(ДД.ММ.ГГГГ) "до"
+	Type  models.CustomFieldType `json:"-"`     // тип поля, заполняется по описанию поля
+}
+
+func (f CustomFieldFilter) Validate() error {
+	values := []string{f.Value, f.From, f.To}
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+		var err error
+		switch f.Type {
+		case models.CustomFieldNumber:
+			_, err = strconv.ParseFloat(value, 64)
+		case models.CustomFieldDate:
+			_, err = time.Parse("02.01.2006", value)
+		case models.CustomFieldBoolean:
+			_, err = strconv.ParseBool(value)
+		}
+		if err != nil {
+			return errors.Errorf("некорректное значение фильтра по полю %v", f.Code)
+		}
+	}
+	return nil
+}
+
+type CustomFieldFilters []CustomFieldFilter
+
+// Condition условие фильтрации, column - jsonb колонка значений в запросе. Фильтры должны быть предварительно проверены
+func (f CustomFieldFilters) Condition(column string) (query string, args map[string]interface{}) {
+	conditions := []string{}
+	args = map[string]interface{}{}
+	for k, filter := range f {
+		code := fmt.Sprintf("cf_code_%v", k)
+		args[code] = filter.Code
+		text := fmt.Sprintf("(%v->>@%v)", column, code)
+		addCond := func(suffix, cond string, value interface{}) {
+			name := fmt.Sprintf("cf_%v_%v", suffix, k)
+			args[name] = value
+			conditions = append(conditions, strings.ReplaceAll(cond, "{v}", "@"+name))
+		}
+		switch filter.Type {
+		case models.CustomFieldText:
+			if filter.Value != "" {
+				addCond("value", "LOWER"+text+" LIKE {v}", "%"+strings.ToLower(filter.Value)+"%")
+			}
+		case models.CustomFieldEnum, models.CustomFieldUser:
+			if filter.Value != "" {
+				addCond("value", text+" = {v}", filter.Value)
+			}
+		case models.CustomFieldMultiEnum:
+			if filter.Value != "" {
+				addCond("value", fmt.Sprintf("(%v->@%v) @> jsonb_build_array({v}::text)", column, code), filter.Value)
+			}
+		case models.CustomFieldBoolean:
+			if filter.Value != "" {
+				value, _ := strconv.ParseBool(filter.Value)
+				addCond("value", "COALESCE("+text+"::boolean, false) = {v}", value)
+			}
+		case models.CustomFieldNumber:
+			for _, bound := range filter.bounds() {
+				value, _ := strconv.ParseFloat(bound.value, 64)
+				addCond(bound.name, text+"::numeric "+bound.op+" {v}", value)
+			}
+		case models.CustomFieldDate:
+			for _, bound := range filter.bounds() {
+				value, _ := time.Parse("02.01.2006", bound.value)
+				addCond(bound.name, "to_date("+text+", 'DD.MM.YYYY') "+bound.op+" {v}", value)
+			}
+		}
+	}
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return "(" + strings.Join(conditions, " AND ") + ")", args
+}
+
+type filterBound struct {
+	name  string
+	op    string
+	value string
+}
+
+// bounds заполненные условия сравнения для числа и даты
+func (f CustomFieldFilter) bounds() []filterBound {
+	result := []filterBound{}
+	for _, bound := range []filterBound{{"value", "=", f.Value}, {"from", ">=", f.From}, {"to", "<=", f.To}} {
+		if bound.value != "" {
+			result = append(result, bound)
+		}
+	}
+	return result
+}
@@ -0,0 +1,119 @@
+package dbmodels
+
+import (
+	"hr-tools-backend/models"
+	"strings"
+)
+
+// DataScope область видимости пользователя, ограничивает выборки вакансий, заявок и кандидатов.
+// В хранилищах nil означает выборку без ограничений (системные вызовы, воркеры)
+type DataScope struct {
+	UserID        string
+	Scope         models.DataScope
+	FullAccess    bool     // администратор спейса, видит все, включая конфиденциальные заявки
+	DepartmentIDs []string // подразделение пользователя с дочерними, для models.DataScopeDepartment
+}
+
+const (
+	// автор, ответственный, автор или согласующий исходной заявки
+	vacancyOwnCond = `{t}.author_id = @scope_user
+ OR EXISTS (SELECT 1 FROM vacancy_teams vt_s WHERE vt_s.vacancy_id = {t}.id AND vt_s.user_id = @scope_user AND vt_s.responsible)
+ OR EXISTS (SELECT 1 FROM vacancy_requests vr_s WHERE vr_s.id = {t}.vacancy_request_id AND (vr_s.author_id = @scope_user
+  OR EXISTS (SELECT 1 FROM approval_tasks at_s WHERE at_s.request_id = vr_s.id AND at_s.assignee_user_id = @scope_user)))`
+	vacancyTeamCond            = `EXISTS (SELECT 1 FROM vacancy_teams vt_s WHERE vt_s.vacancy_id = {t}.id AND vt_s.user_id = @scope_user)`
+	vacancyNotConfidentialCond = `NOT EXISTS (SELECT 1 FROM vacancy_requests vr_s WHERE vr_s.id = {t}.vacancy_request_id AND vr_s.confidential)`
+
+	// автор или согласующий
+	requestOwnCond = `{t}.author_id = @scope_user
+ OR EXISTS (SELECT 1 FROM approval_tasks at_s WHERE at_s.request_id = {t}.id AND at_s.assignee_user_id = @scope_user)`
+	// участник команды вакансии, созданной по заявке
+	requestTeamCond = `EXISTS (SELECT 1 FROM vacancies v_s JOIN vacancy_teams vt_s ON vt_s.vacancy_id = v_s.id
+ WHERE v_s.vacancy_request_id = {t}.id AND vt_s.user_id = @scope_user)`
+	requestNotConfidentialCond = `{t}.confidential IS NOT TRUE`
+
+	departmentCond = `{t}.department_id IN @scope_departments`
+)
+
+// GetScope область видимости с учетом подразделения: без подразделения видимость ограничивается командой
+func (s *DataScope) GetScope() models.DataScope {
+	if s.Scope == models.DataScopeDepartment && len(s.DepartmentIDs) == 0 {
+		return models.DataScopeTeam
+	}
+	return s.Scope
+}
+
+// VacancyCondition условие видимости вакансий, table - таблица или алиас вакансий в запросе.
+// Вакансии по конфиденциальным заявкам видны только своим участникам и команде
+func (s *DataScope) VacancyCondition(table string) (query string, args map[string]interface{}) {
+	if s == nil || s.FullAccess {
+		return "", nil
+	}
+	conditions := []string{vacancyOwnCond}
+	switch s.GetScope() {
+	case models.DataScopeTeam:
+		conditions = append(conditions, vacancyTeamCond)
+	case models.DataScopeDepartment:
+		conditions = append(conditions, vacancyTeamCond, "("+vacancyNotConfidentialCond+" AND "+departmentCond+")")
+	case models.DataScopeSpace:
+		conditions = append(conditions, vacancyTeamCond, vacancyNotConfidentialCond)
+	}
+	return s.build(conditions, table)
+}
+
+// VacancyRequestCondition условие видимости заявок, table - таблица или алиас заявок в запросе.
+// Конфиденциальные заявки видны только автору, согласующим и администраторам
+func (s *DataScope) VacancyRequestCondition(table string) (query string, args map[string]interface{}) {
+	if s == nil || s.FullAccess {
+		return "", nil
+	}
+	conditions := []string{requestOwnCond}
+	switch s.GetScope() {
+	case models.DataScopeTeam:
+		conditions = append(conditions, "("+requestNotConfidentialCond+" AND "+requestTeamCond+")")
+	case models.DataScopeDepartment:
+		conditions = append(conditions, "("+requestNotConfidentialCond+" AND ("+requestTeamCond+" OR "+departmentCond+"))")
+	case models.DataScopeSpace:
+		conditions = append(conditions, requestNotConfidentialCond)
+	}
+	return s.build(conditions, table)
+}
+
+// ApplicantCondition условие видимости кандидатов: кандидат виден, если видна его вакансия
+func (s *DataScope) ApplicantCondition(table string) (query string, args map[string]interface{}) {
+	vacancyQuery, args := s.VacancyCondition("v_scope")
+	if vacancyQuery == "" {
+		return "", nil
+	}
+	query = table + ".vacancy_id IN (SELECT v_scope.id FROM vacancies v_scope WHERE v_scope.space_id = " + table + ".space_id AND " + vacancyQuery + ")"
+	return query, args
+}
+
+// ApplicantRefCondition условие видимости записей, ссылающихся на кандидата (интервью, офферы, файлы),
+// column - колонка с идентификатором кандидата
+func (s *DataScope) ApplicantRefCondition(column string) (query string, args map[string]interface{}) {
+	applicantQuery, args := s.ApplicantCondition("a_scope")
+	if applicantQuery == "" {
+		return "", nil
+	}
+	query = column + " IN (SELECT a_scope.id FROM applicants a_scope WHERE " + applicantQuery + ")"
+	return query, args
+}
+
+// CandidateCondition условие видимости кандидатов (людей): виден, если видно его участие хотя бы в одной вакансии
+func (s *DataScope) CandidateCondition(table string) (query string, args map[string]interface{}) {
+	applicantQuery, args := s.ApplicantCondition("a_scope")
+	if applicantQuery == "" {
+		return "", nil
+	}
+	query = "EXISTS (SELECT 1 FROM applicants a_scope WHERE a_scope.candidate_id = " + table + ".id AND " + applicantQuery + ")"
+	return query, args
+}
+
+func (s *DataScope) build(conditions []string, table string) (query string, args map[string]interface{}) {
+	query = "(" + strings.ReplaceAll(strings.Join(conditions, " OR "), "{t}", table) + ")"
+	args = map[string]interface{}{
+		"scope_user":        s.UserID,
+		"scope_departments": s.DepartmentIDs,
+	}
+	return query, args
+}
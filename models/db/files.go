@@ -33,6 +33,7 @@ const (
 	CompanyLogo             FileType = "company_logo"
 	CompanySign             FileType = "company_sign"
 	CompanyStamp            FileType = "company_stamp"
+	OfferDocument           FileType = "offer_document"
 )
 
 type UploadFileInfo struct {
@@ -0,0 +1,71 @@
+package dbmodels
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type InterviewStatus string
+
+const (
+	InterviewScheduled InterviewStatus = "scheduled" // Назначено
+	InterviewCanceled  InterviewStatus = "canceled"  // Отменено
+)
+
+type Interview struct {
+	BaseSpaceModel
+	ApplicantID      string                 `gorm:"type:varchar(36);index" comment:"Идентификатор кандидата"`
+	Applicant        *Applicant             `gorm:"foreignKey:ApplicantID"`
+	VacancyID        string                 `gorm:"type:varchar(36);index" comment:"Идентификатор вакансии"`
+	Vacancy          *Vacancy               `gorm:"foreignKey:VacancyID"`
+	SelectionStageID string                 `gorm:"type:varchar(36)" comment:"Идентификатор этапа подбора"`
+	SelectionStage   *SelectionStage        `gorm:"foreignKey:SelectionStageID"`
+	AuthorID         string                 `gorm:"type:varchar(36)" comment:"Идентификатор автора"`
+	StartAt          time.Time              `gorm:"index" comment:"Начало"`
+	EndAt            time.Time              `comment:"Окончание"`
+	Location         string                 `comment:"Место проведения / ссылка на встречу"`
+	Comment          string                 `comment:"Комментарий"`
+	Status           InterviewStatus        `gorm:"type:varchar(50);index" comment:"Статус"`
+	Sequence         int                    // номер изменения приглашения в календаре (SEQUENCE в ics)
+	ReminderSent     bool                   // напоминание отправлено
+	Participants     []InterviewParticipant `gorm:"foreignKey:InterviewID"`
+}
+
+// InterviewParticipant участник интервью со стороны компании (член команды вакансии)
+type InterviewParticipant struct {
+	BaseSpaceModel
+	InterviewID string     `gorm:"type:varchar(36);index"`
+	UserID      string     `gorm:"type:varchar(36);index"`
+	SpaceUser   *SpaceUser `gorm:"foreignKey:UserID"`
+}
+
+// InterviewSlot интервал, в который интервьюер доступен для проведения интервью
+type InterviewSlot struct {
+	BaseSpaceModel
+	UserID      string     `gorm:"type:varchar(36);index"`
+	SpaceUser   *SpaceUser `gorm:"foreignKey:UserID"`
+	StartAt     time.Time  `gorm:"index"`
+	EndAt       time.Time
+	InterviewID *string `gorm:"type:varchar(36)"` // слот занят интервью
+}
+
+// InterviewBooking ссылка для самостоятельной записи кандидата на интервью
+type InterviewBooking struct {
+	BaseSpaceModel
+	ApplicantID      string         `gorm:"type:varchar(36);index"`
+	VacancyID        string         `gorm:"type:varchar(36)"`
+	SelectionStageID string         `gorm:"type:varchar(36)"`
+	AuthorID         string         `gorm:"type:varchar(36)"`
+	UserIDs          pq.StringArray `gorm:"type:text[]"` // интервьюеры, слоты которых доступны кандидату
+	Location         string
+	ExpiresAt        time.Time
+	InterviewID      *string `gorm:"type:varchar(36)"` // кандидат записался на интервью
+}
+
+type InterviewSlotFilter struct {
+	UserIDs  []string
+	From     time.Time
+	To       time.Time
+	FreeOnly bool
+}
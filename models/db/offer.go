@@ -0,0 +1,91 @@
+package dbmodels
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type OfferStatus string
+
+const (
+	OfferStatusDraft      OfferStatus = "draft"       // Черновик
+	OfferStatusInApproval OfferStatus = "in_approval" // На согласовании
+	OfferStatusApproved   OfferStatus = "approved"    // Согласован
+	OfferStatusRejected   OfferStatus = "rejected"    // Отклонен при согласовании
+	OfferStatusSent       OfferStatus = "sent"        // Отправлен кандидату
+	OfferStatusExpired    OfferStatus = "expired"     // Истек срок действия (не хранится, вычисляется для отправленного оффера)
+	OfferStatusAccepted   OfferStatus = "accepted"    // Принят кандидатом
+	OfferStatusDeclined   OfferStatus = "declined"    // Отклонен кандидатом
+	OfferStatusCanceled   OfferStatus = "canceled"    // Отозван
+)
+
+// AllowEdit изменение условий оффера (создается новая версия, требуется повторное согласование)
+func (s OfferStatus) AllowEdit() bool {
+	switch s {
+	case OfferStatusDraft, OfferStatusApproved, OfferStatusRejected, OfferStatusSent, OfferStatusExpired, OfferStatusDeclined:
+		return true
+	}
+	return false
+}
+
+func (s OfferStatus) AllowApproval() bool {
+	return s == OfferStatusDraft || s == OfferStatusRejected
+}
+
+func (s OfferStatus) IsFinal() bool {
+	return s == OfferStatusAccepted || s == OfferStatusCanceled
+}
+
+// Offer предложение о работе кандидату на этапе "Оффер"
+type Offer struct {
+	BaseSpaceModel
+	ApplicantID    string      `gorm:"type:varchar(36);index" comment:"Идентификатор кандидата"`
+	Applicant      *Applicant  `gorm:"foreignKey:ApplicantID"`
+	VacancyID      string      `gorm:"type:varchar(36);index" comment:"Идентификатор вакансии"`
+	AuthorID       string      `gorm:"type:varchar(36)" comment:"Идентификатор автора"`
+	Author         *SpaceUser  `gorm:"foreignKey:AuthorID"`
+	TemplateID     string      `gorm:"type:varchar(36)" comment:"Идентификатор шаблона оффера"`
+	Version        int         `comment:"Версия"`
+	Salary         int         `comment:"Заработная плата"`
+	StartDate      time.Time   `comment:"Дата выхода"`
+	Conditions     string      `comment:"Условия"`
+	ExpiresAt      time.Time   `comment:"Срок действия"`
+	Status         OfferStatus `gorm:"type:varchar(50);index" comment:"Статус"`
+	SentAt         *time.Time  `comment:"Дата отправки кандидату"`
+	DecidedAt      *time.Time  `comment:"Дата ответа кандидата"`
+	DeclineReason  string      `comment:"Причина отказа кандидата"`
+	DocumentFileID string      `gorm:"type:varchar(36)" comment:"Идентификатор отправленного кандидату документа"`
+	DocumentHash   string      `gorm:"type:varchar(64)" comment:"Хеш SHA-256 отправленного кандидату документа"`
+}
+
+// GetStatus статус с учетом срока действия отправленного оффера
+func (r Offer) GetStatus() OfferStatus {
+	if r.Status == OfferStatusSent && r.ExpiresAt.Before(time.Now()) {
+		return OfferStatusExpired
+	}
+	return r.Status
+}
+
+// GetNumber номер документа оффера
+func (r Offer) GetNumber() string {
+	number := r.ID
+	if len(number) > 8 {
+		number = number[:8]
+	}
+	return fmt.Sprintf("%v/%v", strings.ToUpper(number), r.Version)
+}
+
+// OfferVersion условия оффера на момент изменения
+type OfferVersion struct {
+	BaseSpaceModel
+	OfferID    string     `gorm:"type:varchar(36);index"`
+	Version    int        `comment:"Версия"`
+	AuthorID   string     `gorm:"type:varchar(36)" comment:"Идентификатор автора изменений"`
+	Author     *SpaceUser `gorm:"foreignKey:AuthorID"`
+	TemplateID string     `gorm:"type:varchar(36)"`
+	Salary     int
+	StartDate  time.Time
+	Conditions string
+	ExpiresAt  time.Time
+}
@@ -0,0 +1,42 @@
+package dbmodels
+
+import (
+	"hr-tools-backend/models"
+	"time"
+)
+
+// PersonalDataConsent согласие кандидата на обработку персональных данных
+type PersonalDataConsent struct {
+	BaseSpaceModel
+	ApplicantID string               `gorm:"type:varchar(36);index"`
+	Source      models.ConsentSource `gorm:"type:varchar(50)"`
+	PolicyUrl   string               `gorm:"type:varchar(500)"` // политика обработки ПД, действовавшая на момент согласия
+	IP          string               `gorm:"type:varchar(50)"`
+	UserAgent   string               `gorm:"type:varchar(500)"`
+}
+
+// DataSubjectRequest запрос субъекта персональных данных на выгрузку или удаление данных
+type DataSubjectRequest struct {
+	BaseSpaceModel
+	ApplicantID   string                          `gorm:"type:varchar(36);index"`
+	RequestType   models.DataSubjectRequestType   `gorm:"type:varchar(50)"`
+	Status        models.DataSubjectRequestStatus `gorm:"type:varchar(50);index"`
+	Comment       string
+	AuthorID      string     `gorm:"type:varchar(36)"`
+	Author        *SpaceUser `gorm:"foreignKey:AuthorID"`
+	ProcessedByID *string    `gorm:"type:varchar(36)"`
+	ProcessedBy   *SpaceUser `gorm:"foreignKey:ProcessedByID"`
+	ProcessedAt   *time.Time
+	RejectReason  string
+}
+
+// PersonalDataAudit журнал действий с персональными данными кандидатов
+type PersonalDataAudit struct {
+	BaseSpaceModel
+	ApplicantID string               `gorm:"type:varchar(36);index"`
+	RequestID   *string              `gorm:"type:varchar(36)"`
+	UserID      *string              `gorm:"type:varchar(36)"` // пусто - действие выполнено системой
+	User        *SpaceUser           `gorm:"foreignKey:UserID"`
+	Action      models.PdAuditAction `gorm:"type:varchar(50)"`
+	Description string
+}
@@ -0,0 +1,129 @@
+package dbmodels
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// ScorecardTemplate шаблон оценочной карты интервьюера для этапа подбора вакансии
+type ScorecardTemplate struct {
+	BaseSpaceModel
+	VacancyID        string                `gorm:"type:varchar(36);index"`
+	SelectionStageID string                `gorm:"type:varchar(36);uniqueIndex"`
+	SelectionStage   *SelectionStage       `gorm:"foreignKey:SelectionStageID"`
+	Name             string                `gorm:"type:varchar(255)"`
+	ScaleMin         int                   // минимальная оценка по шкале
+	ScaleMax         int                   // максимальная оценка по шкале
+	PassRating       int                   // минимальная оценка, при которой обязательная компетенция считается подтвержденной
+	Competencies     ScorecardCompetencies `gorm:"type:jsonb"`
+}
+
+func (j ScorecardCompetencies) Value() (driver.Value, error) {
+	valueString, err := json.Marshal(j)
+	return string(valueString), err
+}
+
+func (j *ScorecardCompetencies) Scan(value interface{}) error {
+	if err := json.Unmarshal(value.([]byte), &j); err != nil {
+		return err
+	}
+	return nil
+}
+
+type ScorecardCompetencies []ScorecardCompetency
+
+type ScorecardCompetency struct {
+	ID          string `json:"id"`          // Идентификатор компетенции
+	Name        string `json:"name"`        // Название
+	Description string `json:"description"` // Описание / что проверяется
+	MustHave    bool   `json:"must_have"`   // Обязательная компетенция
+}
+
+type ScorecardStatus string
+
+const (
+	ScorecardDraft     ScorecardStatus = "draft"     // Черновик
+	ScorecardSubmitted ScorecardStatus = "submitted" // Отправлена
+)
+
+type ScorecardRecommendation string
+
+const (
+	RecommendationStrongYes ScorecardRecommendation = "strong_yes" // Однозначно да
+	RecommendationYes       ScorecardRecommendation = "yes"        // Да
+	RecommendationNo        ScorecardRecommendation = "no"         // Нет
+	RecommendationStrongNo  ScorecardRecommendation = "strong_no"  // Однозначно нет
+)
+
+var RecommendationNames = map[ScorecardRecommendation]string{
+	RecommendationStrongYes: "Однозначно да",
+	RecommendationYes:       "Да",
+	RecommendationNo:        "Нет",
+	RecommendationStrongNo:  "Однозначно нет",
+}
+
+// Scorecard оценочная карта кандидата, заполненная участником команды вакансии
+type Scorecard struct {
+	BaseSpaceModel
+	ApplicantID      string                  `gorm:"type:varchar(36);index:idx_scorecard_applicant"`
+	Applicant        *Applicant              `gorm:"foreignKey:ApplicantID"`
+	VacancyID        string                  `gorm:"type:varchar(36)"`
+	SelectionStageID string                  `gorm:"type:varchar(36);index:idx_scorecard_applicant"`
+	SelectionStage   *SelectionStage         `gorm:"foreignKey:SelectionStageID"`
+	TemplateID       string                  `gorm:"type:varchar(36)"`
+	UserID           string                  `gorm:"type:varchar(36)"`
+	SpaceUser        *SpaceUser              `gorm:"foreignKey:UserID"`
+	Ratings          ScorecardRatings        `gorm:"type:jsonb"`
+	Recommendation   ScorecardRecommendation `gorm:"type:varchar(20)"`
+	Comment          string
+	Status           ScorecardStatus `gorm:"type:varchar(20)"`
+	SubmittedAt      *time.Time
+}
+
+func (j ScorecardRatings) Value() (driver.Value, error) {
+	valueString, err := json.Marshal(j)
+	return string(valueString), err
+}
+
+func (j *ScorecardRatings) Scan(value interface{}) error {
+	if err := json.Unmarshal(value.([]byte), &j); err != nil {
+		return err
+	}
+	return nil
+}
+
+type ScorecardRatings []ScorecardRating
+
+// WithoutComments оценки без комментариев оценивающих
+func (j ScorecardRatings) WithoutComments() ScorecardRatings {
+	result := make(ScorecardRatings, 0, len(j))
+	for _, rating := range j {
+		rating.Comment = ""
+		result = append(result, rating)
+	}
+	return result
+}
+
+type ScorecardRating struct {
+	CompetencyID string `json:"competency_id"` // Идентификатор компетенции
+	Name         string `json:"name"`          // Название компетенции на момент оценки
+	Rating       int    `json:"rating"`        // Оценка, 0 - не оценивалась
+	Comment      string `json:"comment"`       // Комментарий
+}
+
+// Average средняя оценка по оцененным компетенциям
+func (r ScorecardRatings) Average() float64 {
+	sum, count := 0, 0
+	for _, item := range r {
+		if item.Rating == 0 {
+			continue
+		}
+		sum += item.Rating
+		count++
+	}
+	if count == 0 {
+		return 0
+	}
+	return float64(sum) / float64(count)
+}
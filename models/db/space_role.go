@@ -0,0 +1,37 @@
+package dbmodels
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"hr-tools-backend/models"
+	"slices"
+)
+
+// SpaceRole роль спейса с собственной матрицей разрешений RBAC.
+// Пользователю с такой ролью в поле Role проставляется базовая роль, от которой зависит остальная логика (согласования, уведомления и т.п.)
+type SpaceRole struct {
+	BaseSpaceModel
+	Name        string               `gorm:"type:varchar(255)"`
+	Description string               `gorm:"type:varchar(1000)"`
+	BaseRole    models.UserRole      `gorm:"type:varchar(50)"`
+	Permissions SpaceRolePermissions `gorm:"type:jsonb"`
+}
+
+// SpaceRolePermissions матрица модуль/разрешения
+type SpaceRolePermissions map[models.Module][]models.Permission
+
+func (p SpaceRolePermissions) Has(module models.Module, permission models.Permission) bool {
+	return slices.Contains(p[module], permission)
+}
+
+func (p SpaceRolePermissions) Value() (driver.Value, error) {
+	valueString, err := json.Marshal(p)
+	return string(valueString), err
+}
+
+func (p *SpaceRolePermissions) Scan(value interface{}) error {
+	if err := json.Unmarshal(value.([]byte), &p); err != nil {
+		return err
+	}
+	return nil
+}
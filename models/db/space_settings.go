@@ -66,10 +66,106 @@ var DefaultSpaceSupportEmail = SpaceSetting{
 	Value:   "",
 }
 
+var DefaultImapHostSetting = SpaceSetting{
+	SpaceID: "",
+	Name:    "адрес IMAP сервера почты для откликов (host:port)",
+	Code:    models.ImapHostSetting,
+	Value:   "",
+}
+
+var DefaultImapLoginSetting = SpaceSetting{
+	SpaceID: "",
+	Name:    "логин почты для откликов",
+	Code:    models.ImapLoginSetting,
+	Value:   "",
+}
+
+var DefaultImapPasswordSetting = SpaceSetting{
+	SpaceID: "",
+	Name:    "пароль почты для откликов",
+	Code:    models.ImapPasswordSetting,
+	Value:   "",
+}
+
+var DefaultImapMailboxSetting = SpaceSetting{
+	SpaceID: "",
+	Name:    "папка почты для откликов",
+	Code:    models.ImapMailboxSetting,
+	Value:   "INBOX",
+}
+
+var DefaultImapTLSSetting = SpaceSetting{
+	SpaceID: "",
+	Name:    "подключение к почте для откликов по TLS",
+	Code:    models.ImapTLSSetting,
+	Value:   "true",
+}
+
+var DefaultHolidaysSetting = SpaceSetting{
+	SpaceID: "",
+	Name:    "праздничные нерабочие дни через запятую (ДД.ММ.ГГГГ)",
+	Code:    models.HolidaysSetting,
+	Value:   "",
+}
+
+var DefaultWorkdaysSetting = SpaceSetting{
+	SpaceID: "",
+	Name:    "перенесенные рабочие дни через запятую (ДД.ММ.ГГГГ)",
+	Code:    models.WorkdaysSetting,
+	Value:   "",
+}
+
+var DefaultPdPolicyUrlSetting = SpaceSetting{
+	SpaceID: "",
+	Name:    "ссылка на политику обработки персональных данных",
+	Code:    models.PdPolicyUrlSetting,
+	Value:   "",
+}
+
+var DefaultPdRetentionDaysSetting = SpaceSetting{
+	SpaceID: "",
+	Name:    "срок хранения персональных данных отклоненных и архивных кандидатов (дней)",
+	Code:    models.PdRetentionDaysSetting,
+	Value:   "",
+}
+
+var DefaultPdRetentionActionSetting = SpaceSetting{
+	SpaceID: "",
+	Name:    "действие по истечении срока хранения персональных данных (anonymize - обезличивание, purge - удаление)",
+	Code:    models.PdRetentionActionSetting,
+	Value:   string(models.PdRetentionAnonymize),
+}
+
+var DefaultTwoFactorRequiredSetting = SpaceSetting{
+	SpaceID: "",
+	Name:    "обязательная двухфакторная аутентификация сотрудников (true/false)",
+	Code:    models.TwoFactorRequiredSetting,
+	Value:   "false",
+}
+
+var DefaultAuditLogRetentionDaysSetting = SpaceSetting{
+	SpaceID: "",
+	Name:    "срок хранения журнала аудита (дней)",
+	Code:    models.AuditLogRetentionDaysSetting,
+	Value:   "365",
+}
+
 var DefaultSettinsMap = map[models.SpaceSettingCode]SpaceSetting{
-	models.HhClientIDSetting:        DefaultHhClientIDSetting,
-	models.HhClientSecretSetting:    DefaultHhClientSecretSetting,
-	models.AvitoClientIDSetting:     DefaultAvitoClientIDSetting,
-	models.AvitoClientSecretSetting: DefaultAvitoClientSecretSetting,
-	models.SpaceSenderEmail:         DefaultSpaceSenderEmail,
+	models.HhClientIDSetting:            DefaultHhClientIDSetting,
+	models.HhClientSecretSetting:        DefaultHhClientSecretSetting,
+	models.AvitoClientIDSetting:         DefaultAvitoClientIDSetting,
+	models.AvitoClientSecretSetting:     DefaultAvitoClientSecretSetting,
+	models.SpaceSenderEmail:             DefaultSpaceSenderEmail,
+	models.ImapHostSetting:              DefaultImapHostSetting,
+	models.ImapLoginSetting:             DefaultImapLoginSetting,
+	models.ImapPasswordSetting:          DefaultImapPasswordSetting,
+	models.ImapMailboxSetting:           DefaultImapMailboxSetting,
+	models.ImapTLSSetting:               DefaultImapTLSSetting,
+	models.HolidaysSetting:              DefaultHolidaysSetting,
+	models.WorkdaysSetting:              DefaultWorkdaysSetting,
+	models.PdPolicyUrlSetting:           DefaultPdPolicyUrlSetting,
+	models.PdRetentionDaysSetting:       DefaultPdRetentionDaysSetting,
+	models.PdRetentionActionSetting:     DefaultPdRetentionActionSetting,
+	models.TwoFactorRequiredSetting:     DefaultTwoFactorRequiredSetting,
+	models.AuditLogRetentionDaysSetting: DefaultAuditLogRetentionDaysSetting,
 }
\ No newline at end of file
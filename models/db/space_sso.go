@@ -0,0 +1,96 @@
+package dbmodels
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"hr-tools-backend/models"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type SsoProtocol string
+
+const (
+	SsoProtocolOidc SsoProtocol = "oidc" // OpenID Connect, authorization code flow
+	SsoProtocolSaml SsoProtocol = "saml" // SAML 2.0, HTTP-Redirect запрос и HTTP-POST ответ
+)
+
+func (p SsoProtocol) IsValid() bool {
+	return p == SsoProtocolOidc || p == SsoProtocolSaml
+}
+
+// SpaceSso настройки входа сотрудников спейса через корпоративный IdP
+type SpaceSso struct {
+	BaseModel
+	SpaceID               string          `gorm:"type:varchar(36);uniqueIndex"`
+	IsEnabled             bool            `gorm:"default:false"`
+	Protocol              SsoProtocol     `gorm:"type:varchar(10)"`
+	Domains               pq.StringArray  `gorm:"type:text[]"` // почтовые домены сотрудников для выбора входа по почте
+	PasswordLoginDisabled bool            `gorm:"default:false"`
+	AutoProvision         bool            `gorm:"default:false"`    // создание пользователя при первом входе
+	DefaultRole           models.UserRole `gorm:"type:varchar(50)"` // роль, если группы пользователя не сопоставлены, пусто - вход запрещен
+	GroupRoles            SsoGroupRoles   `gorm:"type:jsonb"`
+	GroupsClaim           string          `gorm:"type:varchar(255)"` // claim ID токена или атрибут SAML с группами пользователя
+	OidcIssuer            string          `gorm:"type:varchar(500)"`
+	OidcClientID          string          `gorm:"type:varchar(255)"`
+	OidcClientSecret      string          `gorm:"type:varchar(1000)"` // зашифрован
+	OidcScopes            pq.StringArray  `gorm:"type:text[]"`
+	SamlIdpEntityID       string          `gorm:"type:varchar(500)"`
+	SamlIdpSsoUrl         string          `gorm:"type:varchar(1000)"`
+	SamlIdpCertificate    string          `gorm:"type:text"`
+}
+
+// GetRole роль по группам пользователя в IdP: первое подходящее сопоставление в порядке настройки
+func (s SpaceSso) GetRole(groups []string) (role models.UserRole, mapped bool) {
+	for _, groupRole := range s.GroupRoles {
+		for _, group := range groups {
+			if group == groupRole.Group {
+				return groupRole.Role, true
+			}
+		}
+	}
+	return s.DefaultRole, false
+}
+
+// IsDomainAllowed почта пользователя из IdP относится к почтовым доменам спейса
+func (s SpaceSso) IsDomainAllowed(email string) bool {
+	_, domain, found := strings.Cut(strings.ToLower(strings.TrimSpace(email)), "@")
+	if !found || domain == "" {
+		return false
+	}
+	return slices.Contains(s.Domains, domain)
+}
+
+type SsoGroupRoles []SsoGroupRole
+
+// SsoGroupRole сопоставление группы IdP и роли пользователя
+type SsoGroupRole struct {
+	Group string          `json:"group"` // Группа в IdP (имя или идентификатор, как передает IdP)
+	Role  models.UserRole `json:"role"`  // Роль пользователя
+}
+
+func (s SsoGroupRoles) Value() (driver.Value, error) {
+	valueString, err := json.Marshal(s)
+	return string(valueString), err
+}
+
+func (s *SsoGroupRoles) Scan(value interface{}) error {
+	if err := json.Unmarshal(value.([]byte), &s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// SsoSession вход через IdP: запрос аутентификации и одноразовый код для получения токенов
+type SsoSession struct {
+	BaseSpaceModel
+	Protocol  SsoProtocol `gorm:"type:varchar(10)"`
+	Nonce     string      `gorm:"type:varchar(64)"`
+	RequestID string      `gorm:"type:varchar(64)"` // ID запроса SAML
+	UserID    string      `gorm:"type:varchar(36)"`
+	LoginCode string      `gorm:"type:varchar(64);index"`
+	ExpiresAt time.Time   `gorm:"index"`
+}
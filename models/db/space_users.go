@@ -32,6 +32,17 @@ type SpaceUser struct {
 	Status              models.UserStatus `gorm:"type:varchar(50);default:WORKING"` // статус пользователя
 	StatusChangedAt     time.Time
 	StatusComment       *string
+	TgChatID            *int64 `gorm:"index"`                  // чат Telegram для уведомлений
+	TgLinkCode          string `gorm:"type:varchar(36);index"` // код привязки аккаунта Telegram
+	TgLinkCodeAt        time.Time
+	IsService           bool       `gorm:"default:false"` // служебная учетная запись API ключа, вход по паролю невозможен
+	FailedLoginAttempts int        // неверных паролей подряд
+	LockedUntil         *time.Time // вход по паролю заблокирован до
+	SpaceRoleID         *string    `gorm:"type:varchar(36);index"` // роль спейса с собственной матрицей разрешений
+	SpaceRole           *SpaceRole
+	DataScope           models.DataScope `gorm:"type:varchar(50)"` // область видимости данных, пусто - по роли
+	DepartmentID        *string          `gorm:"type:varchar(36)"` // подразделение пользователя для области видимости DEPARTMENT
+	Department          *Department
 }
 
 func (r SpaceUser) ToModel() spaceapimodels.SpaceUser {
@@ -49,7 +60,7 @@ func (r SpaceUser) ToModel() spaceapimodels.SpaceUser {
 		},
 		IsEmailVerified: r.IsEmailVerified,
 		NewEmail:        r.NewEmail,
-		RoleName:        r.Role.ToHuman(),
+		RoleName:        r.GetRoleName(),
 		Status:          r.Status.ToHuman(),
 		StatusChangedAt: r.StatusChangedAt,
 		StatusComment:   r.StatusComment,
@@ -58,6 +69,17 @@ func (r SpaceUser) ToModel() spaceapimodels.SpaceUser {
 		result.JobTitleID = *r.JobTitleID
 		result.JobTitleName = r.JobTitle.Name
 	}
+	if r.SpaceRoleID != nil {
+		result.SpaceRoleID = *r.SpaceRoleID
+	}
+	if r.DepartmentID != nil {
+		result.DepartmentID = *r.DepartmentID
+	}
+	if r.Department != nil {
+		result.DepartmentName = r.Department.Name
+	}
+	result.DataScope = r.DataScope
+	result.DataScopeName = r.GetDataScope().ToHuman()
 	return result
 }
 
@@ -65,7 +87,8 @@ func (r SpaceUser) ToProfile() spaceapimodels.SpaceUserProfileView {
 	result := spaceapimodels.SpaceUserProfileView{
 		ID:              r.ID,
 		Role:            r.Role,
-		RoleName:        r.Role.ToHuman(),
+		RoleName:        r.GetRoleName(),
+		SpaceRoleID:     r.SpaceRoleID,
 		IsEmailVerified: r.IsEmailVerified,
 		NewEmail:        r.NewEmail,
 		SpaceUserProfileData: spaceapimodels.SpaceUserProfileData{
@@ -77,6 +100,7 @@ func (r SpaceUser) ToProfile() spaceapimodels.SpaceUserProfileView {
 			UsePersonalSign:     r.UsePersonalSign,
 			TextSign:            r.TextSign,
 		},
+		TgLinked: r.TgChatID != nil,
 	}
 	if r.JobTitle != nil {
 		result.JobTitleName = r.JobTitle.Name
@@ -84,6 +108,22 @@ func (r SpaceUser) ToProfile() spaceapimodels.SpaceUserProfileView {
 	return result
 }
 
+// GetDataScope область видимости данных пользователя, если не задана - по роли
+func (r SpaceUser) GetDataScope() models.DataScope {
+	if r.DataScope != "" {
+		return r.DataScope
+	}
+	return r.Role.DefaultDataScope()
+}
+
+// GetRoleName название роли спейса, если она назначена, иначе базовой роли
+func (r SpaceUser) GetRoleName() string {
+	if r.SpaceRole != nil {
+		return r.SpaceRole.Name
+	}
+	return r.Role.ToHuman()
+}
+
 func (r SpaceUser) GetFullName() string {
 	return fmt.Sprintf("%s %s", r.FirstName, r.LastName)
 }
@@ -0,0 +1,27 @@
+package dbmodels
+
+import (
+	"hr-tools-backend/models"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// UserTwoFactor настройки двухфакторной аутентификации (TOTP) пользователя
+type UserTwoFactor struct {
+	BaseModel
+	UserKind       models.AuthUserKind `gorm:"type:varchar(20);uniqueIndex:idx_two_factor_user"`
+	UserID         string              `gorm:"type:varchar(36);uniqueIndex:idx_two_factor_user"`
+	Secret         string              `gorm:"type:varchar(255)"` // секрет TOTP, зашифрован
+	IsEnabled      bool                // false - секрет выпущен, но еще не подтвержден кодом
+	EnabledAt      *time.Time
+	LastStep       int64          // последний принятый интервал TOTP, повторно код того же интервала не принимается
+	RecoveryCodes  pq.StringArray `gorm:"type:text[]"` // sha256 неиспользованных кодов восстановления
+	FailedAttempts int            // неверных кодов подряд
+	LockedUntil    *time.Time
+}
+
+// IsLocked проверка кодов временно заблокирована после неверных попыток
+func (r UserTwoFactor) IsLocked(now time.Time) bool {
+	return r.LockedUntil != nil && r.LockedUntil.After(now)
+}
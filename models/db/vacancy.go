@@ -49,6 +49,7 @@ type Vacancy struct {
 	Employment      models.Employment `gorm:"type:varchar(255)"` // Занятость
 	Experience      models.Experience `gorm:"type:varchar(255)"` // Опыт работы
 	Schedule        models.Schedule   `gorm:"type:varchar(255)"` // Режим работы
+	CustomFields    CustomFieldValues `gorm:"type:jsonb"`        // Дополнительные поля
 	SelectionStages []SelectionStage
 	VacancyTeam     []VacancyTeam
 	HRSurvey        *HRSurvey
@@ -41,6 +41,8 @@ type VacancyRequest struct {
 	Employment      models.Employment `gorm:"type:varchar(255)"` // Занятость
 	Experience      models.Experience `gorm:"type:varchar(255)"` // Опыт работы
 	Schedule        models.Schedule   `gorm:"type:varchar(255)"` // Режим работы
+	CustomFields    CustomFieldValues `gorm:"type:jsonb"`        // Дополнительные поля
+	Salary          int               // Заработная плата по позиции (верхняя граница)
 	Favorite        bool
 	Pinned          bool
 	Vacancies       []Vacancy
@@ -0,0 +1,87 @@
+package dbmodels
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type WebhookEvent string
+
+const (
+	WebhookEventApplicantCreated      WebhookEvent = "applicant.created"        // Кандидат добавлен (вручную, откликом, с карьерного сайта)
+	WebhookEventApplicantStageChanged WebhookEvent = "applicant.stage_changed"  // Кандидат переведен на этап
+	WebhookEventApplicantRejected     WebhookEvent = "applicant.rejected"       // Кандидат отклонен
+	WebhookEventVacancyStatusChanged  WebhookEvent = "vacancy.status_changed"   // Изменен статус вакансии
+	WebhookEventVacancyReqApproved    WebhookEvent = "vacancy_request.approved" // Заявка на подбор согласована
+	WebhookEventVkReportReady         WebhookEvent = "vk.report_ready"          // Сформирован отчет ВК по кандидату
+	WebhookEventOfferAccepted         WebhookEvent = "offer.accepted"           // Кандидат принял оффер
+)
+
+var WebhookEvents = []WebhookEvent{
+	WebhookEventApplicantCreated,
+	WebhookEventApplicantStageChanged,
+	WebhookEventApplicantRejected,
+	WebhookEventVacancyStatusChanged,
+	WebhookEventVacancyReqApproved,
+	WebhookEventVkReportReady,
+	WebhookEventOfferAccepted,
+}
+
+func (e WebhookEvent) IsValid() bool {
+	for _, event := range WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending WebhookDeliveryStatus = "pending" // ожидает отправки (повторной отправки)
+	WebhookDeliverySuccess WebhookDeliveryStatus = "success" // доставлено
+	WebhookDeliveryFailed  WebhookDeliveryStatus = "failed"  // исчерпаны попытки доставки
+)
+
+// WebhookSubscription подписка внешней системы на события спейса
+type WebhookSubscription struct {
+	BaseSpaceModel
+	Name           string         `gorm:"type:varchar(255)"`
+	Url            string         `gorm:"type:varchar(1000)"`
+	Secret         string         // ключ подписи HMAC, хранится в зашифрованном виде
+	Events         pq.StringArray `gorm:"type:text[]"`
+	IsActive       bool           `gorm:"index"`
+	FailureCount   int            // кол-во неудачных доставок подряд
+	DisabledAt     *time.Time     // дата автоматического отключения после неудачных доставок
+	DisabledReason string
+	AuthorID       string     `gorm:"type:varchar(36)"`
+	Author         *SpaceUser `gorm:"foreignKey:AuthorID"`
+}
+
+// HasEvent подписка на событие
+func (s WebhookSubscription) HasEvent(event WebhookEvent) bool {
+	for _, e := range s.Events {
+		if e == string(event) {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery журнал доставки событий по подписке
+type WebhookDelivery struct {
+	BaseSpaceModel
+	SubscriptionID string                `gorm:"type:varchar(36);index"`
+	Subscription   *WebhookSubscription  `gorm:"foreignKey:SubscriptionID"`
+	Event          WebhookEvent          `gorm:"type:varchar(50)"`
+	Payload        string                // тело запроса
+	Status         WebhookDeliveryStatus `gorm:"type:varchar(50);index"`
+	Attempts       int
+	NextAttemptAt  *time.Time `gorm:"index"`
+	LastAttemptAt  *time.Time
+	ResponseCode   int
+	ResponseBody   string
+	Error          string
+}
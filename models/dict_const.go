@@ -123,6 +123,8 @@ const (
 	AStateRequestChanges ApprovalState = "REQUEST_CHANGES"
 	AStateRejected       ApprovalState = "REJECTED"
 	AStateRemoved        ApprovalState = "REMOVED"
+	AStateWaiting        ApprovalState = "WAITING" // ожидает согласования предыдущих шагов маршрута
+	AStateSkipped        ApprovalState = "SKIPPED" // решение не требуется, кворум шага набран
 )
 
 type VacancyPubStatus string
@@ -304,17 +306,24 @@ const (
 	NegotiationStatusAccepted NegotiationStatus = "Подходит"
 )
 
+// ApplicantSource источник кандидата, источники работных сайтов объявляются коннекторами при регистрации
 type ApplicantSource string
 
 const (
 	ApplicantSourceManual ApplicantSource = "Ручной ввод"
-	ApplicantSourceAvito  ApplicantSource = "Avito"
-	ApplicantSourceHh     ApplicantSource = "HeadHunter"
 	ApplicantSourceEmail  ApplicantSource = "Электронная почта"
 	ApplicantSourceSoc    ApplicantSource = "Социальные сети"
 	ApplicantSite         ApplicantSource = "Карьерный сайт"
 )
 
+// ApplicantSources источники кандидатов, не связанные с коннекторами работных сайтов
+var ApplicantSources = []ApplicantSource{
+	ApplicantSourceManual,
+	ApplicantSourceEmail,
+	ApplicantSourceSoc,
+	ApplicantSite,
+}
+
 type RelocationType string
 
 const (
@@ -518,6 +527,8 @@ type DuplicateType string
 const (
 	DuplicateTypeByAuthor   DuplicateType = "ByAuthor"
 	DuplicateTypeByContacts DuplicateType = "ByContacts"
+	DuplicateTypeByResume   DuplicateType = "ByResume"
+	DuplicateTypeByName     DuplicateType = "ByName"
 )
 
 type RejectInitiator string
@@ -0,0 +1,50 @@
+package models
+
+// ConsentRequiredMsg сообщение при отсутствии согласия на обработку персональных данных
+const ConsentRequiredMsg = "необходимо согласие на обработку персональных данных"
+
+// ConsentSource точка получения согласия на обработку персональных данных
+type ConsentSource string
+
+const (
+	ConsentSourceCareerSite     ConsentSource = "career_site"     // отклик с карьерного сайта
+	ConsentSourceSurvey         ConsentSource = "survey"          // анкета кандидата
+	ConsentSourceVkStep0        ConsentSource = "vk_step0"        // анкета с типовыми вопросами (ВК шаг 0)
+	ConsentSourceVideoInterview ConsentSource = "video_interview" // видео-интервью
+)
+
+// DataSubjectRequestType тип запроса субъекта персональных данных
+type DataSubjectRequestType string
+
+const (
+	DataSubjectRequestExport  DataSubjectRequestType = "export"  // выгрузка персональных данных
+	DataSubjectRequestErasure DataSubjectRequestType = "erasure" // удаление (обезличивание) персональных данных
+)
+
+type DataSubjectRequestStatus string
+
+const (
+	DataSubjectRequestNew      DataSubjectRequestStatus = "new"
+	DataSubjectRequestDone     DataSubjectRequestStatus = "done"
+	DataSubjectRequestRejected DataSubjectRequestStatus = "rejected"
+)
+
+// PdRetentionAction действие по истечении срока хранения персональных данных
+type PdRetentionAction string
+
+const (
+	PdRetentionAnonymize PdRetentionAction = "anonymize" // обезличивание
+	PdRetentionPurge     PdRetentionAction = "purge"     // удаление кандидата
+)
+
+// PdAuditAction действие с персональными данными в журнале
+type PdAuditAction string
+
+const (
+	PdAuditConsentGiven    PdAuditAction = "consent_given"
+	PdAuditRequestCreated  PdAuditAction = "request_created"
+	PdAuditRequestRejected PdAuditAction = "request_rejected"
+	PdAuditExport          PdAuditAction = "export"
+	PdAuditAnonymize       PdAuditAction = "anonymize"
+	PdAuditPurge           PdAuditAction = "purge"
+)
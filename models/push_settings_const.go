@@ -14,9 +14,11 @@ var PushCodeMap = map[SpacePushSettingCode]PushTpl{
 	//Важные уведомления
 	PushLicenseExpire: {Name: "Окончание действия лицензии на продукт", Title: "Окончание действия лицензии", Msg: "Ваша лицензия на продукт истекает {{LicEndDate}}. Пожалуйста, продлите действие, чтобы избежать блокировки."}, //TODO для модуля лицензирования
 
-	PushVRClosed:   {Name: "Необходимость в вакансии закончилась", Title: "Необходимость в вакансии закончилась", Msg: "Заявка «%v» завершена. Статус: %v."},
-	PushVRApproved: {Name: "Согласование заявки", Title: "Заявка согласована", Msg: "Заявка «%v» была согласована пользователем %v."},
-	PushVRRejected: {Name: "Отклонение заявки", Title: "Заявка отклонена", Msg: "Заявка «%v» была отклонена пользователем %v/%v."},
+	PushVRClosed:          {Name: "Необходимость в вакансии закончилась", Title: "Необходимость в вакансии закончилась", Msg: "Заявка «%v» завершена. Статус: %v."},
+	PushVRApproved:        {Name: "Согласование заявки", Title: "Заявка согласована", Msg: "Заявка «%v» была согласована пользователем %v."},
+	PushVRRejected:        {Name: "Отклонение заявки", Title: "Заявка отклонена", Msg: "Заявка «%v» была отклонена пользователем %v/%v."},
+	PushVRApprovalTask:    {Name: "Заявка ожидает согласования", Title: "Согласование заявки", Msg: "Заявка «%v» ожидает вашего согласования."},
+	PushVRApprovalOverdue: {Name: "Нарушен срок согласования заявки", Title: "Просрочено согласование заявки", Msg: "Истек срок согласования заявки «%v» пользователем %v."},
 
 	PushVacancyResponsible: {Name: "Ответственный за вакансию назначен", Title: "Назначен ответственный", Msg: "Теперь за вакансию «%v» отвечает %v."},
 	PushVacancyNewStatus:   {Name: "Изменение статуса вакансии", Title: "Изменён статус вакансии", Msg: "Статус вакансии «%v» изменён на %v."},
@@ -26,14 +28,29 @@ var PushCodeMap = map[SpacePushSettingCode]PushTpl{
 	PushApplicantNote:        {Name: "Заказчик комментирует кандидата на вакансии, в команде которой вы состоите", Title: "Комментарий от заказчика по кандидату", Msg: "Заказчик %v оставил комментарий к кандидату %v на вакансии «%v»."},
 	PushApplicantMsg:         {Name: "Пришло сообщение через Avito/HH", Title: "Новое сообщение от кандидата через %v", Msg: "Получено новое сообщение через %v от кандидата %v по вакансии «%v»."},
 	PushApplicantNewStage:    {Name: "Кандидат переведён на этап «Следующий этап»", Title: "Кандидат переведен на следующий этап", Msg: "Кандидат %v переведён на следующий этап «%v» по вакансии «%v»."},
+	PushApplicantAutomation:  {Name: "Сработало правило автоматизации по кандидату", Title: "Правило автоматизации", Msg: "Правило «%v» по кандидату %v на вакансии «%v»: %v"},
+	PushApplicantOverdue:     {Name: "Кандидат находится на этапе дольше установленного срока", Title: "Просрочен срок на этапе", Msg: "Кандидат %v находится на этапе «%v» вакансии «%v» дольше установленного срока (до %v)."},
+	PushApplicantEscalation:  {Name: "Эскалация просрочки этапа по кандидату", Title: "Просрочка этапа не устранена", Msg: "Кандидат %v остается на этапе «%v» вакансии «%v», срок истек %v. Ответственный: %v."},
+
+	PushInterviewScheduled: {Name: "Назначено интервью с кандидатом", Title: "Назначено интервью", Msg: "Интервью с кандидатом %v по вакансии «%v» назначено на %v."},
+	PushInterviewCanceled:  {Name: "Отменено интервью с кандидатом", Title: "Интервью отменено", Msg: "Интервью с кандидатом %v по вакансии «%v» на %v отменено."},
+	PushInterviewReminder:  {Name: "Напоминание об интервью", Title: "Скоро интервью", Msg: "Интервью с кандидатом %v по вакансии «%v» начнется в %v."},
+
+	PushOfferApproval: {Name: "Оффер кандидату направлен на согласование", Title: "Согласование оффера", Msg: "Оффер кандидату %v по вакансии «%v» ожидает вашего согласования."},
+	PushOfferApproved: {Name: "Оффер кандидату согласован", Title: "Оффер согласован", Msg: "Оффер кандидату %v по вакансии «%v» согласован, его можно отправить кандидату."},
+	PushOfferRejected: {Name: "Оффер кандидату отклонен при согласовании", Title: "Оффер отклонен", Msg: "Оффер кандидату %v по вакансии «%v» отклонен пользователем %v."},
+	PushOfferAccepted: {Name: "Кандидат принял оффер", Title: "Оффер принят", Msg: "Кандидат %v принял оффер по вакансии «%v»."},
+	PushOfferDeclined: {Name: "Кандидат отказался от оффера", Title: "Оффер отклонен кандидатом", Msg: "Кандидат %v отказался от оффера по вакансии «%v»."},
 }
 
 const (
 	PushLicenseExpire SpacePushSettingCode = "PushLicenseExpire"
 
-	PushVRClosed   SpacePushSettingCode = "PushVRClosed"
-	PushVRApproved SpacePushSettingCode = "PushVRApproved"
-	PushVRRejected SpacePushSettingCode = "PushVRRejected"
+	PushVRClosed          SpacePushSettingCode = "PushVRClosed"
+	PushVRApproved        SpacePushSettingCode = "PushVRApproved"
+	PushVRRejected        SpacePushSettingCode = "PushVRRejected"
+	PushVRApprovalTask    SpacePushSettingCode = "PushVRApprovalTask"
+	PushVRApprovalOverdue SpacePushSettingCode = "PushVRApprovalOverdue"
 
 	PushVacancyResponsible SpacePushSettingCode = "PushVacancyResponsible"
 	PushVacancyNewStatus   SpacePushSettingCode = "PushVacancyNewStatus"
@@ -43,12 +60,39 @@ const (
 	PushApplicantNote        SpacePushSettingCode = "PushApplicantNote"
 	PushApplicantMsg         SpacePushSettingCode = "PushApplicantMsg"//!!
 	PushApplicantNewStage    SpacePushSettingCode = "PushApplicantNewStage"
+	PushApplicantAutomation  SpacePushSettingCode = "PushApplicantAutomation"
+	PushApplicantOverdue     SpacePushSettingCode = "PushApplicantOverdue"
+	PushApplicantEscalation  SpacePushSettingCode = "PushApplicantEscalation"
+
+	PushInterviewScheduled SpacePushSettingCode = "PushInterviewScheduled"
+	PushInterviewCanceled  SpacePushSettingCode = "PushInterviewCanceled"
+	PushInterviewReminder  SpacePushSettingCode = "PushInterviewReminder"
+
+	PushOfferApproval SpacePushSettingCode = "PushOfferApproval"
+	PushOfferApproved SpacePushSettingCode = "PushOfferApproved"
+	PushOfferRejected SpacePushSettingCode = "PushOfferRejected"
+	PushOfferAccepted SpacePushSettingCode = "PushOfferAccepted"
+	PushOfferDeclined SpacePushSettingCode = "PushOfferDeclined"
 )
 
 type NotificationData struct {
-	Code  SpacePushSettingCode
-	Msg   string
-	Title string
+	Code     SpacePushSettingCode
+	Msg      string
+	Title    string
+	EntityID string // связанная сущность (заявка, кандидат), для кнопок в Telegram
+	TaskID   string // задача согласования заявки
+}
+
+// WithEntity указание связанной сущности уведомления
+func (n NotificationData) WithEntity(entityID string) NotificationData {
+	n.EntityID = entityID
+	return n
+}
+
+// WithTask указание задачи согласования, по которой можно принять решение из уведомления
+func (n NotificationData) WithTask(taskID string) NotificationData {
+	n.TaskID = taskID
+	return n
 }
 
 func GetPushVRClosed(vacancyName, vrStatus string) NotificationData {
@@ -78,6 +122,24 @@ func GetPushVRRejected(vacancyName, userName, userRole string) NotificationData
 	}
 }
 
+func GetPushVRApprovalTask(vacancyName string) NotificationData {
+	code:= PushVRApprovalTask
+	return NotificationData{
+		Code:  code,
+		Title: PushCodeMap[code].Title,
+		Msg:   fmt.Sprintf(PushCodeMap[code].Msg, vacancyName),
+	}
+}
+
+func GetPushVRApprovalOverdue(vacancyName, userName string) NotificationData {
+	code:= PushVRApprovalOverdue
+	return NotificationData{
+		Code:  code,
+		Title: PushCodeMap[code].Title,
+		Msg:   fmt.Sprintf(PushCodeMap[code].Msg, vacancyName, userName),
+	}
+}
+
 func GetPushVacancyResponsible(vacancyName, responsibleFullName string) NotificationData {
 	code:= PushVacancyResponsible
 	return NotificationData{
@@ -106,7 +168,7 @@ func GetPushVacancyPublished(vacancyName, pubService string) NotificationData {
 }
 
 func GetPushApplicantNegotiation(vacancyName, applicantFullName string) NotificationData {
-	code:= PushVacancyPublished
+	code:= PushApplicantNegotiation
 	return NotificationData{
 		Code:  code,
 		Title: PushCodeMap[code].Title,
@@ -139,4 +201,103 @@ func GetPushApplicantNewStage(vacancyName, userFullName, stageName string) Notif
 		Title: PushCodeMap[code].Title,
 		Msg:   fmt.Sprintf(PushCodeMap[code].Msg, userFullName, stageName, vacancyName),
 	}
-}
\ No newline at end of file
+}
+
+func GetPushApplicantAutomation(ruleName, applicantFIO, vacancyName, message string) NotificationData {
+	code:= PushApplicantAutomation
+	return NotificationData{
+		Code:  code,
+		Title: PushCodeMap[code].Title,
+		Msg:   fmt.Sprintf(PushCodeMap[code].Msg, ruleName, applicantFIO, vacancyName, message),
+	}
+}
+
+func GetPushApplicantOverdue(applicantFIO, stageName, vacancyName, deadline string) NotificationData {
+	code:= PushApplicantOverdue
+	return NotificationData{
+		Code:  code,
+		Title: PushCodeMap[code].Title,
+		Msg:   fmt.Sprintf(PushCodeMap[code].Msg, applicantFIO, stageName, vacancyName, deadline),
+	}
+}
+
+func GetPushApplicantEscalation(applicantFIO, stageName, vacancyName, deadline, responsibleName string) NotificationData {
+	code:= PushApplicantEscalation
+	return NotificationData{
+		Code:  code,
+		Title: PushCodeMap[code].Title,
+		Msg:   fmt.Sprintf(PushCodeMap[code].Msg, applicantFIO, stageName, vacancyName, deadline, responsibleName),
+	}
+}
+
+func GetPushInterviewScheduled(vacancyName, applicantFullName, startAt string) NotificationData {
+	code := PushInterviewScheduled
+	return NotificationData{
+		Code:  code,
+		Title: PushCodeMap[code].Title,
+		Msg:   fmt.Sprintf(PushCodeMap[code].Msg, applicantFullName, vacancyName, startAt),
+	}
+}
+
+func GetPushInterviewCanceled(vacancyName, applicantFullName, startAt string) NotificationData {
+	code := PushInterviewCanceled
+	return NotificationData{
+		Code:  code,
+		Title: PushCodeMap[code].Title,
+		Msg:   fmt.Sprintf(PushCodeMap[code].Msg, applicantFullName, vacancyName, startAt),
+	}
+}
+
+func GetPushInterviewReminder(vacancyName, applicantFullName, startAt string) NotificationData {
+	code := PushInterviewReminder
+	return NotificationData{
+		Code:  code,
+		Title: PushCodeMap[code].Title,
+		Msg:   fmt.Sprintf(PushCodeMap[code].Msg, applicantFullName, vacancyName, startAt),
+	}
+}
+
+func GetPushOfferApproval(vacancyName, applicantFullName string) NotificationData {
+	code := PushOfferApproval
+	return NotificationData{
+		Code:  code,
+		Title: PushCodeMap[code].Title,
+		Msg:   fmt.Sprintf(PushCodeMap[code].Msg, applicantFullName, vacancyName),
+	}
+}
+
+func GetPushOfferApproved(vacancyName, applicantFullName string) NotificationData {
+	code := PushOfferApproved
+	return NotificationData{
+		Code:  code,
+		Title: PushCodeMap[code].Title,
+		Msg:   fmt.Sprintf(PushCodeMap[code].Msg, applicantFullName, vacancyName),
+	}
+}
+
+func GetPushOfferRejected(vacancyName, applicantFullName, userFullName string) NotificationData {
+	code := PushOfferRejected
+	return NotificationData{
+		Code:  code,
+		Title: PushCodeMap[code].Title,
+		Msg:   fmt.Sprintf(PushCodeMap[code].Msg, applicantFullName, vacancyName, userFullName),
+	}
+}
+
+func GetPushOfferAccepted(vacancyName, applicantFullName string) NotificationData {
+	code := PushOfferAccepted
+	return NotificationData{
+		Code:  code,
+		Title: PushCodeMap[code].Title,
+		Msg:   fmt.Sprintf(PushCodeMap[code].Msg, applicantFullName, vacancyName),
+	}
+}
+
+func GetPushOfferDeclined(vacancyName, applicantFullName string) NotificationData {
+	code := PushOfferDeclined
+	return NotificationData{
+		Code:  code,
+		Title: PushCodeMap[code].Title,
+		Msg:   fmt.Sprintf(PushCodeMap[code].Msg, applicantFullName, vacancyName),
+	}
+}
@@ -13,6 +13,7 @@ const (
 	ProfileModule        Module = "PROFILE"
 	CompanyProfileModule Module = "COMPANY_PROFILE"
 	DictModule           Module = "DICT"
+	SecurityModule       Module = "SECURITY" // безопасность и интеграции: SSO, роли, API ключи, вебхуки, журнал аудита, настраиваемые поля
 )
 
 type Permission string
@@ -3,11 +3,23 @@ package models
 type SpaceSettingCode string
 
 const (
-	YandexGPTPromtSetting    SpaceSettingCode = "ya_gpt_promt" // Инструкции для Yandex GPT при генерации описания вакансии
-	HhClientIDSetting        SpaceSettingCode = "HHClientID"
-	HhClientSecretSetting    SpaceSettingCode = "HHClientSecret"
-	AvitoClientIDSetting     SpaceSettingCode = "AvitoClientID"
-	AvitoClientSecretSetting SpaceSettingCode = "AvitoClientSecret"
-	SpaceSenderEmail         SpaceSettingCode = "SpaceSenderEmail"  // почта, с которой отправляются письма кандидатам
-	SpaceSupportEmail        SpaceSettingCode = "SpaceSupportEmail" // почта, тех поддержки
+	YandexGPTPromtSetting        SpaceSettingCode = "ya_gpt_promt" // Инструкции для Yandex GPT при генерации описания вакансии
+	HhClientIDSetting            SpaceSettingCode = "HHClientID"
+	HhClientSecretSetting        SpaceSettingCode = "HHClientSecret"
+	AvitoClientIDSetting         SpaceSettingCode = "AvitoClientID"
+	AvitoClientSecretSetting     SpaceSettingCode = "AvitoClientSecret"
+	SpaceSenderEmail             SpaceSettingCode = "SpaceSenderEmail"  // почта, с которой отправляются письма кандидатам
+	SpaceSupportEmail            SpaceSettingCode = "SpaceSupportEmail" // почта, тех поддержки
+	ImapHostSetting              SpaceSettingCode = "ImapHost"          // адрес IMAP сервера почты для откликов (host:port)
+	ImapLoginSetting             SpaceSettingCode = "ImapLogin"
+	ImapPasswordSetting          SpaceSettingCode = "ImapPassword"
+	ImapMailboxSetting           SpaceSettingCode = "ImapMailbox"           // папка почтового ящика, по умолчанию INBOX
+	ImapTLSSetting               SpaceSettingCode = "ImapTLS"               // подключение по TLS (true/false)
+	HolidaysSetting              SpaceSettingCode = "Holidays"              // праздничные нерабочие дни ДД.ММ.ГГГГ через запятую
+	WorkdaysSetting              SpaceSettingCode = "Workdays"              // перенесенные рабочие дни (суббота/воскресенье) ДД.ММ.ГГГГ через запятую
+	PdPolicyUrlSetting           SpaceSettingCode = "PdPolicyUrl"           // ссылка на политику обработки персональных данных
+	PdRetentionDaysSetting       SpaceSettingCode = "PdRetentionDays"       // срок хранения данных отклоненных и архивных кандидатов в днях, пусто - без ограничения
+	PdRetentionActionSetting     SpaceSettingCode = "PdRetentionAction"     // действие по истечении срока хранения: anonymize/purge
+	TwoFactorRequiredSetting     SpaceSettingCode = "TwoFactorRequired"     // обязательная двухфакторная аутентификация сотрудников (true/false)
+	AuditLogRetentionDaysSetting SpaceSettingCode = "AuditLogRetentionDays" // срок хранения журнала аудита в днях, пусто или 0 - без ограничения
 )
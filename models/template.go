@@ -14,9 +14,27 @@ type TemplateData struct {
 	CompanyDirectorName string
 	CompanyAddress      string
 	CompanyContact      string
+	OfferSalary         string            // Заработная плата по офферу
+	OfferStartDate      string            // Дата выхода по офферу
+	OfferConditions     string            // Условия оффера
+	OfferExpiresAt      string            // Срок действия оффера
+	OfferLink           string            // Ссылка для принятия/отклонения оффера
+	OfferNumber         string            // Номер документа оффера, при заполнении в pdf добавляется блок для подписи кандидата
+	ApplicantFields     map[string]string // Пользовательские поля кандидата: код поля - значение
+	VacancyFields       map[string]string // Пользовательские поля вакансии: код поля - значение
 	Files               TemplateFiles
 }
 
+// OfferTemplateData данные оффера для заполнения шаблона
+type OfferTemplateData struct {
+	Salary     string
+	StartDate  string
+	Conditions string
+	ExpiresAt  string
+	Link       string
+	Number     string
+}
+
 type TemplateFiles struct {
 	Logo  *File
 	Stamp *File
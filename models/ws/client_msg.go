@@ -0,0 +1,14 @@
+package wsmodels
+
+// ClientMessage сообщение клиента
+type ClientMessage struct {
+	Type ClientMessageType `json:"type"` // тип сообщения (ack, ping)
+	IDs  []string          `json:"ids"`  // идентификаторы полученных событий для ack
+}
+
+type ClientMessageType string
+
+const (
+	ClientMessageAck  ClientMessageType = "ack"  // подтверждение получения событий, после подтверждения события не отправляются повторно
+	ClientMessagePing ClientMessageType = "ping" // heartbeat клиента
+)
@@ -3,9 +3,18 @@ package wsmodels
 import ()
 
 type ServerMessage struct {
-	ToUserID string `json:"-"`
-	Time     string `json:"time"`  // время события
-	Code     string `json:"code"`  // код события
-	Title    string `json:"title"` // заголовок события
-	Msg      string `json:"msg"`   // текст события
+	ToUserID string            `json:"-"`
+	ID       string            `json:"id,omitempty"` // идентификатор события, передается клиентом в подтверждении получения
+	Type     ServerMessageType `json:"type"`         // тип сообщения (push, pong)
+	Time     string            `json:"time"`         // время события
+	Code     string            `json:"code"`         // код события
+	Title    string            `json:"title"`        // заголовок события
+	Msg      string            `json:"msg"`          // текст события
 }
+
+type ServerMessageType string
+
+const (
+	ServerMessagePush ServerMessageType = "push" // событие, требует подтверждения получения
+	ServerMessagePong ServerMessageType = "pong" // ответ на ping клиента
+)